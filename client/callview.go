@@ -1,10 +1,13 @@
 package client
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -17,6 +20,22 @@ const (
 )
 
 func (c *WaspClient) CallView(chainID *iscp.ChainID, hContract iscp.Hname, functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
+	return c.callView(routes.CallView(chainID.Base58(), hContract.String(), functionName), args, optimisticReadTimeout...)
+}
+
+// CallViewAtBlock calls the view function against the state of the chain after the block with the given index
+func (c *WaspClient) CallViewAtBlock(chainID *iscp.ChainID, blockIndex uint32, hContract iscp.Hname, functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
+	route := routes.CallView(chainID.Base58(), hContract.String(), functionName) + blockIndexQuery(blockIndex)
+	return c.callView(route, args, optimisticReadTimeout...)
+}
+
+// CallViewAtStateHash calls the view function against the state of the chain with the given hash
+func (c *WaspClient) CallViewAtStateHash(chainID *iscp.ChainID, stateHash hashing.HashValue, hContract iscp.Hname, functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
+	route := routes.CallView(chainID.Base58(), hContract.String(), functionName) + stateHashQuery(stateHash)
+	return c.callView(route, args, optimisticReadTimeout...)
+}
+
+func (c *WaspClient) callView(route string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
 	deadline := time.Now().Add(defaultOptimisticReadTimeout)
 	if len(optimisticReadTimeout) > 0 {
 		deadline = time.Now().Add(optimisticReadTimeout[0])
@@ -28,7 +47,7 @@ func (c *WaspClient) CallView(chainID *iscp.ChainID, hContract iscp.Hname, funct
 	var res dict.Dict
 	var err error
 	for {
-		err = c.do(http.MethodGet, routes.CallView(chainID.Base58(), hContract.String(), functionName), arguments, &res)
+		err = c.do(http.MethodGet, route, arguments, &res)
 		switch {
 		case err == nil:
 			return res, err
@@ -42,3 +61,11 @@ func (c *WaspClient) CallView(chainID *iscp.ChainID, hContract iscp.Hname, funct
 		}
 	}
 }
+
+func blockIndexQuery(blockIndex uint32) string {
+	return "?" + url.Values{"blockIndex": {fmt.Sprintf("%d", blockIndex)}}.Encode()
+}
+
+func stateHashQuery(stateHash hashing.HashValue) string {
+	return "?" + url.Values{"stateHash": {stateHash.Base58()}}.Encode()
+}
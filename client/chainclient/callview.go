@@ -3,6 +3,7 @@ package chainclient
 import (
 	"time"
 
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 )
@@ -11,3 +12,13 @@ import (
 func (c *Client) CallView(hContract iscp.Hname, functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
 	return c.WaspClient.CallView(c.ChainID, hContract, functionName, args, optimisticReadTimeout...)
 }
+
+// CallViewAtBlock calls the view function against the state of the chain after the block with the given index
+func (c *Client) CallViewAtBlock(blockIndex uint32, hContract iscp.Hname, functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
+	return c.WaspClient.CallViewAtBlock(c.ChainID, blockIndex, hContract, functionName, args, optimisticReadTimeout...)
+}
+
+// CallViewAtStateHash calls the view function against the state of the chain with the given hash
+func (c *Client) CallViewAtStateHash(stateHash hashing.HashValue, hContract iscp.Hname, functionName string, args dict.Dict, optimisticReadTimeout ...time.Duration) (dict.Dict, error) {
+	return c.WaspClient.CallViewAtStateHash(c.ChainID, stateHash, hContract, functionName, args, optimisticReadTimeout...)
+}
@@ -1,6 +1,9 @@
 package chainclient
 
 import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/client"
@@ -13,6 +16,8 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/transaction"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
 )
 
 // Client allows to interact with a specific chain in the node, for example to send on-ledger or off-ledger requests
@@ -44,6 +49,12 @@ type PostRequestParams struct {
 	Transfer colored.Balances
 	Args     requestargs.RequestArgs
 	Nonce    uint64
+	// PriorityFee is paid out of the Transfer to be processed sooner under load
+	PriorityFee uint64
+	// EthereumKey signs the off-ledger request instead of the KeyPair of the client
+	EthereumKey *ecdsa.PrivateKey
+	// Encrypted seals the Args to the public key of the committee, so they are revealed only after ordering
+	Encrypted bool
 }
 
 // Post1Request sends an on-ledger transaction with one request on it to the chain
@@ -56,14 +67,23 @@ func (c *Client) Post1Request(
 	if len(params) > 0 {
 		par = params[0]
 	}
+	if par.Encrypted {
+		args, err := c.EncryptArgs(ledgerstate.NewED25519Address(c.KeyPair.PublicKey),
+			iscp.NewRequestTarget(contractHname, entryPoint), par.Args)
+		if err != nil {
+			return nil, err
+		}
+		par.Args = args
+	}
 	return c.GoshimmerClient.PostRequestTransaction(transaction.NewRequestTransactionParams{
 		SenderKeyPair: c.KeyPair,
 		Requests: []transaction.RequestParams{{
-			ChainID:    c.ChainID,
-			Contract:   contractHname,
-			EntryPoint: entryPoint,
-			Transfer:   par.Transfer,
-			Args:       par.Args,
+			ChainID:     c.ChainID,
+			Contract:    contractHname,
+			EntryPoint:  entryPoint,
+			Transfer:    par.Transfer,
+			Args:        par.Args,
+			PriorityFee: par.PriorityFee,
 		}},
 	})
 }
@@ -78,16 +98,76 @@ func (c *Client) PostOffLedgerRequest(
 	if len(params) > 0 {
 		par = params[0]
 	}
+	if par.EthereumKey != nil && par.Nonce == 0 {
+		// the nonce of the Ethereum account is tracked by the chain
+		account, err := c.WaspClient.GetEthereumAccount(c.ChainID, crypto.PubkeyToAddress(par.EthereumKey.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+		par.Nonce = account.Nonce
+	}
 	if par.Nonce == 0 {
 		c.nonces[c.KeyPair.PublicKey]++
 		par.Nonce = c.nonces[c.KeyPair.PublicKey]
 	}
-	offledgerReq := request.NewOffLedger(c.ChainID, contractHname, entrypoint, par.Args).WithTransfer(par.Transfer)
+	if par.Encrypted {
+		sender := ledgerstate.Address(ledgerstate.NewED25519Address(c.KeyPair.PublicKey))
+		if par.EthereumKey != nil {
+			sender = request.AddressFromEthereum(crypto.PubkeyToAddress(par.EthereumKey.PublicKey))
+		}
+		args, err := c.EncryptArgs(sender, iscp.NewRequestTarget(contractHname, entrypoint), par.Args)
+		if err != nil {
+			return nil, err
+		}
+		par.Args = args
+	}
+	offledgerReq := request.NewOffLedger(c.ChainID, contractHname, entrypoint, par.Args).
+		WithTransfer(par.Transfer).
+		WithPriorityFee(par.PriorityFee)
 	offledgerReq.WithNonce(par.Nonce)
-	offledgerReq.Sign(c.KeyPair)
+	if par.EthereumKey != nil {
+		offledgerReq.SignSecp256k1(par.EthereumKey)
+	} else {
+		offledgerReq.Sign(c.KeyPair)
+	}
 	return offledgerReq, c.WaspClient.PostOffLedgerRequest(c.ChainID, offledgerReq)
 }
 
+// CommitteePublicKey fetches the shared public key of the committee which currently runs the chain. The key is
+// verified against the state address of the chain output on L1, so the node is not trusted
+func (c *Client) CommitteePublicKey() (kyber.Point, error) {
+	chainOutput, err := c.GoshimmerClient.GetAliasOutput(c.ChainID.AsAliasAddress())
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := c.WaspClient.GetCommitteePublicKey(c.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	b, err := pubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if !ledgerstate.NewBLSAddress(b).Equals(chainOutput.GetStateAddress()) {
+		return nil, xerrors.Errorf("CommitteePublicKey: the key of the node does not match the state address %s of the chain",
+			chainOutput.GetStateAddress().Base58())
+	}
+	return pubKey, nil
+}
+
+// EncryptArgs seals the arguments of the request from the sender to the target with the public key of the committee
+func (c *Client) EncryptArgs(
+	sender ledgerstate.Address,
+	target iscp.RequestTarget,
+	args requestargs.RequestArgs,
+) (requestargs.RequestArgs, error) {
+	pubKey, err := c.CommitteePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return request.EncryptArgs(pubKey, c.ChainID, sender, target, args)
+}
+
 func (c *Client) DepositFunds(n uint64) (*ledgerstate.Transaction, error) {
 	return c.Post1Request(accounts.Contract.Hname(), accounts.FuncDeposit.Hname(), PostRequestParams{
 		Transfer: colored.NewBalancesForIotas(n),
@@ -1,6 +1,39 @@
 package chainclient
 
+import (
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/trie"
+)
+
 // StateGet fetches the raw value associated with the given key in the chain state
 func (c *Client) StateGet(key string) ([]byte, error) {
 	return c.WaspClient.StateGet(c.ChainID, key)
 }
+
+// StateGetAtBlock fetches the raw value associated with the given key in the chain state after the block with the given index
+func (c *Client) StateGetAtBlock(blockIndex uint32, key string) ([]byte, error) {
+	return c.WaspClient.StateGetAtBlock(c.ChainID, blockIndex, key)
+}
+
+// StateGetAtStateHash fetches the raw value associated with the given key in the chain state with the given hash
+func (c *Client) StateGetAtStateHash(stateHash hashing.HashValue, key string) ([]byte, error) {
+	return c.WaspClient.StateGetAtStateHash(c.ChainID, stateHash, key)
+}
+
+// StateGetProof fetches the raw value associated with the given key in the chain state and verifies
+// the proof of it against the state hash of the chain output on L1. Returns the value and the state hash
+func (c *Client) StateGetProof(key string) ([]byte, hashing.HashValue, *trie.Proof, error) {
+	chainOutput, err := c.GoshimmerClient.GetAliasOutput(c.ChainID.AsAliasAddress())
+	if err != nil {
+		return nil, hashing.NilHash, nil, err
+	}
+	stateHash, err := hashing.HashValueFromBytes(chainOutput.GetStateData())
+	if err != nil {
+		return nil, hashing.NilHash, nil, err
+	}
+	value, proof, err := c.WaspClient.StateGetProof(c.ChainID, stateHash, key)
+	if err != nil {
+		return nil, hashing.NilHash, nil, err
+	}
+	return value, stateHash, proof, nil
+}
@@ -1,13 +1,16 @@
 package client
 
 import (
+	"encoding/base64"
 	"net/http"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"go.dedis.ch/kyber/v3"
 )
 
 // PutCommitteeRecord sends a request to write a Record
@@ -32,3 +35,21 @@ func (c *WaspClient) GetCommitteeForChain(chainID *iscp.ChainID) (*registry.Comm
 	}
 	return res.Record(), nil
 }
+
+// GetCommitteePublicKey fetches the shared public key of the committee which currently runs the given chain.
+// The key is not verified, it must be checked against the state address of the chain output on L1
+func (c *WaspClient) GetCommitteePublicKey(chainID *iscp.ChainID) (kyber.Point, error) {
+	res := &model.CommitteePublicKey{}
+	if err := c.do(http.MethodGet, routes.CommitteePublicKey(chainID.Base58()), nil, res); err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(res.SharedPubKey)
+	if err != nil {
+		return nil, err
+	}
+	ret := tcrypto.DefaultSuite().G2().Point()
+	if err := ret.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -21,6 +21,13 @@ func (c *WaspClient) DKSharesPost(request *model.DKSharesPostRequest) (*model.DK
 	return &response, err
 }
 
+// DKSharesReshare redistributes an existing DKShare to a new committee and returns its new state.
+func (c *WaspClient) DKSharesReshare(request *model.DKSharesReshareRequest) (*model.DKSharesInfo, error) {
+	var response model.DKSharesInfo
+	err := c.do(http.MethodPost, routes.DKSharesReshare(), request, &response)
+	return &response, err
+}
+
 // DKSharesGet retrieves the representation of an existing DKShare.
 func (c *WaspClient) DKSharesGet(addr ledgerstate.Address) (*model.DKSharesInfo, error) {
 	addrStr := addr.Base58()
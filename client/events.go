@@ -0,0 +1,48 @@
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// ContractEvents fetches a page of typed events of the contract selected by the query.
+// The cursor of the response continues the query with the next page
+func (c *WaspClient) ContractEvents(chainID *iscp.ChainID, q *blocklog.TypedEventsQuery) (*model.EventsResponse, error) {
+	route := routes.ContractEvents(chainID.Base58(), q.Contract.String())
+	if q.EventName != "" {
+		route = routes.ContractEventsByName(chainID.Base58(), q.Contract.String(), url.PathEscape(q.EventName))
+	}
+	query := url.Values{}
+	for i, t := range q.Topics {
+		if t != nil {
+			query.Set(fmt.Sprintf("topic%d", i), hex.EncodeToString(t))
+		}
+	}
+	if q.FromBlock != 0 {
+		query.Set("fromBlock", fmt.Sprintf("%d", q.FromBlock))
+	}
+	if q.ToBlock != 0 {
+		query.Set("toBlock", fmt.Sprintf("%d", q.ToBlock))
+	}
+	if q.Cursor != nil {
+		query.Set("cursor", fmt.Sprintf("%d", *q.Cursor))
+	}
+	if q.Limit != 0 {
+		query.Set("limit", fmt.Sprintf("%d", q.Limit))
+	}
+	if len(query) > 0 {
+		route += "?" + query.Encode()
+	}
+	res := &model.EventsResponse{}
+	if err := c.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
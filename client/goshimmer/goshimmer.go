@@ -80,6 +80,20 @@ func (c *Client) GetConfirmedOutputs(address ledgerstate.Address) ([]ledgerstate
 	return ret, nil
 }
 
+// GetAliasOutput returns the confirmed unspent alias output of the chain with the given alias address
+func (c *Client) GetAliasOutput(aliasAddress *ledgerstate.AliasAddress) (*ledgerstate.AliasOutput, error) {
+	outs, err := c.GetConfirmedOutputs(aliasAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, out := range outs {
+		if aliasOut, ok := out.(*ledgerstate.AliasOutput); ok && aliasOut.GetAliasAddress().Equals(aliasAddress) {
+			return aliasOut, nil
+		}
+	}
+	return nil, fmt.Errorf("GetAliasOutput: alias output of %s not found", aliasAddress.Base58())
+}
+
 func (c *Client) postTx(tx *ledgerstate.Transaction) error {
 	data := tx.Bytes()
 	if len(data) > parameters.MaxSerializedTransactionToGoshimmer {
@@ -0,0 +1,20 @@
+package multiclient
+
+import (
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+// PostOffLedgerRequests sends the batch of off-ledger requests to all nodes.
+// It returns the results of the requests for each node
+func (m *MultiClient) PostOffLedgerRequests(chainID *iscp.ChainID, reqs []*request.OffLedger) ([][]model.OffLedgerRequestResult, error) {
+	ret := make([][]model.OffLedgerRequestResult, len(m.nodes))
+	err := m.Do(func(i int, w *client.WaspClient) error {
+		var err error
+		ret[i], err = w.PostOffLedgerRequests(chainID, reqs)
+		return err
+	})
+	return ret, err
+}
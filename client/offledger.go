@@ -1,6 +1,7 @@
 package client
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/request"
 	"github.com/iotaledger/wasp/packages/webapi/model"
@@ -13,3 +14,27 @@ func (c *WaspClient) PostOffLedgerRequest(chainID *iscp.ChainID, req *request.Of
 	}
 	return c.do("POST", routes.NewRequest(chainID.Base58()), data, nil)
 }
+
+// PostOffLedgerRequests sends a batch of off-ledger requests and returns the result of each request, in the same order
+func (c *WaspClient) PostOffLedgerRequests(chainID *iscp.ChainID, reqs []*request.OffLedger) ([]model.OffLedgerRequestResult, error) {
+	data := model.OffLedgerRequestBatchBody{
+		Requests: make([]model.Bytes, len(reqs)),
+	}
+	for i, req := range reqs {
+		data.Requests[i] = model.NewBytes(req.Bytes())
+	}
+	res := &model.OffLedgerRequestBatchResponse{}
+	if err := c.do("POST", routes.NewRequestBatch(chainID.Base58()), data, res); err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
+
+// GetEthereumAccount returns the agent ID and the next nonce of the sender of the off-ledger requests signed with the Ethereum key
+func (c *WaspClient) GetEthereumAccount(chainID *iscp.ChainID, ethAddress common.Address) (*model.EthereumAccountResponse, error) {
+	res := &model.EthereumAccountResponse{}
+	if err := c.do("GET", routes.EthereumAccount(chainID.Base58(), ethAddress.Hex()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
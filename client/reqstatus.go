@@ -20,6 +20,15 @@ func (c *WaspClient) RequestStatus(chainID *iscp.ChainID, reqID iscp.RequestID)
 	return res, nil
 }
 
+// RequestReceipt fetches the receipt of the processed request
+func (c *WaspClient) RequestReceipt(chainID *iscp.ChainID, reqID iscp.RequestID) (*model.RequestReceiptResponse, error) {
+	res := &model.RequestReceiptResponse{}
+	if err := c.do(http.MethodGet, routes.RequestReceipt(chainID.Base58(), reqID.Base58()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // WaitUntilRequestProcessed blocks until the request has been processed by the node
 func (c *WaspClient) WaitUntilRequestProcessed(chainID *iscp.ChainID, reqID iscp.RequestID, timeout time.Duration) error {
 	if timeout == 0 {
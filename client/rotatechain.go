@@ -0,0 +1,20 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// RotateChain asks the node to rotate the chain to a new committee. The governance calls are signed by the
+// identity of the node. Blocks until the new committee produces a block and returns its address
+func (c *WaspClient) RotateChain(chID *iscp.ChainID, req *model.RotateChainRequest) (ledgerstate.Address, error) {
+	res := &model.RotateChainResponse{}
+	if err := c.do(http.MethodPost, routes.RotateChain(chID.Base58()), req, res); err != nil {
+		return nil, err
+	}
+	return res.StateControllerAddress.Address(), nil
+}
@@ -1,18 +1,66 @@
 package client
 
 import (
+	"bytes"
 	"encoding/hex"
 	"net/http"
 
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/trie"
+	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"golang.org/x/xerrors"
 )
 
 // StateGet fetches the raw value associated with the given key in the chain state
 func (c *WaspClient) StateGet(chainID *iscp.ChainID, key string) ([]byte, error) {
+	return c.stateGet(routes.StateGet(chainID.Base58(), hex.EncodeToString([]byte(key))))
+}
+
+// StateGetAtBlock fetches the raw value associated with the given key in the chain state after the block with the given index
+func (c *WaspClient) StateGetAtBlock(chainID *iscp.ChainID, blockIndex uint32, key string) ([]byte, error) {
+	return c.stateGet(routes.StateGet(chainID.Base58(), hex.EncodeToString([]byte(key))) + blockIndexQuery(blockIndex))
+}
+
+// StateGetAtStateHash fetches the raw value associated with the given key in the chain state with the given hash
+func (c *WaspClient) StateGetAtStateHash(chainID *iscp.ChainID, stateHash hashing.HashValue, key string) ([]byte, error) {
+	return c.stateGet(routes.StateGet(chainID.Base58(), hex.EncodeToString([]byte(key))) + stateHashQuery(stateHash))
+}
+
+func (c *WaspClient) stateGet(route string) ([]byte, error) {
 	var res []byte
-	if err := c.do(http.MethodGet, routes.StateGet(chainID.Base58(), hex.EncodeToString([]byte(key))), nil, &res); err != nil {
+	if err := c.do(http.MethodGet, route, nil, &res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
+
+// StateGetProof fetches the raw value associated with the given key in the chain state together with the proof
+// of inclusion (or absence) of the key. The proof is verified against the expected state hash, which must be
+// obtained from a trusted source, e.g. the state output of the chain on L1. The node is not trusted
+func (c *WaspClient) StateGetProof(chainID *iscp.ChainID, stateHash hashing.HashValue, key string) ([]byte, *trie.Proof, error) {
+	var res model.StateProofResponse
+	if err := c.do(http.MethodGet, routes.StateGetProof(chainID.Base58(), hex.EncodeToString([]byte(key))), nil, &res); err != nil {
+		return nil, nil, err
+	}
+	if res.StateHash.HashValue() != stateHash {
+		return nil, nil, xerrors.Errorf("StateGetProof: the node is at state %s, expected %s",
+			res.StateHash.HashValue().String(), stateHash.String())
+	}
+	proof, err := trie.ProofFromBytes(res.Proof.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(proof.Key, []byte(key)) {
+		return nil, nil, xerrors.New("StateGetProof: proof is for another key")
+	}
+	var value []byte
+	if proof.Leaf != nil && bytes.Equal(proof.Leaf.Key, proof.Key) {
+		value = res.Value.Bytes()
+	}
+	if err := proof.Verify(stateHash, value); err != nil {
+		return nil, nil, err
+	}
+	return value, proof, nil
+}
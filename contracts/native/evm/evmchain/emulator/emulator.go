@@ -364,7 +364,7 @@ func (e *EVMEmulator) CallContract(call ethereum.CallMsg, blockNumber *big.Int)
 	if err != nil {
 		return nil, err
 	}
-	res, err := e.callContract(call, header, stateDB)
+	res, err := e.callContract(call, header, stateDB, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -429,7 +429,7 @@ func (e *EVMEmulator) EstimateGas(call ethereum.CallMsg) (uint64, error) {
 		call.Gas = gas
 
 		snapshot := e.pending.state.Snapshot()
-		res, err := e.callContract(call, e.pending.header, e.pending.state)
+		res, err := e.callContract(call, e.pending.header, e.pending.state, nil)
 		e.pending.state.RevertToSnapshot(snapshot)
 
 		if err != nil {
@@ -478,7 +478,7 @@ func (e *EVMEmulator) EstimateGas(call ethereum.CallMsg) (uint64, error) {
 
 // callContract implements common code between normal and pending contract calls.
 // state is modified during execution, make sure to copy it if necessary.
-func (e *EVMEmulator) callContract(call ethereum.CallMsg, header *types.Header, stateDB *state.StateDB) (*core.ExecutionResult, error) {
+func (e *EVMEmulator) callContract(call ethereum.CallMsg, header *types.Header, stateDB *state.StateDB, tracer vm.Tracer) (*core.ExecutionResult, error) {
 	// Ensure message is initialized properly.
 	if call.GasPrice == nil {
 		call.GasPrice = big.NewInt(1)
@@ -499,12 +499,81 @@ func (e *EVMEmulator) callContract(call ethereum.CallMsg, header *types.Header,
 	evmContext := core.NewEVMBlockContext(header, e.blockchain, nil)
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
-	vmEnv := vm.NewEVM(evmContext, txContext, stateDB, e.blockchain.Config(), vmConfig)
+	vmEnv := vm.NewEVM(evmContext, txContext, stateDB, e.blockchain.Config(), tracingVMConfig(tracer))
 	gasPool := new(core.GasPool).AddGas(math.MaxUint64)
 
 	return core.NewStateTransition(vmEnv, msg, gasPool).TransitionDb()
 }
 
+// tracingVMConfig returns the VM config with the tracer enabled, or the default config if tracer is nil
+func tracingVMConfig(tracer vm.Tracer) vm.Config {
+	if tracer == nil {
+		return vmConfig
+	}
+	return vm.Config{Debug: true, Tracer: tracer}
+}
+
+// TraceCall executes a contract call with the given tracer on top of the state of the given block
+func (e *EVMEmulator) TraceCall(call ethereum.CallMsg, blockNumber *big.Int, tracer vm.Tracer) (*core.ExecutionResult, error) {
+	header, err := e.HeaderByNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, ErrBlockDoesNotExist
+	}
+	stateDB, err := e.blockchain.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	return e.callContract(call, header, stateDB, tracer)
+}
+
+// TraceTransaction re-executes an already included transaction with the given tracer,
+// on top of the state of the parent block and the preceding transactions in the same block.
+func (e *EVMEmulator) TraceTransaction(txHash common.Hash, tracer vm.Tracer) (*core.ExecutionResult, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(e.database, txHash)
+	if tx == nil {
+		return nil, ErrTransactionDoesNotExist
+	}
+	block := e.blockchain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, ErrBlockDoesNotExist
+	}
+	parent := e.blockchain.GetBlockByHash(block.ParentHash())
+	if parent == nil {
+		return nil, ErrBlockDoesNotExist
+	}
+	stateDB, err := e.blockchain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	config := e.blockchain.Config()
+	signer := types.MakeSigner(config, block.Number())
+	blockContext := core.NewEVMBlockContext(block.Header(), e.blockchain, nil)
+	applyTx := func(tx *types.Transaction, i int, vmConfig vm.Config) (*core.ExecutionResult, error) {
+		msg, err := tx.AsMessage(signer, block.BaseFee())
+		if err != nil {
+			return nil, err
+		}
+		stateDB.Prepare(tx.Hash(), i)
+		vmEnv := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), stateDB, config, vmConfig)
+		res, err := core.ApplyMessage(vmEnv, msg, new(core.GasPool).AddGas(msg.Gas()))
+		if err != nil {
+			return nil, err
+		}
+		stateDB.Finalise(config.IsEIP158(block.Number()))
+		return res, nil
+	}
+	for i, prevTx := range block.Transactions()[:index] {
+		if _, err := applyTx(prevTx, i, vmConfig); err != nil {
+			return nil, xerrors.Errorf("cannot re-execute transaction %s: %w", prevTx.Hash(), err)
+		}
+	}
+	return applyTx(tx, int(index), tracingVMConfig(tracer))
+}
+
 // SendTransaction updates the pending block to include the given transaction.
 // It returns an error if the transaction is invalid.
 func (e *EVMEmulator) SendTransaction(tx *types.Transaction) (*types.Receipt, error) {
@@ -5,8 +5,10 @@ package evmchain
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/iotaledger/wasp/contracts/native/evm"
 	"github.com/iotaledger/wasp/contracts/native/evm/evmchain/emulator"
 	"github.com/iotaledger/wasp/contracts/native/evm/evminternal"
@@ -37,6 +39,8 @@ var Processor = Contract.Processor(initialize, append(
 	evm.FuncGetTransactionCountByBlockNumber.WithHandler(getTransactionCountByBlockNumber),
 	evm.FuncGetStorage.WithHandler(getStorage),
 	evm.FuncGetLogs.WithHandler(getLogs),
+	evm.FuncTraceTransaction.WithHandler(traceTransaction),
+	evm.FuncTraceCall.WithHandler(traceCall),
 )...)
 
 func initialize(ctx iscp.Sandbox) (dict.Dict, error) {
@@ -225,3 +229,26 @@ func estimateGas(ctx iscp.SandboxView) (dict.Dict, error) {
 		return evminternal.Result(codec.EncodeUint64(gas)), nil
 	})
 }
+
+func traceTransaction(ctx iscp.SandboxView) (dict.Dict, error) {
+	txHash := common.BytesToHash(ctx.Params().MustGet(evm.FieldTransactionHash))
+
+	return withEmulatorR(ctx, func(emu *emulator.EVMEmulator) (dict.Dict, error) {
+		return evminternal.Trace(ctx, func(tracer vm.Tracer) (*core.ExecutionResult, error) {
+			return emu.TraceTransaction(txHash, tracer)
+		})
+	})
+}
+
+func traceCall(ctx iscp.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	callMsg, err := evmtypes.DecodeCallMsg(ctx.Params().MustGet(evm.FieldCallMsg))
+	a.RequireNoError(err)
+
+	return withEmulatorR(ctx, func(emu *emulator.EVMEmulator) (dict.Dict, error) {
+		blockNumber := paramBlockNumberOrHashAsNumber(ctx, emu)
+		return evminternal.Trace(ctx, func(tracer vm.Tracer) (*core.ExecutionResult, error) {
+			return emu.TraceCall(callMsg, blockNumber, tracer)
+		})
+	})
+}
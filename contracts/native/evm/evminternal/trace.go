@@ -0,0 +1,29 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package evminternal
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/iotaledger/wasp/contracts/native/evm"
+	"github.com/iotaledger/wasp/packages/evm/evmtrace"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/assert"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+)
+
+// Trace runs the execution with the tracer selected in the FieldTraceConfig parameter,
+// and returns the JSON-encoded trace
+func Trace(ctx iscp.SandboxView, execute func(tracer vm.Tracer) (*core.ExecutionResult, error)) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	config, err := evmtrace.DecodeTraceConfig(ctx.Params().MustGet(evm.FieldTraceConfig))
+	a.RequireNoError(err)
+	tracer, err := evmtrace.New(config)
+	a.RequireNoError(err)
+	res, err := execute(tracer)
+	a.RequireNoError(err)
+	trace, err := tracer.Result(res)
+	a.RequireNoError(err)
+	return Result(trace), nil
+}
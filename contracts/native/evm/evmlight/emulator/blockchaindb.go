@@ -40,6 +40,7 @@ const (
 	keyBlockNumberByBlockHash = "bh:n"
 	keyBlockNumberByTxHash    = "th:n"
 	keyBlockIndexByTxHash     = "th:i"
+	keyStateIndexByTxHash     = "th:s"
 )
 
 // BlockchainDB contains logic for storing a fake blockchain (more like a list of blocks),
@@ -147,6 +148,10 @@ func makeBlockIndexByTxHashKey(hash common.Hash) kv.Key {
 	return keyBlockIndexByTxHash + kv.Key(hash.Bytes())
 }
 
+func makeStateIndexByTxHashKey(hash common.Hash) kv.Key {
+	return keyStateIndexByTxHash + kv.Key(hash.Bytes())
+}
+
 func (bc *BlockchainDB) getTxArray(blockNumber uint64) *collections.Array32 {
 	return collections.NewArray32(bc.kv, string(makeTransactionsByBlockNumberKey(blockNumber)))
 }
@@ -238,6 +243,7 @@ func (bc *BlockchainDB) deleteBlock(blockNumber uint64) {
 		txHash := bc.GetTransactionByBlockNumberAndIndex(blockNumber, i).Hash()
 		bc.kv.Del(makeBlockNumberByTxHashKey(txHash))
 		bc.kv.Del(makeBlockIndexByTxHashKey(txHash))
+		bc.kv.Del(makeStateIndexByTxHashKey(txHash))
 	}
 	txs.MustErase()
 	bc.getReceiptArray(blockNumber).MustErase()
@@ -368,6 +374,29 @@ func (bc *BlockchainDB) GetBlockIndexByTxHash(txHash common.Hash) uint32 {
 	return n
 }
 
+// SetStateIndexByTxHash records the index of the ISCP state on top of which the ISCP block
+// including the transaction was run, so that the transaction can be re-executed later
+func (bc *BlockchainDB) SetStateIndexByTxHash(txHash common.Hash, stateIndex uint32) {
+	bc.kv.Set(makeStateIndexByTxHashKey(txHash), codec.EncodeUint32(stateIndex))
+}
+
+func (bc *BlockchainDB) GetStateIndexByTxHash(txHash common.Hash) (uint32, bool) {
+	b := bc.kv.MustGet(makeStateIndexByTxHashKey(txHash))
+	if b == nil {
+		return 0, false
+	}
+	n, err := codec.DecodeUint32(b)
+	if err != nil {
+		panic(err)
+	}
+	return n, true
+}
+
+// GetPendingTransactionCount returns the amount of transactions already added to the pending block
+func (bc *BlockchainDB) GetPendingTransactionCount() uint32 {
+	return bc.getTxArray(bc.GetPendingBlockNumber()).MustLen()
+}
+
 func (bc *BlockchainDB) GetReceiptByTxHash(txHash common.Hash) *types.Receipt {
 	blockNumber, ok := bc.GetBlockNumberByTxHash(txHash)
 	if !ok {
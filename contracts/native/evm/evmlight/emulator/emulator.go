@@ -143,7 +143,7 @@ func (e *revertError) ErrorData() interface{} {
 
 // CallContract executes a contract call, without committing changes to the state
 func (e *EVMEmulator) CallContract(call ethereum.CallMsg) ([]byte, error) {
-	res, err := e.callContract(call)
+	res, err := e.callContract(call, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +173,7 @@ func (e *EVMEmulator) EstimateGas(call ethereum.CallMsg) (uint64, error) {
 	// Create a helper to check if a gas allowance results in an executable transaction
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		call.Gas = gas
-		res, err := e.callContract(call)
+		res, err := e.callContract(call, nil)
 		if err != nil {
 			if errors.Is(err, core.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -226,7 +226,12 @@ func (e *EVMEmulator) ChainContext() core.ChainContext {
 	}
 }
 
-func (e *EVMEmulator) callContract(call ethereum.CallMsg) (*core.ExecutionResult, error) {
+// TraceCall executes a contract call with the given tracer, without committing changes to the state
+func (e *EVMEmulator) TraceCall(call ethereum.CallMsg, tracer vm.Tracer) (*core.ExecutionResult, error) {
+	return e.callContract(call, tracer)
+}
+
+func (e *EVMEmulator) callContract(call ethereum.CallMsg, tracer vm.Tracer) (*core.ExecutionResult, error) {
 	// Ensure message is initialized properly.
 	if call.GasPrice == nil {
 		call.GasPrice = big.NewInt(0)
@@ -244,21 +249,23 @@ func (e *EVMEmulator) callContract(call ethereum.CallMsg) (*core.ExecutionResult
 	// run the EVM code on a buffered state (so that writes are not committed)
 	statedb := e.StateDB().Buffered().StateDB()
 
-	return e.applyMessage(msg, statedb, pendingHeader)
+	return e.applyMessage(msg, statedb, pendingHeader, tracer)
 }
 
-func (e *EVMEmulator) applyMessage(msg core.Message, statedb vm.StateDB, header *types.Header) (*core.ExecutionResult, error) {
+func (e *EVMEmulator) applyMessage(msg core.Message, statedb vm.StateDB, header *types.Header, tracer vm.Tracer) (*core.ExecutionResult, error) {
 	blockContext := core.NewEVMBlockContext(header, e.ChainContext(), nil)
 	txContext := core.NewEVMTxContext(msg)
-	vmEnv := vm.NewEVM(blockContext, txContext, statedb, e.chainConfig, e.vmConfig())
+	vmEnv := vm.NewEVM(blockContext, txContext, statedb, e.chainConfig, e.vmConfig(tracer))
 	gasPool := core.GasPool(msg.Gas())
 	vmEnv.Reset(txContext, statedb)
 	return core.ApplyMessage(vmEnv, msg, &gasPool)
 }
 
-func (e *EVMEmulator) vmConfig() vm.Config {
+func (e *EVMEmulator) vmConfig(tracer vm.Tracer) vm.Config {
 	return vm.Config{
 		JumpTable: vm.NewISCPInstructionSet(e.GetIEVMBackend),
+		Debug:     tracer != nil,
+		Tracer:    tracer,
 	}
 }
 
@@ -266,26 +273,40 @@ func (e *EVMEmulator) GetIEVMBackend() vm.ISCPBackend {
 	return e.IEVMBackend
 }
 
-func (e *EVMEmulator) SendTransaction(tx *types.Transaction) (*types.Receipt, error) {
-	buf := e.StateDB().Buffered()
-	statedb := buf.StateDB()
-	pendingHeader := e.BlockchainDB().GetPendingHeader()
-
+func (e *EVMEmulator) transactionMessage(tx *types.Transaction, pendingHeader *types.Header) (types.Message, error) {
 	sender, err := types.Sender(e.Signer(), tx)
 	if err != nil {
-		return nil, xerrors.Errorf("invalid transaction: %w", err)
+		return types.Message{}, xerrors.Errorf("invalid transaction: %w", err)
 	}
 	nonce := e.StateDB().GetNonce(sender)
 	if tx.Nonce() != nonce {
-		return nil, xerrors.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce)
+		return types.Message{}, xerrors.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce)
 	}
+	return tx.AsMessage(types.MakeSigner(e.chainConfig, pendingHeader.Number), pendingHeader.BaseFee)
+}
+
+// TraceTransaction executes the transaction on top of the pending block with the given tracer,
+// without committing changes to the state
+func (e *EVMEmulator) TraceTransaction(tx *types.Transaction, tracer vm.Tracer) (*core.ExecutionResult, error) {
+	pendingHeader := e.BlockchainDB().GetPendingHeader()
+	msg, err := e.transactionMessage(tx, pendingHeader)
+	if err != nil {
+		return nil, err
+	}
+	return e.applyMessage(msg, e.StateDB().Buffered().StateDB(), pendingHeader, tracer)
+}
+
+func (e *EVMEmulator) SendTransaction(tx *types.Transaction) (*types.Receipt, error) {
+	buf := e.StateDB().Buffered()
+	statedb := buf.StateDB()
+	pendingHeader := e.BlockchainDB().GetPendingHeader()
 
-	msg, err := tx.AsMessage(types.MakeSigner(e.chainConfig, pendingHeader.Number), pendingHeader.BaseFee)
+	msg, err := e.transactionMessage(tx, pendingHeader)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := e.applyMessage(msg, statedb, pendingHeader)
+	result, err := e.applyMessage(msg, statedb, pendingHeader, nil)
 	if err != nil {
 		return nil, err
 	}
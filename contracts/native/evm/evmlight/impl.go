@@ -7,7 +7,9 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/iotaledger/wasp/contracts/native/evm"
 	"github.com/iotaledger/wasp/contracts/native/evm/evminternal"
 	"github.com/iotaledger/wasp/contracts/native/evm/evmlight/emulator"
@@ -16,7 +18,9 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/assert"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"golang.org/x/xerrors"
 )
 
 var Processor = Contract.Processor(initialize, append(
@@ -40,6 +44,9 @@ var Processor = Contract.Processor(initialize, append(
 	evm.FuncGetTransactionCountByBlockNumber.WithHandler(getTransactionCountByBlockNumber),
 	evm.FuncGetStorage.WithHandler(getStorage),
 	evm.FuncGetLogs.WithHandler(getLogs),
+	evm.FuncTraceTransaction.WithHandler(traceTransaction),
+	evm.FuncTraceCall.WithHandler(traceCall),
+	evm.FuncGetTransactionTraceBase.WithHandler(getTransactionTraceBase),
 )...)
 
 func initialize(ctx iscp.Sandbox) (dict.Dict, error) {
@@ -87,7 +94,13 @@ func applyTransaction(ctx iscp.Sandbox) (dict.Dict, error) {
 			// next block will be minted when the ISCP block is closed
 			emu = getEmulatorInBlockContext(ctx)
 		}
-		return emu.SendTransaction(tx)
+		receipt, err := emu.SendTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+		// needed to trace the transaction later, from the state before the ISCP block
+		emu.BlockchainDB().SetStateIndexByTxHash(tx.Hash(), ctx.StateAnchor().StateIndex())
+		return receipt, nil
 	})
 }
 
@@ -193,3 +206,68 @@ func estimateGas(ctx iscp.SandboxView) (dict.Dict, error) {
 	a.RequireNoError(err)
 	return evminternal.Result(codec.EncodeUint64(gas)), nil
 }
+
+// getTransactionTraceBase returns the index of the ISCP state on top of which the transaction
+// was executed, and the transactions of its block up to and including the transaction.
+// evmlight does not keep the state of previous blocks, so traceTransaction must be called
+// at that ISCP state, with these transactions.
+func getTransactionTraceBase(ctx iscp.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	txHash := common.BytesToHash(ctx.Params().MustGet(evm.FieldTransactionHash))
+	emu := createEmulatorR(ctx)
+	bc := emu.BlockchainDB()
+	blockNumber, ok := bc.GetBlockNumberByTxHash(txHash)
+	a.Require(ok, "transaction not found")
+	stateIndex, ok := bc.GetStateIndexByTxHash(txHash)
+	a.Require(ok, "state index of the transaction not found")
+
+	ret := dict.New()
+	ret.Set(evm.FieldStateIndex, codec.EncodeUint32(stateIndex))
+	txs := collections.NewArray32(ret, evm.FieldBlockTransactions)
+	for i := uint32(0); i <= bc.GetBlockIndexByTxHash(txHash); i++ {
+		txs.MustPush(evmtypes.EncodeTransaction(bc.GetTransactionByBlockNumberAndIndex(blockNumber, i)))
+	}
+	return ret, nil
+}
+
+// traceTransaction re-executes the last of the given block transactions with the tracer.
+// It must be called at the ISCP state returned by getTransactionTraceBase: the transactions
+// of the block, which are not yet included in that state, are re-executed first.
+func traceTransaction(ctx iscp.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	txHash := common.BytesToHash(ctx.Params().MustGet(evm.FieldTransactionHash))
+	txs := collections.NewArray32ReadOnly(ctx.Params(), evm.FieldBlockTransactions)
+	n := txs.MustLen()
+	a.Require(n > 0, "traceTransaction: evmlight requires the transactions of the block, see getTransactionTraceBase")
+
+	emu := createEmulatorTrace(ctx)
+	for i := emu.BlockchainDB().GetPendingTransactionCount(); i < n; i++ {
+		tx, err := evmtypes.DecodeTransaction(txs.MustGetAt(i))
+		a.RequireNoError(err)
+		if i < n-1 {
+			_, err = emu.SendTransaction(tx)
+			a.RequireNoError(err, "cannot re-execute transaction", tx.Hash().Hex())
+			continue
+		}
+		a.Require(tx.Hash() == txHash, "traceTransaction: the transaction %s is not the last of the block transactions", txHash)
+		return evminternal.Trace(ctx, func(tracer vm.Tracer) (*core.ExecutionResult, error) {
+			return emu.TraceTransaction(tx, tracer)
+		})
+	}
+	return nil, xerrors.Errorf("traceTransaction: transaction %s is already included in the state", txHash)
+}
+
+// traceCall traces a call on top of the latest state. A previous block in the params is rejected,
+// since evmlight does not keep the historical state.
+func traceCall(ctx iscp.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	callMsg, err := evmtypes.DecodeCallMsg(ctx.Params().MustGet(evm.FieldCallMsg))
+	a.RequireNoError(err)
+	emu := createEmulatorR(ctx)
+	if blockNumber := paramBlockNumberOrHashAsNumber(ctx, emu, true); blockNumber != emu.BlockchainDB().GetNumber() {
+		return nil, xerrors.Errorf("traceCall: only the latest block is supported by evmlight, requested block %d", blockNumber)
+	}
+	return evminternal.Trace(ctx, func(tracer vm.Tracer) (*core.ExecutionResult, error) {
+		return emu.TraceCall(callMsg, tracer)
+	})
+}
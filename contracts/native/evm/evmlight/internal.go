@@ -40,6 +40,11 @@ func createEmulatorR(ctx iscp.SandboxView) *emulator.EVMEmulator {
 	return emulator.NewEVMEmulator(evminternal.EVMStateSubrealm(buffered.NewBufferedKVStoreAccess(ctx.State())), timestamp(ctx), &iscpBackendR{ctx})
 }
 
+// createEmulatorTrace creates an emulator for re-executing transactions in a view
+func createEmulatorTrace(ctx iscp.SandboxView) *emulator.EVMEmulator {
+	return emulator.NewEVMEmulator(evminternal.EVMStateSubrealm(buffered.NewBufferedKVStoreAccess(ctx.State())), timestamp(ctx), &iscpBackendTrace{})
+}
+
 // timestamp returns the current timestamp in seconds since epoch
 func timestamp(ctx iscp.SandboxBase) uint64 {
 	tsNano := time.Duration(ctx.GetTimestamp()) * time.Nanosecond
@@ -142,7 +147,7 @@ func paramBlockNumber(ctx iscp.SandboxView, emu *emulator.EVMEmulator, allowPrev
 	return current
 }
 
-func paramBlockNumberOrHashAsNumber(ctx iscp.SandboxView, emu *emulator.EVMEmulator, allowPrevious bool) uint64 { // nolint:unparam
+func paramBlockNumberOrHashAsNumber(ctx iscp.SandboxView, emu *emulator.EVMEmulator, allowPrevious bool) uint64 {
 	if ctx.Params().MustHas(evm.FieldBlockHash) {
 		a := assert.NewAssert(ctx.Log())
 		blockHash := common.BytesToHash(ctx.Params().MustGet(evm.FieldBlockHash))
@@ -175,3 +180,12 @@ var _ vm.ISCPBackend = &iscpBackendR{}
 
 func (i *iscpBackendR) Event(s string)    { panic("should not happen") }
 func (i *iscpBackendR) Entropy() [32]byte { panic("should not happen") }
+
+// iscpBackendTrace is used when re-executing transactions for tracing: the events
+// are discarded, and the entropy of the original ISCP request is not available
+type iscpBackendTrace struct{}
+
+var _ vm.ISCPBackend = &iscpBackendTrace{}
+
+func (i *iscpBackendTrace) Event(s string)    {}
+func (i *iscpBackendTrace) Entropy() [32]byte { return [32]byte{} }
@@ -29,6 +29,11 @@ var (
 	FuncGetStorage                          = coreutil.ViewFunc("getStorage")
 	FuncGetLogs                             = coreutil.ViewFunc("getLogs")
 
+	// Execution tracing
+	FuncTraceTransaction        = coreutil.ViewFunc("traceTransaction") // evmlight expects to be called at the state returned by getTransactionTraceBase
+	FuncTraceCall               = coreutil.ViewFunc("traceCall")
+	FuncGetTransactionTraceBase = coreutil.ViewFunc("getTransactionTraceBase") // only implemented by evmlight
+
 	// EVMchain SC management
 	FuncSetNextOwner    = coreutil.Func("setNextOwner")
 	FuncClaimOwnership  = coreutil.Func("claimOwnership")
@@ -62,11 +67,14 @@ const (
 	FieldGasUsed                 = "gu"
 	FieldGasLimit                = "gl"
 	FieldFilterQuery             = "fq"
+	FieldTraceConfig             = "tc"
 
 	// evmlight only:
 
-	FieldBlockTime       = "bt" // uint32, avg block time in seconds
-	FieldBlockKeepAmount = "bk" // int32
+	FieldBlockTime         = "bt"  // uint32, avg block time in seconds
+	FieldBlockKeepAmount   = "bk"  // int32
+	FieldStateIndex        = "si"  // uint32, index of the ISCP state before the block of the transaction
+	FieldBlockTransactions = "btx" // array of the transactions of the block up to the traced one
 )
 
 const (
@@ -13,17 +13,21 @@ import "github.com/iotaledger/wasp/packages/vm/wasmlib/go/wasmlib"
 type Erc20Events struct{}
 
 func (e Erc20Events) Approval(amount int64, owner wasmlib.ScAgentID, spender wasmlib.ScAgentID) {
-	wasmlib.NewEventEncoder("erc20.approval").
-		Int64(amount).
-		AgentID(owner).
-		AgentID(spender).
+	wasmlib.NewTypedEventEncoder("erc20.approval").
+		Int64("amount", amount).
+		AgentID("owner", owner).
+		Indexed().
+		AgentID("spender", spender).
+		Indexed().
 		Emit()
 }
 
 func (e Erc20Events) Transfer(amount int64, from wasmlib.ScAgentID, to wasmlib.ScAgentID) {
-	wasmlib.NewEventEncoder("erc20.transfer").
-		Int64(amount).
-		AgentID(from).
-		AgentID(to).
+	wasmlib.NewTypedEventEncoder("erc20.transfer").
+		Int64("amount", amount).
+		AgentID("from", from).
+		Indexed().
+		AgentID("to", to).
+		Indexed().
 		Emit()
 }
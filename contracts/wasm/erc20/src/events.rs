@@ -15,18 +15,22 @@ pub struct Erc20Events {
 impl Erc20Events {
 
 	pub fn approval(&self, amount: i64, owner: &ScAgentID, spender: &ScAgentID) {
-		let mut encoder = EventEncoder::new("erc20.approval");
-		encoder.int64(amount);
-		encoder.agent_id(&owner);
-		encoder.agent_id(&spender);
+		let mut encoder = TypedEventEncoder::new("erc20.approval");
+		encoder.int64("amount", amount);
+		encoder.agent_id("owner", &owner);
+		encoder.indexed();
+		encoder.agent_id("spender", &spender);
+		encoder.indexed();
 		encoder.emit();
 	}
 
 	pub fn transfer(&self, amount: i64, from: &ScAgentID, to: &ScAgentID) {
-		let mut encoder = EventEncoder::new("erc20.transfer");
-		encoder.int64(amount);
-		encoder.agent_id(&from);
-		encoder.agent_id(&to);
+		let mut encoder = TypedEventEncoder::new("erc20.transfer");
+		encoder.int64("amount", amount);
+		encoder.agent_id("from", &from);
+		encoder.indexed();
+		encoder.agent_id("to", &to);
+		encoder.indexed();
 		encoder.emit();
 	}
 }
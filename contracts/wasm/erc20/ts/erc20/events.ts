@@ -10,18 +10,22 @@ import * as wasmlib from "wasmlib";
 export class Erc20Events {
 
 	approval(amount: i64, owner: wasmlib.ScAgentID, spender: wasmlib.ScAgentID): void {
-		new wasmlib.EventEncoder("erc20.approval").
-		int64(amount).
-		agentID(owner).
-		agentID(spender).
+		new wasmlib.TypedEventEncoder("erc20.approval").
+		int64("amount", amount).
+		agentID("owner", owner).
+		indexed().
+		agentID("spender", spender).
+		indexed().
 		emit();
 	}
 
 	transfer(amount: i64, from: wasmlib.ScAgentID, to: wasmlib.ScAgentID): void {
-		new wasmlib.EventEncoder("erc20.transfer").
-		int64(amount).
-		agentID(from).
-		agentID(to).
+		new wasmlib.TypedEventEncoder("erc20.transfer").
+		int64("amount", amount).
+		agentID("from", from).
+		indexed().
+		agentID("to", to).
+		indexed().
 		emit();
 	}
 }
@@ -13,40 +13,50 @@ import "github.com/iotaledger/wasp/packages/vm/wasmlib/go/wasmlib"
 type Erc721Events struct{}
 
 func (e Erc721Events) Approval(approved wasmlib.ScAgentID, owner wasmlib.ScAgentID, tokenID wasmlib.ScHash) {
-	wasmlib.NewEventEncoder("erc721.approval").
-		AgentID(approved).
-		AgentID(owner).
-		Hash(tokenID).
+	wasmlib.NewTypedEventEncoder("erc721.approval").
+		AgentID("approved", approved).
+		Indexed().
+		AgentID("owner", owner).
+		Indexed().
+		Hash("tokenID", tokenID).
+		Indexed().
 		Emit()
 }
 
 func (e Erc721Events) ApprovalForAll(approval bool, operator wasmlib.ScAgentID, owner wasmlib.ScAgentID) {
-	wasmlib.NewEventEncoder("erc721.approvalForAll").
-		Bool(approval).
-		AgentID(operator).
-		AgentID(owner).
+	wasmlib.NewTypedEventEncoder("erc721.approvalForAll").
+		Bool("approval", approval).
+		AgentID("operator", operator).
+		Indexed().
+		AgentID("owner", owner).
+		Indexed().
 		Emit()
 }
 
 func (e Erc721Events) Init(name string, symbol string) {
-	wasmlib.NewEventEncoder("erc721.init").
-		String(name).
-		String(symbol).
+	wasmlib.NewTypedEventEncoder("erc721.init").
+		String("name", name).
+		String("symbol", symbol).
 		Emit()
 }
 
 func (e Erc721Events) Mint(balance uint64, owner wasmlib.ScAgentID, tokenID wasmlib.ScHash) {
-	wasmlib.NewEventEncoder("erc721.mint").
-		Uint64(balance).
-		AgentID(owner).
-		Hash(tokenID).
+	wasmlib.NewTypedEventEncoder("erc721.mint").
+		Uint64("balance", balance).
+		AgentID("owner", owner).
+		Indexed().
+		Hash("tokenID", tokenID).
+		Indexed().
 		Emit()
 }
 
 func (e Erc721Events) Transfer(from wasmlib.ScAgentID, to wasmlib.ScAgentID, tokenID wasmlib.ScHash) {
-	wasmlib.NewEventEncoder("erc721.transfer").
-		AgentID(from).
-		AgentID(to).
-		Hash(tokenID).
+	wasmlib.NewTypedEventEncoder("erc721.transfer").
+		AgentID("from", from).
+		Indexed().
+		AgentID("to", to).
+		Indexed().
+		Hash("tokenID", tokenID).
+		Indexed().
 		Emit()
 }
@@ -10,41 +10,51 @@ import * as wasmlib from "wasmlib";
 export class Erc721Events {
 
 	approval(approved: wasmlib.ScAgentID, owner: wasmlib.ScAgentID, tokenID: wasmlib.ScHash): void {
-		new wasmlib.EventEncoder("erc721.approval").
-		agentID(approved).
-		agentID(owner).
-		hash(tokenID).
+		new wasmlib.TypedEventEncoder("erc721.approval").
+		agentID("approved", approved).
+		indexed().
+		agentID("owner", owner).
+		indexed().
+		hash("tokenID", tokenID).
+		indexed().
 		emit();
 	}
 
 	approvalForAll(approval: bool, operator: wasmlib.ScAgentID, owner: wasmlib.ScAgentID): void {
-		new wasmlib.EventEncoder("erc721.approvalForAll").
-		bool(approval).
-		agentID(operator).
-		agentID(owner).
+		new wasmlib.TypedEventEncoder("erc721.approvalForAll").
+		bool("approval", approval).
+		agentID("operator", operator).
+		indexed().
+		agentID("owner", owner).
+		indexed().
 		emit();
 	}
 
 	init(name: string, symbol: string): void {
-		new wasmlib.EventEncoder("erc721.init").
-		string(name).
-		string(symbol).
+		new wasmlib.TypedEventEncoder("erc721.init").
+		string("name", name).
+		string("symbol", symbol).
 		emit();
 	}
 
 	mint(balance: u64, owner: wasmlib.ScAgentID, tokenID: wasmlib.ScHash): void {
-		new wasmlib.EventEncoder("erc721.mint").
-		uint64(balance).
-		agentID(owner).
-		hash(tokenID).
+		new wasmlib.TypedEventEncoder("erc721.mint").
+		uint64("balance", balance).
+		agentID("owner", owner).
+		indexed().
+		hash("tokenID", tokenID).
+		indexed().
 		emit();
 	}
 
 	transfer(from: wasmlib.ScAgentID, to: wasmlib.ScAgentID, tokenID: wasmlib.ScHash): void {
-		new wasmlib.EventEncoder("erc721.transfer").
-		agentID(from).
-		agentID(to).
-		hash(tokenID).
+		new wasmlib.TypedEventEncoder("erc721.transfer").
+		agentID("from", from).
+		indexed().
+		agentID("to", to).
+		indexed().
+		hash("tokenID", tokenID).
+		indexed().
 		emit();
 	}
 }
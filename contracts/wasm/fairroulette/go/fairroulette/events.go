@@ -13,38 +13,41 @@ import "github.com/iotaledger/wasp/packages/vm/wasmlib/go/wasmlib"
 type FairRouletteEvents struct{}
 
 func (e FairRouletteEvents) Bet(address wasmlib.ScAddress, amount int64, number int64) {
-	wasmlib.NewEventEncoder("fairroulette.bet").
-		Address(address).
-		Int64(amount).
-		Int64(number).
+	wasmlib.NewTypedEventEncoder("fairroulette.bet").
+		Address("address", address).
+		Indexed().
+		Int64("amount", amount).
+		Int64("number", number).
 		Emit()
 }
 
 func (e FairRouletteEvents) Payout(address wasmlib.ScAddress, amount int64) {
-	wasmlib.NewEventEncoder("fairroulette.payout").
-		Address(address).
-		Int64(amount).
+	wasmlib.NewTypedEventEncoder("fairroulette.payout").
+		Address("address", address).
+		Indexed().
+		Int64("amount", amount).
 		Emit()
 }
 
 func (e FairRouletteEvents) Round(number int64) {
-	wasmlib.NewEventEncoder("fairroulette.round").
-		Int64(number).
+	wasmlib.NewTypedEventEncoder("fairroulette.round").
+		Int64("number", number).
+		Indexed().
 		Emit()
 }
 
 func (e FairRouletteEvents) Start() {
-	wasmlib.NewEventEncoder("fairroulette.start").
+	wasmlib.NewTypedEventEncoder("fairroulette.start").
 		Emit()
 }
 
 func (e FairRouletteEvents) Stop() {
-	wasmlib.NewEventEncoder("fairroulette.stop").
+	wasmlib.NewTypedEventEncoder("fairroulette.stop").
 		Emit()
 }
 
 func (e FairRouletteEvents) Winner(number int64) {
-	wasmlib.NewEventEncoder("fairroulette.winner").
-		Int64(number).
+	wasmlib.NewTypedEventEncoder("fairroulette.winner").
+		Int64("number", number).
 		Emit()
 }
@@ -15,37 +15,40 @@ pub struct FairRouletteEvents {
 impl FairRouletteEvents {
 
 	pub fn bet(&self, address: &ScAddress, amount: i64, number: i64) {
-		let mut encoder = EventEncoder::new("fairroulette.bet");
-		encoder.address(&address);
-		encoder.int64(amount);
-		encoder.int64(number);
+		let mut encoder = TypedEventEncoder::new("fairroulette.bet");
+		encoder.address("address", &address);
+		encoder.indexed();
+		encoder.int64("amount", amount);
+		encoder.int64("number", number);
 		encoder.emit();
 	}
 
 	pub fn payout(&self, address: &ScAddress, amount: i64) {
-		let mut encoder = EventEncoder::new("fairroulette.payout");
-		encoder.address(&address);
-		encoder.int64(amount);
+		let mut encoder = TypedEventEncoder::new("fairroulette.payout");
+		encoder.address("address", &address);
+		encoder.indexed();
+		encoder.int64("amount", amount);
 		encoder.emit();
 	}
 
 	pub fn round(&self, number: i64) {
-		let mut encoder = EventEncoder::new("fairroulette.round");
-		encoder.int64(number);
+		let mut encoder = TypedEventEncoder::new("fairroulette.round");
+		encoder.int64("number", number);
+		encoder.indexed();
 		encoder.emit();
 	}
 
 	pub fn start(&self) {
-		EventEncoder::new("fairroulette.start").emit();
+		TypedEventEncoder::new("fairroulette.start").emit();
 	}
 
 	pub fn stop(&self) {
-		EventEncoder::new("fairroulette.stop").emit();
+		TypedEventEncoder::new("fairroulette.stop").emit();
 	}
 
 	pub fn winner(&self, number: i64) {
-		let mut encoder = EventEncoder::new("fairroulette.winner");
-		encoder.int64(number);
+		let mut encoder = TypedEventEncoder::new("fairroulette.winner");
+		encoder.int64("number", number);
 		encoder.emit();
 	}
 }
@@ -10,39 +10,42 @@ import * as wasmlib from "wasmlib";
 export class FairRouletteEvents {
 
 	bet(address: wasmlib.ScAddress, amount: i64, number: i64): void {
-		new wasmlib.EventEncoder("fairroulette.bet").
-		address(address).
-		int64(amount).
-		int64(number).
+		new wasmlib.TypedEventEncoder("fairroulette.bet").
+		address("address", address).
+		indexed().
+		int64("amount", amount).
+		int64("number", number).
 		emit();
 	}
 
 	payout(address: wasmlib.ScAddress, amount: i64): void {
-		new wasmlib.EventEncoder("fairroulette.payout").
-		address(address).
-		int64(amount).
+		new wasmlib.TypedEventEncoder("fairroulette.payout").
+		address("address", address).
+		indexed().
+		int64("amount", amount).
 		emit();
 	}
 
 	round(number: i64): void {
-		new wasmlib.EventEncoder("fairroulette.round").
-		int64(number).
+		new wasmlib.TypedEventEncoder("fairroulette.round").
+		int64("number", number).
+		indexed().
 		emit();
 	}
 
 	start(): void {
-		new wasmlib.EventEncoder("fairroulette.start").
+		new wasmlib.TypedEventEncoder("fairroulette.start").
 		emit();
 	}
 
 	stop(): void {
-		new wasmlib.EventEncoder("fairroulette.stop").
+		new wasmlib.TypedEventEncoder("fairroulette.stop").
 		emit();
 	}
 
 	winner(number: i64): void {
-		new wasmlib.EventEncoder("fairroulette.winner").
-		int64(number).
+		new wasmlib.TypedEventEncoder("fairroulette.winner").
+		int64("number", number).
 		emit();
 	}
 }
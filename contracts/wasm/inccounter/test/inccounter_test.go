@@ -5,9 +5,9 @@ package test
 
 import (
 	"testing"
-	"time"
 
 	"github.com/iotaledger/wasp/contracts/wasm/inccounter/go/inccounter"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 	"github.com/iotaledger/wasp/packages/vm/wasmhost"
 	"github.com/iotaledger/wasp/packages/vm/wasmsolo"
 	"github.com/stretchr/testify/require"
@@ -156,22 +156,17 @@ func TestLeb128(t *testing.T) {
 }
 
 func TestLoop(t *testing.T) {
-	if *wasmsolo.GoDebug || *wasmsolo.GoWasmEdge {
-		// no timeout possible because goroutines cannot be killed
-		// or because there is no way to interrupt the Wasm code
+	if *wasmsolo.GoDebug {
+		// Go code is not metered per instruction and goroutines cannot be killed
 		t.SkipNow()
 	}
 
 	ctx := setupTest(t)
 
-	save := wasmhost.DisableWasmTimeout
-	wasmhost.DisableWasmTimeout = false
-	wasmhost.WasmTimeout = 1 * time.Second
 	endlessLoop := inccounter.ScFuncs.EndlessLoop(ctx)
 	endlessLoop.Func.TransferIotas(1).Post()
 	require.Error(t, ctx.Err)
-	require.Contains(t, ctx.Err.Error(), "interrupt")
-	wasmhost.DisableWasmTimeout = save
+	require.Contains(t, ctx.Err.Error(), gas.ErrNotEnoughGas.Error())
 
 	inccounter.ScFuncs.Increment(ctx).Func.TransferIotas(1).Post()
 	require.NoError(t, ctx.Err)
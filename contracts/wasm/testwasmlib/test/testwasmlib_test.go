@@ -181,7 +181,7 @@ func TestViewBlockRecords(t *testing.T) {
 	rec.Func.Call()
 	require.NoError(t, ctx.Err)
 	require.True(t, rec.Results.Record().Exists())
-	require.EqualValues(t, 339, len(rec.Results.Record().Value()))
+	require.EqualValues(t, 419, len(rec.Results.Record().Value()))
 }
 
 func TestClearArray(t *testing.T) {
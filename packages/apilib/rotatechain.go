@@ -0,0 +1,201 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package apilib
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/client/multiclient"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"golang.org/x/xerrors"
+)
+
+// DefaultRotateChainTimeout is the default time to wait for each governance call of the rotation
+const DefaultRotateChainTimeout = 60 * time.Second
+
+// GovernanceCallFn posts the request to the entry point of the governance contract on behalf of the chain owner
+type GovernanceCallFn func(entryPoint string, params dict.Dict) (iscp.RequestID, error)
+
+type RotateChainParams struct {
+	ChainID *iscp.ChainID
+	// AllPeeringHosts are the peers of the chain, put into the chain records of the nodes
+	// which start running the chain
+	AllPeeringHosts       []string
+	CommitteeAPIHosts     []string
+	CommitteePeeringHosts []string
+	T                     uint16
+	// PostGovernanceCall posts the governance requests of the rotation
+	PostGovernanceCall GovernanceCallFn
+	// ClaimOwnership makes the poster of the governance requests claim the ownership of the chain delegated
+	// to it before the rotation
+	ClaimOwnership bool
+	Timeout        time.Duration
+	Textout        io.Writer
+	Prefix         string
+}
+
+// RotateChain performs all actions needed to rotate the chain to a new committee: runs DKG among the new
+// committee nodes, activates the chain on the nodes which don't run it yet, allows the new state controller
+// address and rotates the chain to it. Then it waits until the new committee produces a block.
+// If a step fails before the rotation, the chain is left with the previous committee: the address is removed
+// from the allowed state controllers and the chain is deactivated on the nodes where it was activated.
+// Returns the address of the new committee
+func RotateChain(par RotateChainParams) (ledgerstate.Address, error) {
+	textout := io.Discard
+	if par.Textout != nil {
+		textout = par.Textout
+	}
+	timeout := par.Timeout
+	if timeout == 0 {
+		timeout = DefaultRotateChainTimeout
+	}
+	if len(par.AllPeeringHosts) > 0 && !util.IsSubset(par.CommitteePeeringHosts, par.AllPeeringHosts) {
+		return nil, xerrors.Errorf("RotateChain: committee nodes must all be among peers")
+	}
+	step := func(format string, args ...interface{}) func(err error) {
+		msg := fmt.Sprintf(format, args...)
+		return func(err error) {
+			fmt.Fprint(textout, par.Prefix)
+			if err != nil {
+				fmt.Fprintf(textout, "%s.. FAILED: %v\n", msg, err)
+				return
+			}
+			fmt.Fprintf(textout, "%s.. OK\n", msg)
+		}
+	}
+
+	dkgInitiatorIndex := uint16(rand.Intn(len(par.CommitteeAPIHosts)))
+	stateControllerAddr, err := RunDKG(par.CommitteeAPIHosts, par.CommitteePeeringHosts, par.T, dkgInitiatorIndex)
+	step("running DKG among %d nodes, T = %d", len(par.CommitteeAPIHosts), par.T)(err)
+	if err != nil {
+		return nil, xerrors.Errorf("RotateChain: %w", err)
+	}
+
+	activated, err := activateChainIfNeeded(par.CommitteeAPIHosts, par.AllPeeringHosts, par.ChainID)
+	step("activating chain %s on %d new nodes", par.ChainID.Base58(), len(activated))(err)
+	rollback := func(removeAddress bool) {
+		if removeAddress {
+			_, err := callGovernance(par, governance.FuncRemoveAllowedStateControllerAddress.Name, stateControllerAddr, timeout)
+			step("rollback: removing allowed state controller address %s", stateControllerAddr.Base58())(err)
+		}
+		if len(activated) > 0 {
+			err := multiclient.New(activated).DeactivateChain(par.ChainID)
+			step("rollback: deactivating chain on %d nodes", len(activated))(err)
+		}
+	}
+	if err != nil {
+		rollback(false)
+		return nil, xerrors.Errorf("RotateChain: %w", err)
+	}
+
+	if par.ClaimOwnership {
+		_, err = callGovernance(par, governance.FuncClaimChainOwnership.Name, nil, timeout)
+		step("claiming chain ownership")(err)
+		if err != nil {
+			rollback(false)
+			return nil, xerrors.Errorf("RotateChain: %w", err)
+		}
+	}
+
+	posted, err := callGovernance(par, governance.FuncAddAllowedStateControllerAddress.Name, stateControllerAddr, timeout)
+	step("allowing state controller address %s", stateControllerAddr.Base58())(err)
+	if err != nil {
+		rollback(posted)
+		return nil, xerrors.Errorf("RotateChain: %w", err)
+	}
+
+	posted, err = callGovernance(par, governance.FuncRotateStateController.Name, stateControllerAddr, timeout)
+	step("rotating chain to %s", stateControllerAddr.Base58())(err)
+	if err != nil {
+		if !posted {
+			rollback(true)
+			return nil, xerrors.Errorf("RotateChain: %w", err)
+		}
+		// the rotation may already be on the ledger, it can't be rolled back without the new committee
+		return nil, xerrors.Errorf("RotateChain: the rotation to %s hasn't been confirmed: %w", stateControllerAddr.Base58(), err)
+	}
+
+	err = waitStateController(par.CommitteeAPIHosts, par.ChainID, stateControllerAddr, timeout)
+	step("waiting for the new committee")(err)
+	if err != nil {
+		return nil, xerrors.Errorf("RotateChain: %w", err)
+	}
+	return stateControllerAddr, nil
+}
+
+// activateChainIfNeeded puts the chain records into the nodes which don't run the chain and activates it.
+// Returns the API hosts of the nodes where the chain was activated
+func activateChainIfNeeded(apiHosts, peers []string, chainID *iscp.ChainID) ([]string, error) {
+	activated := make([]string, 0)
+	for _, host := range apiHosts {
+		cl := client.NewWaspClient(host)
+		rec, err := cl.GetChainRecord(chainID)
+		if err == nil && rec.Active {
+			continue
+		}
+		if err = cl.PutChainRecord(&registry.ChainRecord{ChainID: chainID, Peers: peers}); err != nil {
+			return activated, xerrors.Errorf("activateChainIfNeeded: %w", err)
+		}
+		if err = cl.ActivateChain(chainID); err != nil {
+			return activated, xerrors.Errorf("activateChainIfNeeded: %w", err)
+		}
+		activated = append(activated, host)
+	}
+	return activated, nil
+}
+
+// callGovernance posts the governance call with the state controller address, if any, and waits until it is
+// processed successfully by the new committee nodes. Returns true if the request has been posted
+func callGovernance(par RotateChainParams, entryPoint string, addr ledgerstate.Address, timeout time.Duration) (bool, error) {
+	params := dict.New()
+	if addr != nil {
+		params.Set(governance.ParamStateControllerAddress, codec.EncodeAddress(addr))
+	}
+	reqID, err := par.PostGovernanceCall(entryPoint, params)
+	if err != nil {
+		return false, err
+	}
+	if err = multiclient.New(par.CommitteeAPIHosts).WaitUntilRequestProcessed(par.ChainID, reqID, timeout); err != nil {
+		return true, err
+	}
+	receipt, err := client.NewWaspClient(par.CommitteeAPIHosts[0]).RequestReceipt(par.ChainID, reqID)
+	if err != nil {
+		return true, err
+	}
+	if receipt.Error != nil {
+		return true, xerrors.Errorf("%s: %s", entryPoint, receipt.Error.Message)
+	}
+	return true, nil
+}
+
+// waitStateController waits until all nodes see the block produced by the committee with the address
+func waitStateController(apiHosts []string, chainID *iscp.ChainID, addr ledgerstate.Address, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	return multiclient.New(apiHosts).Do(func(i int, cl *client.WaspClient) error {
+		for {
+			ret, err := cl.CallView(chainID, blocklog.Contract.Hname(), blocklog.FuncControlAddresses.Name, nil)
+			if err == nil {
+				var a ledgerstate.Address
+				if a, err = codec.DecodeAddress(ret.MustGet(blocklog.ParamStateControllerAddress)); err == nil && a.Equals(addr) {
+					return nil
+				}
+			}
+			if time.Now().After(deadline) {
+				return xerrors.Errorf("timeout waiting for the state controller %s on %s", addr.Base58(), apiHosts[i])
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/util/ready"
 	"github.com/iotaledger/wasp/packages/vm/processors"
+	"go.dedis.ch/kyber/v3"
 )
 
 type ChainCore interface {
@@ -29,12 +30,15 @@ type ChainCore interface {
 	Processors() *processors.Cache
 	GlobalStateSync() coreutil.ChainStateSync
 	GetStateReader() state.OptimisticStateReader
+	GetHistoricalStateReader(blockIndex uint32) (state.OptimisticStateReader, error)
+	FindBlockIndexByStateHash(stateHash hashing.HashValue) (uint32, error)
 	Log() *logger.Logger
 
 	// Most of these methods are made public for mocking in tests
 	EnqueueDismissChain(reason string) // This one should really be public
 	EnqueueLedgerState(chainOutput *ledgerstate.AliasOutput, timestamp time.Time)
 	EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsgIn)
+	EnqueueOffLedgerRequestBatchMsg(msg *messages.OffLedgerRequestBatchMsgIn)
 	EnqueueRequestAckMsg(msg *messages.RequestAckMsgIn)
 	EnqueueMissingRequestIDsMsg(msg *messages.MissingRequestIDsMsgIn)
 	EnqueueMissingRequestMsg(msg *messages.MissingRequestMsg)
@@ -53,6 +57,8 @@ type ChainEntry interface {
 // ChainRequests is an interface to query status of the request
 type ChainRequests interface {
 	GetRequestProcessingStatus(id iscp.RequestID) RequestProcessingStatus
+	// CheckRequest returns the reason why the mempool of the chain would not accept the request, if any
+	CheckRequest(req iscp.Request) error
 	AttachToRequestProcessed(func(iscp.RequestID)) (attachID *events.Closure)
 	DetachFromRequestProcessed(attachID *events.Closure)
 }
@@ -139,6 +145,8 @@ type StateManager interface {
 	Ready() *ready.Ready
 	EnqueueGetBlockMsg(msg *messages.GetBlockMsgIn)
 	EnqueueBlockMsg(msg *messages.BlockMsgIn)
+	EnqueueGetSnapshotMsg(msg *messages.GetSnapshotMsgIn)
+	EnqueueSnapshotMsg(msg *messages.SnapshotMsgIn)
 	EnqueueStateMsg(msg *messages.StateMsg)
 	EnqueueOutputMsg(msg ledgerstate.Output)
 	EnqueueStateCandidateMsg(state.VirtualStateAccess, ledgerstate.OutputID)
@@ -151,6 +159,7 @@ type Consensus interface {
 	EnqueueStateTransitionMsg(state.VirtualStateAccess, *ledgerstate.AliasOutput, time.Time)
 	EnqueueSignedResultMsg(*messages.SignedResultMsgIn)
 	EnqueueSignedResultAckMsg(*messages.SignedResultAckMsgIn)
+	EnqueueDecryptionSharesMsg(*messages.DecryptionSharesMsgIn)
 	EnqueueInclusionsStateMsg(ledgerstate.TransactionID, ledgerstate.InclusionState)
 	EnqueueAsynchronousCommonSubsetMsg(msg *messages.AsynchronousCommonSubsetMsg)
 	EnqueueVMResultMsg(msg *messages.VMResultMsg)
@@ -164,6 +173,7 @@ type Consensus interface {
 type Mempool interface {
 	ReceiveRequests(reqs ...iscp.Request)
 	ReceiveRequest(req iscp.Request) bool
+	CheckRequest(req iscp.Request) error
 	RemoveRequests(reqs ...iscp.RequestID)
 	ReadyNow(nowis ...time.Time) []iscp.Request
 	ReadyFromIDs(nowis time.Time, reqIDs ...iscp.RequestID) ([]iscp.Request, []int, bool)
@@ -213,6 +223,7 @@ type ReadyListRecord struct {
 
 type CommitteeInfo struct {
 	Address       ledgerstate.Address
+	SharedPublic  kyber.Point
 	Size          uint16
 	Quorum        uint16
 	QuorumIsAlive bool
@@ -0,0 +1,84 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainimpl
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+)
+
+// updateAccessNodes reads the list of access nodes from the 'governance' contract in the committed state and
+// includes them into the chain peers, so that the access nodes can pull blocks from the committee and forward
+// requests to it. Any node outside of the committee follows the chain the same way, access nodes are just the
+// ones known to the committee
+func (c *chainObj) updateAccessNodes(committedState kv.KVStoreReader) {
+	govState := subrealm.NewReadOnly(committedState, kv.Key(governance.Contract.Hname().Bytes()))
+	pubKeys, err := governance.GetAccessNodes(govState)
+	if err != nil {
+		c.log.Errorf("updateAccessNodes: %v", err)
+		return
+	}
+	ownPubKey := c.netProvider.Self().PubKey()
+	netIDs := make([]string, 0, len(pubKeys))
+	for i := range pubKeys {
+		if ownPubKey != nil && *ownPubKey == pubKeys[i] {
+			continue
+		}
+		peer, err := c.netProvider.PeerByPubKey(&pubKeys[i])
+		if err != nil {
+			c.log.Warnf("updateAccessNodes: access node %s is not a known peer: %v", pubKeys[i].String(), err)
+			continue
+		}
+		netIDs = append(netIDs, peer.NetID())
+		peer.Close()
+	}
+
+	c.accessNodesMutex.Lock()
+	defer c.accessNodesMutex.Unlock()
+
+	sort.Strings(netIDs)
+	if !equalStrings(netIDs, c.accessNodeNetIDs) {
+		c.accessNodeNetIDs = netIDs
+		c.chainPeers.UpdatePeers(append(netIDs, c.peerNetworkConfig.Neighbors()...))
+		c.log.Debugf("updateAccessNodes: access nodes of the chain: %+v", netIDs)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getRandomCommitteePeers returns random peers among the configured peers of the chain, i.e. excluding the access nodes.
+// A node, which is not in the committee, forwards requests to them
+func (c *chainObj) getRandomCommitteePeers(upToNumPeers int) []string {
+	c.accessNodesMutex.RLock()
+	defer c.accessNodesMutex.RUnlock()
+
+	ret := make([]string, 0, len(c.peerNetworkConfig.Neighbors()))
+	for _, netID := range c.peerNetworkConfig.Neighbors() {
+		if !util.StringInList(netID, c.accessNodeNetIDs) {
+			ret = append(ret, netID)
+		}
+	}
+	rand.Shuffle(len(ret), func(i, j int) {
+		ret[i], ret[j] = ret[j], ret[i]
+	})
+	if upToNumPeers < len(ret) {
+		ret = ret[:upToNumPeers]
+	}
+	return ret
+}
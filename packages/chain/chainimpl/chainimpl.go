@@ -66,11 +66,15 @@ type chainObj struct {
 	receiveChainPeerMessagesAttachID   interface{}
 	detachFromCommitteePeerMessagesFun func()
 	chainPeers                         peering.PeerDomainProvider
+	accessNodesMutex                   sync.RWMutex
+	accessNodeNetIDs                   []string
 	offLedgerReqsAcksMutex             sync.RWMutex
 	offLedgerReqsAcks                  map[iscp.RequestID][]string
 	offledgerBroadcastUpToNPeers       int
 	offledgerBroadcastInterval         time.Duration
 	pullMissingRequestsFromCommittee   bool
+	maxBatchSize                       uint16
+	vmParallelism                      int
 	chainMetrics                       metrics.ChainMetrics
 	dismissChainMsgPipe                pipe.Pipe
 	stateMsgPipe                       pipe.Pipe
@@ -100,6 +104,11 @@ func NewChain(
 	offledgerBroadcastUpToNPeers int,
 	offledgerBroadcastInterval time.Duration,
 	pullMissingRequestsFromCommittee bool,
+	maxBatchSize uint16,
+	vmParallelism int,
+	snapshotConfig statemgr.SnapshotConfig,
+	historyDepth uint32,
+	mempoolConfig mempool.Config,
 	chainMetrics metrics.ChainMetrics,
 ) chain.Chain {
 	log.Debugf("creating chain object for %s", chainID.String())
@@ -107,7 +116,7 @@ func NewChain(
 	chainLog := log.Named(chainID.Base58()[:6] + ".")
 	chainStateSync := coreutil.NewChainStateSync()
 	ret := &chainObj{
-		mempool:           mempool.New(state.NewOptimisticStateReader(db, chainStateSync), blobProvider, chainLog, chainMetrics),
+		mempool:           mempool.New(state.NewOptimisticStateReader(db, chainStateSync), blobProvider, chainLog, chainMetrics, mempoolConfig),
 		procset:           processors.MustNew(processorConfig),
 		chainID:           chainID,
 		log:               chainLog,
@@ -130,6 +139,8 @@ func NewChain(
 		offledgerBroadcastUpToNPeers:     offledgerBroadcastUpToNPeers,
 		offledgerBroadcastInterval:       offledgerBroadcastInterval,
 		pullMissingRequestsFromCommittee: pullMissingRequestsFromCommittee,
+		maxBatchSize:                     maxBatchSize,
+		vmParallelism:                    vmParallelism,
 		chainMetrics:                     chainMetrics,
 		dismissChainMsgPipe:              pipe.NewLimitInfinitePipe(1),
 		stateMsgPipe:                     pipe.NewLimitInfinitePipe(maxMsgBuffer),
@@ -147,7 +158,7 @@ func NewChain(
 		log.Errorf("NewChain: %v", err)
 		return nil
 	}
-	ret.stateMgr = statemgr.New(db, ret, ret.chainPeers, ret.nodeConn, chainMetrics)
+	ret.stateMgr = statemgr.New(db, ret, ret.chainPeers, ret.nodeConn, chainMetrics, snapshotConfig, historyDepth)
 
 	ret.eventChainTransitionClosure = events.NewClosure(ret.processChainTransition)
 	ret.eventChainTransition.Attach(ret.eventChainTransitionClosure)
@@ -231,6 +242,7 @@ func (c *chainObj) processChainTransition(msg *chain.ChainTransitionEventData) {
 			stateIndex, iscp.OID(msg.ChainOutput.ID()), msg.VirtualState.StateCommitment().String(), c.mempoolLastCleanedIndex)
 		// normal state update:
 		c.stateReader.SetBaseline()
+		c.updateAccessNodes(msg.VirtualState.KVStoreReader())
 		chainID := iscp.NewChainID(msg.ChainOutput.GetAliasAddress())
 		var reqids []iscp.RequestID
 		for i := c.mempoolLastCleanedIndex + 1; i <= msg.VirtualState.BlockIndex(); i++ {
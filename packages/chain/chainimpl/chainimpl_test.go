@@ -3,9 +3,14 @@ package chainimpl
 import (
 	"testing"
 
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 )
 
 func TestValidateOffledger(t *testing.T) {
@@ -20,3 +25,55 @@ func TestValidateOffledger(t *testing.T) {
 	wrongChainReq := testutil.DummyOffledgerRequest(iscp.RandomChainID())
 	require.False(t, c.isRequestValid(wrongChainReq))
 }
+
+func TestRandomCommitteePeers(t *testing.T) {
+	peerNetConfig, err := peering.NewStaticPeerNetworkConfigProvider("localhost:4000", 4000,
+		"localhost:4000", "localhost:4001", "localhost:4002", "localhost:4003")
+	require.NoError(t, err)
+	c := &chainObj{
+		peerNetworkConfig: peerNetConfig,
+		accessNodeNetIDs:  []string{"localhost:4003", "localhost:4004"},
+	}
+	require.ElementsMatch(t, []string{"localhost:4001", "localhost:4002"}, c.getRandomCommitteePeers(10))
+	require.Len(t, c.getRandomCommitteePeers(1), 1)
+	require.NotContains(t, c.getRandomCommitteePeers(1), "localhost:4003")
+}
+
+type dkShareCommittee struct {
+	chain.Committee
+	dkShare *tcrypto.DKShare
+}
+
+func (c *dkShareCommittee) Address() ledgerstate.Address {
+	return c.dkShare.Address
+}
+
+func (c *dkShareCommittee) DKShare() *tcrypto.DKShare {
+	return c.dkShare
+}
+
+func TestIsReshared(t *testing.T) {
+	suite := tcrypto.DefaultSuite()
+	x := suite.G2().Scalar().Pick(suite.RandomStream())
+	pub := suite.G2().Point().Mul(x, nil)
+	dks, err := tcrypto.NewDKShare(0, 1, 1, pub, nil, []kyber.Point{pub}, x)
+	require.NoError(t, err)
+	reg := testutil.NewDkgRegistryProvider(suite)
+	require.NoError(t, reg.SaveDKShare(dks))
+
+	c := &chainObj{dksProvider: reg}
+	cmt := &dkShareCommittee{dkShare: dks}
+	reshared, err := c.isReshared(cmt)
+	require.NoError(t, err)
+	require.False(t, reshared)
+
+	// the same key with another share of the node
+	other := suite.G2().Point().Pick(suite.RandomStream())
+	newShare, err := tcrypto.NewDKShare(1, 2, 2, pub, nil, []kyber.Point{other, pub}, x)
+	require.NoError(t, err)
+	require.True(t, newShare.Address.Equals(dks.Address))
+	require.NoError(t, reg.ReplaceDKShare(newShare))
+	reshared, err = c.isReshared(cmt)
+	require.NoError(t, err)
+	require.True(t, reshared)
+}
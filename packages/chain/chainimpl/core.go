@@ -9,6 +9,7 @@ import (
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 	"github.com/iotaledger/wasp/packages/state"
@@ -26,6 +27,7 @@ func (c *chainObj) GetCommitteeInfo() *chain.CommitteeInfo {
 	}
 	return &chain.CommitteeInfo{
 		Address:       cmt.DKShare().Address,
+		SharedPublic:  cmt.DKShare().SharedPublic,
 		Size:          cmt.Size(),
 		Quorum:        cmt.Quorum(),
 		QuorumIsAlive: cmt.QuorumIsAlive(),
@@ -54,6 +56,17 @@ func (c *chainObj) GetStateReader() state.OptimisticStateReader {
 	return state.NewOptimisticStateReader(c.db, c.chainStateSync)
 }
 
+// GetHistoricalStateReader returns an optimistic reader of the state after the block with the given index.
+// It fails with state.ErrStateNotRetained if the state is older than the retained history
+func (c *chainObj) GetHistoricalStateReader(blockIndex uint32) (state.OptimisticStateReader, error) {
+	return state.NewHistoricalStateReader(c.db, c.chainStateSync, blockIndex)
+}
+
+// FindBlockIndexByStateHash returns the index of the block which produced the state with the given hash
+func (c *chainObj) FindBlockIndexByStateHash(stateHash hashing.HashValue) (uint32, error) {
+	return state.FindBlockIndexByStateHash(c.db, c.chainStateSync, stateHash)
+}
+
 func (c *chainObj) Log() *logger.Logger {
 	return c.log
 }
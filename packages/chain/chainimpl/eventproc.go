@@ -4,6 +4,7 @@
 package chainimpl
 
 import (
+	"bytes"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
@@ -44,7 +45,12 @@ func (c *chainObj) recvLoop() {
 			}
 		case msg, ok := <-offLedgerRequestMsgChannel:
 			if ok {
-				c.handleOffLedgerRequestMsg(msg.(*messages.OffLedgerRequestMsgIn))
+				switch msg := msg.(type) {
+				case *messages.OffLedgerRequestMsgIn:
+					c.handleOffLedgerRequestMsg(msg)
+				case *messages.OffLedgerRequestBatchMsgIn:
+					c.handleOffLedgerRequestBatchMsg(msg)
+				}
 			} else {
 				offLedgerRequestMsgChannel = nil
 			}
@@ -131,11 +137,18 @@ func (c *chainObj) handleLedgerState(msg *messages.StateMsg) {
 
 func (c *chainObj) rotateCommitteeIfNeeded(anchorOutput *ledgerstate.AliasOutput, currentCmt chain.Committee) error {
 	if currentCmt.Address().Equals(anchorOutput.GetStateAddress()) {
-		// nothing changed. no rotation
-		return nil
-	}
-	// address changed
-	if !anchorOutput.GetIsGovernanceUpdated() {
+		// the address stays the same when the key is reshared to another committee
+		reshared, err := c.isReshared(currentCmt)
+		if err != nil {
+			return xerrors.Errorf("rotateCommitteeIfNeeded: %w", err)
+		}
+		if !reshared {
+			// nothing changed. no rotation
+			return nil
+		}
+		c.log.Infof("the key of the committee %s has been reshared", currentCmt.Address().Base58())
+	} else if !anchorOutput.GetIsGovernanceUpdated() {
+		// address changed
 		return xerrors.Errorf("rotateCommitteeIfNeeded: inconsistency. Governance transition expected... New output: %s", anchorOutput.String())
 	}
 	rec, err := c.getOwnCommitteeRecord(anchorOutput.GetStateAddress())
@@ -159,6 +172,16 @@ func (c *chainObj) rotateCommitteeIfNeeded(anchorOutput *ledgerstate.AliasOutput
 	return nil
 }
 
+// isReshared returns true if the share of the key held by the node is not the one the committee was created with.
+// After the resharing the node has another index and the public shares of the new committee
+func (c *chainObj) isReshared(cmt chain.Committee) (bool, error) {
+	dkShare, err := c.dksProvider.LoadDKShare(cmt.Address())
+	if err != nil {
+		return false, xerrors.Errorf("isReshared: loading DKShare: %w", err)
+	}
+	return !bytes.Equal(dkShare.WithoutPrivateShare().Bytes(), cmt.DKShare().WithoutPrivateShare().Bytes()), nil
+}
+
 func (c *chainObj) createCommitteeIfNeeded(anchorOutput *ledgerstate.AliasOutput) error {
 	// check if I am in the committee
 	rec, err := c.getOwnCommitteeRecord(anchorOutput.GetStateAddress())
@@ -214,7 +237,7 @@ func (c *chainObj) createNewCommitteeAndConsensus(cmtRec *registry.CommitteeReco
 		cmtPeerGroup.Detach(attachID)
 	}
 	c.log.Debugf("creating new consensus object...")
-	c.consensus = consensus.New(c, c.mempool, cmt, cmtPeerGroup, c.nodeConn, c.pullMissingRequestsFromCommittee, c.chainMetrics)
+	c.consensus = consensus.New(c, c.mempool, cmt, cmtPeerGroup, c.nodeConn, c.pullMissingRequestsFromCommittee, c.maxBatchSize, c.vmParallelism, c.chainMetrics)
 	c.setCommittee(cmt)
 
 	c.log.Infof("NEW COMMITTEE OF VALIDATORS has been initialized for the state address %s", cmtRec.Address.Base58())
@@ -244,6 +267,49 @@ func (c *chainObj) handleOffLedgerRequestMsg(msg *messages.OffLedgerRequestMsgIn
 	c.log.Debugf("handleOffLedgerRequestMsg message added to mempool and broadcasted: reqID: %s", msg.Req.ID().Base58())
 }
 
+// EnqueueOffLedgerRequestBatchMsg queues the batch of requests received by the webapi of the node.
+// The batch shares the queue with single off-ledger requests, so the order of the requests is kept
+func (c *chainObj) EnqueueOffLedgerRequestBatchMsg(msg *messages.OffLedgerRequestBatchMsgIn) {
+	c.offLedgerRequestPeerMsgPipe.In() <- msg
+	c.chainMetrics.CountMessages()
+}
+
+// handleOffLedgerRequestBatchMsg admits the requests of the batch to the mempool one by one, in the order
+// of the batch. Only the accepted requests are broadcasted. The results are reported to the sender of the batch
+func (c *chainObj) handleOffLedgerRequestBatchMsg(msg *messages.OffLedgerRequestBatchMsgIn) {
+	c.log.Debugf("handleOffLedgerRequestBatchMsg message received, %d requests", len(msg.Reqs))
+	results := make([]error, len(msg.Reqs))
+	accepted := 0
+	for i, req := range msg.Reqs {
+		if !c.isRequestValid(req) {
+			c.log.Errorf("handleOffLedgerRequestBatchMsg request %s ignored: request is not valid", req.ID().Base58())
+			results[i] = xerrors.New("request is not valid")
+			continue
+		}
+		if !c.mempool.ReceiveRequest(req) {
+			if c.mempool.HasRequest(req.ID()) {
+				// the request has been accepted before
+				continue
+			}
+			c.log.Debugf("handleOffLedgerRequestBatchMsg request %s ignored: mempool hasn't accepted it", req.ID().Base58())
+			if results[i] = c.mempool.CheckRequest(req); results[i] == nil {
+				results[i] = xerrors.New("mempool hasn't accepted the request")
+			}
+			continue
+		}
+		c.broadcastOffLedgerRequest(req)
+		accepted++
+	}
+	if msg.Results != nil {
+		select {
+		case msg.Results <- results:
+		default:
+			c.log.Warnf("handleOffLedgerRequestBatchMsg: results of the batch are not received")
+		}
+	}
+	c.log.Debugf("handleOffLedgerRequestBatchMsg %d requests added to mempool and broadcasted", accepted)
+}
+
 func (c *chainObj) sendRequestAcknowledgementMsg(reqID iscp.RequestID, peerID string) {
 	c.log.Debugf("sendRequestAcknowledgementMsg: reqID: %s, peerID: %s", reqID.Base58(), peerID)
 	if peerID == "" {
@@ -264,7 +330,8 @@ func (c *chainObj) broadcastOffLedgerRequest(req *request.OffLedger) {
 		Req:     req,
 	}
 	cmt := c.getCommittee()
-	getPeerIDs := c.chainPeers.GetRandomPeers
+	// nodes outside of the committee, e.g. access nodes, forward requests to the committee
+	getPeerIDs := c.getRandomCommitteePeers
 
 	if cmt != nil {
 		getPeerIDs = cmt.GetRandomValidators
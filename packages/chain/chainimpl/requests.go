@@ -28,6 +28,10 @@ func (c *chainObj) GetRequestProcessingStatus(reqID iscp.RequestID) chain.Reques
 	return chain.RequestProcessingStatusCompleted
 }
 
+func (c *chainObj) CheckRequest(req iscp.Request) error {
+	return c.mempool.CheckRequest(req)
+}
+
 func (c *chainObj) AttachToRequestProcessed(handler func(iscp.RequestID)) *events.Closure {
 	closure := events.NewClosure(handler)
 	c.eventRequestProcessed.Attach(closure)
@@ -6,10 +6,12 @@ package consensus
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/messages"
@@ -22,6 +24,7 @@ import (
 	"github.com/iotaledger/wasp/packages/transaction"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"golang.org/x/xerrors"
 )
 
@@ -35,6 +38,7 @@ func (c *consensus) takeAction() {
 
 	c.proposeBatchIfNeeded()
 	c.runVMIfNeeded()
+	c.broadcastDecryptionSharesIfNeeded()
 	c.broadcastSignedResultIfNeeded()
 	c.checkQuorum()
 	c.postTransactionIfNeeded()
@@ -119,6 +123,11 @@ func (c *consensus) runVMIfNeeded() {
 		return
 	}
 
+	if !c.decryptRequestsIfNeeded(reqs) {
+		c.log.Debugf("runVM not needed: encrypted requests are not decrypted yet")
+		return
+	}
+
 	c.log.Debugf("runVM needed: total number of requests = %d", len(reqs))
 	// here reqs as a set is deterministic. Must be sorted to have fully deterministic list
 	c.sortBatch(reqs)
@@ -232,8 +241,11 @@ func (c *consensus) prepareVMTask(reqs []iscp.Request) *vm.VMTask {
 		Requests:           reqs,
 		Timestamp:          c.consensusBatch.Timestamp,
 		VirtualStateAccess: c.currentState.Copy(),
+		Parallelism:        c.vmParallelism,
 		Log:                c.log,
 	}
+	task.ValidatorParticipation = c.consensusParticipation
+	task.RandomBeacon = c.consensusRandomBeacon
 	task.OnFinish = func(_ dict.Dict, err error, vmError error) {
 		if vmError != nil {
 			c.log.Errorf("runVM OnFinish callback: VM task failed: %v", vmError)
@@ -413,8 +425,16 @@ func (c *consensus) pullInclusionStateIfNeeded() {
 	c.log.Debugf("pullInclusionState: request for inclusion state sent")
 }
 
-// prepareBatchProposal creates a batch proposal structure out of requests
+// prepareBatchProposal creates a batch proposal structure out of requests.
+// The requests come from the mempool ordered by priority fee, so only the first ones which fit into the batch are proposed
 func (c *consensus) prepareBatchProposal(reqs []iscp.Request) *BatchProposal {
+	maxBatchSize := c.maxBatchSize
+	if maxBatchSize == 0 {
+		maxBatchSize = math.MaxUint16
+	}
+	if len(reqs) > int(maxBatchSize) {
+		reqs = reqs[:maxBatchSize]
+	}
 	ts := time.Now()
 	if !ts.After(c.stateTimestamp) {
 		ts = c.stateTimestamp.Add(1 * time.Nanosecond)
@@ -422,30 +442,51 @@ func (c *consensus) prepareBatchProposal(reqs []iscp.Request) *BatchProposal {
 	consensusManaPledge := identity.ID{}
 	accessManaPledge := identity.ID{}
 	feeDestination := iscp.NewAgentID(c.chain.ID().AsAddress(), 0)
-	// sign state output ID. It will be used to produce unpredictable entropy in consensus
-	sigShare, err := c.committee.DKShare().SignShare(c.stateOutput.ID().Bytes())
-	c.assert.RequireNoError(err, fmt.Sprintf("prepareBatchProposal: signing output ID %v failed", iscp.OID(c.stateOutput.ID())))
+	// sign the random beacon message of the next block. It will be used as the random beacon
+	// and to produce unpredictable entropy in consensus
+	sigShare, err := c.committee.DKShare().SignShare(c.randomBeaconMessage())
+	c.assert.RequireNoError(err, fmt.Sprintf("prepareBatchProposal: signing random beacon of the block #%d failed", c.stateOutput.GetStateIndex()+1))
 
 	ret := &BatchProposal{
-		ValidatorIndex:          c.committee.OwnPeerIndex(),
-		StateOutputID:           c.stateOutput.ID(),
-		RequestIDs:              make([]iscp.RequestID, len(reqs)),
-		RequestHashes:           make([][32]byte, len(reqs)),
-		Timestamp:               ts,
-		ConsensusManaPledge:     consensusManaPledge,
-		AccessManaPledge:        accessManaPledge,
-		FeeDestination:          feeDestination,
-		SigShareOfStateOutputID: sigShare,
+		ValidatorIndex:         c.committee.OwnPeerIndex(),
+		StateOutputID:          c.stateOutput.ID(),
+		RequestIDs:             make([]iscp.RequestID, len(reqs)),
+		RequestHashes:          make([][32]byte, len(reqs)),
+		RequestFees:            make([]uint64, len(reqs)),
+		Timestamp:              ts,
+		MaxBatchSize:           maxBatchSize,
+		ConsensusManaPledge:    consensusManaPledge,
+		AccessManaPledge:       accessManaPledge,
+		FeeDestination:         feeDestination,
+		SigShareOfRandomBeacon: sigShare,
 	}
 	for i, req := range reqs {
 		ret.RequestIDs[i] = req.ID()
 		ret.RequestHashes[i] = req.Hash()
+		ret.RequestFees[i] = req.PriorityFee()
 	}
 
 	c.log.Debugf("prepareBatchProposal: proposal prepared")
 	return ret
 }
 
+// committeeNodePubKeys returns the public keys of the committee nodes by their indices. The keys identify
+// the nodes in the validator rewards of the chain
+func (c *consensus) committeeNodePubKeys() map[uint16]ed25519.PublicKey {
+	ret := make(map[uint16]ed25519.PublicKey)
+	for index, node := range c.committeePeerGroup.AllNodes() {
+		if node.PubKey() != nil {
+			ret[index] = *node.PubKey()
+		}
+	}
+	return ret
+}
+
+// randomBeaconMessage is the data signed by the committee to produce the random beacon of the next block
+func (c *consensus) randomBeaconMessage() []byte {
+	return blocklog.RandomBeaconMessage(c.chain.ID(), c.stateOutput.GetStateIndex()+1)
+}
+
 // receiveACS processed new ACS received from ACS consensus
 //nolint:funlen
 func (c *consensus) receiveACS(values [][]byte, sessionID uint64) {
@@ -514,7 +555,7 @@ func (c *consensus) receiveACS(values [][]byte, sessionID uint64) {
 	}
 
 	// calculate intersection of proposals
-	inBatchIDs, inBatchHashes := calcIntersection(acs, c.committee.Size())
+	inBatchIDs, inBatchHashes, inBatchFees := calcIntersection(acs, c.committee.Size())
 	if len(inBatchIDs) == 0 {
 		// if intersection is empty, reset workflow and retry after some time. It means not all requests
 		// reached nodes and we have give it a time. Should not happen often
@@ -532,18 +573,25 @@ func (c *consensus) receiveACS(values [][]byte, sessionID uint64) {
 			c.stateOutput.GetStateIndex(), sessionID, err)
 		c.resetWorkflow()
 		c.delayBatchProposalUntil = time.Now().Add(c.timers.ProposeBatchRetry)
+		return
 	}
+	// under load, the requests with the highest priority fee make it into the batch
+	inBatchIDs, inBatchHashes, inBatchFees = cutoffByFee(inBatchIDs, inBatchHashes, inBatchFees, par.maxBatchSize)
 	c.consensusBatch = &BatchProposal{
 		ValidatorIndex:      c.committee.OwnPeerIndex(),
 		StateOutputID:       c.stateOutput.ID(),
 		RequestIDs:          inBatchIDs,
 		RequestHashes:       inBatchHashes,
+		RequestFees:         inBatchFees,
 		Timestamp:           par.timestamp, // It will be possibly adjusted later, when all requests are received.
+		MaxBatchSize:        par.maxBatchSize,
 		ConsensusManaPledge: par.consensusPledge,
 		AccessManaPledge:    par.accessPledge,
 		FeeDestination:      par.feeDestination,
 	}
 	c.consensusEntropy = par.entropy
+	c.consensusRandomBeacon = par.randomBeacon
+	c.consensusParticipation = calcParticipation(acs, inBatchIDs, c.committeeNodePubKeys())
 
 	c.iAmContributor = iAmContributor
 	c.myContributionSeqNumber = myContributionSeqNumber
@@ -663,8 +711,13 @@ func (c *consensus) resetWorkflow() {
 	c.resultTxEssence = nil
 	c.finalTx = nil
 	c.consensusBatch = nil
+	c.consensusParticipation = nil
+	c.consensusRandomBeacon = nil
 	c.contributors = nil
 	c.resultSigAck = c.resultSigAck[:0]
+	c.decryptionShares = nil
+	c.decryptionSharesMsgs = nil
+	c.ownDecryptionSharesMsg = nil
 	c.workflow = workflowFlags{
 		stateReceived: c.stateOutput != nil,
 		inProgress:    c.stateOutput != nil,
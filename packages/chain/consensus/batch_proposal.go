@@ -4,29 +4,36 @@
 package consensus
 
 import (
+	"bytes"
+	"encoding/binary"
 	"sort"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"go.dedis.ch/kyber/v3/sign/tbls"
 	"golang.org/x/xerrors"
 )
 
 type BatchProposal struct {
-	ValidatorIndex          uint16
-	StateOutputID           ledgerstate.OutputID
-	RequestIDs              []iscp.RequestID
-	RequestHashes           [][32]byte
-	Timestamp               time.Time
-	ConsensusManaPledge     identity.ID
-	AccessManaPledge        identity.ID
-	FeeDestination          *iscp.AgentID
-	SigShareOfStateOutputID tbls.SigShare
+	ValidatorIndex         uint16
+	StateOutputID          ledgerstate.OutputID
+	RequestIDs             []iscp.RequestID
+	RequestHashes          [][32]byte
+	RequestFees            []uint64
+	Timestamp              time.Time
+	MaxBatchSize           uint16
+	ConsensusManaPledge    identity.ID
+	AccessManaPledge       identity.ID
+	FeeDestination         *iscp.AgentID
+	SigShareOfRandomBeacon tbls.SigShare
 }
 
 type consensusBatchParams struct {
@@ -35,6 +42,8 @@ type consensusBatchParams struct {
 	consensusPledge identity.ID
 	feeDestination  *iscp.AgentID
 	entropy         hashing.HashValue
+	randomBeacon    []byte
+	maxBatchSize    uint16
 }
 
 func BatchProposalFromBytes(data []byte) (*BatchProposal, error) {
@@ -70,6 +79,10 @@ func BatchProposalFromMarshalUtil(mu *marshalutil.MarshalUtil) (*BatchProposal,
 	if err != nil {
 		return nil, xerrors.Errorf(errFmt, err)
 	}
+	ret.MaxBatchSize, err = mu.ReadUint16()
+	if err != nil {
+		return nil, xerrors.Errorf(errFmt, err)
+	}
 	size, err := mu.ReadUint16()
 	if err != nil {
 		return nil, xerrors.Errorf(errFmt, err)
@@ -78,11 +91,12 @@ func BatchProposalFromMarshalUtil(mu *marshalutil.MarshalUtil) (*BatchProposal,
 	if sigShareSize, err = mu.ReadByte(); err != nil {
 		return nil, xerrors.Errorf(errFmt, err)
 	}
-	if ret.SigShareOfStateOutputID, err = mu.ReadBytes(int(sigShareSize)); err != nil {
+	if ret.SigShareOfRandomBeacon, err = mu.ReadBytes(int(sigShareSize)); err != nil {
 		return nil, xerrors.Errorf(errFmt, err)
 	}
 	ret.RequestIDs = make([]iscp.RequestID, size)
 	ret.RequestHashes = make([][32]byte, size)
+	ret.RequestFees = make([]uint64, size)
 	for i := range ret.RequestIDs {
 		ret.RequestIDs[i], err = iscp.RequestIDFromMarshalUtil(mu)
 		if err != nil {
@@ -94,6 +108,10 @@ func BatchProposalFromMarshalUtil(mu *marshalutil.MarshalUtil) (*BatchProposal,
 		if err != nil {
 			return nil, xerrors.Errorf(errFmt, err)
 		}
+		ret.RequestFees[i], err = mu.ReadUint64()
+		if err != nil {
+			return nil, xerrors.Errorf(errFmt, err)
+		}
 	}
 	return ret, nil
 }
@@ -106,12 +124,14 @@ func (b *BatchProposal) Bytes() []byte {
 		Write(b.ConsensusManaPledge).
 		Write(b.FeeDestination).
 		WriteTime(b.Timestamp).
+		WriteUint16(b.MaxBatchSize).
 		WriteUint16(uint16(len(b.RequestIDs))).
-		WriteByte(byte(len(b.SigShareOfStateOutputID))).
-		WriteBytes(b.SigShareOfStateOutputID)
+		WriteByte(byte(len(b.SigShareOfRandomBeacon))).
+		WriteBytes(b.SigShareOfRandomBeacon)
 	for i := range b.RequestIDs {
 		mu.Write(b.RequestIDs[i])
 		mu.WriteBytes(b.RequestHashes[i][:])
+		mu.WriteUint64(b.RequestFees[i])
 	}
 	return mu.Bytes()
 }
@@ -143,6 +163,7 @@ func (b *BatchProposal) EnsureTimestampConsistent(requests []iscp.Request, state
 // mana pledges and fee destination.
 //
 // Timestamp is calculated by taking maximal proposed timestamp excluding F highest proposals.
+// The maximal batch size is calculated the same way, so it cannot be imposed by the faulty nodes.
 //
 // TODO final version of pledges and fee destination
 func (c *consensus) calcBatchParameters(props []*BatchProposal) (*consensusBatchParams, error) {
@@ -159,24 +180,39 @@ func (c *consensus) calcBatchParameters(props []*BatchProposal) (*consensusBatch
 	maxFaulty := c.committee.Size() - c.committee.Quorum() // T = N-F ==> F = N-T
 	retTS = ts[proposalCount-int(maxFaulty)-1]             // Max(|acsProposals|-F Lowest) ~= 66 percentile.
 
+	sizes := make([]uint16, len(props))
+	for i := range sizes {
+		sizes[i] = props[i].MaxBatchSize
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		return sizes[i] < sizes[j]
+	})
+	retMaxBatchSize := sizes[proposalCount-int(maxFaulty)-1]
+
 	indices := make([]uint16, len(props))
 	for i := range indices {
 		indices[i] = uint16(i)
 	}
 	// verify signatures calculate entropy
+	beaconMsg := c.randomBeaconMessage()
 	sigSharesToAggregate := make([][]byte, len(props))
 	for i, prop := range props {
-		err := c.committee.DKShare().VerifySigShare(c.stateOutput.ID().Bytes(), prop.SigShareOfStateOutputID)
+		err := c.committee.DKShare().VerifySigShare(beaconMsg, prop.SigShareOfRandomBeacon)
 		if err != nil {
 			return nil, xerrors.Errorf("INVALID SIGNATURE in ACS from peer #%d: %v", prop.ValidatorIndex, err)
 		}
-		sigSharesToAggregate[i] = prop.SigShareOfStateOutputID
+		// the signature share authenticates the validator index of the proposal
+		if idx, err := prop.SigShareOfRandomBeacon.Index(); err != nil || idx != int(prop.ValidatorIndex) {
+			return nil, xerrors.Errorf("INVALID SIGNATURE in ACS from peer #%d: signed by another validator", prop.ValidatorIndex)
+		}
+		sigSharesToAggregate[i] = prop.SigShareOfRandomBeacon
 	}
-	// aggregate signatures for use as unpredictable entropy
-	signatureWithPK, err := c.committee.DKShare().RecoverFullSignature(sigSharesToAggregate, c.stateOutput.ID().Bytes())
+	// aggregate signatures into the random beacon of the block. It is also used as unpredictable entropy
+	signatureWithPK, err := c.committee.DKShare().RecoverFullSignature(sigSharesToAggregate, beaconMsg)
 	if err != nil {
 		return nil, xerrors.Errorf("recovering signature from ACS: %v", err)
 	}
+	randomBeacon := signatureWithPK.Bytes()
 
 	// selects pseudo-random based on seed, the calculated timestamp
 	selectedIndex := util.SelectDeterministicRandomUint16(indices, retTS.UnixNano())
@@ -185,26 +221,64 @@ func (c *consensus) calcBatchParameters(props []*BatchProposal) (*consensusBatch
 		accessPledge:    props[selectedIndex].AccessManaPledge,
 		consensusPledge: props[selectedIndex].ConsensusManaPledge,
 		feeDestination:  props[selectedIndex].FeeDestination,
-		entropy:         hashing.HashData(signatureWithPK.Bytes()),
+		entropy:         blocklog.RandomSeed(randomBeacon),
+		randomBeacon:    randomBeacon,
+		maxBatchSize:    retMaxBatchSize,
 	}, nil
 }
 
-const keyLen = ledgerstate.OutputIDLength + 32
+// calcParticipation calculates the weights of the ACS participants in the validator fees of the block.
+// The weight of a participant is the number of the batch requests included in its proposal. The participant
+// is identified by the public key of the committee node with the validator index of the proposal.
+// The result is ordered by the validator index, because ACS returns the proposals in a random order
+func calcParticipation(acs []*BatchProposal, batchIDs []iscp.RequestID, nodePubKeys map[uint16]ed25519.PublicKey) []*governance.ValidatorParticipation {
+	inBatch := make(map[iscp.RequestID]struct{}, len(batchIDs))
+	for _, reqID := range batchIDs {
+		inBatch[reqID] = struct{}{}
+	}
+	props := make([]*BatchProposal, len(acs))
+	copy(props, acs)
+	sort.Slice(props, func(i, j int) bool {
+		return props[i].ValidatorIndex < props[j].ValidatorIndex
+	})
+	ret := make([]*governance.ValidatorParticipation, 0, len(props))
+	for _, prop := range props {
+		var weight uint64
+		for _, reqID := range prop.RequestIDs {
+			if _, ok := inBatch[reqID]; ok {
+				weight++
+			}
+		}
+		pubKey, ok := nodePubKeys[prop.ValidatorIndex]
+		if weight == 0 || !ok {
+			continue
+		}
+		ret = append(ret, &governance.ValidatorParticipation{
+			NodePubKey: pubKey,
+			Weight:     weight,
+		})
+	}
+	return ret
+}
+
+const keyLen = ledgerstate.OutputIDLength + 32 + 8
 
 // calcIntersection a simple algorithm to calculate acceptable intersection. It simply takes all requests
 // seen by 1/3+1 node. The assumptions is there can be at max 1/3 of bizantine nodes, so if something is reported
 // by more that 1/3 of nodes it means it is correct
-func calcIntersection(acs []*BatchProposal, n uint16) ([]iscp.RequestID, [][32]byte) {
+func calcIntersection(acs []*BatchProposal, n uint16) ([]iscp.RequestID, [][32]byte, []uint64) {
 	minNumberMentioned := n/3 + 1
 	numMentioned := make(map[[keyLen]byte]uint16)
 
 	maxLen := 0
 	for _, prop := range acs {
 		for i, reqid := range prop.RequestIDs {
-			// save ID + Hash as key to avoid batching requests where different nodes have mismatching request content with the same ID
+			// save ID + Hash + Fee as key to avoid batching requests where different nodes have mismatching request
+			// content with the same ID, or report a different priority fee for it
 			hash := prop.RequestHashes[i]
 			var key [keyLen]byte
 			copy(key[:], append(reqid.Bytes(), hash[:]...))
+			binary.LittleEndian.PutUint64(key[len(key)-8:], prop.RequestFees[i])
 			numMentioned[key]++
 		}
 		if len(prop.RequestIDs) > maxLen {
@@ -213,18 +287,47 @@ func calcIntersection(acs []*BatchProposal, n uint16) ([]iscp.RequestID, [][32]b
 	}
 	retIDs := make([]iscp.RequestID, 0, maxLen)
 	retHashes := make([][32]byte, 0)
+	retFees := make([]uint64, 0)
 	for key, num := range numMentioned {
 		if num < minNumberMentioned {
 			continue
 		}
-		reqID, err := iscp.RequestIDFromBytes(key[:])
+		reqID, err := iscp.RequestIDFromBytes(key[:ledgerstate.OutputIDLength])
 		if err != nil {
 			continue
 		}
 		retIDs = append(retIDs, reqID)
 		var hash [32]byte
-		copy(hash[:], key[len(key)-32:])
+		copy(hash[:], key[ledgerstate.OutputIDLength:ledgerstate.OutputIDLength+32])
 		retHashes = append(retHashes, hash)
+		retFees = append(retFees, binary.LittleEndian.Uint64(key[len(key)-8:]))
+	}
+	return retIDs, retHashes, retFees
+}
+
+// cutoffByFee deterministically orders the requests by priority fee, the highest first, then by request ID,
+// and leaves at most maxBatchSize of them. The order of execution is decided later, by the consensus entropy
+func cutoffByFee(ids []iscp.RequestID, hashes [][32]byte, fees []uint64, maxBatchSize uint16) ([]iscp.RequestID, [][32]byte, []uint64) {
+	idx := make([]int, len(ids))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool {
+		if fees[idx[i]] != fees[idx[j]] {
+			return fees[idx[i]] > fees[idx[j]]
+		}
+		return bytes.Compare(ids[idx[i]][:], ids[idx[j]][:]) < 0
+	})
+	if maxBatchSize > 0 && len(idx) > int(maxBatchSize) {
+		idx = idx[:maxBatchSize]
+	}
+	retIDs := make([]iscp.RequestID, len(idx))
+	retHashes := make([][32]byte, len(idx))
+	retFees := make([]uint64, len(idx))
+	for i, j := range idx {
+		retIDs[i] = ids[j]
+		retHashes[i] = hashes[j]
+		retFees[i] = fees[j]
 	}
-	return retIDs, retHashes
+	return retIDs, retHashes, retFees
 }
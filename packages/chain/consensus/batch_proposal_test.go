@@ -0,0 +1,106 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/stretchr/testify/require"
+)
+
+func testRequestID(i byte) iscp.RequestID {
+	return iscp.RequestID(ledgerstate.NewOutputID(ledgerstate.TransactionID{i}, 0))
+}
+
+func testProposal(index uint16, ids []iscp.RequestID, fees []uint64) *BatchProposal {
+	ret := &BatchProposal{
+		ValidatorIndex: index,
+		RequestIDs:     ids,
+		RequestHashes:  make([][32]byte, len(ids)),
+		RequestFees:    fees,
+		Timestamp:      time.Now(),
+		MaxBatchSize:   10,
+		FeeDestination: iscp.NewAgentID(ledgerstate.NewED25519Address(ed25519.PublicKey{}), 0),
+	}
+	for i, id := range ids {
+		ret.RequestHashes[i] = [32]byte{id[0]}
+	}
+	return ret
+}
+
+func TestBatchProposalMarshal(t *testing.T) {
+	prop := testProposal(2, []iscp.RequestID{testRequestID(1), testRequestID(2)}, []uint64{0, 5})
+	back, err := BatchProposalFromBytes(prop.Bytes())
+	require.NoError(t, err)
+	require.EqualValues(t, prop.RequestIDs, back.RequestIDs)
+	require.EqualValues(t, prop.RequestHashes, back.RequestHashes)
+	require.EqualValues(t, prop.RequestFees, back.RequestFees)
+	require.EqualValues(t, prop.MaxBatchSize, back.MaxBatchSize)
+}
+
+func TestCalcIntersectionFees(t *testing.T) {
+	r1, r2, r3 := testRequestID(1), testRequestID(2), testRequestID(3)
+	acs := []*BatchProposal{
+		testProposal(0, []iscp.RequestID{r1, r2, r3}, []uint64{1, 2, 3}),
+		testProposal(1, []iscp.RequestID{r1, r2, r3}, []uint64{1, 2, 3}),
+		// a faulty node reporting a different fee for r3
+		testProposal(2, []iscp.RequestID{r1, r2, r3}, []uint64{1, 2, 100}),
+		testProposal(3, []iscp.RequestID{r1}, []uint64{1}),
+	}
+	ids, hashes, fees := calcIntersection(acs, 4)
+	require.Len(t, ids, 3)
+	require.Len(t, hashes, 3)
+	for i, id := range ids {
+		require.EqualValues(t, uint64(id[0]), fees[i])
+		require.EqualValues(t, id[0], hashes[i][0])
+	}
+}
+
+func TestCutoffByFee(t *testing.T) {
+	r1, r2, r3, r4 := testRequestID(1), testRequestID(2), testRequestID(3), testRequestID(4)
+	ids := []iscp.RequestID{r4, r1, r3, r2}
+	hashes := [][32]byte{{4}, {1}, {3}, {2}}
+	fees := []uint64{0, 5, 5, 9}
+
+	retIDs, retHashes, retFees := cutoffByFee(ids, hashes, fees, 3)
+	require.EqualValues(t, []iscp.RequestID{r2, r1, r3}, retIDs)
+	require.EqualValues(t, [][32]byte{{2}, {1}, {3}}, retHashes)
+	require.EqualValues(t, []uint64{9, 5, 5}, retFees)
+
+	// the result does not depend on the order of the input
+	retIDs2, _, _ := cutoffByFee([]iscp.RequestID{r2, r3, r1, r4}, [][32]byte{{2}, {3}, {1}, {4}}, []uint64{9, 5, 5, 0}, 3)
+	require.EqualValues(t, retIDs, retIDs2)
+
+	retIDs, _, _ = cutoffByFee(ids, hashes, fees, 0)
+	require.Len(t, retIDs, 4)
+}
+
+func TestCalcParticipation(t *testing.T) {
+	r1, r2, r3 := testRequestID(1), testRequestID(2), testRequestID(3)
+	acs := []*BatchProposal{
+		testProposal(2, []iscp.RequestID{r1, r2, r3}, []uint64{0, 0, 0}),
+		// the node without the public key
+		testProposal(4, []iscp.RequestID{r1, r2}, []uint64{0, 0}),
+		testProposal(0, []iscp.RequestID{r1, r3}, []uint64{0, 0}),
+		// none of the proposed requests made it into the batch
+		testProposal(3, []iscp.RequestID{r3}, []uint64{0}),
+		testProposal(1, []iscp.RequestID{r2}, []uint64{0}),
+	}
+	nodePubKeys := map[uint16]ed25519.PublicKey{}
+	for i := uint16(0); i < 4; i++ {
+		nodePubKeys[i] = ed25519.PublicKey{byte(i + 1)}
+	}
+	ret := calcParticipation(acs, []iscp.RequestID{r1, r2}, nodePubKeys)
+	require.Len(t, ret, 3)
+	require.EqualValues(t, ed25519.PublicKey{1}, ret[0].NodePubKey)
+	require.EqualValues(t, 1, ret[0].Weight)
+	require.EqualValues(t, ed25519.PublicKey{2}, ret[1].NodePubKey)
+	require.EqualValues(t, 1, ret[1].Weight)
+	require.EqualValues(t, ed25519.PublicKey{3}, ret[2].NodePubKey)
+	require.EqualValues(t, 2, ret[2].Weight)
+}
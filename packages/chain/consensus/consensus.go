@@ -17,8 +17,10 @@ import (
 	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/util/pipe"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/runvm"
 	"go.uber.org/atomic"
 )
@@ -37,6 +39,8 @@ type consensus struct {
 	acsSessionID                     uint64
 	consensusBatch                   *BatchProposal
 	consensusEntropy                 hashing.HashValue
+	consensusParticipation           []*governance.ValidatorParticipation
+	consensusRandomBeacon            []byte
 	iAmContributor                   bool
 	myContributionSeqNumber          uint16
 	contributors                     []uint16
@@ -48,6 +52,10 @@ type consensus struct {
 	resultState                      state.VirtualStateAccess
 	resultSignatures                 []*messages.SignedResultMsgIn
 	resultSigAck                     []uint16
+	decryptionShares                 map[iscp.RequestID]map[uint16]*tcrypto.DecryptionShare
+	decryptionSharesMsgs             []*messages.DecryptionSharesMsgIn
+	ownDecryptionSharesMsg           *messages.DecryptionSharesMsg
+	delaySendingDecryptionShares     time.Time
 	finalTx                          *ledgerstate.Transaction
 	postTxDeadline                   time.Time
 	pullInclusionStateDeadline       time.Time
@@ -58,6 +66,7 @@ type consensus struct {
 	eventStateTransitionMsgPipe      pipe.Pipe
 	eventSignedResultMsgPipe         pipe.Pipe
 	eventSignedResultAckMsgPipe      pipe.Pipe
+	eventDecryptionSharesMsgPipe     pipe.Pipe
 	eventInclusionStateMsgPipe       pipe.Pipe
 	eventACSMsgPipe                  pipe.Pipe
 	eventVMResultMsgPipe             pipe.Pipe
@@ -66,6 +75,8 @@ type consensus struct {
 	missingRequestsFromBatch         map[iscp.RequestID][32]byte
 	missingRequestsMutex             sync.Mutex
 	pullMissingRequestsFromCommittee bool
+	maxBatchSize                     uint16
+	vmParallelism                    int
 	receivePeerMessagesAttachID      interface{}
 	consensusMetrics                 metrics.ConsensusMetrics
 }
@@ -74,6 +85,7 @@ type workflowFlags struct {
 	stateReceived        bool
 	batchProposalSent    bool
 	consensusBatchKnown  bool
+	requestsDecrypted    bool
 	vmStarted            bool
 	vmResultSigned       bool
 	transactionFinalized bool
@@ -87,11 +99,12 @@ var _ chain.Consensus = &consensus{}
 const (
 	peerMsgTypeSignedResult = iota
 	peerMsgTypeSignedResultAck
+	peerMsgTypeDecryptionShares
 
 	maxMsgBuffer = 1000
 )
 
-func New(chainCore chain.ChainCore, mempool chain.Mempool, committee chain.Committee, peerGroup peering.GroupProvider, nodeConn chain.ChainNodeConnection, pullMissingRequestsFromCommittee bool, consensusMetrics metrics.ConsensusMetrics, timersOpt ...ConsensusTimers) chain.Consensus {
+func New(chainCore chain.ChainCore, mempool chain.Mempool, committee chain.Committee, peerGroup peering.GroupProvider, nodeConn chain.ChainNodeConnection, pullMissingRequestsFromCommittee bool, maxBatchSize uint16, vmParallelism int, consensusMetrics metrics.ConsensusMetrics, timersOpt ...ConsensusTimers) chain.Consensus {
 	var timers ConsensusTimers
 	if len(timersOpt) > 0 {
 		timers = timersOpt[0]
@@ -113,12 +126,15 @@ func New(chainCore chain.ChainCore, mempool chain.Mempool, committee chain.Commi
 		eventStateTransitionMsgPipe:      pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventSignedResultMsgPipe:         pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventSignedResultAckMsgPipe:      pipe.NewLimitInfinitePipe(maxMsgBuffer),
+		eventDecryptionSharesMsgPipe:     pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventInclusionStateMsgPipe:       pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventACSMsgPipe:                  pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventVMResultMsgPipe:             pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventTimerMsgPipe:                pipe.NewLimitInfinitePipe(1),
 		assert:                           assert.NewAssert(log),
 		pullMissingRequestsFromCommittee: pullMissingRequestsFromCommittee,
+		maxBatchSize:                     maxBatchSize,
+		vmParallelism:                    vmParallelism,
 		consensusMetrics:                 consensusMetrics,
 	}
 	ret.receivePeerMessagesAttachID = ret.committeePeerGroup.Attach(peering.PeerMessageReceiverConsensus, ret.receiveCommitteePeerMessages)
@@ -152,6 +168,16 @@ func (c *consensus) receiveCommitteePeerMessages(peerMsg *peering.PeerMessageGro
 			SignedResultAckMsg: *msg,
 			SenderIndex:        peerMsg.SenderIndex,
 		})
+	case peerMsgTypeDecryptionShares:
+		msg, err := messages.NewDecryptionSharesMsg(peerMsg.MsgData)
+		if err != nil {
+			c.log.Error(err)
+			return
+		}
+		c.EnqueueDecryptionSharesMsg(&messages.DecryptionSharesMsgIn{
+			DecryptionSharesMsg: *msg,
+			SenderIndex:         peerMsg.SenderIndex,
+		})
 	default:
 		c.log.Warnf("Wrong type of consensus message: %v, ignoring it", peerMsg.MsgType)
 	}
@@ -168,6 +194,7 @@ func (c *consensus) Close() {
 	c.eventStateTransitionMsgPipe.Close()
 	c.eventSignedResultMsgPipe.Close()
 	c.eventSignedResultAckMsgPipe.Close()
+	c.eventDecryptionSharesMsgPipe.Close()
 	c.eventInclusionStateMsgPipe.Close()
 	c.eventACSMsgPipe.Close()
 	c.eventVMResultMsgPipe.Close()
@@ -178,6 +205,7 @@ func (c *consensus) recvLoop() {
 	eventStateTransitionMsgCh := c.eventStateTransitionMsgPipe.Out()
 	eventSignedResultMsgCh := c.eventSignedResultMsgPipe.Out()
 	eventSignedResultAckMsgCh := c.eventSignedResultAckMsgPipe.Out()
+	eventDecryptionSharesMsgCh := c.eventDecryptionSharesMsgPipe.Out()
 	eventInclusionStateMsgCh := c.eventInclusionStateMsgPipe.Out()
 	eventACSMsgCh := c.eventACSMsgPipe.Out()
 	eventVMResultMsgCh := c.eventVMResultMsgPipe.Out()
@@ -186,6 +214,7 @@ func (c *consensus) recvLoop() {
 		return eventStateTransitionMsgCh == nil &&
 			eventSignedResultMsgCh == nil &&
 			eventSignedResultAckMsgCh == nil &&
+			eventDecryptionSharesMsgCh == nil &&
 			eventInclusionStateMsgCh == nil &&
 			eventACSMsgCh == nil &&
 			eventVMResultMsgCh == nil &&
@@ -227,6 +256,14 @@ func (c *consensus) recvLoop() {
 			} else {
 				eventSignedResultAckMsgCh = nil
 			}
+		case msg, ok := <-eventDecryptionSharesMsgCh:
+			if ok {
+				c.log.Debugf("Consensus::recvLoop, handleDecryptionSharesMsg...")
+				c.handleDecryptionSharesMsg(msg.(*messages.DecryptionSharesMsgIn))
+				c.log.Debugf("Consensus::recvLoop, handleDecryptionSharesMsg... Done")
+			} else {
+				eventDecryptionSharesMsgCh = nil
+			}
 		case msg, ok := <-eventInclusionStateMsgCh:
 			if ok {
 				c.log.Debugf("Consensus::recvLoop, eventInclusionState...")
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/iotaledger/wasp/packages/chain/consensus"
+	"github.com/iotaledger/wasp/packages/iscp/request"
 	"github.com/stretchr/testify/require"
 )
 
@@ -99,6 +100,58 @@ func TestConsensusPostRequestMockedACS(t *testing.T) {
 	})
 }
 
+func TestConsensusPostEncryptedRequestsMockedACS(t *testing.T) {
+	env, _ := consensus.NewMockedEnvWithMockedACS(t, 4, 3, false)
+	env.CreateNodes(consensus.NewConsensusTimers())
+	defer env.Log.Sync()
+	env.StartTimers()
+	env.SetInitialConsensusState()
+	reqsByNode := env.PostDummyEncryptedRequests(10)
+	err := env.WaitMempool(10, 3, 5*time.Second)
+	require.NoError(t, err)
+
+	decrypted := 0
+	for _, reqs := range reqsByNode {
+		ok := true
+		for _, req := range reqs {
+			ok = ok && request.IsDecrypted(req)
+		}
+		if ok {
+			decrypted++
+		}
+	}
+	require.GreaterOrEqual(t, decrypted, 3)
+}
+
+func TestConsensusCopiedEncryptedRequestsMockedACS(t *testing.T) {
+	env, _ := consensus.NewMockedEnvWithMockedACS(t, 4, 3, false)
+	env.CreateNodes(consensus.NewConsensusTimers())
+	defer env.Log.Sync()
+	env.StartTimers()
+	env.SetInitialConsensusState()
+	reqsByNode := env.PostDummyEncryptedRequests(5)
+	// the envelopes are copied to the requests of another sender
+	copiesByNode := env.PostCopiedEncryptedRequests(reqsByNode[0])
+	err := env.WaitMempool(10, 3, 5*time.Second)
+	require.NoError(t, err)
+
+	decrypted := 0
+	for i, reqs := range reqsByNode {
+		ok := true
+		for _, req := range reqs {
+			ok = ok && request.IsDecrypted(req)
+		}
+		if ok {
+			decrypted++
+		}
+		// no node produces the decryption shares of the copied envelopes
+		for _, req := range copiesByNode[i] {
+			require.False(t, request.IsDecrypted(req))
+		}
+	}
+	require.GreaterOrEqual(t, decrypted, 3)
+}
+
 func TestConsensusMoreNodesMockedACS(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
@@ -0,0 +1,178 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// The encrypted requests are decrypted only after the ACS has fixed the batch, so nobody can front-run them.
+// Each node broadcasts its decryption shares of the encrypted requests of the batch. Once a quorum of valid
+// shares is collected for each of them, the node opens the envelopes and runs the VM.
+// The result of the decryption is deterministic, so all honest nodes run the VM on the same arguments.
+// A request which can't be decrypted is run with the envelope and fails in the VM.
+
+// decryptRequestsIfNeeded returns true if there are no encrypted requests in the batch left to decrypt
+func (c *consensus) decryptRequestsIfNeeded(reqs []iscp.Request) bool {
+	if c.workflow.requestsDecrypted {
+		return true
+	}
+	dkShare := c.committee.DKShare()
+	encrypted := make(map[iscp.RequestID]iscp.Request)
+	for _, req := range reqs {
+		if _, ok := request.EncryptedArgs(req); !ok {
+			continue
+		}
+		if _, err := request.CheckEncryptedArgs(dkShare, c.chain.ID(), req); err != nil {
+			// every node rejects the malformed envelope or the one copied from another request,
+			// the request will fail in the VM
+			c.log.Warnf("decryptRequests: request %s: %v", req.ID().Base58(), err)
+			continue
+		}
+		encrypted[req.ID()] = req
+	}
+	if len(encrypted) == 0 {
+		c.workflow.requestsDecrypted = true
+		return true
+	}
+	if c.ownDecryptionSharesMsg == nil {
+		if err := c.prepareOwnDecryptionShares(encrypted); err != nil {
+			c.log.Errorf("decryptRequests: %v", err)
+			return false
+		}
+		c.broadcastDecryptionSharesIfNeeded()
+	}
+	c.processDecryptionSharesMsgs(encrypted)
+
+	for reqID := range encrypted {
+		if len(c.decryptionShares[reqID]) < int(dkShare.T) {
+			c.log.Debugf("runVM not needed: %d of %d decryption shares of the request %s",
+				len(c.decryptionShares[reqID]), dkShare.T, reqID.Base58())
+			return false
+		}
+	}
+	for reqID, req := range encrypted {
+		ciphertext, _ := request.EncryptedArgs(req)
+		shares := make([]*tcrypto.DecryptionShare, 0, len(c.decryptionShares[reqID]))
+		for _, s := range c.decryptionShares[reqID] {
+			shares = append(shares, s)
+		}
+		plaintext, err := dkShare.Decrypt(ciphertext, shares, request.EncryptionAdditionalData(c.chain.ID(), req))
+		if err == nil {
+			err = request.SetDecryptedArgs(req, plaintext)
+		}
+		if err != nil {
+			c.log.Warnf("decryptRequests: request %s can't be decrypted: %v", reqID.Base58(), err)
+		}
+	}
+	c.log.Debugf("decryptRequests: %d encrypted requests decrypted", len(encrypted))
+	c.workflow.requestsDecrypted = true
+	return true
+}
+
+// prepareOwnDecryptionShares produces the decryption shares of the node for the encrypted requests of the batch
+func (c *consensus) prepareOwnDecryptionShares(encrypted map[iscp.RequestID]iscp.Request) error {
+	dkShare := c.committee.DKShare()
+	msg := &messages.DecryptionSharesMsg{
+		ChainInputID: c.stateOutput.ID(),
+		RequestIDs:   make([]iscp.RequestID, 0, len(encrypted)),
+		Shares:       make([][]byte, 0, len(encrypted)),
+	}
+	decShares := make([]*tcrypto.DecryptionShare, 0, len(encrypted))
+	for reqID, req := range encrypted {
+		ciphertext, _ := request.EncryptedArgs(req)
+		// the private share may be held by the signer process, which is not reachable now
+		decShare, err := dkShare.DecryptShare(ciphertext, request.EncryptionAdditionalData(c.chain.ID(), req))
+		if err != nil {
+			return xerrors.Errorf("prepareOwnDecryptionShares: request %s: %w", reqID.Base58(), err)
+		}
+		decShares = append(decShares, decShare)
+		msg.RequestIDs = append(msg.RequestIDs, reqID)
+		msg.Shares = append(msg.Shares, decShare.Bytes())
+	}
+	for i, reqID := range msg.RequestIDs {
+		c.storeDecryptionShare(reqID, c.committee.OwnPeerIndex(), decShares[i])
+	}
+	c.ownDecryptionSharesMsg = msg
+	return nil
+}
+
+// broadcastDecryptionSharesIfNeeded sends the own decryption shares to the committee until the transaction
+// is finalized, so the peers which were late get them too
+func (c *consensus) broadcastDecryptionSharesIfNeeded() {
+	if c.ownDecryptionSharesMsg == nil || c.workflow.transactionFinalized {
+		return
+	}
+	if time.Now().Before(c.delaySendingDecryptionShares) {
+		c.log.Debugf("broadcastDecryptionShares not needed: delayed till %v", c.delaySendingDecryptionShares)
+		return
+	}
+	c.committeePeerGroup.SendMsgBroadcast(peering.PeerMessageReceiverConsensus, peerMsgTypeDecryptionShares,
+		util.MustBytes(c.ownDecryptionSharesMsg))
+	c.delaySendingDecryptionShares = time.Now().Add(c.timers.BroadcastDecryptionSharesRetry)
+	c.log.Debugf("broadcastDecryptionShares: broadcasted %d decryption shares, chain input %s",
+		len(c.ownDecryptionSharesMsg.RequestIDs), iscp.OID(c.ownDecryptionSharesMsg.ChainInputID))
+}
+
+// receiveDecryptionShares keeps the message until the shares can be verified against the requests of the batch
+func (c *consensus) receiveDecryptionShares(msg *messages.DecryptionSharesMsgIn) {
+	if c.stateOutput == nil || msg.ChainInputID != c.stateOutput.ID() {
+		c.log.Debugf("receiveDecryptionShares: out of context chain input ID %v from peer #%d",
+			iscp.OID(msg.ChainInputID), msg.SenderIndex)
+		return
+	}
+	if msg.SenderIndex >= c.committee.Size() || msg.SenderIndex == c.committee.OwnPeerIndex() {
+		c.log.Errorf("receiveDecryptionShares: wrong sender #%d", msg.SenderIndex)
+		return
+	}
+	c.decryptionSharesMsgs = append(c.decryptionSharesMsgs, msg)
+}
+
+func (c *consensus) processDecryptionSharesMsgs(encrypted map[iscp.RequestID]iscp.Request) {
+	dkShare := c.committee.DKShare()
+	for _, msg := range c.decryptionSharesMsgs {
+		for i, reqID := range msg.RequestIDs {
+			req, ok := encrypted[reqID]
+			if !ok {
+				continue
+			}
+			if _, ok := c.decryptionShares[reqID][msg.SenderIndex]; ok {
+				continue
+			}
+			decShare, err := tcrypto.DecryptionShareFromBytes(msg.Shares[i], tcrypto.DefaultSuite())
+			if err == nil && decShare.Index != msg.SenderIndex {
+				err = xerrors.Errorf("wrong index %d", decShare.Index)
+			}
+			if err == nil {
+				ciphertext, _ := request.EncryptedArgs(req)
+				err = dkShare.VerifyDecryptionShare(ciphertext, decShare)
+			}
+			if err != nil {
+				c.log.Errorf("receiveDecryptionShares: wrong decryption share of %s from peer #%d: %v",
+					reqID.Base58(), msg.SenderIndex, err)
+				continue
+			}
+			c.storeDecryptionShare(reqID, msg.SenderIndex, decShare)
+		}
+	}
+	c.decryptionSharesMsgs = nil
+}
+
+func (c *consensus) storeDecryptionShare(reqID iscp.RequestID, index uint16, decShare *tcrypto.DecryptionShare) {
+	if c.decryptionShares == nil {
+		c.decryptionShares = make(map[iscp.RequestID]map[uint16]*tcrypto.DecryptionShare)
+	}
+	if c.decryptionShares[reqID] == nil {
+		c.decryptionShares[reqID] = make(map[uint16]*tcrypto.DecryptionShare)
+	}
+	c.decryptionShares[reqID][index] = decShare
+}
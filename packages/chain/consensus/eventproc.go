@@ -52,6 +52,17 @@ func (c *consensus) handleSignedResultAckMsg(msg *messages.SignedResultAckMsgIn)
 	c.takeAction()
 }
 
+func (c *consensus) EnqueueDecryptionSharesMsg(msg *messages.DecryptionSharesMsgIn) {
+	c.eventDecryptionSharesMsgPipe.In() <- msg
+}
+
+func (c *consensus) handleDecryptionSharesMsg(msg *messages.DecryptionSharesMsgIn) {
+	c.log.Debugf("DecryptionSharesMsg received: from sender %d, num shares %d, chain input id=%v",
+		msg.SenderIndex, len(msg.RequestIDs), iscp.OID(msg.ChainInputID))
+	c.receiveDecryptionShares(msg)
+	c.takeAction()
+}
+
 func (c *consensus) EnqueueInclusionsStateMsg(txID ledgerstate.TransactionID, inclusionState ledgerstate.InclusionState) {
 	c.eventInclusionStateMsgPipe.In() <- &messages.InclusionStateMsg{
 		TxID:  txID,
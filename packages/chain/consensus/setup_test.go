@@ -18,6 +18,7 @@ import (
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/committee"
 	"github.com/iotaledger/wasp/packages/chain/mempool"
@@ -27,6 +28,7 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/iscp/requestargs"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/peering"
@@ -201,7 +203,7 @@ func (env *MockedEnv) NewNode(nodeIndex uint16, timers ConsensusTimers) *mockedN
 		}
 	})
 	mempoolMetrics := metrics.DefaultChainMetrics()
-	ret.Mempool = mempool.New(ret.ChainCore.GetStateReader(), iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	ret.Mempool = mempool.New(ret.ChainCore.GetStateReader(), iscp.NewInMemoryBlobCache(), log, mempoolMetrics, mempool.DefaultConfig())
 
 	cfg := &consensusTestConfigProvider{
 		ownNetID:  nodeID,
@@ -258,7 +260,7 @@ func (env *MockedEnv) NewNode(nodeIndex uint16, timers ConsensusTimers) *mockedN
 	ret.stateSync.SetSolidIndex(0)
 	require.NoError(env.T, err)
 
-	cons := New(ret.ChainCore, ret.Mempool, cmt, cmtPeerGroup, ret.NodeConn, true, metrics.DefaultChainMetrics(), timers)
+	cons := New(ret.ChainCore, ret.Mempool, cmt, cmtPeerGroup, ret.NodeConn, true, 100, 0, metrics.DefaultChainMetrics(), timers)
 	cons.(*consensus).vmRunner = testchain.NewMockedVMRunner(env.T, log)
 	ret.Consensus = cons
 
@@ -445,6 +447,51 @@ func (env *MockedEnv) PostDummyRequests(n int, randomize ...bool) {
 	}
 }
 
+// PostDummyEncryptedRequests posts requests with the arguments encrypted to the committee.
+// Each node receives its own copy of the requests, which are returned by the node index
+func (env *MockedEnv) PostDummyEncryptedRequests(n int) [][]iscp.Request {
+	dkShare, err := env.DKSRegistries[0].LoadDKShare(env.StateAddress)
+	require.NoError(env.T, err)
+	sender := ledgerstate.NewED25519Address(env.OriginatorKeyPair.PublicKey)
+	target := iscp.NewRequestTarget(iscp.Hn("dummy"), iscp.Hn("dummy"))
+	reqs := make([]*request.OffLedger, n)
+	for i := 0; i < n; i++ {
+		args, err := request.EncryptArgs(dkShare.SharedPublic, env.ChainID, sender, target,
+			requestargs.New(nil).AddEncodeSimple("c", util.Uint64To8Bytes(uint64(i))))
+		require.NoError(env.T, err)
+		reqs[i] = request.NewOffLedger(env.ChainID, target.Contract, target.EntryPoint, args)
+		reqs[i].Sign(env.OriginatorKeyPair)
+	}
+	return env.postRequestCopies(reqs)
+}
+
+// PostCopiedEncryptedRequests posts the requests of another sender with the envelopes copied from the requests.
+// Each node receives its own copy of the requests, which are returned by the node index
+func (env *MockedEnv) PostCopiedEncryptedRequests(from []iscp.Request) [][]iscp.Request {
+	keyPair, _ := env.Ledger.NewKeyPairByIndex(1)
+	reqs := make([]*request.OffLedger, len(from))
+	for i, orig := range from {
+		target := orig.Target()
+		reqs[i] = request.NewOffLedger(env.ChainID, target.Contract, target.EntryPoint, orig.(*request.OffLedger).Args())
+		reqs[i].Sign(keyPair)
+	}
+	return env.postRequestCopies(reqs)
+}
+
+func (env *MockedEnv) postRequestCopies(reqs []*request.OffLedger) [][]iscp.Request {
+	var err error
+	ret := make([][]iscp.Request, len(env.Nodes))
+	for i, node := range env.Nodes {
+		ret[i] = make([]iscp.Request, len(reqs))
+		for j, req := range reqs {
+			ret[i][j], err = request.FromMarshalUtil(marshalutil.New(req.Bytes()))
+			require.NoError(env.T, err)
+			node.Mempool.ReceiveRequest(ret[i][j])
+		}
+	}
+	return ret
+}
+
 // TODO: should this object be obtained from peering.NetworkProvider?
 // Or should registry.PeerNetworkConfigProvider methods methods be part of
 // peering.NetworkProvider interface
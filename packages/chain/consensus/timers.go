@@ -10,6 +10,7 @@ import (
 type ConsensusTimers struct {
 	VMRunRetryToWaitForReadyRequests time.Duration
 	BroadcastSignedResultRetry       time.Duration
+	BroadcastDecryptionSharesRetry   time.Duration
 	PostTxSequenceStep               time.Duration
 	PullInclusionStateRetry          time.Duration
 	ProposeBatchRetry                time.Duration
@@ -20,6 +21,7 @@ func NewConsensusTimers() ConsensusTimers {
 	return ConsensusTimers{
 		VMRunRetryToWaitForReadyRequests: 500 * time.Millisecond,
 		BroadcastSignedResultRetry:       1 * time.Second,
+		BroadcastDecryptionSharesRetry:   1 * time.Second,
 		PostTxSequenceStep:               1 * time.Second,
 		PullInclusionStateRetry:          1 * time.Second,
 		ProposeBatchRetry:                500 * time.Millisecond,
@@ -0,0 +1,239 @@
+package mempool
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/iscp/rotate"
+	"golang.org/x/xerrors"
+)
+
+// EvictionPolicy selects which requests leave the pool first when it is full.
+// Only off-ledger requests are ever evicted
+type EvictionPolicy string
+
+const (
+	// EvictOldest evicts the requests which were received first
+	EvictOldest = EvictionPolicy("oldest")
+	// EvictLowestFee evicts the requests which offer the lowest priority fee
+	EvictLowestFee = EvictionPolicy("lowestFee")
+	// EvictFarthestTimeLock evicts the requests time locked farthest in the future,
+	// then the oldest ones. Off-ledger requests are not time locked, so it orders them as EvictOldest
+	EvictFarthestTimeLock = EvictionPolicy("farthestTimeLock")
+)
+
+// Config limits the resources taken by the mempool. The limits count the requests both
+// in the in-buffer and in the pool. On-ledger requests are counted, but never rejected
+// nor evicted. A zero value means no limit
+type Config struct {
+	// MaxPoolSize is the maximum number of requests
+	MaxPoolSize int
+	// MaxRequestsPerSender is the maximum number of requests of a single sender account
+	MaxRequestsPerSender int
+	// MaxTotalBytes is the maximum total size of the requests in bytes
+	MaxTotalBytes int
+	// EvictionPolicy is applied when the pool is full. Defaults to EvictOldest
+	EvictionPolicy EvictionPolicy
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxPoolSize:          10000,
+		MaxRequestsPerSender: 1000,
+		MaxTotalBytes:        64 * 1024 * 1024,
+		EvictionPolicy:       EvictOldest,
+	}
+}
+
+func (c Config) Validate() error {
+	if c.MaxPoolSize < 0 || c.MaxRequestsPerSender < 0 || c.MaxTotalBytes < 0 {
+		return xerrors.New("mempool limits cannot be negative")
+	}
+	switch c.EvictionPolicy {
+	case "", EvictOldest, EvictLowestFee, EvictFarthestTimeLock:
+		return nil
+	}
+	return xerrors.Errorf("unknown mempool eviction policy '%s'", c.EvictionPolicy)
+}
+
+// Reasons why the mempool does not accept a request
+var (
+	ErrPoolFull            = xerrors.New("mempool is full")
+	ErrSenderLimitExceeded = xerrors.New("too many pending requests of the sender")
+	ErrRequestTooLarge     = xerrors.New("request exceeds the mempool size limit")
+)
+
+// RejectReason returns a short label of the error for the metrics
+func RejectReason(err error) string {
+	switch {
+	case xerrors.Is(err, ErrPoolFull):
+		return "pool_full"
+	case xerrors.Is(err, ErrSenderLimitExceeded):
+		return "sender_limit"
+	case xerrors.Is(err, ErrRequestTooLarge):
+		return "too_large"
+	}
+	return "other"
+}
+
+func senderKey(req iscp.Request) string {
+	return string(req.SenderAccount().Bytes())
+}
+
+func requestSize(req iscp.Request) int {
+	return len(req.Bytes())
+}
+
+func requestTimeLock(req iscp.Request) time.Time {
+	if r, ok := req.(*request.OnLedger); ok {
+		return r.TimeLock()
+	}
+	return time.Time{}
+}
+
+// evictsBefore returns true if, according to the policy, the request a must leave the pool before the request b.
+// Ties are resolved by the time of arrival and then by the request id
+func (p EvictionPolicy) evictsBefore(a, b *requestRef) bool {
+	switch p {
+	case EvictLowestFee:
+		if feeA, feeB := a.req.PriorityFee(), b.req.PriorityFee(); feeA != feeB {
+			return feeA < feeB
+		}
+	case EvictFarthestTimeLock:
+		if tlA, tlB := requestTimeLock(a.req), requestTimeLock(b.req); !tlA.Equal(tlB) {
+			return tlA.After(tlB)
+		}
+	}
+	if !a.whenReceived.Equal(b.whenReceived) {
+		return a.whenReceived.Before(b.whenReceived)
+	}
+	aid, bid := a.req.ID(), b.req.ID()
+	return bytes.Compare(aid[:], bid[:]) < 0
+}
+
+// servedBefore returns true if the request a must be proposed for the batch before the request b:
+// the higher priority fee goes first, then the time of arrival and the request id
+func (a *requestRef) servedBefore(b *requestRef) bool {
+	if feeA, feeB := a.req.PriorityFee(), b.req.PriorityFee(); feeA != feeB {
+		return feeA > feeB
+	}
+	if !a.whenReceived.Equal(b.whenReceived) {
+		return a.whenReceived.Before(b.whenReceived)
+	}
+	aid, bid := a.req.ID(), b.req.ID()
+	return bytes.Compare(aid[:], bid[:]) < 0
+}
+
+// checkLimits checks if the request can be admitted to the mempool. If the pool is full, it returns
+// the requests which have to be evicted to make room for it. On-ledger requests are always admitted:
+// they are received only once from the ledger and would be lost if dropped. They are counted against
+// the limits, but only off-ledger requests are rejected or evicted. Must be called with poolMutex locked
+func (m *mempool) checkLimits(req iscp.Request, nowis time.Time) ([]iscp.RequestID, error) {
+	if !req.IsOffLedger() {
+		return nil, nil
+	}
+	size := requestSize(req)
+	if m.config.MaxTotalBytes > 0 && size > m.config.MaxTotalBytes {
+		return nil, ErrRequestTooLarge
+	}
+	if m.config.MaxRequestsPerSender > 0 && m.senderCounts[senderKey(req)] >= m.config.MaxRequestsPerSender {
+		return nil, ErrSenderLimitExceeded
+	}
+	fits := func(count, totalBytes int) bool {
+		if m.config.MaxPoolSize > 0 && count+1 > m.config.MaxPoolSize {
+			return false
+		}
+		return m.config.MaxTotalBytes <= 0 || totalBytes+size <= m.config.MaxTotalBytes
+	}
+	if fits(m.totalRequests, m.totalBytes) {
+		return nil, nil
+	}
+	// on-ledger requests, including the rotation requests the chain cannot make progress without, are never evicted
+	candidates := make([]*requestRef, 0, len(m.pool))
+	for _, ref := range m.pool {
+		if ref.req.IsOffLedger() && !rotate.IsRotateStateControllerRequest(ref.req) {
+			candidates = append(candidates, ref)
+		}
+	}
+	policy := m.config.EvictionPolicy
+	sort.Slice(candidates, func(i, j int) bool {
+		return policy.evictsBefore(candidates[i], candidates[j])
+	})
+	newRef := &requestRef{req: req, whenReceived: nowis}
+	count, totalBytes := m.totalRequests, m.totalBytes
+	evict := make([]iscp.RequestID, 0)
+	for _, ref := range candidates {
+		if !policy.evictsBefore(ref, newRef) {
+			// the new request would be the next one to be evicted
+			break
+		}
+		evict = append(evict, ref.req.ID())
+		count--
+		totalBytes -= requestSize(ref.req)
+		if fits(count, totalBytes) {
+			return evict, nil
+		}
+	}
+	return nil, ErrPoolFull
+}
+
+// CheckRequest returns the reason why the request would not be accepted by the mempool, if any.
+// A request already known to the mempool is accepted
+func (m *mempool) CheckRequest(req iscp.Request) error {
+	m.poolMutex.RLock()
+	defer m.poolMutex.RUnlock()
+
+	if m.isKnown(req.ID()) {
+		return nil
+	}
+	_, err := m.checkLimits(req, time.Now())
+	return err
+}
+
+// isKnown must be called with poolMutex locked
+func (m *mempool) isKnown(reqid iscp.RequestID) bool {
+	if _, ok := m.pool[reqid]; ok {
+		return true
+	}
+	m.inMutex.RLock()
+	defer m.inMutex.RUnlock()
+	_, ok := m.inBuffer[reqid]
+	return ok
+}
+
+// evict removes the requests from the pool to make room for the new ones. Must be called with poolMutex locked
+func (m *mempool) evict(reqids []iscp.RequestID) {
+	for _, rid := range reqids {
+		ref, ok := m.pool[rid]
+		if !ok {
+			continue
+		}
+		delete(m.pool, rid)
+		m.release(ref.req)
+		m.mempoolMetrics.CountRequestEvicted()
+		m.log.Debugf("EVICTED FROM MEMPOOL %s", rid)
+	}
+}
+
+// account adds the request to the resource counters. Must be called with poolMutex locked
+func (m *mempool) account(req iscp.Request) {
+	m.totalRequests++
+	m.totalBytes += requestSize(req)
+	m.senderCounts[senderKey(req)]++
+	m.mempoolMetrics.RecordMempoolSize(m.totalRequests, m.totalBytes)
+}
+
+// release removes the request from the resource counters. Must be called with poolMutex locked
+func (m *mempool) release(req iscp.Request) {
+	m.totalRequests--
+	m.totalBytes -= requestSize(req)
+	key := senderKey(req)
+	m.senderCounts[key]--
+	if m.senderCounts[key] <= 0 {
+		delete(m.senderCounts, key)
+	}
+	m.mempoolMetrics.RecordMempoolSize(m.totalRequests, m.totalBytes)
+}
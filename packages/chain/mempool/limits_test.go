@@ -0,0 +1,161 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/testutil/testkey"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func newLimitedMempool(t *testing.T, config Config) (*mempool, *MockMempoolMetrics) {
+	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
+	rdr, _ := createStateReader(t, glb)
+	mempoolMetrics := new(MockMempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics, config)
+	t.Cleanup(pool.Close)
+	return pool.(*mempool), mempoolMetrics
+}
+
+// receiveInOrder adds the requests to the pool one by one, so that their order of arrival is known
+func receiveInOrder(t *testing.T, pool *mempool, reqs ...iscp.Request) {
+	for _, req := range reqs {
+		require.True(t, pool.ReceiveRequest(req))
+		require.True(t, pool.WaitRequestInPool(req.ID()))
+	}
+}
+
+func offLedgerWithFee(fee uint64) *request.OffLedger {
+	req := request.NewOffLedger(iscp.RandomChainID(), iscp.Hn("contract"), iscp.Hn("entrypoint"), nil).
+		WithPriorityFee(fee)
+	keyPair, _ := testkey.GenKeyAddr()
+	req.Sign(keyPair)
+	return req
+}
+
+// offLedgerOfSender returns requests of the same size, all sent by the owner of the key pair
+func offLedgerOfSender(keyPair *ed25519.KeyPair, amount int) []iscp.Request {
+	chainID := iscp.RandomChainID()
+	ret := make([]iscp.Request, amount)
+	for i := range ret {
+		req := request.NewOffLedger(chainID, iscp.Hn("contract"), iscp.Hn("entrypoint"), nil)
+		req.WithNonce(uint64(i))
+		req.Sign(keyPair)
+		ret[i] = req
+	}
+	return ret
+}
+
+func TestSenderLimit(t *testing.T) {
+	pool, mempoolMetrics := newLimitedMempool(t, Config{MaxRequestsPerSender: 2})
+	keyPair, _ := testkey.GenKeyAddr()
+	requests := offLedgerOfSender(keyPair, 3)
+
+	receiveInOrder(t, pool, requests[0], requests[1])
+	require.True(t, xerrors.Is(pool.CheckRequest(requests[2]), ErrSenderLimitExceeded))
+	require.False(t, pool.ReceiveRequest(requests[2]))
+	require.EqualValues(t, 1, mempoolMetrics.rejectedRequestCounter)
+	// requests of other senders are not affected
+	require.NoError(t, pool.CheckRequest(testutil.DummyOffledgerRequest(iscp.RandomChainID())))
+	// a request already in the mempool is not counted twice
+	require.NoError(t, pool.CheckRequest(requests[0]))
+
+	pool.RemoveRequests(requests[0].ID())
+	require.NoError(t, pool.CheckRequest(requests[2]))
+	receiveInOrder(t, pool, requests[2])
+}
+
+func TestRequestTooLarge(t *testing.T) {
+	req := testutil.DummyOffledgerRequest(iscp.RandomChainID())
+	pool, _ := newLimitedMempool(t, Config{MaxTotalBytes: len(req.Bytes()) - 1})
+
+	require.True(t, xerrors.Is(pool.CheckRequest(req), ErrRequestTooLarge))
+	require.False(t, pool.ReceiveRequest(req))
+}
+
+func TestEvictOldest(t *testing.T) {
+	pool, mempoolMetrics := newLimitedMempool(t, Config{MaxPoolSize: 3})
+	keyPair, _ := testkey.GenKeyAddr()
+	requests := offLedgerOfSender(keyPair, 5)
+
+	receiveInOrder(t, pool, requests[0], requests[1], requests[2])
+	receiveInOrder(t, pool, requests[3])
+	require.False(t, pool.HasRequest(requests[0].ID()))
+	receiveInOrder(t, pool, requests[4])
+	require.False(t, pool.HasRequest(requests[1].ID()))
+
+	require.EqualValues(t, 3, pool.Info().TotalPool)
+	require.EqualValues(t, 2, mempoolMetrics.evictedRequestCounter)
+}
+
+func TestEvictFarthestTimeLock(t *testing.T) {
+	pool, _ := newLimitedMempool(t, Config{MaxPoolSize: 3, EvictionPolicy: EvictFarthestTimeLock})
+	onLedger, _ := getRequestsOnLedger(t, 1)
+	onLedger[0].Output().(*ledgerstate.ExtendedLockedOutput).WithTimeLock(time.Now().Add(time.Hour))
+	keyPair, _ := testkey.GenKeyAddr()
+	requests := offLedgerOfSender(keyPair, 3)
+
+	receiveInOrder(t, pool, onLedger[0], requests[0], requests[1])
+	receiveInOrder(t, pool, requests[2])
+	// the time locked request is on-ledger: the oldest off-ledger request is evicted instead
+	require.True(t, pool.HasRequest(onLedger[0].ID()))
+	require.False(t, pool.HasRequest(requests[0].ID()))
+	require.EqualValues(t, 3, pool.Info().TotalPool)
+}
+
+func TestEvictLowestFee(t *testing.T) {
+	pool, _ := newLimitedMempool(t, Config{MaxPoolSize: 3, EvictionPolicy: EvictLowestFee})
+	requests := []*request.OffLedger{
+		offLedgerWithFee(5),
+		offLedgerWithFee(1),
+		offLedgerWithFee(3),
+		offLedgerWithFee(2),
+		offLedgerWithFee(1),
+	}
+
+	receiveInOrder(t, pool, requests[0], requests[1], requests[2])
+	receiveInOrder(t, pool, requests[3])
+	require.False(t, pool.HasRequest(requests[1].ID()))
+
+	// the lowest fee in the pool is now 2: a request with fee 1 is rejected
+	require.True(t, xerrors.Is(pool.CheckRequest(requests[4]), ErrPoolFull))
+	require.False(t, pool.ReceiveRequest(requests[4]))
+}
+
+func TestEvictionByBytes(t *testing.T) {
+	keyPair, _ := testkey.GenKeyAddr()
+	requests := offLedgerOfSender(keyPair, 3)
+	size := len(requests[0].Bytes())
+	pool, _ := newLimitedMempool(t, Config{MaxTotalBytes: 2 * size})
+
+	receiveInOrder(t, pool, requests[0], requests[1], requests[2])
+	require.False(t, pool.HasRequest(requests[0].ID()))
+	require.EqualValues(t, 2, pool.Info().TotalPool)
+}
+
+func TestOnLedgerRequestsNotLimited(t *testing.T) {
+	pool, mempoolMetrics := newLimitedMempool(t, Config{MaxPoolSize: 2, MaxRequestsPerSender: 1})
+	requests, _ := getRequestsOnLedger(t, 3)
+	offLedger := testutil.DummyOffledgerRequest(iscp.RandomChainID())
+
+	receiveInOrder(t, pool, offLedger)
+	// on-ledger requests exceed both limits, but are neither rejected nor evict other requests
+	receiveInOrder(t, pool, requests[0], requests[1], requests[2])
+	for _, req := range requests {
+		require.True(t, pool.HasRequest(req.ID()))
+	}
+	require.True(t, pool.HasRequest(offLedger.ID()))
+	require.EqualValues(t, 4, pool.Info().TotalPool)
+	require.EqualValues(t, 0, mempoolMetrics.rejectedRequestCounter)
+
+	// evicting the only off-ledger request does not make enough room
+	require.True(t, xerrors.Is(pool.CheckRequest(testutil.DummyOffledgerRequest(iscp.RandomChainID())), ErrPoolFull))
+}
@@ -2,10 +2,13 @@
 // It contains both on-ledger and off-ledger requests. The mempool consists of 2 parts: the in-buffer and the pool
 // All incoming requests are stored into the in-buffer first. Then they are asynchronously validated
 // and moved to the pool itself.
+// The resources taken by the mempool are bounded by the Config: when the pool is full, requests are
+// evicted according to the eviction policy or, if that is not possible, the new requests are rejected.
 package mempool
 
 import (
 	"bytes"
+	"sort"
 	"sync"
 	"time"
 
@@ -35,6 +38,11 @@ type mempool struct {
 	solidificationLoopDelay time.Duration
 	log                     *logger.Logger
 	mempoolMetrics          metrics.MempoolMetrics
+	config                  Config
+	// resources taken by the requests both in the in-buffer and in the pool. Guarded by poolMutex
+	totalRequests int
+	totalBytes    int
+	senderCounts  map[string]int
 }
 
 type requestRef struct {
@@ -49,7 +57,7 @@ const (
 
 var _ chain.Mempool = &mempool{}
 
-func New(stateReader state.OptimisticStateReader, blobCache registry.BlobCache, log *logger.Logger, mempoolMetrics metrics.MempoolMetrics, solidificationLoopDelay ...time.Duration) chain.Mempool {
+func New(stateReader state.OptimisticStateReader, blobCache registry.BlobCache, log *logger.Logger, mempoolMetrics metrics.MempoolMetrics, config Config, solidificationLoopDelay ...time.Duration) chain.Mempool {
 	ret := &mempool{
 		inBuffer:       make(map[iscp.RequestID]iscp.Request),
 		stateReader:    stateReader,
@@ -58,6 +66,11 @@ func New(stateReader state.OptimisticStateReader, blobCache registry.BlobCache,
 		blobCache:      blobCache,
 		log:            log.Named("m"),
 		mempoolMetrics: mempoolMetrics,
+		config:         config,
+		senderCounts:   make(map[string]int),
+	}
+	if ret.config.EvictionPolicy == "" {
+		ret.config.EvictionPolicy = EvictOldest
 	}
 	if len(solidificationLoopDelay) > 0 {
 		ret.solidificationLoopDelay = solidificationLoopDelay[0]
@@ -69,13 +82,26 @@ func New(stateReader state.OptimisticStateReader, blobCache registry.BlobCache,
 	return ret
 }
 
+// addToInBuffer returns false if the request is already in the pool or the limits of the mempool do not allow it
 func (m *mempool) addToInBuffer(req iscp.Request) bool {
+	m.poolMutex.Lock()
+	defer m.poolMutex.Unlock()
 	// just check if it is already in the pool
-	if m.HasRequest(req.ID()) {
+	if _, inPool := m.pool[req.ID()]; inPool {
 		return false
 	}
 	m.inMutex.Lock()
 	defer m.inMutex.Unlock()
+	if _, inBuffer := m.inBuffer[req.ID()]; !inBuffer {
+		evict, err := m.checkLimits(req, time.Now())
+		if err != nil {
+			m.mempoolMetrics.CountRequestRejected(RejectReason(err))
+			m.log.Debugf("REJECTED BY MEMPOOL %s: %v", req.ID(), err)
+			return false
+		}
+		m.evict(evict)
+		m.account(req)
+	}
 	// may be repeating but does not matter
 	m.inBuffer[req.ID()] = req
 	m.inBufCounter++
@@ -118,6 +144,9 @@ func (m *mempool) addToPool(req iscp.Request) bool {
 	}
 	if alreadyProcessed {
 		// remove from the in-buffer but not include into the pool
+		m.poolMutex.Lock()
+		m.release(req)
+		m.poolMutex.Unlock()
 		return true
 	}
 	m.poolMutex.Lock()
@@ -174,15 +203,17 @@ func (m *mempool) RemoveRequests(reqs ...iscp.RequestID) {
 	defer m.poolMutex.Unlock()
 
 	for _, rid := range reqs {
-		if _, ok := m.pool[rid]; !ok {
+		ref, ok := m.pool[rid]
+		if !ok {
 			continue
 		}
 		m.outPoolCounter++
 		m.mempoolMetrics.CountRequestOut()
 		m.mempoolMetrics.CountBlocksPerChain()
-		elapsed := time.Since(m.pool[rid].whenReceived)
+		elapsed := time.Since(ref.whenReceived)
 		m.mempoolMetrics.RecordRequestProcessingTime(rid, elapsed)
 		delete(m.pool, rid)
+		m.release(ref.req)
 		m.traceOut(rid)
 	}
 }
@@ -243,7 +274,8 @@ func isRequestReady(ref *requestRef, nowis time.Time) (isReady, shouldBeRemoved
 // ReadyNow returns preliminary batch of requests for consensus.
 // Note that later status of request may change due to the time change and time constraints
 // If there's at least one committee rotation request in the mempool, the ReadyNow returns
-// batch with only one request, the oldest committee rotation request
+// batch with only one request, the oldest committee rotation request.
+// Otherwise, the requests are ordered by the priority fee, the highest first
 func (m *mempool) ReadyNow(now ...time.Time) []iscp.Request {
 	m.poolMutex.RLock()
 
@@ -256,7 +288,7 @@ func (m *mempool) ReadyNow(now ...time.Time) []iscp.Request {
 
 	toRemove := []iscp.RequestID{}
 
-	ret := make([]iscp.Request, 0, len(m.pool))
+	ready := make([]*requestRef, 0, len(m.pool))
 	for _, ref := range m.pool {
 		rdy, shouldBeRemoved := isRequestReady(ref, nowis)
 		if shouldBeRemoved {
@@ -266,7 +298,7 @@ func (m *mempool) ReadyNow(now ...time.Time) []iscp.Request {
 		if !rdy {
 			continue
 		}
-		ret = append(ret, ref.req)
+		ready = append(ready, ref)
 		if !rotate.IsRotateStateControllerRequest(ref.req) {
 			continue
 		}
@@ -294,6 +326,13 @@ func (m *mempool) ReadyNow(now ...time.Time) []iscp.Request {
 	if oldestRotate != nil {
 		return []iscp.Request{oldestRotate}
 	}
+	sort.Slice(ready, func(i, j int) bool {
+		return ready[i].servedBefore(ready[j])
+	})
+	ret := make([]iscp.Request, len(ready))
+	for i, ref := range ready {
+		ret[i] = ref.req
+	}
 	return ret
 }
 
@@ -400,6 +439,7 @@ func (m *mempool) Info() chain.MempoolInfo {
 	m.poolMutex.RLock()
 	defer m.poolMutex.RUnlock()
 
+	m.inMutex.RLock()
 	ret := chain.MempoolInfo{
 		InPoolCounter:  m.inPoolCounter,
 		OutPoolCounter: m.outPoolCounter,
@@ -407,6 +447,7 @@ func (m *mempool) Info() chain.MempoolInfo {
 		OutBufCounter:  m.outBufCounter,
 		TotalPool:      len(m.pool),
 	}
+	m.inMutex.RUnlock()
 	nowis := time.Now()
 	for _, ref := range m.pool {
 		rdy, _ := isRequestReady(ref, nowis)
@@ -70,6 +70,8 @@ type MockMempoolMetrics struct {
 	offLedgerRequestCounter int
 	onLedgerRequestCounter  int
 	processedRequestCounter int
+	rejectedRequestCounter  int
+	evictedRequestCounter   int
 }
 
 func (m *MockMempoolMetrics) CountOffLedgerRequestIn() {
@@ -89,13 +91,23 @@ func (m *MockMempoolMetrics) RecordRequestProcessingTime(reqID iscp.RequestID, e
 
 func (m *MockMempoolMetrics) CountBlocksPerChain() {}
 
+func (m *MockMempoolMetrics) CountRequestRejected(reason string) {
+	m.rejectedRequestCounter++
+}
+
+func (m *MockMempoolMetrics) CountRequestEvicted() {
+	m.evictedRequestCounter++
+}
+
+func (m *MockMempoolMetrics) RecordMempoolSize(requests, bytes int) {}
+
 // Test if mempool is created
 func TestMempool(t *testing.T) {
 	log := testlogger.NewLogger(t)
 	glb := coreutil.NewChainStateSync()
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	time.Sleep(2 * time.Second)
 	stats := pool.Info()
@@ -113,7 +125,7 @@ func TestAddRequest(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 1)
 
@@ -133,7 +145,7 @@ func TestAddRequestInvalidState(t *testing.T) {
 	glb.InvalidateSolidIndex()
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 1)
 
@@ -162,7 +174,7 @@ func TestAddRequestTwice(t *testing.T) {
 	rdr, _ := createStateReader(t, glb)
 
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 1)
 
@@ -192,7 +204,7 @@ func TestAddOffLedgerRequest(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	onLedgerRequests, keyPair := getRequestsOnLedger(t, 2)
 
@@ -222,7 +234,7 @@ func TestProcessedRequest(t *testing.T) {
 	wrt := vs.KVStore()
 
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 
 	stats := pool.Info()
@@ -260,7 +272,7 @@ func TestAddRemoveRequests(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), log, mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 6)
 
@@ -309,7 +321,7 @@ func TestTimeLock(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 6)
 
@@ -411,7 +423,7 @@ func TestFallbackOptions(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 3)
 
@@ -456,7 +468,7 @@ func TestReadyFromIDs(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 6)
 
@@ -541,7 +553,7 @@ func TestSolidification(t *testing.T) {
 	rdr, _ := createStateReader(t, glb)
 	blobCache := iscp.NewInMemoryBlobCache()
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, blobCache, log, mempoolMetrics, 20*time.Millisecond) // Solidification initiated on pool creation
+	pool := New(rdr, blobCache, log, mempoolMetrics, DefaultConfig(), 20*time.Millisecond) // Solidification initiated on pool creation
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 4)
 
@@ -580,7 +592,7 @@ func TestRotateRequest(t *testing.T) {
 	glb := coreutil.NewChainStateSync().SetSolidIndex(0)
 	rdr, _ := createStateReader(t, glb)
 	mempoolMetrics := new(MockMempoolMetrics)
-	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics)
+	pool := New(rdr, iscp.NewInMemoryBlobCache(), testlogger.NewLogger(t), mempoolMetrics, DefaultConfig())
 	require.NotNil(t, pool)
 	requests, _ := getRequestsOnLedger(t, 6)
 
@@ -648,3 +660,22 @@ func TestRotateRequest(t *testing.T) {
 	require.True(t, result)
 	require.True(t, len(ready) == 5)
 }
+
+// Test that the ready requests are ordered by the priority fee, then by the time of arrival
+func TestReadyNowPriorityFee(t *testing.T) {
+	pool, _ := newLimitedMempool(t, DefaultConfig())
+	requests := []*request.OffLedger{
+		offLedgerWithFee(0),
+		offLedgerWithFee(7),
+		offLedgerWithFee(2),
+		offLedgerWithFee(7),
+	}
+	receiveInOrder(t, pool, requests[0], requests[1], requests[2], requests[3])
+
+	ready := pool.ReadyNow()
+	require.Len(t, ready, 4)
+	require.EqualValues(t, requests[1].ID(), ready[0].ID())
+	require.EqualValues(t, requests[3].ID(), ready[1].ID())
+	require.EqualValues(t, requests[2].ID(), ready[2].ID())
+	require.EqualValues(t, requests[0].ID(), ready[3].ID())
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// Consensus -> Consensus
+// DecryptionSharesMsg carries the decryption shares of the sender for the encrypted requests of the consensus batch
+type DecryptionSharesMsg struct {
+	ChainInputID ledgerstate.OutputID
+	RequestIDs   []iscp.RequestID
+	Shares       [][]byte
+}
+
+type DecryptionSharesMsgIn struct {
+	DecryptionSharesMsg
+	SenderIndex uint16
+}
+
+func NewDecryptionSharesMsg(data []byte) (*DecryptionSharesMsg, error) {
+	msg := &DecryptionSharesMsg{}
+	r := bytes.NewReader(data)
+	var err error
+	if err = util.ReadOutputID(r, &msg.ChainInputID); err != nil { // nolint:gocritic // - ignore sloppyReassign
+		return nil, err
+	}
+	var size uint16
+	if err = util.ReadUint16(r, &size); err != nil {
+		return nil, err
+	}
+	msg.RequestIDs = make([]iscp.RequestID, size)
+	msg.Shares = make([][]byte, size)
+	for i := range msg.RequestIDs {
+		if err = util.ReadOutputID(r, (*ledgerstate.OutputID)(&msg.RequestIDs[i])); err != nil {
+			return nil, err
+		}
+		if msg.Shares[i], err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return msg, nil
+}
+
+func (msg *DecryptionSharesMsg) Write(w io.Writer) error {
+	if _, err := w.Write(msg.ChainInputID[:]); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, uint16(len(msg.RequestIDs))); err != nil {
+		return err
+	}
+	for i := range msg.RequestIDs {
+		if _, err := w.Write(msg.RequestIDs[i][:]); err != nil {
+			return err
+		}
+		if err := util.WriteBytes16(w, msg.Shares[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
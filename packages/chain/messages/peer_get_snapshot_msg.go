@@ -0,0 +1,36 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// GetSnapshotMsg StateManager queries the snapshot of the committed state from another peer.
+// The peer responds only if its committed state is not older than MinBlockIndex
+// StateManager -> StateManager
+type GetSnapshotMsg struct {
+	MinBlockIndex uint32
+}
+
+type GetSnapshotMsgIn struct {
+	GetSnapshotMsg
+	SenderNetID string
+}
+
+func NewGetSnapshotMsg(data []byte) (*GetSnapshotMsg, error) {
+	msg := &GetSnapshotMsg{}
+	r := bytes.NewReader(data)
+	if err := util.ReadUint32(r, &msg.MinBlockIndex); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (msg *GetSnapshotMsg) Write(w io.Writer) error {
+	return util.WriteUint32(w, msg.MinBlockIndex)
+}
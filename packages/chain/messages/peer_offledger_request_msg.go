@@ -20,6 +20,15 @@ type OffLedgerRequestMsgIn struct {
 	SenderNetID string
 }
 
+// OffLedgerRequestBatchMsgIn is a batch of off-ledger requests received by the webapi of the node
+type OffLedgerRequestBatchMsgIn struct {
+	ChainID *iscp.ChainID
+	Reqs    []*request.OffLedger
+	// Results receives the result of the admission of each request to the mempool, in the order of Reqs.
+	// The chain doesn't wait for the receiver, the channel must be buffered
+	Results chan []error
+}
+
 func NewOffLedgerRequestMsg(data []byte) (*OffLedgerRequestMsg, error) {
 	mu := marshalutil.New(data)
 	chainID, err := iscp.ChainIDFromMarshalUtil(mu)
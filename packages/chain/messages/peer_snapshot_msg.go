@@ -0,0 +1,51 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// SnapshotMsg StateManager in response to GetSnapshotMsg streams the serialized snapshot of its committed state
+// as a sequence of chunks. The chunk with index 0 starts a new snapshot, the chunk with Last set completes it
+// StateManager -> StateManager
+type SnapshotMsg struct {
+	ChunkIndex uint32
+	Last       bool
+	Data       []byte
+}
+
+type SnapshotMsgIn struct {
+	SnapshotMsg
+	SenderNetID string
+}
+
+func NewSnapshotMsg(data []byte) (*SnapshotMsg, error) {
+	msg := &SnapshotMsg{}
+	r := bytes.NewReader(data)
+	var err error
+	if err = util.ReadUint32(r, &msg.ChunkIndex); err != nil {
+		return nil, err
+	}
+	if err = util.ReadBoolByte(r, &msg.Last); err != nil {
+		return nil, err
+	}
+	if msg.Data, err = util.ReadBytes32(r); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (msg *SnapshotMsg) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, msg.ChunkIndex); err != nil {
+		return err
+	}
+	if err := util.WriteBoolByte(w, msg.Last); err != nil {
+		return err
+	}
+	return util.WriteBytes32(w, msg.Data)
+}
@@ -0,0 +1,243 @@
+package nodeconnimpl
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/txstream"
+	txstream_client "github.com/iotaledger/goshimmer/packages/txstream/client"
+	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
+	"go.uber.org/atomic"
+)
+
+const (
+	dialTimeout       = 1 * time.Second
+	endpointQueueSize = 1000
+)
+
+// endpoint is the connection to the txstream server of one L1 node
+type endpoint struct {
+	pool    *Pool
+	index   int
+	address string
+	client  *txstream_client.Client
+	// calls to the client are queued: the client blocks while it is not connected
+	queue chan func(*txstream_client.Client)
+
+	mutex        sync.RWMutex
+	connected    bool
+	lastReceived time.Time
+	probeSent    time.Time
+	probeAlias   *ledgerstate.AliasAddress
+	probePending bool
+	// number of the requests for the unspent alias outputs sent on behalf of the chains and not answered yet.
+	// The answers to them are forwarded even if they also answer the probe
+	aliasRequests map[[ledgerstate.AddressLength]byte]int
+
+	connects atomic.Uint32
+	sent     atomic.Uint32
+	received atomic.Uint32
+}
+
+var _ nodeconnmetrics.NodeConnectionEndpointMetrics = &endpoint{}
+
+func newEndpoint(pool *Pool, index int, address string) *endpoint {
+	return &endpoint{
+		pool:          pool,
+		index:         index,
+		address:       address,
+		queue:         make(chan func(*txstream_client.Client), endpointQueueSize),
+		aliasRequests: make(map[[ledgerstate.AddressLength]byte]int),
+	}
+}
+
+// start creates the client, which keeps connecting to the server until closed
+func (e *endpoint) start() {
+	e.client = txstream_client.New(e.pool.clientID, e.pool.log.Named(e.address), e.dial)
+	go e.sendLoop()
+}
+
+func (e *endpoint) dial() (string, net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", e.address, dialTimeout)
+	if err != nil {
+		return e.address, nil, err
+	}
+	e.setConnected(true)
+	return e.address, &trackedConn{Conn: conn, endpoint: e}, nil
+}
+
+// sendLoop executes the queued calls to the client one by one
+func (e *endpoint) sendLoop() {
+	for {
+		select {
+		case <-e.pool.shutdown:
+			return
+		case f := <-e.queue:
+			f(e.client)
+			e.sent.Inc()
+		}
+	}
+}
+
+// send queues a call to the client. If the endpoint doesn't keep up, the call is dropped
+func (e *endpoint) send(f func(*txstream_client.Client)) {
+	select {
+	case e.queue <- f:
+	default:
+		e.pool.log.Warnf("node connection: send queue of %s is full, message dropped", e.address)
+	}
+}
+
+func (e *endpoint) setConnected(connected bool) {
+	e.mutex.Lock()
+	changed := e.connected != connected
+	e.connected = connected
+	if connected {
+		e.lastReceived = time.Now()
+	}
+	e.probePending = false
+	if !connected {
+		// the requests sent over the lost connection won't be answered
+		e.aliasRequests = make(map[[ledgerstate.AddressLength]byte]int)
+	}
+	e.mutex.Unlock()
+	if !changed {
+		return
+	}
+	if connected {
+		e.connects.Inc()
+		e.pool.log.Infof("node connection: connected to %s", e.address)
+	} else {
+		e.pool.log.Warnf("node connection: lost connection to %s", e.address)
+	}
+	e.pool.checkHealth()
+}
+
+func (e *endpoint) dataReceived() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.lastReceived = time.Now()
+}
+
+// probe asks the server for the unspent output of the alias unless the previous probe is still waiting
+// for the answer. The probe is repeated if the answer doesn't come within probeTimeout
+func (e *endpoint) probe(alias *ledgerstate.AliasAddress, probeTimeout time.Duration) {
+	e.mutex.Lock()
+	if !e.connected || (e.probePending && time.Since(e.probeSent) < probeTimeout) {
+		e.mutex.Unlock()
+		return
+	}
+	e.probeSent = time.Now()
+	e.probeAlias = alias
+	e.probePending = true
+	e.mutex.Unlock()
+	e.send(func(c *txstream_client.Client) { c.RequestUnspentAliasOutput(alias) })
+}
+
+// requestUnspentAliasOutput asks the server for the unspent output of the alias on behalf of the chain
+func (e *endpoint) requestUnspentAliasOutput(alias *ledgerstate.AliasAddress) {
+	e.mutex.Lock()
+	e.aliasRequests[alias.Array()]++
+	e.mutex.Unlock()
+	e.send(func(c *txstream_client.Client) { c.RequestUnspentAliasOutput(alias) })
+}
+
+// isProbeAnswer returns true if the message only answers the pending probe. Such an answer is consumed:
+// the probes are internal to the pool and are not forwarded to the node connection.
+// The answer to a request of the chain is never consumed, it also answers the probe for the same alias
+func (e *endpoint) isProbeAnswer(msg *txstream.MsgUnspentAliasOutput) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	answersProbe := e.probePending && e.probeAlias.Equals(msg.AliasAddress)
+	if answersProbe {
+		e.probePending = false
+	}
+	key := msg.AliasAddress.Array()
+	if n := e.aliasRequests[key]; n > 0 {
+		if n == 1 {
+			delete(e.aliasRequests, key)
+		} else {
+			e.aliasRequests[key] = n - 1
+		}
+		return false
+	}
+	return answersProbe
+}
+
+// isHealthy returns true if the endpoint is connected and either has answered the last probe
+// or has been sending data since then
+func (e *endpoint) isHealthy(probeTimeout time.Duration) bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if !e.connected {
+		return false
+	}
+	return !e.probePending || time.Since(e.probeSent) < probeTimeout || e.lastReceived.After(e.probeSent)
+}
+
+func (e *endpoint) GetAddress() string {
+	return e.address
+}
+
+func (e *endpoint) IsConnected() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.connected
+}
+
+func (e *endpoint) IsHealthy() bool {
+	return e.isHealthy(e.pool.probeTimeout)
+}
+
+func (e *endpoint) IsActive() bool {
+	return e.pool.activeEndpoint() == e
+}
+
+func (e *endpoint) GetConnects() uint32 {
+	return e.connects.Load()
+}
+
+func (e *endpoint) GetMessagesSent() uint32 {
+	return e.sent.Load()
+}
+
+func (e *endpoint) GetMessagesReceived() uint32 {
+	return e.received.Load()
+}
+
+func (e *endpoint) GetLastReceived() time.Time {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.lastReceived
+}
+
+// trackedConn reports the activity and the loss of the connection to the endpoint
+type trackedConn struct {
+	net.Conn
+	endpoint  *endpoint
+	closeOnce sync.Once
+}
+
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.endpoint.dataReceived()
+	}
+	if err != nil {
+		c.lost()
+	}
+	return n, err
+}
+
+func (c *trackedConn) Close() error {
+	c.lost()
+	return c.Conn.Close()
+}
+
+func (c *trackedConn) lost() {
+	c.closeOnce.Do(func() {
+		c.endpoint.setConnected(false)
+	})
+}
@@ -3,7 +3,6 @@ package nodeconnimpl
 import (
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/goshimmer/packages/txstream"
-	txstream_client "github.com/iotaledger/goshimmer/packages/txstream/client"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
@@ -11,7 +10,7 @@ import (
 )
 
 type nodeConnImplementation struct {
-	client                 *txstream_client.Client
+	client                 *Pool
 	transactionHandlers    map[ledgerstate.AliasAddress]chain.NodeConnectionHandleTransactionFun
 	iStateHandlers         map[ledgerstate.AliasAddress]chain.NodeConnectionHandleInclusionStateFun
 	outputHandlers         map[ledgerstate.AliasAddress]chain.NodeConnectionHandleOutputFun
@@ -26,7 +25,7 @@ type nodeConnImplementation struct {
 
 var _ chain.NodeConnection = &nodeConnImplementation{}
 
-func NewNodeConnection(nodeConnClient *txstream_client.Client, metrics nodeconnmetrics.NodeConnectionMetrics, log *logger.Logger) chain.NodeConnection {
+func NewNodeConnection(nodeConnClient *Pool, metrics nodeconnmetrics.NodeConnectionMetrics, log *logger.Logger) chain.NodeConnection {
 	ret := &nodeConnImplementation{
 		client:                 nodeConnClient,
 		transactionHandlers:    make(map[ledgerstate.AliasAddress]chain.NodeConnectionHandleTransactionFun),
@@ -36,6 +35,7 @@ func NewNodeConnection(nodeConnClient *txstream_client.Client, metrics nodeconnm
 		metrics:                metrics,
 		log:                    log,
 	}
+	nodeConnClient.SetMetrics(metrics)
 
 	ret.transactionClosure = events.NewClosure(ret.handleTransactionReceived)
 	ret.client.Events.TransactionReceived.Attach(ret.transactionClosure)
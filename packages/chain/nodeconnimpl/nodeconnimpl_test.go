@@ -6,22 +6,173 @@ import (
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
-	txstream "github.com/iotaledger/goshimmer/packages/txstream/client"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate/utxoutil"
+	"github.com/iotaledger/goshimmer/packages/txstream"
+	"github.com/iotaledger/goshimmer/packages/txstream/server"
+	"github.com/iotaledger/goshimmer/packages/txstream/utxodbledger"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
-	"golang.org/x/xerrors"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBasic(t *testing.T) {
 	logger := testlogger.NewLogger(t)
 
 	var addr ledgerstate.AliasAddress
-	nconn := txstream.New("dummyID", logger, func() (addr string, conn net.Conn, err error) {
-		return "", nil, xerrors.New("dummy dial error")
-	})
-	nconnimpl := NewNodeConnection(nconn, nodeconnmetrics.NewEmptyNodeConnectionMetrics(), logger)
+	pool, err := NewPool("dummyID", []string{"127.0.0.1:1"}, logger)
+	require.NoError(t, err)
+	defer pool.Close()
+	nconnimpl := NewNodeConnection(pool, nodeconnmetrics.NewEmptyNodeConnectionMetrics(), logger)
 	nconnimpl.AttachToTransactionReceived(&addr, func(*ledgerstate.Transaction) {})
 	nconnimpl.AttachToInclusionStateReceived(&addr, func(ledgerstate.TransactionID, ledgerstate.InclusionState) {})
 	nconnimpl.AttachToOutputReceived(&addr, func(ledgerstate.Output) {})
 	nconnimpl.AttachToUnspentAliasOutputReceived(&addr, func(*ledgerstate.AliasOutput, time.Time) {})
 }
+
+func TestNoAddresses(t *testing.T) {
+	_, err := NewPool("dummyID", nil, testlogger.NewLogger(t))
+	require.Error(t, err)
+}
+
+// startServer starts a mock txstream server on a free port
+func startServer(t *testing.T, ledger *utxodbledger.UtxoDBLedger) (string, chan struct{}) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	shutdown := make(chan struct{})
+	require.NoError(t, server.Listen(ledger, addr, testlogger.NewLogger(t).Named(addr), shutdown))
+	return addr, shutdown
+}
+
+func createAliasChain(t *testing.T, ledger *utxodbledger.UtxoDBLedger) *ledgerstate.AliasAddress {
+	creatorKP, creatorAddr := ledger.NewKeyPairByIndex(1)
+	require.NoError(t, ledger.RequestFunds(creatorAddr))
+	_, stateControlAddr := ledger.NewKeyPairByIndex(2)
+
+	txb := utxoutil.NewBuilder(ledger.GetAddressOutputs(creatorAddr)...)
+	require.NoError(t, txb.AddNewAliasMint(map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 100}, stateControlAddr, nil))
+	require.NoError(t, txb.AddRemainderOutputIfNeeded(creatorAddr, nil))
+	tx, err := txb.BuildWithED25519(creatorKP)
+	require.NoError(t, err)
+	require.NoError(t, ledger.PostTransaction(tx))
+
+	chainOutput, err := utxoutil.GetSingleChainedAliasOutput(tx)
+	require.NoError(t, err)
+	return chainOutput.GetAliasAddress()
+}
+
+func newRequestTx(t *testing.T, ledger *utxodbledger.UtxoDBLedger, chainAddr *ledgerstate.AliasAddress) *ledgerstate.Transaction {
+	kp, addr := ledger.NewKeyPairByIndex(3)
+	require.NoError(t, ledger.RequestFunds(addr))
+	txb := utxoutil.NewBuilder(ledger.GetAddressOutputs(addr)...)
+	require.NoError(t, txb.AddExtendedOutputConsume(chainAddr, nil, map[ledgerstate.Color]uint64{ledgerstate.ColorIOTA: 1}))
+	require.NoError(t, txb.AddRemainderOutputIfNeeded(addr, nil))
+	tx, err := txb.BuildWithED25519(kp)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestFailover(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	// both servers serve the same ledger, as the nodes of the same network do
+	ledger := utxodbledger.New(log)
+	chainAddr := createAliasChain(t, ledger)
+	addr1, shutdown1 := startServer(t, ledger)
+	addr2, shutdown2 := startServer(t, ledger)
+
+	pool, err := NewPool("test", []string{addr1, addr2}, log, 20*time.Millisecond, 500*time.Millisecond)
+	require.NoError(t, err)
+	defer pool.Close()
+	metrics := nodeconnmetrics.New(log)
+	pool.SetMetrics(metrics)
+	require.Len(t, metrics.GetEndpoints(), 2)
+
+	aliasOutputs := make(chan *txstream.MsgUnspentAliasOutput, 100)
+	pool.Events.UnspentAliasOutputReceived.Attach(events.NewClosure(func(msg *txstream.MsgUnspentAliasOutput) {
+		aliasOutputs <- msg
+	}))
+	transactions := make(chan *txstream.MsgTransaction, 100)
+	pool.Events.TransactionReceived.Attach(events.NewClosure(func(msg *txstream.MsgTransaction) {
+		transactions <- msg
+	}))
+	pool.Subscribe(chainAddr)
+
+	require.Eventually(t, func() bool { return pool.ActiveAddress() != "" }, 5*time.Second, 10*time.Millisecond)
+	active, activeIndex, standby, shutdownActive, shutdownStandby := addr1, 0, addr2, shutdown1, shutdown2
+	if pool.ActiveAddress() == addr2 {
+		active, activeIndex, standby, shutdownActive, shutdownStandby = addr2, 1, addr1, shutdown2, shutdown1
+	}
+	defer close(shutdownStandby)
+	receiveAliasOutput := func() {
+		select {
+		case msg := <-aliasOutputs:
+			require.True(t, msg.AliasAddress.Equals(chainAddr))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout")
+		}
+	}
+	pool.RequestUnspentAliasOutput(chainAddr)
+	receiveAliasOutput()
+
+	// the transaction is posted to the active endpoint only once
+	tx := newRequestTx(t, ledger, chainAddr)
+	pool.PostTransaction(tx)
+	pool.PostTransaction(tx)
+	pool.mutex.RLock()
+	require.EqualValues(t, map[int]bool{activeIndex: true}, pool.posted[tx.ID()].endpoints)
+	pool.mutex.RUnlock()
+	require.Eventually(t, func() bool {
+		_, ok := ledger.GetTransaction(tx.ID())
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the node of the active endpoint goes down
+	close(shutdownActive)
+	require.Eventually(t, func() bool { return pool.ActiveAddress() == standby }, 5*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 1, metrics.GetFailovers())
+	for _, e := range metrics.GetEndpoints() {
+		require.Equal(t, e.GetAddress() == standby, e.IsActive())
+		require.Equal(t, e.GetAddress() != active, e.IsConnected())
+	}
+
+	// the state of the chain is pulled from the new endpoint, the pending transaction is posted to it
+	receiveAliasOutput()
+	pool.mutex.RLock()
+	require.EqualValues(t, map[int]bool{0: true, 1: true}, pool.posted[tx.ID()].endpoints)
+	pool.mutex.RUnlock()
+
+	// the new endpoint is subscribed to the chain
+	tx2 := newRequestTx(t, ledger, chainAddr)
+	pool.PostTransaction(tx2)
+	require.Eventually(t, func() bool {
+		select {
+		case msg := <-transactions:
+			return msg.Tx.ID() == tx2.ID()
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestProbeAnswer(t *testing.T) {
+	ep := newEndpoint(&Pool{log: testlogger.NewLogger(t)}, 0, "127.0.0.1:1")
+	ep.connected = true
+	alias := ledgerstate.NewAliasAddress([]byte("alias"))
+	msg := &txstream.MsgUnspentAliasOutput{AliasAddress: alias}
+
+	// the answer only to the probe is consumed
+	ep.probe(alias, time.Minute)
+	require.True(t, ep.isProbeAnswer(msg))
+	require.False(t, ep.probePending)
+
+	// the answer to the request of the chain is forwarded, it also answers the probe
+	ep.probe(alias, time.Minute)
+	ep.requestUnspentAliasOutput(alias)
+	require.False(t, ep.isProbeAnswer(msg))
+	require.False(t, ep.probePending)
+	require.Empty(t, ep.aliasRequests)
+	require.False(t, ep.isProbeAnswer(msg))
+}
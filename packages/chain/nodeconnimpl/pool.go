@@ -0,0 +1,385 @@
+package nodeconnimpl
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/goshimmer/packages/txstream"
+	txstream_client "github.com/iotaledger/goshimmer/packages/txstream/client"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
+	"golang.org/x/xerrors"
+)
+
+const (
+	DefaultProbeInterval = 5 * time.Second
+	DefaultProbeTimeout  = 10 * time.Second
+	// postedTxTTL is how long the posted transactions are remembered to avoid posting them again
+	postedTxTTL = 1 * time.Minute
+)
+
+// Pool is a set of connections to the txstream servers of L1 nodes. Only one endpoint, the active one,
+// is used at a time: its events are forwarded to the Events of the pool and the requests are sent to it.
+// The health of all the endpoints is probed periodically. When the active endpoint is lost or doesn't
+// respond in time, the pool fails over to the first healthy endpoint in the order of configuration.
+// All the endpoints are subscribed to the same addresses, so the failover doesn't lose subscriptions
+type Pool struct {
+	Events        txstream_client.Events
+	clientID      string
+	endpoints     []*endpoint
+	active        *endpoint
+	subscriptions map[[ledgerstate.AddressLength]byte]ledgerstate.Address
+	posted        map[ledgerstate.TransactionID]*postedTx
+	mutex         sync.RWMutex
+	probeInterval time.Duration
+	probeTimeout  time.Duration
+	metrics       nodeconnmetrics.NodeConnectionMetrics
+	log           *logger.Logger
+	shutdown      chan struct{}
+	closeOnce     sync.Once
+}
+
+// postedTx is a posted transaction with the set of endpoints it has been posted to
+type postedTx struct {
+	tx        *ledgerstate.Transaction
+	postedAt  time.Time
+	endpoints map[int]bool
+}
+
+// NewPool connects to the txstream servers at the addresses. The order of addresses is the order of preference
+func NewPool(clientID string, addresses []string, log *logger.Logger, probeIntervalTimeout ...time.Duration) (*Pool, error) {
+	if len(addresses) == 0 {
+		return nil, xerrors.New("NewPool: no L1 node addresses")
+	}
+	ret := &Pool{
+		Events: txstream_client.Events{
+			TransactionReceived:        events.NewEvent(handleTransactionReceived),
+			InclusionStateReceived:     events.NewEvent(handleInclusionStateReceived),
+			OutputReceived:             events.NewEvent(handleOutputReceived),
+			UnspentAliasOutputReceived: events.NewEvent(handleUnspentAliasOutputReceived),
+			Connected:                  events.NewEvent(events.VoidCaller),
+		},
+		clientID:      clientID,
+		subscriptions: make(map[[ledgerstate.AddressLength]byte]ledgerstate.Address),
+		posted:        make(map[ledgerstate.TransactionID]*postedTx),
+		probeInterval: DefaultProbeInterval,
+		probeTimeout:  DefaultProbeTimeout,
+		metrics:       nodeconnmetrics.NewEmptyNodeConnectionMetrics(),
+		log:           log,
+		shutdown:      make(chan struct{}),
+	}
+	if len(probeIntervalTimeout) > 0 {
+		ret.probeInterval = probeIntervalTimeout[0]
+	}
+	if len(probeIntervalTimeout) > 1 {
+		ret.probeTimeout = probeIntervalTimeout[1]
+	}
+	for i, addr := range addresses {
+		ret.endpoints = append(ret.endpoints, newEndpoint(ret, i, addr))
+	}
+	for _, ep := range ret.endpoints {
+		ep.start()
+		ret.attachToEndpoint(ep)
+	}
+	go ret.probeLoop()
+	return ret, nil
+}
+
+func handleTransactionReceived(handler interface{}, params ...interface{}) {
+	handler.(func(*txstream.MsgTransaction))(params[0].(*txstream.MsgTransaction))
+}
+
+func handleInclusionStateReceived(handler interface{}, params ...interface{}) {
+	handler.(func(*txstream.MsgTxInclusionState))(params[0].(*txstream.MsgTxInclusionState))
+}
+
+func handleOutputReceived(handler interface{}, params ...interface{}) {
+	handler.(func(*txstream.MsgOutput))(params[0].(*txstream.MsgOutput))
+}
+
+func handleUnspentAliasOutputReceived(handler interface{}, params ...interface{}) {
+	handler.(func(*txstream.MsgUnspentAliasOutput))(params[0].(*txstream.MsgUnspentAliasOutput))
+}
+
+// attachToEndpoint forwards the events of the endpoint to the events of the pool while the endpoint is active
+func (p *Pool) attachToEndpoint(ep *endpoint) {
+	forward := func(event *events.Event, msg interface{}) {
+		ep.received.Inc()
+		if p.activeEndpoint() == ep {
+			event.Trigger(msg)
+		}
+	}
+	ep.client.Events.TransactionReceived.Attach(events.NewClosure(func(msg *txstream.MsgTransaction) {
+		forward(p.Events.TransactionReceived, msg)
+	}))
+	ep.client.Events.InclusionStateReceived.Attach(events.NewClosure(func(msg *txstream.MsgTxInclusionState) {
+		if msg.State == ledgerstate.Confirmed {
+			p.mutex.Lock()
+			delete(p.posted, msg.TxID)
+			p.mutex.Unlock()
+		}
+		forward(p.Events.InclusionStateReceived, msg)
+	}))
+	ep.client.Events.OutputReceived.Attach(events.NewClosure(func(msg *txstream.MsgOutput) {
+		forward(p.Events.OutputReceived, msg)
+	}))
+	ep.client.Events.UnspentAliasOutputReceived.Attach(events.NewClosure(func(msg *txstream.MsgUnspentAliasOutput) {
+		if ep.isProbeAnswer(msg) {
+			ep.received.Inc()
+			return
+		}
+		forward(p.Events.UnspentAliasOutputReceived, msg)
+	}))
+	ep.client.Events.Connected.Attach(events.NewClosure(func() {
+		p.resubscribe(ep)
+	}))
+}
+
+// SetMetrics makes the pool report the state of its endpoints and the failovers to the metrics
+func (p *Pool) SetMetrics(metrics nodeconnmetrics.NodeConnectionMetrics) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.metrics = metrics
+	for _, ep := range p.endpoints {
+		metrics.AddEndpoint(ep)
+	}
+}
+
+func (p *Pool) activeEndpoint() *endpoint {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.active
+}
+
+// ActiveAddress returns the address of the active endpoint or an empty string if there is none
+func (p *Pool) ActiveAddress() string {
+	if ep := p.activeEndpoint(); ep != nil {
+		return ep.address
+	}
+	return ""
+}
+
+func (p *Pool) probeLoop() {
+	ticker := time.NewTicker(p.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.shutdown:
+			return
+		case <-ticker.C:
+			p.probe()
+			p.checkHealth()
+			p.cleanupPosted()
+		}
+	}
+}
+
+// probe asks each connected endpoint for the state of one of the subscribed chains.
+// Without subscriptions only the state of the connections is known
+func (p *Pool) probe() {
+	p.mutex.RLock()
+	var alias *ledgerstate.AliasAddress
+	for _, addr := range p.subscriptions {
+		if a, ok := addr.(*ledgerstate.AliasAddress); ok {
+			alias = a
+			break
+		}
+	}
+	p.mutex.RUnlock()
+	if alias == nil {
+		return
+	}
+	for _, ep := range p.endpoints {
+		ep.probe(alias, p.probeTimeout)
+	}
+}
+
+// checkHealth fails over to another endpoint if the active one is not healthy
+func (p *Pool) checkHealth() {
+	p.mutex.Lock()
+	if p.active != nil && p.active.isHealthy(p.probeTimeout) {
+		p.mutex.Unlock()
+		return
+	}
+	var next *endpoint
+	for _, ep := range p.endpoints {
+		if ep.isHealthy(p.probeTimeout) {
+			next = ep
+			break
+		}
+	}
+	prev := p.active
+	p.active = next
+	metrics := p.metrics
+	p.mutex.Unlock()
+
+	if prev == next {
+		return
+	}
+	switch {
+	case next == nil:
+		p.log.Errorf("node connection: no healthy L1 node endpoints")
+		return
+	case prev == nil:
+		p.log.Infof("node connection: using endpoint %s", next.address)
+	default:
+		p.log.Warnf("node connection: failing over from %s to %s", prev.address, next.address)
+		metrics.CountFailover()
+	}
+	p.catchUp(next)
+	p.Events.Connected.Trigger()
+}
+
+// catchUp re-sends to the new active endpoint what could have been lost with the previous one:
+// the pending transactions and the requests for the states of the subscribed chains
+func (p *Pool) catchUp(ep *endpoint) {
+	p.mutex.Lock()
+	txs := make([]*ledgerstate.Transaction, 0)
+	for _, ptx := range p.posted {
+		if !ptx.endpoints[ep.index] {
+			ptx.endpoints[ep.index] = true
+			txs = append(txs, ptx.tx)
+		}
+	}
+	aliases := make([]*ledgerstate.AliasAddress, 0)
+	for _, addr := range p.subscriptions {
+		if a, ok := addr.(*ledgerstate.AliasAddress); ok {
+			aliases = append(aliases, a)
+		}
+	}
+	p.mutex.Unlock()
+
+	for _, tx := range txs {
+		tx := tx
+		ep.send(func(c *txstream_client.Client) { c.PostTransaction(tx) })
+	}
+	for _, alias := range aliases {
+		ep.requestUnspentAliasOutput(alias)
+	}
+}
+
+// resubscribe sends all the subscriptions to the endpoint after it (re)connects.
+// The txstream client only sends subscriptions when a new address is added
+func (p *Pool) resubscribe(ep *endpoint) {
+	p.mutex.RLock()
+	addrs := make([]ledgerstate.Address, 0, len(p.subscriptions))
+	for _, addr := range p.subscriptions {
+		addrs = append(addrs, addr)
+	}
+	p.mutex.RUnlock()
+	for _, addr := range addrs {
+		addr := addr
+		ep.send(func(c *txstream_client.Client) {
+			c.Unsubscribe(addr)
+			c.Subscribe(addr)
+		})
+	}
+}
+
+func (p *Pool) cleanupPosted() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for txid, ptx := range p.posted {
+		if time.Since(ptx.postedAt) > postedTxTTL {
+			delete(p.posted, txid)
+		}
+	}
+}
+
+// sendToActive queues the call to the active endpoint. Returns false if there is no healthy endpoint
+func (p *Pool) sendToActive(f func(*txstream_client.Client)) bool {
+	ep := p.activeEndpoint()
+	if ep == nil {
+		return false
+	}
+	ep.send(f)
+	return true
+}
+
+func (p *Pool) RequestUnspentAliasOutput(addr *ledgerstate.AliasAddress) {
+	if ep := p.activeEndpoint(); ep != nil {
+		ep.requestUnspentAliasOutput(addr)
+	} else {
+		p.log.Debugf("node connection: no active endpoint, unspent alias output request for %s dropped", addr.Base58())
+	}
+}
+
+func (p *Pool) RequestTxInclusionState(addr ledgerstate.Address, txid ledgerstate.TransactionID) {
+	if !p.sendToActive(func(c *txstream_client.Client) { c.RequestTxInclusionState(addr, txid) }) {
+		p.log.Debugf("node connection: no active endpoint, inclusion state request for %s dropped", txid.Base58())
+	}
+}
+
+func (p *Pool) RequestConfirmedOutput(addr ledgerstate.Address, outputID ledgerstate.OutputID) {
+	if !p.sendToActive(func(c *txstream_client.Client) { c.RequestConfirmedOutput(addr, outputID) }) {
+		p.log.Debugf("node connection: no active endpoint, confirmed output request for %s dropped", outputID.Base58())
+	}
+}
+
+// PostTransaction posts the transaction to the active endpoint. The transaction is posted to each
+// endpoint at most once: repeated posts are ignored, and the pending transactions are posted
+// to the new active endpoint after the failover
+func (p *Pool) PostTransaction(tx *ledgerstate.Transaction) {
+	p.mutex.Lock()
+	ptx, ok := p.posted[tx.ID()]
+	if !ok {
+		ptx = &postedTx{
+			tx:        tx,
+			postedAt:  time.Now(),
+			endpoints: make(map[int]bool),
+		}
+		p.posted[tx.ID()] = ptx
+	}
+	ep := p.active
+	if ep == nil {
+		p.mutex.Unlock()
+		p.log.Warnf("node connection: no active endpoint, transaction %s will be posted later", tx.ID().Base58())
+		return
+	}
+	if ptx.endpoints[ep.index] {
+		p.mutex.Unlock()
+		p.log.Debugf("node connection: transaction %s has already been posted to %s", tx.ID().Base58(), ep.address)
+		return
+	}
+	ptx.endpoints[ep.index] = true
+	p.mutex.Unlock()
+
+	ep.send(func(c *txstream_client.Client) { c.PostTransaction(tx) })
+}
+
+// Subscribe subscribes all the endpoints to the address
+func (p *Pool) Subscribe(addr ledgerstate.Address) {
+	p.mutex.Lock()
+	p.subscriptions[addr.Array()] = addr
+	p.mutex.Unlock()
+	for _, ep := range p.endpoints {
+		ep.send(func(c *txstream_client.Client) { c.Subscribe(addr) })
+	}
+}
+
+// Unsubscribe unsubscribes all the endpoints from the address
+func (p *Pool) Unsubscribe(addr ledgerstate.Address) {
+	p.mutex.Lock()
+	delete(p.subscriptions, addr.Array())
+	p.mutex.Unlock()
+	for _, ep := range p.endpoints {
+		ep.send(func(c *txstream_client.Client) { c.Unsubscribe(addr) })
+	}
+}
+
+// Close closes the connections to all the endpoints
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.shutdown)
+		for _, ep := range p.endpoints {
+			ep.client.Close()
+		}
+		p.Events.TransactionReceived.DetachAll()
+		p.Events.InclusionStateReceived.DetachAll()
+		p.Events.OutputReceived.DetachAll()
+		p.Events.UnspentAliasOutputReceived.DetachAll()
+		p.Events.Connected.DetachAll()
+	})
+}
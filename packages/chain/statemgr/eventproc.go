@@ -4,6 +4,8 @@
 package statemgr
 
 import (
+	"time"
+
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -77,6 +79,68 @@ func (sm *stateManager) handleBlockMsg(msg *messages.BlockMsgIn) {
 	}
 }
 
+// EventGetSnapshotMsg is a request for a snapshot of the committed state while syncing
+func (sm *stateManager) EnqueueGetSnapshotMsg(msg *messages.GetSnapshotMsgIn) {
+	sm.eventGetSnapshotMsgPipe.In() <- msg
+}
+
+func (sm *stateManager) handleGetSnapshotMsg(msg *messages.GetSnapshotMsgIn) {
+	sm.log.Debugw("handleGetSnapshotMsg: ",
+		"sender", msg.SenderNetID,
+		"min block index", msg.MinBlockIndex,
+	)
+	if sm.solidState.BlockIndex() < msg.MinBlockIndex {
+		sm.log.Debugf("handleGetSnapshotMsg: message ignored: solid state index #%d is older than requested #%d",
+			sm.solidState.BlockIndex(), msg.MinBlockIndex)
+		return
+	}
+	nowis := time.Now()
+	if nowis.Sub(sm.snapshotServedTime[msg.SenderNetID]) < sm.timers.ServeSnapshotInterval {
+		sm.log.Debugf("handleGetSnapshotMsg: message ignored: snapshot was recently sent to peer %s", msg.SenderNetID)
+		return
+	}
+	// writing the snapshot takes long, so only one snapshot is sent at a time, outside the event loop
+	if !sm.sendingSnapshot.CAS(false, true) {
+		sm.log.Debugf("handleGetSnapshotMsg: message ignored: another snapshot is being sent")
+		return
+	}
+	sm.snapshotServedTime[msg.SenderNetID] = nowis
+	go func() {
+		defer sm.sendingSnapshot.Store(false)
+		header, err := sm.sendSnapshot(msg.SenderNetID)
+		if err != nil {
+			sm.log.Warnf("handleGetSnapshotMsg: failed to send snapshot to peer %s: %v", msg.SenderNetID, err)
+			return
+		}
+		sm.log.Debugf("handleGetSnapshotMsg: responded to peer %s by snapshot at index %v", msg.SenderNetID, header.BlockIndex())
+	}()
+}
+
+// EventSnapshotMsg
+func (sm *stateManager) EnqueueSnapshotMsg(msg *messages.SnapshotMsgIn) {
+	sm.eventSnapshotMsgPipe.In() <- msg
+}
+
+func (sm *stateManager) handleSnapshotMsg(msg *messages.SnapshotMsgIn) {
+	sm.log.Debugw("handleSnapshotMsg: ",
+		"sender", msg.SenderNetID,
+		"chunk", msg.ChunkIndex,
+		"last", msg.Last,
+		"size", len(msg.Data),
+	)
+	if sm.stateOutput == nil {
+		sm.log.Debugf("handleSnapshotMsg: message ignored: stateOutput is nil")
+		return
+	}
+	snapshotBytes := sm.receiveSnapshotChunk(msg)
+	if snapshotBytes == nil {
+		return
+	}
+	if sm.addSnapshotCandidate(snapshotBytes, msg.SenderNetID) {
+		sm.takeAction()
+	}
+}
+
 func (sm *stateManager) EnqueueOutputMsg(msg ledgerstate.Output) {
 	sm.eventOutputMsgPipe.In() <- msg
 }
@@ -46,6 +46,8 @@ type MockedEnv struct {
 	ChainID           *iscp.ChainID
 	mutex             sync.Mutex
 	Nodes             map[string]*MockedNode
+	SnapshotConfig    SnapshotConfig
+	HistoryDepth      uint32
 	push              bool
 }
 
@@ -231,9 +233,29 @@ func (env *MockedEnv) NewMockedNode(nodeIndex int, timers StateManagerTimers) *M
 				BlockMsg:    *msg,
 				SenderNetID: peerMsg.SenderNetID,
 			})
+		case peerMsgTypeGetSnapshot:
+			msg, err := messages.NewGetSnapshotMsg(peerMsg.MsgData)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			ret.StateManager.EnqueueGetSnapshotMsg(&messages.GetSnapshotMsgIn{
+				GetSnapshotMsg: *msg,
+				SenderNetID:    peerMsg.SenderNetID,
+			})
+		case peerMsgTypeSnapshot:
+			msg, err := messages.NewSnapshotMsg(peerMsg.MsgData)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			ret.StateManager.EnqueueSnapshotMsg(&messages.SnapshotMsgIn{
+				SnapshotMsg: *msg,
+				SenderNetID: peerMsg.SenderNetID,
+			})
 		}
 	})
-	ret.StateManager = New(ret.store, ret.ChainCore, ret.ChainPeers, ret.NodeConn, stateMgrMetrics, timers)
+	ret.StateManager = New(ret.store, ret.ChainCore, ret.ChainPeers, ret.NodeConn, stateMgrMetrics, env.SnapshotConfig, env.HistoryDepth, timers)
 	ret.StateTransition = testchain.NewMockedStateTransition(env.T, env.OriginatorKeyPair)
 	ret.StateTransition.OnNextState(func(vstate state.VirtualStateAccess, tx *ledgerstate.Transaction) {
 		log.Debugf("MockedEnv.onNextState: state index %d", vstate.BlockIndex())
@@ -0,0 +1,386 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package statemgr
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	snapshotFileExtension = ".snapshot"
+	// snapshots are sent to peers in chunks of this size, to keep the peer messages small
+	snapshotChunkSize = 64 * 1024
+	// limits of the snapshot received from a peer, to protect the node from memory exhaustion
+	maxSnapshotSize   = 1024 * 1024 * 1024
+	maxSnapshotChunks = maxSnapshotSize / snapshotChunkSize
+)
+
+// SnapshotConfig defines how the state manager uses snapshots of the state
+type SnapshotConfig struct {
+	// directory of snapshot files of the chain. Empty means snapshots are neither written to nor loaded from disk
+	Dir string
+	// snapshot of the committed state is written to Dir each Interval blocks. 0 means snapshots are not written
+	Interval uint32
+	// if solid state is behind the state output by more than SyncThreshold blocks, the state manager
+	// bootstraps from the newest available snapshot and syncs only remaining blocks. 0 means snapshots are not used for syncing
+	SyncThreshold uint32
+}
+
+// snapshotCandidate is a snapshot received from peer or loaded from disk. Its state hash must be approved
+// by the alias output, before it is imported
+type snapshotCandidate struct {
+	header              *state.SnapshotHeader
+	data                []byte
+	source              string
+	approved            bool
+	pullOutputRetryTime time.Time
+}
+
+// snapshotTransfer collects the chunks of the snapshot received from peer
+type snapshotTransfer struct {
+	nextChunk        uint32
+	data             bytes.Buffer
+	lastReceivedTime time.Time
+}
+
+// syncFromSnapshotIfNeeded bootstraps the solid state from a snapshot if the state manager is too far behind.
+// Returns true if the snapshot is still being received or approved; blocks must not be synced meanwhile
+func (sm *stateManager) syncFromSnapshotIfNeeded() bool {
+	if sm.snapshotConfig.SyncThreshold == 0 || sm.stateOutput.GetStateIndex()-sm.solidState.BlockIndex() <= sm.snapshotConfig.SyncThreshold {
+		sm.snapshotCandidate = nil
+		return false
+	}
+	if sm.snapshotCandidate == nil {
+		sm.loadSnapshotCandidateFromDir()
+	}
+	if sm.snapshotCandidate == nil {
+		sm.requestSnapshotFromPeers()
+		return sm.isSnapshotTransferPending()
+	}
+	if !sm.snapshotCandidate.approved {
+		sm.approveSnapshotCandidateIfPossible()
+		if sm.snapshotCandidate == nil {
+			return sm.isSnapshotTransferPending()
+		}
+		if !sm.snapshotCandidate.approved {
+			return true
+		}
+	}
+	sm.importSnapshotCandidate()
+	return false
+}
+
+// isSnapshotTransferPending returns true if some peer is sending a snapshot. Transfers, which
+// did not receive a chunk for GetSnapshotRetry, are dropped
+func (sm *stateManager) isSnapshotTransferPending() bool {
+	nowis := time.Now()
+	for peerNetID, transfer := range sm.snapshotTransfers {
+		if nowis.Sub(transfer.lastReceivedTime) > sm.timers.GetSnapshotRetry {
+			sm.log.Debugf("isSnapshotTransferPending: snapshot transfer from %s timed out", peerNetID)
+			delete(sm.snapshotTransfers, peerNetID)
+		}
+	}
+	return len(sm.snapshotTransfers) > 0
+}
+
+func (sm *stateManager) requestSnapshotFromPeers() {
+	nowis := time.Now()
+	if nowis.Before(sm.getSnapshotRetryTime) {
+		return
+	}
+	sm.log.Debugf("requestSnapshotFromPeers: requesting snapshot from %v random peers", numberOfNodesToRequestBlockFromConst)
+	getSnapshotMsg := &messages.GetSnapshotMsg{MinBlockIndex: sm.solidState.BlockIndex() + 1}
+	sm.chainPeers.SendPeerMsgToRandomPeers(numberOfNodesToRequestBlockFromConst, peering.PeerMessageReceiverStateManager, peerMsgTypeGetSnapshot, util.MustBytes(getSnapshotMsg))
+	sm.getSnapshotRetryTime = nowis.Add(sm.timers.GetSnapshotRetry)
+}
+
+// addSnapshotCandidate returns true if the snapshot is newer than the current candidate and is usable for syncing
+func (sm *stateManager) addSnapshotCandidate(data []byte, source string) bool {
+	header, err := state.ReadSnapshotHeader(bytes.NewReader(data))
+	if err != nil {
+		sm.log.Warnf("addSnapshotCandidate: wrong snapshot from %s: %v", source, err)
+		return false
+	}
+	if !sm.isSnapshotUsable(header) {
+		sm.log.Debugf("addSnapshotCandidate: snapshot from %s at index %v is not needed", source, header.BlockIndex())
+		return false
+	}
+	if sm.snapshotCandidate != nil && sm.snapshotCandidate.header.BlockIndex() >= header.BlockIndex() {
+		sm.log.Debugf("addSnapshotCandidate: snapshot from %s at index %v is not newer than current candidate",
+			source, header.BlockIndex())
+		return false
+	}
+	sm.log.Infof("addSnapshotCandidate: snapshot from %s at index %v, state hash %s is a candidate for syncing",
+		source, header.BlockIndex(), header.StateHash)
+	sm.snapshotCandidate = &snapshotCandidate{
+		header: header,
+		data:   data,
+		source: source,
+	}
+	return true
+}
+
+func (sm *stateManager) isSnapshotUsable(header *state.SnapshotHeader) bool {
+	if sm.stateOutput == nil {
+		return false
+	}
+	if _, rejected := sm.rejectedSnapshots[header.StateHash]; rejected {
+		return false
+	}
+	return header.BlockIndex() > sm.solidState.BlockIndex() && header.BlockIndex() <= sm.stateOutput.GetStateIndex()
+}
+
+func (sm *stateManager) rejectSnapshotCandidate(reason string) {
+	sm.log.Warnf("snapshot from %s at index %v rejected: %s",
+		sm.snapshotCandidate.source, sm.snapshotCandidate.header.BlockIndex(), reason)
+	sm.rejectedSnapshots[sm.snapshotCandidate.header.StateHash] = struct{}{}
+	sm.snapshotCandidate = nil
+}
+
+// approveSnapshotCandidateIfPossible checks the state hash of the snapshot against the alias output
+// with the same state index. If it is not the current state output, the approving output is pulled from L1
+func (sm *stateManager) approveSnapshotCandidateIfPossible() {
+	header := sm.snapshotCandidate.header
+	if header.BlockIndex() == sm.stateOutput.GetStateIndex() {
+		sm.checkSnapshotCandidateOutput(sm.stateOutput)
+		return
+	}
+	nowis := time.Now()
+	if nowis.After(sm.snapshotCandidate.pullOutputRetryTime) {
+		sm.log.Debugf("approveSnapshotCandidateIfPossible: requesting approving output ID %v", iscp.OID(header.Block.ApprovingOutputID()))
+		sm.nodeConn.PullConfirmedOutput(header.Block.ApprovingOutputID())
+		sm.snapshotCandidate.pullOutputRetryTime = nowis.Add(sm.timers.GetSnapshotRetry)
+	}
+}
+
+// snapshotOutputPulled returns true if the output was pulled to approve the snapshot candidate
+func (sm *stateManager) snapshotOutputPulled(output *ledgerstate.AliasOutput) bool {
+	if sm.snapshotCandidate == nil || sm.snapshotCandidate.approved {
+		return false
+	}
+	if output.ID() != sm.snapshotCandidate.header.Block.ApprovingOutputID() {
+		return false
+	}
+	sm.checkSnapshotCandidateOutput(output)
+	return true
+}
+
+func (sm *stateManager) checkSnapshotCandidateOutput(output *ledgerstate.AliasOutput) {
+	header := sm.snapshotCandidate.header
+	if output.GetStateIndex() != header.BlockIndex() {
+		sm.rejectSnapshotCandidate(fmt.Sprintf("index of the output %v is %v", iscp.OID(output.ID()), output.GetStateIndex()))
+		return
+	}
+	if !bytes.Equal(output.GetStateData(), header.StateHash.Bytes()) {
+		sm.rejectSnapshotCandidate(fmt.Sprintf("state hash does not match state hash of the output %v", iscp.OID(output.ID())))
+		return
+	}
+	sm.log.Debugf("snapshot at index %v is approved by output %v", header.BlockIndex(), iscp.OID(output.ID()))
+	sm.snapshotCandidate.approved = true
+}
+
+func (sm *stateManager) importSnapshotCandidate() {
+	candidate := sm.snapshotCandidate
+	if !sm.isSnapshotUsable(candidate.header) {
+		sm.snapshotCandidate = nil
+		return
+	}
+	// invalidate solid state, same as in commitCandidates
+	sm.chain.GlobalStateSync().InvalidateSolidIndex()
+	importedState, err := state.ImportSnapshot(sm.store, sm.chain.ID(), bytes.NewReader(candidate.data))
+	if err != nil {
+		sm.chain.GlobalStateSync().SetSolidIndex(sm.solidState.BlockIndex())
+		sm.rejectSnapshotCandidate(fmt.Sprintf("import failed: %v", err))
+		return
+	}
+	sm.chain.GlobalStateSync().SetSolidIndex(importedState.BlockIndex())
+	sm.solidState = importedState
+	sm.snapshotCandidate = nil
+	sm.syncingBlocks.deleteSyncingBlocksNotNewerThan(importedState.BlockIndex())
+	sm.log.Infof("SOLID STATE has been imported from snapshot from %s. Block index: #%d, State hash: %s",
+		candidate.source, importedState.BlockIndex(), importedState.StateCommitment().String())
+}
+
+// loadSnapshotCandidateFromDir takes the newest usable snapshot file from the snapshot directory
+func (sm *stateManager) loadSnapshotCandidateFromDir() {
+	if sm.snapshotConfig.Dir == "" {
+		return
+	}
+	files, err := ioutil.ReadDir(sm.snapshotConfig.Dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			sm.log.Warnf("loadSnapshotCandidateFromDir: %v", err)
+		}
+		return
+	}
+	var bestFile string
+	var bestIndex uint32
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), snapshotFileExtension) {
+			continue
+		}
+		fileName := filepath.Join(sm.snapshotConfig.Dir, file.Name())
+		header, err := readSnapshotFileHeader(fileName)
+		if err != nil {
+			sm.log.Warnf("loadSnapshotCandidateFromDir: wrong snapshot file %s: %v", fileName, err)
+			continue
+		}
+		if sm.isSnapshotUsable(header) && header.BlockIndex() > bestIndex {
+			bestFile = fileName
+			bestIndex = header.BlockIndex()
+		}
+	}
+	if bestFile == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(bestFile)
+	if err != nil {
+		sm.log.Warnf("loadSnapshotCandidateFromDir: %v", err)
+		return
+	}
+	sm.addSnapshotCandidate(data, bestFile)
+}
+
+func readSnapshotFileHeader(fileName string) (*state.SnapshotHeader, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return state.ReadSnapshotHeader(f)
+}
+
+// storeSnapshotIfNeeded writes the snapshot of the committed state to the snapshot directory
+// if the committed blocks from..to cross the snapshot interval
+func (sm *stateManager) storeSnapshotIfNeeded(from, to uint32) {
+	if sm.snapshotConfig.Dir == "" || sm.snapshotConfig.Interval == 0 {
+		return
+	}
+	if from == 0 {
+		// the origin state is not worth a snapshot
+		from = 1
+	}
+	if from > to || to/sm.snapshotConfig.Interval == (from-1)/sm.snapshotConfig.Interval {
+		return
+	}
+	if err := os.MkdirAll(sm.snapshotConfig.Dir, 0o755); err != nil {
+		sm.log.Errorf("storeSnapshotIfNeeded: %v", err)
+		return
+	}
+	fileName := filepath.Join(sm.snapshotConfig.Dir, fmt.Sprintf("%010d%s", to, snapshotFileExtension))
+	tmpFileName := fileName + ".tmp"
+	f, err := os.Create(tmpFileName)
+	if err != nil {
+		sm.log.Errorf("storeSnapshotIfNeeded: %v", err)
+		return
+	}
+	header, err := state.WriteSnapshot(sm.store, f)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmpFileName, fileName)
+	}
+	if err != nil {
+		sm.log.Errorf("storeSnapshotIfNeeded: failed to write snapshot %s: %v", fileName, err)
+		_ = os.Remove(tmpFileName)
+		return
+	}
+	sm.log.Infof("snapshot of the state at index %v with hash %s written to %s", header.BlockIndex(), header.StateHash, fileName)
+}
+
+// sendSnapshot streams the snapshot of the committed state to the peer in chunks of snapshotChunkSize.
+// It is run outside of the event loop, so the snapshot is written to memory first and dropped
+// if the solid state changed while it was being written
+func (sm *stateManager) sendSnapshot(peerNetID string) (*state.SnapshotHeader, error) {
+	baseline := sm.chain.GlobalStateSync().GetSolidIndexBaseline()
+	if !baseline.IsValid() {
+		return nil, xerrors.New("solid state is being changed")
+	}
+	var buf bytes.Buffer
+	header, err := state.WriteSnapshot(sm.store, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if !baseline.IsValid() {
+		return nil, xerrors.New("solid state was changed while writing the snapshot")
+	}
+	w := &snapshotChunkWriter{send: func(msg *messages.SnapshotMsg) {
+		sm.chainPeers.SendMsgByNetID(peerNetID, peering.PeerMessageReceiverStateManager, peerMsgTypeSnapshot, util.MustBytes(msg))
+	}}
+	_, _ = w.Write(buf.Bytes())
+	w.flush(true)
+	return header, nil
+}
+
+// receiveSnapshotChunk adds the chunk to the snapshot being received from the peer.
+// Returns the whole snapshot when its last chunk is received
+func (sm *stateManager) receiveSnapshotChunk(msg *messages.SnapshotMsgIn) []byte {
+	transfer, ok := sm.snapshotTransfers[msg.SenderNetID]
+	if msg.ChunkIndex == 0 {
+		transfer = &snapshotTransfer{}
+		sm.snapshotTransfers[msg.SenderNetID] = transfer
+	} else if !ok || msg.ChunkIndex != transfer.nextChunk {
+		sm.log.Debugf("receiveSnapshotChunk: unexpected chunk #%d from %s, snapshot dropped", msg.ChunkIndex, msg.SenderNetID)
+		delete(sm.snapshotTransfers, msg.SenderNetID)
+		return nil
+	}
+	if len(msg.Data) > snapshotChunkSize || msg.ChunkIndex >= maxSnapshotChunks ||
+		transfer.data.Len()+len(msg.Data) > maxSnapshotSize {
+		sm.log.Warnf("receiveSnapshotChunk: snapshot from %s exceeds the limits, snapshot dropped", msg.SenderNetID)
+		delete(sm.snapshotTransfers, msg.SenderNetID)
+		return nil
+	}
+	transfer.data.Write(msg.Data)
+	transfer.nextChunk++
+	transfer.lastReceivedTime = time.Now()
+	if !msg.Last {
+		return nil
+	}
+	delete(sm.snapshotTransfers, msg.SenderNetID)
+	return transfer.data.Bytes()
+}
+
+// snapshotChunkWriter sends everything written to it as a sequence of SnapshotMsg chunks
+type snapshotChunkWriter struct {
+	send       func(*messages.SnapshotMsg)
+	buf        []byte
+	chunkIndex uint32
+}
+
+func (w *snapshotChunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		l := snapshotChunkSize - len(w.buf)
+		if l > len(p) {
+			l = len(p)
+		}
+		w.buf = append(w.buf, p[:l]...)
+		p = p[l:]
+		if len(w.buf) == snapshotChunkSize {
+			w.flush(false)
+		}
+	}
+	return n, nil
+}
+
+func (w *snapshotChunkWriter) flush(last bool) {
+	w.send(&messages.SnapshotMsg{ChunkIndex: w.chunkIndex, Last: last, Data: w.buf})
+	w.buf = nil
+	w.chunkIndex++
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/state"
@@ -35,6 +36,14 @@ type stateManager struct {
 	currentSyncData             atomic.Value
 	notifiedAnchorOutputID      ledgerstate.OutputID
 	syncingBlocks               *syncingBlocks
+	snapshotConfig              SnapshotConfig
+	historyDepth                uint32
+	snapshotCandidate           *snapshotCandidate
+	rejectedSnapshots           map[hashing.HashValue]struct{}
+	snapshotTransfers           map[string]*snapshotTransfer
+	getSnapshotRetryTime        time.Time
+	snapshotServedTime          map[string]time.Time
+	sendingSnapshot             atomic.Bool
 	receivePeerMessagesAttachID interface{}
 	timers                      StateManagerTimers
 	log                         *logger.Logger
@@ -42,6 +51,8 @@ type stateManager struct {
 	// Channels for accepting external events.
 	eventGetBlockMsgPipe       pipe.Pipe
 	eventBlockMsgPipe          pipe.Pipe
+	eventGetSnapshotMsgPipe    pipe.Pipe
+	eventSnapshotMsgPipe       pipe.Pipe
 	eventStateOutputMsgPipe    pipe.Pipe
 	eventOutputMsgPipe         pipe.Pipe
 	eventStateCandidateMsgPipe pipe.Pipe
@@ -58,9 +69,11 @@ const (
 
 	peerMsgTypeGetBlock = iota
 	peerMsgTypeBlock
+	peerMsgTypeGetSnapshot
+	peerMsgTypeSnapshot
 )
 
-func New(store kvstore.KVStore, c chain.ChainCore, peers peering.PeerDomainProvider, nodeconn chain.ChainNodeConnection, stateManagerMetrics metrics.StateManagerMetrics, timersOpt ...StateManagerTimers) chain.StateManager {
+func New(store kvstore.KVStore, c chain.ChainCore, peers peering.PeerDomainProvider, nodeconn chain.ChainNodeConnection, stateManagerMetrics metrics.StateManagerMetrics, snapshotConfig SnapshotConfig, historyDepth uint32, timersOpt ...StateManagerTimers) chain.StateManager {
 	var timers StateManagerTimers
 	if len(timersOpt) > 0 {
 		timers = timersOpt[0]
@@ -74,11 +87,18 @@ func New(store kvstore.KVStore, c chain.ChainCore, peers peering.PeerDomainProvi
 		nodeConn:                   nodeconn,
 		chainPeers:                 peers,
 		syncingBlocks:              newSyncingBlocks(c.Log(), timers.GetBlockRetry),
+		snapshotConfig:             snapshotConfig,
+		historyDepth:               historyDepth,
+		rejectedSnapshots:          make(map[hashing.HashValue]struct{}),
+		snapshotTransfers:          make(map[string]*snapshotTransfer),
+		snapshotServedTime:         make(map[string]time.Time),
 		timers:                     timers,
 		log:                        c.Log().Named("s"),
 		pullStateRetryTime:         time.Now(),
 		eventGetBlockMsgPipe:       pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventBlockMsgPipe:          pipe.NewLimitInfinitePipe(maxMsgBuffer),
+		eventGetSnapshotMsgPipe:    pipe.NewLimitInfinitePipe(maxMsgBuffer),
+		eventSnapshotMsgPipe:       pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventStateOutputMsgPipe:    pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventOutputMsgPipe:         pipe.NewLimitInfinitePipe(maxMsgBuffer),
 		eventStateCandidateMsgPipe: pipe.NewLimitInfinitePipe(maxMsgBuffer),
@@ -114,6 +134,26 @@ func (sm *stateManager) receiveChainPeerMessages(peerMsg *peering.PeerMessageIn)
 			BlockMsg:    *msg,
 			SenderNetID: peerMsg.SenderNetID,
 		})
+	case peerMsgTypeGetSnapshot:
+		msg, err := messages.NewGetSnapshotMsg(peerMsg.MsgData)
+		if err != nil {
+			sm.log.Error(err)
+			return
+		}
+		sm.EnqueueGetSnapshotMsg(&messages.GetSnapshotMsgIn{
+			GetSnapshotMsg: *msg,
+			SenderNetID:    peerMsg.SenderNetID,
+		})
+	case peerMsgTypeSnapshot:
+		msg, err := messages.NewSnapshotMsg(peerMsg.MsgData)
+		if err != nil {
+			sm.log.Error(err)
+			return
+		}
+		sm.EnqueueSnapshotMsg(&messages.SnapshotMsgIn{
+			SnapshotMsg: *msg,
+			SenderNetID: peerMsg.SenderNetID,
+		})
 	default:
 		sm.log.Warnf("Wrong type of state manager message: %v, ignoring it", peerMsg.MsgType)
 	}
@@ -125,6 +165,8 @@ func (sm *stateManager) Close() {
 
 	sm.eventGetBlockMsgPipe.Close()
 	sm.eventBlockMsgPipe.Close()
+	sm.eventGetSnapshotMsgPipe.Close()
+	sm.eventSnapshotMsgPipe.Close()
 	sm.eventStateOutputMsgPipe.Close()
 	sm.eventOutputMsgPipe.Close()
 	sm.eventStateCandidateMsgPipe.Close()
@@ -182,6 +224,8 @@ func (sm *stateManager) recvLoop() {
 	sm.ready.SetReady()
 	eventGetBlockMsgCh := sm.eventGetBlockMsgPipe.Out()
 	eventBlockMsgCh := sm.eventBlockMsgPipe.Out()
+	eventGetSnapshotMsgCh := sm.eventGetSnapshotMsgPipe.Out()
+	eventSnapshotMsgCh := sm.eventSnapshotMsgPipe.Out()
 	eventStateOutputMsgCh := sm.eventStateOutputMsgPipe.Out()
 	eventOutputMsgCh := sm.eventOutputMsgPipe.Out()
 	eventStateCandidateMsgCh := sm.eventStateCandidateMsgPipe.Out()
@@ -200,6 +244,18 @@ func (sm *stateManager) recvLoop() {
 			} else {
 				eventBlockMsgCh = nil
 			}
+		case msg, ok := <-eventGetSnapshotMsgCh:
+			if ok {
+				sm.handleGetSnapshotMsg(msg.(*messages.GetSnapshotMsgIn))
+			} else {
+				eventGetSnapshotMsgCh = nil
+			}
+		case msg, ok := <-eventSnapshotMsgCh:
+			if ok {
+				sm.handleSnapshotMsg(msg.(*messages.SnapshotMsgIn))
+			} else {
+				eventSnapshotMsgCh = nil
+			}
 		case msg, ok := <-eventStateOutputMsgCh:
 			if ok {
 				sm.handleStateMsg(msg.(*messages.StateMsg))
@@ -227,6 +283,8 @@ func (sm *stateManager) recvLoop() {
 		}
 		if eventGetBlockMsgCh == nil &&
 			eventBlockMsgCh == nil &&
+			eventGetSnapshotMsgCh == nil &&
+			eventSnapshotMsgCh == nil &&
 			eventStateOutputMsgCh == nil &&
 			eventOutputMsgCh == nil &&
 			eventStateCandidateMsgCh == nil &&
@@ -5,6 +5,7 @@ package statemgr
 
 import (
 	"math/rand"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -12,7 +13,9 @@ import (
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate/utxoutil"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/stretchr/testify/require"
 )
 
@@ -375,6 +378,108 @@ func TestCruelWorld(t *testing.T) {
 	}
 }
 
+func TestSyncFromSnapshotFromPeer(t *testing.T) {
+	env, _ := NewMockedEnv(2, t, false)
+	env.SetPushStateToNodesOption(true)
+
+	node := env.NewMockedNode(0, NewStateManagerTimers())
+	node.StateManager.Ready().MustWait()
+	node.StartTimer()
+
+	env.AddNode(node)
+
+	const targetBlockIndex = 10
+	node.OnStateTransitionMakeNewStateTransition(targetBlockIndex)
+	waitSyncBlockIndexAndCheck(10*time.Second, t, node, targetBlockIndex)
+
+	env.SnapshotConfig = SnapshotConfig{SyncThreshold: 3}
+	node1 := env.NewMockedNode(1, NewStateManagerTimers())
+	node1.StateManager.Ready().MustWait()
+	node1.StartTimer()
+	env.AddNode(node1)
+
+	waitSyncBlockIndexAndCheck(10*time.Second, t, node1, targetBlockIndex)
+	// blocks before the snapshot were not synced
+	blockBytes, err := state.LoadBlockBytes(node1.store, targetBlockIndex/2)
+	require.NoError(t, err)
+	require.Nil(t, blockBytes)
+}
+
+func TestSyncFromSnapshotFromDir(t *testing.T) {
+	env, _ := NewMockedEnv(2, t, false)
+	env.SetPushStateToNodesOption(true)
+
+	dir := t.TempDir()
+	env.SnapshotConfig = SnapshotConfig{Dir: dir, Interval: 5}
+	node := env.NewMockedNode(0, NewStateManagerTimers())
+	node.StateManager.Ready().MustWait()
+	node.StartTimer()
+
+	env.AddNode(node)
+
+	const targetBlockIndex = 12
+	node.OnStateTransitionMakeNewStateTransition(targetBlockIndex)
+	waitSyncBlockIndexAndCheck(10*time.Second, t, node, targetBlockIndex)
+
+	require.FileExists(t, filepath.Join(dir, "0000000005.snapshot"))
+	require.FileExists(t, filepath.Join(dir, "0000000010.snapshot"))
+
+	env.SnapshotConfig = SnapshotConfig{Dir: dir, SyncThreshold: 3}
+	node1 := env.NewMockedNode(1, NewStateManagerTimers())
+	node1.StateManager.Ready().MustWait()
+	node1.StartTimer()
+	env.AddNode(node1)
+
+	waitSyncBlockIndexAndCheck(10*time.Second, t, node1, targetBlockIndex)
+	blockBytes, err := state.LoadBlockBytes(node1.store, 5)
+	require.NoError(t, err)
+	require.Nil(t, blockBytes)
+}
+
+func TestSnapshotChunks(t *testing.T) {
+	sm := &stateManager{
+		snapshotTransfers: make(map[string]*snapshotTransfer),
+		timers:            NewStateManagerTimers(),
+		log:               testlogger.NewLogger(t),
+	}
+	data := make([]byte, 2*snapshotChunkSize+10)
+	rand.Read(data)
+
+	var received []byte
+	w := &snapshotChunkWriter{send: func(msg *messages.SnapshotMsg) {
+		msgIn := &messages.SnapshotMsgIn{SnapshotMsg: *msg, SenderNetID: "peer"}
+		require.Nil(t, received)
+		received = sm.receiveSnapshotChunk(msgIn)
+	}}
+	_, err := w.Write(data[:10])
+	require.NoError(t, err)
+	_, err = w.Write(data[10:])
+	require.NoError(t, err)
+	w.flush(true)
+	require.EqualValues(t, 3, w.chunkIndex)
+	require.Equal(t, data, received)
+
+	// a missing chunk drops the snapshot
+	require.Nil(t, sm.receiveSnapshotChunk(&messages.SnapshotMsgIn{SnapshotMsg: messages.SnapshotMsg{ChunkIndex: 0}, SenderNetID: "peer"}))
+	require.Nil(t, sm.receiveSnapshotChunk(&messages.SnapshotMsgIn{SnapshotMsg: messages.SnapshotMsg{ChunkIndex: 2, Last: true}, SenderNetID: "peer"}))
+	require.Empty(t, sm.snapshotTransfers)
+
+	// chunks exceeding the limits drop the snapshot
+	require.Nil(t, sm.receiveSnapshotChunk(&messages.SnapshotMsgIn{SnapshotMsg: messages.SnapshotMsg{ChunkIndex: 0, Data: data}, SenderNetID: "peer"}))
+	require.Empty(t, sm.snapshotTransfers)
+	require.Nil(t, sm.receiveSnapshotChunk(&messages.SnapshotMsgIn{SnapshotMsg: messages.SnapshotMsg{ChunkIndex: 0, Data: data[:10]}, SenderNetID: "peer"}))
+	require.True(t, sm.isSnapshotTransferPending())
+	sm.snapshotTransfers["peer"].nextChunk = maxSnapshotChunks
+	require.Nil(t, sm.receiveSnapshotChunk(&messages.SnapshotMsgIn{SnapshotMsg: messages.SnapshotMsg{ChunkIndex: maxSnapshotChunks, Data: data[:10]}, SenderNetID: "peer"}))
+	require.False(t, sm.isSnapshotTransferPending())
+
+	// stalled transfer is no longer pending
+	require.Nil(t, sm.receiveSnapshotChunk(&messages.SnapshotMsgIn{SnapshotMsg: messages.SnapshotMsg{ChunkIndex: 0, Data: data[:10]}, SenderNetID: "peer"}))
+	sm.snapshotTransfers["peer"].lastReceivedTime = time.Now().Add(-2 * sm.timers.GetSnapshotRetry)
+	require.False(t, sm.isSnapshotTransferPending())
+	require.Empty(t, sm.snapshotTransfers)
+}
+
 func waitSyncBlockIndexAndCheck(duration time.Duration, t *testing.T, node *MockedNode, target uint32) *chain.SyncInfo {
 	si, err := node.WaitSyncBlockIndex(target, duration)
 	require.NoError(t, err)
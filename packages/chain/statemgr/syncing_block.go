@@ -183,3 +183,11 @@ func (syncsT *syncingBlocks) restartSyncing() {
 func (syncsT *syncingBlocks) deleteSyncingBlock(stateIndex uint32) {
 	delete(syncsT.blocks, stateIndex)
 }
+
+func (syncsT *syncingBlocks) deleteSyncingBlocksNotNewerThan(stateIndex uint32) {
+	for i := range syncsT.blocks {
+		if i <= stateIndex {
+			delete(syncsT.blocks, i)
+		}
+	}
+}
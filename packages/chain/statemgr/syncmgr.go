@@ -16,6 +16,9 @@ import (
 
 func (sm *stateManager) outputPulled(output *ledgerstate.AliasOutput) bool {
 	sm.log.Debugf("outputPulled: output index %v id %v", output.GetStateIndex(), iscp.OID(output.ID()))
+	if sm.snapshotOutputPulled(output) {
+		return true
+	}
 	if !sm.syncingBlocks.isSyncing(output.GetStateIndex()) {
 		// not interested
 		sm.log.Debugf("outputPulled: not interested in output for state index %v", output.GetStateIndex())
@@ -70,6 +73,14 @@ func (sm *stateManager) doSyncActionIfNeeded() {
 		sm.log.Panicf("doSyncAction inconsistency: solid state index is larger than state output index")
 	}
 	// not synced
+	if sm.syncFromSnapshotIfNeeded() {
+		sm.log.Debugf("doSyncAction: waiting for snapshot to sync state up to index #%d", sm.stateOutput.GetStateIndex())
+		return
+	}
+	if sm.solidState.BlockIndex() == sm.stateOutput.GetStateIndex() {
+		sm.log.Debugf("doSyncAction: state is synced from snapshot at index #%d", sm.stateOutput.GetStateIndex())
+		return
+	}
 	startSyncFromIndex := sm.solidState.BlockIndex() + 1
 	sm.log.Debugf("doSyncAction: trying to sync state from index %v to %v", startSyncFromIndex, sm.stateOutput.GetStateIndex())
 	for i := startSyncFromIndex; i <= sm.stateOutput.GetStateIndex(); i++ {
@@ -78,7 +89,7 @@ func (sm *stateManager) doSyncActionIfNeeded() {
 		approvedBlockCandidatesCount := sm.syncingBlocks.getApprovedBlockCandidatesCount(i)
 		sm.log.Debugf("doSyncAction: trying to sync state for index %v; requestBlockRetryTime %v, blockCandidates count %v, approved blockCandidates count %v",
 			i, requestBlockRetryTime, blockCandidatesCount, approvedBlockCandidatesCount)
-		// TODO: temporary if. We need to find a solution to synchronize over large gaps. Making state snapshots may help.
+		// snapshots are used to synchronize over large gaps. If no snapshot is available, the chain can't be synced
 		if i > startSyncFromIndex+maxBlocksToCommitConst {
 			sm.chain.EnqueueDismissChain(fmt.Sprintf("StateManager.doSyncActionIfNeeded: too many blocks to catch up: %v", sm.stateOutput.GetStateIndex()-startSyncFromIndex+1))
 			return
@@ -192,4 +203,21 @@ func (sm *stateManager) commitCandidates(candidates []*candidateBlock, tentative
 	sm.solidState = tentativeState
 
 	sm.log.Debugf("commitCandidates: committing of block indices from %v to %v was successful", from, to)
+	sm.storeSnapshotIfNeeded(from, to)
+	sm.pruneStateHistory(from, to)
+}
+
+// pruneStateHistory deletes the history of the state which is older than historyDepth blocks.
+// The reverse diffs of the committed blocks from..to push the same number of old ones out of the window
+func (sm *stateManager) pruneStateHistory(from, to uint32) {
+	if sm.historyDepth == 0 || to <= sm.historyDepth {
+		return
+	}
+	pruneFrom := uint32(1)
+	if from > sm.historyDepth {
+		pruneFrom = from - sm.historyDepth
+	}
+	if err := state.PruneStateHistory(sm.store, pruneFrom, to-sm.historyDepth+1); err != nil {
+		sm.log.Errorf("pruneStateHistory: %v", err)
+	}
 }
@@ -13,6 +13,10 @@ type StateManagerTimers struct {
 	// how long delay state pull after state candidate received
 	PullStateAfterStateCandidateDelay time.Duration
 	GetBlockRetry                     time.Duration
+	// period of retry of snapshot request to peers or of approving output pull for snapshot
+	GetSnapshotRetry time.Duration
+	// minimal period between snapshots sent to the same peer
+	ServeSnapshotInterval time.Duration
 }
 
 func NewStateManagerTimers() StateManagerTimers {
@@ -20,5 +24,7 @@ func NewStateManagerTimers() StateManagerTimers {
 		PullStateRetry:                    1 * time.Second,
 		PullStateAfterStateCandidateDelay: 1 * time.Second,
 		GetBlockRetry:                     3 * time.Second,
+		GetSnapshotRetry:                  5 * time.Second,
+		ServeSnapshotInterval:             30 * time.Second,
 	}
 }
@@ -1,6 +1,7 @@
 package chains
 
 import (
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/chainimpl"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
+	"github.com/iotaledger/wasp/packages/chain/statemgr"
 	"github.com/iotaledger/wasp/packages/database/dbmanager"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/metrics"
@@ -38,6 +41,11 @@ type Chains struct {
 	offledgerBroadcastUpToNPeers     int
 	offledgerBroadcastInterval       time.Duration
 	pullMissingRequestsFromCommittee bool
+	maxBatchSize                     uint16
+	vmParallelism                    int
+	snapshotConfig                   statemgr.SnapshotConfig
+	historyDepth                     uint32
+	mempoolConfig                    mempool.Config
 	networkProvider                  peering.NetworkProvider
 	getOrCreateKVStore               dbmanager.ChainKVStoreProvider
 }
@@ -48,6 +56,11 @@ func New(
 	offledgerBroadcastUpToNPeers int,
 	offledgerBroadcastInterval time.Duration,
 	pullMissingRequestsFromCommittee bool,
+	maxBatchSize uint16,
+	vmParallelism int,
+	snapshotConfig statemgr.SnapshotConfig,
+	historyDepth uint32,
+	mempoolConfig mempool.Config,
 	networkProvider peering.NetworkProvider,
 	getOrCreateKVStore dbmanager.ChainKVStoreProvider,
 ) *Chains {
@@ -58,6 +71,11 @@ func New(
 		offledgerBroadcastUpToNPeers:     offledgerBroadcastUpToNPeers,
 		offledgerBroadcastInterval:       offledgerBroadcastInterval,
 		pullMissingRequestsFromCommittee: pullMissingRequestsFromCommittee,
+		maxBatchSize:                     maxBatchSize,
+		vmParallelism:                    vmParallelism,
+		snapshotConfig:                   snapshotConfig,
+		historyDepth:                     historyDepth,
+		mempoolConfig:                    mempoolConfig,
 		networkProvider:                  networkProvider,
 		getOrCreateKVStore:               getOrCreateKVStore,
 	}
@@ -133,6 +151,11 @@ func (c *Chains) Activate(chr *registry.ChainRecord, registryProvider registry.P
 	defaultRegistry := registryProvider()
 	chainKVStore := c.getOrCreateKVStore(chr.ChainID)
 	chainMetrics := allMetrics.NewChainMetrics(chr.ChainID)
+	// each chain keeps its snapshots in its own subdirectory
+	snapshotConfig := c.snapshotConfig
+	if snapshotConfig.Dir != "" {
+		snapshotConfig.Dir = filepath.Join(snapshotConfig.Dir, chr.ChainID.Base58())
+	}
 	newChain := chainimpl.NewChain(
 		chr.ChainID,
 		c.log,
@@ -147,6 +170,11 @@ func (c *Chains) Activate(chr *registry.ChainRecord, registryProvider registry.P
 		c.offledgerBroadcastUpToNPeers,
 		c.offledgerBroadcastInterval,
 		c.pullMissingRequestsFromCommittee,
+		c.maxBatchSize,
+		c.vmParallelism,
+		snapshotConfig,
+		c.historyDepth,
+		c.mempoolConfig,
 		chainMetrics,
 	)
 	if newChain == nil {
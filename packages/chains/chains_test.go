@@ -6,6 +6,8 @@ import (
 
 	"github.com/iotaledger/goshimmer/packages/database"
 	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
+	"github.com/iotaledger/wasp/packages/chain/statemgr"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/iotaledger/wasp/packages/vm/processors"
@@ -20,5 +22,5 @@ func TestBasic(t *testing.T) {
 		return db.NewStore()
 	}
 
-	_ = New(logger, processors.NewConfig(), 10, time.Second, false, nil, getOrCreateKVStore)
+	_ = New(logger, processors.NewConfig(), 10, time.Second, false, 100, 0, statemgr.SnapshotConfig{}, 0, mempool.DefaultConfig(), nil, getOrCreateKVStore)
 }
@@ -104,3 +104,12 @@ func TestDashboardChainContract(t *testing.T) {
 	})
 	checkProperConversionsToString(t, html)
 }
+
+func TestDashboardMetricsNodeconn(t *testing.T) {
+	env := initDashboardTest(t)
+	env.newChain()
+	html := testutil.CallHTMLRequestHandler(t, env.echo, env.dashboard.handleMetricsNodeconn, "/metrics/nodeconn", nil)
+	require.Equal(t, 2, html.Find("table").First().Find("tbody tr").Length())
+	require.Contains(t, html.Text(), "127.0.0.1:5001")
+	checkProperConversionsToString(t, html)
+}
@@ -6,6 +6,7 @@ package dashboard
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
@@ -131,9 +132,26 @@ func (w *waspServicesMock) GetChainNodeConnectionMetrics(*iscp.ChainID) (nodecon
 }
 
 func (w *waspServicesMock) GetNodeConnectionMetrics() (nodeconnmetrics.NodeConnectionMetrics, error) {
-	panic("Not implemented")
+	metrics := nodeconnmetrics.New(testlogger.NewSimple(false))
+	metrics.AddEndpoint(&endpointMetricsMock{address: "127.0.0.1:5000", active: true})
+	metrics.AddEndpoint(&endpointMetricsMock{address: "127.0.0.1:5001"})
+	return metrics, nil
+}
+
+type endpointMetricsMock struct {
+	address string
+	active  bool
 }
 
+func (e *endpointMetricsMock) GetAddress() string          { return e.address }
+func (e *endpointMetricsMock) IsConnected() bool           { return true }
+func (e *endpointMetricsMock) IsHealthy() bool             { return true }
+func (e *endpointMetricsMock) IsActive() bool              { return e.active }
+func (e *endpointMetricsMock) GetConnects() uint32         { return 1 }
+func (e *endpointMetricsMock) GetMessagesSent() uint32     { return 0 }
+func (e *endpointMetricsMock) GetMessagesReceived() uint32 { return 0 }
+func (e *endpointMetricsMock) GetLastReceived() time.Time  { return time.Now() }
+
 type dashboardTestEnv struct {
 	wasp      *waspServicesMock
 	echo      *echo.Echo
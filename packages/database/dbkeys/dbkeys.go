@@ -16,6 +16,9 @@ const (
 	ObjectTypeBlobCache
 	ObjectTypeBlobCacheTTL
 	ObjectTypeTrustedPeer
+	ObjectTypeTrieNode
+	ObjectTypeStateReverseDiff
+	ObjectTypeBlocksPrunedUpTo
 )
 
 // MakeKey makes key within the partition. It consists to one byte for object type
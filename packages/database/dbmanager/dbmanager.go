@@ -13,7 +13,11 @@ import (
 	"github.com/iotaledger/hive.go/timeutil"
 	"github.com/iotaledger/wasp/packages/database/registrykvstore"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 )
 
 type ChainKVStoreProvider func(chainID *iscp.ChainID) kvstore.KVStore
@@ -136,3 +140,43 @@ func (m *DBManager) gc(db database.DB, shutdownSignal <-chan struct{}) {
 		}
 	}, gcTimeInterval, shutdownSignal)
 }
+
+// RunPruning periodically deletes the blocks of each chain which are older than
+// the pruning policy of the chain requires
+func (m *DBManager) RunPruning(shutdownSignal <-chan struct{}) {
+	interval := parameters.GetInt(parameters.DatabasePruningInterval)
+	if interval <= 0 {
+		return
+	}
+	timeutil.NewTicker(m.PruneBlocks, time.Duration(interval)*time.Second, shutdownSignal)
+}
+
+// PruneBlocks deletes old blocks of all chains according to their pruning policies
+func (m *DBManager) PruneBlocks() {
+	m.mutex.RLock()
+	stores := make(map[[ledgerstate.AddressLength]byte]kvstore.KVStore, len(m.stores))
+	for chainID, store := range m.stores {
+		stores[chainID] = store
+	}
+	m.mutex.RUnlock()
+
+	for chainID, store := range stores {
+		if err := pruneBlocks(store); err != nil {
+			chid, _ := iscp.ChainIDFromBytes(chainID[:])
+			m.log.Warnf("Pruning of blocks of %s failed: %s", getChainBase58(chid), err)
+		}
+	}
+}
+
+func pruneBlocks(store kvstore.KVStore) error {
+	vs, exists, err := state.LoadSolidState(store, nil)
+	if err != nil || !exists {
+		return err
+	}
+	policy := governance.GetPruningPolicy(subrealm.NewReadOnly(vs.KVStoreReader(), kv.Key(governance.Contract.Hname().Bytes())))
+	if policy.BlocksToKeep == 0 || vs.BlockIndex() < policy.BlocksToKeep {
+		return nil
+	}
+	_, err = state.PruneBlocks(store, vs.BlockIndex()-policy.BlocksToKeep+1)
+	return err
+}
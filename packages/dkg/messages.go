@@ -56,6 +56,13 @@ const (
 	// in response to duplicated messages from other peers. They should be treated
 	// in a special way to avoid infinite message loops.
 	rabinEcho byte = peering.FirstUserMsgCode + 44
+	//
+	// Initiator <-> Peer communication for the resharing of an existing key.
+	// The init message goes to the node, as the initiatorInitMsgType, so it must be unique as well.
+	reshareInitMsgType   byte = peering.FirstUserMsgCode + 185 // Initiator -> Peer: init new resharing, reply with initiatorStatusMsgType.
+	reshareMsgBase       byte = peering.FirstUserMsgCode + 54
+	reshareDealMsgType   byte = reshareMsgBase + 1 // Peer -> Initiator: a deal of the old member, response to initiatorStepMsgType.
+	reshareSharesMsgType byte = reshareMsgBase + 2 // Initiator -> Peer: the deals for the new member, reply with initiatorPubShareMsgType.
 )
 
 var initPeeringID peering.PeeringID
@@ -168,6 +175,24 @@ func readInitiatorMsg(peerMessage *peering.PeerMessageData, blsSuite kyber.Group
 			return true, nil, err
 		}
 		return true, &msg, nil
+	case reshareInitMsgType:
+		msg := reshareInitMsg{}
+		if err := msg.fromBytes(peerMessage.MsgData); err != nil {
+			return true, nil, err
+		}
+		return true, &msg, nil
+	case reshareDealMsgType:
+		msg := reshareDealMsg{}
+		if err := msg.fromBytes(peerMessage.MsgData, blsSuite); err != nil {
+			return true, nil, err
+		}
+		return true, &msg, nil
+	case reshareSharesMsgType:
+		msg := reshareSharesMsg{}
+		if err := msg.fromBytes(peerMessage.MsgData, blsSuite); err != nil {
+			return true, nil, err
+		}
+		return true, &msg, nil
 	default:
 		return false, nil, nil
 	}
@@ -582,6 +607,375 @@ func (m *initiatorStatusMsg) IsResponse() bool {
 	return true
 }
 
+//
+// reshareInitMsg
+//
+// This is a message sent by the initiator to all the members of the old
+// and the new committee to initiate the resharing of an existing key.
+//
+type reshareInitMsg struct {
+	step          byte
+	dkgRef        string // Some unique string to identify duplicate initialization.
+	peeringID     peering.PeeringID
+	sharedAddress ledgerstate.Address
+	oldNetIDs     []string
+	newNetIDs     []string
+	newPeerPubs   []ed25519.PublicKey
+	initiatorPub  ed25519.PublicKey
+	threshold     uint16
+	timeout       time.Duration
+}
+
+type reshareInitMsgIn struct {
+	reshareInitMsg
+	SenderNetID string
+}
+
+func (m *reshareInitMsg) MsgType() byte {
+	return reshareInitMsgType
+}
+
+func (m *reshareInitMsg) Step() byte {
+	return m.step
+}
+
+func (m *reshareInitMsg) SetStep(step byte) {
+	m.step = step
+}
+
+//nolint:gocritic
+func (m *reshareInitMsg) Write(w io.Writer) error {
+	var err error
+	if err = util.WriteByte(w, m.step); err != nil {
+		return err
+	}
+	if err = util.WriteString16(w, m.dkgRef); err != nil {
+		return err
+	}
+	if _, err = w.Write(m.peeringID[:]); err != nil {
+		return err
+	}
+	if err = util.WriteBytes16(w, m.sharedAddress.Bytes()); err != nil {
+		return err
+	}
+	if err = util.WriteStrings16(w, m.oldNetIDs); err != nil {
+		return err
+	}
+	if err = util.WriteStrings16(w, m.newNetIDs); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, uint16(len(m.newPeerPubs))); err != nil {
+		return err
+	}
+	for i := range m.newPeerPubs {
+		if err = util.WriteBytes16(w, m.newPeerPubs[i].Bytes()); err != nil {
+			return err
+		}
+	}
+	if err = util.WriteBytes16(w, m.initiatorPub.Bytes()); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, m.threshold); err != nil {
+		return err
+	}
+	return util.WriteInt64(w, m.timeout.Milliseconds())
+}
+
+//nolint:gocritic
+func (m *reshareInitMsg) Read(r io.Reader) error {
+	var err error
+	var n int
+	if m.step, err = util.ReadByte(r); err != nil {
+		return err
+	}
+	if m.dkgRef, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	if n, err = r.Read(m.peeringID[:]); err != nil {
+		return err
+	}
+	if n != ledgerstate.AddressLength {
+		return fmt.Errorf("error while reading peering ID: read %v bytes, expected %v bytes",
+			n, ledgerstate.AddressLength)
+	}
+	var sharedAddressBin []byte
+	if sharedAddressBin, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	if m.sharedAddress, _, err = ledgerstate.AddressFromBytes(sharedAddressBin); err != nil {
+		return err
+	}
+	if m.oldNetIDs, err = util.ReadStrings16(r); err != nil {
+		return err
+	}
+	if m.newNetIDs, err = util.ReadStrings16(r); err != nil {
+		return err
+	}
+	var arrLen uint16
+	if err = util.ReadUint16(r, &arrLen); err != nil {
+		return err
+	}
+	m.newPeerPubs = make([]ed25519.PublicKey, arrLen)
+	for i := range m.newPeerPubs {
+		var peerPubBytes []byte
+		if peerPubBytes, err = util.ReadBytes16(r); err != nil {
+			return err
+		}
+		if m.newPeerPubs[i], _, err = ed25519.PublicKeyFromBytes(peerPubBytes); err != nil {
+			return err
+		}
+	}
+	var initiatorPubBytes []byte
+	if initiatorPubBytes, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	if m.initiatorPub, _, err = ed25519.PublicKeyFromBytes(initiatorPubBytes); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &m.threshold); err != nil {
+		return err
+	}
+	var timeoutMS int64
+	if err = util.ReadInt64(r, &timeoutMS); err != nil {
+		return err
+	}
+	m.timeout = time.Duration(timeoutMS) * time.Millisecond
+	return nil
+}
+
+func (m *reshareInitMsg) fromBytes(buf []byte) error {
+	r := bytes.NewReader(buf)
+	return m.Read(r)
+}
+
+func (m *reshareInitMsg) Error() error {
+	return nil
+}
+
+func (m *reshareInitMsg) IsResponse() bool {
+	return false
+}
+
+//
+// reshareDealMsg
+//
+// This is a message responded to the initiator by the members of the old committee.
+// It carries the public part of the existing key share of the member, and the deal
+// of its private share to the new committee. The share for each new member is
+// encrypted with the public key of that member, so the initiator can relay them.
+//
+type reshareDealMsg struct {
+	step         byte
+	index        uint16 // Index of the member in the old committee.
+	n            uint16
+	t            uint16
+	sharedPublic kyber.Point
+	publicShares []kyber.Point
+	commits      []kyber.Point
+	encShares    [][]byte
+	blsSuite     kyber.Group // Transient, for un-marshaling only.
+}
+
+func (m *reshareDealMsg) MsgType() byte {
+	return reshareDealMsgType
+}
+
+func (m *reshareDealMsg) Step() byte {
+	return m.step
+}
+
+func (m *reshareDealMsg) SetStep(step byte) {
+	m.step = step
+}
+
+//nolint:gocritic
+func (m *reshareDealMsg) Write(w io.Writer) error {
+	var err error
+	if err = util.WriteByte(w, m.step); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, m.index); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, m.n); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, m.t); err != nil {
+		return err
+	}
+	if err = util.WriteMarshaled(w, m.sharedPublic); err != nil {
+		return err
+	}
+	if err = writePoints(w, m.publicShares); err != nil {
+		return err
+	}
+	if err = writePoints(w, m.commits); err != nil {
+		return err
+	}
+	return writeBytesArray(w, m.encShares)
+}
+
+//nolint:gocritic
+func (m *reshareDealMsg) Read(r io.Reader) error {
+	var err error
+	if m.step, err = util.ReadByte(r); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &m.index); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &m.n); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &m.t); err != nil {
+		return err
+	}
+	m.sharedPublic = m.blsSuite.Point()
+	if err = util.ReadMarshaled(r, m.sharedPublic); err != nil {
+		return xerrors.Errorf("failed to unmarshal reshareDealMsg.sharedPublic: %w", err)
+	}
+	if m.publicShares, err = readPoints(r, m.blsSuite); err != nil {
+		return xerrors.Errorf("failed to unmarshal reshareDealMsg.publicShares: %w", err)
+	}
+	if m.commits, err = readPoints(r, m.blsSuite); err != nil {
+		return xerrors.Errorf("failed to unmarshal reshareDealMsg.commits: %w", err)
+	}
+	if m.encShares, err = readBytesArray(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *reshareDealMsg) fromBytes(buf []byte, blsSuite kyber.Group) error {
+	r := bytes.NewReader(buf)
+	m.blsSuite = blsSuite
+	return m.Read(r)
+}
+
+func (m *reshareDealMsg) Error() error {
+	return nil
+}
+
+func (m *reshareDealMsg) IsResponse() bool {
+	return true
+}
+
+//
+// reshareSharesMsg
+//
+// This is a message sent by the initiator to each member of the new committee.
+// It carries the public part of the existing key and the deals of the old members,
+// with the shares encrypted for the receiving member.
+//
+type reshareSharesMsg struct {
+	step         byte
+	n            uint16
+	t            uint16
+	sharedPublic kyber.Point
+	publicShares []kyber.Point
+	dealers      []uint16
+	commits      [][]kyber.Point
+	encShares    [][]byte
+	blsSuite     kyber.Group // Transient, for un-marshaling only.
+}
+
+func (m *reshareSharesMsg) MsgType() byte {
+	return reshareSharesMsgType
+}
+
+func (m *reshareSharesMsg) Step() byte {
+	return m.step
+}
+
+func (m *reshareSharesMsg) SetStep(step byte) {
+	m.step = step
+}
+
+//nolint:gocritic
+func (m *reshareSharesMsg) Write(w io.Writer) error {
+	var err error
+	if err = util.WriteByte(w, m.step); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, m.n); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, m.t); err != nil {
+		return err
+	}
+	if err = util.WriteMarshaled(w, m.sharedPublic); err != nil {
+		return err
+	}
+	if err = writePoints(w, m.publicShares); err != nil {
+		return err
+	}
+	if err = util.WriteUint16(w, uint16(len(m.dealers))); err != nil {
+		return err
+	}
+	for i := range m.dealers {
+		if err = util.WriteUint16(w, m.dealers[i]); err != nil {
+			return err
+		}
+		if err = writePoints(w, m.commits[i]); err != nil {
+			return err
+		}
+	}
+	return writeBytesArray(w, m.encShares)
+}
+
+//nolint:gocritic
+func (m *reshareSharesMsg) Read(r io.Reader) error {
+	var err error
+	if m.step, err = util.ReadByte(r); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &m.n); err != nil {
+		return err
+	}
+	if err = util.ReadUint16(r, &m.t); err != nil {
+		return err
+	}
+	m.sharedPublic = m.blsSuite.Point()
+	if err = util.ReadMarshaled(r, m.sharedPublic); err != nil {
+		return xerrors.Errorf("failed to unmarshal reshareSharesMsg.sharedPublic: %w", err)
+	}
+	if m.publicShares, err = readPoints(r, m.blsSuite); err != nil {
+		return xerrors.Errorf("failed to unmarshal reshareSharesMsg.publicShares: %w", err)
+	}
+	var arrLen uint16
+	if err = util.ReadUint16(r, &arrLen); err != nil {
+		return err
+	}
+	m.dealers = make([]uint16, arrLen)
+	m.commits = make([][]kyber.Point, arrLen)
+	for i := range m.dealers {
+		if err = util.ReadUint16(r, &m.dealers[i]); err != nil {
+			return err
+		}
+		if m.commits[i], err = readPoints(r, m.blsSuite); err != nil {
+			return xerrors.Errorf("failed to unmarshal reshareSharesMsg.commits: %w", err)
+		}
+	}
+	if m.encShares, err = readBytesArray(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *reshareSharesMsg) fromBytes(buf []byte, blsSuite kyber.Group) error {
+	r := bytes.NewReader(buf)
+	m.blsSuite = blsSuite
+	return m.Read(r)
+}
+
+func (m *reshareSharesMsg) Error() error {
+	return nil
+}
+
+func (m *reshareSharesMsg) IsResponse() bool {
+	return false
+}
+
 //
 //	rabin_dkg.Deal
 //
@@ -1204,3 +1598,57 @@ func readVssDeal(r io.Reader, d **rabin_vss.Deal, blsSuite kyber.Group) error {
 	*d = &dd
 	return nil
 }
+
+func writePoints(w io.Writer, points []kyber.Point) error {
+	if err := util.WriteUint16(w, uint16(len(points))); err != nil {
+		return err
+	}
+	for i := range points {
+		if err := util.WriteMarshaled(w, points[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPoints(r io.Reader, blsSuite kyber.Group) ([]kyber.Point, error) {
+	var arrLen uint16
+	if err := util.ReadUint16(r, &arrLen); err != nil {
+		return nil, err
+	}
+	points := make([]kyber.Point, arrLen)
+	for i := range points {
+		points[i] = blsSuite.Point()
+		if err := util.ReadMarshaled(r, points[i]); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func writeBytesArray(w io.Writer, arr [][]byte) error {
+	if err := util.WriteUint16(w, uint16(len(arr))); err != nil {
+		return err
+	}
+	for i := range arr {
+		if err := util.WriteBytes16(w, arr[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readBytesArray(r io.Reader) ([][]byte, error) {
+	var arrLen uint16
+	if err := util.ReadUint16(r, &arrLen); err != nil {
+		return nil, err
+	}
+	arr := make([][]byte, arrLen)
+	for i := range arr {
+		var err error
+		if arr[i], err = util.ReadBytes16(r); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
@@ -35,8 +35,10 @@ type Node struct {
 	netProvider  peering.NetworkProvider          // Network to communicate through.
 	registry     registry.DKShareRegistryProvider // Where to store the generated keys.
 	processes    map[string]*proc                 // Only for introspection.
+	reshares     map[string]*reshareProc          // Resharing processes, by dkgRef.
 	procLock     *sync.RWMutex                    // To guard access to the process pool.
 	initMsgQueue chan *initiatorInitMsgIn         // Incoming events processed async.
+	reshareQueue chan *reshareInitMsgIn           // Incoming resharing events processed async.
 	attachID     interface{}                      // Peering attach ID
 	log          *logger.Logger
 }
@@ -62,12 +64,15 @@ func NewNode(
 		netProvider:  netProvider,
 		registry:     reg,
 		processes:    make(map[string]*proc),
+		reshares:     make(map[string]*reshareProc),
 		procLock:     &sync.RWMutex{},
 		initMsgQueue: make(chan *initiatorInitMsgIn),
+		reshareQueue: make(chan *reshareInitMsgIn),
 		log:          log,
 	}
 	n.attachID = netProvider.Attach(&initPeeringID, peering.PeerMessageReceiverDkgInit, n.receiveInitMessage)
 	go n.recvLoop()
+	go n.reshareRecvLoop()
 	return &n, nil
 }
 
@@ -76,6 +81,10 @@ func (n *Node) receiveInitMessage(peerMsg *peering.PeerMessageIn) {
 		panic(fmt.Errorf("DKG init handler does not accept peer messages of other receiver type %v, message type=%v",
 			peerMsg.MsgReceiver, peerMsg.MsgType))
 	}
+	if peerMsg.MsgType == reshareInitMsgType {
+		n.receiveReshareInitMessage(peerMsg)
+		return
+	}
 	if peerMsg.MsgType != initiatorInitMsgType {
 		panic(fmt.Errorf("Wrong type of DKG init message: %v", peerMsg.MsgType))
 	}
@@ -92,6 +101,7 @@ func (n *Node) receiveInitMessage(peerMsg *peering.PeerMessageIn) {
 
 func (n *Node) Close() {
 	close(n.initMsgQueue)
+	close(n.reshareQueue)
 	n.netProvider.Detach(n.attachID)
 }
 
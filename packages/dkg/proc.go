@@ -626,20 +626,25 @@ func (p *proc) nodeInQUAL(nodeIdx uint16) bool {
 }
 
 func (p *proc) makeInitiatorPubShareMsg(step byte) (*initiatorPubShareMsg, error) {
+	return newInitiatorPubShareMsg(p.node.blsSuite, p.dkShare, step)
+}
+
+// newInitiatorPubShareMsg makes the public share of the node, signed with its private share.
+func newInitiatorPubShareMsg(blsSuite Suite, dkShare *tcrypto.DKShare, step byte) (*initiatorPubShareMsg, error) {
 	var err error
 	var publicShareBytes []byte
-	if publicShareBytes, err = p.dkShare.PublicShares[*p.dkShare.Index].MarshalBinary(); err != nil {
+	if publicShareBytes, err = dkShare.PublicShares[*dkShare.Index].MarshalBinary(); err != nil {
 		return nil, err
 	}
 	var signature []byte
-	if signature, err = bdn.Sign(p.node.blsSuite, p.dkShare.PrivateShare, publicShareBytes); err != nil {
+	if signature, err = bdn.Sign(blsSuite, dkShare.PrivateShare, publicShareBytes); err != nil {
 		return nil, err
 	}
 	return &initiatorPubShareMsg{
 		step:          step,
-		sharedAddress: p.dkShare.Address,
-		sharedPublic:  p.dkShare.SharedPublic,
-		publicShare:   p.dkShare.PublicShares[*p.dkShare.Index],
+		sharedAddress: dkShare.Address,
+		sharedPublic:  dkShare.SharedPublic,
+		publicShare:   dkShare.PublicShares[*dkShare.Index],
 		signature:     signature,
 	}, nil
 }
@@ -0,0 +1,650 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package dkg
+
+//
+// This file contains the resharing of an existing distributed key to a new committee.
+//
+// The members of the old committee deal their private shares to the members of
+// the new committee with the new threshold, see tcrypto.ReshareDeal. The new members
+// combine the deals into the shares of the same key, so the shared address stays the same.
+// All the messages are relayed by the initiator, the shares are encrypted for
+// the receiving member with its public key. When the new committee has committed its
+// shares, the members of the old committee, which are not in the new one, delete theirs.
+//
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+	"go.dedis.ch/kyber/v3/sign/bdn"
+)
+
+const (
+	reshareStep0Initialize = byte(0)
+	reshareStep1Deal       = byte(1)
+	reshareStep2Combine    = byte(2)
+	reshareStep3Commit     = byte(3)
+	reshareStep4Drop       = byte(4)
+)
+
+// ReshareDistributedKey redistributes the existing distributed key to the new committee with the new threshold.
+// The key, and therefore the shared address, stays the same. The oldNetIDs are the members of the current
+// committee taking part in the resharing, at least T of them are needed. The nodes of oldNetIDs, which are
+// not in the new committee, delete their old shares as the last step, after the new committee has stored
+// its shares. The old shares stay valid until then: any T of them still sign for the address. The same
+// holds for the members of the old committee, which did not take part in the resharing, they keep their
+// shares until those are deleted by the operators. This function is executed on the initiator node, which must be a member of the old or the new committee.
+//
+//nolint:funlen,gocritic
+func (n *Node) ReshareDistributedKey(
+	sharedAddress ledgerstate.Address,
+	oldNetIDs []string,
+	newNetIDs []string,
+	newPeerPubs []ed25519.PublicKey,
+	threshold uint16,
+	stepRetry time.Duration, // Retry for Initiator -> Peer communication.
+	timeout time.Duration, // Timeout for the entire procedure.
+) (*tcrypto.DKShare, error) {
+	n.log.Infof("Starting new DKG resharing of %v, initiator=%v, old peers=%+v, new peers=%+v",
+		sharedAddress.Base58(), n.netProvider.Self().NetID(), oldNetIDs, newNetIDs)
+	var err error
+	peerCount := uint16(len(newNetIDs))
+	//
+	// Some validation for the parameters.
+	if len(oldNetIDs) < 1 {
+		return nil, invalidParams(errors.New("wrong resharing parameters: no members of the old committee"))
+	}
+	if peerCount < 1 || threshold < 1 || threshold > peerCount {
+		return nil, invalidParams(fmt.Errorf("wrong DKG parameters: N = %d, T = %d", peerCount, threshold))
+	}
+	if threshold < peerCount/2+1 {
+		// Quorum t must be larger than half size in order to avoid more than one valid quorum in committee.
+		return nil, invalidParams(fmt.Errorf("wrong DKG parameters: for N = %d value T must be at least %d", peerCount, peerCount/2+1))
+	}
+	if newPeerPubs != nil && len(newPeerPubs) != len(newNetIDs) {
+		return nil, invalidParams(fmt.Errorf("wrong resharing parameters: %d public keys for %d peers", len(newPeerPubs), len(newNetIDs)))
+	}
+	allNetIDs, err := reshareGroupNetIDs(oldNetIDs, newNetIDs)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+	//
+	// Setup network connections.
+	dkgID := peering.RandomPeeringID()
+	var netGroup peering.GroupProvider
+	if netGroup, err = n.netProvider.PeerGroup(dkgID, allNetIDs); err != nil {
+		return nil, err
+	}
+	defer netGroup.Close()
+	recvCh := make(chan *peering.PeerMessageIn, len(allNetIDs)*2)
+	attachID := n.netProvider.Attach(&dkgID, peering.PeerMessageReceiverDkg, func(recv *peering.PeerMessageIn) {
+		recvCh <- recv
+	})
+	defer n.netProvider.Detach(attachID)
+	rTimeout := stepRetry
+	gTimeout := timeout
+	allPeers := netGroup.AllNodes()
+	oldPeers := make(map[uint16]peering.PeerSender, len(oldNetIDs))
+	for i := range oldNetIDs {
+		oldPeers[uint16(i)] = allPeers[uint16(i)]
+	}
+	newPeers := make(map[uint16]peering.PeerSender, len(newNetIDs))
+	newIndexes := make(map[uint16]uint16, len(newNetIDs)) // Group index -> index in the new committee.
+	for i := range newNetIDs {
+		var peerIdx uint16
+		if peerIdx, err = netGroup.PeerIndexByNetID(newNetIDs[i]); err != nil {
+			return nil, err
+		}
+		newPeers[peerIdx] = allPeers[peerIdx]
+		newIndexes[peerIdx] = uint16(i)
+	}
+	if newPeerPubs == nil {
+		// Take the public keys from the peering network, if they were not specified.
+		newPeerPubs = make([]ed25519.PublicKey, peerCount)
+		for peerIdx, peer := range newPeers {
+			if err = peer.Await(timeout); err != nil {
+				return nil, err
+			}
+			peerPub := peer.PubKey()
+			if peerPub == nil {
+				return nil, fmt.Errorf("Have no public key for %v", peer.NetID())
+			}
+			newPeerPubs[newIndexes[peerIdx]] = *peerPub
+		}
+	}
+	//
+	// Initialize the peers.
+	if err = n.exchangeInitiatorAcks(netGroup, allPeers, recvCh, rTimeout, gTimeout, reshareStep0Initialize,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep0Initialize, peer.NetID())
+			peer.SendMsg(makePeerMessage(initPeeringID, peering.PeerMessageReceiverDkgInit, reshareStep0Initialize, &reshareInitMsg{
+				dkgRef:        dkgID.String(),
+				peeringID:     dkgID,
+				sharedAddress: sharedAddress,
+				oldNetIDs:     oldNetIDs,
+				newNetIDs:     newNetIDs,
+				newPeerPubs:   newPeerPubs,
+				initiatorPub:  n.identity.PublicKey,
+				threshold:     threshold,
+				timeout:       timeout,
+			}))
+		},
+	); err != nil {
+		return nil, err
+	}
+	//
+	// Collect the deals from the old committee.
+	deals := make(map[uint16]*reshareDealMsg, len(oldPeers))
+	if err = n.exchangeInitiatorMsgs(netGroup, oldPeers, recvCh, rTimeout, gTimeout, reshareStep1Deal,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep1Deal, peer.NetID())
+			peer.SendMsg(makePeerMessage(dkgID, peering.PeerMessageReceiverDkg, reshareStep1Deal, &initiatorStepMsg{}))
+		},
+		func(recv *peering.PeerMessageGroupIn, initMsg initiatorMsg) (bool, error) {
+			switch msg := initMsg.(type) {
+			case *reshareDealMsg:
+				deals[recv.SenderIndex] = msg
+				return true, nil
+			default:
+				n.log.Errorf("unexpected message type instead of reshareDealMsg: %V", msg)
+				return false, errors.New("unexpected message type instead of reshareDealMsg")
+			}
+		},
+	); err != nil {
+		return nil, err
+	}
+	oldShare, dealers, err := n.checkReshareDeals(sharedAddress, deals, peerCount, threshold)
+	if err != nil {
+		return nil, err
+	}
+	//
+	// Pass the deals to the new committee and get the new public shares.
+	pubShareResponses := make(map[uint16]*initiatorPubShareMsg, len(newPeers))
+	if err = n.exchangeInitiatorMsgs(netGroup, newPeers, recvCh, rTimeout, gTimeout, reshareStep2Combine,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep2Combine, peer.NetID())
+			sharesMsg := &reshareSharesMsg{
+				n:            oldShare.N,
+				t:            oldShare.T,
+				sharedPublic: oldShare.SharedPublic,
+				publicShares: oldShare.PublicShares,
+				dealers:      make([]uint16, len(dealers)),
+				commits:      make([][]kyber.Point, len(dealers)),
+				encShares:    make([][]byte, len(dealers)),
+			}
+			for i, deal := range dealers {
+				sharesMsg.dealers[i] = deal.index
+				sharesMsg.commits[i] = deal.commits
+				sharesMsg.encShares[i] = deal.encShares[newIndexes[peerIdx]]
+			}
+			peer.SendMsg(makePeerMessage(dkgID, peering.PeerMessageReceiverDkg, reshareStep2Combine, sharesMsg))
+		},
+		func(recv *peering.PeerMessageGroupIn, initMsg initiatorMsg) (bool, error) {
+			switch msg := initMsg.(type) {
+			case *initiatorPubShareMsg:
+				pubShareResponses[newIndexes[recv.SenderIndex]] = msg
+				return true, nil
+			default:
+				n.log.Errorf("unexpected message type instead of initiatorPubShareMsg: %V", msg)
+				return false, errors.New("unexpected message type instead of initiatorPubShareMsg")
+			}
+		},
+	); err != nil {
+		return nil, err
+	}
+	publicShares := make([]kyber.Point, peerCount)
+	for i := range pubShareResponses {
+		if !sharedAddress.Equals(pubShareResponses[i].sharedAddress) {
+			return nil, fmt.Errorf("nodes reshared to a different address")
+		}
+		if !oldShare.SharedPublic.Equal(pubShareResponses[i].sharedPublic) {
+			return nil, fmt.Errorf("nodes reshared to a different shared public key")
+		}
+		publicShares[i] = pubShareResponses[i].publicShare
+		var pubShareBytes []byte
+		if pubShareBytes, err = pubShareResponses[i].publicShare.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		if err = bdn.Verify(n.blsSuite, pubShareResponses[i].publicShare, pubShareBytes, pubShareResponses[i].signature); err != nil {
+			return nil, err
+		}
+	}
+	n.log.Debugf("Reshared SharedAddress=%v to %v", sharedAddress, newNetIDs)
+	//
+	// Commit the keys to persistent storage.
+	if err = n.exchangeInitiatorAcks(netGroup, newPeers, recvCh, rTimeout, gTimeout, reshareStep3Commit,
+		func(peerIdx uint16, peer peering.PeerSender) {
+			n.log.Debugf("Initiator sends step=%v command to %v", reshareStep3Commit, peer.NetID())
+			peer.SendMsg(makePeerMessage(dkgID, peering.PeerMessageReceiverDkg, reshareStep3Commit, &initiatorDoneMsg{
+				pubShares: publicShares,
+			}))
+		},
+	); err != nil {
+		return nil, err
+	}
+	//
+	// Drop the shares of the old committee members, which are not in the new one.
+	droppedPeers := make(map[uint16]peering.PeerSender, len(oldPeers))
+	for peerIdx, peer := range oldPeers {
+		if _, ok := newPeers[peerIdx]; !ok {
+			droppedPeers[peerIdx] = peer
+		}
+	}
+	if len(droppedPeers) > 0 {
+		if err = n.exchangeInitiatorStep(netGroup, droppedPeers, recvCh, rTimeout, gTimeout, dkgID, reshareStep4Drop); err != nil {
+			return nil, fmt.Errorf("the key is reshared, but the old shares are not deleted: %w", err)
+		}
+	}
+	dkShare := tcrypto.DKShare{
+		Address:       sharedAddress,
+		N:             peerCount,
+		T:             threshold,
+		Index:         nil, // Not meaningful in this case.
+		SharedPublic:  oldShare.SharedPublic,
+		PublicCommits: nil, // Not meaningful in this case.
+		PublicShares:  publicShares,
+		PrivateShare:  nil, // Not meaningful in this case.
+	}
+	return &dkShare, nil
+}
+
+// checkReshareDeals checks, if all the members of the old committee hold the shares of the
+// same key and their deals correspond to their shares. Returns the public part of the old
+// key and the deals, ordered by the index of the dealer in the old committee.
+func (n *Node) checkReshareDeals(
+	sharedAddress ledgerstate.Address,
+	deals map[uint16]*reshareDealMsg,
+	peerCount, threshold uint16,
+) (*tcrypto.DKShare, []*reshareDealMsg, error) {
+	var err error
+	dealers := make([]*reshareDealMsg, 0, len(deals))
+	for _, deal := range deals {
+		dealers = append(dealers, deal)
+	}
+	sort.Slice(dealers, func(i, j int) bool { return dealers[i].index < dealers[j].index })
+	first := dealers[0]
+	var publicSharesBytes []byte
+	if publicSharesBytes, err = pointsToBytes(first.publicShares); err != nil {
+		return nil, nil, err
+	}
+	for i, deal := range dealers {
+		if i > 0 && deal.index == dealers[i-1].index {
+			return nil, nil, fmt.Errorf("several members of the old committee hold the share %d", deal.index)
+		}
+		if deal.n != first.n || deal.t != first.t || !deal.sharedPublic.Equal(first.sharedPublic) {
+			return nil, nil, fmt.Errorf("members of the old committee hold different keys")
+		}
+		var dealPublicSharesBytes []byte
+		if dealPublicSharesBytes, err = pointsToBytes(deal.publicShares); err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(publicSharesBytes, dealPublicSharesBytes) {
+			return nil, nil, fmt.Errorf("members of the old committee hold different public shares")
+		}
+		if int(deal.index) >= len(deal.publicShares) || len(deal.commits) != int(threshold) || len(deal.encShares) != int(peerCount) {
+			return nil, nil, fmt.Errorf("malformed deal from the member %d of the old committee", deal.index)
+		}
+		if !deal.commits[0].Equal(deal.publicShares[deal.index]) {
+			return nil, nil, fmt.Errorf("the deal of the member %d does not correspond to its public share", deal.index)
+		}
+	}
+	if len(dealers) < int(first.t) {
+		return nil, nil, invalidParams(fmt.Errorf("%d members of the old committee are needed, %d given", first.t, len(dealers)))
+	}
+	var sharedPublicBytes []byte
+	if sharedPublicBytes, err = first.sharedPublic.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	if !ledgerstate.NewBLSAddress(sharedPublicBytes).Equals(sharedAddress) {
+		return nil, nil, fmt.Errorf("members of the old committee hold the key of another address")
+	}
+	oldShare := &tcrypto.DKShare{
+		Address:      sharedAddress,
+		N:            first.n,
+		T:            first.t,
+		SharedPublic: first.sharedPublic,
+		PublicShares: first.publicShares,
+	}
+	return oldShare, dealers, nil
+}
+
+// reshareGroupNetIDs returns the members of both committees, the old ones first.
+func reshareGroupNetIDs(oldNetIDs, newNetIDs []string) ([]string, error) {
+	ret := make([]string, 0, len(oldNetIDs)+len(newNetIDs))
+	seen := make(map[string]bool, len(oldNetIDs)+len(newNetIDs))
+	for _, netID := range oldNetIDs {
+		if seen[netID] {
+			return nil, fmt.Errorf("duplicate peer %v in the old committee", netID)
+		}
+		seen[netID] = true
+		ret = append(ret, netID)
+	}
+	newSeen := make(map[string]bool, len(newNetIDs))
+	for _, netID := range newNetIDs {
+		if newSeen[netID] {
+			return nil, fmt.Errorf("duplicate peer %v in the new committee", netID)
+		}
+		newSeen[netID] = true
+		if !seen[netID] {
+			ret = append(ret, netID)
+		}
+	}
+	return ret, nil
+}
+
+func pointsToBytes(points []kyber.Point) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writePoints(&buf, points); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *Node) receiveReshareInitMessage(peerMsg *peering.PeerMessageIn) {
+	msg := &reshareInitMsg{}
+	if err := msg.fromBytes(peerMsg.MsgData); err != nil {
+		n.log.Warnf("Dropping unknown message: %v", peerMsg)
+		return
+	}
+	n.reshareQueue <- &reshareInitMsgIn{
+		reshareInitMsg: *msg,
+		SenderNetID:    peerMsg.SenderNetID,
+	}
+}
+
+// Async recv is needed to avoid locking on the even publisher (Recv vs Attach in reshareProc).
+func (n *Node) reshareRecvLoop() {
+	for recv := range n.reshareQueue {
+		n.onReshareInitMsg(recv)
+	}
+}
+
+// onReshareInitMsg is a callback to handle the resharing initialization messages.
+func (n *Node) onReshareInitMsg(msg *reshareInitMsgIn) {
+	var err error
+	var p *reshareProc
+	n.procLock.RLock()
+	if _, ok := n.reshares[msg.dkgRef]; ok {
+		// Duplicate messages are considered as success, if process is already created.
+		n.procLock.RUnlock()
+		n.netProvider.SendMsgByNetID(msg.SenderNetID, makePeerMessage(msg.peeringID, peering.PeerMessageReceiverDkg, msg.step, &initiatorStatusMsg{
+			error: nil,
+		}))
+		return
+	}
+	n.procLock.RUnlock()
+	go func() {
+		n.procLock.Lock()
+		if p, err = onReshareInit(msg.peeringID, &msg.reshareInitMsg, n); err == nil {
+			n.reshares[p.dkgRef] = p
+		}
+		n.procLock.Unlock()
+		n.netProvider.SendMsgByNetID(msg.SenderNetID, makePeerMessage(msg.peeringID, peering.PeerMessageReceiverDkg, msg.step, &initiatorStatusMsg{
+			error: err,
+		}))
+	}()
+}
+
+// Called by the resharing process on termination.
+func (n *Node) dropReshareProcess(p *reshareProc) bool {
+	n.procLock.Lock()
+	defer n.procLock.Unlock()
+	if found := n.reshares[p.dkgRef]; found != nil {
+		delete(n.reshares, p.dkgRef)
+		return true
+	}
+	return false
+}
+
+// Stands for a resharing procedure instance on a particular node.
+// Each step is a request from the initiator, which is answered once,
+// the repeated requests are answered with the same response.
+type reshareProc struct {
+	dkgRef        string            // User supplied unique ID for this instance.
+	dkgID         peering.PeeringID // Resharing procedure ID we are participating in.
+	node          *Node             // DKG node we are running in.
+	sharedAddress ledgerstate.Address
+	oldShare      *tcrypto.DKShare    // Existing share, nil if this node is not in the old committee.
+	newIndex      *uint16             // Index in the new committee, nil if this node is not in it.
+	newNetIDs     []string            // Members of the new committee.
+	newPeerPubs   []ed25519.PublicKey // Public keys of the new committee to encrypt the deals to.
+	threshold     uint16              // Threshold of the new committee.
+	dkShare       *tcrypto.DKShare    // This will be produced as a result of this procedure.
+	netGroup      peering.GroupProvider
+	responses     map[byte]*peering.PeerMessageData // Responses to the initiator, by step.
+	peerMsgCh     chan *peering.PeerMessageGroupIn  // A buffer for the received peer messages.
+	log           *logger.Logger
+}
+
+func onReshareInit(dkgID peering.PeeringID, msg *reshareInitMsg, node *Node) (*reshareProc, error) {
+	var err error
+	if len(msg.newPeerPubs) != len(msg.newNetIDs) {
+		return nil, fmt.Errorf("%d public keys for %d peers of the new committee", len(msg.newPeerPubs), len(msg.newNetIDs))
+	}
+	var allNetIDs []string
+	if allNetIDs, err = reshareGroupNetIDs(msg.oldNetIDs, msg.newNetIDs); err != nil {
+		return nil, err
+	}
+	var netGroup peering.GroupProvider
+	if netGroup, err = node.netProvider.PeerGroup(dkgID, allNetIDs); err != nil {
+		return nil, err
+	}
+	p := reshareProc{
+		dkgRef:        msg.dkgRef,
+		dkgID:         dkgID,
+		node:          node,
+		sharedAddress: msg.sharedAddress,
+		newNetIDs:     msg.newNetIDs,
+		newPeerPubs:   msg.newPeerPubs,
+		threshold:     msg.threshold,
+		netGroup:      netGroup,
+		responses:     make(map[byte]*peering.PeerMessageData),
+		peerMsgCh:     make(chan *peering.PeerMessageGroupIn, len(allNetIDs)),
+		log:           node.log.With("dkgID", dkgID.String()),
+	}
+	myNetID := node.netProvider.Self().NetID()
+	for i := range msg.oldNetIDs {
+		if msg.oldNetIDs[i] == myNetID {
+			if p.oldShare, err = node.registry.LoadDKShare(msg.sharedAddress); err != nil {
+				netGroup.Close()
+				return nil, err
+			}
+		}
+	}
+	for i := range msg.newNetIDs {
+		if msg.newNetIDs[i] == myNetID {
+			newIndex := uint16(i)
+			p.newIndex = &newIndex
+		}
+	}
+	p.log.Infof("Starting DKG resharing process at %v for DkgID=%v", myNetID, dkgID.String())
+	go p.processLoop(msg.timeout)
+	p.netGroup.Attach(peering.PeerMessageReceiverDkg, p.onPeerMessage)
+	return &p, nil
+}
+
+// Handles a message from a peer and pass it to the main thread.
+func (p *reshareProc) onPeerMessage(peerMsg *peering.PeerMessageGroupIn) {
+	p.peerMsgCh <- peerMsg
+}
+
+// That's the main thread executing all the procedure steps.
+// The process is kept until the timeout to answer the repeated requests.
+func (p *reshareProc) processLoop(timeout time.Duration) {
+	timeoutCh := time.After(timeout)
+	for {
+		select {
+		case recv := <-p.peerMsgCh:
+			p.handleStep(recv)
+		case <-timeoutCh:
+			p.netGroup.Close()
+			if p.node.dropReshareProcess(p) {
+				p.log.Debugf("Deleting the resharing process on timeout.")
+			}
+			return
+		}
+	}
+}
+
+// Handles the step requests from the initiator.
+func (p *reshareProc) handleStep(recv *peering.PeerMessageGroupIn) {
+	isInitMsg, initMsg, err := readInitiatorMsg(&recv.PeerMessageData, p.node.blsSuite)
+	if !isInitMsg || initMsg.IsResponse() {
+		return // Responses are for the initiator.
+	}
+	if err != nil {
+		p.log.Warnf("Dropping message, failed to decode: %v", recv)
+		return
+	}
+	step := initMsg.Step()
+	resp, ok := p.responses[step]
+	if !ok {
+		var respMsg msgByteCoder
+		switch step {
+		case reshareStep1Deal:
+			respMsg, err = p.makeDeal()
+		case reshareStep2Combine:
+			sharesMsg, isShares := initMsg.(*reshareSharesMsg)
+			if !isShares {
+				return
+			}
+			respMsg, err = p.combineShares(sharesMsg)
+		case reshareStep3Commit:
+			doneMsg, isDone := initMsg.(*initiatorDoneMsg)
+			if !isDone {
+				return
+			}
+			err = p.commit(doneMsg)
+			respMsg = &initiatorStatusMsg{}
+		case reshareStep4Drop:
+			err = p.drop()
+			respMsg = &initiatorStatusMsg{}
+		default:
+			p.log.Warnf("Dropping message with unexpected step=%v", step)
+			return
+		}
+		if err != nil {
+			p.log.Errorf("Resharing step %v failed, reason=%v", step, err)
+			respMsg = &initiatorStatusMsg{error: err}
+		}
+		resp = makePeerMessage(p.dkgID, peering.PeerMessageReceiverDkg, step, respMsg)
+		p.responses[step] = resp
+	}
+	p.netGroup.SendMsgByIndex(recv.SenderIndex, resp.MsgReceiver, resp.MsgType, resp.MsgData)
+}
+
+func (p *reshareProc) makeDeal() (*reshareDealMsg, error) {
+	var err error
+	if p.oldShare == nil {
+		return nil, errors.New("this node is not a member of the old committee")
+	}
+	n := uint16(len(p.newPeerPubs))
+	var deal *tcrypto.ReshareDeal
+	if deal, err = p.oldShare.NewReshareDeal(p.node.blsSuite, n, p.threshold); err != nil {
+		return nil, err
+	}
+	encShares := make([][]byte, n)
+	for i := range encShares {
+		peerPub := p.node.edSuite.Point()
+		if err = peerPub.UnmarshalBinary(p.newPeerPubs[i].Bytes()); err != nil {
+			return nil, err
+		}
+		var shareBytes []byte
+		if shareBytes, err = deal.Shares[i].MarshalBinary(); err != nil {
+			return nil, err
+		}
+		if encShares[i], err = ecies.Encrypt(p.node.edSuite, peerPub, shareBytes, nil); err != nil {
+			return nil, err
+		}
+	}
+	return &reshareDealMsg{
+		index:        *p.oldShare.Index,
+		n:            p.oldShare.N,
+		t:            p.oldShare.T,
+		sharedPublic: p.oldShare.SharedPublic,
+		publicShares: p.oldShare.PublicShares,
+		commits:      deal.Commits,
+		encShares:    encShares,
+	}, nil
+}
+
+func (p *reshareProc) combineShares(msg *reshareSharesMsg) (*initiatorPubShareMsg, error) {
+	var err error
+	if p.newIndex == nil {
+		return nil, errors.New("this node is not a member of the new committee")
+	}
+	if len(msg.encShares) != len(msg.dealers) {
+		return nil, fmt.Errorf("%d shares received from %d dealers", len(msg.encShares), len(msg.dealers))
+	}
+	values := make([]kyber.Scalar, len(msg.dealers))
+	for i := range values {
+		var shareBytes []byte
+		if shareBytes, err = ecies.Decrypt(p.node.edSuite, p.node.secKey, msg.encShares[i], nil); err != nil {
+			return nil, fmt.Errorf("failed to decrypt the share from %d: %w", msg.dealers[i], err)
+		}
+		values[i] = p.node.blsSuite.Scalar()
+		if err = values[i].UnmarshalBinary(shareBytes); err != nil {
+			return nil, err
+		}
+	}
+	oldShare := &tcrypto.DKShare{
+		Address:      p.sharedAddress,
+		N:            msg.n,
+		T:            msg.t,
+		SharedPublic: msg.sharedPublic,
+		PublicShares: msg.publicShares,
+	}
+	if p.dkShare, err = oldShare.Reshare(
+		p.node.blsSuite, msg.dealers, msg.commits, values,
+		*p.newIndex, uint16(len(p.newPeerPubs)), p.threshold,
+	); err != nil {
+		return nil, err
+	}
+	return newInitiatorPubShareMsg(p.node.blsSuite, p.dkShare, reshareStep2Combine)
+}
+
+func (p *reshareProc) commit(msg *initiatorDoneMsg) error {
+	if p.dkShare == nil {
+		return errors.New("there is no dkShare to commit")
+	}
+	if len(msg.pubShares) != len(p.dkShare.PublicShares) {
+		return errors.New("wrong number of the public shares")
+	}
+	for i := range msg.pubShares {
+		if !msg.pubShares[i].Equal(p.dkShare.PublicShares[i]) {
+			return fmt.Errorf("public share %d differs from the reshared one", i)
+		}
+	}
+	if err := p.node.registry.ReplaceDKShare(p.dkShare); err != nil {
+		return err
+	}
+	if committees, ok := p.node.registry.(registry.CommitteeRegistryProvider); ok {
+		// The committee of the address is changed as well, if the node keeps the committee records.
+		return committees.SaveCommitteeRecord(registry.NewCommitteeRecord(p.sharedAddress, p.newNetIDs...))
+	}
+	return nil
+}
+
+// drop deletes the old share of the node, which is not a member of the new committee.
+func (p *reshareProc) drop() error {
+	if p.oldShare == nil {
+		return errors.New("this node is not a member of the old committee")
+	}
+	if p.newIndex != nil {
+		return errors.New("this node is a member of the new committee")
+	}
+	return p.node.registry.DeleteDKShare(p.sharedAddress)
+}
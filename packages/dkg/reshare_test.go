@@ -0,0 +1,159 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package dkg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/dkg"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/testutil/testpeers"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/sign/bdn"
+)
+
+// TestReshare checks, if the key is reshared to a new committee, and the new committee signs for the same address.
+func TestReshare(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	defer log.Sync()
+	//
+	// Create a fake network and keys for the tests.
+	timeout := 100 * time.Second
+	peerNetIDs, peerIdentities := testpeers.SetupKeys(6)
+	var peeringNetwork *testutil.PeeringNetwork = testutil.NewPeeringNetwork(
+		peerNetIDs, peerIdentities, 10000,
+		testutil.NewPeeringNetReliable(log),
+		testlogger.WithLevel(log, logger.LevelWarn, false),
+	)
+	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
+	//
+	// Initialize the DKG subsystem in each node.
+	dkgNodes := make([]*dkg.Node, len(peerNetIDs))
+	registries := make([]*testutil.DkgRegistryProvider, len(peerNetIDs))
+	for i := range peerNetIDs {
+		registries[i] = testutil.NewDkgRegistryProvider(tcrypto.DefaultSuite())
+		dkgNode, err := dkg.NewNode(
+			peerIdentities[i], networkProviders[i], registries[i],
+			testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
+		)
+		require.NoError(t, err)
+		dkgNodes[i] = dkgNode
+	}
+	//
+	// The key is generated by the first 4 nodes.
+	dkShare, err := dkgNodes[0].GenerateDistributedKey(
+		peerNetIDs[:4],
+		testpeers.PublicKeys(peerIdentities[:4]),
+		3,
+		1*time.Second,
+		2*time.Second,
+		timeout,
+	)
+	require.NoError(t, err)
+	//
+	// The node 0 is down, the node 3 is replaced by the nodes 4 and 5.
+	newNetIDs := []string{peerNetIDs[1], peerNetIDs[5], peerNetIDs[2], peerNetIDs[4]}
+	reshared, err := dkgNodes[1].ReshareDistributedKey(
+		dkShare.Address,
+		peerNetIDs[1:4],
+		newNetIDs,
+		nil, // Taken from the peering network.
+		3,
+		2*time.Second,
+		timeout,
+	)
+	require.NoError(t, err)
+	require.True(t, reshared.Address.Equals(dkShare.Address))
+	require.True(t, reshared.SharedPublic.Equal(dkShare.SharedPublic))
+	require.EqualValues(t, 4, reshared.N)
+	require.EqualValues(t, 3, reshared.T)
+	//
+	// Any 3 members of the new committee sign for the address.
+	data := []byte("data to sign")
+	newShares := make([]*tcrypto.DKShare, 0, 3)
+	for _, i := range []int{5, 2, 4} {
+		newShare, err := registries[i].LoadDKShare(dkShare.Address)
+		require.NoError(t, err)
+		require.EqualValues(t, 4, newShare.N)
+		newShares = append(newShares, newShare)
+	}
+	sigShares := make([][]byte, len(newShares))
+	for i := range newShares {
+		sigShare, err := newShares[i].SignShare(data)
+		require.NoError(t, err)
+		require.NoError(t, newShares[0].VerifySigShare(data, sigShare))
+		sigShares[i] = sigShare
+	}
+	sig, err := newShares[0].RecoverFullSignature(sigShares, data)
+	require.NoError(t, err)
+	require.NoError(t, bdn.Verify(tcrypto.DefaultSuite(), dkShare.SharedPublic, data, sig.Signature.Bytes()))
+	//
+	// The node 3 is not in the new committee, its old share is deleted.
+	// It can't produce a partial signature anymore.
+	_, err = registries[3].LoadDKShare(dkShare.Address)
+	require.Error(t, err)
+	//
+	// The node 0 did not take part in the resharing, it keeps the old share until it is deleted by other means.
+	oldShare, err := registries[0].LoadDKShare(dkShare.Address)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, oldShare.N)
+	require.EqualValues(t, 0, *oldShare.Index)
+}
+
+// TestReshareNotEnoughMembers checks, if the resharing fails without T members of the old committee.
+func TestReshareNotEnoughMembers(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	defer log.Sync()
+	timeout := 10 * time.Second
+	peerNetIDs, peerIdentities := testpeers.SetupKeys(4)
+	var peeringNetwork *testutil.PeeringNetwork = testutil.NewPeeringNetwork(
+		peerNetIDs, peerIdentities, 10000,
+		testutil.NewPeeringNetReliable(log),
+		testlogger.WithLevel(log, logger.LevelWarn, false),
+	)
+	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
+	dkgNodes := make([]*dkg.Node, len(peerNetIDs))
+	for i := range peerNetIDs {
+		dkgNode, err := dkg.NewNode(
+			peerIdentities[i], networkProviders[i], testutil.NewDkgRegistryProvider(tcrypto.DefaultSuite()),
+			testlogger.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
+		)
+		require.NoError(t, err)
+		dkgNodes[i] = dkgNode
+	}
+	dkShare, err := dkgNodes[0].GenerateDistributedKey(
+		peerNetIDs,
+		testpeers.PublicKeys(peerIdentities),
+		3,
+		1*time.Second,
+		2*time.Second,
+		timeout,
+	)
+	require.NoError(t, err)
+	_, err = dkgNodes[0].ReshareDistributedKey(
+		dkShare.Address,
+		peerNetIDs[:2],
+		peerNetIDs,
+		testpeers.PublicKeys(peerIdentities),
+		3,
+		2*time.Second,
+		timeout,
+	)
+	require.Error(t, err)
+	_, err = dkgNodes[0].ReshareDistributedKey(
+		dkShare.Address,
+		peerNetIDs,
+		peerNetIDs,
+		testpeers.PublicKeys(peerIdentities),
+		2, // Not a quorum.
+		2*time.Second,
+		timeout,
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package evmtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// CallFrame is a call in the call tree produced by the call tracer, in the
+// same format as go-ethereum's callTracer
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []*CallFrame    `json:"calls,omitempty"`
+}
+
+// callTracer collects the tree of calls, without the opcode level details
+type callTracer struct {
+	// stack of the calls being executed; the first element is the top-level call
+	callstack []*CallFrame
+}
+
+var _ vm.Tracer = &callTracer{}
+
+func newCallTracer() *callTracer {
+	return &callTracer{}
+}
+
+func newCallFrame(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) *CallFrame {
+	frame := &CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    &to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return frame
+}
+
+func (frame *CallFrame) processOutput(output []byte, err error) {
+	frame.Output = common.CopyBytes(output)
+	if err == nil {
+		return
+	}
+	frame.Error = err.Error()
+	if frame.Type == vm.CREATE.String() || frame.Type == vm.CREATE2.String() {
+		frame.To = nil
+	}
+	if err == vm.ErrExecutionReverted && len(output) > 0 {
+		if reason, errUnpack := abi.UnpackRevert(output); errUnpack == nil {
+			frame.RevertReason = reason
+		}
+	} else {
+		frame.Output = nil
+	}
+}
+
+func (t *callTracer) CaptureStart(env *vm.EVM, from, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.callstack = []*CallFrame{newCallFrame(typ, from, to, input, gas, value)}
+}
+
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *callTracer) CaptureEnter(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.callstack = append(t.callstack, newCallFrame(typ, from, to, input, gas, value))
+}
+
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	call.GasUsed = hexutil.Uint64(gasUsed)
+	call.processOutput(output, err)
+	parent := t.callstack[size-2]
+	parent.Calls = append(parent.Calls, call)
+}
+
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	if len(t.callstack) == 0 {
+		return
+	}
+	t.callstack[0].processOutput(output, err)
+}
+
+func (t *callTracer) Result(res *core.ExecutionResult) (json.RawMessage, error) {
+	if len(t.callstack) == 0 {
+		// the execution failed before entering the EVM
+		return json.Marshal(nil)
+	}
+	root := t.callstack[0]
+	// the gas used by the transaction, including the intrinsic gas
+	root.GasUsed = hexutil.Uint64(res.UsedGas)
+	return json.Marshal(root)
+}
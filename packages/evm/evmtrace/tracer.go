@@ -0,0 +1,67 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// package evmtrace provides the EVM execution tracers used by the debug_traceTransaction
+// and debug_traceCall JSON-RPC endpoints
+package evmtrace
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"golang.org/x/xerrors"
+)
+
+// CallTracerName selects the call tracer. When no tracer is specified, the struct logger is used.
+const CallTracerName = "callTracer"
+
+// TraceConfig holds the tracing options, in the same format as go-ethereum's debug API
+type TraceConfig struct {
+	*vm.LogConfig
+	Tracer *string
+}
+
+// Tracer collects the trace during the EVM execution
+type Tracer interface {
+	vm.Tracer
+	// Result returns the JSON-encoded trace, given the result of the traced execution
+	Result(res *core.ExecutionResult) (json.RawMessage, error)
+}
+
+// New creates the tracer selected by the config
+func New(config *TraceConfig) (Tracer, error) {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	if config.Tracer == nil || *config.Tracer == "" {
+		return newStructLogger(config.LogConfig), nil
+	}
+	switch *config.Tracer {
+	case CallTracerName:
+		return newCallTracer(), nil
+	}
+	return nil, xerrors.Errorf("unsupported tracer: %s", *config.Tracer)
+}
+
+func EncodeTraceConfig(config *TraceConfig) []byte {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func DecodeTraceConfig(b []byte) (*TraceConfig, error) {
+	config := &TraceConfig{}
+	if len(b) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(b, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
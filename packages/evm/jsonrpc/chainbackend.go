@@ -13,5 +13,7 @@ type ChainBackend interface {
 	PostOnLedgerRequest(scName string, funName string, transfer colored.Balances, args dict.Dict) error
 	PostOffLedgerRequest(scName string, funName string, transfer colored.Balances, args dict.Dict) error
 	CallView(scName string, funName string, args dict.Dict) (dict.Dict, error)
+	// CallViewAtBlock calls the view against the state of the chain after the block with the given index
+	CallViewAtBlock(blockIndex uint32, scName string, funName string, args dict.Dict) (dict.Dict, error)
 	Signer() *ed25519.KeyPair
 }
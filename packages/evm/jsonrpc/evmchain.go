@@ -4,7 +4,9 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -12,6 +14,8 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/contracts/native/evm"
+	"github.com/iotaledger/wasp/contracts/native/evm/evmlight"
+	"github.com/iotaledger/wasp/packages/evm/evmtrace"
 	"github.com/iotaledger/wasp/packages/evm/evmtypes"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
@@ -26,10 +30,24 @@ type EVMChain struct {
 	backend      ChainBackend
 	chainID      int
 	contractName string
+
+	// block events, see evmchainevents.go
+	newBlockFeed  eventFeed
+	pendingTxFeed eventFeed
+	eventsMutex   sync.Mutex
+	subscribers   int
+	stopPolling   chan struct{}
+	pollMutex     sync.Mutex
+	sendMutex     sync.Mutex
+	lastBlock     uint64
 }
 
 func NewEVMChain(backend ChainBackend, chainID int, contractName string) *EVMChain {
-	return &EVMChain{backend, chainID, contractName}
+	return &EVMChain{
+		backend:      backend,
+		chainID:      chainID,
+		contractName: contractName,
+	}
 }
 
 func (e *EVMChain) Signer() types.Signer {
@@ -110,9 +128,14 @@ func (e *EVMChain) SendTransaction(tx *types.Transaction) error {
 		return err
 	}
 	// send the Ethereum transaction
-	return e.backend.PostOffLedgerRequest(e.contractName, evm.FuncSendTransaction.Name, fee, dict.Dict{
+	err = e.backend.PostOffLedgerRequest(e.contractName, evm.FuncSendTransaction.Name, fee, dict.Dict{
 		evm.FieldTransactionData: txdata,
 	})
+	if err != nil {
+		return err
+	}
+	e.notifyPendingTransaction(tx)
+	return nil
 }
 
 func paramsWithOptionalBlockNumber(blockNumber *big.Int, params dict.Dict) dict.Dict {
@@ -328,3 +351,57 @@ func (e *EVMChain) Logs(q *ethereum.FilterQuery) ([]*types.Log, error) {
 	}
 	return evmtypes.DecodeLogs(ret.MustGet(evm.FieldResult))
 }
+
+func (e *EVMChain) TraceTransaction(txHash common.Hash, config *evmtrace.TraceConfig) (json.RawMessage, error) {
+	if e.contractName == evmlight.Contract.Name {
+		return e.traceLightTransaction(txHash, config)
+	}
+	ret, err := e.backend.CallView(e.contractName, evm.FuncTraceTransaction.Name, dict.Dict{
+		evm.FieldTransactionHash: txHash.Bytes(),
+		evm.FieldTraceConfig:     evmtrace.EncodeTraceConfig(config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret.MustGet(evm.FieldResult), nil
+}
+
+// traceLightTransaction traces the transaction on top of the retained ISCP state, in which
+// the block of the transaction was run, since evmlight does not keep the state of previous blocks
+func (e *EVMChain) traceLightTransaction(txHash common.Hash, config *evmtrace.TraceConfig) (json.RawMessage, error) {
+	base, err := e.backend.CallView(e.contractName, evm.FuncGetTransactionTraceBase.Name, dict.Dict{
+		evm.FieldTransactionHash: txHash.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	stateIndex, err := codec.DecodeUint32(base.MustGet(evm.FieldStateIndex))
+	if err != nil {
+		return nil, err
+	}
+	params := dict.Dict{
+		evm.FieldTransactionHash: txHash.Bytes(),
+		evm.FieldTraceConfig:     evmtrace.EncodeTraceConfig(config),
+	}
+	for k, v := range base {
+		if k != evm.FieldStateIndex {
+			params.Set(k, v)
+		}
+	}
+	ret, err := e.backend.CallViewAtBlock(stateIndex, e.contractName, evm.FuncTraceTransaction.Name, params)
+	if err != nil {
+		return nil, err
+	}
+	return ret.MustGet(evm.FieldResult), nil
+}
+
+func (e *EVMChain) TraceCall(args ethereum.CallMsg, blockNumberOrHash rpc.BlockNumberOrHash, config *evmtrace.TraceConfig) (json.RawMessage, error) {
+	ret, err := e.backend.CallView(e.contractName, evm.FuncTraceCall.Name, paramsWithOptionalBlockNumberOrHash(blockNumberOrHash, dict.Dict{
+		evm.FieldCallMsg:     evmtypes.EncodeCallMsg(args),
+		evm.FieldTraceConfig: evmtrace.EncodeTraceConfig(config),
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return ret.MustGet(evm.FieldResult), nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonrpc
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"golang.org/x/xerrors"
+)
+
+// blockPollInterval is how often the EVM chain is checked for new blocks
+// while there is at least one subscriber
+const blockPollInterval = 1 * time.Second
+
+// NewBlockEvent is sent to the subscribers of EVMChain.SubscribeNewBlocks
+// each time a new block is detected in the EVM chain
+type NewBlockEvent struct {
+	Block *types.Block
+	// Logs are all logs emitted by the transactions in the block
+	Logs []*types.Log
+}
+
+// subscriptionBufferSize is the recommended buffer size of the channels
+// passed to SubscribeNewBlocks and SubscribePendingTransactions
+const subscriptionBufferSize = 100
+
+var errSubscriptionOverflow = xerrors.New("subscription channel overflow")
+
+// SubscribeNewBlocks subscribes the channel to the new block events.
+// The EVM chain is polled only while there are active subscriptions.
+// Events are never waited for: if the channel is full, the subscription is
+// closed with an error, so ch should be buffered.
+func (e *EVMChain) SubscribeNewBlocks(ch chan<- *NewBlockEvent) event.Subscription {
+	return e.subscribe(&e.newBlockFeed, func(ev interface{}) bool {
+		select {
+		case ch <- ev.(*NewBlockEvent):
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// SubscribePendingTransactions subscribes the channel to the hashes of the
+// transactions sent through this EVMChain. Same as in SubscribeNewBlocks, if
+// the channel is full, the subscription is closed with an error.
+func (e *EVMChain) SubscribePendingTransactions(ch chan<- common.Hash) event.Subscription {
+	return e.subscribe(&e.pendingTxFeed, func(ev interface{}) bool {
+		select {
+		case ch <- ev.(common.Hash):
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// eventFeed delivers the events to the subscribers without blocking
+type eventFeed struct {
+	mutex sync.Mutex
+	subs  map[*chainSubscription]struct{}
+}
+
+func (f *eventFeed) send(ev interface{}) {
+	f.mutex.Lock()
+	subs := make([]*chainSubscription, 0, len(f.subs))
+	for sub := range f.subs {
+		subs = append(subs, sub)
+	}
+	f.mutex.Unlock()
+
+	for _, sub := range subs {
+		if !sub.send(ev) {
+			sub.close(errSubscriptionOverflow)
+		}
+	}
+}
+
+// chainSubscription is removed from the feed on Unsubscribe or on overflow
+// and stops the block polling after the last subscriber is gone
+type chainSubscription struct {
+	feed    *eventFeed
+	send    func(interface{}) bool
+	err     chan error
+	once    sync.Once
+	onClose func()
+}
+
+func (s *chainSubscription) Unsubscribe() {
+	s.close(nil)
+}
+
+func (s *chainSubscription) Err() <-chan error {
+	return s.err
+}
+
+func (s *chainSubscription) close(err error) {
+	s.once.Do(func() {
+		s.feed.mutex.Lock()
+		delete(s.feed.subs, s)
+		s.feed.mutex.Unlock()
+		if err != nil {
+			s.err <- err
+		}
+		close(s.err)
+		s.onClose()
+	})
+}
+
+func (e *EVMChain) subscribe(feed *eventFeed, send func(interface{}) bool) event.Subscription {
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+
+	if e.subscribers == 0 {
+		e.startPolling()
+	}
+	e.subscribers++
+	sub := &chainSubscription{
+		feed:    feed,
+		send:    send,
+		err:     make(chan error, 1),
+		onClose: e.untrackSubscription,
+	}
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+	if feed.subs == nil {
+		feed.subs = make(map[*chainSubscription]struct{})
+	}
+	feed.subs[sub] = struct{}{}
+	return sub
+}
+
+func (e *EVMChain) untrackSubscription() {
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+
+	e.subscribers--
+	if e.subscribers == 0 {
+		close(e.stopPolling)
+	}
+}
+
+func (e *EVMChain) hasSubscribers() bool {
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+	return e.subscribers > 0
+}
+
+// startPolling must be called with eventsMutex locked
+func (e *EVMChain) startPolling() {
+	e.pollMutex.Lock()
+	// events are sent only for the blocks minted after the first subscription
+	if n, err := e.BlockNumber(); err == nil {
+		e.lastBlock = n.Uint64()
+	}
+	e.pollMutex.Unlock()
+
+	stop := make(chan struct{})
+	e.stopPolling = stop
+	go func() {
+		ticker := time.NewTicker(blockPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				e.checkNewBlocks()
+			}
+		}
+	}()
+}
+
+// checkNewBlocks sends a NewBlockEvent for each block minted since the last check
+func (e *EVMChain) checkNewBlocks() {
+	e.pollMutex.Lock()
+	events := e.collectNewBlocks()
+	// the events are sent outside of pollMutex, sendMutex keeps them in order
+	e.sendMutex.Lock()
+	defer e.sendMutex.Unlock()
+	e.pollMutex.Unlock()
+
+	for _, ev := range events {
+		e.newBlockFeed.send(ev)
+	}
+}
+
+// collectNewBlocks must be called with pollMutex locked
+func (e *EVMChain) collectNewBlocks() []*NewBlockEvent {
+	n, err := e.BlockNumber()
+	if err != nil {
+		return nil
+	}
+	var events []*NewBlockEvent
+	for i := e.lastBlock + 1; i <= n.Uint64(); i++ {
+		ev, err := e.newBlockEvent(new(big.Int).SetUint64(i))
+		if err != nil {
+			// try again on the next check
+			break
+		}
+		events = append(events, ev)
+		e.lastBlock = i
+	}
+	return events
+}
+
+func (e *EVMChain) newBlockEvent(blockNumber *big.Int) (*NewBlockEvent, error) {
+	block, err := e.BlockByNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := e.Logs(&ethereum.FilterQuery{FromBlock: blockNumber, ToBlock: blockNumber})
+	if err != nil {
+		return nil, err
+	}
+	return &NewBlockEvent{Block: block, Logs: logs}, nil
+}
+
+// notifyPendingTransaction is called after the transaction was posted to the chain
+func (e *EVMChain) notifyPendingTransaction(tx *types.Transaction) {
+	if !e.hasSubscribers() {
+		return
+	}
+	e.pendingTxFeed.send(tx.Hash())
+	// the transaction may already be included in a block, no need to wait for the next poll
+	e.checkNewBlocks()
+}
+
+// filterLogs returns the logs matching the block range, addresses and topics of the query
+func filterLogs(logs []*types.Log, q *ethereum.FilterQuery) []*types.Log {
+	ret := make([]*types.Log, 0)
+	for _, log := range logs {
+		if q.FromBlock != nil && q.FromBlock.Sign() >= 0 && q.FromBlock.Uint64() > log.BlockNumber {
+			continue
+		}
+		if q.ToBlock != nil && q.ToBlock.Sign() >= 0 && q.ToBlock.Uint64() < log.BlockNumber {
+			continue
+		}
+		if q.BlockHash != nil && *q.BlockHash != log.BlockHash {
+			continue
+		}
+		if !logMatches(log, q.Addresses, q.Topics) {
+			continue
+		}
+		ret = append(ret, log)
+	}
+	return ret
+}
+
+func logMatches(log *types.Log, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		found := false
+		for _, a := range addresses {
+			if log.Address == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(topics) > len(log.Topics) {
+		return false
+	}
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			// empty rule set == wildcard
+			continue
+		}
+		found := false
+		for _, topic := range sub {
+			if log.Topics[i] == topic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
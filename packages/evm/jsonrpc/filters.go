@@ -0,0 +1,219 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonrpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
+)
+
+// filterTimeout is the time after which a filter that is not polled is uninstalled
+const filterTimeout = 5 * time.Minute
+
+var errFilterNotFound = xerrors.New("filter not found")
+
+type filterKind int
+
+const (
+	logsFilter = filterKind(iota)
+	blocksFilter
+	pendingTxFilter
+)
+
+// filter accumulates the changes between two calls to eth_getFilterChanges
+type filter struct {
+	kind     filterKind
+	crit     ethereum.FilterQuery
+	sub      event.Subscription
+	lastPoll time.Time
+	hashes   []common.Hash
+	logs     []*types.Log
+	// err is set when the subscription is closed because of overflow
+	err error
+}
+
+// filterManager keeps the state of the filters installed with eth_newFilter,
+// eth_newBlockFilter and eth_newPendingTransactionFilter
+type filterManager struct {
+	evmChain *EVMChain
+	timeout  time.Duration
+	mutex    sync.Mutex
+	filters  map[rpc.ID]*filter
+	expiring bool
+}
+
+func newFilterManager(evmChain *EVMChain, timeout time.Duration) *filterManager {
+	return &filterManager{
+		evmChain: evmChain,
+		timeout:  timeout,
+		filters:  make(map[rpc.ID]*filter),
+	}
+}
+
+func (m *filterManager) newLogsFilter(crit *ethereum.FilterQuery) rpc.ID {
+	return m.installBlocksFilter(&filter{kind: logsFilter, crit: *crit}, func(f *filter, ev *NewBlockEvent) {
+		f.logs = append(f.logs, filterLogs(ev.Logs, &f.crit)...)
+	})
+}
+
+func (m *filterManager) newBlocksFilter() rpc.ID {
+	return m.installBlocksFilter(&filter{kind: blocksFilter}, func(f *filter, ev *NewBlockEvent) {
+		f.hashes = append(f.hashes, ev.Block.Hash())
+	})
+}
+
+func (m *filterManager) installBlocksFilter(f *filter, onBlock func(*filter, *NewBlockEvent)) rpc.ID {
+	ch := make(chan *NewBlockEvent, subscriptionBufferSize)
+	f.sub = m.evmChain.SubscribeNewBlocks(ch)
+	go func() {
+		for {
+			select {
+			case ev := <-ch:
+				m.mutex.Lock()
+				onBlock(f, ev)
+				m.mutex.Unlock()
+			case err := <-f.sub.Err():
+				m.closeFilter(f, err)
+				return
+			}
+		}
+	}()
+	return m.install(f)
+}
+
+func (m *filterManager) newPendingTxFilter() rpc.ID {
+	f := &filter{kind: pendingTxFilter}
+	ch := make(chan common.Hash, subscriptionBufferSize)
+	f.sub = m.evmChain.SubscribePendingTransactions(ch)
+	go func() {
+		for {
+			select {
+			case hash := <-ch:
+				m.mutex.Lock()
+				f.hashes = append(f.hashes, hash)
+				m.mutex.Unlock()
+			case err := <-f.sub.Err():
+				m.closeFilter(f, err)
+				return
+			}
+		}
+	}()
+	return m.install(f)
+}
+
+// closeFilter records the error of the subscription, which is
+// returned on the next poll of the filter
+func (m *filterManager) closeFilter(f *filter, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	f.err = err
+}
+
+func (m *filterManager) install(f *filter) rpc.ID {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	id := rpc.NewID()
+	f.lastPoll = time.Now()
+	m.filters[id] = f
+	if !m.expiring {
+		m.expiring = true
+		go m.expireFilters()
+	}
+	return id
+}
+
+// expireFilters periodically uninstalls the filters that were not polled
+// within the timeout. It stops when there are no filters left.
+func (m *filterManager) expireFilters() {
+	ticker := time.NewTicker(m.timeout)
+	defer ticker.Stop()
+	for range ticker.C {
+		var expired []*filter
+		m.mutex.Lock()
+		for id, f := range m.filters {
+			if time.Since(f.lastPoll) >= m.timeout {
+				expired = append(expired, f)
+				delete(m.filters, id)
+			}
+		}
+		done := len(m.filters) == 0
+		if done {
+			m.expiring = false
+		}
+		m.mutex.Unlock()
+
+		for _, f := range expired {
+			f.sub.Unsubscribe()
+		}
+		if done {
+			return
+		}
+	}
+}
+
+func (m *filterManager) uninstall(id rpc.ID) bool {
+	m.mutex.Lock()
+	f, ok := m.filters[id]
+	delete(m.filters, id)
+	m.mutex.Unlock()
+
+	if !ok {
+		return false
+	}
+	f.sub.Unsubscribe()
+	return true
+}
+
+// changes returns the changes since the last poll: either the logs or the
+// block / transaction hashes, depending on the kind of filter
+func (m *filterManager) changes(id rpc.ID) (interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	f, ok := m.filters[id]
+	if !ok {
+		return nil, errFilterNotFound
+	}
+	if f.err != nil {
+		delete(m.filters, id)
+		return nil, f.err
+	}
+	f.lastPoll = time.Now()
+	if f.kind == logsFilter {
+		logs := f.logs
+		f.logs = nil
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		return logs, nil
+	}
+	hashes := f.hashes
+	f.hashes = nil
+	if hashes == nil {
+		hashes = []common.Hash{}
+	}
+	return hashes, nil
+}
+
+// criteria returns the filter query of the logs filter
+func (m *filterManager) criteria(id rpc.ID) (*ethereum.FilterQuery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	f, ok := m.filters[id]
+	if !ok || f.kind != logsFilter {
+		return nil, errFilterNotFound
+	}
+	f.lastPoll = time.Now()
+	crit := f.crit
+	return &crit, nil
+}
@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -345,3 +346,99 @@ func (e *Env) TestRPCInvalidNonce() {
 	require.Error(e.T, err)
 	require.Regexp(e.T, `invalid transaction nonce: got 1, want 0`, err.Error())
 }
+
+func (e *Env) TestRPCFilters() {
+	creator, creatorAddress := evmtest.Accounts[0], evmtest.AccountAddress(0)
+	contractABI, err := abi.JSON(strings.NewReader(evmtest.ERC20ContractABI))
+	require.NoError(e.T, err)
+	contractAddress := crypto.CreateAddress(creatorAddress, e.NonceAt(creatorAddress))
+
+	var blockFilter, logsFilter, pendingTxFilter rpc.ID
+	require.NoError(e.T, e.RawClient.Call(&blockFilter, "eth_newBlockFilter"))
+	require.NoError(e.T, e.RawClient.Call(&logsFilter, "eth_newFilter", map[string]interface{}{
+		"address": contractAddress,
+	}))
+	require.NoError(e.T, e.RawClient.Call(&pendingTxFilter, "eth_newPendingTransactionFilter"))
+
+	var hashes []common.Hash
+	require.NoError(e.T, e.RawClient.Call(&hashes, "eth_getFilterChanges", blockFilter))
+	require.Empty(e.T, hashes)
+
+	tx, _ := e.DeployEVMContract(creator, contractABI, evmtest.ERC20ContractBytecode, "TestCoin", "TEST")
+	receipt := e.MustTxReceipt(tx.Hash())
+
+	require.NoError(e.T, e.RawClient.Call(&hashes, "eth_getFilterChanges", blockFilter))
+	require.Contains(e.T, hashes, receipt.BlockHash)
+
+	require.NoError(e.T, e.RawClient.Call(&hashes, "eth_getFilterChanges", pendingTxFilter))
+	require.Equal(e.T, []common.Hash{tx.Hash()}, hashes)
+
+	var logs []types.Log
+	require.NoError(e.T, e.RawClient.Call(&logs, "eth_getFilterChanges", logsFilter))
+	require.Len(e.T, logs, 1)
+	require.Equal(e.T, contractAddress, logs[0].Address)
+
+	// changes are returned only once
+	require.NoError(e.T, e.RawClient.Call(&logs, "eth_getFilterChanges", logsFilter))
+	require.Empty(e.T, logs)
+
+	// eth_getFilterLogs returns all matching logs
+	require.NoError(e.T, e.RawClient.Call(&logs, "eth_getFilterLogs", logsFilter))
+	require.Len(e.T, logs, 1)
+
+	var ok bool
+	require.NoError(e.T, e.RawClient.Call(&ok, "eth_uninstallFilter", blockFilter))
+	require.True(e.T, ok)
+	require.NoError(e.T, e.RawClient.Call(&ok, "eth_uninstallFilter", blockFilter))
+	require.False(e.T, ok)
+	require.Error(e.T, e.RawClient.Call(&hashes, "eth_getFilterChanges", blockFilter))
+}
+
+func (e *Env) TestRPCSubscriptions() {
+	creator, creatorAddress := evmtest.Accounts[0], evmtest.AccountAddress(0)
+	contractABI, err := abi.JSON(strings.NewReader(evmtest.ERC20ContractABI))
+	require.NoError(e.T, err)
+	contractAddress := crypto.CreateAddress(creatorAddress, e.NonceAt(creatorAddress))
+
+	heads := make(chan *types.Header, 10)
+	headsSub, err := e.Client.SubscribeNewHead(context.Background(), heads)
+	require.NoError(e.T, err)
+	defer headsSub.Unsubscribe()
+
+	logs := make(chan types.Log, 10)
+	logsSub, err := e.Client.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{
+		Addresses: []common.Address{contractAddress},
+	}, logs)
+	require.NoError(e.T, err)
+	defer logsSub.Unsubscribe()
+
+	pendingTxs := make(chan common.Hash, 10)
+	pendingTxsSub, err := e.RawClient.EthSubscribe(context.Background(), pendingTxs, "newPendingTransactions")
+	require.NoError(e.T, err)
+	defer pendingTxsSub.Unsubscribe()
+
+	tx, _ := e.DeployEVMContract(creator, contractABI, evmtest.ERC20ContractBytecode, "TestCoin", "TEST")
+	receipt := e.MustTxReceipt(tx.Hash())
+
+	timeout := time.After(10 * time.Second)
+	select {
+	case hash := <-pendingTxs:
+		require.Equal(e.T, tx.Hash(), hash)
+	case <-timeout:
+		e.T.Fatal("timeout waiting for pending transaction")
+	}
+	for found := false; !found; {
+		select {
+		case header := <-heads:
+			found = header.Hash() == receipt.BlockHash
+		case <-timeout:
+			e.T.Fatal("timeout waiting for new head")
+		}
+	}
+	select {
+	case log := <-logs:
+		require.Equal(e.T, contractAddress, log.Address)
+	case <-timeout:
+		e.T.Fatal("timeout waiting for log")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -20,9 +21,12 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/contracts/native/evm"
+	"github.com/iotaledger/wasp/contracts/native/evm/evmlight"
 	"github.com/iotaledger/wasp/packages/evm/evmflavors"
 	"github.com/iotaledger/wasp/packages/evm/evmtest"
+	"github.com/iotaledger/wasp/packages/evm/evmtrace"
 	"github.com/iotaledger/wasp/packages/evm/evmtypes"
 	"github.com/iotaledger/wasp/packages/evm/jsonrpc"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
@@ -39,7 +43,10 @@ func withEVMFlavors(t *testing.T, f func(*testing.T, *coreutil.ContractInfo)) {
 
 type soloTestEnv struct {
 	Env
-	solo *solo.Solo
+	solo       *solo.Solo
+	soloChain  *solo.Chain
+	chainOwner *ed25519.KeyPair
+	evmChain   *jsonrpc.EVMChain
 }
 
 func newSoloTestEnv(t *testing.T, evmFlavor *coreutil.ContractInfo) *soloTestEnv {
@@ -79,7 +86,10 @@ func newSoloTestEnv(t *testing.T, evmFlavor *coreutil.ContractInfo) *soloTestEnv
 			RawClient: rawClient,
 			ChainID:   chainID,
 		},
-		solo: s,
+		solo:       s,
+		soloChain:  chain,
+		chainOwner: chainOwner,
+		evmChain:   evmChain,
 	}
 }
 
@@ -443,6 +453,160 @@ func TestRPCGetLogs(t *testing.T) {
 	})
 }
 
+func TestRPCFilters(t *testing.T) {
+	withEVMFlavors(t, func(t *testing.T, evmFlavor *coreutil.ContractInfo) {
+		newSoloTestEnv(t, evmFlavor).TestRPCFilters()
+	})
+}
+
+func TestRPCSubscriptions(t *testing.T) {
+	withEVMFlavors(t, func(t *testing.T, evmFlavor *coreutil.ContractInfo) {
+		newSoloTestEnv(t, evmFlavor).TestRPCSubscriptions()
+	})
+}
+
+func TestRPCSlowSubscriber(t *testing.T) {
+	withEVMFlavors(t, func(t *testing.T, evmFlavor *coreutil.ContractInfo) {
+		env := newSoloTestEnv(t, evmFlavor)
+
+		// nobody reads from the channels
+		blocksSub := env.evmChain.SubscribeNewBlocks(make(chan *jsonrpc.NewBlockEvent))
+		defer blocksSub.Unsubscribe()
+		pendingTxsSub := env.evmChain.SubscribePendingTransactions(make(chan common.Hash))
+		defer pendingTxsSub.Unsubscribe()
+
+		// sending the transaction is not blocked by the subscribers
+		_, addr := generateKey(t)
+		tx := env.RequestFunds(addr)
+		env.MustTxReceipt(tx.Hash())
+
+		timeout := time.After(10 * time.Second)
+		for _, sub := range []ethereum.Subscription{pendingTxsSub, blocksSub} {
+			select {
+			case err := <-sub.Err():
+				require.Error(t, err)
+			case <-timeout:
+				t.Fatal("timeout waiting for the subscription to be closed")
+			}
+		}
+	})
+}
+
+func TestRPCTraceCall(t *testing.T) {
+	withEVMFlavors(t, func(t *testing.T, evmFlavor *coreutil.ContractInfo) {
+		env := newSoloTestEnv(t, evmFlavor)
+		creator, creatorAddress := evmtest.Accounts[0], evmtest.AccountAddress(0)
+		contractABI, err := abi.JSON(strings.NewReader(evmtest.ERC20ContractABI))
+		require.NoError(t, err)
+		_, contractAddress := env.DeployEVMContract(creator, contractABI, evmtest.ERC20ContractBytecode, "TestCoin", "TEST")
+
+		// account 1 has no tokens, so the transfer reverts
+		callArguments, err := contractABI.Pack("transfer", creatorAddress, big.NewInt(1337))
+		require.NoError(t, err)
+		callArgs := map[string]interface{}{
+			"from": evmtest.AccountAddress(1),
+			"to":   contractAddress,
+			"data": hexutil.Bytes(callArguments),
+		}
+
+		var callTrace evmtrace.CallFrame
+		err = env.RawClient.Call(&callTrace, "debug_traceCall", callArgs, "latest", map[string]interface{}{
+			"tracer": evmtrace.CallTracerName,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "CALL", callTrace.Type)
+		require.Equal(t, contractAddress, *callTrace.To)
+		require.Equal(t, "execution reverted", callTrace.Error)
+		require.NotZero(t, callTrace.GasUsed)
+
+		var structTrace evmtrace.ExecutionResult
+		err = env.RawClient.Call(&structTrace, "debug_traceCall", callArgs, "latest", nil)
+		require.NoError(t, err)
+		require.True(t, structTrace.Failed)
+		require.NotEmpty(t, structTrace.StructLogs)
+		require.Equal(t, "REVERT", structTrace.StructLogs[len(structTrace.StructLogs)-1].Op)
+
+		if evmFlavor.Name == evmlight.Contract.Name {
+			// the state of previous blocks is not kept
+			err = env.RawClient.Call(&structTrace, "debug_traceCall", callArgs, "0x0", nil)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestRPCTraceTransaction(t *testing.T) {
+	withEVMFlavors(t, func(t *testing.T, evmFlavor *coreutil.ContractInfo) {
+		testRPCTraceTransaction(t, newSoloTestEnv(t, evmFlavor))
+	})
+}
+
+func TestRPCTraceTransactionEVMLightBlockTime(t *testing.T) {
+	env := newSoloTestEnv(t, evmlight.Contract)
+	// the EVM block spans several ISCP blocks, the previous transactions are already in the traced state
+	_, err := env.soloChain.PostRequestSync(
+		solo.NewCallParams(evmlight.Contract.Name, evm.FuncSetBlockTime.Name, evm.FieldBlockTime, codec.EncodeUint32(60)).WithIotas(1),
+		env.chainOwner,
+	)
+	require.NoError(t, err)
+	testRPCTraceTransaction(t, env)
+	require.Zero(t, env.BlockNumber())
+}
+
+func testRPCTraceTransaction(t *testing.T, env *soloTestEnv) {
+	creator, creatorAddress := evmtest.Accounts[0], evmtest.AccountAddress(0)
+	contractABI, err := abi.JSON(strings.NewReader(evmtest.ERC20ContractABI))
+	require.NoError(t, err)
+	_, contractAddress := env.DeployEVMContract(creator, contractABI, evmtest.ERC20ContractBytecode, "TestCoin", "TEST")
+
+	// two transfers in separate blocks; the second one must be traced on top of the state after the first one
+	transfer := func(amount int64) common.Hash {
+		callArguments, err := contractABI.Pack("transfer", evmtest.AccountAddress(1), big.NewInt(amount))
+		require.NoError(t, err)
+		nonce := hexutil.Uint64(env.NonceAt(creatorAddress))
+		gas, err := env.Client.EstimateGas(context.Background(), ethereum.CallMsg{
+			From: creatorAddress,
+			To:   &contractAddress,
+			Data: callArguments,
+		})
+		require.NoError(t, err)
+		return env.MustSendTransaction(&jsonrpc.SendTxArgs{
+			From:     creatorAddress,
+			To:       &contractAddress,
+			Gas:      (*hexutil.Uint64)(&gas),
+			GasPrice: (*hexutil.Big)(evm.GasPrice),
+			Nonce:    &nonce,
+			Data:     (*hexutil.Bytes)(&callArguments),
+		})
+	}
+	transfer(1)
+	txHash := transfer(2)
+	receipt := env.MustTxReceipt(txHash)
+
+	var callTrace evmtrace.CallFrame
+	err = env.RawClient.Call(&callTrace, "debug_traceTransaction", txHash, map[string]interface{}{
+		"tracer": evmtrace.CallTracerName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "CALL", callTrace.Type)
+	require.Equal(t, creatorAddress, callTrace.From)
+	require.Equal(t, contractAddress, *callTrace.To)
+	require.Empty(t, callTrace.Error)
+	require.EqualValues(t, receipt.GasUsed, callTrace.GasUsed)
+
+	var structTrace evmtrace.ExecutionResult
+	err = env.RawClient.Call(&structTrace, "debug_traceTransaction", txHash, map[string]interface{}{
+		"disableStorage": true,
+	})
+	require.NoError(t, err)
+	require.False(t, structTrace.Failed)
+	require.Equal(t, receipt.GasUsed, structTrace.Gas)
+	require.NotEmpty(t, structTrace.StructLogs)
+	require.Nil(t, structTrace.StructLogs[0].Storage)
+
+	err = env.RawClient.Call(&callTrace, "debug_traceTransaction", common.Hash{})
+	require.Error(t, err)
+}
+
 func TestRPCGasLimit(t *testing.T) {
 	withEVMFlavors(t, func(t *testing.T, evmFlavor *coreutil.ContractInfo) {
 		newSoloTestEnv(t, evmFlavor).TestRPCGasLimit()
@@ -17,6 +17,7 @@ func NewServer(evmChain *EVMChain, accountManager *AccountManager) *rpc.Server {
 		{"net", NewNetService(evmChain.chainID)},
 		{"eth", NewEthService(evmChain, accountManager)},
 		{"txpool", NewTxPoolService()},
+		{"debug", NewDebugService(evmChain)},
 	} {
 		err := rpcsrv.RegisterName(srv.namespace, srv.service)
 		if err != nil {
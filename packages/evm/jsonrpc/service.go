@@ -6,6 +6,8 @@
 package jsonrpc
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -18,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/iotaledger/wasp/packages/evm/evmtrace"
 	"golang.org/x/crypto/sha3"
 	"golang.org/x/xerrors"
 )
@@ -25,10 +28,11 @@ import (
 type EthService struct {
 	evmChain *EVMChain
 	accounts *AccountManager
+	filters  *filterManager
 }
 
 func NewEthService(evmChain *EVMChain, accounts *AccountManager) *EthService {
-	return &EthService{evmChain, accounts}
+	return &EthService{evmChain, accounts, newFilterManager(evmChain, filterTimeout)}
 }
 
 func (e *EthService) ProtocolVersion() hexutil.Uint {
@@ -279,14 +283,127 @@ func (e *EthService) ChainId() hexutil.Uint { //nolint:revive
 	return hexutil.Uint(e.evmChain.chainID)
 }
 
+// NewFilter creates a filter for the logs emitted in the new blocks. Filters
+// that are not polled with eth_getFilterChanges for 5 minutes are uninstalled.
+func (e *EthService) NewFilter(crit RPCFilterQuery) rpc.ID {
+	return e.filters.newLogsFilter((*ethereum.FilterQuery)(&crit))
+}
+
+// NewBlockFilter creates a filter for the hashes of the new blocks
+func (e *EthService) NewBlockFilter() rpc.ID {
+	return e.filters.newBlocksFilter()
+}
+
+// NewPendingTransactionFilter creates a filter for the hashes of the transactions
+// sent through this server
+func (e *EthService) NewPendingTransactionFilter() rpc.ID {
+	return e.filters.newPendingTxFilter()
+}
+
+func (e *EthService) UninstallFilter(id rpc.ID) bool {
+	return e.filters.uninstall(id)
+}
+
+// GetFilterChanges returns the logs or hashes received by the filter since the last poll
+func (e *EthService) GetFilterChanges(id rpc.ID) (interface{}, error) {
+	return e.filters.changes(id)
+}
+
+// GetFilterLogs returns all logs matching the criteria of the logs filter
+func (e *EthService) GetFilterLogs(id rpc.ID) ([]*types.Log, error) {
+	crit, err := e.filters.criteria(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.evmChain.Logs(crit)
+}
+
+// NewHeads implements eth_subscribe("newHeads")
+func (e *EthService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+	ch := make(chan *NewBlockEvent, subscriptionBufferSize)
+	sub := e.evmChain.SubscribeNewBlocks(ch)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-ch:
+				_ = notifier.Notify(rpcSub.ID, ev.Block.Header())
+			case <-sub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// Logs implements eth_subscribe("logs")
+func (e *EthService) Logs(ctx context.Context, crit RPCFilterQuery) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	q := (*ethereum.FilterQuery)(&crit)
+	rpcSub := notifier.CreateSubscription()
+	ch := make(chan *NewBlockEvent, subscriptionBufferSize)
+	sub := e.evmChain.SubscribeNewBlocks(ch)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-ch:
+				for _, log := range filterLogs(ev.Logs, q) {
+					_ = notifier.Notify(rpcSub.ID, log)
+				}
+			case <-sub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// NewPendingTransactions implements eth_subscribe("newPendingTransactions")
+func (e *EthService) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+	ch := make(chan common.Hash, subscriptionBufferSize)
+	sub := e.evmChain.SubscribePendingTransactions(ch)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case hash := <-ch:
+				_ = notifier.Notify(rpcSub.ID, hash)
+			case <-sub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 /*
 Not implemented:
-func (e *EthService) NewFilter()
-func (e *EthService) NewBlockFilter()
-func (e *EthService) NewPendingTransactionFilter()
-func (e *EthService) UninstallFilter()
-func (e *EthService) GetFilterChanges()
-func (e *EthService) GetFilterLogs()
 func (e *EthService) SubmitWork()
 func (e *EthService) GetWork()
 func (e *EthService) SubmitHashrate()
@@ -350,3 +467,25 @@ func (s *TxPoolService) Status() map[string]hexutil.Uint {
 		"queued":  hexutil.Uint(0),
 	}
 }
+
+// DebugService implements the debug namespace, limited to the execution tracing endpoints
+type DebugService struct {
+	evmChain *EVMChain
+}
+
+func NewDebugService(evmChain *EVMChain) *DebugService {
+	return &DebugService{evmChain}
+}
+
+// TraceTransaction re-executes the transaction on top of the state in which it
+// was executed, and returns the trace produced by the tracer selected in config.
+// With evmlight, the ISCP state before the block of the transaction must still be retained by the node.
+func (d *DebugService) TraceTransaction(txHash common.Hash, config *evmtrace.TraceConfig) (json.RawMessage, error) {
+	return d.evmChain.TraceTransaction(txHash, config)
+}
+
+// TraceCall executes the call on top of the state of the given block, and returns
+// the trace produced by the tracer selected in config
+func (d *DebugService) TraceCall(args *RPCCallArgs, blockNumberOrHash rpc.BlockNumberOrHash, config *evmtrace.TraceConfig) (json.RawMessage, error) {
+	return d.evmChain.TraceCall(args.parse(), blockNumberOrHash, config)
+}
@@ -45,3 +45,7 @@ func (s *SoloBackend) PostOffLedgerRequest(scName, funName string, transfer colo
 func (s *SoloBackend) CallView(scName, funName string, args dict.Dict) (dict.Dict, error) {
 	return s.Chain.CallView(scName, funName, args)
 }
+
+func (s *SoloBackend) CallViewAtBlock(blockIndex uint32, scName, funName string, args dict.Dict) (dict.Dict, error) {
+	return s.Chain.CallViewAtBlock(blockIndex, scName, funName, args)
+}
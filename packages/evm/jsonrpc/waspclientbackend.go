@@ -67,3 +67,7 @@ func (w *WaspClientBackend) PostOffLedgerRequest(scName, funName string, transfe
 func (w *WaspClientBackend) CallView(scName, funName string, args dict.Dict) (dict.Dict, error) {
 	return w.ChainClient.CallView(iscp.Hn(scName), funName, args)
 }
+
+func (w *WaspClientBackend) CallViewAtBlock(blockIndex uint32, scName, funName string, args dict.Dict) (dict.Dict, error) {
+	return w.ChainClient.CallViewAtBlock(blockIndex, iscp.Hn(scName), funName, args)
+}
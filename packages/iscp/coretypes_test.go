@@ -62,3 +62,16 @@ func TestHnameCollision(t *testing.T) {
 
 	require.NotEqualValues(t, hn1, hn2)
 }
+
+func TestRequestError(t *testing.T) {
+	e := NewRequestError(Hn("dummy"), RequestErrorContract, "insufficient balance", "42", "100")
+	back, err := RequestErrorFromBytes(e.Bytes())
+	require.NoError(t, err)
+	require.EqualValues(t, e, back)
+	require.EqualValues(t, "insufficient balance", back.Error())
+
+	e = NewRequestError(Hn("dummy"), RequestErrorPanic, "panic in VM: boom")
+	back, err = RequestErrorFromBytes(e.Bytes())
+	require.NoError(t, err)
+	require.EqualValues(t, e, back)
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package iscp
+
+import (
+	"strings"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"golang.org/x/xerrors"
+)
+
+// MaxEventTopics is the maximum number of indexed fields of the event
+const MaxEventTopics = 4
+
+// EventField is a named and typed value of the event
+type EventField struct {
+	Name string
+	// Type is the name of the schema type of the value, for example "Int64" or "AgentID".
+	// It tells the readers how to decode the value
+	Type  string
+	Value []byte
+	// Indexed fields are the topics of the event, they can be used to filter the events
+	Indexed bool
+}
+
+// Event is a typed event emitted by a smart contract. Indexed fields are stored in the
+// blocklog together with indexes which allow to query the events by their values
+type Event struct {
+	Name   string
+	Fields []*EventField
+}
+
+func NewEvent(name string) *Event {
+	return &Event{Name: name, Fields: make([]*EventField, 0)}
+}
+
+// WithField adds a field which is not indexed
+func (e *Event) WithField(name, typ string, value []byte) *Event {
+	e.Fields = append(e.Fields, &EventField{Name: name, Type: typ, Value: value})
+	return e
+}
+
+// WithIndexedField adds a field which is a topic of the event
+func (e *Event) WithIndexedField(name, typ string, value []byte) *Event {
+	e.Fields = append(e.Fields, &EventField{Name: name, Type: typ, Value: value, Indexed: true})
+	return e
+}
+
+// Topics returns the values of the indexed fields in the order of declaration
+func (e *Event) Topics() [][]byte {
+	ret := make([][]byte, 0, MaxEventTopics)
+	for _, f := range e.Fields {
+		if f.Indexed {
+			ret = append(ret, f.Value)
+		}
+	}
+	return ret
+}
+
+// Field returns the field with the given name or nil
+func (e *Event) Field(name string) *EventField {
+	for _, f := range e.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (e *Event) Validate() error {
+	if e.Name == "" || strings.ContainsRune(e.Name, '|') {
+		return xerrors.Errorf("invalid event name '%s'", e.Name)
+	}
+	if len(e.Topics()) > MaxEventTopics {
+		return xerrors.Errorf("event '%s' has more than %d indexed fields", e.Name, MaxEventTopics)
+	}
+	names := make(map[string]bool)
+	for _, f := range e.Fields {
+		if f.Name == "" {
+			return xerrors.Errorf("event '%s' has a field without a name", e.Name)
+		}
+		if names[f.Name] {
+			return xerrors.Errorf("event '%s' has duplicate field '%s'", e.Name, f.Name)
+		}
+		names[f.Name] = true
+	}
+	return nil
+}
+
+func EventFromBytes(data []byte) (*Event, error) {
+	return EventFromMarshalUtil(marshalutil.New(data))
+}
+
+func EventFromMarshalUtil(mu *marshalutil.MarshalUtil) (*Event, error) {
+	ret := &Event{}
+	var err error
+	if ret.Name, err = readString(mu); err != nil {
+		return nil, err
+	}
+	numFields, err := mu.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+	ret.Fields = make([]*EventField, numFields)
+	for i := range ret.Fields {
+		f := &EventField{}
+		if f.Name, err = readString(mu); err != nil {
+			return nil, err
+		}
+		if f.Type, err = readString(mu); err != nil {
+			return nil, err
+		}
+		size, err := mu.ReadUint32()
+		if err != nil {
+			return nil, err
+		}
+		if f.Value, err = mu.ReadBytes(int(size)); err != nil {
+			return nil, err
+		}
+		if f.Indexed, err = mu.ReadBool(); err != nil {
+			return nil, err
+		}
+		ret.Fields[i] = f
+	}
+	return ret, nil
+}
+
+func (e *Event) Bytes() []byte {
+	mu := marshalutil.New()
+	writeString(mu, e.Name)
+	mu.WriteUint16(uint16(len(e.Fields)))
+	for _, f := range e.Fields {
+		writeString(mu, f.Name)
+		writeString(mu, f.Type)
+		mu.WriteUint32(uint32(len(f.Value))).
+			WriteBytes(f.Value).
+			WriteBool(f.Indexed)
+	}
+	return mu.Bytes()
+}
@@ -0,0 +1,30 @@
+package iscp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventMarshal(t *testing.T) {
+	ev := NewEvent("transfer").
+		WithIndexedField("from", "String", []byte("alice")).
+		WithIndexedField("to", "String", []byte("bob")).
+		WithField("amount", "Int64", []byte{1, 0, 0, 0, 0, 0, 0, 0})
+	require.NoError(t, ev.Validate())
+	require.EqualValues(t, [][]byte{[]byte("alice"), []byte("bob")}, ev.Topics())
+
+	back, err := EventFromBytes(ev.Bytes())
+	require.NoError(t, err)
+	require.EqualValues(t, ev, back)
+}
+
+func TestEventValidate(t *testing.T) {
+	require.Error(t, NewEvent("").Validate())
+	require.Error(t, NewEvent("dup").WithField("a", "Int64", nil).WithField("a", "Int64", nil).Validate())
+	ev := NewEvent("topics")
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		ev.WithIndexedField(name, "Int64", nil)
+	}
+	require.Error(t, ev.Validate())
+}
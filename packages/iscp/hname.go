@@ -26,9 +26,13 @@ const HnameLength = 4
 // FuncInit is a name of the init function for any smart contract
 const FuncInit = "init"
 
+// FuncMigrate is a name of the optional function called by 'root' right after the contract is upgraded
+const FuncMigrate = "migrate"
+
 // well known hnames
 var (
-	EntryPointInit = Hn(FuncInit)
+	EntryPointInit    = Hn(FuncInit)
+	EntryPointMigrate = Hn(FuncMigrate)
 )
 
 // HnameFromBytes constructor, unmarshalling
@@ -34,6 +34,10 @@ type Request interface {
 	Target() RequestTarget
 	// Timestamp returns a request TX timestamp, if such TX exist, otherwise zero is returned.
 	Timestamp() time.Time
+	// GasBudget is the maximum gas the request may burn. 0 means the default budget
+	GasBudget() uint64
+	// PriorityFee is paid on top of the chain fees to be processed sooner under load. 0 means no priority
+	PriorityFee() uint64
 	// Bytes returns binary representation of the request
 	Bytes() []byte
 	// Hash returns the hash of the request (used for consensus)
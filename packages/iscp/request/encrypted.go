@@ -0,0 +1,107 @@
+package request
+
+import (
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/requestargs"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// The arguments of the encrypted request are sealed in the envelope, encrypted to the public key of the committee.
+// Nobody, including the members of the committee, can see the arguments before the request is ordered
+// in the batch by the ACS. Then a quorum of the committee decrypts them jointly before the VM runs the batch.
+// The envelope is the single argument ParamEncryptedArgs, so the encrypted request travels as any other request
+
+// ParamEncryptedArgs is the only argument of the encrypted request. It is reserved and can't be used by contracts
+const ParamEncryptedArgs = kv.Key("$encrypted")
+
+// EncryptArgs seals the arguments in the envelope, which can only be opened by the committee with the public key
+// for the request from the sender to the target on the chain. The arguments can't reference blobs
+func EncryptArgs(
+	committeePublicKey kyber.Point,
+	chainID *iscp.ChainID,
+	sender ledgerstate.Address,
+	target iscp.RequestTarget,
+	args requestargs.RequestArgs,
+) (requestargs.RequestArgs, error) {
+	params := dict.New()
+	for k, v := range args {
+		if len(k) == 0 || k[0] == '*' {
+			return nil, xerrors.Errorf("EncryptArgs: blob reference '%s' can't be encrypted", k)
+		}
+		params.Set(k[1:], v)
+	}
+	ciphertext, err := tcrypto.Encrypt(tcrypto.DefaultSuite(), committeePublicKey, params.Bytes(),
+		encryptionAdditionalData(chainID, sender, target))
+	if err != nil {
+		return nil, xerrors.Errorf("EncryptArgs: %w", err)
+	}
+	return requestargs.New(nil).AddEncodeSimple(ParamEncryptedArgs, ciphertext), nil
+}
+
+// EncryptedArgs returns the encrypted arguments of the request or false, if the request is not encrypted
+func EncryptedArgs(req iscp.Request) ([]byte, bool) {
+	sreq, ok := req.(SolidifiableRequest)
+	if !ok {
+		return nil, false
+	}
+	args := sreq.Args()
+	if len(args) != 1 {
+		return nil, false
+	}
+	for k, v := range args {
+		if len(k) > 1 && k[0] != '*' && k[1:] == ParamEncryptedArgs {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// CheckEncryptedArgs returns the envelope of the encrypted request, if it was sealed for this request.
+// The committee must not produce decryption shares of the envelope, which was copied from another request
+func CheckEncryptedArgs(dkShare *tcrypto.DKShare, chainID *iscp.ChainID, req iscp.Request) ([]byte, error) {
+	ciphertext, ok := EncryptedArgs(req)
+	if !ok {
+		return nil, xerrors.New("CheckEncryptedArgs: the request is not encrypted")
+	}
+	if err := dkShare.CheckCiphertext(ciphertext, EncryptionAdditionalData(chainID, req)); err != nil {
+		return nil, xerrors.Errorf("CheckEncryptedArgs: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// EncryptionAdditionalData binds the encrypted arguments to the request, so they can't be replayed in another one
+func EncryptionAdditionalData(chainID *iscp.ChainID, req iscp.Request) []byte {
+	return encryptionAdditionalData(chainID, req.SenderAddress(), req.Target())
+}
+
+func encryptionAdditionalData(chainID *iscp.ChainID, sender ledgerstate.Address, target iscp.RequestTarget) []byte {
+	ret := make([]byte, 0, 2*ledgerstate.AddressLength+2*iscp.HnameLength)
+	ret = append(ret, chainID.Bytes()...)
+	ret = append(ret, sender.Bytes()...)
+	ret = append(ret, target.Contract.Bytes()...)
+	return append(ret, target.EntryPoint.Bytes()...)
+}
+
+// SetDecryptedArgs replaces the params of the encrypted request with the decrypted arguments
+func SetDecryptedArgs(req iscp.Request, plaintext []byte) error {
+	params, err := dict.FromBytes(plaintext)
+	if err != nil {
+		return xerrors.Errorf("SetDecryptedArgs: %w", err)
+	}
+	req.(SolidifiableRequest).SetParams(params)
+	return nil
+}
+
+// IsDecrypted returns false if the request is encrypted and its params are still the envelope
+func IsDecrypted(req iscp.Request) bool {
+	if _, encrypted := EncryptedArgs(req); !encrypted {
+		return true
+	}
+	params, ok := req.Params()
+	return ok && !params.MustHas(ParamEncryptedArgs)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate/utxoutil"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
@@ -22,6 +23,7 @@ import (
 const (
 	onLedgerRequestType byte = iota
 	offLedgerRequestType
+	offLedgerSecp256k1RequestType
 )
 
 // FromMarshalUtil re-creates request from bytes. First byte is treated as type of the request
@@ -35,7 +37,9 @@ func FromMarshalUtil(mu *marshalutil.MarshalUtil) (iscp.Request, error) {
 	case onLedgerRequestType:
 		return onLedgerFromMarshalUtil(mu)
 	case offLedgerRequestType:
-		return offLedgerFromMarshalUtil(mu)
+		return offLedgerFromMarshalUtil(mu, SignatureSchemeED25519)
+	case offLedgerSecp256k1RequestType:
+		return offLedgerFromMarshalUtil(mu, SignatureSchemeSecp256k1)
 	}
 	return nil, xerrors.Errorf("invalid Request Type")
 }
@@ -54,6 +58,10 @@ type Metadata struct {
 	requestNonce uint8
 	// request arguments, not decoded yet wrt blobRefs
 	args requestargs.RequestArgs
+	// maximum gas the request may burn, 0 means default
+	gasBudget uint64
+	// fee paid out of the transferred tokens to be processed sooner
+	priorityFee uint64
 }
 
 func NewMetadata() *Metadata {
@@ -97,6 +105,16 @@ func (p *Metadata) WithArgs(args requestargs.RequestArgs) *Metadata {
 	return p
 }
 
+func (p *Metadata) WithGasBudget(gasBudget uint64) *Metadata {
+	p.gasBudget = gasBudget
+	return p
+}
+
+func (p *Metadata) WithPriorityFee(priorityFee uint64) *Metadata {
+	p.priorityFee = priorityFee
+	return p
+}
+
 func (p *Metadata) Clone() *Metadata {
 	ret := *p
 	ret.args = p.args.Clone()
@@ -139,6 +157,20 @@ func (p *Metadata) Args() requestargs.RequestArgs {
 	return p.args
 }
 
+func (p *Metadata) GasBudget() uint64 {
+	if !p.ParsedOk() {
+		return 0
+	}
+	return p.gasBudget
+}
+
+func (p *Metadata) PriorityFee() uint64 {
+	if !p.ParsedOk() {
+		return 0
+	}
+	return p.priorityFee
+}
+
 func (p *Metadata) Bytes() []byte {
 	mu := marshalutil.New()
 	p.WriteToMarshalUtil(mu)
@@ -151,6 +183,8 @@ func (p *Metadata) WriteToMarshalUtil(mu *marshalutil.MarshalUtil) {
 		Write(p.entryPoint).
 		WriteByte(p.requestNonce)
 	p.args.WriteToMarshalUtil(mu)
+	mu.WriteUint64(p.gasBudget).
+		WriteUint64(p.priorityFee)
 }
 
 func (p *Metadata) ReadFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
@@ -170,9 +204,25 @@ func (p *Metadata) ReadFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
 	if p.args, err = requestargs.FromMarshalUtil(mu); err != nil {
 		return err
 	}
+	// the gas budget and the priority fee were appended to the layout later: metadata written without them
+	// ends here and has the default values
+	if p.gasBudget, err = readOptionalUint64(mu); err != nil {
+		return err
+	}
+	if p.priorityFee, err = readOptionalUint64(mu); err != nil {
+		return err
+	}
 	return nil
 }
 
+// readOptionalUint64 reads the trailing uint64 or returns 0 if there is no more data
+func readOptionalUint64(mu *marshalutil.MarshalUtil) (uint64, error) {
+	if mu.ReadOffset() >= len(mu.Bytes()) {
+		return 0, nil
+	}
+	return mu.ReadUint64()
+}
+
 // endregion
 
 // region OnLedger //////////////////////////////////////////////////////////////////
@@ -314,6 +364,14 @@ func (req *OnLedger) Timestamp() time.Time {
 	return req.txTimestamp
 }
 
+func (req *OnLedger) GasBudget() uint64 {
+	return req.requestMetadata.GasBudget()
+}
+
+func (req *OnLedger) PriorityFee() uint64 {
+	return req.requestMetadata.PriorityFee()
+}
+
 func (req *OnLedger) TimeLock() time.Time {
 	return req.outputObj.TimeLock()
 }
@@ -394,11 +452,17 @@ type OffLedger struct {
 	contract   iscp.Hname
 	entryPoint iscp.Hname
 	params     atomic.Value // mutable
+	scheme     SignatureScheme
 	publicKey  ed25519.PublicKey
 	sender     ledgerstate.Address
 	signature  ed25519.Signature
-	nonce      uint64
-	transfer   colored.Balances
+	// sender and signature of the requests signed with secp256k1 keys
+	ethSender    common.Address
+	ethSignature []byte
+	nonce        uint64
+	transfer     colored.Balances
+	gasBudget    uint64
+	priorityFee  uint64
 }
 
 // implements iscp.Request interface
@@ -418,14 +482,19 @@ func NewOffLedger(chainID *iscp.ChainID, contract, entryPoint iscp.Hname, args r
 // Bytes encodes request as bytes with first type byte
 func (req *OffLedger) Bytes() []byte {
 	mu := marshalutil.New()
-	mu.WriteByte(offLedgerRequestType)
+	if req.scheme == SignatureSchemeSecp256k1 {
+		mu.WriteByte(offLedgerSecp256k1RequestType)
+	} else {
+		mu.WriteByte(offLedgerRequestType)
+	}
 	req.writeToMarshalUtil(mu)
 	return mu.Bytes()
 }
 
-// offLedgerFromMarshalUtil creates a request from previously serialized bytes. Does not expects type byte
-func offLedgerFromMarshalUtil(mu *marshalutil.MarshalUtil) (req *OffLedger, err error) {
-	req = &OffLedger{}
+// offLedgerFromMarshalUtil creates a request from previously serialized bytes. Does not expects type byte,
+// the signature scheme is determined by it
+func offLedgerFromMarshalUtil(mu *marshalutil.MarshalUtil, scheme SignatureScheme) (req *OffLedger, err error) {
+	req = &OffLedger{scheme: scheme}
 	if err := req.readFromMarshalUtil(mu); err != nil {
 		return nil, err
 	}
@@ -434,6 +503,12 @@ func offLedgerFromMarshalUtil(mu *marshalutil.MarshalUtil) (req *OffLedger, err
 
 func (req *OffLedger) writeToMarshalUtil(mu *marshalutil.MarshalUtil) {
 	req.writeEssenceToMarshalUtil(mu)
+	if req.scheme == SignatureSchemeSecp256k1 {
+		sig := make([]byte, secp256k1SignatureLength)
+		copy(sig, req.ethSignature)
+		mu.WriteBytes(sig)
+		return
+	}
 	mu.WriteBytes(req.signature[:])
 }
 
@@ -441,6 +516,14 @@ func (req *OffLedger) readFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
 	if err := req.readEssenceFromMarshalUtil(mu); err != nil {
 		return err
 	}
+	if req.scheme == SignatureSchemeSecp256k1 {
+		sig, err := mu.ReadBytes(secp256k1SignatureLength)
+		if err != nil {
+			return err
+		}
+		req.ethSignature = sig
+		return nil
+	}
 	sig, err := mu.ReadBytes(len(req.signature))
 	if err != nil {
 		return err
@@ -453,10 +536,16 @@ func (req *OffLedger) writeEssenceToMarshalUtil(mu *marshalutil.MarshalUtil) {
 	mu.Write(req.chainID).
 		Write(req.contract).
 		Write(req.entryPoint).
-		Write(req.args).
-		WriteBytes(req.publicKey[:]).
-		WriteUint64(req.nonce).
-		Write(req.transfer)
+		Write(req.args)
+	if req.scheme == SignatureSchemeSecp256k1 {
+		mu.WriteBytes(req.ethSender[:])
+	} else {
+		mu.WriteBytes(req.publicKey[:])
+	}
+	mu.WriteUint64(req.nonce).
+		Write(req.transfer).
+		WriteUint64(req.gasBudget).
+		WriteUint64(req.priorityFee)
 }
 
 func (req *OffLedger) readEssenceFromMarshalUtil(mu *marshalutil.MarshalUtil) error {
@@ -476,17 +565,31 @@ func (req *OffLedger) readEssenceFromMarshalUtil(mu *marshalutil.MarshalUtil) er
 		return err
 	}
 	req.args = requestargs.New(a)
-	pk, err := mu.ReadBytes(len(req.publicKey))
-	if err != nil {
-		return err
+	if req.scheme == SignatureSchemeSecp256k1 {
+		sender, err := mu.ReadBytes(common.AddressLength)
+		if err != nil {
+			return err
+		}
+		req.ethSender = common.BytesToAddress(sender)
+	} else {
+		pk, err := mu.ReadBytes(len(req.publicKey))
+		if err != nil {
+			return err
+		}
+		copy(req.publicKey[:], pk)
 	}
-	copy(req.publicKey[:], pk)
 	if req.nonce, err = mu.ReadUint64(); err != nil {
 		return err
 	}
 	if req.transfer, err = colored.BalancesFromMarshalUtil(mu); err != nil {
 		return err
 	}
+	if req.gasBudget, err = mu.ReadUint64(); err != nil {
+		return err
+	}
+	if req.priorityFee, err = mu.ReadUint64(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -497,12 +600,27 @@ func (req *OffLedger) Hash() [32]byte {
 
 // Sign signs essence
 func (req *OffLedger) Sign(keyPair *ed25519.KeyPair) {
+	req.scheme = SignatureSchemeED25519
 	req.publicKey = keyPair.PublicKey
 	mu := marshalutil.New()
 	req.writeEssenceToMarshalUtil(mu)
 	req.signature = keyPair.PrivateKey.Sign(mu.Bytes())
 }
 
+// SignWith signs essence with the function holding the private key of the public key, e.g. an external signer
+func (req *OffLedger) SignWith(publicKey ed25519.PublicKey, sign func(data []byte) (ed25519.Signature, error)) error {
+	req.scheme = SignatureSchemeED25519
+	req.publicKey = publicKey
+	mu := marshalutil.New()
+	req.writeEssenceToMarshalUtil(mu)
+	sig, err := sign(mu.Bytes())
+	if err != nil {
+		return err
+	}
+	req.signature = sig
+	return nil
+}
+
 // Tokens returns the transfers passed to the request
 func (req *OffLedger) Tokens() colored.Balances {
 	return req.transfer
@@ -514,8 +632,23 @@ func (req *OffLedger) WithTransfer(transfer colored.Balances) *OffLedger {
 	return req
 }
 
+// WithGasBudget sets the maximum gas the request may burn. Must be set before signing
+func (req *OffLedger) WithGasBudget(gasBudget uint64) *OffLedger {
+	req.gasBudget = gasBudget
+	return req
+}
+
+// WithPriorityFee sets the fee paid out of the transfer to be processed sooner. Must be set before signing
+func (req *OffLedger) WithPriorityFee(priorityFee uint64) *OffLedger {
+	req.priorityFee = priorityFee
+	return req
+}
+
 // VerifySignature verifies essence signature
 func (req *OffLedger) VerifySignature() bool {
+	if req.scheme == SignatureSchemeSecp256k1 {
+		return req.verifySecp256k1Signature()
+	}
 	mu := marshalutil.New()
 	req.writeEssenceToMarshalUtil(mu)
 	return req.publicKey.VerifySignature(mu.Bytes(), req.signature)
@@ -566,17 +699,36 @@ func (req *OffLedger) SenderAccount() *iscp.AgentID {
 	return iscp.NewAgentID(req.SenderAddress(), 0)
 }
 
+// SenderAddress returns the address of the sender. The sender of the request signed with a secp256k1 key
+// is represented by the address derived from its Ethereum address
 func (req *OffLedger) SenderAddress() ledgerstate.Address {
 	if req.sender == nil {
-		req.sender = ledgerstate.NewED25519Address(req.publicKey)
+		if req.scheme == SignatureSchemeSecp256k1 {
+			req.sender = AddressFromEthereum(req.ethSender)
+		} else {
+			req.sender = ledgerstate.NewED25519Address(req.publicKey)
+		}
 	}
 	return req.sender
 }
 
+// SignatureScheme returns the scheme the request is signed with
+func (req *OffLedger) SignatureScheme() SignatureScheme {
+	return req.scheme
+}
+
 func (req *OffLedger) Target() iscp.RequestTarget {
 	return iscp.NewRequestTarget(req.contract, req.entryPoint)
 }
 
+func (req *OffLedger) GasBudget() uint64 {
+	return req.gasBudget
+}
+
+func (req *OffLedger) PriorityFee() uint64 {
+	return req.priorityFee
+}
+
 func (req *OffLedger) Timestamp() time.Time {
 	// no request TX, return zero time
 	return time.Time{}
@@ -1,16 +1,23 @@
 package request
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"github.com/iotaledger/wasp/packages/iscp/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
 )
 
 func TestMetadata(t *testing.T) {
@@ -18,13 +25,30 @@ func TestMetadata(t *testing.T) {
 		sender := iscp.Hn("sender")
 		target := iscp.Hn("target")
 		ep := iscp.Hn("entryp")
-		md := NewMetadata().WithSender(sender).WithTarget(target).WithEntryPoint(ep)
+		md := NewMetadata().WithSender(sender).WithTarget(target).WithEntryPoint(ep).WithGasBudget(1000).WithPriorityFee(10)
 
 		data := md.Bytes()
 		back := MetadataFromBytes(data)
 		require.True(t, back.ParsedOk())
 		require.NoError(t, back.ParsedError())
 		require.EqualValues(t, md.Bytes(), back.Bytes())
+		require.EqualValues(t, 1000, back.GasBudget())
+		require.EqualValues(t, 10, back.PriorityFee())
+	})
+	t.Run("without gas budget and priority fee", func(t *testing.T) {
+		md := NewMetadata().WithTarget(iscp.Hn("target")).WithEntryPoint(iscp.Hn("entryp")).WithGasBudget(1000)
+		data := md.Bytes()
+
+		back := MetadataFromBytes(data[:len(data)-8])
+		require.True(t, back.ParsedOk())
+		require.EqualValues(t, 1000, back.GasBudget())
+		require.EqualValues(t, 0, back.PriorityFee())
+
+		back = MetadataFromBytes(data[:len(data)-16])
+		require.True(t, back.ParsedOk())
+		require.EqualValues(t, md.TargetContract(), back.TargetContract())
+		require.EqualValues(t, 0, back.GasBudget())
+		require.EqualValues(t, 0, back.PriorityFee())
 	})
 	t.Run("parse  error", func(t *testing.T) {
 		var data []byte
@@ -90,12 +114,152 @@ func TestOffLedger(t *testing.T) {
 		target := iscp.Hn("target")
 		ep := iscp.Hn("entry point")
 		args := requestargs.New()
-		req := NewOffLedger(iscp.RandomChainID(), target, ep, args)
+		req := NewOffLedger(iscp.RandomChainID(), target, ep, args).WithPriorityFee(10)
 		reqBack, err := FromMarshalUtil(marshalutil.New(req.Bytes()))
 		require.NoError(t, err)
 		_, ok := reqBack.(*OffLedger)
 		require.True(t, ok)
+		require.EqualValues(t, 10, reqBack.PriorityFee())
 
 		require.EqualValues(t, req.Bytes(), reqBack.Bytes())
 	})
+	t.Run("sign with", func(t *testing.T) {
+		keyPair := ed25519.GenerateKeyPair()
+		req := NewOffLedger(iscp.RandomChainID(), iscp.Hn("target"), iscp.Hn("entry point"), requestargs.New())
+		err := req.SignWith(keyPair.PublicKey, func(data []byte) (ed25519.Signature, error) {
+			return keyPair.PrivateKey.Sign(data), nil
+		})
+		require.NoError(t, err)
+		require.True(t, req.VerifySignature())
+
+		signedWith := req.Bytes()
+		req.Sign(&keyPair)
+		require.EqualValues(t, req.Bytes(), signedWith)
+
+		err = req.SignWith(keyPair.PublicKey, func(data []byte) (ed25519.Signature, error) {
+			return ed25519.Signature{}, xerrors.New("signer is not available")
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestOffLedgerSecp256k1(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ethAddress := crypto.PubkeyToAddress(key.PublicKey)
+
+	newRequest := func() *OffLedger {
+		return NewOffLedger(iscp.RandomChainID(), iscp.Hn("target"), iscp.Hn("entry point"), requestargs.New()).
+			WithPriorityFee(10)
+	}
+
+	t.Run("marshal", func(t *testing.T) {
+		req := newRequest()
+		req.SignSecp256k1(key)
+		require.True(t, req.VerifySignature())
+
+		reqBack, err := FromMarshalUtil(marshalutil.New(req.Bytes()))
+		require.NoError(t, err)
+		offLedger, ok := reqBack.(*OffLedger)
+		require.True(t, ok)
+		require.EqualValues(t, SignatureSchemeSecp256k1, offLedger.SignatureScheme())
+		sender, ok := offLedger.EthereumSender()
+		require.True(t, ok)
+		require.EqualValues(t, ethAddress, sender)
+		require.True(t, offLedger.SenderAddress().Equals(AddressFromEthereum(ethAddress)))
+		require.True(t, offLedger.VerifySignature())
+		require.EqualValues(t, req.Bytes(), reqBack.Bytes())
+		require.EqualValues(t, req.ID(), reqBack.ID())
+	})
+	t.Run("tampered", func(t *testing.T) {
+		req := newRequest()
+		req.SignSecp256k1(key)
+		req.WithPriorityFee(11)
+		require.False(t, req.VerifySignature())
+	})
+	t.Run("other sender", func(t *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		req := newRequest().WithEthereumSender(crypto.PubkeyToAddress(other.PublicKey))
+		sig, err := crypto.Sign(req.secp256k1SigningHash(), key)
+		require.NoError(t, err)
+		require.Error(t, req.SetSecp256k1Signature(sig))
+	})
+	t.Run("wallet signature", func(t *testing.T) {
+		req := newRequest().WithEthereumSender(ethAddress)
+		h := req.EssenceHash()
+		sig, err := crypto.Sign(accounts.TextHash(h[:]), key)
+		require.NoError(t, err)
+		sig[crypto.RecoveryIDOffset] += 27
+		require.NoError(t, req.SetSecp256k1Signature(sig))
+		require.True(t, req.VerifySignature())
+	})
+	t.Run("malleable signature", func(t *testing.T) {
+		req := newRequest()
+		req.SignSecp256k1(key)
+		// (r, N-s, v^1) is a valid signature of the same message
+		s := new(big.Int).SetBytes(req.ethSignature[32:64])
+		s.Sub(crypto.S256().Params().N, s)
+		s.FillBytes(req.ethSignature[32:64])
+		req.ethSignature[crypto.RecoveryIDOffset] ^= 1
+		require.False(t, req.VerifySignature())
+	})
+}
+
+func TestEncryptedArgs(t *testing.T) {
+	suite := tcrypto.DefaultSuite()
+	x := suite.G2().Scalar().Pick(suite.RandomStream())
+	pub := suite.G2().Point().Mul(x, nil)
+	dks, err := tcrypto.NewDKShare(0, 1, 1, pub, nil, []kyber.Point{pub}, x)
+	require.NoError(t, err)
+	dks, err = tcrypto.DKShareFromBytes(dks.Bytes(), suite)
+	require.NoError(t, err)
+
+	keyPair := ed25519.GenerateKeyPair()
+	chainID := iscp.RandomChainID()
+	sender := ledgerstate.NewED25519Address(keyPair.PublicKey)
+	target := iscp.NewRequestTarget(iscp.Hn("fairauction"), iscp.Hn("placeBid"))
+	args, err := EncryptArgs(pub, chainID, sender, target, requestargs.New().AddEncodeSimple("bid", codec.EncodeUint64(42)))
+	require.NoError(t, err)
+
+	req := NewOffLedger(chainID, target.Contract, target.EntryPoint, args)
+	req.Sign(&keyPair)
+	ok, err := SolidifyArgs(req, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.False(t, IsDecrypted(req))
+
+	ciphertext, ok := EncryptedArgs(req)
+	require.True(t, ok)
+	decShare, err := dks.DecryptShare(ciphertext, EncryptionAdditionalData(chainID, req))
+	require.NoError(t, err)
+	plaintext, err := dks.Decrypt(ciphertext, []*tcrypto.DecryptionShare{decShare}, EncryptionAdditionalData(chainID, req))
+	require.NoError(t, err)
+	// the envelope is bound to the target of the request
+	_, err = dks.Decrypt(ciphertext, []*tcrypto.DecryptionShare{decShare},
+		encryptionAdditionalData(chainID, sender, iscp.NewRequestTarget(target.Contract, iscp.Hn("other"))))
+	require.Error(t, err)
+
+	require.NoError(t, SetDecryptedArgs(req, plaintext))
+	require.True(t, IsDecrypted(req))
+	params, _ := req.Params()
+	bid, err := codec.DecodeUint64(params.MustGet("bid"))
+	require.NoError(t, err)
+	require.EqualValues(t, 42, bid)
+
+	// the envelope copied to the request of another sender gets no decryption shares
+	otherKeyPair := ed25519.GenerateKeyPair()
+	copied := NewOffLedger(chainID, target.Contract, target.EntryPoint, args)
+	copied.Sign(&otherKeyPair)
+	_, err = CheckEncryptedArgs(dks, chainID, req)
+	require.NoError(t, err)
+	_, err = CheckEncryptedArgs(dks, chainID, copied)
+	require.Error(t, err)
+	_, err = dks.DecryptShare(ciphertext, EncryptionAdditionalData(chainID, copied))
+	require.Error(t, err)
+
+	blobArgs := requestargs.New()
+	blobArgs.AddAsBlobRef("blob", []byte("data"))
+	_, err = EncryptArgs(pub, chainID, sender, target, blobArgs)
+	require.Error(t, err)
 }
@@ -0,0 +1,131 @@
+package request
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+)
+
+// SignatureScheme is the signature scheme of the off-ledger request
+type SignatureScheme byte
+
+const (
+	SignatureSchemeED25519 SignatureScheme = iota
+	// SignatureSchemeSecp256k1 is the scheme of Ethereum keys. The request is signed as a personal message (EIP-191),
+	// so it can be signed by the Ethereum wallets
+	SignatureSchemeSecp256k1
+)
+
+func (s SignatureScheme) String() string {
+	switch s {
+	case SignatureSchemeED25519:
+		return "ed25519"
+	case SignatureSchemeSecp256k1:
+		return "secp256k1"
+	}
+	return fmt.Sprintf("unknown(%d)", byte(s))
+}
+
+// secp256k1SignatureLength is the length of the [R || S || V] signature
+const secp256k1SignatureLength = crypto.SignatureLength
+
+// AddressFromEthereum derives the address which represents the Ethereum address on the chain.
+// Nobody holds the key of the derived address on L1, so the funds can't be withdrawn to it
+func AddressFromEthereum(addr common.Address) ledgerstate.Address {
+	digest := blake2b.Sum256(append([]byte(SignatureSchemeSecp256k1.String()), addr[:]...))
+	ret, _, err := ledgerstate.ED25519AddressFromBytes(append([]byte{byte(ledgerstate.ED25519AddressType)}, digest[:]...))
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// AgentIDFromEthereum returns the agent ID of the sender of the off-ledger requests signed with the Ethereum key
+func AgentIDFromEthereum(addr common.Address) *iscp.AgentID {
+	return iscp.NewAgentID(AddressFromEthereum(addr), 0)
+}
+
+// WithEthereumSender makes the request to be signed by the secp256k1 key of the Ethereum address.
+// Must be set before signing
+func (req *OffLedger) WithEthereumSender(addr common.Address) *OffLedger {
+	req.scheme = SignatureSchemeSecp256k1
+	req.ethSender = addr
+	req.sender = nil
+	return req
+}
+
+// EthereumSender returns the Ethereum address of the sender of the request signed with the secp256k1 key
+func (req *OffLedger) EthereumSender() (common.Address, bool) {
+	return req.ethSender, req.scheme == SignatureSchemeSecp256k1
+}
+
+// EssenceHash is the hash of the request without the signature. The Ethereum wallets sign it as a personal message
+func (req *OffLedger) EssenceHash() hashing.HashValue {
+	mu := marshalutil.New()
+	req.writeEssenceToMarshalUtil(mu)
+	return hashing.HashData(mu.Bytes())
+}
+
+// SignSecp256k1 signs the request with the secp256k1 key, the same way as the Ethereum wallets do
+func (req *OffLedger) SignSecp256k1(key *ecdsa.PrivateKey) {
+	req.WithEthereumSender(crypto.PubkeyToAddress(key.PublicKey))
+	sig, err := crypto.Sign(req.secp256k1SigningHash(), key)
+	if err != nil {
+		panic(err)
+	}
+	req.ethSignature = sig
+}
+
+// SetSecp256k1Signature sets the signature produced by an Ethereum wallet for the EssenceHash.
+// Both the 0/1 and 27/28 forms of V are accepted
+func (req *OffLedger) SetSecp256k1Signature(sig []byte) error {
+	if req.scheme != SignatureSchemeSecp256k1 {
+		return xerrors.New("the Ethereum sender of the request is not set")
+	}
+	if len(sig) != secp256k1SignatureLength {
+		return xerrors.Errorf("wrong length of the signature: %d", len(sig))
+	}
+	sig = append([]byte(nil), sig...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	req.ethSignature = sig
+	if !req.VerifySignature() {
+		return xerrors.New("the signature doesn't match the sender")
+	}
+	return nil
+}
+
+// secp256k1SigningHash is the hash of the EIP-191 personal message with the essence hash
+func (req *OffLedger) secp256k1SigningHash() []byte {
+	h := req.EssenceHash()
+	return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(h))), h[:])
+}
+
+// verifySecp256k1Signature checks the signature recovers the sender. Only the canonical form
+// of the signature is accepted, otherwise the same request could be submitted with different IDs
+func (req *OffLedger) verifySecp256k1Signature() bool {
+	sig := req.ethSignature
+	if len(sig) != secp256k1SignatureLength {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[crypto.RecoveryIDOffset], r, s, true) {
+		return false
+	}
+	pub, err := crypto.SigToPub(req.secp256k1SigningHash(), sig)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*pub) == req.ethSender
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package iscp
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+)
+
+// RequestErrorCode classifies the reason why the request failed
+type RequestErrorCode uint16
+
+const (
+	// RequestErrorContract is an error returned by the smart contract
+	RequestErrorContract = RequestErrorCode(iota)
+	// RequestErrorPanic is a panic in the smart contract or in the VM
+	RequestErrorPanic
+	// RequestErrorNotEnoughGas means the gas budget of the request was exceeded
+	RequestErrorNotEnoughGas
+	// RequestErrorNotEnoughFees means the request did not pay enough fees and was not called
+	RequestErrorNotEnoughFees
+	// RequestErrorInvalidRequest means the off-ledger request was rejected before the call,
+	// for example because of the unknown sender account or the replayed nonce
+	RequestErrorInvalidRequest
+)
+
+var requestErrorCodeNames = map[RequestErrorCode]string{
+	RequestErrorContract:       "contract",
+	RequestErrorPanic:          "panic",
+	RequestErrorNotEnoughGas:   "not enough gas",
+	RequestErrorNotEnoughFees:  "not enough fees",
+	RequestErrorInvalidRequest: "invalid request",
+}
+
+func (c RequestErrorCode) String() string {
+	if name, ok := requestErrorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("code %d", uint16(c))
+}
+
+// RequestError is the machine readable reason of the failed request. It is recorded in the request receipt.
+// Smart contracts may return it from the entry point to report a specific error code and parameters
+type RequestError struct {
+	// Contract is the contract which reported the error
+	Contract Hname
+	Code     RequestErrorCode
+	Message  string
+	// Params are optional parameters of the error, for example the values the message refers to
+	Params []string
+}
+
+var _ error = &RequestError{}
+
+func NewRequestError(contract Hname, code RequestErrorCode, message string, params ...string) *RequestError {
+	return &RequestError{
+		Contract: contract,
+		Code:     code,
+		Message:  message,
+		Params:   params,
+	}
+}
+
+func RequestErrorFromBytes(data []byte) (*RequestError, error) {
+	return RequestErrorFromMarshalUtil(marshalutil.New(data))
+}
+
+func RequestErrorFromMarshalUtil(mu *marshalutil.MarshalUtil) (*RequestError, error) {
+	ret := &RequestError{}
+	var err error
+	if ret.Contract, err = HnameFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	code, err := mu.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+	ret.Code = RequestErrorCode(code)
+	if ret.Message, err = readString(mu); err != nil {
+		return nil, err
+	}
+	numParams, err := mu.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+	if numParams > 0 {
+		ret.Params = make([]string, numParams)
+	}
+	for i := range ret.Params {
+		if ret.Params[i], err = readString(mu); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (e *RequestError) Bytes() []byte {
+	mu := marshalutil.New()
+	mu.Write(e.Contract).
+		WriteUint16(uint16(e.Code))
+	writeString(mu, e.Message)
+	mu.WriteUint16(uint16(len(e.Params)))
+	for _, p := range e.Params {
+		writeString(mu, p)
+	}
+	return mu.Bytes()
+}
+
+// Error returns the message, so the RequestError reads the same as the plain error
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+func (e *RequestError) String() string {
+	ret := fmt.Sprintf("%s error in contract %s: %s", e.Code, e.Contract, e.Message)
+	if len(e.Params) > 0 {
+		ret += fmt.Sprintf(" %v", e.Params)
+	}
+	return ret
+}
+
+func writeString(mu *marshalutil.MarshalUtil, s string) {
+	mu.WriteUint16(uint16(len(s))).WriteBytes([]byte(s))
+}
+
+func readString(mu *marshalutil.MarshalUtil) (string, error) {
+	size, err := mu.ReadUint16()
+	if err != nil {
+		return "", err
+	}
+	ret, err := mu.ReadBytes(int(size))
+	if err != nil {
+		return "", err
+	}
+	return string(ret), nil
+}
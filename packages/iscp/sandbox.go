@@ -59,8 +59,14 @@ type Sandbox interface {
 	DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error
 	// Event publishes "vmmsg" message through Publisher on nanomsg. It also logs locally, but it is not the same thing
 	Event(msg string)
+	// EmitEvent stores the typed event in the blocklog and indexes it by its name and indexed fields
+	EmitEvent(event *Event)
 	// GetEntropy 32 random bytes based on the hash of the current state transaction
 	GetEntropy() hashing.HashValue // 32 bytes of deterministic and unpredictably random data
+	// GetRandomSeed 32 bytes derived from the random beacon of the block, the threshold signature of the committee
+	// over the chain ID and the block index. Unlike GetEntropy, it can't be influenced by the proposer of the block.
+	// The seed is the same for all requests of the block
+	GetRandomSeed() hashing.HashValue
 	// IncomingTransfer return colored balances transferred by the call. They are already accounted into the Balances()
 	IncomingTransfer() colored.Balances
 	// Minted represents new colored tokens which has been minted in the request transaction
@@ -73,6 +79,10 @@ type Sandbox interface {
 	BlockContext(construct func(sandbox Sandbox) interface{}, onClose func(interface{})) interface{}
 	// properties of the anchor output
 	StateAnchor() StateAnchor
+	// BurnGas charges the request with the gas. Panics if the gas budget of the request is exceeded
+	BurnGas(gas uint64)
+	// GasBudgetLeft is the gas the request still may burn
+	GasBudgetLeft() uint64
 }
 
 // properties of the anchor output/transaction in the current context
@@ -6,6 +6,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/iscp/colored"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
@@ -43,6 +44,10 @@ func Encode(v interface{}) []byte { //nolint:funlen
 		return EncodeHashValue(vt)
 	case ledgerstate.Address:
 		return EncodeAddress(vt)
+	case common.Address:
+		return EncodeEthereumAddress(vt)
+	case *common.Address:
+		return EncodeEthereumAddress(*vt)
 	case *colored.Color:
 		return EncodeColor(*vt)
 	case colored.Color:
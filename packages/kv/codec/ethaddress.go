@@ -0,0 +1,23 @@
+package codec
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+func DecodeEthereumAddress(b []byte, def ...common.Address) (common.Address, error) {
+	if b == nil {
+		if len(def) == 0 {
+			return common.Address{}, xerrors.Errorf("cannot decode nil bytes")
+		}
+		return def[0], nil
+	}
+	if len(b) != common.AddressLength {
+		return common.Address{}, xerrors.Errorf("%d bytes expected for Ethereum address", common.AddressLength)
+	}
+	return common.BytesToAddress(b), nil
+}
+
+func EncodeEthereumAddress(value common.Address) []byte {
+	return value.Bytes()
+}
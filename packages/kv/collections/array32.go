@@ -61,6 +61,11 @@ func array32ElemKey(name string, idx uint32) kv.Key {
 	return kv.Key(buf.Bytes())
 }
 
+// Array32SizeKey returns the KVStore key of the length of the array
+func Array32SizeKey(name string) kv.Key {
+	return array32SizeKey(name)
+}
+
 // Array32RangeKeys returns the KVStore keys for the items between [from, to) (`to` being not inclusive),
 // assuming it has `length` elements.
 func Array32RangeKeys(name string, length, from, to uint32) []kv.Key {
@@ -7,6 +7,7 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"golang.org/x/xerrors"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
@@ -165,6 +166,17 @@ func (p *Decoder) MustGetHashValue(key kv.Key, def ...hashing.HashValue) hashing
 	return ret
 }
 
+func (p *Decoder) GetEthereumAddress(key kv.Key, def ...common.Address) (common.Address, error) {
+	v, err := codec.DecodeEthereumAddress(p.kv.MustGet(key), def...)
+	return v, p.wrapError(key, err)
+}
+
+func (p *Decoder) MustGetEthereumAddress(key kv.Key, def ...common.Address) common.Address {
+	ret, err := p.GetEthereumAddress(key, def...)
+	p.check(err)
+	return ret
+}
+
 func (p *Decoder) GetAddress(key kv.Key, def ...ledgerstate.Address) (ledgerstate.Address, error) {
 	v, err := codec.DecodeAddress(p.kv.MustGet(key), def...)
 	return v, p.wrapError(key, err)
@@ -26,6 +26,9 @@ type MempoolMetrics interface {
 	CountRequestOut()
 	RecordRequestProcessingTime(iscp.RequestID, time.Duration)
 	CountBlocksPerChain()
+	CountRequestRejected(reason string)
+	CountRequestEvicted()
+	RecordMempoolSize(requests, bytes int)
 }
 
 type ConsensusMetrics interface {
@@ -79,6 +82,19 @@ func (c *chainMetricsObj) CountBlocksPerChain() {
 	c.metrics.blocksPerChain.With(prometheus.Labels{"chain": c.chainID.String()}).Inc()
 }
 
+func (c *chainMetricsObj) CountRequestRejected(reason string) {
+	c.metrics.rejectedRequestCounter.With(prometheus.Labels{"chain": c.chainID.String(), "reason": reason}).Inc()
+}
+
+func (c *chainMetricsObj) CountRequestEvicted() {
+	c.metrics.evictedRequestCounter.With(prometheus.Labels{"chain": c.chainID.String()}).Inc()
+}
+
+func (c *chainMetricsObj) RecordMempoolSize(requests, bytes int) {
+	c.metrics.mempoolSize.With(prometheus.Labels{"chain": c.chainID.String()}).Set(float64(requests))
+	c.metrics.mempoolBytes.With(prometheus.Labels{"chain": c.chainID.String()}).Set(float64(bytes))
+}
+
 func (c *chainMetricsObj) RecordBlockSize(blockIndex uint32, blockSize float64) {
 	c.metrics.blockSizes.With(prometheus.Labels{"chain": c.chainID.String(), "block_index": fmt.Sprintf("%d", blockIndex)}).Set(blockSize)
 }
@@ -107,4 +123,10 @@ func (m *defaultChainMetrics) CountVMRuns() {}
 
 func (m *defaultChainMetrics) CountBlocksPerChain() {}
 
+func (m *defaultChainMetrics) CountRequestRejected(_ string) {}
+
+func (m *defaultChainMetrics) CountRequestEvicted() {}
+
+func (m *defaultChainMetrics) RecordMempoolSize(_, _ int) {}
+
 func (m *defaultChainMetrics) RecordBlockSize(_ uint32, _ float64) {}
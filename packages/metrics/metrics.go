@@ -21,6 +21,10 @@ type Metrics struct {
 	offLedgerRequestCounter *prometheus.CounterVec
 	onLedgerRequestCounter  *prometheus.CounterVec
 	processedRequestCounter *prometheus.CounterVec
+	rejectedRequestCounter  *prometheus.CounterVec
+	evictedRequestCounter   *prometheus.CounterVec
+	mempoolSize             *prometheus.GaugeVec
+	mempoolBytes            *prometheus.GaugeVec
 	messagesReceived        *prometheus.CounterVec
 	requestAckMessages      *prometheus.CounterVec
 	requestProcessingTime   *prometheus.GaugeVec
@@ -97,6 +101,30 @@ func (m *Metrics) registerMetrics() {
 	}, []string{"chain"})
 	prometheus.MustRegister(m.processedRequestCounter)
 
+	m.rejectedRequestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wasp_rejected_request_counter",
+		Help: "Number of requests rejected by the mempool",
+	}, []string{"chain", "reason"})
+	prometheus.MustRegister(m.rejectedRequestCounter)
+
+	m.evictedRequestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wasp_evicted_request_counter",
+		Help: "Number of requests evicted from the mempool",
+	}, []string{"chain"})
+	prometheus.MustRegister(m.evictedRequestCounter)
+
+	m.mempoolSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "wasp_mempool_size",
+		Help: "Number of requests in the mempool",
+	}, []string{"chain"})
+	prometheus.MustRegister(m.mempoolSize)
+
+	m.mempoolBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "wasp_mempool_bytes",
+		Help: "Total size of the requests in the mempool",
+	}, []string{"chain"})
+	prometheus.MustRegister(m.mempoolBytes)
+
 	m.messagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "messages_received_per_chain",
 		Help: "Number of messages received",
@@ -26,3 +26,9 @@ func (ncmi *emptyNodeConnectionMetrics) SetUnsubscribed(address ledgerstate.Addr
 func (ncmi *emptyNodeConnectionMetrics) GetSubscribed() []ledgerstate.Address {
 	return []ledgerstate.Address{}
 }
+func (ncmi *emptyNodeConnectionMetrics) AddEndpoint(NodeConnectionEndpointMetrics) {}
+func (ncmi *emptyNodeConnectionMetrics) GetEndpoints() []NodeConnectionEndpointMetrics {
+	return []NodeConnectionEndpointMetrics{}
+}
+func (ncmi *emptyNodeConnectionMetrics) CountFailover()       {}
+func (ncmi *emptyNodeConnectionMetrics) GetFailovers() uint32 { return 0 }
@@ -0,0 +1,81 @@
+package nodeconnmetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NodeConnectionEndpointMetrics is the state of the connection to one of the L1 nodes
+type NodeConnectionEndpointMetrics interface {
+	GetAddress() string
+	IsConnected() bool
+	IsHealthy() bool
+	IsActive() bool
+	GetConnects() uint32
+	GetMessagesSent() uint32
+	GetMessagesReceived() uint32
+	GetLastReceived() time.Time
+}
+
+const endpointLabelName = "endpoint"
+
+var (
+	endpointConnectedDesc = prometheus.NewDesc(
+		"wasp_nodeconn_endpoint_connected",
+		"Whether the connection to the L1 node endpoint is established",
+		[]string{endpointLabelName}, nil,
+	)
+	endpointHealthyDesc = prometheus.NewDesc(
+		"wasp_nodeconn_endpoint_healthy",
+		"Whether the L1 node endpoint passes the health checks",
+		[]string{endpointLabelName}, nil,
+	)
+	endpointActiveDesc = prometheus.NewDesc(
+		"wasp_nodeconn_endpoint_active",
+		"Whether the L1 node endpoint is the one used by the chains",
+		[]string{endpointLabelName}, nil,
+	)
+	endpointSentDesc = prometheus.NewDesc(
+		"wasp_nodeconn_endpoint_messages_sent_total",
+		"Number of messages sent to the L1 node endpoint",
+		[]string{endpointLabelName}, nil,
+	)
+	endpointReceivedDesc = prometheus.NewDesc(
+		"wasp_nodeconn_endpoint_messages_received_total",
+		"Number of messages received from the L1 node endpoint",
+		[]string{endpointLabelName}, nil,
+	)
+)
+
+// endpointsCollector exports the state of the endpoints to Prometheus at the time of scraping
+type endpointsCollector struct {
+	ncmi *nodeConnectionMetricsImpl
+}
+
+var _ prometheus.Collector = &endpointsCollector{}
+
+func (c *endpointsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- endpointConnectedDesc
+	ch <- endpointHealthyDesc
+	ch <- endpointActiveDesc
+	ch <- endpointSentDesc
+	ch <- endpointReceivedDesc
+}
+
+func (c *endpointsCollector) Collect(ch chan<- prometheus.Metric) {
+	boolToFloat := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	for _, e := range c.ncmi.GetEndpoints() {
+		addr := e.GetAddress()
+		ch <- prometheus.MustNewConstMetric(endpointConnectedDesc, prometheus.GaugeValue, boolToFloat(e.IsConnected()), addr)
+		ch <- prometheus.MustNewConstMetric(endpointHealthyDesc, prometheus.GaugeValue, boolToFloat(e.IsHealthy()), addr)
+		ch <- prometheus.MustNewConstMetric(endpointActiveDesc, prometheus.GaugeValue, boolToFloat(e.IsActive()), addr)
+		ch <- prometheus.MustNewConstMetric(endpointSentDesc, prometheus.CounterValue, float64(e.GetMessagesSent()), addr)
+		ch <- prometheus.MustNewConstMetric(endpointReceivedDesc, prometheus.CounterValue, float64(e.GetMessagesReceived()), addr)
+	}
+}
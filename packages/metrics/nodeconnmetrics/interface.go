@@ -34,6 +34,11 @@ type NodeConnectionMetrics interface {
 	SetUnsubscribed(ledgerstate.Address)
 	GetSubscribed() []ledgerstate.Address
 
+	AddEndpoint(NodeConnectionEndpointMetrics)
+	GetEndpoints() []NodeConnectionEndpointMetrics
+	CountFailover()
+	GetFailovers() uint32
+
 	RegisterMetrics()
 	NewMessagesMetrics(chainID *iscp.ChainID) NodeConnectionMessagesMetrics
 }
@@ -1,10 +1,13 @@
 package nodeconnmetrics
 
 import (
+	"sync"
+
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
 )
 
 type nodeConnectionMetricsImpl struct {
@@ -12,7 +15,11 @@ type nodeConnectionMetricsImpl struct {
 	log                 *logger.Logger
 	messageTotalCounter *prometheus.CounterVec
 	lastEventTimeGauge  *prometheus.GaugeVec
+	failoverCounter     prometheus.Counter
 	subscribed          []ledgerstate.Address
+	endpoints           []NodeConnectionEndpointMetrics
+	endpointsMutex      sync.RWMutex
+	failovers           atomic.Uint32
 }
 
 var _ NodeConnectionMetrics = &nodeConnectionMetricsImpl{}
@@ -40,6 +47,12 @@ func (ncmi *nodeConnectionMetricsImpl) RegisterMetrics() {
 		Help: "Last time when the message was sent/received by node connection of the chain",
 	}, []string{chainLabelName, msgTypeLabelName})
 	prometheus.MustRegister(ncmi.lastEventTimeGauge)
+	ncmi.failoverCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "wasp_nodeconn_failover_counter",
+		Help: "Number of times the node connection switched to another L1 node endpoint",
+	})
+	prometheus.MustRegister(ncmi.failoverCounter)
+	prometheus.MustRegister(&endpointsCollector{ncmi: ncmi})
 	ncmi.log.Info("Registering nodeconnection metrics to prometheus... Done")
 }
 
@@ -79,3 +92,28 @@ func (ncmi *nodeConnectionMetricsImpl) SetUnsubscribed(address ledgerstate.Addre
 func (ncmi *nodeConnectionMetricsImpl) GetSubscribed() []ledgerstate.Address {
 	return ncmi.subscribed
 }
+
+func (ncmi *nodeConnectionMetricsImpl) AddEndpoint(endpoint NodeConnectionEndpointMetrics) {
+	ncmi.endpointsMutex.Lock()
+	defer ncmi.endpointsMutex.Unlock()
+	ncmi.endpoints = append(ncmi.endpoints, endpoint)
+}
+
+func (ncmi *nodeConnectionMetricsImpl) GetEndpoints() []NodeConnectionEndpointMetrics {
+	ncmi.endpointsMutex.RLock()
+	defer ncmi.endpointsMutex.RUnlock()
+	ret := make([]NodeConnectionEndpointMetrics, len(ncmi.endpoints))
+	copy(ret, ncmi.endpoints)
+	return ret
+}
+
+func (ncmi *nodeConnectionMetricsImpl) CountFailover() {
+	ncmi.failovers.Inc()
+	if ncmi.failoverCounter != nil {
+		ncmi.failoverCounter.Inc()
+	}
+}
+
+func (ncmi *nodeConnectionMetricsImpl) GetFailovers() uint32 {
+	return ncmi.failovers.Load()
+}
@@ -19,8 +19,9 @@ const (
 	LoggerOutputPaths       = "logger.outputPaths"
 	LoggerDisableEvents     = "logger.disableEvents"
 
-	DatabaseDir      = "database.directory"
-	DatabaseInMemory = "database.inMemory"
+	DatabaseDir             = "database.directory"
+	DatabaseInMemory        = "database.inMemory"
+	DatabasePruningInterval = "database.pruningInterval"
 
 	WebAPIBindAddress            = "webapi.bindAddress"
 	WebAPIAdminWhitelist         = "webapi.adminWhitelist"
@@ -31,13 +32,20 @@ const (
 	DashboardExploreAddressURL = "dashboard.exploreAddressUrl"
 	DashboardAuth              = "dashboard.auth"
 
-	NodeAddress = "nodeconn.address"
+	NodeAddress           = "nodeconn.address"
+	NodeAddresses         = "nodeconn.addresses"
+	NodeConnProbeInterval = "nodeconn.probeInterval"
+	NodeConnProbeTimeout  = "nodeconn.probeTimeout"
 
 	PeeringMyNetID                   = "peering.netid"
 	PeeringPort                      = "peering.port"
 	PeeringNeighbors                 = "peering.neighbors"
 	PullMissingRequestsFromCommittee = "peering.pullMissingRequests"
 
+	ConsensusMaxBatchSize = "consensus.maxBatchSize"
+
+	VMParallelism = "vm.parallelism"
+
 	NanomsgPublisherPort = "nanomsg.port"
 
 	IpfsGatewayAddress = "ipfs.gatewayAddress"
@@ -46,6 +54,17 @@ const (
 	OffledgerBroadcastInterval   = "offledger.broadcastInterval"
 	OffledgerAPICacheTTL         = "offledger.apiCacheTTL"
 
+	MempoolMaxPoolSize          = "mempool.maxPoolSize"
+	MempoolMaxRequestsPerSender = "mempool.maxRequestsPerSender"
+	MempoolMaxTotalBytes        = "mempool.maxTotalBytes"
+	MempoolEvictionPolicy       = "mempool.evictionPolicy"
+
+	SnapshotDirectory     = "snapshot.directory"
+	SnapshotInterval      = "snapshot.interval"
+	SnapshotSyncThreshold = "snapshot.syncThreshold"
+
+	StateHistoryDepth = "state.historyDepth"
+
 	ProfilingBindAddress   = "profiling.bindAddress"
 	ProfilingEnabled       = "profiling.enabled"
 	ProfilingWriteProfiles = "profiling.writeProfiles"
@@ -67,6 +86,7 @@ func Init() *configuration.Configuration {
 
 	flag.String(DatabaseDir, "waspdb", "path to the database folder")
 	flag.Bool(DatabaseInMemory, false, "whether the database is only kept in memory and not persisted")
+	flag.Int(DatabasePruningInterval, 60, "time between runs of pruning of old blocks according to the pruning policies of chains (in seconds). 0 means blocks are never pruned")
 
 	flag.String(WebAPIBindAddress, "127.0.0.1:8080", "the bind address for the web API")
 	flag.StringSlice(WebAPIAdminWhitelist, []string{}, "IP whitelist for /adm wndpoints")
@@ -78,12 +98,20 @@ func Init() *configuration.Configuration {
 	flag.StringToString(DashboardAuth, nil, "authentication scheme for the node dashboard")
 
 	flag.String(NodeAddress, "127.0.0.1:5000", "node host address")
+	flag.StringSlice(NodeAddresses, []string{}, "txstream addresses of several L1 nodes in the order of preference, the node connection fails over between them. Overrides nodeconn.address")
+	flag.Int(NodeConnProbeInterval, 5000, "time between health checks of the connections to L1 nodes (in ms)")
+	flag.Int(NodeConnProbeTimeout, 10000, "a connection to L1 node, which doesn't respond to the health check in this time, is considered unhealthy (in ms)")
 
 	flag.Int(PeeringPort, 4000, "port for Wasp committee connection/peering")
 	flag.String(PeeringMyNetID, "127.0.0.1:4000", "node host address as it is recognized by other peers")
 	flag.StringSlice(PeeringNeighbors, []string{}, "list of neighbors: known peer netIDs")
 	flag.Bool(PullMissingRequestsFromCommittee, true, "whether or not to pull missing requests from other committee members")
 
+	flag.Int(ConsensusMaxBatchSize, 100, "maximum number of requests in a batch, the ones with the highest priority fee are taken. 0 means no limit")
+
+	flag.Int(VMParallelism, 0, "number of off-ledger requests of a block run concurrently by the VM. 0 or 1 means sequential run. "+
+		"Only the requests to core and native contracts are run concurrently, the calls of wasm contracts are run sequentially")
+
 	flag.Int(NanomsgPublisherPort, 5550, "the port for nanomsg even publisher")
 
 	flag.String(IpfsGatewayAddress, "https://ipfs.io/", "the address of HTTP(s) gateway to which download from ipfs requests will be forwarded")
@@ -92,6 +120,17 @@ func Init() *configuration.Configuration {
 	flag.Int(OffledgerBroadcastInterval, 5000, "time between re-broadcast of offledger requests (in ms)")
 	flag.Int(OffledgerAPICacheTTL, 5*60, "time to keep processed offledger requests in api cache (in seconds)")
 
+	flag.Int(MempoolMaxPoolSize, 10000, "maximum number of requests in the mempool of a chain. 0 means no limit")
+	flag.Int(MempoolMaxRequestsPerSender, 1000, "maximum number of requests of a single sender account in the mempool of a chain. 0 means no limit")
+	flag.Int(MempoolMaxTotalBytes, 64*1024*1024, "maximum total size of the requests in the mempool of a chain (in bytes). 0 means no limit")
+	flag.String(MempoolEvictionPolicy, "oldest", "which requests are evicted from a full mempool first: oldest, lowestFee or farthestTimeLock")
+
+	flag.String(SnapshotDirectory, "", "path to the folder of state snapshots of chains. Empty means snapshots are not written to disk")
+	flag.Int(SnapshotInterval, 0, "a snapshot of the chain state is written each snapshot.interval blocks. 0 means snapshots are not written")
+	flag.Int(SnapshotSyncThreshold, 1000, "a node, which is behind by more than snapshot.syncThreshold blocks, syncs from a snapshot. 0 means snapshots are not used for syncing")
+
+	flag.Int(StateHistoryDepth, 1000, "number of past states of each chain which can be queried. 0 means the whole history is kept (archival node)")
+
 	flag.String(ProfilingBindAddress, "127.0.0.1:6060", "pprof http server address")
 	flag.Bool(ProfilingEnabled, false, "whether profiling is enabled")
 	flag.Bool(ProfilingWriteProfiles, false, "whether to write profiling profiles to disk on node shutdown (when enabled some metrics will be unavailable via pprof runtime endpoint)")
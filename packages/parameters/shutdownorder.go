@@ -8,5 +8,6 @@ const (
 	PriorityNodeConnection
 	PriorityWebAPI
 	PriorityDBGarbageCollection
+	PriorityDBPruning
 	PriorityMetrics
 )
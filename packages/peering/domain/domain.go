@@ -119,6 +119,46 @@ func (d *DomainImpl) RemovePeer(netID string) {
 	d.reshufflePeers()
 }
 
+// UpdatePeers replaces peers of the domain with the given ones. Peers not in the list are released.
+// Self and unknown peers are ignored
+func (d *DomainImpl) UpdatePeers(newPeerNetIDs []string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	newPeers := make(map[string]bool, len(newPeerNetIDs))
+	for _, netID := range newPeerNetIDs {
+		if netID != d.netProvider.Self().NetID() {
+			newPeers[netID] = true
+		}
+	}
+	for netID, peer := range d.nodes {
+		if !newPeers[netID] {
+			peer.Close()
+			delete(d.nodes, netID)
+		}
+	}
+	for netID := range newPeers {
+		if _, ok := d.nodes[netID]; ok {
+			continue
+		}
+		peer, err := d.netProvider.PeerByNetID(netID)
+		if err != nil {
+			d.log.Warnf("UpdatePeers: failed to add peer %s: %v", netID, err)
+			continue
+		}
+		d.nodes[netID] = peer
+	}
+	d.reshufflePeers()
+}
+
+func (d *DomainImpl) PeerNetIDs() []string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	ret := make([]string, len(d.netIDs))
+	copy(ret, d.netIDs)
+	return ret
+}
+
 func (d *DomainImpl) ReshufflePeers(seedBytes ...[]byte) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
@@ -131,6 +171,10 @@ func (d *DomainImpl) reshufflePeers(seedBytes ...[]byte) {
 		d.netIDs = append(d.netIDs, netID)
 	}
 	sort.Strings(d.netIDs)
+	if int(d.permutation.Size()) != len(d.netIDs) {
+		// the set of peers has changed
+		d.permutation = util.NewPermutation16(uint16(len(d.netIDs)), nil)
+	}
 	var seedB []byte
 	if len(seedBytes) == 0 {
 		var b [8]byte
@@ -149,7 +193,9 @@ func (d *DomainImpl) Attach(receiver byte, callback func(recv *peering.PeerMessa
 				recv.MsgReceiver, recv.MsgType, recv.SenderNetID)
 			return
 		}
+		d.mutex.RLock()
 		_, ok := d.nodes[recv.SenderNetID]
+		d.mutex.RUnlock()
 		if !ok {
 			d.log.Warnf("dropping message for receiver=%v MsgType=%v from %v: it does not belong to the peer domain.",
 				recv.MsgReceiver, recv.MsgType, recv.SenderNetID)
@@ -116,3 +116,43 @@ func TestRandom(t *testing.T) {
 	d2.Close()
 	require.NoError(t, netCloser.Close())
 }
+
+func TestUpdatePeers(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	defer log.Sync()
+
+	nodeCount := 4
+	netIDs, nodeIdentities := testpeers.SetupKeys(uint16(nodeCount))
+	nodes, netCloser := testpeers.SetupNet(netIDs, nodeIdentities, testutil.NewPeeringNetReliable(log), log)
+	for i := range nodes {
+		go nodes[i].Run(make(<-chan struct{}))
+	}
+	peeringID := peering.RandomPeeringID()
+
+	d, err := nodes[0].PeerDomain(peeringID, netIDs[:2])
+	require.NoError(t, err)
+	require.EqualValues(t, []string{netIDs[1]}, d.PeerNetIDs())
+	require.Len(t, d.GetRandomPeers(10), 1)
+
+	// self is ignored, peers not in the list are removed
+	d.UpdatePeers([]string{netIDs[0], netIDs[2], netIDs[3]})
+	require.ElementsMatch(t, []string{netIDs[2], netIDs[3]}, d.PeerNetIDs())
+	require.ElementsMatch(t, []string{netIDs[2], netIDs[3]}, d.GetRandomPeers(10))
+
+	// messages from the new peers are accepted
+	receiver := byte(8)
+	received := make(chan string, 1)
+	d.Attach(receiver, func(recv *peering.PeerMessageIn) {
+		received <- recv.SenderNetID
+	})
+	nodes[3].SendMsgByNetID(netIDs[0], &peering.PeerMessageData{
+		PeeringID:   peeringID,
+		MsgReceiver: receiver,
+		MsgType:     125,
+		MsgData:     []byte{},
+	})
+	require.EqualValues(t, netIDs[3], <-received)
+
+	d.Close()
+	require.NoError(t, netCloser.Close())
+}
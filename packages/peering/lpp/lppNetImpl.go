@@ -34,6 +34,7 @@ import (
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/peering/domain"
 	"github.com/iotaledger/wasp/packages/peering/group"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/crypto"
@@ -57,17 +58,17 @@ const (
 
 // netImpl implements a peering.NetworkProvider interface.
 type netImpl struct {
-	myNetID     string                  // NetID of this node.
-	lppHost     host.Host               // The instance of the libp2p to use.
-	port        int                     // Port to use for peering.
-	ctx         context.Context         // Context for the libp2p
-	ctxCancel   context.CancelFunc      // A way to close the context.
-	peers       map[libp2ppeer.ID]*peer // By remotePeer.ID()
-	peersLock   *sync.RWMutex
-	recvEvents  *events.Event // Used to publish events to all attached clients.
-	nodeKeyPair *ed25519.KeyPair
-	trusted     peering.TrustedNetworkManager
-	log         *logger.Logger
+	myNetID    string                  // NetID of this node.
+	lppHost    host.Host               // The instance of the libp2p to use.
+	port       int                     // Port to use for peering.
+	ctx        context.Context         // Context for the libp2p
+	ctxCancel  context.CancelFunc      // A way to close the context.
+	peers      map[libp2ppeer.ID]*peer // By remotePeer.ID()
+	peersLock  *sync.RWMutex
+	recvEvents *events.Event // Used to publish events to all attached clients.
+	nodeSigner signer.Signer // Holds the node identity key.
+	trusted    peering.TrustedNetworkManager
+	log        *logger.Logger
 }
 
 var (
@@ -80,11 +81,11 @@ var (
 func NewNetworkProvider(
 	myNetID string,
 	port int,
-	nodeKeyPair *ed25519.KeyPair,
+	nodeSigner signer.Signer,
 	trusted peering.TrustedNetworkManager,
 	log *logger.Logger,
 ) (peering.NetworkProvider, peering.TrustedNetworkManager, error) {
-	privKey, err := crypto.UnmarshalEd25519PrivateKey(nodeKeyPair.PrivateKey.Bytes())
+	privKey, err := newSignerPrivKey(nodeSigner)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("unable to convert the private key: %w", err)
 	}
@@ -106,17 +107,17 @@ func NewNetworkProvider(
 		return nil, nil, xerrors.Errorf("failed to construct libp2p host: %w", err)
 	}
 	n := netImpl{
-		myNetID:     myNetID,
-		lppHost:     lppHost,
-		ctx:         ctx,
-		ctxCancel:   ctxCancel,
-		port:        port,
-		peers:       make(map[libp2ppeer.ID]*peer),
-		peersLock:   &sync.RWMutex{},
-		recvEvents:  nil, // Initialized bellow.
-		nodeKeyPair: nodeKeyPair,
-		trusted:     trusted,
-		log:         log,
+		myNetID:    myNetID,
+		lppHost:    lppHost,
+		ctx:        ctx,
+		ctxCancel:  ctxCancel,
+		port:       port,
+		peers:      make(map[libp2ppeer.ID]*peer),
+		peersLock:  &sync.RWMutex{},
+		recvEvents: nil, // Initialized bellow.
+		nodeSigner: nodeSigner,
+		trusted:    trusted,
+		log:        log,
 	}
 	n.recvEvents = events.NewEvent(n.eventHandler)
 	//
@@ -409,7 +410,8 @@ func (n *netImpl) NetID() string {
 
 // PubKey implements peering.PeerSender for the Self() node.
 func (n *netImpl) PubKey() *ed25519.PublicKey {
-	return &n.nodeKeyPair.PublicKey
+	pubKey := n.nodeSigner.PublicKey()
+	return &pubKey
 }
 
 // SendMsg implements peering.PeerSender for the Self() node.
@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/peering/lpp"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/stretchr/testify/require"
@@ -36,11 +37,11 @@ func TestLPPPeeringImpl(t *testing.T) {
 			require.NoError(t, err)
 		}
 	}
-	nodes[0], _, err = lpp.NewNetworkProvider(netIDs[0], 9027, &keys[0], tnms[0], log.Named("node0"))
+	nodes[0], _, err = lpp.NewNetworkProvider(netIDs[0], 9027, signer.NewLocal(&keys[0], nil), tnms[0], log.Named("node0"))
 	require.NoError(t, err)
-	nodes[1], _, err = lpp.NewNetworkProvider(netIDs[1], 9028, &keys[1], tnms[1], log.Named("node1"))
+	nodes[1], _, err = lpp.NewNetworkProvider(netIDs[1], 9028, signer.NewLocal(&keys[1], nil), tnms[1], log.Named("node1"))
 	require.NoError(t, err)
-	nodes[2], _, err = lpp.NewNetworkProvider(netIDs[2], 9029, &keys[2], tnms[2], log.Named("node2"))
+	nodes[2], _, err = lpp.NewNetworkProvider(netIDs[2], 9029, signer.NewLocal(&keys[2], nil), tnms[2], log.Named("node2"))
 	require.NoError(t, err)
 	for i := range nodes {
 		go nodes[i].Run(make(<-chan struct{}))
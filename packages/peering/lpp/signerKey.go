@@ -0,0 +1,75 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package lpp
+
+import (
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	pb "github.com/libp2p/go-libp2p-core/crypto/pb"
+)
+
+// rawKeyLabel is signed to get the secret, the libp2p derives its transport secrets from.
+const rawKeyLabel = "wasp-peering-raw-key"
+
+// signerPrivKey is the libp2p identity key, which signs through the signer,
+// so that the private key may be held by an external signer process.
+type signerPrivKey struct {
+	signer signer.Signer
+	pubKey crypto.PubKey
+}
+
+var _ crypto.PrivKey = &signerPrivKey{}
+
+func newSignerPrivKey(s signer.Signer) (*signerPrivKey, error) {
+	pubKey, err := crypto.UnmarshalEd25519PublicKey(s.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &signerPrivKey{signer: s, pubKey: pubKey}, nil
+}
+
+// Bytes implements crypto.Key.
+func (k *signerPrivKey) Bytes() ([]byte, error) {
+	return crypto.MarshalPrivateKey(k)
+}
+
+// Equals implements crypto.Key.
+func (k *signerPrivKey) Equals(o crypto.Key) bool {
+	other, ok := o.(crypto.PrivKey)
+	if !ok {
+		return false
+	}
+	return k.pubKey.Equals(other.GetPublic())
+}
+
+// Raw implements crypto.Key. The private key is not available, so the libp2p gets
+// a secret derived from it instead. It is only used to derive the transport secrets
+// (e.g. the QUIC stateless reset key). The Ed25519 signatures are deterministic,
+// so the secret is the same across the restarts.
+func (k *signerPrivKey) Raw() ([]byte, error) {
+	sig, err := k.signer.Sign([]byte(rawKeyLabel))
+	if err != nil {
+		return nil, err
+	}
+	return sig.Bytes(), nil
+}
+
+// Type implements crypto.Key.
+func (k *signerPrivKey) Type() pb.KeyType {
+	return pb.KeyType_Ed25519
+}
+
+// Sign implements crypto.PrivKey.
+func (k *signerPrivKey) Sign(data []byte) ([]byte, error) {
+	sig, err := k.signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Bytes(), nil
+}
+
+// GetPublic implements crypto.PrivKey.
+func (k *signerPrivKey) GetPublic() crypto.PubKey {
+	return k.pubKey
+}
@@ -92,6 +92,8 @@ type GroupProvider interface {
 type PeerDomainProvider interface {
 	ReshufflePeers(seedBytes ...[]byte)
 	GetRandomPeers(upToNumPeers int) []string
+	UpdatePeers(newPeerNetIDs []string)
+	PeerNetIDs() []string
 	Attach(receiver byte, callback func(recv *PeerMessageIn)) interface{}
 	Detach(attachID interface{})
 	SendMsgByNetID(netID string, msgReceiver byte, msgType byte, msgData []byte)
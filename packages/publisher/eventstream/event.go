@@ -0,0 +1,238 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package eventstream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"golang.org/x/xerrors"
+)
+
+// Version of the format of the events. It is incremented on incompatible changes
+const Version = 1
+
+// Types of the events
+const (
+	TypeBlock    = "block"
+	TypeRotation = "rotation"
+	TypeReceipt  = "receipt"
+	TypeEvent    = "event"
+	// TypeError is sent before the stream is closed because of an error
+	TypeError = "error"
+)
+
+var allTypes = []string{TypeBlock, TypeRotation, TypeReceipt, TypeEvent}
+
+// Cursor is the position of the event in the stream of the chain. The events of the block
+// are numbered by Seq in the order of the stream: the block first, then the rotation if the
+// chain was rotated in the block, then the receipt of each request followed by its events.
+// The numbering is derived from the blocklog, so the cursor is the same on all nodes
+type Cursor struct {
+	BlockIndex uint32
+	Seq        uint32
+}
+
+// ParseCursor parses the cursor in the form <blockIndex>-<seq>
+func ParseCursor(s string) (Cursor, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return Cursor{}, xerrors.Errorf("wrong cursor '%s', expected <blockIndex>-<seq>", s)
+	}
+	blockIndex, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return Cursor{}, xerrors.Errorf("wrong cursor '%s': %w", s, err)
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return Cursor{}, xerrors.Errorf("wrong cursor '%s': %w", s, err)
+	}
+	return Cursor{BlockIndex: uint32(blockIndex), Seq: uint32(seq)}, nil
+}
+
+func (c Cursor) String() string {
+	return fmt.Sprintf("%d-%d", c.BlockIndex, c.Seq)
+}
+
+// Less tells if the cursor is before the other one in the stream
+func (c Cursor) Less(other Cursor) bool {
+	if c.BlockIndex != other.BlockIndex {
+		return c.BlockIndex < other.BlockIndex
+	}
+	return c.Seq < other.Seq
+}
+
+// MarshalText encodes the cursor in the form <blockIndex>-<seq>, so the clients can pass it back as is
+func (c Cursor) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Cursor) UnmarshalText(data []byte) error {
+	var err error
+	*c, err = ParseCursor(string(data))
+	return err
+}
+
+// Event is the message of the stream. Exactly one of the payloads is set, according to the Type
+type Event struct {
+	Version  int
+	Type     string
+	ChainID  string
+	Cursor   Cursor
+	Block    *Block         `json:",omitempty"`
+	Rotation *Rotation      `json:",omitempty"`
+	Receipt  *Receipt       `json:",omitempty"`
+	Event    *ContractEvent `json:",omitempty"`
+	Error    string         `json:",omitempty"`
+}
+
+type Block struct {
+	BlockIndex            uint32
+	Timestamp             time.Time
+	TotalRequests         uint16
+	NumSuccessfulRequests uint16
+	NumOffLedgerRequests  uint16
+	PreviousStateHash     string
+}
+
+// Rotation tells that the chain is controlled by the new addresses since the block
+type Rotation struct {
+	StateAddress     string
+	GoverningAddress string
+}
+
+type Receipt struct {
+	RequestID     string
+	RequestIndex  uint16
+	OffLedger     bool
+	SenderAccount string
+	Contract      string
+	EntryPoint    string
+	// Error is empty if the request was successful
+	Error   string `json:",omitempty"`
+	Fee     uint64
+	GasUsed uint64
+}
+
+// ContractEvent is the event emitted by the contract. Name and Fields are set for the typed events only
+type ContractEvent struct {
+	RequestIndex uint16
+	EventIndex   uint16
+	// RequestID is empty if the receipt of the request is pruned
+	RequestID string `json:",omitempty"`
+	Contract  string
+	Text      string
+	Name      string        `json:",omitempty"`
+	Fields    []*EventField `json:",omitempty"`
+}
+
+type EventField struct {
+	Name    string
+	Type    string
+	Value   []byte
+	Text    string
+	Indexed bool
+}
+
+func newErrorEvent(chainID *iscp.ChainID, cursor Cursor, err error) *Event {
+	return &Event{
+		Version: Version,
+		Type:    TypeError,
+		ChainID: chainID.Base58(),
+		Cursor:  cursor,
+		Error:   err.Error(),
+	}
+}
+
+// EventsOfBlock converts the contents of the block to the events of the stream, in the order of the stream
+func EventsOfBlock(chainID *iscp.ChainID, contents *blocklog.BlockContents) []*Event {
+	blockIndex := contents.Info.BlockIndex
+	ret := make([]*Event, 0, 1+len(contents.Receipts)+len(contents.Events))
+	add := func(ev *Event) {
+		ev.Version = Version
+		ev.ChainID = chainID.Base58()
+		ev.Cursor = Cursor{BlockIndex: blockIndex, Seq: uint32(len(ret))}
+		ret = append(ret, ev)
+	}
+
+	add(&Event{Type: TypeBlock, Block: &Block{
+		BlockIndex:            blockIndex,
+		Timestamp:             contents.Info.Timestamp,
+		TotalRequests:         contents.Info.TotalRequests,
+		NumSuccessfulRequests: contents.Info.NumSuccessfulRequests,
+		NumOffLedgerRequests:  contents.Info.NumOffLedgerRequests,
+		PreviousStateHash:     contents.Info.PreviousStateHash.String(),
+	}})
+	if ca := contents.ControlAddresses; ca != nil {
+		add(&Event{Type: TypeRotation, Rotation: &Rotation{
+			StateAddress:     ca.StateAddress.Base58(),
+			GoverningAddress: ca.GoverningAddress.Base58(),
+		}})
+	}
+
+	// the receipts and the events are both in the order of the requests
+	requestIDs := make(map[uint16]string)
+	evIdx := 0
+	addEventsUpTo := func(reqIdx uint16) {
+		for ; evIdx < len(contents.Events) && contents.Events[evIdx].Key.RequestIndex() <= reqIdx; evIdx++ {
+			add(&Event{Type: TypeEvent, Event: newContractEvent(contents.Events[evIdx], requestIDs)})
+		}
+	}
+	for _, rec := range contents.Receipts {
+		if rec.RequestIndex > 0 {
+			addEventsUpTo(rec.RequestIndex - 1)
+		}
+		requestIDs[rec.RequestIndex] = rec.Request.ID().String()
+		add(&Event{Type: TypeReceipt, Receipt: newReceipt(rec)})
+		addEventsUpTo(rec.RequestIndex)
+	}
+	addEventsUpTo(contents.Info.TotalRequests)
+	return ret
+}
+
+func newReceipt(rec *blocklog.RequestReceipt) *Receipt {
+	target := rec.Request.Target()
+	ret := &Receipt{
+		RequestID:     rec.Request.ID().String(),
+		RequestIndex:  rec.RequestIndex,
+		OffLedger:     rec.Request.IsOffLedger(),
+		SenderAccount: rec.Request.SenderAccount().String(),
+		Contract:      target.Contract.String(),
+		EntryPoint:    target.EntryPoint.String(),
+		Fee:           rec.Fee,
+		GasUsed:       rec.GasUsed,
+	}
+	if rec.Error != nil {
+		ret.Error = rec.Error.String()
+	}
+	return ret
+}
+
+func newContractEvent(ev *blocklog.BlockEvent, requestIDs map[uint16]string) *ContractEvent {
+	ret := &ContractEvent{
+		RequestIndex: ev.Key.RequestIndex(),
+		EventIndex:   ev.Key.RequestEventIndex(),
+		RequestID:    requestIDs[ev.Key.RequestIndex()],
+		Contract:     ev.Contract.String(),
+		Text:         ev.Text,
+	}
+	if ev.Typed != nil {
+		ret.Name = ev.Typed.Name
+		ret.Fields = make([]*EventField, len(ev.Typed.Fields))
+		for i, f := range ev.Typed.Fields {
+			ret.Fields[i] = &EventField{
+				Name:    f.Name,
+				Type:    f.Type,
+				Value:   f.Value,
+				Text:    blocklog.EventFieldText(f),
+				Indexed: f.Indexed,
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package eventstream serves the versioned JSON stream of the blocks, the receipts, the contract events
+// and the rotations of the chain over the websocket. All events are read from the blocklog, the publisher
+// only notifies about the new blocks. So a slow client does not lose the events, and a client which
+// reconnects with the cursor of the last event it received gets the missed events first
+package eventstream
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// pollPeriod is how often the stream looks for the new blocks without the notification from the publisher
+const pollPeriod = 10 * time.Second
+
+// ErrCursorPruned is sent when the events after the cursor are already pruned from the blocklog
+var ErrCursorPruned = xerrors.New("the events after the cursor are pruned from the blocklog")
+
+type EventStream struct {
+	log *logger.Logger
+}
+
+func New(log *logger.Logger) *EventStream {
+	return &EventStream{
+		log: log.Named("EventStream"),
+	}
+}
+
+// ServeHTTP parses the subscription from the query parameters, upgrades the connection to the websocket
+// and streams the events of the chain until the client disconnects. getStateReader returns the reader
+// of the latest state of the chain.
+// An error is returned before the upgrade if the subscription is wrong.
+func (s *EventStream) ServeHTTP(
+	chainID *iscp.ChainID,
+	getStateReader func() state.OptimisticStateReader,
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+	filter, err := FilterFromQuery(r.URL.Query())
+	if err != nil {
+		return err
+	}
+	var after *Cursor
+	if str := r.URL.Query().Get(ParamCursor); str != "" {
+		cursor, err := ParseCursor(str)
+		if err != nil {
+			return err
+		}
+		after = &cursor
+	}
+
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		InsecureSkipVerify: true, // TODO: make accept origin configurable
+	})
+	if err != nil {
+		return err
+	}
+	defer c.Close(websocket.StatusInternalError, "something went wrong")
+	ctx := c.CloseRead(r.Context())
+
+	s.log.Debugf("accepted event stream connection from %s", r.RemoteAddr)
+	defer s.log.Debugf("closed event stream connection from %s", r.RemoteAddr)
+
+	// the notification only wakes up the subscription, so it can't overflow
+	notify := make(chan struct{}, 1)
+	cl := events.NewClosure(func(msgType string, parts []string) {
+		if msgType != "state" || len(parts) < 1 || parts[0] != chainID.Base58() {
+			return
+		}
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	})
+	publisher.Event.Attach(cl)
+	defer publisher.Event.Detach(cl)
+
+	sub := &subscription{
+		chainID:        chainID,
+		getStateReader: getStateReader,
+		filter:         filter,
+		conn:           c,
+	}
+	err = sub.run(ctx, after, notify)
+	if ctx.Err() != nil {
+		// the client is gone
+		return nil
+	}
+	s.log.Debugf("event stream to %s failed: %v", r.RemoteAddr, err)
+	if err := wsjson.Write(ctx, c, newErrorEvent(chainID, sub.next, err)); err != nil {
+		return nil
+	}
+	c.Close(websocket.StatusNormalClosure, "")
+	return nil
+}
+
+type subscription struct {
+	chainID        *iscp.ChainID
+	getStateReader func() state.OptimisticStateReader
+	filter         *Filter
+	conn           *websocket.Conn
+	// next is the position of the next event to send
+	next Cursor
+}
+
+func (sub *subscription) run(ctx context.Context, after *Cursor, notify <-chan struct{}) error {
+	latest, err := sub.latestBlockIndex()
+	if err != nil {
+		return err
+	}
+	if after == nil {
+		sub.next = Cursor{BlockIndex: latest + 1}
+	} else {
+		sub.next = Cursor{BlockIndex: after.BlockIndex, Seq: after.Seq + 1}
+	}
+	if sub.next.BlockIndex == 0 {
+		// the origin block has no events
+		sub.next = Cursor{BlockIndex: 1}
+	}
+	for {
+		for ; sub.next.BlockIndex <= latest; sub.next = (Cursor{BlockIndex: sub.next.BlockIndex + 1}) {
+			evs, err := sub.eventsOfBlock(sub.next.BlockIndex)
+			if err != nil {
+				return err
+			}
+			for _, ev := range evs {
+				if ev.Cursor.Less(sub.next) || !sub.filter.Matches(ev) {
+					continue
+				}
+				if err := wsjson.Write(ctx, sub.conn, ev); err != nil {
+					return err
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		case <-time.After(pollPeriod):
+		}
+		if latest, err = sub.latestBlockIndex(); err != nil {
+			return err
+		}
+	}
+}
+
+func (sub *subscription) latestBlockIndex() (uint32, error) {
+	var ret uint32
+	err := optimism.RetryOnStateInvalidated(func() error {
+		stateReader := sub.getStateReader()
+		stateReader.SetBaseline()
+		var err error
+		ret, err = stateReader.BlockIndex()
+		return err
+	})
+	return ret, err
+}
+
+func (sub *subscription) eventsOfBlock(blockIndex uint32) ([]*Event, error) {
+	var contents *blocklog.BlockContents
+	err := optimism.RetryOnStateInvalidated(func() error {
+		stateReader := sub.getStateReader()
+		stateReader.SetBaseline()
+		partition := subrealm.NewReadOnly(stateReader.KVStoreReader(), kv.Key(blocklog.Contract.Hname().Bytes()))
+		firstUnpruned, err := blocklog.GetFirstUnprunedBlock(partition)
+		if err != nil {
+			return err
+		}
+		if blockIndex < firstUnpruned {
+			return ErrCursorPruned
+		}
+		contents, err = blocklog.GetBlockContents(stateReader.KVStoreReader(), blockIndex)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if contents == nil {
+		return nil, xerrors.Errorf("block #%d not found in the blocklog", blockIndex)
+	}
+	return EventsOfBlock(sub.chainID, contents), nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package eventstream_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/publisher/eventstream"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+var (
+	emitterContract = coreutil.NewContract("emitter", "emits events")
+	funcEmit        = coreutil.Func("emit")
+
+	emitterProcessor = emitterContract.Processor(nil,
+		funcEmit.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+			ctx.Event("text event")
+			ctx.EmitEvent(iscp.NewEvent("emitted").WithField("n", "Int64", codec.EncodeInt64(42)))
+			return nil, nil
+		}),
+	)
+)
+
+type streamEnv struct {
+	t      *testing.T
+	ch     *solo.Chain
+	server *httptest.Server
+}
+
+func setup(t *testing.T) *streamEnv {
+	env := solo.New(t, false, false).WithNativeContract(emitterProcessor)
+	ch := env.NewChain(nil, "ch")
+	require.NoError(t, ch.DeployContract(nil, emitterContract.Name, emitterContract.ProgramHash))
+
+	stream := eventstream.New(testlogger.NewLogger(t))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := stream.ServeHTTP(ch.ChainID, ch.GetStateReader, w, r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &streamEnv{t: t, ch: ch, server: server}
+}
+
+func (e *streamEnv) emit() iscp.RequestID {
+	req := solo.NewCallParams(emitterContract.Name, funcEmit.Name).WithIotas(1)
+	tx, _, err := e.ch.PostRequestSyncTx(req, nil)
+	require.NoError(e.t, err)
+	reqs, err := e.ch.Env.RequestsForChain(tx, e.ch.ChainID)
+	require.NoError(e.t, err)
+	return reqs[0].ID()
+}
+
+func (e *streamEnv) subscribe(query url.Values) *websocket.Conn {
+	u := "ws" + strings.TrimPrefix(e.server.URL, "http") + "?" + query.Encode()
+	c, _, err := websocket.Dial(context.Background(), u, nil)
+	require.NoError(e.t, err)
+	e.t.Cleanup(func() { c.Close(websocket.StatusNormalClosure, "") })
+	return c
+}
+
+func (e *streamEnv) read(c *websocket.Conn, n int) []*eventstream.Event {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ret := make([]*eventstream.Event, n)
+	for i := range ret {
+		ret[i] = &eventstream.Event{}
+		require.NoError(e.t, wsjson.Read(ctx, c, ret[i]))
+		require.EqualValues(e.t, eventstream.Version, ret[i].Version)
+		require.NotEqual(e.t, eventstream.TypeError, ret[i].Type, ret[i].Error)
+	}
+	return ret
+}
+
+func TestBackfillAndResume(t *testing.T) {
+	e := setup(t)
+	reqID := e.emit()
+	latest, err := e.ch.StateReader.BlockIndex()
+	require.NoError(t, err)
+
+	// all blocks since the origin
+	c := e.subscribe(url.Values{eventstream.ParamCursor: {"0-0"}})
+	var all []*eventstream.Event
+	// the last block: the block, the receipt and two events
+	last := eventstream.Cursor{BlockIndex: latest, Seq: 3}
+	for len(all) == 0 || all[len(all)-1].Cursor.Less(last) {
+		all = append(all, e.read(c, 1)...)
+	}
+	for i := 1; i < len(all); i++ {
+		require.True(t, all[i-1].Cursor.Less(all[i].Cursor))
+	}
+	tail := all[len(all)-4:]
+	require.Equal(t, eventstream.TypeBlock, tail[0].Type)
+	require.EqualValues(t, latest, tail[0].Block.BlockIndex)
+	require.Equal(t, eventstream.TypeReceipt, tail[1].Type)
+	require.Equal(t, reqID.String(), tail[1].Receipt.RequestID)
+	require.Empty(t, tail[1].Receipt.Error)
+	require.Equal(t, eventstream.TypeEvent, tail[2].Type)
+	require.Equal(t, "text event", tail[2].Event.Text)
+	require.Empty(t, tail[2].Event.Name)
+	require.Equal(t, "emitted", tail[3].Event.Name)
+	require.Equal(t, reqID.String(), tail[3].Event.RequestID)
+	require.Equal(t, "42", tail[3].Event.Fields[0].Text)
+
+	// the client resumes after the last received event
+	c = e.subscribe(url.Values{eventstream.ParamCursor: {tail[1].Cursor.String()}})
+	require.Equal(t, tail[2:], e.read(c, 2))
+}
+
+func TestFilter(t *testing.T) {
+	e := setup(t)
+	reqID1 := e.emit()
+	reqID2 := e.emit()
+
+	c := e.subscribe(url.Values{
+		eventstream.ParamCursor:    {"0-0"},
+		eventstream.ParamContract:  {emitterContract.Hname().String()},
+		eventstream.ParamEventName: {"emitted"},
+	})
+	evs := e.read(c, 2)
+	require.Equal(t, reqID1.String(), evs[0].Event.RequestID)
+	require.Equal(t, reqID2.String(), evs[1].Event.RequestID)
+
+	c = e.subscribe(url.Values{
+		eventstream.ParamCursor:    {"0-0"},
+		eventstream.ParamRequestID: {reqID2.String()},
+		eventstream.ParamTypes:     {eventstream.TypeReceipt},
+	})
+	evs = e.read(c, 1)
+	require.Equal(t, reqID2.String(), evs[0].Receipt.RequestID)
+	require.Equal(t, emitterContract.Hname().String(), evs[0].Receipt.Contract)
+
+	_, _, err := websocket.Dial(context.Background(),
+		"ws"+strings.TrimPrefix(e.server.URL, "http")+"?types=unknown", nil)
+	require.Error(t, err)
+}
+
+func TestLive(t *testing.T) {
+	e := setup(t)
+	c := e.subscribe(url.Values{eventstream.ParamTypes: {eventstream.TypeReceipt}})
+	// the subscription starts with the next block
+	time.Sleep(100 * time.Millisecond)
+
+	reqID := e.emit()
+	evs := e.read(c, 1)
+	require.Equal(t, reqID.String(), evs[0].Receipt.RequestID)
+}
+
+func TestRotation(t *testing.T) {
+	e := setup(t)
+	newKP, newAddr := e.ch.Env.NewKeyPair()
+	require.NoError(t, e.ch.AddAllowedStateController(newAddr, nil))
+	require.NoError(t, e.ch.RotateStateController(newAddr, newKP, nil))
+	e.emit()
+
+	c := e.subscribe(url.Values{
+		eventstream.ParamCursor: {"0-0"},
+		eventstream.ParamTypes:  {eventstream.TypeRotation},
+	})
+	evs := e.read(c, 1)
+	require.Equal(t, newAddr.Base58(), evs[0].Rotation.StateAddress)
+}
+
+func TestCursor(t *testing.T) {
+	cursor, err := eventstream.ParseCursor("12-3")
+	require.NoError(t, err)
+	require.Equal(t, eventstream.Cursor{BlockIndex: 12, Seq: 3}, cursor)
+	require.Equal(t, "12-3", cursor.String())
+	require.True(t, cursor.Less(eventstream.Cursor{BlockIndex: 12, Seq: 4}))
+	require.True(t, cursor.Less(eventstream.Cursor{BlockIndex: 13}))
+	require.False(t, cursor.Less(cursor))
+
+	for _, s := range []string{"", "12", "12-", "-3", "a-3", "12-3-4"} {
+		_, err = eventstream.ParseCursor(s)
+		require.Error(t, err, s)
+	}
+}
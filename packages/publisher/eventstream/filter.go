@@ -0,0 +1,102 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package eventstream
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"golang.org/x/xerrors"
+)
+
+// Query parameters of the subscription
+const (
+	// ParamCursor is the cursor of the last event received by the client. The stream continues after it,
+	// with the events already in the blocklog first. Without the cursor the stream starts with the next block
+	ParamCursor = "cursor"
+	// ParamTypes is the comma separated list of the types of the events. Empty means all types
+	ParamTypes = "types"
+	// ParamContract is the hname of the contract the receipts and the events are filtered by
+	ParamContract = "contract"
+	// ParamEventName is the name of the typed event the events are filtered by
+	ParamEventName = "event"
+	// ParamRequestID is the ID of the request the receipts and the events are filtered by
+	ParamRequestID = "requestId"
+)
+
+// Filter selects the events of the stream. The filters by the contract, the event name and the request ID
+// select the receipts and the contract events, so the blocks and the rotations don't pass them
+type Filter struct {
+	Types     map[string]bool
+	Contract  *iscp.Hname
+	EventName string
+	RequestID *iscp.RequestID
+}
+
+// FilterFromQuery parses the filter from the query parameters
+func FilterFromQuery(query url.Values) (*Filter, error) {
+	ret := &Filter{Types: make(map[string]bool)}
+	if types := query.Get(ParamTypes); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			if !isKnownType(t) {
+				return nil, xerrors.Errorf("unknown event type '%s'", t)
+			}
+			ret.Types[t] = true
+		}
+	}
+	if s := query.Get(ParamContract); s != "" {
+		hname, err := iscp.HnameFromString(s)
+		if err != nil {
+			return nil, xerrors.Errorf("wrong contract hname: %w", err)
+		}
+		ret.Contract = &hname
+	}
+	ret.EventName = query.Get(ParamEventName)
+	if s := query.Get(ParamRequestID); s != "" {
+		reqID, err := iscp.RequestIDFromString(s)
+		if err != nil {
+			return nil, xerrors.Errorf("wrong request ID: %w", err)
+		}
+		ret.RequestID = &reqID
+	}
+	return ret, nil
+}
+
+func isKnownType(t string) bool {
+	for _, known := range allTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches tells if the event passes the filter
+func (f *Filter) Matches(ev *Event) bool {
+	if len(f.Types) > 0 && !f.Types[ev.Type] {
+		return false
+	}
+	switch ev.Type {
+	case TypeReceipt:
+		if f.EventName != "" {
+			return false
+		}
+		return f.matchesContract(ev.Receipt.Contract) && f.matchesRequest(ev.Receipt.RequestID)
+	case TypeEvent:
+		if f.EventName != "" && ev.Event.Name != f.EventName {
+			return false
+		}
+		return f.matchesContract(ev.Event.Contract) && f.matchesRequest(ev.Event.RequestID)
+	}
+	return f.Contract == nil && f.EventName == "" && f.RequestID == nil
+}
+
+func (f *Filter) matchesContract(hname string) bool {
+	return f.Contract == nil || f.Contract.String() == hname
+}
+
+func (f *Filter) matchesRequest(reqID string) bool {
+	return f.RequestID == nil || f.RequestID.String() == reqID
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/signer"
+	flag "github.com/spf13/pflag"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// CfgKeysPassphrase is the passphrase to encrypt the node identity and the key shares at rest.
+	CfgKeysPassphrase = "keys.passphrase"
+	// CfgKeysPassphraseFile is the file with the passphrase, an alternative to CfgKeysPassphrase.
+	CfgKeysPassphraseFile = "keys.passphraseFile"
+	// CfgKeysSignerSocket is the Unix socket of the signer process holding the keys instead of the node.
+	CfgKeysSignerSocket = "keys.signerSocket"
+)
+
+// ErrKeysHeldBySigner is returned when the secret keys are requested but they are held by the external signer
+var ErrKeysHeldBySigner = xerrors.New("the keys are held by the external signer")
+
+func InitFlags() {
+	flag.String(CfgKeysPassphrase, "", "passphrase to encrypt the node identity and the DK shares in the database. Empty (default) means they are stored unencrypted")
+	flag.String(CfgKeysPassphraseFile, "", "file with the passphrase to encrypt the node identity and the DK shares in the database")
+	flag.String(CfgKeysSignerSocket, "", "Unix socket of the signer process which holds the node identity and the DK shares. Empty (default) means the keys are held by the node")
+}
+
+// EncryptorFromConfig creates the encryptor of the secrets with the configured passphrase.
+// Returns nil if no passphrase is configured
+func EncryptorFromConfig() (*signer.Encryptor, error) {
+	passphrase := parameters.GetString(CfgKeysPassphrase)
+	passphraseFile := parameters.GetString(CfgKeysPassphraseFile)
+	switch {
+	case passphrase != "" && passphraseFile != "":
+		return nil, xerrors.Errorf("only one of %s and %s can be set", CfgKeysPassphrase, CfgKeysPassphraseFile)
+	case passphrase != "":
+		return signer.NewEncryptor([]byte(passphrase))
+	case passphraseFile != "":
+		return signer.NewEncryptorFromFile(passphraseFile)
+	}
+	return nil, nil
+}
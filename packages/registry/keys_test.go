@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func newTestDKShare(t *testing.T) *tcrypto.DKShare {
+	suite := tcrypto.DefaultSuite()
+	priPoly := share.NewPriPoly(suite.G2(), 1, nil, random.New())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	dkShare, err := tcrypto.NewDKShare(
+		0, 1, 1,
+		pubPoly.Commit(),
+		[]kyber.Point{pubPoly.Commit()},
+		[]kyber.Point{pubPoly.Eval(0).V},
+		priPoly.Shares(1)[0].V,
+	)
+	require.NoError(t, err)
+	return dkShare
+}
+
+func TestEncryptedKeys(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	store := mapdb.NewMapDB()
+	dkShare := newTestDKShare(t)
+
+	// the keys stored unencrypted
+	reg := NewRegistry(log, store)
+	identity, err := reg.GetNodeIdentity()
+	require.NoError(t, err)
+	require.NoError(t, reg.SaveDKShare(dkShare))
+	data, err := store.Get(dbKeyForNodeIdentity())
+	require.NoError(t, err)
+	require.False(t, signer.IsEncrypted(data))
+
+	// they are encrypted once the encryption is enabled
+	enc, err := signer.NewEncryptor([]byte("passphrase"))
+	require.NoError(t, err)
+	reg = NewRegistry(log, store).WithEncryption(enc)
+	identity2, err := reg.GetNodeIdentity()
+	require.NoError(t, err)
+	require.Equal(t, identity.PrivateKey, identity2.PrivateKey)
+	dkShare2, err := reg.LoadDKShare(dkShare.Address)
+	require.NoError(t, err)
+	require.Equal(t, dkShare.Bytes(), dkShare2.Bytes())
+	for _, key := range [][]byte{dbKeyForNodeIdentity(), dbKeyForDKShare(dkShare.Address)} {
+		data, err = store.Get(key)
+		require.NoError(t, err)
+		require.True(t, signer.IsEncrypted(data))
+	}
+
+	identity2, err = reg.GetNodeIdentity()
+	require.NoError(t, err)
+	require.Equal(t, identity.PrivateKey, identity2.PrivateKey)
+
+	// the encrypted keys can't be read without the passphrase
+	_, err = NewRegistry(log, store).GetNodeIdentity()
+	require.Error(t, err)
+	other, err := signer.NewEncryptor([]byte("other passphrase"))
+	require.NoError(t, err)
+	_, err = NewRegistry(log, store).WithEncryption(other).LoadDKShare(dkShare.Address)
+	require.Error(t, err)
+}
+
+func TestExternalSigner(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	dkShare := newTestDKShare(t)
+
+	identity := ed25519.GenerateKeyPair()
+	signerStore := NewRegistry(log, mapdb.NewMapDB())
+	external := signer.NewLocal(&identity, signerStore)
+
+	store := mapdb.NewMapDB()
+	reg := NewRegistry(log, store).WithSigner(external)
+	_, err := reg.GetNodeIdentity()
+	require.ErrorIs(t, err, ErrKeysHeldBySigner)
+	pubKey, err := reg.GetNodePublicKey()
+	require.NoError(t, err)
+	require.Equal(t, identity.PublicKey, *pubKey)
+	nodeSigner, err := reg.NodeSigner()
+	require.NoError(t, err)
+	require.Equal(t, identity.PublicKey, nodeSigner.PublicKey())
+
+	// the DK share is kept by the signer only
+	require.NoError(t, reg.SaveDKShare(dkShare))
+	has, err := store.Has(dbKeyForDKShare(dkShare.Address))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	loaded, err := reg.LoadDKShare(dkShare.Address)
+	require.NoError(t, err)
+	require.Nil(t, loaded.PrivateShare)
+	data := []byte("data to sign")
+	sigShare, err := loaded.SignShare(data)
+	require.NoError(t, err)
+	require.NoError(t, loaded.VerifySigShare(data, sigShare))
+}
@@ -40,6 +40,10 @@ type PeerNetworkConfigProvider interface { // TODO: KP: Remove or redesign.
 // It should be implemented by registry.impl
 type DKShareRegistryProvider interface {
 	SaveDKShare(dkShare *tcrypto.DKShare) error
+	// ReplaceDKShare stores the share of the key reshared to a new committee in place of the existing one.
+	ReplaceDKShare(dkShare *tcrypto.DKShare) error
+	// DeleteDKShare removes the share of the node, which is not a member of the committee anymore.
+	DeleteDKShare(sharedAddress ledgerstate.Address) error
 	LoadDKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error)
 }
 
@@ -17,7 +17,9 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/packages/tcrypto"
+	"golang.org/x/xerrors"
 )
 
 // region Registry /////////////////////////////////////////////////////////
@@ -25,8 +27,10 @@ import (
 // Impl is just a placeholder to implement all interfaces needed by different components.
 // Each of the interfaces are implemented in the corresponding file in this package.
 type Impl struct {
-	log   *logger.Logger
-	store kvstore.KVStore
+	log       *logger.Logger
+	store     kvstore.KVStore
+	encryptor *signer.Encryptor // Encrypts the node identity and the DK shares, if set.
+	signer    signer.Signer     // Holds the node identity and the DK shares instead of the store, if set.
 }
 
 // New creates new instance of the registry implementation.
@@ -37,6 +41,63 @@ func NewRegistry(log *logger.Logger, store kvstore.KVStore) *Impl {
 	}
 }
 
+// WithEncryption makes the registry keep the node identity and the DK shares encrypted.
+// The ones stored unencrypted are encrypted when they are read.
+func (r *Impl) WithEncryption(encryptor *signer.Encryptor) *Impl {
+	r.encryptor = encryptor
+	return r
+}
+
+// WithSigner makes the registry use the external signer, which holds the node identity and the DK shares.
+func (r *Impl) WithSigner(s signer.Signer) *Impl {
+	r.signer = s
+	return r
+}
+
+// NodeSigner returns the signer of the node: the external signer, if configured,
+// or the one holding the node identity and the DK shares of the registry.
+func (r *Impl) NodeSigner() (signer.Signer, error) {
+	if r.signer != nil {
+		return r.signer, nil
+	}
+	identity, err := r.GetNodeIdentity()
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewLocal(identity, r), nil
+}
+
+// getSecret reads the secret, decrypting it if needed. An unencrypted secret is encrypted, if the encryption is enabled.
+func (r *Impl) getSecret(key []byte) ([]byte, error) {
+	data, err := r.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if signer.IsEncrypted(data) {
+		if r.encryptor == nil {
+			return nil, xerrors.New("the secret is encrypted, but no passphrase is configured")
+		}
+		return r.encryptor.Decrypt(data)
+	}
+	if r.encryptor != nil {
+		if err := r.setSecret(key, data); err != nil {
+			return nil, err
+		}
+		r.log.Info("An unencrypted secret has been encrypted.")
+	}
+	return data, nil
+}
+
+func (r *Impl) setSecret(key, data []byte) error {
+	var err error
+	if r.encryptor != nil {
+		if data, err = r.encryptor.Encrypt(data); err != nil {
+			return err
+		}
+	}
+	return r.store.Set(key, data)
+}
+
 // endregion ////////////////////////////////////////////////////////
 
 // region ChainRecordProvider /////////////////////////////////////////////////////////
@@ -138,7 +199,11 @@ func (r *Impl) SaveCommitteeRecord(rec *CommitteeRecord) error {
 // region DKShareRegistryProvider ////////////////////////////////////////////////////
 
 // SaveDKShare implements dkg.DKShareRegistryProvider.
+// The share is handed over to the external signer, if it is configured.
 func (r *Impl) SaveDKShare(dkShare *tcrypto.DKShare) error {
+	if r.signer != nil {
+		return r.signer.ImportDKShare(dkShare)
+	}
 	var err error
 	var exists bool
 	dbKey := dbKeyForDKShare(dkShare.Address)
@@ -149,12 +214,37 @@ func (r *Impl) SaveDKShare(dkShare *tcrypto.DKShare) error {
 	if exists {
 		return fmt.Errorf("attempt to overwrite existing DK key share")
 	}
-	return r.store.Set(dbKey, dkShare.Bytes())
+	return r.setSecret(dbKey, dkShare.Bytes())
+}
+
+// ReplaceDKShare implements dkg.DKShareRegistryProvider.
+func (r *Impl) ReplaceDKShare(dkShare *tcrypto.DKShare) error {
+	if r.signer != nil {
+		return fmt.Errorf("resharing of the key shares held by the external signer is not supported")
+	}
+	return r.setSecret(dbKeyForDKShare(dkShare.Address), dkShare.Bytes())
+}
+
+// DeleteDKShare implements dkg.DKShareRegistryProvider.
+func (r *Impl) DeleteDKShare(sharedAddress ledgerstate.Address) error {
+	if r.signer != nil {
+		return fmt.Errorf("resharing of the key shares held by the external signer is not supported")
+	}
+	return r.store.Delete(dbKeyForDKShare(sharedAddress))
 }
 
 // LoadDKShare implements dkg.DKShareRegistryProvider.
+// The share held by the external signer comes without the private part and signs through the signer.
 func (r *Impl) LoadDKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error) {
-	data, err := r.store.Get(dbKeyForDKShare(sharedAddress))
+	if r.signer != nil {
+		dkShare, err := r.signer.DKShare(sharedAddress)
+		if err != nil {
+			return nil, err
+		}
+		dkShare.SetSigner(r.signer)
+		return dkShare, nil
+	}
+	data, err := r.getSecret(dbKeyForDKShare(sharedAddress))
 	if err != nil {
 		return nil, err
 	}
@@ -301,7 +391,11 @@ func (r *Impl) HasBlob(h hashing.HashValue) (bool, error) {
 // region NodeIdentity //////////////////////////////////////////
 
 // GetNodeIdentity implements NodeIdentityProvider.
+// The identity held by the external signer is not available, use NodeSigner instead.
 func (r *Impl) GetNodeIdentity() (*ed25519.KeyPair, error) {
+	if r.signer != nil {
+		return nil, ErrKeysHeldBySigner
+	}
 	var err error
 	var pair ed25519.KeyPair
 	dbKey := dbKeyForNodeIdentity()
@@ -311,13 +405,13 @@ func (r *Impl) GetNodeIdentity() (*ed25519.KeyPair, error) {
 	if !exists {
 		pair = ed25519.GenerateKeyPair()
 		data = pair.PrivateKey.Bytes()
-		if err := r.store.Set(dbKey, data); err != nil {
+		if err := r.setSecret(dbKey, data); err != nil {
 			return nil, err
 		}
 		r.log.Info("Node identity key pair generated.")
 		return &pair, nil
 	}
-	if data, err = r.store.Get(dbKey); err != nil {
+	if data, err = r.getSecret(dbKey); err != nil {
 		return nil, err
 	}
 	if pair.PrivateKey, err, _ = ed25519.PrivateKeyFromBytes(data); err != nil {
@@ -329,6 +423,10 @@ func (r *Impl) GetNodeIdentity() (*ed25519.KeyPair, error) {
 
 // GetNodePublicKey implements NodeIdentityProvider.
 func (r *Impl) GetNodePublicKey() (*ed25519.PublicKey, error) {
+	if r.signer != nil {
+		pubKey := r.signer.PublicKey()
+		return &pubKey, nil
+	}
 	var err error
 	var pair *ed25519.KeyPair
 	if pair, err = r.GetNodeIdentity(); err != nil {
@@ -0,0 +1,142 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"golang.org/x/xerrors"
+)
+
+// DefaultCallTimeout is the time the client waits for the response of the signer process
+const DefaultCallTimeout = 10 * time.Second
+
+// Client is the signer, which holds the keys in a separate signer process reached over a Unix socket.
+// The client reconnects to the signer process if the connection breaks
+type Client struct {
+	socketPath string
+	publicKey  ed25519.PublicKey
+	conn       net.Conn
+	mutex      sync.Mutex
+}
+
+var _ Signer = &Client{}
+
+// Dial connects to the signer process listening on the Unix socket
+func Dial(socketPath string) (*Client, error) {
+	c := &Client{socketPath: socketPath}
+	data, err := c.call(opPublicKey, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("signer.Dial: %w", err)
+	}
+	if c.publicKey, _, err = ed25519.PublicKeyFromBytes(data); err != nil {
+		return nil, xerrors.Errorf("signer.Dial: %w", err)
+	}
+	return c, nil
+}
+
+func (c *Client) PublicKey() ed25519.PublicKey {
+	return c.publicKey
+}
+
+func (c *Client) Sign(data []byte) (ed25519.Signature, error) {
+	ret, err := c.call(opSign, data)
+	if err != nil {
+		return ed25519.Signature{}, err
+	}
+	sig, _, err := ed25519.SignatureFromBytes(ret)
+	return sig, err
+}
+
+func (c *Client) SignShare(sharedAddress ledgerstate.Address, data []byte) (tbls.SigShare, error) {
+	payload := make([]byte, 0, ledgerstate.AddressLength+len(data))
+	payload = append(payload, sharedAddress.Bytes()...)
+	payload = append(payload, data...)
+	ret, err := c.call(opSignShare, payload)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c *Client) DecryptShare(sharedAddress ledgerstate.Address, ciphertext, additionalData []byte) (*tcrypto.DecryptionShare, error) {
+	var payload bytes.Buffer
+	payload.Write(sharedAddress.Bytes())
+	if err := util.WriteBytes16(&payload, additionalData); err != nil {
+		return nil, err
+	}
+	payload.Write(ciphertext)
+	ret, err := c.call(opDecryptShare, payload.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tcrypto.DecryptionShareFromBytes(ret, tcrypto.DefaultSuite())
+}
+
+func (c *Client) ImportDKShare(dkShare *tcrypto.DKShare) error {
+	_, err := c.call(opImportDKShare, dkShare.Bytes())
+	return err
+}
+
+func (c *Client) DKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error) {
+	ret, err := c.call(opDKShare, sharedAddress.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tcrypto.DKShareFromBytes(ret, tcrypto.DefaultSuite())
+}
+
+// Close closes the connection to the signer process
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *Client) call(op byte, payload []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == nil {
+		conn, err := net.DialTimeout("unix", c.socketPath, DefaultCallTimeout)
+		if err != nil {
+			return nil, err
+		}
+		c.conn = conn
+	}
+	status, ret, err := c.roundTrip(op, payload)
+	if err != nil {
+		// the connection is in an unknown state, it is re-established by the next call
+		_ = c.conn.Close()
+		c.conn = nil
+		return nil, err
+	}
+	if status != statusOK {
+		return nil, xerrors.Errorf("signer: %s", string(ret))
+	}
+	return ret, nil
+}
+
+func (c *Client) roundTrip(op byte, payload []byte) (byte, []byte, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(DefaultCallTimeout)); err != nil {
+		return 0, nil, err
+	}
+	if err := writeFrame(c.conn, op, payload); err != nil {
+		return 0, nil, err
+	}
+	return readFrame(c.conn)
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	saltLength  = 16
+	nonceLength = 24
+	keyLength   = 32
+
+	// scrypt parameters of the key derivation
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// encryptedPrefix marks the encrypted secrets, so that the plain ones stored by the previous versions are recognized
+var encryptedPrefix = []byte("wasp-enc-v1:")
+
+// Encryptor encrypts the secrets at rest with a key derived from the passphrase.
+// Each secret is encrypted with its own salt and nonce
+type Encryptor struct {
+	passphrase []byte
+	keys       map[[saltLength]byte]*[keyLength]byte // derived keys by salt
+	keysMutex  sync.Mutex
+}
+
+// NewEncryptor creates an encryptor with the passphrase
+func NewEncryptor(passphrase []byte) (*Encryptor, error) {
+	if len(passphrase) == 0 {
+		return nil, xerrors.New("NewEncryptor: the passphrase is empty")
+	}
+	return &Encryptor{
+		passphrase: passphrase,
+		keys:       make(map[[saltLength]byte]*[keyLength]byte),
+	}, nil
+}
+
+// NewEncryptorFromFile creates an encryptor with the content of the key file as the passphrase
+func NewEncryptorFromFile(fname string) (*Encryptor, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, xerrors.Errorf("NewEncryptorFromFile: %w", err)
+	}
+	return NewEncryptor(bytes.TrimSpace(data))
+}
+
+// IsEncrypted checks if the data has been encrypted by an Encryptor
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedPrefix)
+}
+
+func (e *Encryptor) Encrypt(plain []byte) ([]byte, error) {
+	var salt [saltLength]byte
+	var nonce [nonceLength]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	key, err := e.key(salt)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]byte, 0, len(encryptedPrefix)+saltLength+nonceLength+len(plain)+secretbox.Overhead)
+	ret = append(ret, encryptedPrefix...)
+	ret = append(ret, salt[:]...)
+	ret = append(ret, nonce[:]...)
+	return secretbox.Seal(ret, plain, &nonce, key), nil
+}
+
+func (e *Encryptor) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, xerrors.New("Decrypt: the data is not encrypted")
+	}
+	data = data[len(encryptedPrefix):]
+	if len(data) < saltLength+nonceLength+secretbox.Overhead {
+		return nil, xerrors.New("Decrypt: the data is too short")
+	}
+	var salt [saltLength]byte
+	var nonce [nonceLength]byte
+	copy(salt[:], data[:saltLength])
+	copy(nonce[:], data[saltLength:saltLength+nonceLength])
+	key, err := e.key(salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, ok := secretbox.Open(nil, data[saltLength+nonceLength:], &nonce, key)
+	if !ok {
+		return nil, xerrors.New("Decrypt: wrong passphrase or corrupted data")
+	}
+	return plain, nil
+}
+
+// key derives the key for the salt. The key derivation is slow on purpose, so the keys are cached
+func (e *Encryptor) key(salt [saltLength]byte) (*[keyLength]byte, error) {
+	e.keysMutex.Lock()
+	defer e.keysMutex.Unlock()
+
+	if key, ok := e.keys[salt]; ok {
+		return key, nil
+	}
+	derived, err := scrypt.Key(e.passphrase, salt[:], scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	var key [keyLength]byte
+	copy(key[:], derived)
+	e.keys[salt] = &key
+	return &key, nil
+}
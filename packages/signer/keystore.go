@@ -0,0 +1,103 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"golang.org/x/xerrors"
+)
+
+const (
+	identityFileName = "identity.key"
+	dkSharesDirName  = "dkshares"
+)
+
+// FileKeyStore keeps the keys of the signer process in a directory, each key in its own file
+type FileKeyStore struct {
+	dir       string
+	encryptor *Encryptor
+}
+
+var _ DKShareStore = &FileKeyStore{}
+
+// NewFileKeyStore opens the key store in the directory, the keys are encrypted with the encryptor.
+// A nil encryptor means the keys are stored in plain bytes
+func NewFileKeyStore(dir string, encryptor *Encryptor) (*FileKeyStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, dkSharesDirName), 0o700); err != nil {
+		return nil, xerrors.Errorf("NewFileKeyStore: %w", err)
+	}
+	return &FileKeyStore{dir: dir, encryptor: encryptor}, nil
+}
+
+// NodeIdentity returns the key pair of the node identity. The key pair is generated, if it doesn't exist yet
+func (ks *FileKeyStore) NodeIdentity() (*ed25519.KeyPair, error) {
+	fname := filepath.Join(ks.dir, identityFileName)
+	data, err := ks.read(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		pair := ed25519.GenerateKeyPair()
+		if err = ks.write(fname, pair.PrivateKey.Bytes()); err != nil {
+			return nil, err
+		}
+		return &pair, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pair ed25519.KeyPair
+	if pair.PrivateKey, err, _ = ed25519.PrivateKeyFromBytes(data); err != nil {
+		return nil, err
+	}
+	pair.PublicKey = pair.PrivateKey.Public()
+	return &pair, nil
+}
+
+func (ks *FileKeyStore) SaveDKShare(dkShare *tcrypto.DKShare) error {
+	fname := ks.dkShareFileName(dkShare.Address)
+	if _, err := os.Stat(fname); err == nil {
+		return xerrors.Errorf("attempt to overwrite existing DK key share")
+	}
+	return ks.write(fname, dkShare.Bytes())
+}
+
+func (ks *FileKeyStore) LoadDKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error) {
+	data, err := ks.read(ks.dkShareFileName(sharedAddress))
+	if err != nil {
+		return nil, err
+	}
+	return tcrypto.DKShareFromBytes(data, tcrypto.DefaultSuite())
+}
+
+func (ks *FileKeyStore) dkShareFileName(sharedAddress ledgerstate.Address) string {
+	return filepath.Join(ks.dir, dkSharesDirName, sharedAddress.Base58()+".key")
+}
+
+func (ks *FileKeyStore) read(fname string) ([]byte, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if ks.encryptor == nil {
+		return nil, xerrors.Errorf("%s is encrypted, the passphrase is needed", fname)
+	}
+	return ks.encryptor.Decrypt(data)
+}
+
+func (ks *FileKeyStore) write(fname string, data []byte) error {
+	var err error
+	if ks.encryptor != nil {
+		if data, err = ks.encryptor.Encrypt(data); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(fname, data, 0o600)
+}
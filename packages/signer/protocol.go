@@ -0,0 +1,55 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+// The protocol between the node and the signer process. Each request is a frame with the operation
+// code followed by the payload. The response is a frame with the status followed by the result
+// or the error message.
+const (
+	opPublicKey = byte(iota)
+	opSign
+	opSignShare
+	opImportDKShare
+	opDKShare
+	opDecryptShare
+)
+
+const (
+	statusOK = byte(iota)
+	statusError
+)
+
+// maxFrameSize limits the size of the frames, the biggest ones are the key shares of large committees
+const maxFrameSize = 1 << 20
+
+func writeFrame(w io.Writer, code byte, payload []byte) error {
+	buf := make([]byte, 5, 5+len(payload))
+	binary.LittleEndian.PutUint32(buf[:4], uint32(len(payload)+1))
+	buf[4] = code
+	_, err := w.Write(append(buf, payload...))
+	return err
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var sizeBytes [4]byte
+	if _, err := io.ReadFull(r, sizeBytes[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.LittleEndian.Uint32(sizeBytes[:])
+	if size == 0 || size > maxFrameSize {
+		return 0, nil, xerrors.Errorf("readFrame: wrong frame size %d", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, nil, err
+	}
+	return buf[0], buf[1:], nil
+}
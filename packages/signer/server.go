@@ -0,0 +1,161 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// Server serves the signer to the node over a Unix socket. It is run by the signer process
+type Server struct {
+	signer   Signer
+	listener net.Listener
+	log      *logger.Logger
+}
+
+// NewServer starts listening on the Unix socket. Only the owner of the process may connect to the socket
+func NewServer(signer Signer, socketPath string, log *logger.Logger) (*Server, error) {
+	if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, xerrors.Errorf("signer.NewServer: %w", err)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, xerrors.Errorf("signer.NewServer: %w", err)
+	}
+	if err := os.Chmod(socketPath, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, xerrors.Errorf("signer.NewServer: %w", err)
+	}
+	return &Server{
+		signer:   signer,
+		listener: listener,
+		log:      log,
+	}, nil
+}
+
+// Run serves the connections until the shutdown signal
+func (s *Server) Run(shutdownSignal <-chan struct{}) {
+	var wg sync.WaitGroup
+	conns := make(map[net.Conn]struct{})
+	var connsMutex sync.Mutex
+	go func() {
+		<-shutdownSignal
+		_ = s.listener.Close()
+		connsMutex.Lock()
+		for conn := range conns {
+			_ = conn.Close()
+		}
+		connsMutex.Unlock()
+	}()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-shutdownSignal:
+				wg.Wait()
+				return
+			default:
+			}
+			s.log.Errorf("accepting connection: %v", err)
+			continue
+		}
+		connsMutex.Lock()
+		conns[conn] = struct{}{}
+		connsMutex.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(conn)
+			connsMutex.Lock()
+			delete(conns, conn)
+			connsMutex.Unlock()
+		}()
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	for {
+		op, payload, err := readFrame(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.log.Warnf("reading request: %v", err)
+			}
+			return
+		}
+		ret, err := s.handle(op, payload)
+		if err != nil {
+			s.log.Warnf("request %d failed: %v", op, err)
+			err = writeFrame(conn, statusError, []byte(err.Error()))
+		} else {
+			err = writeFrame(conn, statusOK, ret)
+		}
+		if err != nil {
+			s.log.Warnf("writing response: %v", err)
+			return
+		}
+	}
+}
+
+func (s *Server) handle(op byte, payload []byte) ([]byte, error) {
+	switch op {
+	case opPublicKey:
+		return s.signer.PublicKey().Bytes(), nil
+	case opSign:
+		sig, err := s.signer.Sign(payload)
+		if err != nil {
+			return nil, err
+		}
+		return sig.Bytes(), nil
+	case opSignShare:
+		addr, consumed, err := ledgerstate.AddressFromBytes(payload)
+		if err != nil {
+			return nil, err
+		}
+		return s.signer.SignShare(addr, payload[consumed:])
+	case opDecryptShare:
+		addr, consumed, err := ledgerstate.AddressFromBytes(payload)
+		if err != nil {
+			return nil, err
+		}
+		r := bytes.NewReader(payload[consumed:])
+		additionalData, err := util.ReadBytes16(r)
+		if err != nil {
+			return nil, err
+		}
+		decShare, err := s.signer.DecryptShare(addr, payload[len(payload)-r.Len():], additionalData)
+		if err != nil {
+			return nil, err
+		}
+		return decShare.Bytes(), nil
+	case opImportDKShare:
+		dkShare, err := tcrypto.DKShareFromBytes(payload, tcrypto.DefaultSuite())
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.signer.ImportDKShare(dkShare)
+	case opDKShare:
+		addr, _, err := ledgerstate.AddressFromBytes(payload)
+		if err != nil {
+			return nil, err
+		}
+		dkShare, err := s.signer.DKShare(addr)
+		if err != nil {
+			return nil, err
+		}
+		return dkShare.Bytes(), nil
+	}
+	return nil, xerrors.Errorf("unknown operation %d", op)
+}
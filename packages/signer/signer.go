@@ -0,0 +1,107 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signer abstracts the signing with the secret keys of the node: the node identity key
+// and the private key shares of the committees the node takes part in.
+// The keys are either held by the node itself (Local) or by a separate signer process,
+// reached over a Unix socket (Client and Server).
+package signer
+
+import (
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"golang.org/x/xerrors"
+)
+
+// Signer signs on behalf of the node. The secret keys never leave the signer.
+type Signer interface {
+	// PublicKey is the public key of the node identity
+	PublicKey() ed25519.PublicKey
+	// Sign signs the data with the node identity key
+	Sign(data []byte) (ed25519.Signature, error)
+	// SignShare signs the data with the private key share of the committee with the shared address
+	SignShare(sharedAddress ledgerstate.Address, data []byte) (tbls.SigShare, error)
+	// DecryptShare produces the decryption share of the ciphertext with the private key share of the committee,
+	// if the ciphertext was produced for the additional data
+	DecryptShare(sharedAddress ledgerstate.Address, ciphertext, additionalData []byte) (*tcrypto.DecryptionShare, error)
+	// ImportDKShare hands the key share, produced by the DKG, over to the signer
+	ImportDKShare(dkShare *tcrypto.DKShare) error
+	// DKShare returns the key share without its private part
+	DKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error)
+}
+
+// DKShareStore is where the signer keeps the key shares
+type DKShareStore interface {
+	SaveDKShare(dkShare *tcrypto.DKShare) error
+	LoadDKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error)
+}
+
+// Local is the signer holding the keys in the process
+type Local struct {
+	identity *ed25519.KeyPair
+	dkShares DKShareStore
+}
+
+var _ Signer = &Local{}
+
+// NewLocal creates a signer with the node identity and the store of the key shares.
+// The store may be nil if the signer doesn't sign with the key shares
+func NewLocal(identity *ed25519.KeyPair, dkShares DKShareStore) *Local {
+	return &Local{
+		identity: identity,
+		dkShares: dkShares,
+	}
+}
+
+func (s *Local) PublicKey() ed25519.PublicKey {
+	return s.identity.PublicKey
+}
+
+func (s *Local) Sign(data []byte) (ed25519.Signature, error) {
+	return s.identity.PrivateKey.Sign(data), nil
+}
+
+func (s *Local) SignShare(sharedAddress ledgerstate.Address, data []byte) (tbls.SigShare, error) {
+	if s.dkShares == nil {
+		return nil, xerrors.New("SignShare: the signer doesn't hold key shares")
+	}
+	dkShare, err := s.dkShares.LoadDKShare(sharedAddress)
+	if err != nil {
+		return nil, xerrors.Errorf("SignShare: %w", err)
+	}
+	return dkShare.SignShare(data)
+}
+
+func (s *Local) DecryptShare(sharedAddress ledgerstate.Address, ciphertext, additionalData []byte) (*tcrypto.DecryptionShare, error) {
+	if s.dkShares == nil {
+		return nil, xerrors.New("DecryptShare: the signer doesn't hold key shares")
+	}
+	dkShare, err := s.dkShares.LoadDKShare(sharedAddress)
+	if err != nil {
+		return nil, xerrors.Errorf("DecryptShare: %w", err)
+	}
+	return dkShare.DecryptShare(ciphertext, additionalData)
+}
+
+func (s *Local) ImportDKShare(dkShare *tcrypto.DKShare) error {
+	if s.dkShares == nil {
+		return xerrors.New("ImportDKShare: the signer doesn't hold key shares")
+	}
+	if dkShare.PrivateShare == nil {
+		return xerrors.Errorf("ImportDKShare: private share of %s is missing", dkShare.Address.Base58())
+	}
+	return s.dkShares.SaveDKShare(dkShare)
+}
+
+func (s *Local) DKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error) {
+	if s.dkShares == nil {
+		return nil, xerrors.New("DKShare: the signer doesn't hold key shares")
+	}
+	dkShare, err := s.dkShares.LoadDKShare(sharedAddress)
+	if err != nil {
+		return nil, err
+	}
+	return dkShare.WithoutPrivateShare(), nil
+}
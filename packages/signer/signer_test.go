@@ -0,0 +1,150 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package signer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/testutil/testpeers"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+func TestEncryptor(t *testing.T) {
+	enc, err := signer.NewEncryptor([]byte("passphrase"))
+	require.NoError(t, err)
+	secret := []byte("secret key")
+
+	data, err := enc.Encrypt(secret)
+	require.NoError(t, err)
+	require.True(t, signer.IsEncrypted(data))
+	require.NotContains(t, string(data), string(secret))
+
+	plain, err := enc.Decrypt(data)
+	require.NoError(t, err)
+	require.Equal(t, secret, plain)
+
+	// each secret has its own salt and nonce
+	data2, err := enc.Encrypt(secret)
+	require.NoError(t, err)
+	require.NotEqual(t, data, data2)
+
+	other, err := signer.NewEncryptor([]byte("other passphrase"))
+	require.NoError(t, err)
+	_, err = other.Decrypt(data)
+	require.Error(t, err)
+	_, err = enc.Decrypt(secret)
+	require.Error(t, err)
+
+	_, err = signer.NewEncryptor(nil)
+	require.Error(t, err)
+}
+
+func TestFileKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(keyFile, []byte("passphrase\n"), 0o600))
+	enc, err := signer.NewEncryptorFromFile(keyFile)
+	require.NoError(t, err)
+
+	ks, err := signer.NewFileKeyStore(filepath.Join(dir, "keys"), enc)
+	require.NoError(t, err)
+	identity, err := ks.NodeIdentity()
+	require.NoError(t, err)
+
+	// the identity is kept and encrypted at rest
+	data, err := os.ReadFile(filepath.Join(dir, "keys", "identity.key"))
+	require.NoError(t, err)
+	require.True(t, signer.IsEncrypted(data))
+
+	ks, err = signer.NewFileKeyStore(filepath.Join(dir, "keys"), enc)
+	require.NoError(t, err)
+	identity2, err := ks.NodeIdentity()
+	require.NoError(t, err)
+	require.Equal(t, identity.PublicKey, identity2.PublicKey)
+
+	ks, err = signer.NewFileKeyStore(filepath.Join(dir, "keys"), nil)
+	require.NoError(t, err)
+	_, err = ks.NodeIdentity()
+	require.Error(t, err)
+}
+
+func TestClientServer(t *testing.T) {
+	log := testlogger.NewLogger(t)
+	defer log.Sync()
+
+	netIDs, _ := testpeers.SetupKeys(4)
+	address, dkShareRegistries := testpeers.SetupDkgPregenerated(t, 3, netIDs, tcrypto.DefaultSuite())
+	dkShare, err := dkShareRegistries[0].LoadDKShare(address)
+	require.NoError(t, err)
+
+	identity := ed25519.GenerateKeyPair()
+	local := signer.NewLocal(&identity, testutil.NewDkgRegistryProvider(tcrypto.DefaultSuite()))
+	socketPath := filepath.Join(t.TempDir(), "signer.sock")
+	server, err := signer.NewServer(local, socketPath, log)
+	require.NoError(t, err)
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+	go server.Run(shutdown)
+
+	client, err := signer.Dial(socketPath)
+	require.NoError(t, err)
+	defer client.Close()
+	require.Equal(t, identity.PublicKey, client.PublicKey())
+
+	data := []byte("data to sign")
+	sig, err := client.Sign(data)
+	require.NoError(t, err)
+	require.True(t, identity.PublicKey.VerifySignature(data, sig))
+
+	// the key share is not known to the signer yet
+	_, err = client.SignShare(address, data)
+	require.Error(t, err)
+	_, err = client.DKShare(address)
+	require.Error(t, err)
+
+	require.NoError(t, client.ImportDKShare(dkShare))
+	require.Error(t, client.ImportDKShare(dkShare.WithoutPrivateShare()))
+
+	public, err := client.DKShare(address)
+	require.NoError(t, err)
+	require.Nil(t, public.PrivateShare)
+	require.Equal(t, dkShare.WithoutPrivateShare().Bytes(), public.Bytes())
+
+	sigShare, err := client.SignShare(address, data)
+	require.NoError(t, err)
+	require.NoError(t, dkShare.VerifySigShare(data, sigShare))
+
+	// the share without the private part signs through the signer
+	public.SetSigner(client)
+	sigShare, err = public.SignShare(data)
+	require.NoError(t, err)
+	expected, err := dkShare.SignShare(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, sigShare)
+	idx, err := tbls.SigShare(sigShare).Index()
+	require.NoError(t, err)
+	require.EqualValues(t, *dkShare.Index, idx)
+
+	ciphertext, err := tcrypto.Encrypt(tcrypto.DefaultSuite(), dkShare.SharedPublic, data, nil)
+	require.NoError(t, err)
+	decShare, err := public.DecryptShare(ciphertext, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, *dkShare.Index, decShare.Index)
+	require.NoError(t, dkShare.VerifyDecryptionShare(ciphertext, decShare))
+	_, err = public.DecryptShare(ciphertext, []byte("other data"))
+	require.Error(t, err)
+
+	// the client reconnects after the connection breaks
+	require.NoError(t, client.Close())
+	_, err = client.Sign(data)
+	require.NoError(t, err)
+}
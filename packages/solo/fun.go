@@ -263,6 +263,43 @@ func (ch *Chain) DeployWasmContract(keyPair *ed25519.KeyPair, name, fname string
 	return ch.DeployContract(keyPair, name, hprog, params...)
 }
 
+// UpgradeContract replaces the program of the contract 'name' with the one identified by the program hash.
+// If 'migrate' is true, the 'migrate' entry point of the new program is called with the 'params'
+func (ch *Chain) UpgradeContract(keyPair *ed25519.KeyPair, name string, programHash hashing.HashValue, migrate bool, params ...interface{}) error {
+	par := codec.MakeDict(map[string]interface{}{
+		root.ParamHname:       iscp.Hn(name),
+		root.ParamProgramHash: programHash,
+		root.ParamMigrate:     migrate,
+	})
+	for k, v := range parseParams(params) {
+		par[k] = v
+	}
+	req := NewCallParams(root.Contract.Name, root.FuncUpgradeContract.Name, par).WithIotas(1)
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// UpgradeWasmContract is syntactic sugar for uploading Wasm binary from file and
+// upgrading the smart contract in one call
+func (ch *Chain) UpgradeWasmContract(keyPair *ed25519.KeyPair, name, fname string, migrate bool, params ...interface{}) error {
+	hprog, err := ch.UploadWasmFromFile(keyPair, fname)
+	if err != nil {
+		return err
+	}
+	return ch.UpgradeContract(keyPair, name, hprog, migrate, params...)
+}
+
+// GetContractHistory returns the versions of the contract recorded by its upgrades, the oldest first
+func (ch *Chain) GetContractHistory(name string) ([]*root.ContractVersion, error) {
+	res, err := ch.CallView(root.Contract.Name, root.FuncGetContractHistory.Name,
+		root.ParamHname, iscp.Hn(name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return root.DecodeContractHistory(collections.NewArray32ReadOnly(res, root.VarContractHistory))
+}
+
 // GetInfo return main parameters of the chain:
 //  - chainID
 //  - agentID of the chain owner
@@ -456,6 +493,16 @@ func (ch *Chain) GetEventsForBlock(blockIndex uint32) ([]string, error) {
 	return eventsFromViewResult(ch.Env.T, viewResult), nil
 }
 
+// GetTypedEvents calls the view in the 'blocklog' core smart contract to retrieve a page of typed events
+// selected by the query. It returns the events and the cursor of the next page, or nil if there are no more events
+func (ch *Chain) GetTypedEvents(q *blocklog.TypedEventsQuery) ([]*blocklog.EventRecord, *uint32, error) {
+	viewResult, err := ch.CallView(blocklog.Contract.Name, blocklog.FuncGetTypedEvents.Name, q.Params())
+	if err != nil {
+		return nil, nil, err
+	}
+	return blocklog.DecodeTypedEvents(viewResult)
+}
+
 // CommonAccount return the agentID of the common account (controlled by the owner)
 func (ch *Chain) CommonAccount() *iscp.AgentID {
 	return commonaccount.Get(ch.ChainID)
@@ -489,13 +536,28 @@ func (ch *Chain) GetBlockInfo(blockIndex uint32) (*blocklog.BlockInfo, error) {
 	return blockInfo, nil
 }
 
+// GetRandomBeacon returns the random beacon of the block, the seed derived from it and the state controller
+// address of the committee which produced it
+func (ch *Chain) GetRandomBeacon(blockIndex uint32) ([]byte, hashing.HashValue, ledgerstate.Address, error) {
+	ret, err := ch.CallView(blocklog.Contract.Name, blocklog.FuncGetRandomBeacon.Name,
+		blocklog.ParamBlockIndex, blockIndex)
+	if err != nil {
+		return nil, hashing.NilHash, nil, err
+	}
+	resultDecoder := kvdecoder.New(ret, ch.Log)
+	return resultDecoder.MustGetBytes(blocklog.ParamRandomBeacon),
+		resultDecoder.MustGetHashValue(blocklog.ParamRandomSeed),
+		resultDecoder.MustGetAddress(blocklog.ParamStateControllerAddress),
+		nil
+}
+
 // IsRequestProcessed checks if the request is booked on the chain as processed
 func (ch *Chain) IsRequestProcessed(reqID iscp.RequestID) bool {
 	ret, err := ch.CallView(blocklog.Contract.Name, blocklog.FuncIsRequestProcessed.Name,
 		blocklog.ParamRequestID, reqID)
 	require.NoError(ch.Env.T, err)
 	resultDecoder := kvdecoder.New(ret, ch.Log)
-	bin, err := resultDecoder.GetBytes(blocklog.ParamRequestProcessed)
+	bin, err := resultDecoder.GetBytes(blocklog.ParamRequestProcessed, nil)
 	require.NoError(ch.Env.T, err)
 	return bin != nil
 }
@@ -630,6 +692,108 @@ func (ch *Chain) GetAllowedStateControllerAddresses() []ledgerstate.Address {
 	return ret
 }
 
+// SetPruningPolicy sets the pruning policy of the chain
+func (ch *Chain) SetPruningPolicy(policy governance.PruningPolicy, keyPair *ed25519.KeyPair) error {
+	req := NewCallParamsFromDic(coreutil.CoreContractGovernance, governance.FuncSetPruningPolicy.Name, policy.Params()).WithIotas(1)
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// GetPruningPolicy returns the pruning policy of the chain
+func (ch *Chain) GetPruningPolicy() governance.PruningPolicy {
+	res, err := ch.CallView(coreutil.CoreContractGovernance, governance.FuncGetPruningPolicy.Name)
+	require.NoError(ch.Env.T, err)
+	return governance.GetPruningPolicy(res)
+}
+
+// SetCouncil makes the chain governed by the council. The council becomes the chain owner
+func (ch *Chain) SetCouncil(council *governance.Council, keyPair *ed25519.KeyPair) error {
+	req := NewCallParamsFromDic(coreutil.CoreContractGovernance, governance.FuncSetCouncil.Name, council.Params()).WithIotas(1)
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// GetCouncil returns the council of the chain, or nil if the chain is not governed by a council
+func (ch *Chain) GetCouncil() *governance.Council {
+	res, err := ch.CallView(coreutil.CoreContractGovernance, governance.FuncGetCouncil.Name)
+	require.NoError(ch.Env.T, err)
+	return governance.GetCouncil(res)
+}
+
+// Propose creates the proposal for the council of the chain. Returns the ID of the proposal
+func (ch *Chain) Propose(proposal *governance.ProposalRequest, keyPair *ed25519.KeyPair) (uint32, error) {
+	req := NewCallParamsFromDic(coreutil.CoreContractGovernance, governance.FuncPropose.Name, proposal.AsDict()).WithIotas(1)
+	res, err := ch.PostRequestSync(req, keyPair)
+	if err != nil {
+		return 0, err
+	}
+	return codec.DecodeUint32(res.MustGet(governance.ParamProposalID))
+}
+
+// Vote votes for the proposal as a member of the council
+func (ch *Chain) Vote(proposalID uint32, approve bool, keyPair *ed25519.KeyPair) error {
+	req := NewCallParams(coreutil.CoreContractGovernance, governance.FuncVote.Name,
+		governance.ParamProposalID, proposalID,
+		governance.ParamApprove, approve,
+	).WithIotas(1)
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// ExecuteProposal executes the approved proposal
+func (ch *Chain) ExecuteProposal(proposalID uint32, keyPair *ed25519.KeyPair) (dict.Dict, error) {
+	req := NewCallParams(coreutil.CoreContractGovernance, governance.FuncExecuteProposal.Name,
+		governance.ParamProposalID, proposalID,
+	).WithIotas(1)
+	return ch.PostRequestSync(req, keyPair)
+}
+
+// GetProposals returns the pending proposals of the council
+func (ch *Chain) GetProposals() []*governance.Proposal {
+	res, err := ch.CallView(coreutil.CoreContractGovernance, governance.FuncGetProposals.Name)
+	require.NoError(ch.Env.T, err)
+	ret, err := governance.DecodeProposals(res)
+	require.NoError(ch.Env.T, err)
+	return ret
+}
+
+// SetPayoutAddress registers the account which receives the validator rewards of the node.
+// The request is signed by the key of the node
+func (ch *Chain) SetPayoutAddress(payout *iscp.AgentID, nodeKeyPair *ed25519.KeyPair) error {
+	req := NewCallParams(coreutil.CoreContractGovernance, governance.FuncSetPayoutAddress.Name,
+		governance.ParamNodePubKey, nodeKeyPair.PublicKey.Bytes(),
+		governance.ParamPayoutAddress, payout,
+	).WithIotas(1)
+	_, err := ch.PostRequestSync(req, nodeKeyPair)
+	return err
+}
+
+// WithdrawValidatorRewards moves the validator rewards of the node to its payout account.
+// The request is signed either by the key of the node or by the key of the payout account
+func (ch *Chain) WithdrawValidatorRewards(nodePubKey ed25519.PublicKey, keyPair *ed25519.KeyPair) error {
+	req := NewCallParams(coreutil.CoreContractGovernance, governance.FuncWithdrawValidatorRewards.Name,
+		governance.ParamNodePubKey, nodePubKey.Bytes(),
+	).WithIotas(1)
+	_, err := ch.PostRequestSync(req, keyPair)
+	return err
+}
+
+// GetValidatorRewards returns the validator rewards accrued by the node and its payout account, if registered
+func (ch *Chain) GetValidatorRewards(nodePubKey ed25519.PublicKey) (colored.Balances, *iscp.AgentID) {
+	res, err := ch.CallView(coreutil.CoreContractGovernance, governance.FuncGetValidatorRewards.Name,
+		governance.ParamNodePubKey, nodePubKey.Bytes(),
+	)
+	require.NoError(ch.Env.T, err)
+	rewards, err := colored.BalancesFromBytes(res.MustGet(governance.ParamValidatorRewards))
+	require.NoError(ch.Env.T, err)
+	if !res.MustHas(governance.ParamPayoutAddress) {
+		return rewards, nil
+	}
+	payout, err := codec.DecodeAgentID(res.MustGet(governance.ParamPayoutAddress))
+	require.NoError(ch.Env.T, err)
+	return rewards, payout
+}
+
 // RotateStateController rotates the chain to the new controller address.
 // We assume self-governed chain here.
 // Mostly use for the testinng of committee rotation logic, otherwise not much needed for smart contract testing
@@ -4,6 +4,7 @@
 package solo
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm/viewcontext"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
@@ -33,6 +35,8 @@ type CallParams struct {
 	mintAmount  uint64
 	mintAddress ledgerstate.Address
 	args        requestargs.RequestArgs
+	gasBudget   uint64
+	priorityFee uint64
 }
 
 func NewCallParamsFromDic(scName, funName string, par dict.Dict) *CallParams {
@@ -51,10 +55,11 @@ func NewCallParamsFromDic(scName, funName string, par dict.Dict) *CallParams {
 
 // NewCallParams creates structure which wraps in one object call parameters, used in PostRequestSync and callViewFull
 // calls:
-//  - 'scName' is a a name of the target smart contract
-//  - 'funName' is a name of the target entry point (the function) of he smart contract program
-//  - 'params' is either a dict.Dict, or a sequence of pairs 'paramName', 'paramValue' which constitute call parameters
+//   - 'scName' is a a name of the target smart contract
+//   - 'funName' is a name of the target entry point (the function) of he smart contract program
+//   - 'params' is either a dict.Dict, or a sequence of pairs 'paramName', 'paramValue' which constitute call parameters
 //     The 'paramName' must be a string and 'paramValue' must different types (encoded based on type)
+//
 // With the WithTransfers the CallParams structure may be complemented with attached colored
 // tokens sent together with the request
 func NewCallParams(scName, funName string, params ...interface{}) *CallParams {
@@ -100,13 +105,38 @@ func (r *CallParams) WithMint(targetAddress ledgerstate.Address, amount uint64)
 	return r
 }
 
+// WithGasBudget limits the gas the request may burn. 0 means the default budget
+func (r *CallParams) WithGasBudget(gasBudget uint64) *CallParams {
+	r.gasBudget = gasBudget
+	return r
+}
+
+// WithPriorityFee sets the fee paid out of the transfer on top of the chain fees
+func (r *CallParams) WithPriorityFee(priorityFee uint64) *CallParams {
+	r.priorityFee = priorityFee
+	return r
+}
+
 // NewRequestOffLedger creates off-ledger request from parameters
 func (r *CallParams) NewRequestOffLedger(chainID *iscp.ChainID, keyPair *ed25519.KeyPair) *request.OffLedger {
-	ret := request.NewOffLedger(chainID, r.target, r.entryPoint, r.args).WithTransfer(r.transfer)
+	ret := request.NewOffLedger(chainID, r.target, r.entryPoint, r.args).
+		WithTransfer(r.transfer).
+		WithGasBudget(r.gasBudget).
+		WithPriorityFee(r.priorityFee)
 	ret.Sign(keyPair)
 	return ret
 }
 
+// NewRequestOffLedgerSecp256k1 creates off-ledger request signed with the secp256k1 (Ethereum) key
+func (r *CallParams) NewRequestOffLedgerSecp256k1(chainID *iscp.ChainID, key *ecdsa.PrivateKey) *request.OffLedger {
+	ret := request.NewOffLedger(chainID, r.target, r.entryPoint, r.args).
+		WithTransfer(r.transfer).
+		WithGasBudget(r.gasBudget).
+		WithPriorityFee(r.priorityFee)
+	ret.SignSecp256k1(key)
+	return ret
+}
+
 func parseParams(params []interface{}) dict.Dict {
 	if len(params) == 1 {
 		return params[0].(dict.Dict)
@@ -153,7 +183,9 @@ func (ch *Chain) RequestFromParamsToLedger(req *CallParams, keyPair *ed25519.Key
 	metadata := request.NewMetadata().
 		WithTarget(req.target).
 		WithEntryPoint(req.entryPoint).
-		WithArgs(req.args)
+		WithArgs(req.args).
+		WithGasBudget(req.gasBudget).
+		WithPriorityFee(req.priorityFee)
 
 	mdata := metadata.Bytes()
 	mdataBack := request.MetadataFromBytes(mdata)
@@ -187,14 +219,15 @@ func (ch *Chain) RequestFromParamsToLedger(req *CallParams, keyPair *ed25519.Key
 }
 
 // PostRequestSync posts a request synchronously  sent by the test program to the smart contract on the same or another chain:
-//  - creates a request transaction with the request block on it. The sigScheme is used to
-//    sign the inputs of the transaction or OriginatorKeyPair is used if parameter is nil
-//  - adds request transaction to UTXODB
-//  - runs the request in the VM. It results in new updated virtual state and a new transaction
-//    which anchors the state.
-//  - adds the resulting transaction to UTXODB
-//  - posts requests, contained in the resulting transaction to backlog queues of respective chains
-//  - returns the result of the call to the smart contract's entry point
+//   - creates a request transaction with the request block on it. The sigScheme is used to
+//     sign the inputs of the transaction or OriginatorKeyPair is used if parameter is nil
+//   - adds request transaction to UTXODB
+//   - runs the request in the VM. It results in new updated virtual state and a new transaction
+//     which anchors the state.
+//   - adds the resulting transaction to UTXODB
+//   - posts requests, contained in the resulting transaction to backlog queues of respective chains
+//   - returns the result of the call to the smart contract's entry point
+//
 // Note that in real network of Wasp nodes (the committee) posting the transaction is completely
 // asynchronous, i.e. result of the call is not available to the originator of the post.
 //
@@ -212,7 +245,43 @@ func (ch *Chain) PostRequestOffLedger(req *CallParams, keyPair *ed25519.KeyPair)
 	if keyPair == nil {
 		keyPair = ch.OriginatorKeyPair
 	}
-	r := req.NewRequestOffLedger(ch.ChainID, keyPair)
+	return ch.postOffLedger(req.NewRequestOffLedger(ch.ChainID, keyPair))
+}
+
+// PostRequestOffLedgerSecp256k1 posts the off-ledger request signed with the secp256k1 (Ethereum) key.
+// The sender is the agent ID derived from the Ethereum address of the key
+func (ch *Chain) PostRequestOffLedgerSecp256k1(req *CallParams, key *ecdsa.PrivateKey) (dict.Dict, error) {
+	defer ch.logRequestLastBlock()
+
+	return ch.postOffLedger(req.NewRequestOffLedgerSecp256k1(ch.ChainID, key))
+}
+
+// PostRequestsOffLedger posts the off-ledger requests, signed with the corresponding key pairs, in one block.
+// Returns the error of the first failed request
+func (ch *Chain) PostRequestsOffLedger(reqs []*CallParams, keyPairs []*ed25519.KeyPair) error {
+	defer ch.logRequestLastBlock()
+
+	require.EqualValues(ch.Env.T, len(reqs), len(keyPairs))
+	batch := make([]iscp.Request, len(reqs))
+	for i, req := range reqs {
+		keyPair := keyPairs[i]
+		if keyPair == nil {
+			keyPair = ch.OriginatorKeyPair
+		}
+		batch[i] = req.NewRequestOffLedger(ch.ChainID, keyPair)
+	}
+	if _, err := ch.runRequestsSync(batch, "off-ledger batch"); err != nil {
+		return err
+	}
+	for _, r := range batch {
+		if err := ch.mustGetErrorFromReceipt(r.ID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ch *Chain) postOffLedger(r *request.OffLedger) (dict.Dict, error) {
 	res, err := ch.runRequestsSync([]iscp.Request{r}, "off-ledger")
 	if err != nil {
 		return nil, err
@@ -239,15 +308,15 @@ func (ch *Chain) PostRequestSyncTx(req *CallParams, keyPair *ed25519.KeyPair) (*
 func (ch *Chain) mustGetErrorFromReceipt(reqid iscp.RequestID) error {
 	rec, _, _, ok := ch.GetRequestReceipt(reqid)
 	require.True(ch.Env.T, ok)
-	var err error
-	if len(rec.Error) > 0 {
-		err = xerrors.New(rec.Error)
+	if rec.Error != nil {
+		return rec.Error
 	}
-	return err
+	return nil
 }
 
 // callViewFull calls the view entry point of the smart contract
 // with params wrapped into the CallParams object. The transfer part, fs any, is ignored
+//
 //nolint:unused
 func (ch *Chain) callViewFull(req *CallParams) (dict.Dict, error) {
 	ch.runVMMutex.Lock()
@@ -278,6 +347,30 @@ func (ch *Chain) CallView(scName, funName string, params ...interface{}) (dict.D
 	return vctx.CallView(iscp.Hn(scName), iscp.Hn(funName), p)
 }
 
+// CallViewAtBlock calls the view entry point of the smart contract against the state of the chain
+// after the block with the given index
+func (ch *Chain) CallViewAtBlock(blockIndex uint32, scName, funName string, params ...interface{}) (dict.Dict, error) {
+	ch.Log.Infof("callViewAtBlock: #%d %s::%s", blockIndex, scName, funName)
+
+	p := parseParams(params)
+
+	ch.runVMMutex.Lock()
+	defer ch.runVMMutex.Unlock()
+
+	stateReader, err := state.NewHistoricalStateReader(ch.Env.dbmanager.GetOrCreateKVStore(ch.ChainID), ch.GlobalSync, blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	vctx := viewcontext.New(ch.ChainID, stateReader, ch.proc, ch.Log)
+	return vctx.CallView(iscp.Hn(scName), iscp.Hn(funName), p)
+}
+
+// GetStateReader returns a new optimistic reader of the latest state of the chain.
+// Unlike ch.StateReader, it can be used concurrently with the chain
+func (ch *Chain) GetStateReader() state.OptimisticStateReader {
+	return state.NewOptimisticStateReader(ch.Env.dbmanager.GetOrCreateKVStore(ch.ChainID), ch.GlobalSync)
+}
+
 // WaitUntil waits until the condition specified by the given predicate yields true
 func (ch *Chain) WaitUntil(p func(chain.MempoolInfo) bool, maxWait ...time.Duration) bool {
 	maxw := 10 * time.Second
@@ -19,6 +19,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,9 +48,13 @@ func (ch *Chain) runRequestsNolock(reqs []iscp.Request, trace string) (dict.Dict
 		VirtualStateAccess: ch.State.Copy(),
 		Entropy:            hashing.RandomHash(nil),
 		ValidatorFeeTarget: ch.ValidatorFeeTarget,
+		Parallelism:        ch.VMParallelism,
 		Log:                ch.Log,
 	}
-	var err error
+	task.ValidatorParticipation = ch.ValidatorParticipation
+	beacon, err := ch.RandomBeaconKey.Sign(blocklog.RandomBeaconMessage(ch.ChainID, ch.State.BlockIndex()+1))
+	require.NoError(ch.Env.T, err)
+	task.RandomBeacon = beacon.Bytes()
 	var callRes dict.Dict
 	var callErr error
 	// state baseline always valid in Solo
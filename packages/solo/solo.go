@@ -12,6 +12,7 @@ import (
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate/utxodb"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate/utxoutil"
+	"github.com/iotaledger/hive.go/crypto/bls"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
@@ -29,6 +30,7 @@ import (
 	"github.com/iotaledger/wasp/packages/testutil/testlogger"
 	"github.com/iotaledger/wasp/packages/transaction"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 	"github.com/iotaledger/wasp/packages/vm/runvm"
 	_ "github.com/iotaledger/wasp/packages/vm/sandbox"
@@ -89,6 +91,10 @@ type Chain struct {
 	StateControllerKeyPair *ed25519.KeyPair
 	StateControllerAddress ledgerstate.Address
 
+	// RandomBeaconKey simulates the threshold BLS key of the committee, which signs the random beacons of the blocks.
+	// The beacons can't be verified against the Ed25519 state controller address, only against this key
+	RandomBeaconKey bls.PrivateKey
+
 	// OriginatorKeyPair the signature scheme used to create the chain (origin transaction).
 	// It is a default signature scheme in many of 'solo' calls which require private key.
 	OriginatorKeyPair *ed25519.KeyPair
@@ -105,6 +111,14 @@ type Chain struct {
 	// ValidatorFeeTarget is the agent ID to which all fees are accrued. By default is its equal to OriginatorAddress
 	ValidatorFeeTarget *iscp.AgentID
 
+	// ValidatorParticipation simulates the committee members signing the blocks. If set, the validator fees
+	// are split among them instead of going to the ValidatorFeeTarget
+	ValidatorParticipation []*governance.ValidatorParticipation
+
+	// VMParallelism is the number of off-ledger requests of the block run concurrently by the VM.
+	// The blocks are run sequentially by default
+	VMParallelism int
+
 	// State ia an interface to access virtual state of the chain: the collection of key/value pairs
 	State       state.VirtualStateAccess
 	GlobalSync  coreutil.ChainStateSync
@@ -261,6 +275,7 @@ func (env *Solo) NewChain(chainOriginator *ed25519.KeyPair, name string, validat
 		ChainID:                chainID,
 		StateControllerKeyPair: &stateController,
 		StateControllerAddress: stateAddr,
+		RandomBeaconKey:        bls.PrivateKeyFromRandomness(),
 		OriginatorKeyPair:      chainOriginator,
 		OriginatorAddress:      originatorAddr,
 		OriginatorAgentID:      originatorAgentID,
@@ -271,7 +286,7 @@ func (env *Solo) NewChain(chainOriginator *ed25519.KeyPair, name string, validat
 		proc:                   processors.MustNew(env.processorConfig),
 		Log:                    chainlog,
 	}
-	ret.mempool = mempool.New(ret.StateReader, env.blobCache, chainlog, metrics.DefaultChainMetrics())
+	ret.mempool = mempool.New(ret.StateReader, env.blobCache, chainlog, metrics.DefaultChainMetrics(), mempool.DefaultConfig())
 	require.NoError(env.T, err)
 	require.NoError(env.T, err)
 
@@ -39,3 +39,17 @@ func RunBenchmarkAsync(b *testing.B, chain *solo.Chain, reqs []*solo.CallParams,
 	}
 	require.True(b, chain.WaitForRequestsThrough(nreq+b.N, 20*time.Second))
 }
+
+// RunBenchmarkOffLedgerBlocks processes off-ledger requests in blocks of len(keyPairs) requests, each request
+// of the block signed by another key pair. Set chain.VMParallelism to run the requests of the block in parallel
+func RunBenchmarkOffLedgerBlocks(b *testing.B, chain *solo.Chain, reqs []*solo.CallParams, keyPairs []*ed25519.KeyPair) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i += len(keyPairs) {
+		n := len(keyPairs)
+		if i+n > b.N {
+			n = b.N - i
+		}
+		err := chain.PostRequestsOffLedger(reqs[i:i+n], keyPairs[:n])
+		require.NoError(b, err)
+	}
+}
@@ -0,0 +1,233 @@
+package solobench
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil/testlogger"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/processors"
+	"github.com/iotaledger/wasp/packages/vm/runvm"
+	"github.com/stretchr/testify/require"
+)
+
+// counters keeps a counter for each caller, so the calls of different callers don't conflict
+var (
+	countersContract = coreutil.NewContract("counters", "Counter per caller")
+	funcIncrement    = coreutil.Func("increment")
+	countersProc     = countersContract.Processor(nil,
+		funcIncrement.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+			key := kv.Key(ctx.Caller().Bytes())
+			n, err := codec.DecodeUint64(ctx.State().MustGet(key), 0)
+			if err != nil {
+				return nil, err
+			}
+			ctx.State().Set(key, codec.EncodeUint64(n+1))
+			return nil, nil
+		}),
+	)
+)
+
+const numSenders = 100
+
+func initCounters(t testing.TB, numSenders int) (*solo.Chain, []*ed25519.KeyPair) {
+	log := testlogger.NewSilentLogger(t.Name(), true)
+	env := solo.NewWithLogger(t, log).WithNativeContract(countersProc)
+	chain := env.NewChain(nil, "chain1")
+
+	err := chain.DeployContract(nil, countersContract.Name, countersContract.ProgramHash)
+	require.NoError(t, err)
+
+	// the senders of off-ledger requests must have accounts on the chain
+	keyPairs := make([]*ed25519.KeyPair, numSenders)
+	for i := range keyPairs {
+		keyPairs[i], _ = env.NewKeyPairWithFunds()
+		_, err = chain.PostRequestSync(solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name).WithIotas(100), keyPairs[i])
+		require.NoError(t, err)
+	}
+	return chain, keyPairs
+}
+
+// initTransfers deploys a counters contract for each sender, so the tokens sent by different senders go to different
+// accounts. The accounts of the contracts are created in advance
+func initTransfers(t testing.TB, numSenders int) (*solo.Chain, []*ed25519.KeyPair, []string) {
+	chain, keyPairs := initCounters(t, numSenders)
+	names := make([]string, numSenders)
+	reqs := make([]*solo.CallParams, numSenders)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s%d", countersContract.Name, i)
+		err := chain.DeployContract(nil, names[i], countersContract.ProgramHash)
+		require.NoError(t, err)
+		reqs[i] = solo.NewCallParams(names[i], funcIncrement.Name).WithIotas(1)
+	}
+	err := chain.PostRequestsOffLedger(reqs, keyPairs)
+	require.NoError(t, err)
+	return chain, keyPairs, names
+}
+
+func TestParallelRunProducesSameBlock(t *testing.T) {
+	chain, keyPairs := initCounters(t, 10)
+	// the 4 requests repeating the sender of the preceding request and the 3 transfers to the contract conflict
+	requireSameBlock(t, chain, counterRequests(t, chain, keyPairs, 0), 10)
+}
+
+func TestParallelRunWithTransfersProducesSameBlock(t *testing.T) {
+	chain, keyPairs, names := initTransfers(t, 10)
+	var reqs []iscp.Request
+	for i, keyPair := range keyPairs {
+		req := solo.NewCallParams(names[i], funcIncrement.Name).WithIotas(uint64(i+1)).
+			NewRequestOffLedger(chain.ChainID, keyPair)
+		_, err := request.SolidifyArgs(req, nil)
+		require.NoError(t, err)
+		reqs = append(reqs, req)
+	}
+	// the tokens are moved between different accounts, so none of the requests conflict
+	requireSameBlock(t, chain, reqs, len(reqs))
+}
+
+func TestParallelRunWithFeesProducesSameBlock(t *testing.T) {
+	chain, keyPairs := initCounters(t, 10)
+	_, err := chain.PostRequestSync(solo.NewCallParams(governance.Contract.Name, governance.FuncSetChainInfo.Name,
+		governance.ParamOwnerFee, 1, governance.ParamValidatorFee, 2).WithIotas(1), nil)
+	require.NoError(t, err)
+	reqs := counterRequests(t, chain, keyPairs, 3)
+
+	requireSameBlock(t, chain, reqs, 10)
+
+	// the validator fees are split among the participants of the block
+	chain.ValidatorParticipation = []*governance.ValidatorParticipation{
+		{NodePubKey: ed25519.PublicKey{1}, Weight: 2},
+		{NodePubKey: ed25519.PublicKey{2}, Weight: 1},
+	}
+	requireSameBlock(t, chain, reqs, 10)
+}
+
+// counterRequests returns the off-ledger requests to increment the counters of the senders, with some of them
+// conflicting with each other. Each request brings the fee from the account of the sender
+func counterRequests(t *testing.T, chain *solo.Chain, keyPairs []*ed25519.KeyPair, fee uint64) []iscp.Request {
+	withFee := func(par *solo.CallParams, amount uint64) *solo.CallParams {
+		if amount == 0 {
+			return par
+		}
+		return par.WithIotas(amount)
+	}
+	var reqs []iscp.Request
+	for i, keyPair := range keyPairs {
+		// independent counters
+		reqs = append(reqs, withFee(solo.NewCallParams(countersContract.Name, funcIncrement.Name, "n", i), fee).
+			NewRequestOffLedger(chain.ChainID, keyPair))
+		if i%3 == 0 {
+			// conflicts with the previous request of the same sender
+			reqs = append(reqs, withFee(solo.NewCallParams(countersContract.Name, funcIncrement.Name, "n", i+100), fee).
+				NewRequestOffLedger(chain.ChainID, keyPair))
+		}
+		if i%4 == 0 {
+			// conflicts with all other transfers to the contract
+			reqs = append(reqs, withFee(solo.NewCallParams(countersContract.Name, funcIncrement.Name, "n", i+200), fee+1).
+				NewRequestOffLedger(chain.ChainID, keyPair))
+		}
+	}
+	for _, req := range reqs {
+		_, err := request.SolidifyArgs(req, nil)
+		require.NoError(t, err)
+	}
+	return reqs
+}
+
+// requireSameBlock runs the requests in a block sequentially and in parallel and checks the results are the same.
+// numCommitted is the number of requests expected to be committed from their speculative runs
+func requireSameBlock(t *testing.T, chain *solo.Chain, reqs []iscp.Request, numCommitted int) {
+	timestamp := chain.Env.LogicalTime()
+	entropy := hashing.RandomHash(nil)
+	run := func(parallelism int) *vm.VMTask {
+		task := &vm.VMTask{
+			Processors:             processors.MustNew(processors.NewConfig(countersProc)),
+			ChainInput:             chain.GetChainOutput(),
+			Requests:               reqs,
+			Timestamp:              timestamp,
+			VirtualStateAccess:     chain.State.Copy(),
+			SolidStateBaseline:     chain.GlobalSync.GetSolidIndexBaseline(),
+			Entropy:                entropy,
+			ValidatorFeeTarget:     chain.ValidatorFeeTarget,
+			ValidatorParticipation: chain.ValidatorParticipation,
+			Parallelism:            parallelism,
+			Log:                    chain.Log,
+		}
+		task.OnFinish = func(_ dict.Dict, _ error, err error) {
+			require.NoError(t, err)
+		}
+		runvm.NewVMRunner().Run(task)
+		return task
+	}
+	sequential := run(1)
+	parallel := run(4)
+
+	require.EqualValues(t, len(reqs), parallel.ProcessedRequestsCount)
+	require.EqualValues(t, 0, sequential.CommittedSpeculativeRuns)
+	require.EqualValues(t, numCommitted, parallel.CommittedSpeculativeRuns)
+	require.EqualValues(t, sequential.VirtualStateAccess.StateCommitment(), parallel.VirtualStateAccess.StateCommitment())
+	require.EqualValues(t, sequential.ResultTransactionEssence.Bytes(), parallel.ResultTransactionEssence.Bytes())
+}
+
+func initCountersBenchmark(b *testing.B, parallelism int) (*solo.Chain, []*solo.CallParams, []*ed25519.KeyPair) {
+	chain, keyPairs := initCounters(b, numSenders)
+	chain.VMParallelism = parallelism
+
+	reqs := make([]*solo.CallParams, b.N)
+	for i := range reqs {
+		reqs[i] = solo.NewCallParams(countersContract.Name, funcIncrement.Name)
+	}
+	return chain, reqs, keyPairs
+}
+
+func initTransfersBenchmark(b *testing.B, parallelism int) (*solo.Chain, []*solo.CallParams, []*ed25519.KeyPair) {
+	chain, keyPairs, names := initTransfers(b, numSenders)
+	chain.VMParallelism = parallelism
+
+	// the request is sent by the key pair at the same index in the block
+	reqs := make([]*solo.CallParams, b.N)
+	for i := range reqs {
+		reqs[i] = solo.NewCallParams(names[i%numSenders], funcIncrement.Name).WithIotas(1)
+	}
+	return chain, reqs, keyPairs
+}
+
+// BenchmarkOffLedgerSequential runs blocks of off-ledger requests of different senders one by one.
+// run with: go test -benchmem -run=' ' -bench='Bench.*'
+func BenchmarkOffLedgerSequential(b *testing.B) {
+	chain, reqs, keyPairs := initCountersBenchmark(b, 1)
+	RunBenchmarkOffLedgerBlocks(b, chain, reqs, keyPairs)
+}
+
+// BenchmarkOffLedgerParallel runs the same blocks as BenchmarkOffLedgerSequential, with the requests
+// of each block run in parallel
+func BenchmarkOffLedgerParallel(b *testing.B) {
+	chain, reqs, keyPairs := initCountersBenchmark(b, runtime.NumCPU())
+	RunBenchmarkOffLedgerBlocks(b, chain, reqs, keyPairs)
+}
+
+// BenchmarkOffLedgerTransfersSequential runs blocks of off-ledger requests, each moving tokens from the account
+// of the sender to the account of another contract
+func BenchmarkOffLedgerTransfersSequential(b *testing.B) {
+	chain, reqs, keyPairs := initTransfersBenchmark(b, 1)
+	RunBenchmarkOffLedgerBlocks(b, chain, reqs, keyPairs)
+}
+
+// BenchmarkOffLedgerTransfersParallel runs the same blocks as BenchmarkOffLedgerTransfersSequential, with the
+// requests of each block run in parallel
+func BenchmarkOffLedgerTransfersParallel(b *testing.B) {
+	chain, reqs, keyPairs := initTransfersBenchmark(b, runtime.NumCPU())
+	RunBenchmarkOffLedgerBlocks(b, chain, reqs, keyPairs)
+}
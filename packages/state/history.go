@@ -0,0 +1,241 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/trie"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// The history of the state is kept as reverse diffs: for each committed block the DB contains
+// the values the mutated keys had before the block. Applying the reverse diffs of blocks
+// L, L-1, ... H+1 to the state L gives the state H
+
+// ErrStateNotRetained is returned when the state at the requested block can't be restored,
+// because the history has been pruned or was never kept
+var ErrStateNotRetained = xerrors.New("historical state is not retained")
+
+func reverseDiffKey(blockIndex uint32) []byte {
+	return dbkeys.MakeKey(dbkeys.ObjectTypeStateReverseDiff, util.Uint32To4Bytes(blockIndex))
+}
+
+// saveReverseDiffs calculates reverse diffs of the blocks, in the order of block indices, and saves them to the batch.
+// The previous values are taken from the DB or from the preceding blocks of the same commit
+func saveReverseDiffs(db kvstore.KVStore, batch kvstore.BatchedMutations, blocks []Block) error {
+	committed := kv.NewHiveKVStoreReader(subRealm(db, []byte{dbkeys.ObjectTypeStateVariable}))
+	pending := buffered.NewBufferedKVStoreAccess(committed)
+	for _, blk := range blocks {
+		muts := blk.(*blockImpl).stateUpdate.Mutations()
+		diff := buffered.NewMutations()
+		save := func(k kv.Key) error {
+			prev, err := pending.Get(k)
+			if err != nil {
+				return err
+			}
+			if prev == nil {
+				diff.Del(k)
+			} else {
+				diff.Set(k, prev)
+			}
+			return nil
+		}
+		for k := range muts.Sets {
+			if err := save(k); err != nil {
+				return err
+			}
+		}
+		for k := range muts.Dels {
+			if err := save(k); err != nil {
+				return err
+			}
+		}
+		muts.ApplyTo(pending)
+		if blk.BlockIndex() == 0 {
+			// nothing precedes the origin state
+			continue
+		}
+		if err := batch.Set(reverseDiffKey(blk.BlockIndex()), diff.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadReverseDiff loads the reverse diff of the block or returns ErrStateNotRetained
+func loadReverseDiff(db kvstore.KVStore, blockIndex uint32) (*buffered.Mutations, error) {
+	data, err := db.Get(reverseDiffKey(blockIndex))
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		return nil, ErrStateNotRetained
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := buffered.NewMutations()
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, xerrors.Errorf("loadReverseDiff: %w", err)
+	}
+	return ret, nil
+}
+
+// PruneStateHistory deletes reverse diffs of blocks in the range [fromIndex, toIndex).
+// The states preceding these blocks can't be restored anymore
+func PruneStateHistory(db kvstore.KVStore, fromIndex, toIndex uint32) error {
+	if fromIndex >= toIndex {
+		return nil
+	}
+	batch := db.Batched()
+	for i := fromIndex; i < toIndex; i++ {
+		if err := batch.Delete(reverseDiffKey(i)); err != nil {
+			return err
+		}
+	}
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+	return db.Flush()
+}
+
+// region historicalStateReader ////////////////////////////////////////////////
+
+// historicalStateReader is a read-only access to the state as it was after the given block.
+// It is the optimistic reader of the latest state with reverse diffs applied on top of it,
+// so it is invalidated together with the latest state
+type historicalStateReader struct {
+	db         kvstore.KVStore
+	latest     *OptimisticStateReaderImpl
+	blockIndex uint32
+	kvs        *buffered.BufferedKVStoreAccess
+}
+
+// NewHistoricalStateReader creates optimistic read-only access to the state after the block with the given index.
+// It returns ErrStateNotRetained if the history of the state doesn't reach the block
+func NewHistoricalStateReader(db kvstore.KVStore, glb coreutil.ChainStateSync, blockIndex uint32) (OptimisticStateReader, error) {
+	latest := NewOptimisticStateReader(db, glb)
+	latestIndex, err := latest.BlockIndex()
+	if err != nil {
+		return nil, err
+	}
+	if blockIndex > latestIndex {
+		return nil, xerrors.Errorf("block #%d is ahead of the latest state #%d", blockIndex, latestIndex)
+	}
+	ret := &historicalStateReader{
+		db:         db,
+		latest:     latest,
+		blockIndex: blockIndex,
+		kvs:        buffered.NewBufferedKVStoreAccess(latest.KVStoreReader()),
+	}
+	for i := latestIndex; i > blockIndex; i-- {
+		diff, err := loadReverseDiff(db, i)
+		if err != nil {
+			return nil, xerrors.Errorf("state #%d: %w", blockIndex, err)
+		}
+		diff.ApplyTo(ret.kvs)
+	}
+	// the reverse diffs must be consistent with the latest state they were applied to
+	if !latest.chainState.IsStateValid() {
+		return nil, coreutil.ErrorStateInvalidated
+	}
+	return ret, nil
+}
+
+// FindBlockIndexByStateHash returns the index of the block which produced the state with the given hash.
+// Only the states within the retained history are searched
+func FindBlockIndexByStateHash(db kvstore.KVStore, glb coreutil.ChainStateSync, stateHash hashing.HashValue) (uint32, error) {
+	latest := NewOptimisticStateReader(db, glb)
+	latestIndex, err := latest.BlockIndex()
+	if err != nil {
+		return 0, err
+	}
+	latestHash, err := latest.Hash()
+	if err != nil {
+		return 0, err
+	}
+	if latestHash == stateHash {
+		return latestIndex, nil
+	}
+	// the hash of the state H is recorded in the block H+1
+	for i := latestIndex; i > 0; i-- {
+		if _, err := db.Get(reverseDiffKey(i)); err != nil {
+			if errors.Is(err, kvstore.ErrKeyNotFound) {
+				break
+			}
+			return 0, err
+		}
+		blk, err := LoadBlock(db, i)
+		if err != nil {
+			return 0, err
+		}
+		if blk.PreviousStateHash() == stateHash {
+			return i - 1, nil
+		}
+	}
+	return 0, xerrors.Errorf("state %s: %w", stateHash, ErrStateNotRetained)
+}
+
+func (r *historicalStateReader) BlockIndex() (uint32, error) {
+	return loadStateIndexFromState(r.kvs)
+}
+
+func (r *historicalStateReader) Timestamp() (time.Time, error) {
+	return loadTimestampFromState(r.kvs)
+}
+
+func (r *historicalStateReader) Hash() (hashing.HashValue, error) {
+	latestIndex, err := r.latest.BlockIndex()
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	if latestIndex == r.blockIndex {
+		return r.latest.Hash()
+	}
+	// the state hash of the historical state is the previous state hash of the next block
+	data, err := LoadBlockBytes(r.db, r.blockIndex+1)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	if data == nil {
+		return hashing.NilHash, xerrors.Errorf("state #%d: %w", r.blockIndex, ErrStateNotRetained)
+	}
+	blk, err := BlockFromBytes(data)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	if !r.latest.chainState.IsStateValid() {
+		return hashing.NilHash, coreutil.ErrorStateInvalidated
+	}
+	return blk.PreviousStateHash(), nil
+}
+
+// GetProof is only available for the latest state: the nodes of the state trie are not historical
+func (r *historicalStateReader) GetProof(key kv.Key) (*trie.Proof, error) {
+	latestIndex, err := r.latest.BlockIndex()
+	if err != nil {
+		return nil, err
+	}
+	if latestIndex != r.blockIndex {
+		return nil, xerrors.Errorf("proofs are not available for the historical state #%d", r.blockIndex)
+	}
+	return r.latest.GetProof(key)
+}
+
+func (r *historicalStateReader) KVStoreReader() kv.KVStoreReader {
+	return r.kvs
+}
+
+// SetBaseline is a NOP: the reverse diffs are applied to the latest state of the moment of creation,
+// a new reader must be created once it is invalidated
+func (r *historicalStateReader) SetBaseline() {}
+
+// endregion ///////////////////////////////////////////////////////////////////
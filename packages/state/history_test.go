@@ -0,0 +1,93 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestHistoricalState(t *testing.T) {
+	store := mapdb.NewMapDB()
+	chainID := iscp.RandomChainID([]byte("1"))
+	vs, err := CreateOriginState(store, chainID)
+	require.NoError(t, err)
+
+	hashes := []hashing.HashValue{vs.StateCommitment()}
+	nextBlock := func(i uint32, f func(su StateUpdate)) Block {
+		su := NewStateUpdateWithBlocklogValues(i, time.Now(), vs.StateCommitment())
+		f(su)
+		block, err := newBlock(su.Mutations())
+		require.NoError(t, err)
+		require.NoError(t, vs.ApplyBlock(block))
+		return block
+	}
+	for i := uint32(1); i <= 3; i++ {
+		block := nextBlock(i, func(su StateUpdate) {
+			su.Mutations().Set("counter", codec.EncodeUint32(i))
+			if i == 2 {
+				su.Mutations().Set("temp", []byte("temp"))
+			}
+			if i == 3 {
+				su.Mutations().Del("temp")
+			}
+		})
+		require.NoError(t, vs.Commit(block))
+		hashes = append(hashes, vs.StateCommitment())
+	}
+	// several blocks in one commit
+	block4 := nextBlock(4, func(su StateUpdate) { su.Mutations().Set("counter", codec.EncodeUint32(4)) })
+	block5 := nextBlock(5, func(su StateUpdate) { su.Mutations().Set("counter", codec.EncodeUint32(5)) })
+	require.NoError(t, vs.Commit(block4, block5))
+	hashes = append(hashes, hashing.NilHash, vs.StateCommitment())
+
+	glb := coreutil.NewChainStateSync()
+	glb.SetSolidIndex(5)
+	for i := uint32(0); i <= 5; i++ {
+		rdr, err := NewHistoricalStateReader(store, glb, i)
+		require.NoError(t, err)
+		blockIndex, err := rdr.BlockIndex()
+		require.NoError(t, err)
+		require.EqualValues(t, i, blockIndex)
+		counter, err := codec.DecodeUint32(rdr.KVStoreReader().MustGet("counter"), 0)
+		require.NoError(t, err)
+		require.EqualValues(t, i, counter)
+		require.EqualValues(t, i == 2, rdr.KVStoreReader().MustHas("temp"))
+		if i != 4 {
+			h, err := rdr.Hash()
+			require.NoError(t, err)
+			require.EqualValues(t, hashes[i], h)
+
+			found, err := FindBlockIndexByStateHash(store, glb, hashes[i])
+			require.NoError(t, err)
+			require.EqualValues(t, i, found)
+		}
+	}
+	_, err = NewHistoricalStateReader(store, glb, 6)
+	require.Error(t, err)
+
+	// the latest state keeps the proofs
+	rdr, err := NewHistoricalStateReader(store, glb, 5)
+	require.NoError(t, err)
+	_, err = rdr.GetProof("counter")
+	require.NoError(t, err)
+	rdr, err = NewHistoricalStateReader(store, glb, 3)
+	require.NoError(t, err)
+	_, err = rdr.GetProof("counter")
+	require.Error(t, err)
+
+	// states before the pruned blocks are not available
+	require.NoError(t, PruneStateHistory(store, 1, 3))
+	_, err = NewHistoricalStateReader(store, glb, 1)
+	require.True(t, xerrors.Is(err, ErrStateNotRetained))
+	_, err = FindBlockIndexByStateHash(store, glb, hashes[1])
+	require.True(t, xerrors.Is(err, ErrStateNotRetained))
+	_, err = NewHistoricalStateReader(store, glb, 2)
+	require.NoError(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/database/dbkeys"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/util"
 	"golang.org/x/xerrors"
 )
@@ -20,7 +21,14 @@ func (vs *virtualStateAccess) Commit(blocks ...Block) error {
 	}
 	batch := vs.db.Batched()
 
-	stateCommitment := vs.StateCommitment()
+	stateTrie, err := vs.stateTrie()
+	if err != nil {
+		return err
+	}
+	stateCommitment, err := stateTrie.RootHash()
+	if err != nil {
+		return err
+	}
 	if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeStateHash), stateCommitment.Bytes()); err != nil {
 		return err
 	}
@@ -32,6 +40,11 @@ func (vs *virtualStateAccess) Commit(blocks ...Block) error {
 		}
 	}
 
+	// store the history of the state
+	if err := saveReverseDiffs(vs.db, batch, blocks); err != nil {
+		return err
+	}
+
 	// store mutations
 	for k, v := range vs.kvs.Mutations().Sets {
 		if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeStateVariable, []byte(k)), v); err != nil {
@@ -44,6 +57,19 @@ func (vs *virtualStateAccess) Commit(blocks ...Block) error {
 		}
 	}
 
+	// store modified nodes of the state trie
+	stateTrie.ForEachModifiedNode(func(path kv.Key, data []byte) bool {
+		if data == nil {
+			err = batch.Delete(dbkeys.MakeKey(dbkeys.ObjectTypeTrieNode, []byte(path)))
+		} else {
+			err = batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeTrieNode, []byte(path)), data)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
 	if err := batch.Commit(); err != nil {
 		return err
 	}
@@ -55,7 +81,6 @@ func (vs *virtualStateAccess) Commit(blocks ...Block) error {
 
 	vs.kvs.ClearMutations()
 	vs.kvs.Mutations().ResetModified()
-	vs.appliedBlockHashes = vs.appliedBlockHashes[:0]
 	vs.committedHash = stateCommitment
 	return nil
 }
@@ -0,0 +1,62 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// PruneBlocks deletes the blocks and their reverse diffs with indices below beforeIndex.
+// It doesn't touch the state itself, only the node-local history of it. The origin block is always kept.
+// The pruning continues from the index stored by the previous call. Returns the number of deleted blocks
+func PruneBlocks(db kvstore.KVStore, beforeIndex uint32) (int, error) {
+	from, err := loadBlocksPrunedUpTo(db)
+	if err != nil {
+		return 0, err
+	}
+	if from >= beforeIndex {
+		return 0, nil
+	}
+	batch := db.Batched()
+	n := 0
+	for blockIndex := from; blockIndex < beforeIndex; blockIndex++ {
+		key := dbkeys.MakeKey(dbkeys.ObjectTypeBlock, util.Uint32To4Bytes(blockIndex))
+		found, err := db.Has(key)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			n++
+		}
+		if err := batch.Delete(key); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(reverseDiffKey(blockIndex)); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeBlocksPrunedUpTo), util.Uint32To4Bytes(beforeIndex)); err != nil {
+		return 0, err
+	}
+	if err := batch.Commit(); err != nil {
+		return 0, err
+	}
+	return n, db.Flush()
+}
+
+// loadBlocksPrunedUpTo returns the index of the first block not pruned yet, the origin block is never pruned
+func loadBlocksPrunedUpTo(db kvstore.KVStore) (uint32, error) {
+	data, err := db.Get(dbkeys.MakeKey(dbkeys.ObjectTypeBlocksPrunedUpTo))
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return util.Uint32From4Bytes(data)
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestPruneBlocks(t *testing.T) {
+	store := mapdb.NewMapDB()
+	chainID := iscp.RandomChainID([]byte("1"))
+	vs, err := CreateOriginState(store, chainID)
+	require.NoError(t, err)
+
+	for i := uint32(1); i <= 5; i++ {
+		su := NewStateUpdateWithBlocklogValues(i, time.Now(), vs.StateCommitment())
+		su.Mutations().Set("counter", codec.EncodeUint32(i))
+		block, err := newBlock(su.Mutations())
+		require.NoError(t, err)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.Commit(block))
+	}
+
+	n, err := PruneBlocks(store, 3)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	for i := uint32(0); i <= 5; i++ {
+		data, err := LoadBlockBytes(store, i)
+		require.NoError(t, err)
+		// the origin block is kept
+		require.EqualValues(t, i == 0 || i >= 3, data != nil)
+	}
+	n, err = PruneBlocks(store, 3)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = PruneBlocks(store, 4)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	// the state is not affected, the history is available only for the retained blocks
+	glb := coreutil.NewChainStateSync()
+	glb.SetSolidIndex(5)
+	_, err = NewHistoricalStateReader(store, glb, 2)
+	require.True(t, xerrors.Is(err, ErrStateNotRetained))
+	rdr, err := NewHistoricalStateReader(store, glb, 3)
+	require.NoError(t, err)
+	counter, err := codec.DecodeUint32(rdr.KVStoreReader().MustGet("counter"), 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, counter)
+	_, err = rdr.Hash()
+	require.NoError(t, err)
+}
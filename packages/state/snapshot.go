@@ -0,0 +1,229 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/database/dbkeys"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/trie"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// The snapshot is a serialized committed state of the chain at some block index.
+// It consists of the header and the sequence of all key/value pairs of the state.
+// The header contains the state hash and the last block of the state, which, in turn, contains block index
+// and the ID of the approving alias output. The content of the snapshot is verified against the state hash
+// when imported. The state hash must be checked against the approving alias output by the importer
+
+const snapshotVersion = byte(0)
+
+// SnapshotHeader is the header of the state snapshot
+type SnapshotHeader struct {
+	StateHash hashing.HashValue
+	Block     Block
+}
+
+func (h *SnapshotHeader) BlockIndex() uint32 {
+	return h.Block.BlockIndex()
+}
+
+func (h *SnapshotHeader) Write(w io.Writer) error {
+	if err := util.WriteByte(w, snapshotVersion); err != nil {
+		return err
+	}
+	if _, err := w.Write(h.StateHash[:]); err != nil {
+		return err
+	}
+	return util.WriteBytes32(w, h.Block.Bytes())
+}
+
+func (h *SnapshotHeader) Read(r io.Reader) error {
+	version, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	if version != snapshotVersion {
+		return xerrors.Errorf("unsupported snapshot version %d", version)
+	}
+	if err := util.ReadHashValue(r, &h.StateHash); err != nil {
+		return err
+	}
+	blockBytes, err := util.ReadBytes32(r)
+	if err != nil {
+		return err
+	}
+	h.Block, err = BlockFromBytes(blockBytes)
+	return err
+}
+
+// ReadSnapshotHeader reads only the header of the snapshot
+func ReadSnapshotHeader(r io.Reader) (*SnapshotHeader, error) {
+	ret := &SnapshotHeader{}
+	if err := ret.Read(r); err != nil {
+		return nil, xerrors.Errorf("ReadSnapshotHeader: %w", err)
+	}
+	return ret, nil
+}
+
+// WriteSnapshot serializes the committed state in the store. Returns the header of the written snapshot
+func WriteSnapshot(store kvstore.KVStore, w io.Writer) (*SnapshotHeader, error) {
+	stateHash, exists, err := loadStateHashFromDb(store)
+	if err != nil {
+		return nil, xerrors.Errorf("WriteSnapshot: %w", err)
+	}
+	if !exists {
+		return nil, xerrors.New("WriteSnapshot: state does not exist")
+	}
+	chainState := kv.NewHiveKVStoreReader(subRealm(store, []byte{dbkeys.ObjectTypeStateVariable}))
+	blockIndex, err := loadStateIndexFromState(chainState)
+	if err != nil {
+		return nil, xerrors.Errorf("WriteSnapshot: %w", err)
+	}
+	block, err := LoadBlock(store, blockIndex)
+	if err != nil {
+		return nil, xerrors.Errorf("WriteSnapshot: can't load block #%d: %w", blockIndex, err)
+	}
+	header := &SnapshotHeader{
+		StateHash: stateHash,
+		Block:     block,
+	}
+	bw := bufio.NewWriter(w)
+	if err := header.Write(bw); err != nil {
+		return nil, err
+	}
+	err = chainState.IterateSorted("", func(key kv.Key, value []byte) bool {
+		if err = util.WriteBoolByte(bw, true); err != nil {
+			return false
+		}
+		if err = util.WriteString16(bw, string(key)); err != nil {
+			return false
+		}
+		err = util.WriteBytes32(bw, value)
+		return err == nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("WriteSnapshot: %w", err)
+	}
+	if err := util.WriteBoolByte(bw, false); err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// ImportSnapshot replaces the committed state in the store with the state from the snapshot.
+// The snapshot is fully read and its content is verified against the state hash in its header
+// before anything is written to the store. Blocks already stored in the store are kept
+func ImportSnapshot(store kvstore.KVStore, chainID *iscp.ChainID, r io.Reader) (VirtualStateAccess, error) {
+	br := bufio.NewReader(r)
+	header, err := ReadSnapshotHeader(br)
+	if err != nil {
+		return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+	}
+	entries := make(map[kv.Key][]byte)
+	stateTrie := trie.New(nil)
+	for {
+		var more bool
+		if err := util.ReadBoolByte(br, &more); err != nil {
+			return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+		}
+		if !more {
+			break
+		}
+		key, err := util.ReadString16(br)
+		if err != nil {
+			return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+		}
+		value, err := util.ReadBytes32(br)
+		if err != nil {
+			return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+		}
+		entries[kv.Key(key)] = value
+		if err := stateTrie.Update([]byte(key), value); err != nil {
+			return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+		}
+	}
+	rootHash, err := stateTrie.RootHash()
+	if err != nil {
+		return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+	}
+	if rootHash != header.StateHash {
+		return nil, xerrors.Errorf("ImportSnapshot: content of the snapshot does not match state hash %s", header.StateHash)
+	}
+	blockIndex, err := loadStateIndexFromState(dict.Dict(entries))
+	if err != nil {
+		return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+	}
+	if blockIndex != header.BlockIndex() {
+		return nil, xerrors.Errorf("ImportSnapshot: block index of the state #%d is not equal to the index of the block #%d",
+			blockIndex, header.BlockIndex())
+	}
+	// the last block must be consistent with the state
+	for k, v := range header.Block.(*blockImpl).stateUpdate.mutations.Sets {
+		if !bytes.Equal(entries[k], v) {
+			return nil, xerrors.Errorf("ImportSnapshot: block #%d is inconsistent with the state", header.BlockIndex())
+		}
+	}
+	for k := range header.Block.(*blockImpl).stateUpdate.mutations.Dels {
+		if _, ok := entries[k]; ok {
+			return nil, xerrors.Errorf("ImportSnapshot: block #%d is inconsistent with the state", header.BlockIndex())
+		}
+	}
+	if err := replaceState(store, header, entries, stateTrie); err != nil {
+		return nil, xerrors.Errorf("ImportSnapshot: %w", err)
+	}
+	ret, _, err := LoadSolidState(store, chainID)
+	return ret, err
+}
+
+func replaceState(store kvstore.KVStore, header *SnapshotHeader, entries map[kv.Key][]byte, stateTrie *trie.Trie) error {
+	batch := store.Batched()
+	for _, objType := range []byte{dbkeys.ObjectTypeStateVariable, dbkeys.ObjectTypeTrieNode, dbkeys.ObjectTypeStateReverseDiff} {
+		var err error
+		errIter := store.IterateKeys(dbkeys.MakeKey(objType), func(key kvstore.Key) bool {
+			err = batch.Delete(key)
+			return err == nil
+		})
+		if errIter != nil {
+			return errIter
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for k, v := range entries {
+		if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeStateVariable, []byte(k)), v); err != nil {
+			return err
+		}
+	}
+	var err error
+	stateTrie.ForEachModifiedNode(func(path kv.Key, data []byte) bool {
+		if data != nil {
+			err = batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeTrieNode, []byte(path)), data)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := batch.Set(dbkeys.MakeKey(dbkeys.ObjectTypeStateHash), header.StateHash.Bytes()); err != nil {
+		return err
+	}
+	blockKey := dbkeys.MakeKey(dbkeys.ObjectTypeBlock, util.Uint32To4Bytes(header.BlockIndex()))
+	if err := batch.Set(blockKey, header.Block.Bytes()); err != nil {
+		return err
+	}
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+	return store.Flush()
+}
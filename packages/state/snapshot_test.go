@@ -0,0 +1,105 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	store := mapdb.NewMapDB()
+	chainID := iscp.RandomChainID([]byte("1"))
+	vs, err := CreateOriginState(store, chainID)
+	require.NoError(t, err)
+
+	for i := uint32(1); i <= 5; i++ {
+		su := NewStateUpdateWithBlocklogValues(i, time.Now(), vs.StateCommitment())
+		for j := 0; j < 10; j++ {
+			su.Mutations().Set(kvKey(i, j), []byte(fmt.Sprintf("value %d", j)))
+		}
+		su.Mutations().Del(kvKey(i-1, 0))
+		block, err := newBlock(su.Mutations())
+		require.NoError(t, err)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.Commit(block))
+	}
+
+	var buf bytes.Buffer
+	header, err := WriteSnapshot(store, &buf)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, header.BlockIndex())
+	require.EqualValues(t, vs.StateCommitment(), header.StateHash)
+
+	headerBack, err := ReadSnapshotHeader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.EqualValues(t, header.StateHash, headerBack.StateHash)
+	require.EqualValues(t, header.Block.Bytes(), headerBack.Block.Bytes())
+
+	t.Run("import to empty store", func(t *testing.T) {
+		store2 := mapdb.NewMapDB()
+		vs2, err := ImportSnapshot(store2, chainID, bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		require.EqualValues(t, 5, vs2.BlockIndex())
+		require.EqualValues(t, vs.StateCommitment(), vs2.StateCommitment())
+		require.EqualValues(t, []byte("value 3"), vs2.KVStoreReader().MustGet(kvKey(5, 3)))
+		require.Nil(t, vs2.KVStoreReader().MustGet(kvKey(4, 0)))
+
+		// the state must be continued with the next block
+		su := NewStateUpdateWithBlocklogValues(6, time.Now(), vs2.StateCommitment())
+		su.Mutations().Set("key", []byte("value"))
+		block, err := newBlock(su.Mutations())
+		require.NoError(t, err)
+		require.NoError(t, vs2.ApplyBlock(block))
+		require.NoError(t, vs2.Commit(block))
+		proof, err := vs2.GetProof("key")
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(vs2.StateCommitment(), []byte("value")))
+	})
+	t.Run("import over existing state", func(t *testing.T) {
+		store2 := mapdb.NewMapDB()
+		vs2, err := CreateOriginState(store2, chainID)
+		require.NoError(t, err)
+		su := NewStateUpdateWithBlocklogValues(1, time.Now(), vs2.StateCommitment())
+		su.Mutations().Set("garbage", []byte("garbage"))
+		block, err := newBlock(su.Mutations())
+		require.NoError(t, err)
+		require.NoError(t, vs2.ApplyBlock(block))
+		require.NoError(t, vs2.Commit(block))
+
+		vs2, err = ImportSnapshot(store2, chainID, bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		require.EqualValues(t, vs.StateCommitment(), vs2.StateCommitment())
+		require.Nil(t, vs2.KVStoreReader().MustGet("garbage"))
+		block1, err := LoadBlock(store2, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, block.Bytes(), block1.Bytes())
+	})
+	t.Run("tampered snapshot", func(t *testing.T) {
+		for _, first := range []bool{true, false} {
+			// the first occurrence is in the block of the header, the last is in the state
+			data := make([]byte, buf.Len())
+			copy(data, buf.Bytes())
+			pos := bytes.LastIndex(data, []byte("value 7"))
+			if first {
+				pos = bytes.Index(data, []byte("value 7"))
+			}
+			data[pos+len("value ")] = '8'
+			store2 := mapdb.NewMapDB()
+			_, err := ImportSnapshot(store2, chainID, bytes.NewReader(data))
+			require.Error(t, err)
+			_, exists, err := LoadSolidState(store2, chainID)
+			require.NoError(t, err)
+			require.False(t, exists)
+		}
+	})
+}
+
+func kvKey(i uint32, j int) kv.Key {
+	return kv.Key(fmt.Sprintf("key %d %d", i, j))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/trie"
 	"github.com/iotaledger/wasp/packages/util"
 	"golang.org/x/xerrors"
 )
@@ -25,20 +26,18 @@ import (
 // region VirtualStateAccess /////////////////////////////////////////////////
 
 type virtualStateAccess struct {
-	chainID            *iscp.ChainID
-	db                 kvstore.KVStore
-	kvs                *buffered.BufferedKVStoreAccess
-	committedHash      hashing.HashValue
-	appliedBlockHashes []hashing.HashValue
+	chainID       *iscp.ChainID
+	db            kvstore.KVStore
+	kvs           *buffered.BufferedKVStoreAccess
+	committedHash hashing.HashValue
 }
 
 // newVirtualState creates VirtualStateAccess interface with the partition of KVStore
 func newVirtualState(db kvstore.KVStore, chainID *iscp.ChainID) *virtualStateAccess {
 	sub := subRealm(db, []byte{dbkeys.ObjectTypeStateVariable})
 	ret := &virtualStateAccess{
-		db:                 db,
-		kvs:                buffered.NewBufferedKVStoreAccess(kv.NewHiveKVStoreReader(sub)),
-		appliedBlockHashes: make([]hashing.HashValue, 0),
+		db:  db,
+		kvs: buffered.NewBufferedKVStoreAccess(kv.NewHiveKVStoreReader(sub)),
 	}
 	if chainID != nil {
 		ret.chainID = chainID
@@ -67,29 +66,28 @@ func subRealm(db kvstore.KVStore, realm []byte) kvstore.KVStore {
 	return db.WithRealm(append(db.Realm(), realm...))
 }
 
+// trieNodeStore is the partition of the DB which contains committed nodes of the state trie
+func trieNodeStore(db kvstore.KVStore) trie.NodeStore {
+	if db == nil {
+		return nil
+	}
+	return kv.NewHiveKVStoreReader(subRealm(db, []byte{dbkeys.ObjectTypeTrieNode}))
+}
+
 func (vs *virtualStateAccess) Copy() VirtualStateAccess {
-	ret := &virtualStateAccess{
-		chainID:            vs.chainID.Clone(),
-		db:                 vs.db,
-		committedHash:      vs.committedHash,
-		appliedBlockHashes: make([]hashing.HashValue, len(vs.appliedBlockHashes)),
-		kvs:                vs.kvs.Copy(),
+	return &virtualStateAccess{
+		chainID:       vs.chainID.Clone(),
+		db:            vs.db,
+		committedHash: vs.committedHash,
+		kvs:           vs.kvs.Copy(),
 	}
-	copy(ret.appliedBlockHashes, vs.appliedBlockHashes)
-	return ret
 }
 
 func (vs *virtualStateAccess) DangerouslyConvertToString() string {
-	blockHashes := "["
-	for _, blockHash := range vs.appliedBlockHashes {
-		blockHashes += blockHash.String() + ", "
-	}
-	blockHashes += "]"
-	return fmt.Sprintf("#%d, ts: %v, committed hash: %s, applied block hashes: %s\n%s",
+	return fmt.Sprintf("#%d, ts: %v, committed hash: %s\n%s",
 		vs.BlockIndex(),
 		vs.Timestamp(),
 		vs.committedHash.String(),
-		blockHashes,
 		vs.KVStore().DangerouslyDumpToString(),
 	)
 }
@@ -142,7 +140,6 @@ func (vs *virtualStateAccess) ApplyBlock(b Block) error {
 
 func (vs *virtualStateAccess) applyBlockNoCheck(b Block) {
 	vs.ApplyStateUpdates(b.(*blockImpl).stateUpdate)
-	vs.appliedBlockHashes = append(vs.appliedBlockHashes, hashing.HashData(b.EssenceBytes()))
 }
 
 // ApplyStateUpdates applies one state update. Doesn't change the state hash: it can be changed by Apply block
@@ -170,25 +167,48 @@ func (vs *virtualStateAccess) ExtractBlock() (Block, error) {
 	return ret, nil
 }
 
-// StateCommitment returns the hash of the state, calculated as a hashing of the previous (committed) state hash and the block hash.
+// StateCommitment returns the root of the Merkle Patricia trie over all key/value pairs of the state,
+// including not yet committed mutations
 func (vs *virtualStateAccess) StateCommitment() hashing.HashValue {
 	if vs.kvs.Mutations().IsEmpty() {
 		return vs.committedHash
 	}
-	if len(vs.appliedBlockHashes) == 0 {
-		block, err := vs.ExtractBlock()
-		if err != nil {
-			panic(xerrors.Errorf("StateCommitment: %v", err))
-		}
-		vs.appliedBlockHashes = append(vs.appliedBlockHashes, hashing.HashData(block.EssenceBytes()))
+	tr, err := vs.stateTrie()
+	if err != nil {
+		panic(xerrors.Errorf("StateCommitment: %v", err))
 	}
-	ret := vs.committedHash
-	for i := range vs.appliedBlockHashes {
-		ret = hashing.HashData(ret[:], vs.appliedBlockHashes[i][:])
+	ret, err := tr.RootHash()
+	if err != nil {
+		panic(xerrors.Errorf("StateCommitment: %v", err))
 	}
 	return ret
 }
 
+// GetProof returns proof of inclusion or absence of the key against the StateCommitment
+func (vs *virtualStateAccess) GetProof(key kv.Key) (*trie.Proof, error) {
+	tr, err := vs.stateTrie()
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetProof([]byte(key))
+}
+
+// stateTrie returns committed state trie with not yet committed mutations applied in memory
+func (vs *virtualStateAccess) stateTrie() (*trie.Trie, error) {
+	ret := trie.New(trieNodeStore(vs.db))
+	for k, v := range vs.kvs.Mutations().Sets {
+		if err := ret.Update([]byte(k), v); err != nil {
+			return nil, err
+		}
+	}
+	for k := range vs.kvs.Mutations().Dels {
+		if err := ret.Delete([]byte(k)); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 // endregion ////////////////////////////////////////////////////////////
 
 // region OptimisticStateReader ///////////////////////////////////////////////////
@@ -197,14 +217,18 @@ func (vs *virtualStateAccess) StateCommitment() hashing.HashValue {
 type OptimisticStateReaderImpl struct {
 	db         kvstore.KVStore
 	chainState *optimism.OptimisticKVStoreReader
+	trieNodes  *optimism.OptimisticKVStoreReader
 }
 
 // NewOptimisticStateReader creates new optimistic read-only access to the database. It contains own read baseline
 func NewOptimisticStateReader(db kvstore.KVStore, glb coreutil.ChainStateSync) *OptimisticStateReaderImpl {
 	chainState := kv.NewHiveKVStoreReader(subRealm(db, []byte{dbkeys.ObjectTypeStateVariable}))
+	trieNodes := kv.NewHiveKVStoreReader(subRealm(db, []byte{dbkeys.ObjectTypeTrieNode}))
+	baseline := glb.GetSolidIndexBaseline()
 	return &OptimisticStateReaderImpl{
 		db:         db,
-		chainState: optimism.NewOptimisticKVStoreReader(chainState, glb.GetSolidIndexBaseline()),
+		chainState: optimism.NewOptimisticKVStoreReader(chainState, baseline),
+		trieNodes:  optimism.NewOptimisticKVStoreReader(trieNodes, baseline),
 	}
 }
 
@@ -246,8 +270,14 @@ func (r *OptimisticStateReaderImpl) KVStoreReader() kv.KVStoreReader {
 	return r.chainState
 }
 
+// GetProof returns proof of inclusion or absence of the key against the committed state hash
+func (r *OptimisticStateReaderImpl) GetProof(key kv.Key) (*trie.Proof, error) {
+	return trie.New(r.trieNodes).GetProof([]byte(key))
+}
+
 func (r *OptimisticStateReaderImpl) SetBaseline() {
 	r.chainState.SetBaseline()
+	r.trieNodes.SetBaseline()
 }
 
 // endregion ////////////////////////////////////////////////////////
@@ -299,6 +329,13 @@ func (s *mustOptimisticVirtualStateAccess) StateCommitment() hashing.HashValue {
 	return s.state.StateCommitment()
 }
 
+func (s *mustOptimisticVirtualStateAccess) GetProof(key kv.Key) (*trie.Proof, error) {
+	s.baseline.MustValidate()
+	defer s.baseline.MustValidate()
+
+	return s.state.GetProof(key)
+}
+
 func (s *mustOptimisticVirtualStateAccess) KVStoreReader() kv.KVStoreReader {
 	s.baseline.MustValidate()
 	defer s.baseline.MustValidate()
@@ -453,3 +453,60 @@ func TestVirtualStateMustOptimistic2(t *testing.T) {
 	require.EqualValues(t, hash, hashOpt)
 	require.NotEqualValues(t, hashPrev, hashOpt)
 }
+
+func TestStateProof(t *testing.T) {
+	store := mapdb.NewMapDB()
+	chainID := iscp.RandomChainID([]byte("1"))
+	vs, err := CreateOriginState(store, chainID)
+	require.NoError(t, err)
+
+	su := NewStateUpdateWithBlocklogValues(1, time.Now(), vs.StateCommitment())
+	su.Mutations().Set("key", []byte("value"))
+	block1, err := newBlock(su.Mutations())
+	require.NoError(t, err)
+	err = vs.ApplyBlock(block1)
+	require.NoError(t, err)
+
+	t.Run("uncommitted", func(t *testing.T) {
+		proof, err := vs.GetProof("key")
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(vs.StateCommitment(), []byte("value")))
+	})
+	err = vs.Commit(block1)
+	require.NoError(t, err)
+
+	glb := coreutil.NewChainStateSync()
+	glb.SetSolidIndex(1)
+	rdr := NewOptimisticStateReader(store, glb)
+	stateHash, err := rdr.Hash()
+	require.NoError(t, err)
+	require.EqualValues(t, vs.StateCommitment(), stateHash)
+
+	t.Run("inclusion", func(t *testing.T) {
+		proof, err := rdr.GetProof("key")
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(stateHash, []byte("value")))
+		require.Error(t, proof.Verify(stateHash, []byte("other value")))
+
+		proof, err = rdr.GetProof(kv.Key(coreutil.StatePrefixBlockIndex))
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(stateHash, codec.EncodeUint64(1)))
+	})
+	t.Run("absence", func(t *testing.T) {
+		proof, err := rdr.GetProof("no such key")
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(stateHash, nil))
+	})
+	t.Run("invalidated", func(t *testing.T) {
+		glb.InvalidateSolidIndex()
+		_, err := rdr.GetProof("key")
+		require.EqualValues(t, coreutil.ErrorStateInvalidated, err)
+	})
+	t.Run("baseline reset", func(t *testing.T) {
+		glb.SetSolidIndex(1)
+		rdr.SetBaseline()
+		proof, err := rdr.GetProof("key")
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(stateHash, []byte("value")))
+	})
+}
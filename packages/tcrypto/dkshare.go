@@ -18,6 +18,13 @@ import (
 	"go.dedis.ch/kyber/v3/sign/tbls"
 )
 
+// ShareSigner produces signature and decryption shares on behalf of the node. It is used by the
+// DKShare, whose private share is kept outside of the node, e.g. in a separate signer process.
+type ShareSigner interface {
+	SignShare(sharedAddress ledgerstate.Address, data []byte) (tbls.SigShare, error)
+	DecryptShare(sharedAddress ledgerstate.Address, ciphertext, additionalData []byte) (*DecryptionShare, error)
+}
+
 // DKShare stands for the information stored on
 // a node as a result of the DKG procedure.
 type DKShare struct {
@@ -28,8 +35,9 @@ type DKShare struct {
 	SharedPublic  kyber.Point
 	PublicCommits []kyber.Point
 	PublicShares  []kyber.Point
-	PrivateShare  kyber.Scalar
-	suite         Suite // Transient, only needed for un-marshaling.
+	PrivateShare  kyber.Scalar // nil, if the private share is held by the signer.
+	suite         Suite        // Transient, only needed for un-marshaling.
+	signer        ShareSigner  // Transient, signs instead of the PrivateShare, if set.
 }
 
 // NewDKShare creates new share of the key.
@@ -76,6 +84,19 @@ func DKShareFromBytes(buf []byte, suite Suite) (*DKShare, error) {
 	return &s, nil
 }
 
+// SetSigner makes the share sign with the signer instead of the private share.
+func (s *DKShare) SetSigner(signer ShareSigner) {
+	s.signer = signer
+}
+
+// WithoutPrivateShare returns a copy of the share without the private part.
+func (s *DKShare) WithoutPrivateShare() *DKShare {
+	ret := *s
+	ret.PrivateShare = nil
+	ret.signer = nil
+	return &ret
+}
+
 // Bytes returns byte representation of the share.
 func (s *DKShare) Bytes() []byte {
 	var buf bytes.Buffer
@@ -120,6 +141,9 @@ func (s *DKShare) Write(w io.Writer) error {
 			return err
 		}
 	}
+	if s.PrivateShare == nil {
+		return nil
+	}
 	return util.WriteMarshaled(w, s.PrivateShare)
 }
 
@@ -174,14 +198,28 @@ func (s *DKShare) Read(r io.Reader) error {
 		}
 	}
 	//
-	// Private share.
+	// Private share, the rest of the record. It is absent if the share is held by the signer.
+	var privateShareBytes []byte
+	if privateShareBytes, err = io.ReadAll(r); err != nil {
+		return err
+	}
+	if len(privateShareBytes) == 0 {
+		s.PrivateShare = nil
+		return nil
+	}
 	s.PrivateShare = s.suite.G2().Scalar()
-	return util.ReadMarshaled(r, s.PrivateShare)
+	return util.ReadMarshaled(bytes.NewReader(privateShareBytes), s.PrivateShare)
 }
 
 // SignShare signs the data with the own key share.
 // returns SigShare, which contains signature and the index
 func (s *DKShare) SignShare(data []byte) (tbls.SigShare, error) {
+	if s.signer != nil {
+		return s.signer.SignShare(s.Address, data)
+	}
+	if s.PrivateShare == nil {
+		return nil, xerrors.Errorf("DKShare.SignShare: private share of %s is not available", s.Address.Base58())
+	}
 	priShare := share.PriShare{
 		I: int(*s.Index),
 		V: s.PrivateShare,
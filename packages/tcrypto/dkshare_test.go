@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 	"go.dedis.ch/kyber/v3/util/random"
 
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -43,3 +45,51 @@ func TestMarshaling(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, dks.Bytes(), dksBack.Bytes())
 }
+
+type testShareSigner struct {
+	dkShare *DKShare
+}
+
+func (s *testShareSigner) SignShare(sharedAddress ledgerstate.Address, data []byte) (tbls.SigShare, error) {
+	return s.dkShare.SignShare(data)
+}
+
+func (s *testShareSigner) DecryptShare(sharedAddress ledgerstate.Address, ciphertext, additionalData []byte) (*DecryptionShare, error) {
+	return s.dkShare.DecryptShare(ciphertext, additionalData)
+}
+
+func TestWithoutPrivateShare(t *testing.T) {
+	suite := bn256.NewSuite()
+	randomness := random.New()
+
+	priPoly := share.NewPriPoly(suite.G2(), 1, nil, randomness)
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	index := uint16(0)
+	dks := &DKShare{
+		Address:       ledgerstate.NewAliasAddress(hashing.HashStrings("abc").Bytes()),
+		Index:         &index,
+		N:             1,
+		T:             1,
+		SharedPublic:  pubPoly.Commit(),
+		PublicCommits: []kyber.Point{pubPoly.Commit()},
+		PublicShares:  []kyber.Point{pubPoly.Eval(0).V},
+		PrivateShare:  priPoly.Shares(1)[0].V,
+		suite:         suite,
+	}
+	data := []byte("data to sign")
+
+	public := dks.WithoutPrivateShare()
+	publicBack, err := DKShareFromBytes(public.Bytes(), suite)
+	require.NoError(t, err)
+	require.Nil(t, publicBack.PrivateShare)
+	require.EqualValues(t, public.Bytes(), publicBack.Bytes())
+	require.NotNil(t, dks.PrivateShare)
+
+	_, err = publicBack.SignShare(data)
+	require.Error(t, err)
+
+	publicBack.SetSigner(&testShareSigner{dkShare: dks})
+	sigShare, err := publicBack.SignShare(data)
+	require.NoError(t, err)
+	require.NoError(t, publicBack.VerifySigShare(data, sigShare))
+}
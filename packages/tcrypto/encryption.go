@@ -0,0 +1,297 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/xerrors"
+)
+
+// The data is encrypted to the shared public key of the committee with the hashed ElGamal scheme:
+// the sender picks a random r and derives the symmetric key from r*SharedPublic, which is only
+// known to the sender and, jointly, to a quorum of the committee. Each member publishes its
+// decryption share x_i*U, where U = r*G, along with the DLEQ proof of its correctness.
+// Any T valid decryption shares are interpolated to r*SharedPublic.
+//
+// As in the TDH2 scheme, the sender also proves the knowledge of r, bound to the additional data and to
+// the sealed data: Ū = r*Ḡ for the second generator Ḡ, and the non-interactive proof (e, f) of
+// log_G(U) == log_Ḡ(Ū). A ciphertext copied into another context, i.e. with other additional data,
+// fails the proof, so the committee doesn't produce the decryption shares for it.
+// The ciphertext is U || Ū || e || f || nonce || sealed data.
+
+const encryptionNonceLength = 24
+
+// encryptionGeneratorSeed derives the second generator Ḡ of the ciphertext proofs
+var encryptionGeneratorSeed = []byte("wasp-tcrypto-encryption-generator")
+
+// g2Suite is the suite for the DLEQ proofs in the G2 group of the public shares
+type g2Suite struct {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+func newG2Suite(suite Suite) *g2Suite {
+	return &g2Suite{
+		Group:       suite.G2(),
+		HashFactory: suite,
+		XOFFactory:  suite,
+		Random:      suite,
+	}
+}
+
+// ciphertext is the parsed form of the encrypted data
+type ciphertext struct {
+	u      kyber.Point  // r*G
+	uBar   kyber.Point  // r*Ḡ
+	e      kyber.Scalar // the challenge of the proof
+	f      kyber.Scalar // s + r*e
+	uBytes []byte
+	sealed []byte // nonce || sealed data
+}
+
+func encryptionGenerator(suite Suite) kyber.Point {
+	return suite.G2().Point().Pick(suite.XOF(encryptionGeneratorSeed))
+}
+
+// encryptionChallenge is the challenge of the proof of the knowledge of r for the additional data and the sealed data
+func encryptionChallenge(suite Suite, u, uBar, w, wBar kyber.Point, additionalData, sealed []byte) (kyber.Scalar, error) {
+	data := make([][]byte, 0, 6)
+	for _, p := range []kyber.Point{u, uBar, w, wBar} {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+	}
+	data = append(data, additionalData, sealed)
+	h := hashing.HashData(data...)
+	return suite.G2().Scalar().SetBytes(h[:]), nil
+}
+
+// Encrypt encrypts the data to the shared public key. The additional data is not encrypted,
+// but the ciphertext can only be decrypted with the same additional data
+func Encrypt(suite Suite, sharedPublic kyber.Point, data, additionalData []byte) ([]byte, error) {
+	g := suite.G2()
+	gBar := encryptionGenerator(suite)
+	r := g.Scalar().Pick(suite.RandomStream())
+	u := g.Point().Mul(r, nil)
+	uBar := g.Point().Mul(r, gBar)
+	uBytes, err := u.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("tcrypto.Encrypt: %w", err)
+	}
+	key, err := encryptionKey(g.Point().Mul(r, sharedPublic), uBytes, additionalData)
+	if err != nil {
+		return nil, xerrors.Errorf("tcrypto.Encrypt: %w", err)
+	}
+	var nonce [encryptionNonceLength]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, xerrors.Errorf("tcrypto.Encrypt: %w", err)
+	}
+	sealed := make([]byte, 0, encryptionNonceLength+len(data)+secretbox.Overhead)
+	sealed = append(sealed, nonce[:]...)
+	sealed = secretbox.Seal(sealed, data, &nonce, &key)
+
+	// the proof of the knowledge of r
+	sRand := g.Scalar().Pick(suite.RandomStream())
+	e, err := encryptionChallenge(suite, u, uBar, g.Point().Mul(sRand, nil), g.Point().Mul(sRand, gBar), additionalData, sealed)
+	if err != nil {
+		return nil, xerrors.Errorf("tcrypto.Encrypt: %w", err)
+	}
+	f := g.Scalar().Add(sRand, g.Scalar().Mul(r, e))
+
+	var buf bytes.Buffer
+	buf.Write(uBytes)
+	for _, m := range []kyber.Marshaling{uBar, e, f} {
+		if _, err := m.MarshalTo(&buf); err != nil {
+			return nil, xerrors.Errorf("tcrypto.Encrypt: %w", err)
+		}
+	}
+	buf.Write(sealed)
+	return buf.Bytes(), nil
+}
+
+// encryptionKey derives the symmetric key from the shared secret, the ephemeral key and the additional data
+func encryptionKey(secret kyber.Point, uBytes, additionalData []byte) ([32]byte, error) {
+	secretBytes, err := secret.MarshalBinary()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(hashing.HashData(secretBytes, uBytes, additionalData)), nil
+}
+
+func parseCiphertext(suite Suite, data []byte) (*ciphertext, error) {
+	g := suite.G2()
+	pointLen, scalarLen := g.PointLen(), g.ScalarLen()
+	if len(data) < 2*pointLen+2*scalarLen+encryptionNonceLength+secretbox.Overhead {
+		return nil, xerrors.New("ciphertext is too short")
+	}
+	ret := &ciphertext{
+		u:      g.Point(),
+		uBar:   g.Point(),
+		e:      g.Scalar(),
+		f:      g.Scalar(),
+		uBytes: data[:pointLen],
+		sealed: data[2*pointLen+2*scalarLen:],
+	}
+	r := bytes.NewReader(data)
+	for _, m := range []kyber.Marshaling{ret.u, ret.uBar, ret.e, ret.f} {
+		if _, err := m.UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// verify checks the proof of the knowledge of r for the additional data
+func (c *ciphertext) verify(suite Suite, additionalData []byte) error {
+	g := suite.G2()
+	gBar := encryptionGenerator(suite)
+	// W = f*G - e*U, W̄ = f*Ḡ - e*Ū
+	w := g.Point().Sub(g.Point().Mul(c.f, nil), g.Point().Mul(c.e, c.u))
+	wBar := g.Point().Sub(g.Point().Mul(c.f, gBar), g.Point().Mul(c.e, c.uBar))
+	e, err := encryptionChallenge(suite, c.u, c.uBar, w, wBar, additionalData, c.sealed)
+	if err != nil {
+		return err
+	}
+	if !e.Equal(c.e) {
+		return xerrors.New("the ciphertext is not valid for the additional data")
+	}
+	return nil
+}
+
+// CheckCiphertext checks if the ciphertext is well-formed and was produced for the additional data.
+// It doesn't mean it can be decrypted. The decryption shares must only be produced for the checked ciphertexts
+func (s *DKShare) CheckCiphertext(ciphertext, additionalData []byte) error {
+	c, err := parseCiphertext(s.suite, ciphertext)
+	if err != nil {
+		return err
+	}
+	return c.verify(s.suite, additionalData)
+}
+
+// DecryptionShare is the contribution of a committee member to the decryption of a ciphertext
+type DecryptionShare struct {
+	Index uint16
+	Value kyber.Point // x_i*U
+	Proof *dleq.Proof // log_G(PublicShares[Index]) == log_U(Value)
+}
+
+// DecryptionShareFromBytes reads the decryption share from bytes
+func DecryptionShareFromBytes(data []byte, suite Suite) (*DecryptionShare, error) {
+	r := bytes.NewReader(data)
+	g := suite.G2()
+	ret := &DecryptionShare{
+		Value: g.Point(),
+		Proof: &dleq.Proof{C: g.Scalar(), R: g.Scalar(), VG: g.Point(), VH: g.Point()},
+	}
+	if err := util.ReadUint16(r, &ret.Index); err != nil {
+		return nil, err
+	}
+	for _, m := range []kyber.Marshaling{ret.Value, ret.Proof.C, ret.Proof.R, ret.Proof.VG, ret.Proof.VH} {
+		if err := util.ReadMarshaled(r, m); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// Bytes returns byte representation of the decryption share
+func (d *DecryptionShare) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = util.WriteUint16(&buf, d.Index)
+	for _, m := range []kyber.Marshaling{d.Value, d.Proof.C, d.Proof.R, d.Proof.VG, d.Proof.VH} {
+		if err := util.WriteMarshaled(&buf, m); err != nil {
+			panic(xerrors.Errorf("DecryptionShare.Bytes: %w", err))
+		}
+	}
+	return buf.Bytes()
+}
+
+// DecryptShare produces the own decryption share of the ciphertext, if it was produced for the additional data
+func (s *DKShare) DecryptShare(ciphertext, additionalData []byte) (*DecryptionShare, error) {
+	if s.signer != nil {
+		return s.signer.DecryptShare(s.Address, ciphertext, additionalData)
+	}
+	if s.PrivateShare == nil {
+		return nil, xerrors.Errorf("DKShare.DecryptShare: private share of %s is not available", s.Address.Base58())
+	}
+	c, err := parseCiphertext(s.suite, ciphertext)
+	if err != nil {
+		return nil, xerrors.Errorf("DKShare.DecryptShare: %w", err)
+	}
+	if err := c.verify(s.suite, additionalData); err != nil {
+		return nil, xerrors.Errorf("DKShare.DecryptShare: %w", err)
+	}
+	proof, _, value, err := dleq.NewDLEQProof(newG2Suite(s.suite), s.suite.G2().Point().Base(), c.u, s.PrivateShare)
+	if err != nil {
+		return nil, xerrors.Errorf("DKShare.DecryptShare: %w", err)
+	}
+	return &DecryptionShare{
+		Index: *s.Index,
+		Value: value,
+		Proof: proof,
+	}, nil
+}
+
+// VerifyDecryptionShare checks the decryption share of the ciphertext against the public share of its producer
+func (s *DKShare) VerifyDecryptionShare(ciphertext []byte, decShare *DecryptionShare) error {
+	if decShare.Index >= s.N || int(decShare.Index) >= len(s.PublicShares) {
+		return xerrors.Errorf("VerifyDecryptionShare: wrong index %d", decShare.Index)
+	}
+	c, err := parseCiphertext(s.suite, ciphertext)
+	if err != nil {
+		return xerrors.Errorf("VerifyDecryptionShare: %w", err)
+	}
+	err = decShare.Proof.Verify(newG2Suite(s.suite), s.suite.G2().Point().Base(), c.u, s.PublicShares[decShare.Index], decShare.Value)
+	if err != nil {
+		return xerrors.Errorf("VerifyDecryptionShare: %w", err)
+	}
+	return nil
+}
+
+// Decrypt recovers the plaintext from T decryption shares. The shares must be verified beforehand
+func (s *DKShare) Decrypt(ciphertext []byte, decShares []*DecryptionShare, additionalData []byte) ([]byte, error) {
+	c, err := parseCiphertext(s.suite, ciphertext)
+	if err != nil {
+		return nil, xerrors.Errorf("DKShare.Decrypt: %w", err)
+	}
+	var secret kyber.Point
+	switch {
+	case len(decShares) == 0:
+		return nil, xerrors.New("DKShare.Decrypt: no decryption shares")
+	case s.N > 1:
+		pubShares := make([]*share.PubShare, len(decShares))
+		for i, d := range decShares {
+			pubShares[i] = &share.PubShare{I: int(d.Index), V: d.Value}
+		}
+		if secret, err = share.RecoverCommit(s.suite.G2(), pubShares, int(s.T), int(s.N)); err != nil {
+			return nil, xerrors.Errorf("DKShare.Decrypt: %w", err)
+		}
+	default:
+		secret = decShares[0].Value
+	}
+	key, err := encryptionKey(secret, c.uBytes, additionalData)
+	if err != nil {
+		return nil, xerrors.Errorf("DKShare.Decrypt: %w", err)
+	}
+	var nonce [encryptionNonceLength]byte
+	copy(nonce[:], c.sealed[:encryptionNonceLength])
+	ret, ok := secretbox.Open(nil, c.sealed[encryptionNonceLength:], &nonce, &key)
+	if !ok {
+		return nil, xerrors.New("DKShare.Decrypt: the ciphertext can't be decrypted")
+	}
+	return ret, nil
+}
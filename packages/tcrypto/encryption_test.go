@@ -0,0 +1,72 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdDecryption(t *testing.T) {
+	suite := DefaultSuite()
+	dkShares := newTestDKShares(t, suite, 4, 3)
+	data := []byte("sealed bid")
+	aad := []byte("additional data")
+
+	ciphertext, err := Encrypt(suite, dkShares[0].SharedPublic, data, aad)
+	require.NoError(t, err)
+
+	decShares := make([]*DecryptionShare, len(dkShares))
+	for i, dks := range dkShares {
+		decShares[i], err = dks.DecryptShare(ciphertext, aad)
+		require.NoError(t, err)
+		back, err := DecryptionShareFromBytes(decShares[i].Bytes(), suite)
+		require.NoError(t, err)
+		require.EqualValues(t, decShares[i].Bytes(), back.Bytes())
+		// any member verifies the shares of the others
+		require.NoError(t, dkShares[0].VerifyDecryptionShare(ciphertext, back))
+	}
+	// the ciphertext with other additional data gets no decryption shares
+	require.NoError(t, dkShares[0].CheckCiphertext(ciphertext, aad))
+	require.Error(t, dkShares[0].CheckCiphertext(ciphertext, []byte("other data")))
+	_, err = dkShares[0].DecryptShare(ciphertext, []byte("other data"))
+	require.Error(t, err)
+	// the sealed data can't be replaced either
+	tampered := append([]byte{}, ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+	require.Error(t, dkShares[0].CheckCiphertext(tampered, aad))
+
+	// a share of other ciphertext is rejected
+	other, err := Encrypt(suite, dkShares[0].SharedPublic, data, aad)
+	require.NoError(t, err)
+	require.Error(t, dkShares[0].VerifyDecryptionShare(other, decShares[1]))
+
+	plaintext, err := dkShares[0].Decrypt(ciphertext, decShares[1:], aad)
+	require.NoError(t, err)
+	require.EqualValues(t, data, plaintext)
+
+	_, err = dkShares[0].Decrypt(ciphertext, decShares[:2], aad)
+	require.Error(t, err)
+	_, err = dkShares[0].Decrypt(ciphertext, decShares[:3], []byte("other data"))
+	require.Error(t, err)
+}
+
+func TestDecryptionSingleShare(t *testing.T) {
+	suite := DefaultSuite()
+	dks := newTestDKShares(t, suite, 1, 1)[0]
+	ciphertext, err := Encrypt(suite, dks.SharedPublic, []byte("data"), nil)
+	require.NoError(t, err)
+
+	public := dks.WithoutPrivateShare()
+	_, err = public.DecryptShare(ciphertext, nil)
+	require.Error(t, err)
+	public.SetSigner(&testShareSigner{dkShare: dks})
+	decShare, err := public.DecryptShare(ciphertext, nil)
+	require.NoError(t, err)
+	require.NoError(t, public.VerifyDecryptionShare(ciphertext, decShare))
+	plaintext, err := public.Decrypt(ciphertext, []*DecryptionShare{decShare}, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, []byte("data"), plaintext)
+}
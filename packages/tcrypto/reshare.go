@@ -0,0 +1,113 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"golang.org/x/xerrors"
+)
+
+// ReshareDeal is the contribution of a member of the old group to the resharing of the key to a new group.
+// It is a random polynomial of the new threshold, whose secret is the private share of the dealer.
+// Commits are the public commitments to the coefficients of the polynomial,
+// Shares[i] is the evaluation of the polynomial for the member i of the new group.
+//
+// The key is reshared by the T members of the old group, each of them deals its own share.
+// The new share of a member of the new group is the Lagrange interpolation of the evaluations
+// it received, so the shared key, and therefore the address, stays the same.
+type ReshareDeal struct {
+	Commits []kyber.Point
+	Shares  []kyber.Scalar
+}
+
+// NewReshareDeal creates the deal of this member for the new group of n members with the threshold t.
+func (s *DKShare) NewReshareDeal(suite Suite, n, t uint16) (*ReshareDeal, error) {
+	if s.PrivateShare == nil {
+		return nil, xerrors.Errorf("NewReshareDeal: private share of %s is not available", s.Address.Base58())
+	}
+	if t < 1 || t > n {
+		return nil, xerrors.Errorf("NewReshareDeal: wrong parameters: N = %d, T = %d", n, t)
+	}
+	priPoly := share.NewPriPoly(suite, int(t), s.PrivateShare, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+	ret := &ReshareDeal{
+		Commits: commits,
+		Shares:  make([]kyber.Scalar, n),
+	}
+	for _, priShare := range priPoly.Shares(int(n)) {
+		ret.Shares[priShare.I] = priShare.V
+	}
+	return ret, nil
+}
+
+// VerifyReshareShare checks the evaluation for the member newIndex of the new group, received from the dealer,
+// against the commitments of the deal. The commitments must correspond to the public share of the dealer.
+func (s *DKShare) VerifyReshareShare(suite Suite, dealerIndex uint16, commits []kyber.Point, newIndex uint16, value kyber.Scalar) error {
+	if int(dealerIndex) >= len(s.PublicShares) {
+		return xerrors.Errorf("VerifyReshareShare: wrong dealer index %d", dealerIndex)
+	}
+	if len(commits) == 0 || !commits[0].Equal(s.PublicShares[dealerIndex]) {
+		return xerrors.Errorf("VerifyReshareShare: the deal of %d does not correspond to its public share", dealerIndex)
+	}
+	if !share.NewPubPoly(suite, nil, commits).Check(&share.PriShare{I: int(newIndex), V: value}) {
+		return xerrors.Errorf("VerifyReshareShare: the share from %d does not correspond to the commitments", dealerIndex)
+	}
+	return nil
+}
+
+// Reshare combines the evaluations for the member newIndex, received from the T dealers of the old group,
+// into the share of the same key in the new group of n members with the threshold t.
+// The receiver is the share of the old group, the private share is not needed,
+// so it can be used by the new members, which know only the public part of it.
+func (s *DKShare) Reshare(
+	suite Suite,
+	dealers []uint16,
+	commits [][]kyber.Point,
+	values []kyber.Scalar,
+	newIndex, n, t uint16,
+) (*DKShare, error) {
+	if len(dealers) < int(s.T) || len(commits) != len(dealers) || len(values) != len(dealers) {
+		return nil, xerrors.Errorf("Reshare: %d deals received, %d needed", len(dealers), s.T)
+	}
+	priShares := make([]*share.PriShare, len(dealers))
+	for i, dealer := range dealers {
+		if len(commits[i]) != int(t) {
+			return nil, xerrors.Errorf("Reshare: the deal of %d has %d commitments, expected %d", dealer, len(commits[i]), t)
+		}
+		if err := s.VerifyReshareShare(suite, dealer, commits[i], newIndex, values[i]); err != nil {
+			return nil, err
+		}
+		priShares[i] = &share.PriShare{I: int(dealer), V: values[i]}
+	}
+	privateShare, err := share.RecoverSecret(suite, priShares, int(s.T), int(s.N))
+	if err != nil {
+		return nil, xerrors.Errorf("Reshare: %w", err)
+	}
+	// The commitments of the new polynomial are interpolated the same way as the shares.
+	publicCommits := make([]kyber.Point, t)
+	for k := range publicCommits {
+		pubShares := make([]*share.PubShare, len(dealers))
+		for i, dealer := range dealers {
+			pubShares[i] = &share.PubShare{I: int(dealer), V: commits[i][k]}
+		}
+		if publicCommits[k], err = share.RecoverCommit(suite, pubShares, int(s.T), int(s.N)); err != nil {
+			return nil, xerrors.Errorf("Reshare: %w", err)
+		}
+	}
+	pubPoly := share.NewPubPoly(suite, nil, publicCommits)
+	publicShares := make([]kyber.Point, n)
+	for i := range publicShares {
+		publicShares[i] = pubPoly.Eval(i).V
+	}
+	ret, err := NewDKShare(newIndex, n, t, publicCommits[0], publicCommits, publicShares, privateShare)
+	if err != nil {
+		return nil, err
+	}
+	ret.suite = suite
+	if !ret.Address.Equals(s.Address) {
+		return nil, xerrors.Errorf("Reshare: the reshared key does not correspond to %s", s.Address.Base58())
+	}
+	return ret, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/bdn"
+)
+
+func newTestDKShares(t *testing.T, suite Suite, n, th uint16) []*DKShare {
+	priPoly := share.NewPriPoly(suite, int(th), nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+	publicShares := make([]kyber.Point, n)
+	for i := range publicShares {
+		publicShares[i] = pubPoly.Eval(i).V
+	}
+	ret := make([]*DKShare, n)
+	for i, priShare := range priPoly.Shares(int(n)) {
+		dks, err := NewDKShare(uint16(i), n, th, pubPoly.Commit(), commits, publicShares, priShare.V)
+		require.NoError(t, err)
+		dks.suite = suite
+		ret[i] = dks
+	}
+	return ret
+}
+
+func TestReshare(t *testing.T) {
+	suite := DefaultSuite()
+	oldShares := newTestDKShares(t, suite, 4, 3)
+	// the first member is not among the dealers
+	dealers := []uint16{1, 2, 3}
+	deals := make([]*ReshareDeal, len(dealers))
+	commits := make([][]kyber.Point, len(dealers))
+	for i, d := range dealers {
+		var err error
+		deals[i], err = oldShares[d].NewReshareDeal(suite, 5, 4)
+		require.NoError(t, err)
+		commits[i] = deals[i].Commits
+	}
+
+	newShares := make([]*DKShare, 5)
+	for j := range newShares {
+		values := make([]kyber.Scalar, len(dealers))
+		for i := range dealers {
+			values[i] = deals[i].Shares[j]
+		}
+		// the new members know only the public part of the old share
+		var err error
+		newShares[j], err = oldShares[0].WithoutPrivateShare().Reshare(suite, dealers, commits, values, uint16(j), 5, 4)
+		require.NoError(t, err)
+		require.True(t, newShares[j].Address.Equals(oldShares[0].Address))
+		require.True(t, newShares[j].SharedPublic.Equal(oldShares[0].SharedPublic))
+		for i := range newShares[j].PublicShares {
+			require.True(t, newShares[j].PublicShares[i].Equal(newShares[0].PublicShares[i]))
+		}
+	}
+
+	data := []byte("data to sign")
+	sigShares := make([][]byte, 0, 4)
+	for _, j := range []int{4, 0, 2, 3} {
+		sigShare, err := newShares[j].SignShare(data)
+		require.NoError(t, err)
+		require.NoError(t, newShares[0].VerifySigShare(data, sigShare))
+		sigShares = append(sigShares, sigShare)
+	}
+	sig, err := newShares[0].RecoverFullSignature(sigShares, data)
+	require.NoError(t, err)
+	require.NoError(t, bdn.Verify(suite, oldShares[0].SharedPublic, data, sig.Signature.Bytes()))
+}
+
+func TestReshareWrongDeal(t *testing.T) {
+	suite := DefaultSuite()
+	oldShares := newTestDKShares(t, suite, 4, 3)
+	dealers := []uint16{0, 1, 2}
+	commits := make([][]kyber.Point, len(dealers))
+	values := make([]kyber.Scalar, len(dealers))
+	for i, d := range dealers {
+		deal, err := oldShares[d].NewReshareDeal(suite, 4, 3)
+		require.NoError(t, err)
+		commits[i] = deal.Commits
+		values[i] = deal.Shares[1]
+	}
+	_, err := oldShares[3].Reshare(suite, dealers[:2], commits[:2], values[:2], 1, 4, 3)
+	require.Error(t, err)
+
+	values[2] = suite.Scalar().Pick(suite.RandomStream())
+	_, err = oldShares[3].Reshare(suite, dealers, commits, values, 1, 4, 3)
+	require.Error(t, err)
+
+	// the deal is not of the share of the dealer
+	values[2] = values[1]
+	commits[2] = commits[1]
+	_, err = oldShares[3].Reshare(suite, dealers, commits, values, 1, 4, 3)
+	require.Error(t, err)
+}
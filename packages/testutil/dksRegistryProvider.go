@@ -31,6 +31,18 @@ func (p *DkgRegistryProvider) SaveDKShare(dkShare *tcrypto.DKShare) error {
 	return nil
 }
 
+// ReplaceDKShare implements dkg.DKShareRegistryProvider.
+func (p *DkgRegistryProvider) ReplaceDKShare(dkShare *tcrypto.DKShare) error {
+	p.DB[dkShare.Address.String()] = dkShare.Bytes()
+	return nil
+}
+
+// DeleteDKShare implements dkg.DKShareRegistryProvider.
+func (p *DkgRegistryProvider) DeleteDKShare(sharedAddress ledgerstate.Address) error {
+	delete(p.DB, sharedAddress.String())
+	return nil
+}
+
 // LoadDKShare implements dkg.DKShareRegistryProvider.
 func (p *DkgRegistryProvider) LoadDKShare(sharedAddress ledgerstate.Address) (*tcrypto.DKShare, error) {
 	dkShareBytes := p.DB[sharedAddress.String()]
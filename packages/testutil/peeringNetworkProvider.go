@@ -72,15 +72,6 @@ func (p *PeeringNetwork) NetworkProviders() []peering.NetworkProvider {
 	return cp
 }
 
-func (p *PeeringNetwork) nodeByNetID(nodeNetID string) *peeringNode {
-	for i := range p.nodes {
-		if p.nodes[i].netID == nodeNetID {
-			return p.nodes[i]
-		}
-	}
-	return nil
-}
-
 // Close implements the io.Closer interface.
 func (p *PeeringNetwork) Close() error {
 	for _, n := range p.nodes {
@@ -203,11 +194,11 @@ func (p *peeringNetworkProvider) Self() peering.PeerSender {
 func (p *peeringNetworkProvider) PeerGroup(peeringID peering.PeeringID, peerAddrs []string) (peering.GroupProvider, error) {
 	peers := make([]peering.PeerSender, len(peerAddrs))
 	for i := range peerAddrs {
-		n := p.network.nodeByNetID(peerAddrs[i])
-		if n == nil {
+		s := p.senderByNetID(peerAddrs[i])
+		if s == nil {
 			return nil, errors.New("unknown_node_location")
 		}
-		peers[i] = p.senders[i]
+		peers[i] = s
 	}
 	return group.NewPeeringGroupProvider(p, peeringID, peers, p.network.log)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/contracts/native/inccounter"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 	"github.com/iotaledger/wasp/packages/peering"
@@ -34,6 +35,7 @@ type MockedChainCore struct {
 	onDismissChain          func(reason string)
 	onLedgerState           func(chainOutput *ledgerstate.AliasOutput, timestamp time.Time)
 	onOffLedgerRequest      func(msg *messages.OffLedgerRequestMsgIn)
+	onOffLedgerRequestBatch func(msg *messages.OffLedgerRequestBatchMsgIn)
 	onRequestAck            func(msg *messages.RequestAckMsgIn)
 	onMissingRequestIDs     func(msg *messages.MissingRequestIDsMsgIn)
 	onMissingRequest        func(msg *messages.MissingRequestMsg)
@@ -77,7 +79,10 @@ func NewMockedChainCore(t *testing.T, chainID *iscp.ChainID, log *logger.Logger)
 		onLedgerState: func(chainOutput *ledgerstate.AliasOutput, timestamp time.Time) {
 			t.Fatalf("Receiving ledger state not implemented, chain output=%v", chainOutput)
 		},
-		onOffLedgerRequest:  func(msg *messages.OffLedgerRequestMsgIn) { receiveFailFun("*messages.OffLedgerRequestMsgIn", msg) },
+		onOffLedgerRequest: func(msg *messages.OffLedgerRequestMsgIn) { receiveFailFun("*messages.OffLedgerRequestMsgIn", msg) },
+		onOffLedgerRequestBatch: func(msg *messages.OffLedgerRequestBatchMsgIn) {
+			receiveFailFun("*messages.OffLedgerRequestBatchMsgIn", msg)
+		},
 		onRequestAck:        func(msg *messages.RequestAckMsgIn) { receiveFailFun("*messages.RequestAckMsgIn", msg) },
 		onMissingRequestIDs: func(msg *messages.MissingRequestIDsMsgIn) { receiveFailFun("*messages.MissingRequestIDsMsgIn", msg) },
 		onMissingRequest:    func(msg *messages.MissingRequestMsg) { receiveFailFun("*messages.MissingRequestMsg", msg) },
@@ -111,6 +116,14 @@ func (m *MockedChainCore) GetStateReader() state.OptimisticStateReader {
 	return m.onGetStateReader()
 }
 
+func (m *MockedChainCore) GetHistoricalStateReader(blockIndex uint32) (state.OptimisticStateReader, error) {
+	panic("implement me")
+}
+
+func (m *MockedChainCore) FindBlockIndexByStateHash(stateHash hashing.HashValue) (uint32, error) {
+	panic("implement me")
+}
+
 func (m *MockedChainCore) GetCommitteeInfo() *chain.CommitteeInfo {
 	panic("implement me")
 }
@@ -131,6 +144,10 @@ func (m *MockedChainCore) EnqueueOffLedgerRequestMsg(msg *messages.OffLedgerRequ
 	m.onOffLedgerRequest(msg)
 }
 
+func (m *MockedChainCore) EnqueueOffLedgerRequestBatchMsg(msg *messages.OffLedgerRequestBatchMsgIn) {
+	m.onOffLedgerRequestBatch(msg)
+}
+
 func (m *MockedChainCore) EnqueueRequestAckMsg(msg *messages.RequestAckMsgIn) {
 	m.onRequestAck(msg)
 }
@@ -191,6 +208,10 @@ func (m *MockedChainCore) OnOffLedgerRequest(fun func(msg *messages.OffLedgerReq
 	m.onOffLedgerRequest = fun
 }
 
+func (m *MockedChainCore) OnOffLedgerRequestBatch(fun func(msg *messages.OffLedgerRequestBatchMsgIn)) {
+	m.onOffLedgerRequestBatch = fun
+}
+
 func (m *MockedChainCore) OnRequestAck(fun func(msg *messages.RequestAckMsgIn)) {
 	m.onRequestAck = fun
 }
@@ -16,6 +16,8 @@ type RequestParams struct {
 	EntryPoint iscp.Hname
 	Transfer   colored.Balances
 	Args       requestargs.RequestArgs
+	// PriorityFee is paid out of the Transfer to be processed sooner
+	PriorityFee uint64
 }
 
 type NewRequestTransactionParams struct {
@@ -33,6 +35,7 @@ func NewRequestTransaction(par NewRequestTransactionParams) (*ledgerstate.Transa
 			WithTarget(req.Contract).
 			WithEntryPoint(req.EntryPoint).
 			WithArgs(req.Args).
+			WithPriorityFee(req.PriorityFee).
 			Bytes()
 		var transfer colored.Balances
 		if len(req.Transfer) > 0 {
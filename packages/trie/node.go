@@ -0,0 +1,161 @@
+package trie
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// Arity is the number of children of a branch node. Each level of the trie consumes one nibble of the key hash
+const Arity = 16
+
+const (
+	nodeTypeLeaf   = byte(0)
+	nodeTypeBranch = byte(1)
+)
+
+// node is either a leaf, which commits to one key/value pair, or a branch, which commits to up to 16 children
+type node struct {
+	// leaf
+	key       []byte
+	valueHash hashing.HashValue
+	// branch
+	isBranch bool
+	children [Arity]hashing.HashValue
+}
+
+func newLeaf(key []byte, valueHash hashing.HashValue) *node {
+	return &node{
+		key:       key,
+		valueHash: valueHash,
+	}
+}
+
+func newBranch() *node {
+	return &node{isBranch: true}
+}
+
+func (n *node) hash() hashing.HashValue {
+	if n.isBranch {
+		return branchHash(&n.children)
+	}
+	return leafHash(n.key, n.valueHash)
+}
+
+// numChildren returns number of non-empty children and the index of the last of them
+func (n *node) numChildren() (int, int) {
+	count, last := 0, -1
+	for i := range n.children {
+		if n.children[i] != hashing.NilHash {
+			count++
+			last = i
+		}
+	}
+	return count, last
+}
+
+func (n *node) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = n.Write(&buf)
+	return buf.Bytes()
+}
+
+func (n *node) Write(w io.Writer) error {
+	if !n.isBranch {
+		if err := util.WriteByte(w, nodeTypeLeaf); err != nil {
+			return err
+		}
+		if err := util.WriteBytes16(w, n.key); err != nil {
+			return err
+		}
+		_, err := w.Write(n.valueHash[:])
+		return err
+	}
+	if err := util.WriteByte(w, nodeTypeBranch); err != nil {
+		return err
+	}
+	var flags uint16
+	for i := range n.children {
+		if n.children[i] != hashing.NilHash {
+			flags |= 1 << i
+		}
+	}
+	if err := util.WriteUint16(w, flags); err != nil {
+		return err
+	}
+	for i := range n.children {
+		if n.children[i] == hashing.NilHash {
+			continue
+		}
+		if _, err := w.Write(n.children[i][:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *node) Read(r io.Reader) error {
+	nodeType, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	switch nodeType {
+	case nodeTypeLeaf:
+		n.isBranch = false
+		if n.key, err = util.ReadBytes16(r); err != nil {
+			return err
+		}
+		return util.ReadHashValue(r, &n.valueHash)
+	case nodeTypeBranch:
+		n.isBranch = true
+		var flags uint16
+		if err := util.ReadUint16(r, &flags); err != nil {
+			return err
+		}
+		for i := range n.children {
+			if flags&(1<<i) == 0 {
+				continue
+			}
+			if err := util.ReadHashValue(r, &n.children[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return xerrors.Errorf("trie: unknown node type %d", nodeType)
+}
+
+func nodeFromBytes(data []byte) (*node, error) {
+	ret := &node{}
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, xerrors.Errorf("trie: can't decode node: %w", err)
+	}
+	return ret, nil
+}
+
+func leafHash(key []byte, valueHash hashing.HashValue) hashing.HashValue {
+	keyHash := hashing.HashData(key)
+	return hashing.HashData([]byte{nodeTypeLeaf}, keyHash[:], valueHash[:])
+}
+
+func branchHash(children *[Arity]hashing.HashValue) hashing.HashValue {
+	data := make([][]byte, 0, Arity+1)
+	data = append(data, []byte{nodeTypeBranch})
+	for i := range children {
+		data = append(data, children[i][:])
+	}
+	return hashing.HashData(data...)
+}
+
+// keyPath returns the path of the key in the trie: the nibbles of the hash of the key
+func keyPath(key []byte) []byte {
+	h := hashing.HashData(key)
+	ret := make([]byte, 0, 2*hashing.HashSize)
+	for _, b := range h {
+		ret = append(ret, b>>4, b&0x0f)
+	}
+	return ret
+}
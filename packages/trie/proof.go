@@ -0,0 +1,174 @@
+package trie
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// Proof is a proof of inclusion or of absence of the key in the trie.
+// It contains children of all branch nodes on the path of the key from the root,
+// and the terminal leaf if the path ends in a leaf. The terminal leaf may belong to another key,
+// which proves absence of the key
+type Proof struct {
+	Key      []byte
+	Branches [][Arity]hashing.HashValue
+	Leaf     *ProofLeaf
+}
+
+// ProofLeaf is a leaf of the trie included in the proof
+type ProofLeaf struct {
+	Key       []byte
+	ValueHash hashing.HashValue
+}
+
+// GetProof builds the proof for the key against the current root of the trie
+func (t *Trie) GetProof(key []byte) (*Proof, error) {
+	ret := &Proof{
+		Key:      key,
+		Branches: make([][Arity]hashing.HashValue, 0),
+	}
+	kpath := keyPath(key)
+	var path []byte
+	for {
+		n, err := t.getNode(path)
+		if err != nil {
+			return nil, err
+		}
+		if n == nil {
+			return ret, nil
+		}
+		if !n.isBranch {
+			ret.Leaf = &ProofLeaf{
+				Key:       n.key,
+				ValueHash: n.valueHash,
+			}
+			return ret, nil
+		}
+		ret.Branches = append(ret.Branches, n.children)
+		nibble := kpath[len(path)]
+		if n.children[nibble] == hashing.NilHash {
+			return ret, nil
+		}
+		path = childPath(path, nibble)
+	}
+}
+
+// Verify checks the proof against the root commitment. If value is nil, it checks the proof of absence of the key
+func (p *Proof) Verify(root hashing.HashValue, value []byte) error {
+	kpath := keyPath(p.Key)
+	if len(p.Branches) > len(kpath) {
+		return xerrors.New("trie: proof is too long")
+	}
+	var h hashing.HashValue
+	switch {
+	case value != nil:
+		if p.Leaf == nil || !bytes.Equal(p.Leaf.Key, p.Key) {
+			return xerrors.New("trie: proof does not contain the key")
+		}
+		if p.Leaf.ValueHash != hashing.HashData(value) {
+			return xerrors.New("trie: value does not match the proof")
+		}
+		h = leafHash(p.Leaf.Key, p.Leaf.ValueHash)
+	case p.Leaf != nil:
+		if bytes.Equal(p.Leaf.Key, p.Key) {
+			return xerrors.New("trie: proof of absence contains the key")
+		}
+		if !bytes.Equal(keyPath(p.Leaf.Key)[:len(p.Branches)], kpath[:len(p.Branches)]) {
+			return xerrors.New("trie: leaf in the proof of absence is not on the path of the key")
+		}
+		h = leafHash(p.Leaf.Key, p.Leaf.ValueHash)
+	}
+	for i := len(p.Branches) - 1; i >= 0; i-- {
+		if p.Branches[i][kpath[i]] != h {
+			return xerrors.Errorf("trie: proof is inconsistent at depth %d", i)
+		}
+		h = branchHash(&p.Branches[i])
+	}
+	if h != root {
+		return xerrors.New("trie: proof does not match the root")
+	}
+	return nil
+}
+
+func (p *Proof) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = p.Write(&buf)
+	return buf.Bytes()
+}
+
+func (p *Proof) Write(w io.Writer) error {
+	if err := util.WriteBytes16(w, p.Key); err != nil {
+		return err
+	}
+	if err := util.WriteByte(w, byte(len(p.Branches))); err != nil {
+		return err
+	}
+	for i := range p.Branches {
+		n := node{isBranch: true, children: p.Branches[i]}
+		if err := n.Write(w); err != nil {
+			return err
+		}
+	}
+	if err := util.WriteBoolByte(w, p.Leaf != nil); err != nil {
+		return err
+	}
+	if p.Leaf == nil {
+		return nil
+	}
+	n := newLeaf(p.Leaf.Key, p.Leaf.ValueHash)
+	return n.Write(w)
+}
+
+func (p *Proof) Read(r io.Reader) error {
+	var err error
+	if p.Key, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	size, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	p.Branches = make([][Arity]hashing.HashValue, size)
+	for i := range p.Branches {
+		var n node
+		if err := n.Read(r); err != nil {
+			return err
+		}
+		if !n.isBranch {
+			return xerrors.New("trie: branch node expected")
+		}
+		p.Branches[i] = n.children
+	}
+	var hasLeaf bool
+	if err := util.ReadBoolByte(r, &hasLeaf); err != nil {
+		return err
+	}
+	if !hasLeaf {
+		p.Leaf = nil
+		return nil
+	}
+	var n node
+	if err := n.Read(r); err != nil {
+		return err
+	}
+	if n.isBranch {
+		return xerrors.New("trie: leaf node expected")
+	}
+	p.Leaf = &ProofLeaf{
+		Key:       n.key,
+		ValueHash: n.valueHash,
+	}
+	return nil
+}
+
+func ProofFromBytes(data []byte) (*Proof, error) {
+	ret := &Proof{}
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, xerrors.Errorf("ProofFromBytes: %w", err)
+	}
+	return ret, nil
+}
@@ -0,0 +1,196 @@
+// Package trie implements an authenticated 16-ary Merkle Patricia trie over arbitrary keys.
+// The path of the key in the trie is the sequence of nibbles of the hash of the key.
+// Each leaf is placed at the shallowest position where its path is unique, so the root commitment
+// depends only on the set of key/value pairs and not on the order of updates
+package trie
+
+import (
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"golang.org/x/xerrors"
+)
+
+// NodeStore is a read-only access to the committed nodes of the trie. Nodes are keyed by their path
+type NodeStore interface {
+	Get(key kv.Key) ([]byte, error)
+}
+
+// Trie is a buffered view of the trie. Updates are collected in memory on top of the node store
+// and can be taken out with ForEachModifiedNode for persisting
+type Trie struct {
+	store NodeStore
+	// modified nodes by path. nil means the node was deleted
+	cache map[kv.Key]*node
+}
+
+// New creates a trie on top of the node store. Store may be nil, which means an empty trie
+func New(store NodeStore) *Trie {
+	return &Trie{
+		store: store,
+		cache: make(map[kv.Key]*node),
+	}
+}
+
+// RootHash returns the commitment to the whole content of the trie. Empty trie has hashing.NilHash
+func (t *Trie) RootHash() (hashing.HashValue, error) {
+	root, err := t.getNode(nil)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	if root == nil {
+		return hashing.NilHash, nil
+	}
+	return root.hash(), nil
+}
+
+// Update sets the value of the key. Nil value means deletion of the key
+func (t *Trie) Update(key, value []byte) error {
+	if value == nil {
+		_, err := t.delete(nil, key, keyPath(key))
+		return err
+	}
+	_, err := t.insert(nil, key, keyPath(key), hashing.HashData(value))
+	return err
+}
+
+// Delete removes the key from the trie. Deletion of non-existent key is NOP
+func (t *Trie) Delete(key []byte) error {
+	return t.Update(key, nil)
+}
+
+// ForEachModifiedNode iterates over nodes, which were modified since the trie was created.
+// data == nil means the node at the path must be deleted from the store
+func (t *Trie) ForEachModifiedNode(f func(path kv.Key, data []byte) bool) {
+	for path, n := range t.cache {
+		var data []byte
+		if n != nil {
+			data = n.Bytes()
+		}
+		if !f(path, data) {
+			return
+		}
+	}
+}
+
+// ClearCache forgets all modifications. It must be called only after modified nodes were persisted in the store
+func (t *Trie) ClearCache() {
+	t.cache = make(map[kv.Key]*node)
+}
+
+func (t *Trie) getNode(path []byte) (*node, error) {
+	if n, ok := t.cache[kv.Key(path)]; ok {
+		return n, nil
+	}
+	if t.store == nil {
+		return nil, nil
+	}
+	data, err := t.store.Get(kv.Key(path))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return nodeFromBytes(data)
+}
+
+func (t *Trie) putNode(path []byte, n *node) {
+	t.cache[kv.Key(path)] = n
+}
+
+func (t *Trie) deleteNode(path []byte) {
+	t.cache[kv.Key(path)] = nil
+}
+
+func childPath(path []byte, nibble byte) []byte {
+	ret := make([]byte, len(path)+1)
+	copy(ret, path)
+	ret[len(path)] = nibble
+	return ret
+}
+
+// insert puts the key/value into the subtree at the path and returns the new hash of the subtree
+func (t *Trie) insert(path, key, kpath []byte, valueHash hashing.HashValue) (hashing.HashValue, error) {
+	n, err := t.getNode(path)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	if n == nil {
+		n = newLeaf(key, valueHash)
+		t.putNode(path, n)
+		return n.hash(), nil
+	}
+	if !n.isBranch {
+		if string(n.key) == string(key) {
+			n = newLeaf(key, valueHash)
+			t.putNode(path, n)
+			return n.hash(), nil
+		}
+		// two keys share the prefix: the existing leaf is pushed one level down
+		if len(path) >= len(kpath) {
+			return hashing.NilHash, xerrors.New("trie: key hash collision")
+		}
+		nibble := keyPath(n.key)[len(path)]
+		t.putNode(childPath(path, nibble), n)
+		existing := n.hash()
+		n = newBranch()
+		n.children[nibble] = existing
+	} else {
+		// copy to not modify the cached node of another trie
+		cp := *n
+		n = &cp
+	}
+	nibble := kpath[len(path)]
+	if n.children[nibble], err = t.insert(childPath(path, nibble), key, kpath, valueHash); err != nil {
+		return hashing.NilHash, err
+	}
+	t.putNode(path, n)
+	return n.hash(), nil
+}
+
+// delete removes the key from the subtree at the path and returns the new hash of the subtree.
+// If only one leaf remains in the subtree of the branch, the leaf is pulled up in place of the branch
+func (t *Trie) delete(path, key, kpath []byte) (hashing.HashValue, error) {
+	n, err := t.getNode(path)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	if n == nil {
+		return hashing.NilHash, nil
+	}
+	if !n.isBranch {
+		if string(n.key) != string(key) {
+			return n.hash(), nil
+		}
+		t.deleteNode(path)
+		return hashing.NilHash, nil
+	}
+	cp := *n
+	n = &cp
+	nibble := kpath[len(path)]
+	if n.children[nibble], err = t.delete(childPath(path, nibble), key, kpath); err != nil {
+		return hashing.NilHash, err
+	}
+	count, last := n.numChildren()
+	switch {
+	case count == 0:
+		t.deleteNode(path)
+		return hashing.NilHash, nil
+	case count == 1:
+		lastPath := childPath(path, byte(last))
+		child, err := t.getNode(lastPath)
+		if err != nil {
+			return hashing.NilHash, err
+		}
+		if child == nil {
+			return hashing.NilHash, xerrors.Errorf("trie: inconsistency: missing node at path %x", lastPath)
+		}
+		if !child.isBranch {
+			t.deleteNode(lastPath)
+			t.putNode(path, child)
+			return child.hash(), nil
+		}
+	}
+	t.putNode(path, n)
+	return n.hash(), nil
+}
@@ -0,0 +1,131 @@
+package trie
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+func commit(t *testing.T, tr *Trie, store dict.Dict) {
+	tr.ForEachModifiedNode(func(path kv.Key, data []byte) bool {
+		if data == nil {
+			store.Del(path)
+		} else {
+			store.Set(path, data)
+		}
+		return true
+	})
+	tr.ClearCache()
+}
+
+func TestTrieBasic(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		tr := New(nil)
+		h, err := tr.RootHash()
+		require.NoError(t, err)
+		require.EqualValues(t, hashing.NilHash, h)
+	})
+	t.Run("set and delete", func(t *testing.T) {
+		tr := New(nil)
+		require.NoError(t, tr.Update([]byte("a"), []byte("1")))
+		h1, err := tr.RootHash()
+		require.NoError(t, err)
+		require.NotEqualValues(t, hashing.NilHash, h1)
+
+		require.NoError(t, tr.Update([]byte("b"), []byte("2")))
+		h2, err := tr.RootHash()
+		require.NoError(t, err)
+		require.NotEqualValues(t, h1, h2)
+
+		require.NoError(t, tr.Delete([]byte("b")))
+		h3, err := tr.RootHash()
+		require.NoError(t, err)
+		require.EqualValues(t, h1, h3)
+
+		require.NoError(t, tr.Delete([]byte("a")))
+		h4, err := tr.RootHash()
+		require.NoError(t, err)
+		require.EqualValues(t, hashing.NilHash, h4)
+	})
+}
+
+func TestTrieOrderIndependence(t *testing.T) {
+	const n = 300
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key%d", i))
+	}
+	tr1 := New(nil)
+	for _, k := range keys {
+		require.NoError(t, tr1.Update(k, k))
+	}
+	h1, err := tr1.RootHash()
+	require.NoError(t, err)
+
+	rand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	store := dict.New()
+	tr2 := New(store)
+	for i, k := range keys {
+		require.NoError(t, tr2.Update(k, k))
+		// junk which is removed later
+		require.NoError(t, tr2.Update([]byte(fmt.Sprintf("junk%d", i)), k))
+		if i%50 == 0 {
+			commit(t, tr2, store)
+		}
+	}
+	for i := range keys {
+		require.NoError(t, tr2.Delete([]byte(fmt.Sprintf("junk%d", i))))
+	}
+	commit(t, tr2, store)
+	h2, err := New(store).RootHash()
+	require.NoError(t, err)
+	require.EqualValues(t, h1, h2)
+}
+
+func TestTrieProof(t *testing.T) {
+	store := dict.New()
+	tr := New(store)
+	for i := 0; i < 100; i++ {
+		k := []byte(fmt.Sprintf("key%d", i))
+		require.NoError(t, tr.Update(k, []byte(fmt.Sprintf("value%d", i))))
+	}
+	commit(t, tr, store)
+	tr = New(store)
+	root, err := tr.RootHash()
+	require.NoError(t, err)
+
+	t.Run("inclusion", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			proof, err := tr.GetProof([]byte(fmt.Sprintf("key%d", i)))
+			require.NoError(t, err)
+			value := []byte(fmt.Sprintf("value%d", i))
+			require.NoError(t, proof.Verify(root, value))
+			require.Error(t, proof.Verify(root, []byte("wrong")))
+			require.Error(t, proof.Verify(root, nil))
+			require.Error(t, proof.Verify(hashing.RandomHash(nil), value))
+
+			proofBack, err := ProofFromBytes(proof.Bytes())
+			require.NoError(t, err)
+			require.EqualValues(t, proof, proofBack)
+			require.NoError(t, proofBack.Verify(root, value))
+		}
+	})
+	t.Run("absence", func(t *testing.T) {
+		for i := 100; i < 200; i++ {
+			proof, err := tr.GetProof([]byte(fmt.Sprintf("key%d", i)))
+			require.NoError(t, err)
+			require.NoError(t, proof.Verify(root, nil))
+			require.Error(t, proof.Verify(root, []byte("value")))
+		}
+	})
+	t.Run("empty trie", func(t *testing.T) {
+		proof, err := New(nil).GetProof([]byte("a"))
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(hashing.NilHash, nil))
+	})
+}
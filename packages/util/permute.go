@@ -50,6 +50,10 @@ func (perm *Permutation16) Shuffle(seed []byte) *Permutation16 {
 	return perm
 }
 
+func (perm *Permutation16) Size() uint16 {
+	return perm.size
+}
+
 func (perm *Permutation16) Current() uint16 {
 	return perm.permutation[perm.curSeqIndex]
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/assert"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/iscp/request"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
@@ -20,6 +22,7 @@ var Processor = Contract.Processor(initialize,
 	FuncWithdraw.WithHandler(withdraw),
 	FuncHarvest.WithHandler(harvest),
 	FuncGetAccountNonce.WithHandler(getAccountNonce),
+	FuncPayoutValidatorRewards.WithHandler(payoutValidatorRewards),
 )
 
 // initialize the init call
@@ -88,13 +91,15 @@ func withdraw(ctx iscp.Sandbox) (dict.Dict, error) {
 		// if the caller is on the same chain, do nothing
 		return nil, nil
 	}
+	// will be sending back to default entry point
+	a := assert.NewAssert(ctx.Log())
+	a.Require(!isEthereumSender(ctx), "accounts.withdraw: funds of an Ethereum account can't be withdrawn to L1")
+
 	tokensToWithdraw, ok := GetAccountBalances(state, ctx.Caller())
 	if !ok {
 		// empty balance, nothing to withdraw
 		return nil, nil
 	}
-	// will be sending back to default entry point
-	a := assert.NewAssert(ctx.Log())
 	// bring balances to the current account (owner's account). It is needed for subsequent Send call
 	a.Require(MoveBetweenAccounts(state, ctx.Caller(), commonaccount.Get(ctx.ChainID()), tokensToWithdraw),
 		"accounts.withdraw.inconsistency. failed to move tokens to owner's account")
@@ -112,6 +117,16 @@ func withdraw(ctx iscp.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
+// isEthereumSender returns true if the caller is the sender of the request signed with a secp256k1 key.
+// The address of such sender is derived from its Ethereum address, nobody can spend the funds sent to it on L1
+func isEthereumSender(ctx iscp.Sandbox) bool {
+	req, ok := ctx.Request().(*request.OffLedger)
+	if !ok || req.SignatureScheme() != request.SignatureSchemeSecp256k1 {
+		return false
+	}
+	return ctx.Caller().Equals(req.SenderAccount())
+}
+
 // owner of the chain moves all tokens from the common account to its own account
 // Params:
 //   ParamWithdrawAmount if do not exist or is 0 means withdraw all balance
@@ -152,6 +167,30 @@ func harvest(ctx iscp.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
+// payoutValidatorRewards moves the rewards of a committee member from the validator rewards account to the payout account.
+// The validator rewards account is the one of the governance contract, which keeps the ledger of the rewards,
+// so only the governance contract is allowed to call it
+// Params:
+// - ParamAgentID the payout account
+// - ParamRewards colored balances to move
+func payoutValidatorRewards(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	rewardsAccount := iscp.NewAgentID(ctx.ChainID().AsAddress(), coreutil.CoreContractGovernanceHname)
+	a.Require(ctx.Caller().Equals(rewardsAccount), "accounts.payoutValidatorRewards: not authorized")
+
+	state := ctx.State()
+	mustCheckLedger(state, "accounts.payoutValidatorRewards.begin")
+	defer mustCheckLedger(state, "accounts.payoutValidatorRewards.exit")
+
+	par := kvdecoder.New(ctx.Params(), ctx.Log())
+	targetAccount := commonaccount.AdjustIfNeeded(par.MustGetAgentID(ParamAgentID), ctx.ChainID())
+	rewards, err := colored.BalancesFromBytes(par.MustGetBytes(ParamRewards))
+	a.RequireNoError(err)
+	a.Require(MoveBetweenAccounts(state, rewardsAccount, targetAccount, rewards),
+		"accounts.payoutValidatorRewards.inconsistency: failed to move tokens to %s", targetAccount)
+	return nil, nil
+}
+
 func getAccountNonce(ctx iscp.SandboxView) (dict.Dict, error) {
 	par := kvdecoder.New(ctx.Params(), ctx.Log())
 	account := par.MustGetAgentID(ParamAgentID)
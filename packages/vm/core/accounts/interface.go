@@ -14,6 +14,8 @@ var (
 	FuncWithdraw        = coreutil.Func("withdraw")
 	FuncHarvest         = coreutil.Func("harvest")
 	FuncGetAccountNonce = coreutil.ViewFunc("getAccountNonce")
+	// FuncPayoutValidatorRewards is only called by the governance contract
+	FuncPayoutValidatorRewards = coreutil.Func("payoutValidatorRewards")
 )
 
 const (
@@ -21,4 +23,5 @@ const (
 	ParamWithdrawColor  = "c"
 	ParamWithdrawAmount = "m"
 	ParamAccountNonce   = "n"
+	ParamRewards        = "r"
 )
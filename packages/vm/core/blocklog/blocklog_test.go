@@ -3,10 +3,16 @@ package blocklog
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,10 +22,120 @@ func TestSerdeRequestLogRecord(t *testing.T) {
 	req := request.NewOffLedger(iscp.RandomChainID(), iscp.Hn("0"), iscp.Hn("0"), nil)
 	rec := &RequestReceipt{
 		Request: req,
-		Error:   "some log data",
+		Error:   iscp.NewRequestError(iscp.Hn("0"), iscp.RequestErrorContract, "some log data", "param"),
+		Fee:     10,
+		GasUsed: 1000,
 	}
 	forward := rec.Bytes()
 	back, err := RequestReceiptFromBytes(forward)
 	require.NoError(t, err)
 	require.EqualValues(t, forward, back.Bytes())
+	require.EqualValues(t, rec.Error, back.Error)
+	require.Nil(t, back.Result)
+
+	rec = &RequestReceipt{
+		Request:  req,
+		FeeColor: colored.Color{1},
+		Fee:      10,
+	}
+	rec.WithResult(dict.Dict{"a": []byte("b")})
+	forward = rec.Bytes()
+	back, err = RequestReceiptFromBytes(forward)
+	require.NoError(t, err)
+	require.EqualValues(t, forward, back.Bytes())
+	require.Nil(t, back.Error)
+	require.EqualValues(t, rec.Result, back.Result)
+	require.EqualValues(t, rec.FeeColor, back.FeeColor)
+
+	rec.WithResult(dict.Dict{"a": make([]byte, MaxResultSizeInReceipt)})
+	require.Nil(t, rec.Result)
+}
+
+func TestEventText(t *testing.T) {
+	event := iscp.NewEvent("transfer").
+		WithIndexedField("owner", "String", codec.EncodeString("alice")).
+		WithField("amount", "Uint64", codec.EncodeUint64(10))
+	// the same form as the events of the wasmlib EventEncoder: the timestamp in seconds goes first
+	require.Equal(t, "transfer|1634000000|alice|10", EventText(event, time.Unix(1634000000, 999)))
+}
+
+func TestPruneTypedEventIndexes(t *testing.T) {
+	partition := dict.New()
+	contract := iscp.Hn("contract")
+	transfer := func(owner string) *iscp.Event {
+		return iscp.NewEvent("transfer").WithIndexedField("owner", "String", codec.EncodeString(owner))
+	}
+	SaveNextBlockInfo(partition, &BlockInfo{})
+	for i, owner := range []string{"bob", "alice", "alice"} {
+		blockIndex := SaveNextBlockInfo(partition, &BlockInfo{TotalRequests: 1})
+		require.EqualValues(t, i+1, blockIndex)
+		require.NoError(t, SaveTypedEvent(partition, transfer(owner), NewEventLookupKey(blockIndex, 0, 0), contract, time.Now()))
+	}
+
+	err := PruneBlocks(partition, 3, 1, func(*RequestReceipt) bool { return false })
+	require.NoError(t, err)
+
+	// the index of bob is erased, the others keep the positions of the remaining keys
+	bob := eventTopicIndexName(contract, "transfer", 0, codec.EncodeString("bob"))
+	require.False(t, partition.MustHas(kv.Key(bob)))
+	require.False(t, collections.NewMap(partition, StateVarEventIndexHeads).MustHasAt([]byte(bob)))
+	head, err := typedEventIndexHead(partition, contractEventsIndexName(contract))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, head)
+	require.EqualValues(t, 3, collections.NewArray32(partition, contractEventsIndexName(contract)).MustLen())
+	head, err = typedEventIndexHead(partition, eventTopicIndexName(contract, "transfer", 0, codec.EncodeString("alice")))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, head)
+
+	// the cursor before the head starts from the first retained key
+	cursor := uint32(0)
+	events, next, err := getTypedEventsInternal(partition, &TypedEventsQuery{Contract: contract, ToBlock: 3, Cursor: &cursor})
+	require.NoError(t, err)
+	require.Nil(t, next)
+	require.Len(t, events, 1)
+	require.EqualValues(t, 3, events[0].Key.BlockIndex())
+
+	entries := collections.NewMap(partition, StateVarSmartContractEventsLookup).MustGetAt(contract.Bytes())
+	require.Len(t, entries, len(EventLookupKey{}))
+	require.True(t, collections.NewMap(partition, StateVarEventContracts).MustHasAt(contract.Bytes()))
+}
+
+func TestPruneKeptReceipts(t *testing.T) {
+	partition := dict.New()
+	SaveNextBlockInfo(partition, &BlockInfo{})
+	reqs := make([]*request.OffLedger, 3)
+	for i := range reqs {
+		blockIndex := SaveNextBlockInfo(partition, &BlockInfo{TotalRequests: 1})
+		reqs[i] = request.NewOffLedger(iscp.RandomChainID(), iscp.Hn("0"), iscp.Hn("0"), nil)
+		reqs[i].WithNonce(uint64(i))
+		require.NoError(t, SaveRequestLogRecord(partition, &RequestReceipt{Request: reqs[i]}, NewRequestLookupKey(blockIndex, 0)))
+	}
+	// the receipts with a nonce below the tolerance are not needed for the replay protection
+	tolerance := uint64(0)
+	keepReceipt := func(rec *RequestReceipt) bool { return rec.Request.(*request.OffLedger).Nonce() >= tolerance }
+	isProcessed := func(req iscp.Request) bool {
+		reqID := req.ID()
+		ret, err := isRequestProcessedInternal(partition, &reqID)
+		require.NoError(t, err)
+		return ret
+	}
+
+	require.NoError(t, PruneBlocks(partition, 3, 1, keepReceipt))
+	require.True(t, isProcessed(reqs[0]))
+	require.True(t, isProcessed(reqs[1]))
+	require.EqualValues(t, 2, collections.NewArray32(partition, StateVarKeptReceipts).MustLen())
+
+	tolerance = 1
+	require.NoError(t, PruneBlocks(partition, 4, 1, keepReceipt))
+	require.False(t, isProcessed(reqs[0]))
+	require.True(t, isProcessed(reqs[1]))
+	require.True(t, isProcessed(reqs[2]))
+
+	tolerance = 3
+	require.NoError(t, PruneBlocks(partition, 5, 2, keepReceipt))
+	for _, req := range reqs {
+		require.False(t, isProcessed(req))
+	}
+	require.False(t, partition.MustHas(collections.Array32SizeKey(StateVarKeptReceipts)))
+	require.False(t, partition.MustHas(StateVarKeptReceiptsHead))
 }
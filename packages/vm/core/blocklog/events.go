@@ -0,0 +1,442 @@
+package blocklog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/mr-tron/base58"
+	"golang.org/x/xerrors"
+)
+
+// DefaultEventsLimit is the default number of events returned by one call of FuncGetTypedEvents
+const DefaultEventsLimit = 100
+
+// MaxEventsLimit is the maximum number of events returned by one call of FuncGetTypedEvents
+const MaxEventsLimit = 1000
+
+// region EventRecord /////////////////////////////////////////////////////
+
+// EventRecord is the typed event stored in the blocklog together with its origin
+type EventRecord struct {
+	Key      EventLookupKey
+	Contract iscp.Hname
+	Event    *iscp.Event
+}
+
+func EventRecordFromBytes(data []byte) (*EventRecord, error) {
+	return EventRecordFromMarshalUtil(marshalutil.New(data))
+}
+
+func EventRecordFromMarshalUtil(mu *marshalutil.MarshalUtil) (*EventRecord, error) {
+	ret := &EventRecord{}
+	buf, err := mu.ReadBytes(len(ret.Key))
+	if err != nil {
+		return nil, err
+	}
+	copy(ret.Key[:], buf)
+	if ret.Contract, err = iscp.HnameFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Event, err = iscp.EventFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *EventRecord) Bytes() []byte {
+	return marshalutil.New().
+		WriteBytes(r.Key[:]).
+		Write(r.Contract).
+		WriteBytes(r.Event.Bytes()).
+		Bytes()
+}
+
+func (r *EventRecord) String() string {
+	return fmt.Sprintf("%s: %s%s", r.Contract, r.Event.Name, eventFieldsText(r.Event))
+}
+
+// EventText is the text form of the typed event emitted at the time: the name, the timestamp in seconds and
+// the values of the fields, separated by vertical bars. It is the form of the events of the wasmlib EventEncoder.
+// It is stored as the text event, so the typed events are also returned by the views which return text events
+func EventText(event *iscp.Event, timestamp time.Time) string {
+	return fmt.Sprintf("%s|%d%s", event.Name, timestamp.Unix(), eventFieldsText(event))
+}
+
+func eventFieldsText(event *iscp.Event) string {
+	ret := ""
+	for _, f := range event.Fields {
+		ret += "|" + EventFieldText(f)
+	}
+	return ret
+}
+
+// EventFieldText decodes the value of the field according to its type
+func EventFieldText(f *iscp.EventField) string {
+	var ret interface{ String() string }
+	var err error
+	switch f.Type {
+	case "Address":
+		if addr, err := codec.DecodeAddress(f.Value); err == nil {
+			return addr.Base58()
+		}
+	case "AgentID":
+		ret, err = codec.DecodeAgentID(f.Value)
+	case "Bool":
+		var v bool
+		if v, err = codec.DecodeBool(f.Value); err == nil {
+			return fmt.Sprintf("%v", v)
+		}
+	case "ChainID":
+		ret, err = codec.DecodeChainID(f.Value)
+	case "Color":
+		if col, err := codec.DecodeColor(f.Value); err == nil {
+			return col.String()
+		}
+	case "Hash":
+		ret, err = codec.DecodeHashValue(f.Value)
+	case "Hname":
+		ret, err = codec.DecodeHname(f.Value)
+	case "Int8", "Int16", "Int32", "Int64":
+		if v, ok := decodeEventInt(f); ok {
+			return fmt.Sprintf("%d", v)
+		}
+	case "Uint8", "Uint16", "Uint32", "Uint64":
+		if v, ok := decodeEventInt(f); ok {
+			return fmt.Sprintf("%d", uint64(v))
+		}
+	case "RequestID":
+		ret, err = codec.DecodeRequestID(f.Value)
+	case "String":
+		return strings.ReplaceAll(string(f.Value), "|", "/")
+	}
+	if err == nil && ret != nil {
+		return ret.String()
+	}
+	return base58.Encode(f.Value)
+}
+
+func decodeEventInt(f *iscp.EventField) (int64, bool) {
+	var ret int64
+	var err error
+	switch f.Type {
+	case "Int8":
+		var v int8
+		v, err = codec.DecodeInt8(f.Value)
+		ret = int64(v)
+	case "Uint8":
+		var v uint8
+		v, err = codec.DecodeUint8(f.Value)
+		ret = int64(v)
+	case "Int16":
+		var v int16
+		v, err = codec.DecodeInt16(f.Value)
+		ret = int64(v)
+	case "Uint16":
+		var v uint16
+		v, err = codec.DecodeUint16(f.Value)
+		ret = int64(v)
+	case "Int32":
+		var v int32
+		v, err = codec.DecodeInt32(f.Value)
+		ret = int64(v)
+	case "Uint32":
+		var v uint32
+		v, err = codec.DecodeUint32(f.Value)
+		ret = int64(v)
+	case "Int64":
+		ret, err = codec.DecodeInt64(f.Value)
+	case "Uint64":
+		var v uint64
+		v, err = codec.DecodeUint64(f.Value)
+		ret = int64(v)
+	}
+	return ret, err == nil
+}
+
+// endregion ///////////////////////////////////////////////////////////
+
+// region indexes ///////////////////////////////////////////////////////
+
+// the indexes of the typed events are arrays of EventLookupKey in the order of emission.
+// Each index is an array with a fixed-size name under its own prefix
+
+func contractEventsIndexName(contract iscp.Hname) string {
+	return StateVarContractEventsIndex + string(contract.Bytes())
+}
+
+func eventNameIndexName(contract iscp.Hname, eventName string) string {
+	return StateVarEventNameIndex + string(contract.Bytes()) + string(iscp.Hn(eventName).Bytes())
+}
+
+func eventTopicIndexName(contract iscp.Hname, eventName string, topicIndex int, topic []byte) string {
+	h := hashing.HashData(topic)
+	return StateVarEventTopicIndex + string(contract.Bytes()) + string(iscp.Hn(eventName).Bytes()) +
+		string([]byte{byte(topicIndex)}) + string(h[:])
+}
+
+// typedEventIndexNames returns the names of all indexes which contain the event
+func typedEventIndexNames(contract iscp.Hname, event *iscp.Event) []string {
+	ret := []string{
+		contractEventsIndexName(contract),
+		eventNameIndexName(contract, event.Name),
+	}
+	for i, topic := range event.Topics() {
+		ret = append(ret, eventTopicIndexName(contract, event.Name, i, topic))
+	}
+	return ret
+}
+
+// typedEventIndexHead returns the position of the first key kept in the index after pruning
+func typedEventIndexHead(partition kv.KVStoreReader, name string) (uint32, error) {
+	return codec.DecodeUint32(collections.NewMapReadOnly(partition, StateVarEventIndexHeads).MustGetAt([]byte(name)), 0)
+}
+
+// trimTypedEventIndex deletes the keys of the events up to the block from the beginning of the index.
+// The positions of the remaining keys, used as cursors, don't change. The index which becomes empty is erased
+func trimTypedEventIndex(partition kv.KVStore, name string, blockIndex uint32) error {
+	head, err := typedEventIndexHead(partition, name)
+	if err != nil {
+		return err
+	}
+	index := collections.NewArray32(partition, name)
+	n, err := index.Len()
+	if err != nil {
+		return err
+	}
+	newHead := head
+	for ; newHead < n; newHead++ {
+		data, err := index.GetAt(newHead)
+		if err != nil {
+			return err
+		}
+		var key EventLookupKey
+		copy(key[:], data)
+		if key.BlockIndex() > blockIndex {
+			break
+		}
+	}
+	if newHead == head {
+		return nil
+	}
+	for _, k := range collections.Array32RangeKeys(name, n, head, newHead) {
+		partition.Del(k)
+	}
+	heads := collections.NewMap(partition, StateVarEventIndexHeads)
+	if newHead < n {
+		return heads.SetAt([]byte(name), codec.EncodeUint32(newHead))
+	}
+	// all keys are deleted, the index starts again from the position 0
+	partition.Del(collections.Array32SizeKey(name))
+	return heads.DelAt([]byte(name))
+}
+
+// SaveTypedEvent stores the typed event, its text form and the indexes by contract, event name and topics
+func SaveTypedEvent(partition kv.KVStore, event *iscp.Event, key EventLookupKey, contract iscp.Hname, timestamp time.Time) error {
+	if err := SaveEvent(partition, EventText(event, timestamp), key, contract); err != nil {
+		return err
+	}
+	rec := &EventRecord{Key: key, Contract: contract, Event: event}
+	if err := collections.NewMap(partition, StateVarTypedEvents).SetAt(key.Bytes(), rec.Bytes()); err != nil {
+		return xerrors.Errorf("SaveTypedEvent: %w", err)
+	}
+	for _, name := range typedEventIndexNames(contract, event) {
+		if err := collections.NewArray32(partition, name).Push(key.Bytes()); err != nil {
+			return xerrors.Errorf("SaveTypedEvent: %w", err)
+		}
+	}
+	return nil
+}
+
+// TypedEventsQuery selects the typed events of the contract
+type TypedEventsQuery struct {
+	Contract iscp.Hname
+	// EventName is optional, empty means all events of the contract
+	EventName string
+	// Topics are the values of the indexed fields by their position. Nil means any value.
+	// Topics require the EventName
+	Topics    [][]byte
+	FromBlock uint32
+	// ToBlock is the last block of the range, 0 means the latest block
+	ToBlock uint32
+	// Cursor is the position in the index to continue from, returned by the previous query
+	Cursor *uint32
+	Limit  uint32
+}
+
+// Params returns the parameters of FuncGetTypedEvents for the query
+func (q *TypedEventsQuery) Params() dict.Dict {
+	ret := dict.New()
+	ret.Set(ParamContractHname, codec.EncodeHname(q.Contract))
+	if q.EventName != "" {
+		ret.Set(ParamEventName, codec.EncodeString(q.EventName))
+	}
+	for i, t := range q.Topics {
+		if t != nil {
+			ret.Set(kv.Key(ParamTopic(i)), t)
+		}
+	}
+	ret.Set(ParamFromBlock, codec.EncodeUint32(q.FromBlock))
+	if q.ToBlock != 0 {
+		ret.Set(ParamToBlock, codec.EncodeUint32(q.ToBlock))
+	}
+	if q.Cursor != nil {
+		ret.Set(ParamCursor, codec.EncodeUint32(*q.Cursor))
+	}
+	if q.Limit != 0 {
+		ret.Set(ParamLimit, codec.EncodeUint32(q.Limit))
+	}
+	return ret
+}
+
+// DecodeTypedEvents decodes the result of FuncGetTypedEvents: the events and the cursor of the next page
+func DecodeTypedEvents(res dict.Dict) ([]*EventRecord, *uint32, error) {
+	arr := collections.NewArray32ReadOnly(res, ParamEvent)
+	n, err := arr.Len()
+	if err != nil {
+		return nil, nil, err
+	}
+	ret := make([]*EventRecord, n)
+	for i := range ret {
+		if ret[i], err = EventRecordFromBytes(arr.MustGetAt(uint32(i))); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !res.MustHas(ParamCursor) {
+		return ret, nil, nil
+	}
+	cursor, err := codec.DecodeUint32(res.MustGet(ParamCursor))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ret, &cursor, nil
+}
+
+func (q *TypedEventsQuery) indexName() (string, error) {
+	if q.EventName == "" {
+		for _, t := range q.Topics {
+			if t != nil {
+				return "", xerrors.New("the event name is required to filter by topics")
+			}
+		}
+		return contractEventsIndexName(q.Contract), nil
+	}
+	if len(q.Topics) > iscp.MaxEventTopics {
+		return "", xerrors.Errorf("too many topics, maximum is %d", iscp.MaxEventTopics)
+	}
+	for i, t := range q.Topics {
+		if t != nil {
+			return eventTopicIndexName(q.Contract, q.EventName, i, t), nil
+		}
+	}
+	return eventNameIndexName(q.Contract, q.EventName), nil
+}
+
+func (q *TypedEventsQuery) matches(rec *EventRecord) bool {
+	topics := rec.Event.Topics()
+	for i, t := range q.Topics {
+		if t == nil {
+			continue
+		}
+		if i >= len(topics) || string(topics[i]) != string(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// getTypedEventsInternal returns the events selected by the query and the cursor of the next page,
+// or nil if there are no more events
+func getTypedEventsInternal(partition kv.KVStoreReader, q *TypedEventsQuery) ([]*EventRecord, *uint32, error) {
+	name, err := q.indexName()
+	if err != nil {
+		return nil, nil, err
+	}
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultEventsLimit
+	}
+	if limit > MaxEventsLimit {
+		limit = MaxEventsLimit
+	}
+	index := collections.NewArray32ReadOnly(partition, name)
+	n, err := index.Len()
+	if err != nil {
+		return nil, nil, err
+	}
+	keyAt := func(i uint32) (EventLookupKey, error) {
+		var key EventLookupKey
+		data, err := index.GetAt(i)
+		if err != nil {
+			return key, err
+		}
+		copy(key[:], data)
+		return key, nil
+	}
+	// the keys before the head are pruned
+	head, err := typedEventIndexHead(partition, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	start := head
+	if q.Cursor != nil {
+		if *q.Cursor > head {
+			start = *q.Cursor
+		}
+	} else {
+		// the keys are in the order of blocks
+		var searchErr error
+		start = head + uint32(sort.Search(int(n-head), func(i int) bool {
+			key, err := keyAt(head + uint32(i))
+			if err != nil {
+				searchErr = err
+				return true
+			}
+			return key.BlockIndex() >= q.FromBlock
+		}))
+		if searchErr != nil {
+			return nil, nil, searchErr
+		}
+	}
+	records := collections.NewMapReadOnly(partition, StateVarTypedEvents)
+	ret := make([]*EventRecord, 0)
+	for i := start; i < n; i++ {
+		if uint32(len(ret)) >= limit {
+			next := i
+			return ret, &next, nil
+		}
+		key, err := keyAt(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if key.BlockIndex() > q.ToBlock {
+			break
+		}
+		data, err := records.GetAt(key.Bytes())
+		if err != nil {
+			return nil, nil, err
+		}
+		if data == nil {
+			// pruned, the indexes keep only the keys
+			continue
+		}
+		rec, err := EventRecordFromBytes(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if q.matches(rec) {
+			ret = append(ret, rec)
+		}
+	}
+	return ret, nil, nil
+}
+
+// endregion ///////////////////////////////////////////////////////////
@@ -2,11 +2,14 @@ package blocklog
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/state"
+	"golang.org/x/xerrors"
 )
 
 // GetRequestIDsForLastBlock reads blocklog from chain state and returns request IDs settled in specific block
@@ -39,3 +42,118 @@ func IsRequestProcessed(stateReader kv.KVStoreReader, reqid *iscp.RequestID) (bo
 	partition := subrealm.NewReadOnly(stateReader, kv.Key(Contract.Hname().Bytes()))
 	return isRequestProcessedInternal(partition, reqid)
 }
+
+// BlockContents is what the blocklog keeps of the block
+type BlockContents struct {
+	Info *BlockInfo
+	// ControlAddresses is set if the control addresses of the chain changed in the block, i.e. the chain was rotated
+	ControlAddresses *ControlAddresses
+	// Receipts of the requests in the order of processing. The receipts of the pruned blocks are missing
+	Receipts []*RequestReceipt
+	// Events in the order of emission
+	Events []*BlockEvent
+}
+
+// BlockEvent is the event emitted in the block. Typed is nil for the text events
+type BlockEvent struct {
+	Key      EventLookupKey
+	Contract iscp.Hname
+	Text     string
+	Typed    *iscp.Event
+}
+
+// GetBlockContents reads the block info, the receipts and the events of the block from the chain state.
+// Returns nil if the block does not exist
+func GetBlockContents(stateReader kv.KVStoreReader, blockIndex uint32) (*BlockContents, error) {
+	partition := subrealm.NewReadOnly(stateReader, kv.Key(Contract.Hname().Bytes()))
+	blockInfo, err := getRequestLogRecordsForBlock(partition, blockIndex)
+	if err != nil || blockInfo == nil {
+		return nil, err
+	}
+	ret := &BlockContents{
+		Info:     blockInfo,
+		Receipts: make([]*RequestReceipt, 0, blockInfo.TotalRequests),
+		Events:   make([]*BlockEvent, 0),
+	}
+	if ret.ControlAddresses, err = getControlAddressesChangedIn(partition, blockIndex); err != nil {
+		return nil, err
+	}
+	events := collections.NewMapReadOnly(partition, StateVarRequestEvents)
+	typedEvents := collections.NewMapReadOnly(partition, StateVarTypedEvents)
+	for reqIdx := uint16(0); reqIdx < blockInfo.TotalRequests; reqIdx++ {
+		if data, found := getRequestRecordDataByRef(partition, blockIndex, reqIdx); found {
+			rec, err := RequestReceiptFromBytes(data)
+			if err != nil {
+				return nil, err
+			}
+			ret.Receipts = append(ret.Receipts, rec.WithBlockData(blockIndex, reqIdx))
+		}
+		for eventIdx := uint16(0); ; eventIdx++ {
+			key := NewEventLookupKey(blockIndex, reqIdx, eventIdx)
+			msg, err := events.GetAt(key.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			if msg == nil {
+				break
+			}
+			ev, err := blockEventFromText(key, string(msg))
+			if err != nil {
+				return nil, err
+			}
+			data, err := typedEvents.GetAt(key.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			if data != nil {
+				rec, err := EventRecordFromBytes(data)
+				if err != nil {
+					return nil, err
+				}
+				ev.Typed = rec.Event
+			}
+			ret.Events = append(ret.Events, ev)
+		}
+	}
+	return ret, nil
+}
+
+// blockEventFromText parses the stored text of the event, which starts with the hname of the contract
+func blockEventFromText(key EventLookupKey, text string) (*BlockEvent, error) {
+	parts := strings.SplitN(text, ": ", 2)
+	if len(parts) != 2 {
+		return nil, xerrors.Errorf("wrong format of the event %s", text)
+	}
+	contract, err := iscp.HnameFromString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	return &BlockEvent{Key: key, Contract: contract, Text: parts[1]}, nil
+}
+
+// getControlAddressesChangedIn returns the control addresses set in the block or nil if they did not change.
+// The first record is the origin of the chain, not a change
+func getControlAddressesChangedIn(partition kv.KVStoreReader, blockIndex uint32) (*ControlAddresses, error) {
+	registry := collections.NewArray32ReadOnly(partition, StateVarControlAddresses)
+	l, err := registry.Len()
+	if err != nil {
+		return nil, err
+	}
+	for i := l; i > 1; i-- {
+		data, err := registry.GetAt(i - 1)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := ControlAddressesFromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		if rec.SinceBlockIndex == blockIndex {
+			return rec, nil
+		}
+		if rec.SinceBlockIndex < blockIndex {
+			break
+		}
+	}
+	return nil, nil
+}
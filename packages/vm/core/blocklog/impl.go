@@ -6,6 +6,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/assert"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -24,6 +25,8 @@ var Processor = Contract.Processor(initialize,
 	FuncGetEventsForRequest.WithHandler(viewGetEventsForRequest),
 	FuncGetEventsForBlock.WithHandler(viewGetEventsForBlock),
 	FuncGetEventsForContract.WithHandler(viewGetEventsForContract),
+	FuncGetTypedEvents.WithHandler(viewGetTypedEvents),
+	FuncGetRandomBeacon.WithHandler(viewGetRandomBeacon),
 )
 
 func initialize(ctx iscp.Sandbox) (dict.Dict, error) {
@@ -234,3 +237,79 @@ func viewGetEventsForContract(ctx iscp.SandboxView) (dict.Dict, error) {
 	}
 	return ret, nil
 }
+
+// viewGetTypedEvents returns a page of typed events of the smart contract, in the order of emission.
+// params:
+// ParamContractHname - hname of the contract
+// ParamEventName - optional name of the event
+// ParamTopic(i) - optional value of the i-th indexed field. Requires ParamEventName
+// ParamFromBlock - defaults to 0
+// ParamToBlock - defaults to latest block
+// ParamCursor - optional cursor returned by the previous call, it replaces ParamFromBlock
+// ParamLimit - maximum number of events to return, defaults to DefaultEventsLimit
+// returns:
+// ParamEvent - array of EventRecord
+// ParamCursor - cursor of the next page, absent if there are no more events
+func viewGetTypedEvents(ctx iscp.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	q := &TypedEventsQuery{
+		Contract:  params.MustGetHname(ParamContractHname),
+		EventName: params.MustGetString(ParamEventName, ""),
+		FromBlock: params.MustGetUint32(ParamFromBlock, 0),
+		ToBlock:   params.MustGetUint32(ParamToBlock, math.MaxUint32),
+		Limit:     params.MustGetUint32(ParamLimit, DefaultEventsLimit),
+	}
+	for i := 0; i < iscp.MaxEventTopics; i++ {
+		if topic := params.MustGetBytes(kv.Key(ParamTopic(i)), nil); topic != nil {
+			for len(q.Topics) < i {
+				q.Topics = append(q.Topics, nil)
+			}
+			q.Topics = append(q.Topics, topic)
+		}
+	}
+	if ctx.Params().MustHas(ParamCursor) {
+		cursor := params.MustGetUint32(ParamCursor)
+		q.Cursor = &cursor
+	}
+	events, next, err := getTypedEventsInternal(ctx.State(), q)
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	arr := collections.NewArray32(ret, ParamEvent)
+	for _, rec := range events {
+		arr.MustPush(rec.Bytes())
+	}
+	if next != nil {
+		ret.Set(ParamCursor, codec.EncodeUint32(*next))
+	}
+	return ret, nil
+}
+
+// viewGetRandomBeacon returns the random beacon of the block together with the state controller address
+// which signed it, so the caller can check the beacon with VerifyRandomBeacon
+// params:
+// ParamBlockIndex - defaults to latest block
+// returns:
+// ParamBlockIndex - index of the block
+// ParamRandomBeacon - serialized bls.SignatureWithPublicKey
+// ParamRandomSeed - the seed derived from the beacon
+// ParamStateControllerAddress - the state address of the committee which produced the beacon
+func viewGetRandomBeacon(ctx iscp.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	blockIndex := params.MustGetUint32(ParamBlockIndex, collections.NewArray32ReadOnly(ctx.State(), StateVarBlockRegistry).MustLen()-1)
+	beacon := GetRandomBeacon(ctx.State(), blockIndex)
+	if beacon == nil {
+		return nil, xerrors.Errorf("no random beacon for the block #%d", blockIndex)
+	}
+	stateAddress, err := getStateAddressOfBlock(ctx.State(), blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	ret.Set(ParamBlockIndex, codec.EncodeUint32(blockIndex))
+	ret.Set(ParamRandomBeacon, beacon)
+	ret.Set(ParamRandomSeed, codec.EncodeHashValue(RandomSeed(beacon)))
+	ret.Set(ParamStateControllerAddress, codec.EncodeAddress(stateAddress))
+	return ret, nil
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/util"
 )
 
@@ -27,6 +29,16 @@ const (
 	StateVarRequestReceipts           = "r"
 	StateVarRequestEvents             = "e"
 	StateVarSmartContractEventsLookup = "e"
+	StateVarTypedEvents               = "y"
+	StateVarContractEventsIndex       = "k"
+	StateVarEventNameIndex            = "m"
+	StateVarEventTopicIndex           = "o"
+	StateVarEventIndexHeads           = "h"
+	StateVarEventContracts            = "t"
+	StateVarPrunedUpTo                = "p"
+	StateVarKeptReceipts              = "q"
+	StateVarKeptReceiptsHead          = "u"
+	StateVarRandomBeacons             = "s"
 )
 
 var (
@@ -40,6 +52,8 @@ var (
 	FuncGetEventsForRequest        = coreutil.ViewFunc("getEventsForRequest")
 	FuncGetEventsForBlock          = coreutil.ViewFunc("getEventsForBlock")
 	FuncGetEventsForContract       = coreutil.ViewFunc("getEventsForContract")
+	FuncGetTypedEvents             = coreutil.ViewFunc("getTypedEvents")
+	FuncGetRandomBeacon            = coreutil.ViewFunc("getRandomBeacon")
 )
 
 const (
@@ -56,8 +70,18 @@ const (
 	ParamRequestRecord          = "d"
 	ParamEvent                  = "e"
 	ParamStateControllerAddress = "s"
+	ParamEventName              = "en"
+	ParamCursor                 = "cu"
+	ParamLimit                  = "lm"
+	ParamRandomBeacon           = "rb"
+	ParamRandomSeed             = "rs"
 )
 
+// ParamTopic is the parameter with the value of the i-th indexed field of the event
+func ParamTopic(i int) string {
+	return fmt.Sprintf("tp%d", i)
+}
+
 // region BlockInfo //////////////////////////////////////////////////////////////
 
 type BlockInfo struct {
@@ -260,10 +284,21 @@ func EventLookupKeyFromBytes(r io.Reader) (*EventLookupKey, error) {
 
 // region RequestLogReqcord /////////////////////////////////////////////////////
 
+// MaxResultSizeInReceipt is the maximum size of the serialized result of the request stored in the receipt.
+// Bigger results are not stored
+const MaxResultSizeInReceipt = 1024
+
 // RequestReceipt represents log record of processed request on the chain
 type RequestReceipt struct {
 	Request iscp.Request
-	Error   string
+	// Error is nil if the request was successful
+	Error *iscp.RequestError
+	// Result returned by the entry point. Nil if the request failed or the result is bigger than MaxResultSizeInReceipt
+	Result   dict.Dict
+	FeeColor colored.Color
+	// Fee is the total fee charged from the request
+	Fee     uint64
+	GasUsed uint64
 	// not persistent
 	BlockIndex   uint32
 	RequestIndex uint16
@@ -279,26 +314,62 @@ func RequestReceiptFromMarshalutil(mu *marshalutil.MarshalUtil) (*RequestReceipt
 	if ret.Request, err = request.FromMarshalUtil(mu); err != nil {
 		return nil, err
 	}
-	var size uint16
-	if size, err = mu.ReadUint16(); err != nil {
+	hasError, err := mu.ReadBool()
+	if err != nil {
 		return nil, err
 	}
-	strBytes, err := mu.ReadBytes(int(size))
+	if hasError {
+		if ret.Error, err = iscp.RequestErrorFromMarshalUtil(mu); err != nil {
+			return nil, err
+		}
+	}
+	hasResult, err := mu.ReadBool()
 	if err != nil {
 		return nil, err
 	}
-	ret.Error = string(strBytes)
+	if hasResult {
+		if ret.Result, err = dict.FromMarshalUtil(mu); err != nil {
+			return nil, err
+		}
+	}
+	if ret.FeeColor, err = colored.ColorFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Fee, err = mu.ReadUint64(); err != nil {
+		return nil, err
+	}
+	if ret.GasUsed, err = mu.ReadUint64(); err != nil {
+		return nil, err
+	}
 	return ret, nil
 }
 
 func (r *RequestReceipt) Bytes() []byte {
 	mu := marshalutil.New()
 	mu.WriteBytes(r.Request.Bytes()).
-		WriteUint16(uint16(len(r.Error))).
-		WriteBytes([]byte(r.Error))
+		WriteBool(r.Error != nil)
+	if r.Error != nil {
+		mu.WriteBytes(r.Error.Bytes())
+	}
+	mu.WriteBool(r.Result != nil)
+	if r.Result != nil {
+		mu.WriteBytes(r.Result.Bytes())
+	}
+	mu.WriteBytes(r.FeeColor.Bytes()).
+		WriteUint64(r.Fee).
+		WriteUint64(r.GasUsed)
 	return mu.Bytes()
 }
 
+// WithResult sets the result of the request, unless it is bigger than MaxResultSizeInReceipt
+func (r *RequestReceipt) WithResult(result dict.Dict) *RequestReceipt {
+	r.Result = nil
+	if result != nil && len(result.Bytes()) <= MaxResultSizeInReceipt {
+		r.Result = result.Clone()
+	}
+	return r
+}
+
 func (r *RequestReceipt) WithBlockData(blockIndex uint32, requestIndex uint16) *RequestReceipt {
 	r.BlockIndex = blockIndex
 	r.RequestIndex = requestIndex
@@ -306,10 +377,11 @@ func (r *RequestReceipt) WithBlockData(blockIndex uint32, requestIndex uint16) *
 }
 
 func (r *RequestReceipt) String() string {
-	if len(r.Error) > 0 {
-		return fmt.Sprintf("%s\n Error: '%s'", r.Request.String(), r.Error)
+	ret := fmt.Sprintf("%s\n Fee: %d %s\n Gas used: %d", r.Request.String(), r.Fee, r.FeeColor.String(), r.GasUsed)
+	if r.Error != nil {
+		ret += fmt.Sprintf("\n Error: %s", r.Error.String())
 	}
-	return r.Request.String()
+	return ret
 }
 
 func (r *RequestReceipt) Short() string {
@@ -318,8 +390,8 @@ func (r *RequestReceipt) Short() string {
 		prefix = "api"
 	}
 	ret := fmt.Sprintf("%s/%s", prefix, r.Request.ID())
-	if len(r.Error) > 0 {
-		ret += ": '" + r.Error + "'"
+	if r.Error != nil {
+		ret += ": '" + r.Error.Message + "'"
 	}
 	return ret
 }
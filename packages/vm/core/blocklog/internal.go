@@ -99,6 +99,17 @@ func SaveEvent(partition kv.KVStore, msg string, key EventLookupKey, contract is
 	if err != nil {
 		return xerrors.Errorf("SaveRequestLogRecord: %w", err)
 	}
+	// the contracts with events are registered, so the pruning finds their lookup lists
+	contracts := collections.NewMap(partition, StateVarEventContracts)
+	registered, err := contracts.HasAt(contract.Bytes())
+	if err != nil {
+		return xerrors.Errorf("SaveRequestLogRecord: %w", err)
+	}
+	if !registered {
+		if err := contracts.SetAt(contract.Bytes(), []byte{1}); err != nil {
+			return xerrors.Errorf("SaveRequestLogRecord: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -209,6 +220,10 @@ func getSmartContractEventsInternal(partition kv.KVStoreReader, contract iscp.Hn
 		if err != nil {
 			return nil, xerrors.Errorf("getSmartContractEventsIntern unable to get event by key. %v", err)
 		}
+		if event == nil {
+			// pruned
+			continue
+		}
 		ret = append(ret, string(event))
 	}
 }
@@ -261,12 +276,11 @@ func getRequestLogRecordsForBlockBin(partition kv.KVStoreReader, blockIndex uint
 	if err != nil || blockInfo == nil {
 		return nil, false, err
 	}
-	ret := make([][]byte, blockInfo.TotalRequests)
-	found := false
+	ret := make([][]byte, 0, blockInfo.TotalRequests)
 	for reqIdx := uint16(0); reqIdx < blockInfo.TotalRequests; reqIdx++ {
-		ret[reqIdx], found = getRequestRecordDataByRef(partition, blockIndex, reqIdx)
-		if !found {
-			panic("getRequestLogRecordsForBlockBin: inconsistency: request record not found")
+		// the records of the pruned blocks are missing, except the ones kept for the replay protection
+		if data, found := getRequestRecordDataByRef(partition, blockIndex, reqIdx); found {
+			ret = append(ret, data)
 		}
 	}
 	return ret, true, nil
@@ -0,0 +1,277 @@
+package blocklog
+
+import (
+	"sort"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"golang.org/x/xerrors"
+)
+
+// OffLedgerNonceStrictOrderTolerance is the approximate assumed maximum number of requests in the batch.
+// The blocklog keeps at least this number of last processed off-ledger requests of each address,
+// so the replayed requests are recognized
+const OffLedgerNonceStrictOrderTolerance = 10000
+
+// MaxBlocksPrunedPerBlock limits the work of pruning in one block. When the pruning policy changes,
+// the blocklog catches up with it gradually
+const MaxBlocksPrunedPerBlock = 10
+
+// MaxKeptReceiptsCheckedPerBlock limits the number of receipts kept for the replay protection
+// which are checked again in one block
+const MaxKeptReceiptsCheckedPerBlock = 100
+
+// PruneBlocks deletes receipts and events of the blocks which are older than receiptsToKeep blocks
+// before blockIndex. The blocks are pruned in order, starting from the oldest one not pruned yet.
+// keepReceipt is called for each receipt and tells if it must be kept for the replay protection.
+// The kept receipts are queued in the order of blocks and checked again in the next calls, until
+// keepReceipt releases them. The function runs in the VM, it must be deterministic
+func PruneBlocks(partition kv.KVStore, blockIndex, receiptsToKeep uint32, keepReceipt func(rec *RequestReceipt) bool) error {
+	if receiptsToKeep == 0 || blockIndex <= receiptsToKeep {
+		return nil
+	}
+	if err := pruneKeptReceipts(partition, keepReceipt); err != nil {
+		return xerrors.Errorf("PruneBlocks: %w", err)
+	}
+	next, err := codec.DecodeUint32(partition.MustGet(StateVarPrunedUpTo), 1)
+	if err != nil {
+		return xerrors.Errorf("PruneBlocks: %w", err)
+	}
+	pruneTo := blockIndex - receiptsToKeep
+	for n := 0; next <= pruneTo && n < MaxBlocksPrunedPerBlock; n++ {
+		if err := pruneBlock(partition, next, keepReceipt); err != nil {
+			return xerrors.Errorf("PruneBlocks: block #%d: %w", next, err)
+		}
+		next++
+	}
+	partition.Set(StateVarPrunedUpTo, codec.EncodeUint32(next))
+	return nil
+}
+
+// GetFirstUnprunedBlock returns the index of the oldest block with receipts and events
+func GetFirstUnprunedBlock(partition kv.KVStoreReader) (uint32, error) {
+	return codec.DecodeUint32(partition.MustGet(StateVarPrunedUpTo), 1)
+}
+
+func pruneBlock(partition kv.KVStore, blockIndex uint32, keepReceipt func(rec *RequestReceipt) bool) error {
+	blockInfo, err := getRequestLogRecordsForBlock(partition, blockIndex)
+	if err != nil {
+		return err
+	}
+	if blockInfo == nil {
+		return nil
+	}
+	receipts := collections.NewMap(partition, StateVarRequestReceipts)
+	events := collections.NewMap(partition, StateVarRequestEvents)
+	typedEvents := collections.NewMap(partition, StateVarTypedEvents)
+	indexes := make(map[string]struct{})
+	for reqIdx := uint16(0); reqIdx < blockInfo.TotalRequests; reqIdx++ {
+		for eventIdx := uint16(0); ; eventIdx++ {
+			key := NewEventLookupKey(blockIndex, reqIdx, eventIdx)
+			found, err := events.HasAt(key.Bytes())
+			if err != nil {
+				return err
+			}
+			if !found {
+				break
+			}
+			if err := events.DelAt(key.Bytes()); err != nil {
+				return err
+			}
+			data, err := typedEvents.GetAt(key.Bytes())
+			if err != nil {
+				return err
+			}
+			if data == nil {
+				continue
+			}
+			rec, err := EventRecordFromBytes(data)
+			if err != nil {
+				return err
+			}
+			for _, name := range typedEventIndexNames(rec.Contract, rec.Event) {
+				indexes[name] = struct{}{}
+			}
+			if err := typedEvents.DelAt(key.Bytes()); err != nil {
+				return err
+			}
+		}
+		key := NewRequestLookupKey(blockIndex, reqIdx)
+		data, err := receipts.GetAt(key.Bytes())
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+		rec, err := RequestReceiptFromBytes(data)
+		if err != nil {
+			return err
+		}
+		if keepReceipt(rec) {
+			if err := collections.NewArray32(partition, StateVarKeptReceipts).Push(key.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := deleteReceipt(partition, rec.Request.ID(), key); err != nil {
+			return err
+		}
+	}
+	// indexes and contracts are sorted to keep the order of mutations deterministic
+	sortedIndexes := make([]string, 0, len(indexes))
+	for name := range indexes {
+		sortedIndexes = append(sortedIndexes, name)
+	}
+	sort.Strings(sortedIndexes)
+	for _, name := range sortedIndexes {
+		if err := trimTypedEventIndex(partition, name, blockIndex); err != nil {
+			return err
+		}
+	}
+	contracts := make([]iscp.Hname, 0)
+	var decodeErr error
+	err = collections.NewMap(partition, StateVarEventContracts).IterateKeys(func(elemKey []byte) bool {
+		contract, err := iscp.HnameFromBytes(elemKey)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+		contracts = append(contracts, contract)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if decodeErr != nil {
+		return decodeErr
+	}
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i] < contracts[j] })
+	for _, contract := range contracts {
+		if err := trimSmartContractEventsLookup(partition, contract, blockIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneKeptReceipts checks again the oldest receipts kept for the replay protection and deletes the ones
+// keepReceipt releases. The receipts still kept go to the end of the queue
+func pruneKeptReceipts(partition kv.KVStore, keepReceipt func(rec *RequestReceipt) bool) error {
+	kept := collections.NewArray32(partition, StateVarKeptReceipts)
+	n, err := kept.Len()
+	if err != nil {
+		return err
+	}
+	head, err := codec.DecodeUint32(partition.MustGet(StateVarKeptReceiptsHead), 0)
+	if err != nil {
+		return err
+	}
+	end := head + MaxKeptReceiptsCheckedPerBlock
+	if end > n {
+		end = n
+	}
+	if end == head {
+		return nil
+	}
+	receipts := collections.NewMapReadOnly(partition, StateVarRequestReceipts)
+	for i := head; i < end; i++ {
+		data, err := kept.GetAt(i)
+		if err != nil {
+			return err
+		}
+		var key RequestLookupKey
+		copy(key[:], data)
+		partition.Del(collections.Array32RangeKeys(StateVarKeptReceipts, n, i, i+1)[0])
+		if data, err = receipts.GetAt(key.Bytes()); err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+		rec, err := RequestReceiptFromBytes(data)
+		if err != nil {
+			return err
+		}
+		if keepReceipt(rec) {
+			if err := kept.Push(key.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := deleteReceipt(partition, rec.Request.ID(), key); err != nil {
+			return err
+		}
+	}
+	if n, err = kept.Len(); err != nil {
+		return err
+	}
+	if end < n {
+		partition.Set(StateVarKeptReceiptsHead, codec.EncodeUint32(end))
+		return nil
+	}
+	// the queue is empty, it starts again from the position 0
+	partition.Del(collections.Array32SizeKey(StateVarKeptReceipts))
+	partition.Del(StateVarKeptReceiptsHead)
+	return nil
+}
+
+func deleteReceipt(partition kv.KVStore, reqID iscp.RequestID, key RequestLookupKey) error {
+	if err := collections.NewMap(partition, StateVarRequestReceipts).DelAt(key.Bytes()); err != nil {
+		return err
+	}
+	return deleteRequestLookupKey(partition, reqID, key)
+}
+
+func deleteRequestLookupKey(partition kv.KVStore, reqID iscp.RequestID, key RequestLookupKey) error {
+	lookupTable := collections.NewMap(partition, StateVarRequestLookupIndex)
+	digest := reqID.LookupDigest()
+	bin, err := lookupTable.GetAt(digest[:])
+	if err != nil || bin == nil {
+		return err
+	}
+	lst, err := RequestLookupKeyListFromBytes(bin)
+	if err != nil {
+		return err
+	}
+	ret := make(RequestLookupKeyList, 0, len(lst))
+	for _, k := range lst {
+		if k != key {
+			ret = append(ret, k)
+		}
+	}
+	if len(ret) == 0 {
+		return lookupTable.DelAt(digest[:])
+	}
+	return lookupTable.SetAt(digest[:], ret.Bytes())
+}
+
+// trimSmartContractEventsLookup removes keys of the events up to the block from the lookup list of the contract.
+// The keys are in the order of blocks. The contract without events is removed from the registered ones
+func trimSmartContractEventsLookup(partition kv.KVStore, contract iscp.Hname, blockIndex uint32) error {
+	scLut := collections.NewMap(partition, StateVarSmartContractEventsLookup)
+	entries, err := scLut.GetAt(contract.Bytes())
+	if err != nil {
+		return err
+	}
+	var key EventLookupKey
+	i := 0
+	for ; i+len(key) <= len(entries); i += len(key) {
+		copy(key[:], entries[i:i+len(key)])
+		if key.BlockIndex() > blockIndex {
+			break
+		}
+	}
+	if i == 0 && len(entries) > 0 {
+		return nil
+	}
+	if i >= len(entries) {
+		if err := collections.NewMap(partition, StateVarEventContracts).DelAt(contract.Bytes()); err != nil {
+			return err
+		}
+		return scLut.DelAt(contract.Bytes())
+	}
+	return scLut.SetAt(contract.Bytes(), entries[i:])
+}
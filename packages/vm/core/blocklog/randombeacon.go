@@ -0,0 +1,70 @@
+package blocklog
+
+import (
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/bls"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/util"
+	"golang.org/x/xerrors"
+)
+
+// The random beacon of the block is the threshold BLS signature of the committee over RandomBeaconMessage.
+// The signature is unique for the committee key and the message, so neither the proposer of the block
+// nor any coalition of less than the quorum of the committee members can influence or predict it.
+// The beacon is stored as bls.SignatureWithPublicKey, so it can be verified against the state controller
+// address of the chain, which is the BLS address of the committee public key
+
+// RandomBeaconMessage is the data signed by the committee to produce the random beacon of the block
+func RandomBeaconMessage(chainID *iscp.ChainID, blockIndex uint32) []byte {
+	ret := make([]byte, 0, ledgerstate.AddressLength+4)
+	ret = append(ret, chainID.Bytes()...)
+	return append(ret, util.Uint32To4Bytes(blockIndex)...)
+}
+
+// RandomSeed is the 32 bytes seed derived from the random beacon
+func RandomSeed(beacon []byte) hashing.HashValue {
+	return hashing.HashData(beacon)
+}
+
+// VerifyRandomBeacon checks that the beacon is the signature of the block by the committee which controls
+// the state address
+func VerifyRandomBeacon(beacon []byte, chainID *iscp.ChainID, blockIndex uint32, stateAddress ledgerstate.Address) error {
+	sig, _, err := bls.SignatureWithPublicKeyFromBytes(beacon)
+	if err != nil {
+		return xerrors.Errorf("VerifyRandomBeacon: %w", err)
+	}
+	if !ledgerstate.NewBLSSignature(sig).AddressSignatureValid(stateAddress, RandomBeaconMessage(chainID, blockIndex)) {
+		return xerrors.Errorf("VerifyRandomBeacon: invalid random beacon of the block #%d for the state address %s",
+			blockIndex, stateAddress.Base58())
+	}
+	return nil
+}
+
+// SaveRandomBeacon stores the random beacon of the block. Beacons are not pruned with the receipts
+func SaveRandomBeacon(partition kv.KVStore, blockIndex uint32, beacon []byte) {
+	collections.NewMap(partition, StateVarRandomBeacons).MustSetAt(util.Uint32To4Bytes(blockIndex), beacon)
+}
+
+// GetRandomBeacon returns the random beacon of the block or nil, if the block has no beacon
+func GetRandomBeacon(partition kv.KVStoreReader, blockIndex uint32) []byte {
+	return collections.NewMapReadOnly(partition, StateVarRandomBeacons).MustGetAt(util.Uint32To4Bytes(blockIndex))
+}
+
+// getStateAddressOfBlock returns the state controller address, which signed the block.
+// The block is produced by the committee which controls the state output of the previous block
+func getStateAddressOfBlock(partition kv.KVStoreReader, blockIndex uint32) (ledgerstate.Address, error) {
+	registry := collections.NewArray32ReadOnly(partition, StateVarControlAddresses)
+	for i := registry.MustLen(); i > 0; i-- {
+		rec, err := ControlAddressesFromBytes(registry.MustGetAt(i - 1))
+		if err != nil {
+			return nil, err
+		}
+		if rec.SinceBlockIndex < blockIndex {
+			return rec.StateAddress, nil
+		}
+	}
+	return nil, xerrors.Errorf("unknown state address of the block #%d", blockIndex)
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governance
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"golang.org/x/xerrors"
+)
+
+// Council is the M-of-N set of agents which governs the chain. When the council is set, the chain owner
+// is the governance contract itself: governance actions are executed by the governance contract
+// on behalf of the council once a proposal is approved by the quorum of its members
+type Council struct {
+	Members []*iscp.AgentID
+	// Quorum is the number of members which have to approve a proposal
+	Quorum uint16
+	// Timelock is the time which has to pass between the approval of a proposal and its execution
+	Timelock time.Duration
+	// ProposalLifetime is the time after which a proposal which has not been executed expires
+	ProposalLifetime time.Duration
+}
+
+// CouncilAgentID is the chain owner of the chain governed by the council
+func CouncilAgentID(chainID *iscp.ChainID) *iscp.AgentID {
+	return iscp.NewAgentID(chainID.AsAddress(), Contract.Hname())
+}
+
+// GetCouncil returns the council of the chain from the state of the governance contract, or nil if the chain
+// is not governed by a council
+func GetCouncil(state kv.KVStoreReader) *Council {
+	d := kvdecoder.New(state)
+	quorum := d.MustGetUint16(VarCouncilQuorum, 0)
+	if quorum == 0 {
+		return nil
+	}
+	ret := &Council{
+		Members:          make([]*iscp.AgentID, 0),
+		Quorum:           quorum,
+		Timelock:         time.Duration(d.MustGetUint32(VarCouncilTimelock, 0)) * time.Second,
+		ProposalLifetime: time.Duration(d.MustGetUint32(VarProposalLifetime, 0)) * time.Second,
+	}
+	collections.NewMapReadOnly(state, VarCouncilMembers).MustIterateKeys(func(elemKey []byte) bool {
+		agentID, err := iscp.AgentIDFromBytes(elemKey)
+		if err != nil {
+			panic(xerrors.Errorf("GetCouncil: %w", err))
+		}
+		ret.Members = append(ret.Members, agentID)
+		return true
+	})
+	return ret
+}
+
+// IsCouncilMember checks if the agent is a member of the council of the chain
+func IsCouncilMember(state kv.KVStoreReader, agentID *iscp.AgentID) bool {
+	return collections.NewMapReadOnly(state, VarCouncilMembers).MustHasAt(agentID.Bytes())
+}
+
+// Params returns the parameters of FuncSetCouncil for the council
+func (c *Council) Params() dict.Dict {
+	d := dict.Dict{
+		ParamCouncilQuorum:    codec.EncodeUint16(c.Quorum),
+		ParamCouncilTimelock:  codec.EncodeUint32(uint32(c.Timelock / time.Second)),
+		ParamProposalLifetime: codec.EncodeUint32(uint32(c.ProposalLifetime / time.Second)),
+	}
+	members := collections.NewMap(d, ParamCouncilMembers)
+	for _, member := range c.Members {
+		members.MustSetAt(member.Bytes(), []byte{0xFF})
+	}
+	return d
+}
+
+// ProposalRequest is a governance call proposed to the council
+type ProposalRequest struct {
+	Target      iscp.Hname
+	EntryPoint  iscp.Hname
+	Params      dict.Dict
+	Description string
+}
+
+// AsDict returns the parameters of FuncPropose for the proposal
+func (p *ProposalRequest) AsDict() dict.Dict {
+	d := dict.Dict{
+		ParamProposalTarget:      codec.EncodeHname(p.Target),
+		ParamProposalEntryPoint:  codec.EncodeHname(p.EntryPoint),
+		ParamProposalDescription: codec.EncodeString(p.Description),
+	}
+	if len(p.Params) > 0 {
+		d.Set(ParamProposalParams, p.Params.Bytes())
+	}
+	return d
+}
+
+// Proposal is a pending governance call, stored in the state of the governance contract until it is
+// executed, rejected or expired
+type Proposal struct {
+	ID          uint32
+	Proposer    *iscp.AgentID
+	Target      iscp.Hname
+	EntryPoint  iscp.Hname
+	Params      dict.Dict
+	Description string
+	// Expiry is the timestamp after which the proposal can't be voted for or executed anymore.
+	// It is extended by the late approval, so the proposal can be executed after the timelock
+	Expiry int64
+	// ApprovedAt is the timestamp when the proposal was approved by the quorum, 0 if it is not approved
+	ApprovedAt int64
+	Approvals  []*iscp.AgentID
+	Rejections []*iscp.AgentID
+}
+
+func ProposalFromMarshalUtil(mu *marshalutil.MarshalUtil) (*Proposal, error) {
+	ret := &Proposal{}
+	var err error
+	if ret.ID, err = mu.ReadUint32(); err != nil {
+		return nil, err
+	}
+	if ret.Proposer, err = iscp.AgentIDFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Target, err = iscp.HnameFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.EntryPoint, err = iscp.HnameFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Params, err = dict.FromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	descriptionLen, err := mu.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+	description, err := mu.ReadBytes(int(descriptionLen))
+	if err != nil {
+		return nil, err
+	}
+	ret.Description = string(description)
+	if ret.Expiry, err = mu.ReadInt64(); err != nil {
+		return nil, err
+	}
+	if ret.ApprovedAt, err = mu.ReadInt64(); err != nil {
+		return nil, err
+	}
+	if ret.Approvals, err = readAgentIDs(mu); err != nil {
+		return nil, err
+	}
+	if ret.Rejections, err = readAgentIDs(mu); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func ProposalFromBytes(data []byte) (*Proposal, error) {
+	return ProposalFromMarshalUtil(marshalutil.New(data))
+}
+
+func (p *Proposal) Bytes() []byte {
+	mu := marshalutil.New()
+	mu.WriteUint32(p.ID)
+	mu.Write(p.Proposer)
+	mu.Write(p.Target)
+	mu.Write(p.EntryPoint)
+	p.Params.WriteToMarshalUtil(mu)
+	mu.WriteUint16(uint16(len(p.Description)))
+	mu.WriteBytes([]byte(p.Description))
+	mu.WriteInt64(p.Expiry)
+	mu.WriteInt64(p.ApprovedAt)
+	writeAgentIDs(mu, p.Approvals)
+	writeAgentIDs(mu, p.Rejections)
+	return mu.Bytes()
+}
+
+// Vote records the vote of the member. The previous vote of the member, if any, is replaced
+func (p *Proposal) Vote(member *iscp.AgentID, approve bool) {
+	p.Approvals = removeAgentID(p.Approvals, member)
+	p.Rejections = removeAgentID(p.Rejections, member)
+	if approve {
+		p.Approvals = append(p.Approvals, member)
+	} else {
+		p.Rejections = append(p.Rejections, member)
+	}
+}
+
+// ExecutableAt returns the timestamp from which the approved proposal can be executed
+func (p *Proposal) ExecutableAt(timelock time.Duration) int64 {
+	return p.ApprovedAt + timelock.Nanoseconds()
+}
+
+// GetProposal returns the pending proposal with the ID, or nil if there is no such proposal
+func GetProposal(state kv.KVStoreReader, id uint32) (*Proposal, error) {
+	data := collections.NewMapReadOnly(state, VarProposals).MustGetAt(codec.EncodeUint32(id))
+	if data == nil {
+		return nil, nil
+	}
+	return ProposalFromBytes(data)
+}
+
+// DecodeProposals decodes the result of FuncGetProposals
+func DecodeProposals(d dict.Dict) ([]*Proposal, error) {
+	ret := make([]*Proposal, 0)
+	var err error
+	collections.NewMapReadOnly(d, ParamProposals).MustIterate(func(_ []byte, value []byte) bool {
+		var p *Proposal
+		if p, err = ProposalFromBytes(value); err != nil {
+			return false
+		}
+		ret = append(ret, p)
+		return true
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("DecodeProposals: %w", err)
+	}
+	return ret, nil
+}
+
+func readAgentIDs(mu *marshalutil.MarshalUtil) ([]*iscp.AgentID, error) {
+	n, err := mu.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*iscp.AgentID, n)
+	for i := range ret {
+		if ret[i], err = iscp.AgentIDFromMarshalUtil(mu); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func writeAgentIDs(mu *marshalutil.MarshalUtil, agentIDs []*iscp.AgentID) {
+	mu.WriteUint16(uint16(len(agentIDs)))
+	for _, agentID := range agentIDs {
+		mu.Write(agentID)
+	}
+}
+
+func removeAgentID(agentIDs []*iscp.AgentID, agentID *iscp.AgentID) []*iscp.AgentID {
+	ret := agentIDs[:0]
+	for _, a := range agentIDs {
+		if !a.Equals(agentID) {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
@@ -44,6 +44,10 @@ func claimChainOwnership(ctx iscp.Sandbox) (dict.Dict, error) {
 
 	state.Set(governance.VarChainOwnerID, codec.EncodeAgentID(nextOwner))
 	state.Del(governance.VarChainOwnerIDDelegated)
+	if currentOwner.Equals(governance.CouncilAgentID(ctx.ChainID())) {
+		// the ownership is taken over from the council
+		clearCouncil(state)
+	}
 	ctx.Log().Debugf("governance.chainChainOwner.success: chain owner changed: %s --> %s",
 		currentOwner.String(), nextOwner.String())
 	return nil, nil
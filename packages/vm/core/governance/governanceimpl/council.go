@@ -0,0 +1,270 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governanceimpl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/assert"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+)
+
+// setCouncil makes the chain governed by the M-of-N council. Can only be called by the chain owner.
+// The chain owner becomes the governance contract itself, so from then on the governance calls are
+// made through the proposals of the council. Setting a new council drops the pending proposals
+// Input:
+// - ParamCouncilMembers   - map with agent IDs of the members as keys
+// - ParamCouncilQuorum    - uint16 number of members which have to approve a proposal
+// - ParamCouncilTimelock  - uint32 seconds between the approval and the execution of a proposal, default 0
+// - ParamProposalLifetime - uint32 seconds after which a proposal expires, default 7 days
+func setCouncil(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	a.Require(governance.CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "governance.setCouncil: not authorized")
+
+	members := make([]*iscp.AgentID, 0)
+	var err error
+	collections.NewMapReadOnly(ctx.Params(), governance.ParamCouncilMembers).MustIterateKeys(func(elemKey []byte) bool {
+		var member *iscp.AgentID
+		if member, err = iscp.AgentIDFromBytes(elemKey); err != nil {
+			return false
+		}
+		members = append(members, member)
+		return true
+	})
+	a.RequireNoError(err)
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	quorum := params.MustGetUint16(governance.ParamCouncilQuorum)
+	a.Require(quorum > 0 && int(quorum) <= len(members), "governance.setCouncil: wrong quorum %d of %d", quorum, len(members))
+	timelock := params.MustGetUint32(governance.ParamCouncilTimelock, 0)
+	lifetime := params.MustGetUint32(governance.ParamProposalLifetime, uint32(governance.DefaultProposalLifetime/time.Second))
+	a.Require(lifetime > timelock, "governance.setCouncil: proposal lifetime must be longer than the timelock")
+
+	state := ctx.State()
+	clearCouncil(state)
+	membersMap := collections.NewMap(state, governance.VarCouncilMembers)
+	for _, member := range members {
+		membersMap.MustSetAt(member.Bytes(), []byte{0xFF})
+	}
+	state.Set(governance.VarCouncilQuorum, codec.EncodeUint16(quorum))
+	state.Set(governance.VarCouncilTimelock, codec.EncodeUint32(timelock))
+	state.Set(governance.VarProposalLifetime, codec.EncodeUint32(lifetime))
+
+	state.Set(governance.VarChainOwnerID, codec.EncodeAgentID(governance.CouncilAgentID(ctx.ChainID())))
+	state.Del(governance.VarChainOwnerIDDelegated)
+	ctx.Event(fmt.Sprintf("[council set] quorum %d of %d, timelock %ds", quorum, len(members), timelock))
+	return nil, nil
+}
+
+// clearCouncil removes the council and its pending proposals. The proposal IDs are never reused
+func clearCouncil(state kv.KVStore) {
+	collections.NewMap(state, governance.VarCouncilMembers).Erase()
+	collections.NewMap(state, governance.VarProposals).Erase()
+	state.Del(governance.VarCouncilQuorum)
+	state.Del(governance.VarCouncilTimelock)
+	state.Del(governance.VarProposalLifetime)
+}
+
+// propose creates a proposal of the governance call. Can only be called by the members of the council.
+// The proposer approves the proposal
+// Input:
+// - ParamProposalTarget      - hname of the contract to call
+// - ParamProposalEntryPoint  - hname of the entry point to call
+// - ParamProposalParams      - (optional) serialized dict with the parameters of the call
+// - ParamProposalDescription - (optional) string
+// Output:
+// - ParamProposalID - uint32 ID of the proposal
+func propose(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	state := ctx.State()
+	council := mustGetCouncil(ctx, a)
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	callParams := dict.New()
+	if ctx.Params().MustHas(governance.ParamProposalParams) {
+		var err error
+		callParams, err = dict.FromBytes(params.MustGetBytes(governance.ParamProposalParams))
+		a.RequireNoError(err)
+	}
+
+	proposals := collections.NewMap(state, governance.VarProposals)
+	pruneExpiredProposals(ctx, proposals)
+	a.Require(proposals.MustLen() < governance.MaxPendingProposals, "governance.propose: too many pending proposals")
+
+	stateDecoder := kvdecoder.New(state, ctx.Log())
+	id := stateDecoder.MustGetUint32(governance.VarNextProposalID, 0)
+	state.Set(governance.VarNextProposalID, codec.EncodeUint32(id+1))
+
+	p := &governance.Proposal{
+		ID:          id,
+		Proposer:    ctx.Caller(),
+		Target:      params.MustGetHname(governance.ParamProposalTarget),
+		EntryPoint:  params.MustGetHname(governance.ParamProposalEntryPoint),
+		Params:      callParams,
+		Description: params.MustGetString(governance.ParamProposalDescription, ""),
+		Expiry:      ctx.GetTimestamp() + council.ProposalLifetime.Nanoseconds(),
+		Approvals:   make([]*iscp.AgentID, 0),
+		Rejections:  make([]*iscp.AgentID, 0),
+	}
+	// the proposals are executed on behalf of the governance contract, which holds the validator rewards
+	a.Require(p.Target != accounts.Contract.Hname() || p.EntryPoint != accounts.FuncPayoutValidatorRewards.Hname(),
+		"governance.propose: the validator rewards can't be moved by the council")
+	p.Vote(ctx.Caller(), true)
+	tallyVotes(ctx, council, p)
+	proposals.MustSetAt(codec.EncodeUint32(id), p.Bytes())
+	ctx.Event(fmt.Sprintf("[proposal created] %d: %s::%s by %s", id, p.Target, p.EntryPoint, p.Proposer))
+	return dict.Dict{governance.ParamProposalID: codec.EncodeUint32(id)}, nil
+}
+
+// vote records the vote of the member of the council for the proposal. The member can change the vote while
+// the proposal is pending. The proposal is dropped when it can't reach the quorum anymore
+// Input:
+// - ParamProposalID - uint32
+// - ParamApprove    - (optional) bool, default true
+func vote(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	council := mustGetCouncil(ctx, a)
+	p := mustGetPendingProposal(ctx, a)
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	approve := params.MustGetBool(governance.ParamApprove, true)
+	p.Vote(ctx.Caller(), approve)
+
+	proposals := collections.NewMap(ctx.State(), governance.VarProposals)
+	if !tallyVotes(ctx, council, p) {
+		proposals.MustDelAt(codec.EncodeUint32(p.ID))
+		ctx.Event(fmt.Sprintf("[proposal rejected] %d", p.ID))
+		return nil, nil
+	}
+	proposals.MustSetAt(codec.EncodeUint32(p.ID), p.Bytes())
+	ctx.Event(fmt.Sprintf("[proposal vote] %d: %s approve=%v, %d of %d", p.ID, ctx.Caller(), approve, len(p.Approvals), council.Quorum))
+	return nil, nil
+}
+
+// executeProposal executes the call of the approved proposal once its timelock has passed.
+// Can be called by anyone. The call is made by the governance contract, which is the chain owner
+// Input:
+// - ParamProposalID - uint32
+// Output: the result of the call
+func executeProposal(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	council := governance.GetCouncil(ctx.State())
+	a.Require(council != nil, "governance.executeProposal: the chain is not governed by a council")
+	p := mustGetPendingProposal(ctx, a)
+	a.Require(p.ApprovedAt != 0, "governance.executeProposal: proposal %d is not approved", p.ID)
+	a.Require(ctx.GetTimestamp() >= p.ExecutableAt(council.Timelock),
+		"governance.executeProposal: proposal %d is timelocked until %s", p.ID, time.Unix(0, p.ExecutableAt(council.Timelock)))
+
+	// the proposal is removed before the call, which may change the council
+	collections.NewMap(ctx.State(), governance.VarProposals).MustDelAt(codec.EncodeUint32(p.ID))
+	ctx.Event(fmt.Sprintf("[proposal executed] %d: %s::%s", p.ID, p.Target, p.EntryPoint))
+	return ctx.Call(p.Target, p.EntryPoint, p.Params, nil)
+}
+
+func mustGetCouncil(ctx iscp.Sandbox, a assert.Assert) *governance.Council {
+	council := governance.GetCouncil(ctx.State())
+	a.Require(council != nil, "governance: the chain is not governed by a council")
+	a.Require(governance.IsCouncilMember(ctx.State(), ctx.Caller()), "governance: %s is not a member of the council", ctx.Caller())
+	return council
+}
+
+func mustGetPendingProposal(ctx iscp.Sandbox, a assert.Assert) *governance.Proposal {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := params.MustGetUint32(governance.ParamProposalID)
+	p, err := governance.GetProposal(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(p != nil, "governance: proposal %d not found", id)
+	a.Require(ctx.GetTimestamp() < p.Expiry, "governance: proposal %d has expired", id)
+	return p
+}
+
+// tallyVotes updates the approval time of the proposal. Returns false if the proposal can't be approved anymore.
+// The proposal approved late is kept after its timelock for as long as the one approved when proposed
+func tallyVotes(ctx iscp.Sandbox, council *governance.Council, p *governance.Proposal) bool {
+	if len(p.Approvals) >= int(council.Quorum) {
+		if p.ApprovedAt == 0 {
+			p.ApprovedAt = ctx.GetTimestamp()
+			expiry := p.ExecutableAt(council.Timelock) + (council.ProposalLifetime - council.Timelock).Nanoseconds()
+			if expiry > p.Expiry {
+				p.Expiry = expiry
+			}
+		}
+	} else {
+		p.ApprovedAt = 0
+	}
+	return len(council.Members)-len(p.Rejections) >= int(council.Quorum)
+}
+
+func pruneExpiredProposals(ctx iscp.Sandbox, proposals *collections.Map) {
+	expired := make([][]byte, 0)
+	proposals.MustIterate(func(elemKey []byte, value []byte) bool {
+		p, err := governance.ProposalFromBytes(value)
+		if err != nil {
+			ctx.Log().Panicf("governance: %v", err)
+		}
+		if ctx.GetTimestamp() >= p.Expiry {
+			expired = append(expired, elemKey)
+		}
+		return true
+	})
+	for _, key := range expired {
+		proposals.MustDelAt(key)
+	}
+}
+
+// getCouncil returns the council of the chain, or nothing if the chain is not governed by a council.
+// The result has the layout of the state, so it can be decoded with governance.GetCouncil
+func getCouncil(ctx iscp.SandboxView) (dict.Dict, error) {
+	council := governance.GetCouncil(ctx.State())
+	if council == nil {
+		return nil, nil
+	}
+	return council.Params(), nil
+}
+
+// getProposals returns the pending proposals which have not expired by the timestamp of the latest block
+// Output:
+// - ParamProposals - map of the serialized proposals by IDs
+func getProposals(ctx iscp.SandboxView) (dict.Dict, error) {
+	ret := dict.New()
+	pending := collections.NewMap(ret, governance.ParamProposals)
+	var err error
+	collections.NewMapReadOnly(ctx.State(), governance.VarProposals).MustIterate(func(elemKey []byte, value []byte) bool {
+		var p *governance.Proposal
+		if p, err = governance.ProposalFromBytes(value); err != nil {
+			return false
+		}
+		if ctx.GetTimestamp() < p.Expiry {
+			pending.MustSetAt(elemKey, value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// getProposal returns the pending proposal
+// Input:
+// - ParamProposalID - uint32
+// Output:
+// - ParamProposal - serialized proposal
+func getProposal(ctx iscp.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := params.MustGetUint32(governance.ParamProposalID)
+	p, err := governance.GetProposal(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(p != nil, "governance.getProposal: proposal %d not found", id)
+	return dict.Dict{governance.ParamProposal: p.Bytes()}, nil
+}
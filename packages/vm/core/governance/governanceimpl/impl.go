@@ -38,6 +38,24 @@ var Processor = governance.Contract.Processor(initialize,
 	governance.FuncAddCandidateNode.WithHandler(addCandidateNodeFuncHandler),
 	governance.FuncRevokeAccessNode.WithHandler(revokeAccessNodeFuncHandler),
 	governance.FuncChangeAccessNodes.WithHandler(changeAccessNodesFuncHandler),
+
+	// pruning
+	governance.FuncSetPruningPolicy.WithHandler(setPruningPolicy),
+	governance.FuncGetPruningPolicy.WithHandler(getPruningPolicy),
+
+	// council
+	governance.FuncSetCouncil.WithHandler(setCouncil),
+	governance.FuncPropose.WithHandler(propose),
+	governance.FuncVote.WithHandler(vote),
+	governance.FuncExecuteProposal.WithHandler(executeProposal),
+	governance.FuncGetCouncil.WithHandler(getCouncil),
+	governance.FuncGetProposals.WithHandler(getProposals),
+	governance.FuncGetProposal.WithHandler(getProposal),
+
+	// validator rewards
+	governance.FuncSetPayoutAddress.WithHandler(setPayoutAddress),
+	governance.FuncWithdrawValidatorRewards.WithHandler(withdrawValidatorRewards),
+	governance.FuncGetValidatorRewards.WithHandler(getValidatorRewards),
 )
 
 func initialize(ctx iscp.Sandbox) (dict.Dict, error) {
@@ -0,0 +1,51 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governanceimpl
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/assert"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+)
+
+// setPruningPolicy sets the pruning policy of the chain. Can only be called by the chain owner
+// Input (all optional):
+// - ParamBlocksToKeep   - uint32 number of latest blocks kept by the nodes, 0 means all blocks are kept.
+// - ParamReceiptsToKeep - uint32 number of latest blocks for which the receipts and events are kept, 0 means all are kept.
+// Non-zero values below the minimum are raised to the minimum
+func setPruningPolicy(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	a.Require(governance.CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "governance.setPruningPolicy: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	set := func(param, varName kv.Key, minValue uint32, name string) {
+		if !ctx.Params().MustHas(param) {
+			return
+		}
+		value := params.MustGetUint32(param)
+		if value > 0 && value < minValue {
+			value = minValue
+		}
+		ctx.State().Set(varName, codec.EncodeUint32(value))
+		ctx.Event(fmt.Sprintf("[updated pruning policy] %s: %d", name, value))
+	}
+	set(governance.ParamBlocksToKeep, governance.VarBlocksToKeep, governance.MinBlocksToKeep, "blocks to keep")
+	set(governance.ParamReceiptsToKeep, governance.VarReceiptsToKeep, governance.MinReceiptsToKeep, "receipts to keep")
+	return nil, nil
+}
+
+// getPruningPolicy returns the pruning policy of the chain
+func getPruningPolicy(ctx iscp.SandboxView) (dict.Dict, error) {
+	policy := governance.GetPruningPolicy(ctx.State())
+	return dict.Dict{
+		governance.VarBlocksToKeep:   codec.EncodeUint32(policy.BlocksToKeep),
+		governance.VarReceiptsToKeep: codec.EncodeUint32(policy.ReceiptsToKeep),
+	}, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governanceimpl
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/assert"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+)
+
+// setPayoutAddress registers the account which receives the rewards of the committee member.
+// Must be called with the request signed by the key of the node
+// Input:
+// - ParamNodePubKey    - []byte public key of the node
+// - ParamPayoutAddress - iscp.AgentID payout account
+func setPayoutAddress(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	nodePubKey := mustGetNodePubKey(params, a)
+	a.Require(ctx.Caller().Address().Equals(ledgerstate.NewED25519Address(nodePubKey)),
+		"governance.setPayoutAddress: not authorized")
+
+	payout := params.MustGetAgentID(governance.ParamPayoutAddress)
+	governance.SetPayoutAddress(ctx.State(), nodePubKey, payout)
+	ctx.Event(fmt.Sprintf("[payout address] %s: %s", nodePubKey.String(), payout))
+	return nil, nil
+}
+
+// withdrawValidatorRewards moves the rewards accrued by the committee member to its payout account on the chain,
+// or to the account of the node, if the payout address is not registered.
+// Can be called by the node or by the payout account
+// Input:
+// - ParamNodePubKey - []byte public key of the node
+func withdrawValidatorRewards(ctx iscp.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	nodePubKey := mustGetNodePubKey(params, a)
+	target := governance.PayoutTarget(ctx.State(), nodePubKey)
+	a.Require(ctx.Caller().Address().Equals(ledgerstate.NewED25519Address(nodePubKey)) || ctx.Caller().Equals(target),
+		"governance.withdrawValidatorRewards: not authorized")
+
+	rewards := governance.ClearValidatorRewards(ctx.State(), nodePubKey)
+	if rewards.IsEmpty() {
+		return nil, nil
+	}
+	_, err := ctx.Call(accounts.Contract.Hname(), accounts.FuncPayoutValidatorRewards.Hname(), dict.Dict{
+		accounts.ParamAgentID: codec.EncodeAgentID(target),
+		accounts.ParamRewards: rewards.Bytes(),
+	}, nil)
+	a.RequireNoError(err)
+	ctx.Event(fmt.Sprintf("[validator rewards withdrawn] %s: %s to %s", nodePubKey.String(), rewards.String(), target))
+	return nil, nil
+}
+
+// getValidatorRewards returns the rewards accrued by the committee member
+// Input:
+// - ParamNodePubKey - []byte public key of the node
+// Output:
+// - ParamValidatorRewards - serialized colored.Balances not withdrawn yet
+// - ParamPayoutAddress    - iscp.AgentID the payout account, absent if not registered
+func getValidatorRewards(ctx iscp.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	nodePubKey := mustGetNodePubKey(kvdecoder.New(ctx.Params(), ctx.Log()), a)
+	ret := dict.Dict{
+		governance.ParamValidatorRewards: governance.GetValidatorRewards(ctx.State(), nodePubKey).Bytes(),
+	}
+	if payout := governance.GetPayoutAddress(ctx.State(), nodePubKey); payout != nil {
+		ret.Set(governance.ParamPayoutAddress, codec.EncodeAgentID(payout))
+	}
+	return ret, nil
+}
+
+func mustGetNodePubKey(params kvdecoder.Decoder, a assert.Assert) ed25519.PublicKey {
+	ret, _, err := ed25519.PublicKeyFromBytes(params.MustGetBytes(governance.ParamNodePubKey))
+	a.RequireNoError(err)
+	return ret
+}
@@ -6,6 +6,8 @@
 package governance
 
 import (
+	"time"
+
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 )
 
@@ -16,6 +18,13 @@ const (
 	DefaultMaxEventsPerRequest = uint16(50)
 	DefaultMaxEventSize        = uint16(2000)    // 2Kb
 	DefaultMaxBlobSize         = uint32(1000000) // 1Mb
+	// MinBlocksToKeep leaves enough blocks for the nodes which are behind to sync
+	MinBlocksToKeep   = uint32(1000)
+	MinReceiptsToKeep = uint32(100)
+	// MaxPendingProposals limits the number of proposals waiting for the council
+	MaxPendingProposals = 100
+	// DefaultProposalLifetime is used when the lifetime of proposals is not specified for the council
+	DefaultProposalLifetime = 7 * 24 * time.Hour
 )
 
 var Contract = coreutil.NewContract(coreutil.CoreContractGovernance, "Governance contract")
@@ -46,6 +55,24 @@ var (
 	FuncAddCandidateNode  = coreutil.Func("addCandidateNode")
 	FuncRevokeAccessNode  = coreutil.Func("revokeAccessNode")
 	FuncChangeAccessNodes = coreutil.Func("changeAccessNodes")
+
+	// pruning
+	FuncSetPruningPolicy = coreutil.Func("setPruningPolicy")
+	FuncGetPruningPolicy = coreutil.ViewFunc("getPruningPolicy")
+
+	// council (multi-signature chain ownership)
+	FuncSetCouncil      = coreutil.Func("setCouncil")
+	FuncPropose         = coreutil.Func("propose")
+	FuncVote            = coreutil.Func("vote")
+	FuncExecuteProposal = coreutil.Func("executeProposal")
+	FuncGetCouncil      = coreutil.ViewFunc("getCouncil")
+	FuncGetProposals    = coreutil.ViewFunc("getProposals")
+	FuncGetProposal     = coreutil.ViewFunc("getProposal")
+
+	// validator rewards
+	FuncSetPayoutAddress         = coreutil.Func("setPayoutAddress")
+	FuncWithdrawValidatorRewards = coreutil.Func("withdrawValidatorRewards")
+	FuncGetValidatorRewards      = coreutil.ViewFunc("getValidatorRewards")
 )
 
 // state variables
@@ -77,6 +104,22 @@ const (
 	VarAccessNodes          = "an"
 	VarAccessNodeCandidates = "ac"
 	VarValidatorNodes       = "vn"
+
+	// pruning
+	VarBlocksToKeep   = "bk"
+	VarReceiptsToKeep = "rk"
+
+	// council
+	VarCouncilMembers   = "cm"
+	VarCouncilQuorum    = "cq"
+	VarCouncilTimelock  = "ct"
+	VarProposalLifetime = "cl"
+	VarProposals        = "pp"
+	VarNextProposalID   = "pn"
+
+	// validator rewards
+	VarPayoutAddresses  = "va"
+	VarValidatorRewards = "vr"
 )
 
 // params
@@ -113,4 +156,27 @@ const (
 
 	// access nodes: changeAccessNodes
 	ParamChangeAccessNodesActions = "a"
+
+	// pruning
+	ParamBlocksToKeep   = "bk"
+	ParamReceiptsToKeep = "rk"
+
+	// council
+	ParamCouncilMembers      = "cm"
+	ParamCouncilQuorum       = "cq"
+	ParamCouncilTimelock     = "ct"
+	ParamProposalLifetime    = "cl"
+	ParamProposalID          = "pi"
+	ParamProposalTarget      = "pt"
+	ParamProposalEntryPoint  = "pe"
+	ParamProposalParams      = "pa"
+	ParamProposalDescription = "pd"
+	ParamApprove             = "ap"
+	ParamProposal            = "p"
+	ParamProposals           = "pp"
+
+	// validator rewards
+	ParamNodePubKey       = "np"
+	ParamPayoutAddress    = "pa"
+	ParamValidatorRewards = "vr"
 )
@@ -5,6 +5,7 @@ package governance
 
 import (
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"github.com/iotaledger/wasp/packages/kv"
@@ -42,6 +43,25 @@ func MustGetChainInfo(state kv.KVStoreReader) ChainInfo {
 	return ret
 }
 
+// GetAccessNodes returns public keys of the access nodes of the chain.
+// It is called by the chain to know which nodes follow it without being in the committee
+func GetAccessNodes(state kv.KVStoreReader) ([]ed25519.PublicKey, error) {
+	ret := make([]ed25519.PublicKey, 0)
+	var err error
+	collections.NewMapReadOnly(state, VarAccessNodes).MustIterateKeys(func(pubKeyBin []byte) bool {
+		var pubKey ed25519.PublicKey
+		if pubKey, _, err = ed25519.PublicKeyFromBytes(pubKeyBin); err != nil {
+			return false
+		}
+		ret = append(ret, pubKey)
+		return true
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("GetAccessNodes: %w", err)
+	}
+	return ret, nil
+}
+
 func MustGetChainOwnerID(state kv.KVStoreReader) *iscp.AgentID {
 	d := kvdecoder.New(state)
 	return d.MustGetAgentID(VarChainOwnerID)
@@ -0,0 +1,39 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governance
+
+import (
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+)
+
+// PruningPolicy defines how much of the history of the chain is kept. 0 means the data is kept forever
+type PruningPolicy struct {
+	// BlocksToKeep is the number of latest blocks kept in the databases of the nodes.
+	// Older blocks are deleted by the nodes in the background
+	BlocksToKeep uint32
+	// ReceiptsToKeep is the number of latest blocks for which the blocklog keeps receipts and events
+	// of the requests. Older ones are deleted by the VM. Receipts of off-ledger requests are kept as long
+	// as their nonces are within the replay protection window
+	ReceiptsToKeep uint32
+}
+
+// GetPruningPolicy returns the pruning policy of the chain from the state of the governance contract
+func GetPruningPolicy(state kv.KVStoreReader) PruningPolicy {
+	d := kvdecoder.New(state)
+	return PruningPolicy{
+		BlocksToKeep:   d.MustGetUint32(VarBlocksToKeep, 0),
+		ReceiptsToKeep: d.MustGetUint32(VarReceiptsToKeep, 0),
+	}
+}
+
+// Params returns the parameters of FuncSetPruningPolicy for the policy
+func (p *PruningPolicy) Params() dict.Dict {
+	return dict.Dict{
+		ParamBlocksToKeep:   codec.EncodeUint32(p.BlocksToKeep),
+		ParamReceiptsToKeep: codec.EncodeUint32(p.ReceiptsToKeep),
+	}
+}
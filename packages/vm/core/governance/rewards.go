@@ -0,0 +1,148 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governance
+
+import (
+	"math/bits"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"golang.org/x/xerrors"
+)
+
+// ValidatorParticipation is the weight of a committee member in the ACS result of a block.
+// The validator fees collected in the block are split among the participants proportionally to their weights
+type ValidatorParticipation struct {
+	NodePubKey ed25519.PublicKey
+	Weight     uint64
+}
+
+// ValidatorRewardsAccount is the on-chain account which holds the validator fees until they are withdrawn
+// by the committee members. The accounts of the core contracts are mapped to the common account of the chain,
+// so the tokens on this account can only be moved by the VM and by the governance contract
+func ValidatorRewardsAccount(chainID *iscp.ChainID) *iscp.AgentID {
+	return iscp.NewAgentID(chainID.AsAddress(), Contract.Hname())
+}
+
+// NodeAgentID is the agent ID of the requests signed with the key of the node
+func NodeAgentID(nodePubKey ed25519.PublicKey) *iscp.AgentID {
+	return iscp.NewAgentID(ledgerstate.NewED25519Address(nodePubKey), 0)
+}
+
+// GetValidatorRewards returns the rewards accrued by the committee member and not withdrawn yet
+func GetValidatorRewards(state kv.KVStoreReader, nodePubKey ed25519.PublicKey) colored.Balances {
+	data := collections.NewMapReadOnly(state, VarValidatorRewards).MustGetAt(nodePubKey.Bytes())
+	if data == nil {
+		return colored.NewBalances()
+	}
+	ret, err := colored.BalancesFromBytes(data)
+	if err != nil {
+		panic(xerrors.Errorf("GetValidatorRewards: %w", err))
+	}
+	return ret
+}
+
+func setValidatorRewards(state kv.KVStore, nodePubKey ed25519.PublicKey, rewards colored.Balances) {
+	m := collections.NewMap(state, VarValidatorRewards)
+	if rewards.IsEmpty() {
+		m.MustDelAt(nodePubKey.Bytes())
+		return
+	}
+	m.MustSetAt(nodePubKey.Bytes(), rewards.Bytes())
+}
+
+// ClearValidatorRewards removes the rewards of the committee member from the ledger of the rewards.
+// Returns the removed rewards
+func ClearValidatorRewards(state kv.KVStore, nodePubKey ed25519.PublicKey) colored.Balances {
+	ret := GetValidatorRewards(state, nodePubKey)
+	setValidatorRewards(state, nodePubKey, nil)
+	return ret
+}
+
+// GetPayoutAddress returns the agent ID registered by the committee member to receive its rewards, or nil
+func GetPayoutAddress(state kv.KVStoreReader, nodePubKey ed25519.PublicKey) *iscp.AgentID {
+	data := collections.NewMapReadOnly(state, VarPayoutAddresses).MustGetAt(nodePubKey.Bytes())
+	if data == nil {
+		return nil
+	}
+	ret, err := iscp.AgentIDFromBytes(data)
+	if err != nil {
+		panic(xerrors.Errorf("GetPayoutAddress: %w", err))
+	}
+	return ret
+}
+
+// SetPayoutAddress registers the agent ID which receives the rewards of the committee member
+func SetPayoutAddress(state kv.KVStore, nodePubKey ed25519.PublicKey, payout *iscp.AgentID) {
+	collections.NewMap(state, VarPayoutAddresses).MustSetAt(nodePubKey.Bytes(), payout.Bytes())
+}
+
+// PayoutTarget returns the account to which the rewards of the committee member are withdrawn.
+// It is the node itself, if no payout address is registered
+func PayoutTarget(state kv.KVStoreReader, nodePubKey ed25519.PublicKey) *iscp.AgentID {
+	if ret := GetPayoutAddress(state, nodePubKey); ret != nil {
+		return ret
+	}
+	return NodeAgentID(nodePubKey)
+}
+
+// totalValidatorRewards is the sum of the rewards allocated to the committee members
+func totalValidatorRewards(state kv.KVStoreReader) colored.Balances {
+	ret := colored.NewBalances()
+	collections.NewMapReadOnly(state, VarValidatorRewards).MustIterate(func(_, value []byte) bool {
+		rewards, err := colored.BalancesFromBytes(value)
+		if err != nil {
+			panic(xerrors.Errorf("totalValidatorRewards: %w", err))
+		}
+		ret.AddAll(rewards)
+		return true
+	})
+	return ret
+}
+
+// DistributeValidatorRewards allocates the tokens on the validator rewards account, which are not allocated yet,
+// to the participants of the block. Each participant gets the part proportional to its weight.
+// The remainder of the integer division stays unallocated and is distributed with the fees of the next block
+func DistributeValidatorRewards(state kv.KVStore, available colored.Balances, participants []*ValidatorParticipation) {
+	var totalWeight uint64
+	for _, p := range participants {
+		totalWeight += p.Weight
+	}
+	if totalWeight == 0 {
+		return
+	}
+	allocated := totalValidatorRewards(state)
+	unallocated := colored.NewBalances()
+	available.ForEachSorted(func(col colored.Color, bal uint64) bool {
+		if bal > allocated.Get(col) {
+			unallocated.Set(col, bal-allocated.Get(col))
+		}
+		return true
+	})
+	if unallocated.IsEmpty() {
+		return
+	}
+	rewards := make(map[ed25519.PublicKey]colored.Balances)
+	order := make([]ed25519.PublicKey, 0, len(participants))
+	for _, p := range participants {
+		if _, ok := rewards[p.NodePubKey]; !ok {
+			rewards[p.NodePubKey] = GetValidatorRewards(state, p.NodePubKey)
+			order = append(order, p.NodePubKey)
+		}
+		unallocated.ForEachSorted(func(col colored.Color, bal uint64) bool {
+			// bal * weight / totalWeight without the overflow; the quotient fits, because weight <= totalWeight
+			hi, lo := bits.Mul64(bal, p.Weight)
+			share, _ := bits.Div64(hi, lo, totalWeight)
+			rewards[p.NodePubKey].Add(col, share)
+			return true
+		})
+	}
+	for _, nodePubKey := range order {
+		setValidatorRewards(state, nodePubKey, rewards[nodePubKey])
+	}
+}
@@ -0,0 +1,66 @@
+package root
+
+import (
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+)
+
+// ContractVersion is a record of one upgrade of the contract's program.
+// The history of the contract is the list of its versions, starting from 1
+type ContractVersion struct {
+	// Version is the sequence number of the upgrade
+	Version uint32
+	// PrevProgramHash is the program hash before the upgrade
+	PrevProgramHash hashing.HashValue
+	// ProgramHash is the program hash after the upgrade
+	ProgramHash hashing.HashValue
+	// UpgradedBy is the agentID which requested the upgrade
+	UpgradedBy *iscp.AgentID
+	// Timestamp of the upgrade
+	Timestamp int64
+	// Migrated is true if the 'migrate' entry point was called
+	Migrated bool
+}
+
+func ContractVersionFromMarshalUtil(mu *marshalutil.MarshalUtil) (*ContractVersion, error) {
+	ret := &ContractVersion{}
+	var err error
+	if ret.Version, err = mu.ReadUint32(); err != nil {
+		return nil, err
+	}
+	buf, err := mu.ReadBytes(len(ret.PrevProgramHash))
+	if err != nil {
+		return nil, err
+	}
+	copy(ret.PrevProgramHash[:], buf)
+	if buf, err = mu.ReadBytes(len(ret.ProgramHash)); err != nil {
+		return nil, err
+	}
+	copy(ret.ProgramHash[:], buf)
+	if ret.UpgradedBy, err = iscp.AgentIDFromMarshalUtil(mu); err != nil {
+		return nil, err
+	}
+	if ret.Timestamp, err = mu.ReadInt64(); err != nil {
+		return nil, err
+	}
+	if ret.Migrated, err = mu.ReadBool(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func ContractVersionFromBytes(data []byte) (*ContractVersion, error) {
+	return ContractVersionFromMarshalUtil(marshalutil.New(data))
+}
+
+func (v *ContractVersion) Bytes() []byte {
+	return marshalutil.New().
+		WriteUint32(v.Version).
+		WriteBytes(v.PrevProgramHash[:]).
+		WriteBytes(v.ProgramHash[:]).
+		Write(v.UpgradedBy).
+		WriteInt64(v.Timestamp).
+		WriteBool(v.Migrated).
+		Bytes()
+}
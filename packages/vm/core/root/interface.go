@@ -17,6 +17,7 @@ const (
 	VarDeployPermissionsEnabled = "a"
 	VarDeployPermissions        = "p"
 	VarStateInitialized         = "i"
+	VarContractHistory          = "v"
 )
 
 // param variables
@@ -29,6 +30,7 @@ const (
 	ParamContractFound            = "cf"
 	ParamDescription              = "ds"
 	ParamDeployPermissionsEnabled = "de"
+	ParamMigrate                  = "mg"
 )
 
 // function names
@@ -37,6 +39,8 @@ var (
 	FuncGrantDeployPermission    = coreutil.Func("grantDeployPermission")
 	FuncRevokeDeployPermission   = coreutil.Func("revokeDeployPermission")
 	FuncRequireDeployPermissions = coreutil.Func("requireDeployPermissions")
+	FuncUpgradeContract          = coreutil.Func("upgradeContract")
 	FuncFindContract             = coreutil.ViewFunc("findContract")
 	FuncGetContractRecords       = coreutil.ViewFunc("getContractRecords")
+	FuncGetContractHistory       = coreutil.ViewFunc("getContractHistory")
 )
@@ -53,3 +53,27 @@ func DecodeContractRegistry(contractRegistry *collections.ImmutableMap) (map[isc
 	})
 	return ret, err
 }
+
+// ContractHistoryArrayName is the name of the array in the 'root' state which keeps the versions of the contract
+func ContractHistoryArrayName(hname iscp.Hname) string {
+	return VarContractHistory + string(hname.Bytes())
+}
+
+// DecodeContractHistory decodes the versions of the contract from the array, the oldest first
+func DecodeContractHistory(history *collections.ImmutableArray32) ([]*ContractVersion, error) {
+	n, err := history.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*ContractVersion, n)
+	for i := range ret {
+		data, err := history.GetAt(uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = ContractVersionFromBytes(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
@@ -4,6 +4,7 @@
 // - maintaining (setting, delegating) chain owner ID
 // - maintaining (granting, revoking) smart contract deployment rights
 // - deployment of smart contracts on the chain and maintenance of contract registry
+// - upgrade of the deployed smart contracts and maintenance of their version history
 package rootimpl
 
 import (
@@ -30,6 +31,8 @@ var Processor = root.Contract.Processor(initialize,
 	root.FuncFindContract.WithHandler(findContract),
 	root.FuncGetContractRecords.WithHandler(getContractRecords),
 	root.FuncRequireDeployPermissions.WithHandler(requireDeployPermissions),
+	root.FuncUpgradeContract.WithHandler(upgradeContract),
+	root.FuncGetContractHistory.WithHandler(getContractHistory),
 )
 
 // initialize handles constructor, the "init" request. This is the first call to the chain
@@ -117,6 +120,90 @@ func deployContract(ctx iscp.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
+// upgradeContract replaces the program of a deployed contract, keeping its hname, state and balances.
+// Only the creator of the contract or the chain owner can upgrade it. Core contracts cannot be upgraded.
+// If requested, calls the 'migrate' entry point of the new program within the same request. If the migration
+// fails, the upgrade is reverted together with the whole request
+// Inputs:
+// - ParamHname iscp.Hname of the contract to upgrade
+// - ParamProgramHash HashValue of the new program
+// - ParamMigrate bool whether to call 'migrate' entry point after the upgrade. Defaults to false
+// All other params are passed to the 'migrate' entry point
+func upgradeContract(ctx iscp.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.upgradeContract.begin")
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	a := assert.NewAssert(ctx.Log())
+
+	hname := params.MustGetHname(root.ParamHname)
+	progHash := params.MustGetHashValue(root.ParamProgramHash)
+	migrate := params.MustGetBool(root.ParamMigrate, false)
+
+	rec, found := root.FindContract(ctx.State(), hname)
+	a.Require(found, "root.upgradeContract.fail: contract %s not found", hname)
+	a.Require(!isCoreContract(rec), "root.upgradeContract.fail: core contract '%s' cannot be upgraded", rec.Name)
+	if !rec.Creator.Equals(ctx.Caller()) && !isChainOwner(a, ctx) {
+		return nil, fmt.Errorf("root.upgradeContract: upgrade of '%s' not permitted for: %s", rec.Name, ctx.Caller())
+	}
+	a.Require(rec.ProgramHash != progHash, "root.upgradeContract.fail: '%s' already runs program %s", rec.Name, progHash)
+
+	// pass to migrate function all params not consumed so far
+	migrateParams := dict.New()
+	for key, value := range ctx.Params() {
+		if key != root.ParamHname && key != root.ParamProgramHash && key != root.ParamMigrate {
+			migrateParams.Set(key, value)
+		}
+	}
+	// call to load VM from binary to check if it loads successfully
+	err := ctx.DeployContract(progHash, "", "", nil)
+	a.Require(err == nil, "root.upgradeContract.fail 1: %v", err)
+
+	prevProgHash := rec.ProgramHash
+	rec.ProgramHash = progHash
+	collections.NewMap(ctx.State(), root.VarContractRegistry).MustSetAt(hname.Bytes(), rec.Bytes())
+
+	history := collections.NewArray32(ctx.State(), root.ContractHistoryArrayName(hname))
+	version := &root.ContractVersion{
+		Version:         history.MustLen() + 1,
+		PrevProgramHash: prevProgHash,
+		ProgramHash:     progHash,
+		UpgradedBy:      ctx.Caller(),
+		Timestamp:       ctx.GetTimestamp(),
+		Migrated:        migrate,
+	}
+	history.MustPush(version.Bytes())
+
+	if migrate {
+		_, err = ctx.Call(hname, iscp.EntryPointMigrate, migrateParams, nil)
+		a.RequireNoError(err)
+	}
+
+	ctx.Event(fmt.Sprintf("[upgrade] name: %s hname: %s, version: %d, progHash: %s, migrated: %v",
+		rec.Name, hname, version.Version, progHash.String(), migrate))
+	return nil, nil
+}
+
+// getContractHistory view returns the versions of the contract recorded by upgradeContract, the oldest first
+// Input:
+// - ParamHname
+// Output:
+// - VarContractHistory array of encoded root.ContractVersion
+func getContractHistory(ctx iscp.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	hname, err := params.GetHname(root.ParamHname)
+	if err != nil {
+		return nil, err
+	}
+	src := collections.NewArray32ReadOnly(ctx.State(), root.ContractHistoryArrayName(hname))
+
+	ret := dict.New()
+	dst := collections.NewArray32(ret, root.VarContractHistory)
+	n := src.MustLen()
+	for i := uint32(0); i < n; i++ {
+		dst.MustPush(src.MustGetAt(i))
+	}
+	return ret, nil
+}
+
 // findContract view finds and returns encoded record of the contract
 // Input:
 // - ParamHname
@@ -57,6 +57,11 @@ func isAuthorizedToDeploy(ctx iscp.Sandbox) bool {
 	return collections.NewMap(ctx.State(), root.VarDeployPermissions).MustHasAt(caller.Bytes())
 }
 
+// isCoreContract checks if the contract was stored during the chain initialization
+func isCoreContract(rec *root.ContractRecord) bool {
+	return !rec.HasCreator() || rec.Creator.Equals(&iscp.NilAgentID)
+}
+
 func isChainOwner(a assert.Assert, ctx iscp.Sandbox) bool {
 	ret, err := ctx.Call(governance.Contract.Hname(), governance.FuncGetChainOwner.Hname(), nil, nil)
 	a.RequireNoError(err)
@@ -14,7 +14,9 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
@@ -209,3 +211,43 @@ func TestGetAccountNonce(t *testing.T) {
 
 	require.GreaterOrEqual(t, getAccountNonce(t, chain, userAddress), nowNanoTs)
 }
+
+func TestEthereumSender(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ethAddress := crypto.PubkeyToAddress(key.PublicKey)
+	ethAgentID := request.AgentIDFromEthereum(ethAddress)
+
+	// fund the Ethereum account from an L1 wallet
+	userWallet, _ := env.NewKeyPairWithFunds()
+	_, err = chain.PostRequestSync(
+		solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name,
+			accounts.ParamAgentID, ethAgentID,
+		).WithIotas(1000),
+		userWallet,
+	)
+	require.NoError(t, err)
+	chain.AssertIotas(ethAgentID, 1000)
+	require.Zero(t, getAccountNonce(t, chain, request.AddressFromEthereum(ethAddress)))
+
+	nowNanoTs := uint64(time.Now().UnixNano())
+
+	_, err = chain.PostRequestOffLedgerSecp256k1(
+		solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name),
+		key,
+	)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, getAccountNonce(t, chain, request.AddressFromEthereum(ethAddress)), nowNanoTs)
+
+	// nobody holds the key of the derived L1 address, so funds can't leave the chain
+	_, err = chain.PostRequestOffLedgerSecp256k1(
+		solo.NewCallParams(accounts.Contract.Name, accounts.FuncWithdraw.Name),
+		key,
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Ethereum account")
+	chain.AssertIotas(ethAgentID, 1000)
+}
@@ -126,7 +126,7 @@ func TestRequestLogRecord(t *testing.T) {
 	a := reqs[0].Bytes()
 	b := receipt.Request.Bytes()
 	require.Equal(t, a, b)
-	require.EqualValues(t, 0, len(receipt.Error))
+	require.Nil(t, receipt.Error)
 	require.EqualValues(t, 2, blockIndex)
 	require.EqualValues(t, 0, requestIndex)
 }
@@ -0,0 +1,192 @@
+package testcore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCouncil(t *testing.T) (*solo.Solo, *solo.Chain, []*ed25519.KeyPair) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+
+	members := make([]*ed25519.KeyPair, 3)
+	council := &governance.Council{
+		Quorum:           2,
+		Timelock:         time.Hour,
+		ProposalLifetime: 24 * time.Hour,
+	}
+	for i := range members {
+		var addr ledgerstate.Address
+		members[i], addr = env.NewKeyPairWithFunds()
+		council.Members = append(council.Members, iscp.NewAgentID(addr, 0))
+	}
+	require.NoError(t, chain.SetCouncil(council, nil))
+	return env, chain, members
+}
+
+func pruningPolicyProposal(blocksToKeep uint32) *governance.ProposalRequest {
+	policy := governance.PruningPolicy{BlocksToKeep: blocksToKeep}
+	return &governance.ProposalRequest{
+		Target:      governance.Contract.Hname(),
+		EntryPoint:  governance.FuncSetPruningPolicy.Hname(),
+		Params:      policy.Params(),
+		Description: "keep less blocks",
+	}
+}
+
+func TestCouncil(t *testing.T) {
+	t.Run("set council", func(t *testing.T) {
+		_, chain, members := setupCouncil(t)
+
+		_, ownerID, _ := chain.GetInfo()
+		require.True(t, ownerID.Equals(governance.CouncilAgentID(chain.ChainID)))
+		council := chain.GetCouncil()
+		require.NotNil(t, council)
+		require.Len(t, council.Members, len(members))
+		require.EqualValues(t, 2, council.Quorum)
+		require.EqualValues(t, time.Hour, council.Timelock)
+		require.EqualValues(t, 24*time.Hour, council.ProposalLifetime)
+
+		// the previous owner can't govern the chain anymore
+		require.Error(t, chain.SetPruningPolicy(governance.PruningPolicy{BlocksToKeep: 5000}, nil))
+		require.Error(t, chain.SetCouncil(council, nil))
+	})
+	t.Run("wrong quorum", func(t *testing.T) {
+		env := solo.New(t, false, false)
+		chain := env.NewChain(nil, "chain1")
+		council := &governance.Council{
+			Members:          []*iscp.AgentID{chain.OriginatorAgentID},
+			Quorum:           2,
+			ProposalLifetime: time.Hour,
+		}
+		require.Error(t, chain.SetCouncil(council, nil))
+		council.Quorum = 1
+		council.Timelock = time.Hour
+		require.Error(t, chain.SetCouncil(council, nil))
+		require.Nil(t, chain.GetCouncil())
+	})
+	t.Run("execute proposal", func(t *testing.T) {
+		env, chain, members := setupCouncil(t)
+
+		_, err := chain.Propose(pruningPolicyProposal(2000), nil)
+		require.Error(t, err)
+
+		id, err := chain.Propose(pruningPolicyProposal(2000), members[0])
+		require.NoError(t, err)
+		proposals := chain.GetProposals()
+		require.Len(t, proposals, 1)
+		require.EqualValues(t, id, proposals[0].ID)
+		require.EqualValues(t, "keep less blocks", proposals[0].Description)
+		require.Len(t, proposals[0].Approvals, 1)
+
+		// not approved by the quorum
+		_, err = chain.ExecuteProposal(id, members[2])
+		require.Error(t, err)
+
+		require.NoError(t, chain.Vote(id, true, members[1]))
+		require.Len(t, chain.GetProposals()[0].Approvals, 2)
+
+		// timelocked
+		_, err = chain.ExecuteProposal(id, members[2])
+		require.Error(t, err)
+
+		env.AdvanceClockBy(time.Hour + time.Second)
+		// anyone can execute the approved proposal
+		userWallet, _ := env.NewKeyPairWithFunds()
+		_, err = chain.ExecuteProposal(id, userWallet)
+		require.NoError(t, err)
+		require.EqualValues(t, 2000, chain.GetPruningPolicy().BlocksToKeep)
+		require.Empty(t, chain.GetProposals())
+
+		_, err = chain.ExecuteProposal(id, members[2])
+		require.Error(t, err)
+	})
+	t.Run("withdrawn approval", func(t *testing.T) {
+		env, chain, members := setupCouncil(t)
+
+		id, err := chain.Propose(pruningPolicyProposal(2000), members[0])
+		require.NoError(t, err)
+		require.NoError(t, chain.Vote(id, true, members[1]))
+		env.AdvanceClockBy(time.Hour + time.Second)
+		require.NoError(t, chain.Vote(id, false, members[1]))
+
+		_, err = chain.ExecuteProposal(id, members[0])
+		require.Error(t, err)
+		require.EqualValues(t, 0, chain.GetPruningPolicy().BlocksToKeep)
+
+		// the timelock starts again from the new approval
+		require.NoError(t, chain.Vote(id, true, members[2]))
+		_, err = chain.ExecuteProposal(id, members[0])
+		require.Error(t, err)
+	})
+	t.Run("late approval", func(t *testing.T) {
+		env, chain, members := setupCouncil(t)
+
+		id, err := chain.Propose(pruningPolicyProposal(2000), members[0])
+		require.NoError(t, err)
+		env.AdvanceClockBy(23 * time.Hour)
+		require.NoError(t, chain.Vote(id, true, members[1]))
+
+		// the proposal doesn't expire before it can be executed
+		env.AdvanceClockBy(time.Hour + time.Second)
+		_, err = chain.ExecuteProposal(id, members[0])
+		require.NoError(t, err)
+		require.EqualValues(t, 2000, chain.GetPruningPolicy().BlocksToKeep)
+	})
+	t.Run("rejected proposal", func(t *testing.T) {
+		_, chain, members := setupCouncil(t)
+
+		id, err := chain.Propose(pruningPolicyProposal(2000), members[0])
+		require.NoError(t, err)
+		require.NoError(t, chain.Vote(id, false, members[1]))
+		require.Len(t, chain.GetProposals(), 1)
+		require.NoError(t, chain.Vote(id, false, members[2]))
+		require.Empty(t, chain.GetProposals())
+
+		_, err = chain.CallView(governance.Contract.Name, governance.FuncGetProposal.Name, governance.ParamProposalID, id)
+		require.Error(t, err)
+	})
+	t.Run("expired proposal", func(t *testing.T) {
+		env, chain, members := setupCouncil(t)
+
+		id, err := chain.Propose(pruningPolicyProposal(2000), members[0])
+		require.NoError(t, err)
+		env.AdvanceClockBy(24 * time.Hour)
+		require.Error(t, chain.Vote(id, true, members[1]))
+		// views see the timestamp of the latest block
+		require.Empty(t, chain.GetProposals())
+	})
+	t.Run("give up ownership", func(t *testing.T) {
+		env, chain, members := setupCouncil(t)
+
+		id, err := chain.Propose(&governance.ProposalRequest{
+			Target:     governance.Contract.Hname(),
+			EntryPoint: governance.FuncDelegateChainOwnership.Hname(),
+			Params:     dict.Dict{governance.ParamChainOwner: codec.EncodeAgentID(chain.OriginatorAgentID)},
+		}, members[0])
+		require.NoError(t, err)
+		require.NoError(t, chain.Vote(id, true, members[2]))
+		env.AdvanceClockBy(time.Hour + time.Second)
+		_, err = chain.ExecuteProposal(id, members[0])
+		require.NoError(t, err)
+
+		_, err = chain.PostRequestSync(
+			solo.NewCallParams(governance.Contract.Name, governance.FuncClaimChainOwnership.Name).WithIotas(1),
+			nil,
+		)
+		require.NoError(t, err)
+		_, ownerID, _ := chain.GetInfo()
+		require.True(t, ownerID.Equals(chain.OriginatorAgentID))
+		require.Nil(t, chain.GetCouncil())
+		require.NoError(t, chain.SetPruningPolicy(governance.PruningPolicy{BlocksToKeep: 5000}, nil))
+	})
+}
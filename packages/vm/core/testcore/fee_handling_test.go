@@ -171,3 +171,39 @@ func TestFeesNotEnough(t *testing.T) {
 	chain.AssertIotas(userAgentID, 0)
 	env.AssertAddressIotas(userAddr, solo.Saldo-7)
 }
+
+func TestPriorityFee(t *testing.T) {
+	env := solo.New(t, false, false)
+	_, validatorAddr := env.NewKeyPairWithFunds()
+	validatorAgentID := iscp.NewAgentID(validatorAddr, 0)
+	chain := env.NewChain(nil, "chain1", validatorAgentID)
+
+	user, userAddr := env.NewKeyPairWithFunds()
+
+	req := solo.NewCallParams(blob.Contract.Name, blob.FuncStoreBlob.Name, "par1", []byte("data1")).
+		WithIotas(7).
+		WithPriorityFee(3)
+	_, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+
+	chain.AssertIotas(validatorAgentID, 3)
+	env.AssertAddressIotas(userAddr, solo.Saldo-7)
+}
+
+func TestPriorityFeeNotEnough(t *testing.T) {
+	env := solo.New(t, false, false)
+	_, validatorAddr := env.NewKeyPairWithFunds()
+	validatorAgentID := iscp.NewAgentID(validatorAddr, 0)
+	chain := env.NewChain(nil, "chain1", validatorAgentID)
+
+	user, _ := env.NewKeyPairWithFunds()
+
+	req := solo.NewCallParams(blob.Contract.Name, blob.FuncStoreBlob.Name, "par1", []byte("data1")).
+		WithIotas(2).
+		WithPriorityFee(5)
+	_, err := chain.PostRequestSync(req, user)
+	require.Error(t, err)
+
+	// whatever was transferred is taken
+	chain.AssertIotas(validatorAgentID, 2)
+}
@@ -4,7 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/vm/core"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
@@ -181,6 +184,12 @@ func TestAccessNodes(t *testing.T) {
 	require.Equal(t, "http://my-api/url", getChainNodesResponse.AccessNodeCandidates[0].AccessAPI)
 	require.Equal(t, 1, len(getChainNodesResponse.AccessNodes))
 
+	// the chain reads the access nodes directly from the state
+	govState := subrealm.NewReadOnly(chain.State.KVStoreReader(), kv.Key(governance.Contract.Hname().Bytes()))
+	accessNodes, err := governance.GetAccessNodes(govState)
+	require.NoError(t, err)
+	require.EqualValues(t, []ed25519.PublicKey{node1KP.PublicKey}, accessNodes)
+
 	//
 	// Revoke the access node (by the node owner).
 	_, err = chain.PostRequestSync(
@@ -204,4 +213,9 @@ func TestAccessNodes(t *testing.T) {
 	getChainNodesResponse = governance.NewGetChainNodesResponseFromDict(res)
 	require.Empty(t, getChainNodesResponse.AccessNodeCandidates)
 	require.Empty(t, getChainNodesResponse.AccessNodes)
+
+	govState = subrealm.NewReadOnly(chain.State.KVStoreReader(), kv.Key(governance.Contract.Hname().Bytes()))
+	accessNodes, err = governance.GetAccessNodes(govState)
+	require.NoError(t, err)
+	require.Empty(t, accessNodes)
 }
@@ -0,0 +1,42 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestCallViewAtBlock(t *testing.T) {
+	env := solo.New(t, false, false).WithNativeContract(inccounter.Processor)
+	chain := env.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, "counter", inccounter.Contract.ProgramHash, inccounter.VarCounter, 0)
+	require.NoError(t, err)
+
+	deployedAt := chain.State.BlockIndex()
+	for i := 0; i < 3; i++ {
+		_, err = chain.PostRequestSync(solo.NewCallParams("counter", inccounter.FuncIncCounter.Name).WithIotas(1), nil)
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, deployedAt+3, chain.State.BlockIndex())
+
+	for i := uint32(0); i <= 3; i++ {
+		res, err := chain.CallViewAtBlock(deployedAt+i, "counter", inccounter.FuncGetCounter.Name)
+		require.NoError(t, err)
+		counter, err := codec.DecodeInt64(res.MustGet(inccounter.VarCounter), 0)
+		require.NoError(t, err)
+		require.EqualValues(t, i, counter)
+	}
+
+	// the contract doesn't exist before it is deployed
+	_, err = chain.CallViewAtBlock(deployedAt-1, "counter", inccounter.FuncGetCounter.Name)
+	require.Error(t, err)
+	// the future is not known
+	_, err = chain.CallViewAtBlock(deployedAt+4, "counter", inccounter.FuncGetCounter.Name)
+	require.Error(t, err)
+	require.False(t, xerrors.Is(err, state.ErrStateNotRetained))
+}
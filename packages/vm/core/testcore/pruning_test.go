@@ -0,0 +1,79 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruningPolicy(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+
+	require.EqualValues(t, governance.PruningPolicy{}, chain.GetPruningPolicy())
+
+	err := chain.SetPruningPolicy(governance.PruningPolicy{BlocksToKeep: 5000, ReceiptsToKeep: 1}, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, governance.PruningPolicy{
+		BlocksToKeep:   5000,
+		ReceiptsToKeep: governance.MinReceiptsToKeep,
+	}, chain.GetPruningPolicy())
+
+	// only the chain owner can change the policy
+	user, _ := env.NewKeyPairWithFunds()
+	err = chain.SetPruningPolicy(governance.PruningPolicy{}, user)
+	require.Error(t, err)
+	require.EqualValues(t, governance.MinReceiptsToKeep, chain.GetPruningPolicy().ReceiptsToKeep)
+}
+
+func TestPruneReceipts(t *testing.T) {
+	env := solo.New(t, false, false).WithNativeContract(inccounter.Processor)
+	chain := env.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, "counter", inccounter.Contract.ProgramHash, inccounter.VarCounter, 0)
+	require.NoError(t, err)
+	err = chain.SetPruningPolicy(governance.PruningPolicy{ReceiptsToKeep: governance.MinReceiptsToKeep}, nil)
+	require.NoError(t, err)
+
+	// the receipt of the older off-ledger request is not needed for the replay protection anymore
+	user, _ := env.NewKeyPairWithFunds()
+	_, err = chain.PostRequestSync(solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name).WithIotas(10), user)
+	require.NoError(t, err)
+	_, err = chain.PostRequestSync(solo.NewCallParams("counter", inccounter.FuncIncCounter.Name).WithIotas(1), nil)
+	require.NoError(t, err)
+	firstBlock := chain.State.BlockIndex()
+	onLedger := chain.GetRequestIDsForBlock(firstBlock)[0]
+	events, err := chain.GetEventsForBlock(firstBlock)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+
+	_, err = chain.PostRequestOffLedger(solo.NewCallParams("counter", inccounter.FuncIncCounter.Name), user)
+	require.NoError(t, err)
+	offLedgerOld := chain.GetRequestIDsForBlock(chain.State.BlockIndex())[0]
+	_, err = chain.PostRequestOffLedger(solo.NewCallParams("counter", inccounter.FuncIncCounter.Name), user)
+	require.NoError(t, err)
+	offLedgerLast := chain.GetRequestIDsForBlock(chain.State.BlockIndex())[0]
+
+	for i := uint32(0); i < governance.MinReceiptsToKeep; i++ {
+		_, err = chain.PostRequestSync(solo.NewCallParams("counter", inccounter.FuncIncCounter.Name).WithIotas(1), nil)
+		require.NoError(t, err)
+	}
+	lastBlock := chain.State.BlockIndex()
+
+	require.False(t, chain.IsRequestProcessed(offLedgerOld))
+	require.True(t, chain.IsRequestProcessed(offLedgerLast))
+	require.False(t, chain.IsRequestProcessed(onLedger))
+	require.Empty(t, chain.GetRequestReceiptsForBlock(firstBlock))
+	events, err = chain.GetEventsForBlock(firstBlock)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// the retained blocks are intact
+	require.Len(t, chain.GetRequestReceiptsForBlock(lastBlock-governance.MinReceiptsToKeep+1), 1)
+	events, err = chain.GetEventsForContract("counter")
+	require.NoError(t, err)
+	require.Len(t, events, int(governance.MinReceiptsToKeep))
+}
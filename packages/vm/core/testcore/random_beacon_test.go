@@ -0,0 +1,65 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/bls"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	randomSeedContract = coreutil.NewContract("RandomSeedContract", "random seed contract")
+	funcGetRandomSeed  = coreutil.Func("getRandomSeed")
+
+	randomSeedContractProcessor = randomSeedContract.Processor(nil,
+		funcGetRandomSeed.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+			return dict.Dict{"seed": codec.EncodeHashValue(ctx.GetRandomSeed())}, nil
+		}),
+	)
+)
+
+func TestRandomBeacon(t *testing.T) {
+	env := solo.New(t, false, false).WithNativeContract(randomSeedContractProcessor)
+	ch := env.NewChain(nil, "ch")
+	err := ch.DeployContract(nil, randomSeedContract.Name, randomSeedContract.ProgramHash)
+	require.NoError(t, err)
+
+	seeds := make(map[hashing.HashValue]bool)
+	for i := 0; i < 3; i++ {
+		res, err := ch.PostRequestSync(solo.NewCallParams(randomSeedContract.Name, funcGetRandomSeed.Name).WithIotas(1), nil)
+		require.NoError(t, err)
+		seed, err := codec.DecodeHashValue(res.MustGet("seed"))
+		require.NoError(t, err)
+
+		blockIndex := ch.State.BlockIndex()
+		beacon, beaconSeed, stateAddr, err := ch.GetRandomBeacon(blockIndex)
+		require.NoError(t, err)
+		require.EqualValues(t, beaconSeed, seed)
+		require.True(t, stateAddr.Equals(ch.StateControllerAddress))
+		require.False(t, seeds[seed])
+		seeds[seed] = true
+
+		// in Solo the beacon is signed by the simulated committee key, not by the state controller
+		sig, _, err := bls.SignatureWithPublicKeyFromBytes(beacon)
+		require.NoError(t, err)
+		require.EqualValues(t, ch.RandomBeaconKey.PublicKey().Bytes(), sig.PublicKey.Bytes())
+		require.True(t, sig.IsValid(blocklog.RandomBeaconMessage(ch.ChainID, blockIndex)))
+		require.Error(t, blocklog.VerifyRandomBeacon(beacon, ch.ChainID, blockIndex, stateAddr))
+		blsAddr := ledgerstate.NewBLSAddress(ch.RandomBeaconKey.PublicKey().Bytes())
+		require.NoError(t, blocklog.VerifyRandomBeacon(beacon, ch.ChainID, blockIndex, blsAddr))
+		require.Error(t, blocklog.VerifyRandomBeacon(beacon, ch.ChainID, blockIndex+1, blsAddr))
+	}
+	// the view defaults to the latest block
+	_, err = ch.CallView(blocklog.Contract.Name, blocklog.FuncGetRandomBeacon.Name)
+	require.NoError(t, err)
+	_, _, _, err = ch.GetRandomBeacon(ch.State.BlockIndex() + 1)
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package sbtests
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+	"github.com/stretchr/testify/require"
+)
+
+func lastReceipt(t *testing.T, chain *solo.Chain) *blocklog.RequestReceipt {
+	recs := chain.GetRequestReceiptsForBlock(chain.GetLatestBlockInfo().BlockIndex)
+	require.EqualValues(t, 1, len(recs))
+	return recs[0]
+}
+
+func callFibonacciIndirect(chain *solo.Chain, gasBudget uint64) error {
+	req := solo.NewCallParams(ScName, sbtestsc.FuncCallOnChain.Name,
+		sbtestsc.ParamIntParamValue, n,
+		sbtestsc.ParamHnameContract, HScName,
+		sbtestsc.ParamHnameEP, sbtestsc.FuncGetFibonacci.Hname())
+	_, err := chain.PostRequestSync(req.WithIotas(1).WithGasBudget(gasBudget), nil)
+	return err
+}
+
+func TestGasUsed(t *testing.T) { run2(t, testGasUsed) }
+func testGasUsed(t *testing.T, w bool) {
+	var gasUsed [2]uint64
+	for i := range gasUsed {
+		_, chain := setupChain(t, nil)
+		setupTestSandboxSC(t, chain, nil, w)
+
+		err := callFibonacciIndirect(chain, 0)
+		require.NoError(t, err)
+		rec := lastReceipt(t, chain)
+		require.Nil(t, rec.Error)
+		require.Greater(t, rec.GasUsed, gas.Call)
+		gasUsed[i] = rec.GasUsed
+	}
+	// metering is deterministic
+	require.EqualValues(t, gasUsed[0], gasUsed[1])
+}
+
+func TestGasBudgetExceeded(t *testing.T) { run2(t, testGasBudgetExceeded) }
+func testGasBudgetExceeded(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	err := callFibonacciIndirect(chain, gas.Call)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), gas.ErrNotEnoughGas.Error())
+	rec := lastReceipt(t, chain)
+	require.EqualValues(t, gas.Call, rec.GasUsed)
+
+	// state updates of the request are rolled back
+	ret, err := chain.CallView(ScName, sbtestsc.FuncGetCounter.Name)
+	require.NoError(t, err)
+	r, err := codec.DecodeInt64(ret.MustGet(sbtestsc.VarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, r)
+}
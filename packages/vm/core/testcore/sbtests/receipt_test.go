@@ -0,0 +1,94 @@
+package sbtests
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sbtests/sbtestsc"
+	"github.com/iotaledger/wasp/packages/vm/gas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptResult(t *testing.T) { run2(t, testReceiptResult) }
+func testReceiptResult(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(ScName, sbtestsc.FuncCallOnChain.Name,
+		sbtestsc.ParamIntParamValue, n,
+		sbtestsc.ParamHnameContract, HScName,
+		sbtestsc.ParamHnameEP, sbtestsc.FuncGetFibonacci.Hname())
+	ret, err := chain.PostRequestSync(req.WithIotas(1), nil)
+	require.NoError(t, err)
+
+	rec := lastReceipt(t, chain)
+	require.Nil(t, rec.Error)
+	require.NotEmpty(t, rec.Result)
+	require.EqualValues(t, ret, rec.Result)
+}
+
+func TestReceiptPanic(t *testing.T) { run2(t, testReceiptPanic) }
+func testReceiptPanic(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(ScName, sbtestsc.FuncPanicFullEP.Name)
+	_, err := chain.PostRequestSync(req.WithIotas(1), nil)
+	require.Error(t, err)
+
+	rec := lastReceipt(t, chain)
+	require.NotNil(t, rec.Error)
+	require.EqualValues(t, iscp.RequestErrorPanic, rec.Error.Code)
+	require.EqualValues(t, HScName, rec.Error.Contract)
+	require.Contains(t, rec.Error.Message, sbtestsc.MsgFullPanic)
+	require.Empty(t, rec.Result)
+}
+
+func TestReceiptNotEnoughGas(t *testing.T) { run2(t, testReceiptNotEnoughGas) }
+func testReceiptNotEnoughGas(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	err := callFibonacciIndirect(chain, gas.Call)
+	require.Error(t, err)
+
+	rec := lastReceipt(t, chain)
+	require.NotNil(t, rec.Error)
+	require.EqualValues(t, iscp.RequestErrorNotEnoughGas, rec.Error.Code)
+}
+
+func TestReceiptFee(t *testing.T) { run2(t, testReceiptFee) }
+func testReceiptFee(t *testing.T, w bool) {
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil, w)
+
+	req := solo.NewCallParams(governance.Contract.Name, governance.FuncSetContractFee.Name,
+		governance.ParamHname, HScName,
+		governance.ParamOwnerFee, 5,
+	)
+	_, err := chain.PostRequestSync(req.WithIotas(1), nil)
+	require.NoError(t, err)
+
+	// the chain owner does not pay fees
+	user, _ := chain.Env.NewKeyPairWithFunds()
+	req = solo.NewCallParams(ScName, sbtestsc.FuncDoNothing.Name)
+	_, err = chain.PostRequestSync(req.WithIotas(10), user)
+	require.NoError(t, err)
+
+	rec := lastReceipt(t, chain)
+	require.Nil(t, rec.Error)
+	require.EqualValues(t, 5, rec.Fee)
+	require.EqualValues(t, colored.IOTA, rec.FeeColor)
+
+	req = solo.NewCallParams(ScName, sbtestsc.FuncDoNothing.Name)
+	_, err = chain.PostRequestSync(req.WithIotas(1), user)
+	require.Error(t, err)
+
+	rec = lastReceipt(t, chain)
+	require.NotNil(t, rec.Error)
+	require.EqualValues(t, iscp.RequestErrorNotEnoughFees, rec.Error.Code)
+	require.EqualValues(t, 1, rec.Fee)
+}
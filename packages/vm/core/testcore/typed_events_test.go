@@ -0,0 +1,161 @@
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	paramTokenOwner  = "owner"
+	paramTokenAmount = "amount"
+	paramTokenCount  = "count"
+)
+
+var (
+	typedEventsContract = coreutil.NewContract("TypedEventsContract", "typed events contract")
+
+	funcTransfer = coreutil.Func("transfer")
+	funcTooMany  = coreutil.Func("tooManyTopics")
+
+	typedEventsContractProcessor = typedEventsContract.Processor(nil,
+		funcTransfer.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+			params := kvdecoder.New(ctx.Params(), ctx.Log())
+			owner := params.MustGetString(paramTokenOwner)
+			amount := params.MustGetInt64(paramTokenAmount)
+			count := params.MustGetInt64(paramTokenCount, 1)
+			for i := int64(0); i < count; i++ {
+				ctx.EmitEvent(iscp.NewEvent("transfer").
+					WithIndexedField(paramTokenOwner, "String", codec.EncodeString(owner)).
+					WithField(paramTokenAmount, "Int64", codec.EncodeInt64(amount+i)))
+			}
+			ctx.EmitEvent(iscp.NewEvent("done"))
+			return nil, nil
+		}),
+		funcTooMany.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+			ev := iscp.NewEvent("tooMany")
+			for i := 0; i <= iscp.MaxEventTopics; i++ {
+				ev.WithIndexedField(fmt.Sprintf("f%d", i), "Int64", codec.EncodeInt64(int64(i)))
+			}
+			ctx.EmitEvent(ev)
+			return nil, nil
+		}),
+	)
+)
+
+func setupTypedEvents(t *testing.T) *solo.Chain {
+	env := solo.New(t, false, false).WithNativeContract(typedEventsContractProcessor)
+	ch := env.NewChain(nil, "ch")
+	err := ch.DeployContract(nil, typedEventsContract.Name, typedEventsContract.ProgramHash)
+	require.NoError(t, err)
+	return ch
+}
+
+func postTransfer(t *testing.T, ch *solo.Chain, owner string, amount, count int64) {
+	req := solo.NewCallParams(typedEventsContract.Name, funcTransfer.Name,
+		paramTokenOwner, owner,
+		paramTokenAmount, amount,
+		paramTokenCount, count,
+	)
+	_, err := ch.PostRequestSync(req.WithIotas(1), nil)
+	require.NoError(t, err)
+}
+
+func TestTypedEvents(t *testing.T) {
+	ch := setupTypedEvents(t)
+	postTransfer(t, ch, "alice", 10, 1)
+	postTransfer(t, ch, "bob", 20, 1)
+	postTransfer(t, ch, "alice", 30, 1)
+
+	contract := iscp.Hn(typedEventsContract.Name)
+	events, cursor, err := ch.GetTypedEvents(&blocklog.TypedEventsQuery{Contract: contract})
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+	require.Len(t, events, 6)
+
+	events, _, err = ch.GetTypedEvents(&blocklog.TypedEventsQuery{Contract: contract, EventName: "transfer"})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	events, _, err = ch.GetTypedEvents(&blocklog.TypedEventsQuery{
+		Contract:  contract,
+		EventName: "transfer",
+		Topics:    [][]byte{codec.EncodeString("alice")},
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.EqualValues(t, contract, events[0].Contract)
+	require.EqualValues(t, "transfer", events[0].Event.Name)
+	amount, err := codec.DecodeInt64(events[1].Event.Field(paramTokenAmount).Value)
+	require.NoError(t, err)
+	require.EqualValues(t, 30, amount)
+	require.Greater(t, events[1].Key.BlockIndex(), events[0].Key.BlockIndex())
+
+	// the block range applies
+	events, _, err = ch.GetTypedEvents(&blocklog.TypedEventsQuery{
+		Contract:  contract,
+		EventName: "transfer",
+		Topics:    [][]byte{codec.EncodeString("alice")},
+		FromBlock: events[1].Key.BlockIndex(),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// the typed events are also returned as text, with the timestamp of the legacy events
+	texts, err := ch.GetEventsForContract(typedEventsContract.Name)
+	require.NoError(t, err)
+	require.Len(t, texts, 6)
+	require.Regexp(t, `transfer\|\d+\|alice\|10`, texts[0])
+
+	// topics require the event name
+	_, _, err = ch.GetTypedEvents(&blocklog.TypedEventsQuery{Contract: contract, Topics: [][]byte{codec.EncodeString("alice")}})
+	require.Error(t, err)
+}
+
+func TestTypedEventsPagination(t *testing.T) {
+	ch := setupTypedEvents(t)
+	postTransfer(t, ch, "alice", 0, 25)
+
+	contract := iscp.Hn(typedEventsContract.Name)
+	q := &blocklog.TypedEventsQuery{Contract: contract, EventName: "transfer", Limit: 10}
+	amounts := make([]int64, 0)
+	pages := 0
+	for {
+		events, cursor, err := ch.GetTypedEvents(q)
+		require.NoError(t, err)
+		pages++
+		for _, ev := range events {
+			amount, err := codec.DecodeInt64(ev.Event.Field(paramTokenAmount).Value)
+			require.NoError(t, err)
+			amounts = append(amounts, amount)
+		}
+		if cursor == nil {
+			break
+		}
+		q.Cursor = cursor
+	}
+	require.EqualValues(t, 3, pages)
+	require.Len(t, amounts, 25)
+	for i, a := range amounts {
+		require.EqualValues(t, i, a)
+	}
+}
+
+func TestTypedEventsTooManyTopics(t *testing.T) {
+	ch := setupTypedEvents(t)
+	req := solo.NewCallParams(typedEventsContract.Name, funcTooMany.Name)
+	_, err := ch.PostRequestSync(req.WithIotas(1), nil)
+	require.Error(t, err)
+
+	events, _, err := ch.GetTypedEvents(&blocklog.TypedEventsQuery{Contract: iscp.Hn(typedEventsContract.Name)})
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+}
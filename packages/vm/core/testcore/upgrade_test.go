@@ -0,0 +1,168 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+const paramFactor = "factor"
+
+// incCounterV2 is an upgrade of the inccounter contract: it increments by 10 and
+// multiplies the counter by 'factor' during the migration
+var incCounterV2 = coreutil.NewContract("inccounterV2", "Increment counter, version 2")
+
+var funcMigrate = coreutil.Func(iscp.FuncMigrate)
+
+var incCounterV2Processor = incCounterV2.Processor(nil,
+	inccounter.FuncIncCounter.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+		state := kvdecoder.New(ctx.State(), ctx.Log())
+		ctx.State().Set(inccounter.VarCounter, codec.EncodeInt64(state.MustGetInt64(inccounter.VarCounter, 0)+10))
+		return nil, nil
+	}),
+	inccounter.FuncGetCounter.WithHandler(func(ctx iscp.SandboxView) (dict.Dict, error) {
+		ret := dict.New()
+		ret.Set(inccounter.VarCounter, ctx.State().MustGet(inccounter.VarCounter))
+		return ret, nil
+	}),
+	funcMigrate.WithHandler(func(ctx iscp.Sandbox) (dict.Dict, error) {
+		if ctx.Caller().Hname() != root.Contract.Hname() {
+			return nil, fmt.Errorf("migrate can only be called by root")
+		}
+		params := kvdecoder.New(ctx.Params(), ctx.Log())
+		factor := params.MustGetInt64(paramFactor, 1)
+		if factor == 0 {
+			return nil, fmt.Errorf("factor cannot be 0")
+		}
+		state := kvdecoder.New(ctx.State(), ctx.Log())
+		ctx.State().Set(inccounter.VarCounter, codec.EncodeInt64(state.MustGetInt64(inccounter.VarCounter, 0)*factor))
+		return nil, nil
+	}),
+)
+
+func setupUpgrade(t *testing.T) (*solo.Solo, *solo.Chain) {
+	env := solo.New(t, false, false).
+		WithNativeContract(inccounter.Processor).
+		WithNativeContract(incCounterV2Processor)
+	chain := env.NewChain(nil, "chain1")
+
+	err := chain.DeployContract(nil, "counter", inccounter.Contract.ProgramHash, inccounter.VarCounter, 5)
+	require.NoError(t, err)
+	return env, chain
+}
+
+func checkCounter(t *testing.T, chain *solo.Chain, expected int64) {
+	res, err := chain.CallView("counter", inccounter.FuncGetCounter.Name)
+	require.NoError(t, err)
+	counter, err := codec.DecodeInt64(res.MustGet(inccounter.VarCounter), 0)
+	require.NoError(t, err)
+	require.EqualValues(t, expected, counter)
+}
+
+func TestUpgradeContract(t *testing.T) {
+	_, chain := setupUpgrade(t)
+
+	err := chain.UpgradeContract(nil, "counter", incCounterV2.ProgramHash, true, paramFactor, 2)
+	require.NoError(t, err)
+
+	// the state is kept and migrated
+	checkCounter(t, chain, 10)
+	rec, err := chain.FindContract("counter")
+	require.NoError(t, err)
+	require.EqualValues(t, incCounterV2.ProgramHash, rec.ProgramHash)
+	require.True(t, chain.OriginatorAgentID.Equals(rec.Creator))
+
+	// the new program is running
+	_, err = chain.PostRequestSync(solo.NewCallParams("counter", inccounter.FuncIncCounter.Name).WithIotas(1), nil)
+	require.NoError(t, err)
+	checkCounter(t, chain, 20)
+
+	history, err := chain.GetContractHistory("counter")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.EqualValues(t, 1, history[0].Version)
+	require.EqualValues(t, inccounter.Contract.ProgramHash, history[0].PrevProgramHash)
+	require.EqualValues(t, incCounterV2.ProgramHash, history[0].ProgramHash)
+	require.True(t, chain.OriginatorAgentID.Equals(history[0].UpgradedBy))
+	require.True(t, history[0].Migrated)
+
+	// back to the first version, without migration
+	err = chain.UpgradeContract(nil, "counter", inccounter.Contract.ProgramHash, false)
+	require.NoError(t, err)
+	checkCounter(t, chain, 20)
+
+	history, err = chain.GetContractHistory("counter")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.EqualValues(t, 2, history[1].Version)
+	require.False(t, history[1].Migrated)
+}
+
+func TestUpgradeMigrationFails(t *testing.T) {
+	_, chain := setupUpgrade(t)
+
+	err := chain.UpgradeContract(nil, "counter", incCounterV2.ProgramHash, true, paramFactor, 0)
+	require.Error(t, err)
+
+	// the upgrade is reverted
+	checkCounter(t, chain, 5)
+	rec, err := chain.FindContract("counter")
+	require.NoError(t, err)
+	require.EqualValues(t, inccounter.Contract.ProgramHash, rec.ProgramHash)
+	history, err := chain.GetContractHistory("counter")
+	require.NoError(t, err)
+	require.Len(t, history, 0)
+}
+
+func TestUpgradeByCreator(t *testing.T) {
+	env, chain := setupUpgrade(t)
+	user1, addr1 := env.NewKeyPairWithFunds()
+	user2, _ := env.NewKeyPairWithFunds()
+
+	req := solo.NewCallParams(root.Contract.Name, root.FuncGrantDeployPermission.Name,
+		root.ParamDeployer, iscp.NewAgentID(addr1, 0),
+	)
+	_, err := chain.PostRequestSync(req.WithIotas(1), nil)
+	require.NoError(t, err)
+	err = chain.DeployContract(user1, "counter1", inccounter.Contract.ProgramHash)
+	require.NoError(t, err)
+
+	// neither the creator of another contract nor a stranger can upgrade
+	err = chain.UpgradeContract(user1, "counter", incCounterV2.ProgramHash, false)
+	require.Error(t, err)
+	err = chain.UpgradeContract(user2, "counter1", incCounterV2.ProgramHash, false)
+	require.Error(t, err)
+
+	err = chain.UpgradeContract(user1, "counter1", incCounterV2.ProgramHash, false)
+	require.NoError(t, err)
+	// the chain owner can upgrade any contract
+	err = chain.UpgradeContract(nil, "counter1", inccounter.Contract.ProgramHash, false)
+	require.NoError(t, err)
+
+	history, err := chain.GetContractHistory("counter1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.True(t, iscp.NewAgentID(addr1, 0).Equals(history[0].UpgradedBy))
+	require.True(t, chain.OriginatorAgentID.Equals(history[1].UpgradedBy))
+}
+
+func TestUpgradeCoreContract(t *testing.T) {
+	env := solo.New(t, false, false).WithNativeContract(incCounterV2Processor)
+	chain := env.NewChain(nil, "chain1")
+
+	err := chain.UpgradeContract(nil, blob.Contract.Name, incCounterV2.ProgramHash, false)
+	require.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/stretchr/testify/require"
+)
+
+func setupValidatorRewards(t *testing.T) (*solo.Solo, *solo.Chain, []*ed25519.KeyPair) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+
+	req := solo.NewCallParams(governance.Contract.Name, governance.FuncSetContractFee.Name,
+		governance.ParamHname, accounts.Contract.Hname(),
+		governance.ParamValidatorFee, 10,
+	).WithIotas(1)
+	_, err := chain.PostRequestSync(req, nil)
+	require.NoError(t, err)
+
+	nodes := make([]*ed25519.KeyPair, 2)
+	for i := range nodes {
+		nodes[i], _ = env.NewKeyPairWithFunds()
+	}
+	chain.ValidatorParticipation = []*governance.ValidatorParticipation{
+		{NodePubKey: nodes[0].PublicKey, Weight: 1},
+		{NodePubKey: nodes[1].PublicKey, Weight: 3},
+	}
+	return env, chain, nodes
+}
+
+func depositWithFee(t *testing.T, chain *solo.Chain, user *ed25519.KeyPair) {
+	req := solo.NewCallParams(accounts.Contract.Name, accounts.FuncDeposit.Name).WithIotas(100)
+	_, err := chain.PostRequestSync(req, user)
+	require.NoError(t, err)
+}
+
+func TestValidatorRewards(t *testing.T) {
+	t.Run("split by weight", func(t *testing.T) {
+		env, chain, nodes := setupValidatorRewards(t)
+		user, _ := env.NewKeyPairWithFunds()
+
+		depositWithFee(t, chain, user)
+		rewards, payout := chain.GetValidatorRewards(nodes[0].PublicKey)
+		require.EqualValues(t, 2, rewards.Get(colored.IOTA))
+		require.Nil(t, payout)
+		rewards, _ = chain.GetValidatorRewards(nodes[1].PublicKey)
+		require.EqualValues(t, 7, rewards.Get(colored.IOTA))
+
+		// the remainder of the previous block is distributed with the fees of the next one
+		depositWithFee(t, chain, user)
+		rewards, _ = chain.GetValidatorRewards(nodes[0].PublicKey)
+		require.EqualValues(t, 4, rewards.Get(colored.IOTA))
+		rewards, _ = chain.GetValidatorRewards(nodes[1].PublicKey)
+		require.EqualValues(t, 15, rewards.Get(colored.IOTA))
+		chain.AssertIotas(governance.ValidatorRewardsAccount(chain.ChainID), 20)
+	})
+	t.Run("withdraw to payout address", func(t *testing.T) {
+		env, chain, nodes := setupValidatorRewards(t)
+		user, _ := env.NewKeyPairWithFunds()
+		depositWithFee(t, chain, user)
+
+		payoutKeyPair, payoutAddr := env.NewKeyPairWithFunds()
+		payoutAgentID := iscp.NewAgentID(payoutAddr, 0)
+		require.NoError(t, chain.SetPayoutAddress(payoutAgentID, nodes[1]))
+		_, payout := chain.GetValidatorRewards(nodes[1].PublicKey)
+		require.True(t, payoutAgentID.Equals(payout))
+
+		require.NoError(t, chain.WithdrawValidatorRewards(nodes[1].PublicKey, payoutKeyPair))
+		rewards, _ := chain.GetValidatorRewards(nodes[1].PublicKey)
+		require.True(t, rewards.IsEmpty())
+		chain.AssertIotas(payoutAgentID, 7)
+		chain.AssertIotas(governance.ValidatorRewardsAccount(chain.ChainID), 3)
+	})
+	t.Run("withdraw to node", func(t *testing.T) {
+		env, chain, nodes := setupValidatorRewards(t)
+		user, _ := env.NewKeyPairWithFunds()
+		depositWithFee(t, chain, user)
+
+		require.NoError(t, chain.WithdrawValidatorRewards(nodes[0].PublicKey, nodes[0]))
+		chain.AssertIotas(governance.NodeAgentID(nodes[0].PublicKey), 2)
+	})
+	t.Run("not authorized", func(t *testing.T) {
+		env, chain, nodes := setupValidatorRewards(t)
+		user, userAddr := env.NewKeyPairWithFunds()
+		depositWithFee(t, chain, user)
+
+		req := solo.NewCallParams(governance.Contract.Name, governance.FuncSetPayoutAddress.Name,
+			governance.ParamNodePubKey, nodes[1].PublicKey.Bytes(),
+			governance.ParamPayoutAddress, iscp.NewAgentID(userAddr, 0),
+		).WithIotas(1)
+		_, err := chain.PostRequestSync(req, user)
+		require.Error(t, err)
+		require.Error(t, chain.WithdrawValidatorRewards(nodes[1].PublicKey, user))
+		rewards, payout := chain.GetValidatorRewards(nodes[1].PublicKey)
+		require.EqualValues(t, 7, rewards.Get(colored.IOTA))
+		require.Nil(t, payout)
+
+		// the rewards account can't be spent directly. The fee of the request is added to the rewards
+		req = solo.NewCallParams(accounts.Contract.Name, accounts.FuncPayoutValidatorRewards.Name,
+			accounts.ParamAgentID, iscp.NewAgentID(userAddr, 0),
+			accounts.ParamRewards, colored.NewBalancesForIotas(7).Bytes(),
+		).WithIotas(11)
+		_, err = chain.PostRequestSync(req, user)
+		require.Error(t, err)
+		chain.AssertIotas(governance.ValidatorRewardsAccount(chain.ChainID), 20)
+	})
+	t.Run("council can't move the rewards", func(t *testing.T) {
+		_, chain, members := setupCouncil(t)
+		_, err := chain.Propose(&governance.ProposalRequest{
+			Target:     accounts.Contract.Hname(),
+			EntryPoint: accounts.FuncPayoutValidatorRewards.Hname(),
+			Params: dict.Dict{
+				accounts.ParamAgentID: codec.EncodeAgentID(governance.NodeAgentID(members[0].PublicKey)),
+				accounts.ParamRewards: colored.NewBalancesForIotas(1).Bytes(),
+			},
+		}, members[0])
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gas defines the deterministic cost of the execution of smart contracts.
+// Each request is run with the gas budget. Wasm code is charged per executed instruction,
+// host calls and sandbox calls of both Wasm and native contracts are charged per call and per byte
+package gas
+
+import "golang.org/x/xerrors"
+
+const (
+	// MaxGasPerRequest is the budget of the request which does not specify the budget
+	// and the upper limit of the budget of any request
+	MaxGasPerRequest = uint64(100_000_000)
+	// MaxGasPerView is the budget of the view call
+	MaxGasPerView = uint64(10_000_000)
+
+	// WasmInstruction is the cost of each executed Wasm instruction
+	WasmInstruction = uint64(1)
+	// WasmHostCall is the cost of each call from the Wasm code to the host
+	WasmHostCall = uint64(10)
+
+	// StorageRead is the cost of reading or checking existence of the key in the state
+	StorageRead = uint64(100)
+	// StorageWrite is the cost of setting or deleting the key in the state
+	StorageWrite = uint64(500)
+	// StoragePerByte is the cost of each byte of the key and the value written to the state
+	StoragePerByte = uint64(10)
+
+	// Call is the cost of the call of another smart contract
+	Call = uint64(1000)
+	// Deploy is the cost of the deployment of the smart contract
+	Deploy = uint64(10_000)
+	// Send is the cost of sending tokens or posting the request to L1
+	Send = uint64(2000)
+	// Event is the cost of the event. Each byte of the event is charged by EventPerByte
+	Event = uint64(200)
+	// EventPerByte is the cost of each byte of the event
+	EventPerByte = uint64(10)
+	// EventTopic is the cost of indexing each indexed field of the typed event
+	EventTopic = uint64(500)
+)
+
+// ErrNotEnoughGas is the reason of the panic when the gas budget is exceeded
+var ErrNotEnoughGas = xerrors.New("gas budget exceeded")
+
+// BudgetOrDefault returns the effective gas budget for the requested one. 0 means the default budget
+func BudgetOrDefault(budget uint64) uint64 {
+	if budget == 0 || budget > MaxGasPerRequest {
+		return MaxGasPerRequest
+	}
+	return budget
+}
@@ -0,0 +1,553 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package gas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"golang.org/x/xerrors"
+)
+
+// Wasm code is metered by instrumentation of the binary module before it is loaded by the Wasm VM,
+// so metering is deterministic and does not depend on the VM implementation.
+// The module gets a new mutable i64 global, exported as WasmGasGlobal, which holds the gas left.
+// The code of each function is split into linear segments at control flow instructions and each
+// segment is prefixed by the code which subtracts the cost of the segment from the global.
+// If the gas left is not enough for the segment, the global is set to WasmGasExhausted and the code traps
+
+const (
+	// WasmGasGlobal is the name of the exported global which holds the gas left for the Wasm code
+	WasmGasGlobal = "wasp_gas"
+	// WasmGasExhausted is the value of the gas global after the Wasm code trapped because of the lack of gas
+	WasmGasExhausted = uint64(math.MaxUint64)
+)
+
+const (
+	wasmMagic   = "\x00asm"
+	wasmVersion = 1
+
+	sectionCustom = 0
+	sectionImport = 2
+	sectionGlobal = 6
+	sectionExport = 7
+	sectionCode   = 10
+
+	importKindFunc   = 0
+	importKindTable  = 1
+	importKindMemory = 2
+	importKindGlobal = 3
+	exportKindGlobal = 3
+
+	valTypeI64 = 0x7e
+	blockEmpty = 0x40
+
+	opUnreachable  = 0x00
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+	opSelectT      = 0x1c
+	opGlobalGet    = 0x23
+	opGlobalSet    = 0x24
+	opI32Const     = 0x41
+	opI64Const     = 0x42
+	opF32Const     = 0x43
+	opF64Const     = 0x44
+	opI64LtU       = 0x54
+	opI64Sub       = 0x7d
+	opRefNull      = 0xd0
+	opRefFunc      = 0xd2
+	opPrefixFC     = 0xfc
+)
+
+// InjectWasmMetering returns the instrumented copy of the Wasm module
+func InjectWasmMetering(wasmData []byte) ([]byte, error) {
+	if len(wasmData) < 8 || string(wasmData[:4]) != wasmMagic || binary.LittleEndian.Uint32(wasmData[4:8]) != wasmVersion {
+		return nil, xerrors.New("InjectWasmMetering: not a Wasm module")
+	}
+	sections, err := readSections(wasmData[8:])
+	if err != nil {
+		return nil, xerrors.Errorf("InjectWasmMetering: %w", err)
+	}
+	globalIndex, err := gasGlobalIndex(sections)
+	if err != nil {
+		return nil, xerrors.Errorf("InjectWasmMetering: %w", err)
+	}
+	sections = addSectionEntry(sections, sectionGlobal, gasGlobalEntry())
+	sections = addSectionEntry(sections, sectionExport, gasExportEntry(globalIndex))
+	for _, s := range sections {
+		if s.id != sectionCode {
+			continue
+		}
+		if s.content, err = meterCodeSection(s.content, globalIndex); err != nil {
+			return nil, xerrors.Errorf("InjectWasmMetering: %w", err)
+		}
+	}
+	var buf bytes.Buffer
+	buf.Write(wasmData[:8])
+	for _, s := range sections {
+		buf.WriteByte(s.id)
+		writeU32(&buf, uint32(len(s.content)))
+		buf.Write(s.content)
+	}
+	return buf.Bytes(), nil
+}
+
+type section struct {
+	id      byte
+	content []byte
+}
+
+func readSections(data []byte) ([]*section, error) {
+	r := &reader{data: data}
+	ret := make([]*section, 0)
+	for !r.done() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		content, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &section{id: id, content: content})
+	}
+	return ret, nil
+}
+
+// addSectionEntry appends the entry to the vector section. The section is created in the right order if missing
+func addSectionEntry(sections []*section, id byte, entry []byte) []*section {
+	for _, s := range sections {
+		if s.id != id {
+			continue
+		}
+		r := &reader{data: s.content}
+		count, _ := r.u32()
+		var buf bytes.Buffer
+		writeU32(&buf, count+1)
+		buf.Write(s.content[r.pos:])
+		buf.Write(entry)
+		s.content = buf.Bytes()
+		return sections
+	}
+	var buf bytes.Buffer
+	writeU32(&buf, 1)
+	buf.Write(entry)
+	newSection := &section{id: id, content: buf.Bytes()}
+	// custom sections may be anywhere, the others are ordered by id
+	pos := len(sections)
+	for i, s := range sections {
+		if s.id != sectionCustom && s.id > id {
+			pos = i
+			break
+		}
+	}
+	ret := make([]*section, 0, len(sections)+1)
+	ret = append(ret, sections[:pos]...)
+	ret = append(ret, newSection)
+	return append(ret, sections[pos:]...)
+}
+
+// gasGlobalIndex returns the index of the global to be added: imported globals go first, then the defined ones
+func gasGlobalIndex(sections []*section) (uint32, error) {
+	var ret uint32
+	for _, s := range sections {
+		r := &reader{data: s.content}
+		switch s.id {
+		case sectionImport:
+			n, err := countImportedGlobals(r)
+			if err != nil {
+				return 0, err
+			}
+			ret += n
+		case sectionGlobal:
+			n, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			ret += n
+		}
+	}
+	return ret, nil
+}
+
+func countImportedGlobals(r *reader) (uint32, error) {
+	count, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	var ret uint32
+	for i := uint32(0); i < count; i++ {
+		// module and field names
+		for j := 0; j < 2; j++ {
+			size, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if _, err = r.bytes(int(size)); err != nil {
+				return 0, err
+			}
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		switch kind {
+		case importKindFunc:
+			_, err = r.u32()
+		case importKindTable:
+			if _, err = r.byte(); err == nil {
+				err = r.skipLimits()
+			}
+		case importKindMemory:
+			err = r.skipLimits()
+		case importKindGlobal:
+			ret++
+			_, err = r.bytes(2)
+		default:
+			err = xerrors.Errorf("unknown import kind %d", kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return ret, nil
+}
+
+func gasGlobalEntry() []byte {
+	// mutable i64 initialized by MaxGasPerRequest, so the start function of the module can run
+	var buf bytes.Buffer
+	buf.Write([]byte{valTypeI64, 1, opI64Const})
+	writeS64(&buf, int64(MaxGasPerRequest))
+	buf.WriteByte(opEnd)
+	return buf.Bytes()
+}
+
+func gasExportEntry(globalIndex uint32) []byte {
+	var buf bytes.Buffer
+	writeU32(&buf, uint32(len(WasmGasGlobal)))
+	buf.WriteString(WasmGasGlobal)
+	buf.WriteByte(exportKindGlobal)
+	writeU32(&buf, globalIndex)
+	return buf.Bytes()
+}
+
+func meterCodeSection(content []byte, globalIndex uint32) ([]byte, error) {
+	r := &reader{data: content}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeU32(&buf, count)
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		metered, err := meterFunctionBody(body, globalIndex)
+		if err != nil {
+			return nil, xerrors.Errorf("function #%d: %w", i, err)
+		}
+		writeU32(&buf, uint32(len(metered)))
+		buf.Write(metered)
+	}
+	return buf.Bytes(), nil
+}
+
+func meterFunctionBody(body []byte, globalIndex uint32) ([]byte, error) {
+	r := &reader{data: body}
+	// locals are copied as they are
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < count; i++ {
+		if _, err = r.u32(); err != nil {
+			return nil, err
+		}
+		if _, err = r.byte(); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	buf.Write(body[:r.pos])
+
+	segmentStart := r.pos
+	var segmentCost uint64
+	for !r.done() {
+		opcode, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if err = r.skipImmediates(opcode); err != nil {
+			return nil, err
+		}
+		segmentCost += WasmInstruction
+		if !endsSegment(opcode) {
+			continue
+		}
+		writeMeteringCode(&buf, globalIndex, segmentCost)
+		buf.Write(body[segmentStart:r.pos])
+		segmentStart = r.pos
+		segmentCost = 0
+	}
+	if segmentCost > 0 {
+		// valid function body always ends with 'end', so it does not happen
+		return nil, xerrors.New("unterminated function body")
+	}
+	return buf.Bytes(), nil
+}
+
+// endsSegment returns true for instructions after which the execution may continue not at the next instruction
+// or may continue at the next instruction from another place. Calls do not end the segment,
+// because the callee is metered itself
+func endsSegment(opcode byte) bool {
+	switch opcode {
+	case opUnreachable, opBlock, opLoop, opIf, opElse, opEnd, opBr, opBrIf, opBrTable, opReturn:
+		return true
+	}
+	return false
+}
+
+func writeMeteringCode(buf *bytes.Buffer, globalIndex uint32, cost uint64) {
+	// if gas < cost { gas = exhausted; unreachable }
+	buf.WriteByte(opGlobalGet)
+	writeU32(buf, globalIndex)
+	buf.WriteByte(opI64Const)
+	writeS64(buf, int64(cost))
+	buf.WriteByte(opI64LtU)
+	buf.WriteByte(opIf)
+	buf.WriteByte(blockEmpty)
+	buf.WriteByte(opI64Const)
+	writeS64(buf, -1)
+	buf.WriteByte(opGlobalSet)
+	writeU32(buf, globalIndex)
+	buf.WriteByte(opUnreachable)
+	buf.WriteByte(opEnd)
+	// gas -= cost
+	buf.WriteByte(opGlobalGet)
+	writeU32(buf, globalIndex)
+	buf.WriteByte(opI64Const)
+	writeS64(buf, int64(cost))
+	buf.WriteByte(opI64Sub)
+	buf.WriteByte(opGlobalSet)
+	writeU32(buf, globalIndex)
+}
+
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.done() {
+		return 0, xerrors.New("unexpected end of data")
+	}
+	r.pos++
+	return r.data[r.pos-1], nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, xerrors.New("unexpected end of data")
+	}
+	r.pos += n
+	return r.data[r.pos-n : r.pos], nil
+}
+
+// leb reads LEB128 encoded integer of at most maxBits bits. The value is not decoded, only skipped
+func (r *reader) leb(maxBits int) (uint64, error) {
+	var ret uint64
+	for shift := 0; shift < maxBits+7; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift < 64 {
+			ret |= uint64(b&0x7f) << shift
+		}
+		if b&0x80 == 0 {
+			return ret, nil
+		}
+	}
+	return 0, xerrors.New("LEB128 integer is too long")
+}
+
+func (r *reader) u32() (uint32, error) {
+	ret, err := r.leb(32)
+	return uint32(ret), err
+}
+
+func (r *reader) skipLimits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if _, err = r.u32(); err != nil {
+		return err
+	}
+	if flags&1 != 0 {
+		_, err = r.u32()
+	}
+	return err
+}
+
+func (r *reader) skipBlockType() error {
+	b, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if b == blockEmpty || (b >= 0x6f && b <= 0x7f) {
+		// empty or value type
+		return nil
+	}
+	// type index, signed 33 bit integer
+	r.pos--
+	_, err = r.leb(33)
+	return err
+}
+
+func (r *reader) skipU32s(n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := r.u32(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//nolint:gocyclo
+func (r *reader) skipImmediates(opcode byte) error {
+	switch {
+	case opcode == opBlock || opcode == opLoop || opcode == opIf:
+		return r.skipBlockType()
+	case opcode == opBr || opcode == opBrIf || opcode == opCall || opcode == opRefFunc:
+		return r.skipU32s(1)
+	case opcode == opBrTable:
+		count, err := r.u32()
+		if err != nil {
+			return err
+		}
+		return r.skipU32s(int(count) + 1)
+	case opcode == opCallIndirect:
+		return r.skipU32s(2)
+	case opcode == opSelectT:
+		count, err := r.u32()
+		if err != nil {
+			return err
+		}
+		_, err = r.bytes(int(count))
+		return err
+	case opcode >= 0x20 && opcode <= 0x26:
+		// local.get, local.set, local.tee, global.get, global.set, table.get, table.set
+		return r.skipU32s(1)
+	case opcode >= 0x28 && opcode <= 0x3e:
+		// memory loads and stores: align and offset
+		return r.skipU32s(2)
+	case opcode == 0x3f || opcode == 0x40:
+		// memory.size, memory.grow
+		return r.skipU32s(1)
+	case opcode == opI32Const:
+		_, err := r.leb(32)
+		return err
+	case opcode == opI64Const:
+		_, err := r.leb(64)
+		return err
+	case opcode == opF32Const:
+		_, err := r.bytes(4)
+		return err
+	case opcode == opF64Const:
+		_, err := r.bytes(8)
+		return err
+	case opcode == opRefNull:
+		_, err := r.byte()
+		return err
+	case opcode == opPrefixFC:
+		return r.skipPrefixFCImmediates()
+	case opcode <= 0x01 || opcode == opElse || opcode == opEnd || opcode == opReturn:
+		// unreachable, nop, else, end, return
+		return nil
+	case opcode == 0x1a || opcode == 0x1b:
+		// drop, select
+		return nil
+	case opcode >= 0x45 && opcode <= 0xc4:
+		// numeric instructions including sign extension
+		return nil
+	case opcode == 0xd1:
+		// ref.is_null
+		return nil
+	}
+	return xerrors.Errorf("unsupported Wasm opcode 0x%02x", opcode)
+}
+
+func (r *reader) skipPrefixFCImmediates() error {
+	subcode, err := r.u32()
+	if err != nil {
+		return err
+	}
+	switch {
+	case subcode <= 7:
+		// saturating truncations
+		return nil
+	case subcode == 8:
+		// memory.init: data index and memory index
+		return r.skipU32s(2)
+	case subcode == 9 || subcode == 11 || subcode == 13:
+		// data.drop, memory.fill, elem.drop
+		return r.skipU32s(1)
+	case subcode == 10 || subcode == 12 || subcode == 14:
+		// memory.copy, table.init, table.copy
+		return r.skipU32s(2)
+	case subcode <= 17:
+		// table.grow, table.size, table.fill
+		return r.skipU32s(1)
+	}
+	return xerrors.Errorf("unsupported Wasm opcode 0xfc %d", subcode)
+}
+
+func writeU32(buf *bytes.Buffer, v uint32) {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			buf.WriteByte(b)
+			return
+		}
+		buf.WriteByte(b | 0x80)
+	}
+}
+
+func writeS64(buf *bytes.Buffer, v int64) {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			buf.WriteByte(b)
+			return
+		}
+		buf.WriteByte(b | 0x80)
+	}
+}
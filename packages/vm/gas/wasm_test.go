@@ -0,0 +1,102 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package gas
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/stretchr/testify/require"
+)
+
+func wasmSection(id byte, entries ...[]byte) []byte {
+	var content bytes.Buffer
+	writeU32(&content, uint32(len(entries)))
+	for _, e := range entries {
+		content.Write(e)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(id)
+	writeU32(&buf, uint32(content.Len()))
+	buf.Write(content.Bytes())
+	return buf.Bytes()
+}
+
+func wasmExport(name string, index byte) []byte {
+	return append(append([]byte{byte(len(name))}, name...), 0, index)
+}
+
+func wasmBody(code ...byte) []byte {
+	// no locals
+	return append([]byte{byte(len(code) + 1), 0}, code...)
+}
+
+// testModule exports 'add' which adds two i32 and 'spin' which loops forever
+func testModule() []byte {
+	module := []byte(wasmMagic + "\x01\x00\x00\x00")
+	module = append(module, wasmSection(1,
+		[]byte{0x60, 2, 0x7f, 0x7f, 1, 0x7f},
+		[]byte{0x60, 0, 0},
+	)...)
+	module = append(module, wasmSection(3, []byte{0}, []byte{1})...)
+	module = append(module, wasmSection(sectionExport, wasmExport("add", 0), wasmExport("spin", 1))...)
+	module = append(module, wasmSection(sectionCode,
+		// local.get 0, local.get 1, i32.add, end
+		wasmBody(0x20, 0, 0x20, 1, 0x6a, opEnd),
+		// loop, br 0, end, end
+		wasmBody(opLoop, blockEmpty, opBr, 0, opEnd, opEnd),
+	)...)
+	return module
+}
+
+func instantiate(t *testing.T, wasmData []byte) (*wasmtime.Store, *wasmtime.Instance) {
+	metered, err := InjectWasmMetering(wasmData)
+	require.NoError(t, err)
+	store := wasmtime.NewStore(wasmtime.NewEngine())
+	module, err := wasmtime.NewModule(store.Engine, metered)
+	require.NoError(t, err)
+	instance, err := wasmtime.NewInstance(store, module, nil)
+	require.NoError(t, err)
+	return store, instance
+}
+
+func TestWasmMetering(t *testing.T) {
+	store, instance := instantiate(t, testModule())
+	global := instance.GetExport(store, WasmGasGlobal).Global()
+	require.NotNil(t, global)
+	require.EqualValues(t, MaxGasPerRequest, global.Get(store).I64())
+
+	t.Run("straight code", func(t *testing.T) {
+		require.NoError(t, global.Set(store, wasmtime.ValI64(100)))
+		res, err := instance.GetFunc(store, "add").Call(store, 2, 3)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, res)
+		// 4 instructions
+		require.EqualValues(t, 96, global.Get(store).I64())
+	})
+	t.Run("not enough gas", func(t *testing.T) {
+		require.NoError(t, global.Set(store, wasmtime.ValI64(3)))
+		_, err := instance.GetFunc(store, "add").Call(store, 2, 3)
+		require.Error(t, err)
+		require.EqualValues(t, WasmGasExhausted, uint64(global.Get(store).I64()))
+	})
+	t.Run("infinite loop", func(t *testing.T) {
+		require.NoError(t, global.Set(store, wasmtime.ValI64(1_000_000)))
+		_, err := instance.GetFunc(store, "spin").Call(store)
+		require.Error(t, err)
+		require.EqualValues(t, WasmGasExhausted, uint64(global.Get(store).I64()))
+	})
+}
+
+func TestWasmMeteringOfContract(t *testing.T) {
+	wasmData, err := ioutil.ReadFile("../core/testcore/sbtests/sbtestsc/testcore_bg.wasm")
+	require.NoError(t, err)
+	metered, err := InjectWasmMetering(wasmData)
+	require.NoError(t, err)
+	// the contract imports host functions, so it is only validated and not instantiated
+	_, err = wasmtime.NewModule(wasmtime.NewEngine(), metered)
+	require.NoError(t, err)
+}
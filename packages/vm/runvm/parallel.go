@@ -0,0 +1,66 @@
+package runvm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/vmcontext"
+)
+
+// runSpeculatively runs off-ledger requests of the batch concurrently, each on the snapshot of the state at the
+// beginning of the block. Returns nil if the batch is run sequentially, otherwise the speculative run for each
+// off-ledger request at its index in the batch. The runs are committed in the order of the batch and the requests
+// conflicting with the preceding ones are run again, so the block is the same as produced by the sequential run
+func runSpeculatively(vmctx *vmcontext.VMContext, task *vm.VMTask) []*vmcontext.SpeculativeRun {
+	if task.Parallelism < 2 {
+		return nil
+	}
+	numOffLedger := 0
+	for _, req := range task.Requests {
+		if req.IsOffLedger() {
+			numOffLedger++
+		}
+	}
+	if numOffLedger < 2 {
+		return nil
+	}
+	ret := make([]*vmcontext.SpeculativeRun, len(task.Requests))
+	runs := make([]*vmcontext.SpeculativeRun, 0, numOffLedger)
+	// the entropy and the timestamp are expected to advance with each request run before, as in runTask
+	entropy := task.Entropy
+	var numRun int
+	var numOnLedger uint8
+	for i, req := range task.Requests {
+		if req.IsOffLedger() {
+			ret[i] = vmctx.NewSpeculativeRun(req, uint16(i), entropy, task.Timestamp.Add(time.Duration(numRun)))
+			runs = append(runs, ret[i])
+		} else {
+			if numOnLedger == vmcontext.MaxBlockInputCount {
+				continue
+			}
+			numOnLedger++
+		}
+		entropy = hashing.HashData(entropy[:])
+		numRun++
+	}
+
+	jobs := make(chan *vmcontext.SpeculativeRun)
+	var wg sync.WaitGroup
+	for w := 0; w < task.Parallelism && w < len(runs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range jobs {
+				run.Run()
+			}
+		}()
+	}
+	for _, run := range runs {
+		jobs <- run
+	}
+	close(jobs)
+	wg.Wait()
+	return ret
+}
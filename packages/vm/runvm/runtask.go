@@ -40,6 +40,7 @@ func NewVMRunner() VMRunner {
 // runTask runs batch of requests on VM
 func runTask(task *vm.VMTask) {
 	vmctx := vmcontext.CreateVMContext(task)
+	speculativeRuns := runSpeculatively(vmctx, task)
 
 	var lastResult dict.Dict
 	var lastErr error
@@ -60,7 +61,10 @@ func runTask(task *vm.VMTask) {
 			numOnLedger++
 		}
 
-		vmctx.RunTheRequest(req, uint16(i))
+		committed := speculativeRuns != nil && vmctx.CommitSpeculativeRun(speculativeRuns[i])
+		if !committed {
+			vmctx.RunTheRequest(req, uint16(i))
+		}
 		lastResult, lastTotalAssets, lastErr, exceededBlockOutputLimit = vmctx.GetResult()
 
 		if exceededBlockOutputLimit {
@@ -73,6 +77,9 @@ func runTask(task *vm.VMTask) {
 			continue
 		}
 		task.ProcessedRequestsCount++
+		if committed {
+			task.CommittedSpeculativeRuns++
+		}
 		if lastErr == nil {
 			numSuccess++
 		} else {
@@ -80,7 +87,8 @@ func runTask(task *vm.VMTask) {
 		}
 	}
 
-	task.Log.Debugf("runTask, ran %d requests. success: %d, offledger: %d", task.ProcessedRequestsCount, numSuccess, numOffLedger)
+	task.Log.Debugf("runTask, ran %d requests. success: %d, offledger: %d, committed from speculative runs: %d",
+		task.ProcessedRequestsCount, numSuccess, numOffLedger, task.CommittedSpeculativeRuns)
 
 	blockIndex, stateCommitment, timestamp, rotationAddr := vmctx.CloseVMContext(task.ProcessedRequestsCount, numSuccess, numOffLedger)
 
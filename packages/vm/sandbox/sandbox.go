@@ -74,10 +74,19 @@ func (s *sandbox) Event(msg string) {
 	s.vmctx.MustSaveEvent(s.vmctx.CurrentContractHname(), msg)
 }
 
+func (s *sandbox) EmitEvent(event *iscp.Event) {
+	s.Log().Infof("event::%s -> '%s'", s.vmctx.CurrentContractHname(), event.Name)
+	s.vmctx.MustSaveTypedEvent(s.vmctx.CurrentContractHname(), event)
+}
+
 func (s *sandbox) GetEntropy() hashing.HashValue {
 	return s.vmctx.Entropy()
 }
 
+func (s *sandbox) GetRandomSeed() hashing.HashValue {
+	return s.vmctx.RandomSeed()
+}
+
 func (s *sandbox) GetTimestamp() int64 {
 	return s.vmctx.Timestamp()
 }
@@ -121,3 +130,11 @@ func (s *sandbox) BlockContext(construct func(ctx iscp.Sandbox) interface{}, onC
 func (s *sandbox) StateAnchor() iscp.StateAnchor {
 	return s.vmctx
 }
+
+func (s *sandbox) BurnGas(gas uint64) {
+	s.vmctx.BurnGas(gas)
+}
+
+func (s *sandbox) GasBudgetLeft() uint64 {
+	return s.vmctx.GasBudgetLeft()
+}
@@ -71,3 +71,12 @@ func (s sandboxView) State() kv.KVStoreReader {
 func (s sandboxView) Utils() iscp.Utils {
 	return sandbox_utils.NewUtils()
 }
+
+// BurnGas charges the request, when the view is called in the context of the request
+func (s sandboxView) BurnGas(gas uint64) {
+	s.vmctx.BurnGas(gas)
+}
+
+func (s sandboxView) GasBudgetLeft() uint64 {
+	return s.vmctx.GasBudgetLeft()
+}
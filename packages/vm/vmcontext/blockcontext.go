@@ -3,6 +3,7 @@ package vmcontext
 import "github.com/iotaledger/wasp/packages/iscp"
 
 func (vmctx *VMContext) BlockContext(ctx iscp.Sandbox, construct func(ctx iscp.Sandbox) interface{}, onClose func(interface{})) interface{} {
+	vmctx.abortSpeculation("BlockContext")
 	hname := vmctx.CurrentContractHname()
 	if bctx, alreadyExists := vmctx.blockContext[hname]; alreadyExists {
 		return bctx.obj
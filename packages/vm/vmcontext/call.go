@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 var ErrContractNotFound = errors.New("contract not found")
@@ -17,6 +18,7 @@ var ErrContractNotFound = errors.New("contract not found")
 // Call
 func (vmctx *VMContext) Call(targetContract, epCode iscp.Hname, params dict.Dict, transfer colored.Balances) (dict.Dict, error) {
 	vmctx.log.Debugw("Call", "targetContract", targetContract, "epCode", epCode)
+	vmctx.BurnGas(gas.Call)
 	rec, ok := vmctx.findContractByHname(targetContract)
 	if !ok {
 		return nil, ErrContractNotFound
@@ -25,6 +27,7 @@ func (vmctx *VMContext) Call(targetContract, epCode iscp.Hname, params dict.Dict
 }
 
 func (vmctx *VMContext) callByProgramHash(targetContract, epCode iscp.Hname, params dict.Dict, transfer colored.Balances, progHash hashing.HashValue) (dict.Dict, error) {
+	vmctx.abortSpeculationIfNotNative(progHash)
 	proc, err := vmctx.processors.GetOrCreateProcessorByProgramHash(progHash, vmctx.getBinary)
 	if err != nil {
 		return nil, err
@@ -61,6 +64,7 @@ func (vmctx *VMContext) callByProgramHash(targetContract, epCode iscp.Hname, par
 }
 
 func (vmctx *VMContext) callNonViewByProgramHash(targetContract, epCode iscp.Hname, params dict.Dict, transfer colored.Balances, progHash hashing.HashValue) (dict.Dict, error) {
+	vmctx.abortSpeculationIfNotNative(progHash)
 	proc, err := vmctx.processors.GetOrCreateProcessorByProgramHash(progHash, vmctx.getBinary)
 	if err != nil {
 		return nil, err
@@ -87,6 +91,17 @@ func (vmctx *VMContext) callNonViewByProgramHash(targetContract, epCode iscp.Hna
 	return ep.Call(NewSandbox(vmctx))
 }
 
+// abortSpeculationIfNotNative stops the speculative run before calling the contract run by a VM, e.g. wasm.
+// Only core and native processors can be called concurrently
+func (vmctx *VMContext) abortSpeculationIfNotNative(progHash hashing.HashValue) {
+	if vmctx.speculation == nil {
+		return
+	}
+	if _, ok := vmctx.processors.Config.GetNativeProcessorType(progHash); !ok {
+		vmctx.abortSpeculation("call of VM contract")
+	}
+}
+
 func (vmctx *VMContext) callerIsRoot() bool {
 	caller := vmctx.Caller()
 	if !caller.Address().Equals(vmctx.chainID.AsAddress()) {
@@ -11,7 +11,8 @@ import (
 )
 
 func (vmctx *VMContext) pushCallContextWithTransfer(contract iscp.Hname, params dict.Dict, transfer colored.Balances) error {
-	if transfer != nil {
+	// nothing to move if the request brings no tokens
+	if len(transfer) > 0 {
 		targetAccount := iscp.NewAgentID(vmctx.ChainID().AsAddress(), contract)
 		targetAccount = vmctx.adjustAccount(targetAccount)
 		if len(vmctx.callStack) == 0 {
@@ -5,12 +5,14 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 // DeployContract deploys contract by its program hash
 // - if called from 'root' contract only loads VM from binary
 // - otherwise calls 'root' contract 'DeployContract' entry point to do the job.
 func (vmctx *VMContext) DeployContract(programHash hashing.HashValue, name, description string, initParams dict.Dict) error {
+	vmctx.abortSpeculation("DeployContract")
 	vmtype, programBinary, err := vmctx.getBinary(programHash)
 	if err != nil {
 		return err
@@ -18,6 +20,7 @@ func (vmctx *VMContext) DeployContract(programHash hashing.HashValue, name, desc
 	if vmctx.CurrentContractHname() == root.Contract.Hname() {
 		// from root contract only loading VM
 		vmctx.log.Debugf("vmcontext.DeployContract: %s from root", programHash.String())
+		vmctx.BurnGas(gas.Deploy)
 		return vmctx.processors.NewProcessor(programHash, programBinary, vmtype)
 	}
 	vmctx.log.Debugf("vmcontext.DeployContract: %s, name: %s, dscr: '%s'", programHash.String(), name, description)
@@ -0,0 +1,28 @@
+package vmcontext
+
+import (
+	"github.com/iotaledger/wasp/packages/vm/gas"
+)
+
+// BurnGas charges the request with the gas. Gas is burned only while the request is being called,
+// i.e. fee handling and logging of the request are free. Panics with gas.ErrNotEnoughGas when the
+// gas budget of the request is exceeded
+func (vmctx *VMContext) BurnGas(g uint64) {
+	if !vmctx.gasMetering {
+		return
+	}
+	if g > vmctx.gasBudget-vmctx.gasBurned {
+		vmctx.gasBurned = vmctx.gasBudget
+		panic(gas.ErrNotEnoughGas)
+	}
+	vmctx.gasBurned += g
+}
+
+// GasBudgetLeft is the gas the current request still may burn
+func (vmctx *VMContext) GasBudgetLeft() uint64 {
+	return vmctx.gasBudget - vmctx.gasBurned
+}
+
+func (vmctx *VMContext) GasBurned() uint64 {
+	return vmctx.gasBurned
+}
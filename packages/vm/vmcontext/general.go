@@ -9,6 +9,8 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp/request"
 	"github.com/iotaledger/wasp/packages/iscp/requestargs"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 func (vmctx *VMContext) ChainID() *iscp.ChainID {
@@ -58,6 +60,15 @@ func (vmctx *VMContext) Entropy() hashing.HashValue {
 	return vmctx.entropy
 }
 
+// RandomSeed is the seed derived from the random beacon of the block. It is the same for all requests
+// of the block. NilHash if the block has no beacon
+func (vmctx *VMContext) RandomSeed() hashing.HashValue {
+	if vmctx.randomBeacon == nil {
+		return hashing.NilHash
+	}
+	return blocklog.RandomSeed(vmctx.randomBeacon)
+}
+
 func (vmctx *VMContext) Request() iscp.Request {
 	return vmctx.req
 }
@@ -65,6 +76,7 @@ func (vmctx *VMContext) Request() iscp.Request {
 const maxParamSize = 512
 
 func (vmctx *VMContext) Send(target ledgerstate.Address, tokens colored.Balances, metadata *iscp.SendMetadata, options ...iscp.SendOptions) bool {
+	vmctx.abortSpeculation("Send")
 	if vmctx.requestOutputCount >= MaxBlockOutputCount {
 		vmctx.log.Panicf("request with ID %s exceeded max number of allowed outputs (%d)", vmctx.req.ID().Base58(), MaxBlockOutputCount)
 	}
@@ -73,6 +85,7 @@ func (vmctx *VMContext) Send(target ledgerstate.Address, tokens colored.Balances
 		vmctx.log.Errorf("Send: transfer can't be empty")
 		return false
 	}
+	vmctx.BurnGas(gas.Send)
 	data := request.NewMetadata().
 		WithRequestNonce(vmctx.blockOutputCount).
 		WithSender(vmctx.CurrentContractHname())
@@ -1,14 +1,19 @@
 package vmcontext
 
 import (
+	"errors"
+
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 // creditToAccount deposits transfer from request to chain account of of the called contract
@@ -47,6 +52,13 @@ func (vmctx *VMContext) totalAssets() colored.Balances {
 	return accounts.GetTotalAssets(vmctx.State())
 }
 
+// mustCheckLedger checks the ledger of the accounts contract after the update of the request is applied to the state.
+// The contracts postpone the check to this point, so it is run once per request and it doesn't burn gas
+func (vmctx *VMContext) mustCheckLedger() {
+	state := subrealm.New(vmctx.virtualState.KVStore(), kv.Key(accounts.Contract.Hname().Bytes()))
+	accounts.MustCheckLedger(state, "request "+vmctx.req.ID().Short())
+}
+
 func (vmctx *VMContext) findContractByHname(contractHname iscp.Hname) (*root.ContractRecord, bool) {
 	vmctx.pushCallContext(root.Contract.Hname(), nil, nil)
 	defer vmctx.popCallContext()
@@ -86,6 +98,14 @@ func (vmctx *VMContext) getBalanceOfAccount(agentID *iscp.AgentID, col colored.C
 	return accounts.GetBalance(vmctx.State(), agentID, col)
 }
 
+func (vmctx *VMContext) getBalancesOfAccount(agentID *iscp.AgentID) colored.Balances {
+	vmctx.pushCallContext(accounts.Contract.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	ret, _ := accounts.GetAccountBalances(vmctx.State(), agentID)
+	return ret
+}
+
 func (vmctx *VMContext) getBalance(col colored.Color) uint64 {
 	return vmctx.getBalanceOfAccount(vmctx.MyAgentID(), col)
 }
@@ -125,19 +145,38 @@ func (vmctx *VMContext) mustLogRequestToBlockLog(errProvided error) {
 	vmctx.pushCallContext(blocklog.Contract.Hname(), nil, nil)
 	defer vmctx.popCallContext()
 
-	errStr := ""
-	if errProvided != nil {
-		errStr = errProvided.Error()
+	receipt := &blocklog.RequestReceipt{
+		Request:  vmctx.req,
+		Error:    vmctx.requestError(errProvided),
+		FeeColor: vmctx.feeColor,
+		Fee:      vmctx.requestFee,
+		GasUsed:  vmctx.gasBurned,
 	}
-	err := blocklog.SaveRequestLogRecord(vmctx.State(), &blocklog.RequestReceipt{
-		Request: vmctx.req,
-		Error:   errStr,
-	}, vmctx.requestLookupKey())
+	if errProvided == nil {
+		receipt.WithResult(vmctx.lastResult)
+	}
+	err := blocklog.SaveRequestLogRecord(vmctx.State(), receipt, vmctx.requestLookupKey())
 	if err != nil {
 		vmctx.Panicf("logRequestToBlockLog: %v", err)
 	}
 }
 
+// requestError converts the error of the request to the structured error stored in the receipt
+func (vmctx *VMContext) requestError(err error) *iscp.RequestError {
+	if err == nil {
+		return nil
+	}
+	var reqErr *iscp.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr
+	}
+	code := iscp.RequestErrorContract
+	if errors.Is(err, gas.ErrNotEnoughGas) {
+		code = iscp.RequestErrorNotEnoughGas
+	}
+	return iscp.NewRequestError(vmctx.req.Target().Contract, code, err.Error())
+}
+
 func (vmctx *VMContext) MustSaveEvent(contract iscp.Hname, msg string) {
 	vmctx.pushCallContext(blocklog.Contract.Hname(), nil, nil)
 	defer vmctx.popCallContext()
@@ -148,6 +187,7 @@ func (vmctx *VMContext) MustSaveEvent(contract iscp.Hname, msg string) {
 	if len([]byte(msg)) > int(vmctx.maxEventSize) {
 		vmctx.Panicf("event too large: %s, request index: %d", contract.String(), vmctx.requestIndex)
 	}
+	vmctx.BurnGas(gas.Event + uint64(len(msg))*gas.EventPerByte)
 
 	vmctx.log.Debugf("MustSaveEvent/%s: msg: '%s'", contract.String(), msg)
 	err := blocklog.SaveEvent(vmctx.State(), msg, vmctx.eventLookupKey(), contract)
@@ -156,3 +196,28 @@ func (vmctx *VMContext) MustSaveEvent(contract iscp.Hname, msg string) {
 	}
 	vmctx.requestEventIndex++
 }
+
+// MustSaveTypedEvent saves the typed event and its indexes. It shares the limits and the counter of events
+// of the request with MustSaveEvent
+func (vmctx *VMContext) MustSaveTypedEvent(contract iscp.Hname, event *iscp.Event) {
+	vmctx.pushCallContext(blocklog.Contract.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+	if vmctx.requestEventIndex > vmctx.maxEventsPerReq {
+		vmctx.Panicf("too many events issued for contract: %s, request index: %d", contract.String(), vmctx.requestIndex)
+	}
+	if err := event.Validate(); err != nil {
+		vmctx.Panicf("invalid event: %v", err)
+	}
+	data := event.Bytes()
+	if len(data) > int(vmctx.maxEventSize) {
+		vmctx.Panicf("event too large: %s, request index: %d", contract.String(), vmctx.requestIndex)
+	}
+	vmctx.BurnGas(gas.Event + uint64(len(data))*gas.EventPerByte + uint64(len(event.Topics()))*gas.EventTopic)
+
+	vmctx.log.Debugf("MustSaveTypedEvent/%s: event: '%s'", contract.String(), event.Name)
+	err := blocklog.SaveTypedEvent(vmctx.State(), event, vmctx.eventLookupKey(), contract, vmctx.virtualState.Timestamp())
+	if err != nil {
+		vmctx.Panicf("MustSaveTypedEvent: %v", err)
+	}
+	vmctx.requestEventIndex++
+}
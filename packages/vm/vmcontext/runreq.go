@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
@@ -15,16 +16,11 @@ import (
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
-	"golang.org/x/xerrors"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
-// TODO temporary place for the constant. In the future must be shared with pruning module
-//  the number is approximate assumed maximum number of requests in the batch
-//  The node must guarantee at least this number of last requests processed recorded in the state
-//  for each address
-const OffLedgerNonceStrictOrderTolerance = 10000
-
 // RunTheRequest processes any request based on the Extended output, even if it
 // doesn't parse correctly as a SC request
 func (vmctx *VMContext) RunTheRequest(req iscp.Request, requestIndex uint16) {
@@ -53,7 +49,7 @@ func (vmctx *VMContext) RunTheRequest(req iscp.Request, requestIndex uint16) {
 
 	// snapshot state baseline for rollback in case of panic
 	snapshotTxBuilder := vmctx.txBuilder.Clone()
-	vmctx.virtualState.ApplyStateUpdates(vmctx.currentStateUpdate)
+	vmctx.applyStateUpdate(vmctx.currentStateUpdate)
 	// request run updates will be collected to the new state update
 	vmctx.currentStateUpdate = state.NewStateUpdate()
 
@@ -61,6 +57,7 @@ func (vmctx *VMContext) RunTheRequest(req iscp.Request, requestIndex uint16) {
 	func() {
 		// panic catcher for the whole call from request to the VM
 		defer func() {
+			vmctx.gasMetering = false
 			r := recover()
 			if r == nil {
 				return
@@ -70,15 +67,20 @@ func (vmctx *VMContext) RunTheRequest(req iscp.Request, requestIndex uint16) {
 			case *kv.DBError:
 				panic(err)
 			case error:
-				if errors.Is(err, coreutil.ErrorStateInvalidated) {
+				if errors.Is(err, coreutil.ErrorStateInvalidated) || errors.Is(err, errSpeculationAborted) {
 					panic(err)
 				}
 			}
+			code := iscp.RequestErrorPanic
+			if err, ok := r.(error); ok && errors.Is(err, gas.ErrNotEnoughGas) {
+				code = iscp.RequestErrorNotEnoughGas
+			}
 			vmctx.lastResult = nil
-			vmctx.lastError = xerrors.Errorf("panic in VM: %v", r)
+			vmctx.lastError = iscp.NewRequestError(vmctx.req.Target().Contract, code, fmt.Sprintf("panic in VM: %v", r))
 			vmctx.Debugf("%v", vmctx.lastError)
 			vmctx.Debugf(string(debug.Stack()))
 		}()
+		vmctx.gasMetering = true
 		vmctx.mustCallFromRequest()
 	}()
 
@@ -111,6 +113,12 @@ func (vmctx *VMContext) mustSetUpRequestContext(req iscp.Request, requestIndex u
 	vmctx.requestEventIndex = 0
 	vmctx.requestOutputCount = 0
 	vmctx.exceededBlockOutputLimit = false
+	vmctx.lastResult = nil
+	vmctx.lastError = nil
+	vmctx.requestFee = 0
+	vmctx.gasBudget = gas.BudgetOrDefault(req.GasBudget())
+	vmctx.gasBurned = 0
+	vmctx.gasMetering = false
 
 	if !req.IsOffLedger() {
 		vmctx.txBuilder.AddConsumable(vmctx.req.(*request.OnLedger).Output())
@@ -200,7 +208,9 @@ func (vmctx *VMContext) validateRequest() bool {
 
 	// off-ledger account must exist, i.e. it should have non zero balance on the chain
 	if _, exists := accounts.GetAccountBalances(vmctx.State(), req.SenderAccount()); !exists {
-		vmctx.lastError = fmt.Errorf("validateRequest: unverified account %s for %s", req.SenderAccount(), req.ID().String())
+		vmctx.lastError = iscp.NewRequestError(req.Target().Contract, iscp.RequestErrorInvalidRequest,
+			fmt.Sprintf("validateRequest: unverified account %s for %s", req.SenderAccount(), req.ID().String()),
+			req.SenderAccount().String())
 		return false
 	}
 
@@ -210,18 +220,26 @@ func (vmctx *VMContext) validateRequest() bool {
 	maxAssumed := accounts.GetMaxAssumedNonce(vmctx.State(), req.SenderAddress())
 
 	vmctx.log.Debugf("vmctx.validateRequest - nonce check - maxAssumed: %d, tolerance: %d, request nonce: %d ",
-		maxAssumed, OffLedgerNonceStrictOrderTolerance, req.Nonce())
+		maxAssumed, blocklog.OffLedgerNonceStrictOrderTolerance, req.Nonce())
 
-	if maxAssumed < OffLedgerNonceStrictOrderTolerance {
+	if maxAssumed < blocklog.OffLedgerNonceStrictOrderTolerance {
 		return true
 	}
-	return req.Nonce() > maxAssumed-OffLedgerNonceStrictOrderTolerance
+	if req.Nonce() <= maxAssumed-blocklog.OffLedgerNonceStrictOrderTolerance {
+		vmctx.lastError = iscp.NewRequestError(req.Target().Contract, iscp.RequestErrorInvalidRequest,
+			fmt.Sprintf("validateRequest: nonce %d of request %s is too old", req.Nonce(), req.ID().String()),
+			strconv.FormatUint(req.Nonce(), 10))
+		return false
+	}
+	return true
 }
 
 // mustHandleFees handles node fees. If not enough, takes as much as it can, the rest sends back
 // Return false if not enough fees
 func (vmctx *VMContext) mustHandleFees() bool {
-	totalFee := vmctx.ownerFee + vmctx.validatorFee
+	// the priority fee goes to the validators, on top of the regular validator fee
+	priorityFee := vmctx.req.PriorityFee()
+	totalFee := vmctx.ownerFee + vmctx.validatorFee + priorityFee
 	if totalFee == 0 || vmctx.requesterIsLocal() {
 		// no fees enabled or the caller is the chain owner
 		vmctx.log.Debugf("mustHandleFees: no fees charged")
@@ -229,17 +247,21 @@ func (vmctx *VMContext) mustHandleFees() bool {
 	}
 
 	// process fees for owner and validator
+	validatorFeeAccount := vmctx.validatorFeeAccount()
 	if vmctx.grabFee(vmctx.commonAccount(), vmctx.ownerFee) &&
-		vmctx.grabFee(vmctx.validatorFeeTarget, vmctx.validatorFee) {
-		// there were enough fees for both
+		vmctx.grabFee(validatorFeeAccount, vmctx.validatorFee) &&
+		vmctx.grabFee(validatorFeeAccount, priorityFee) {
+		// there were enough fees for all
 		return true
 	}
 
 	// not enough fees available
 	vmctx.mustSendBack(vmctx.remainingAfterFees)
 	vmctx.remainingAfterFees = nil
-	vmctx.lastError = fmt.Errorf("mustHandleFees: not enough fees for request %s. Remaining tokens were sent back to %s",
-		vmctx.req.ID(), vmctx.req.SenderAddress().Base58())
+	vmctx.lastError = iscp.NewRequestError(vmctx.req.Target().Contract, iscp.RequestErrorNotEnoughFees,
+		fmt.Sprintf("mustHandleFees: not enough fees for request %s. Remaining tokens were sent back to %s",
+			vmctx.req.ID(), vmctx.req.SenderAddress().Base58()),
+		strconv.FormatUint(totalFee, 10), vmctx.feeColor.String())
 	return false
 }
 
@@ -265,13 +287,30 @@ func (vmctx *VMContext) grabFee(account *iscp.AgentID, amount uint64) bool {
 	transfer := colored.NewBalancesForColor(vmctx.feeColor, amount)
 
 	if !vmctx.req.IsFeePrepaid() {
-		vmctx.creditToAccount(account, transfer)
+		vmctx.moveFee(nil, account, transfer)
+		vmctx.requestFee += amount
 		return enoughFees
 	}
 
 	// fees should have been deposited in sender account on chain
-	sender := vmctx.req.SenderAccount()
-	return vmctx.moveBetweenAccounts(sender, account, transfer) && enoughFees
+	if !vmctx.moveFee(vmctx.req.SenderAccount(), account, transfer) {
+		return false
+	}
+	vmctx.requestFee += amount
+	return enoughFees
+}
+
+// moveFee moves the fee from the account of the sender to the account, or credits it from the tokens of the request
+// if the sender is nil. The speculative run of the request moves the fee only when it is committed
+func (vmctx *VMContext) moveFee(sender, account *iscp.AgentID, transfer colored.Balances) bool {
+	if vmctx.speculation != nil {
+		return vmctx.speculateFee(sender, account, transfer)
+	}
+	if sender == nil {
+		vmctx.creditToAccount(account, transfer)
+		return true
+	}
+	return vmctx.moveBetweenAccounts(sender, account, transfer)
 }
 
 func (vmctx *VMContext) mustSendBack(tokens colored.Balances) {
@@ -304,6 +343,12 @@ func (vmctx *VMContext) mustCallFromRequest() {
 	vmctx.mustUpdateOffledgerRequestMaxAssumedNonce()
 
 	// calling only non view entry points. Calling the view will trigger error and fallback
+	if !request.IsDecrypted(vmctx.req) {
+		// the quorum of the committee failed to open the envelope, e.g. it is encrypted to another key
+		vmctx.lastError = iscp.NewRequestError(vmctx.req.Target().Contract, iscp.RequestErrorInvalidRequest,
+			fmt.Sprintf("encrypted arguments of %s can't be decrypted", vmctx.req.ID().String()))
+		return
+	}
 	entryPoint := vmctx.req.Target().EntryPoint
 	targetContract := vmctx.contractRecord.Hname()
 	params, _ := vmctx.req.Params()
@@ -324,11 +369,18 @@ func (vmctx *VMContext) mustFinalizeRequestCall() {
 	if vmctx.exceededBlockOutputLimit {
 		return
 	}
+	if vmctx.speculation != nil {
+		// the receipt is logged and the assets are totalled when the speculative run is committed
+		vmctx.applyStateUpdate(vmctx.currentStateUpdate)
+		vmctx.currentStateUpdate = nil
+		return
+	}
 	vmctx.mustLogRequestToBlockLog(vmctx.lastError) // panic not caught
 	vmctx.lastTotalAssets = vmctx.totalAssets()
 
-	vmctx.virtualState.ApplyStateUpdates(vmctx.currentStateUpdate)
+	vmctx.applyStateUpdate(vmctx.currentStateUpdate)
 	vmctx.currentStateUpdate = nil
+	vmctx.mustCheckLedger()
 
 	vmctx.log.Debug("runTheRequest OUT. ",
 		"reqId: ", vmctx.req.ID().Short(),
@@ -0,0 +1,309 @@
+package vmcontext
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"golang.org/x/xerrors"
+)
+
+// errSpeculationAborted stops the speculative run of the request which has effects outside of the chain state.
+// Such a request is run again in the order of the block
+var errSpeculationAborted = errors.New("speculative run aborted")
+
+// speculation collects the state accessed by the request run speculatively
+type speculation struct {
+	reads    map[kv.Key][]byte              // values as seen by the first read of each key
+	iterated map[kv.Key]map[kv.Key]struct{} // iterated prefixes with the keys seen in the state
+	writes   state.StateUpdate              // all state updates of the request
+	// the fees are moved to the shared fee accounts when the run is committed, so the runs don't conflict on them
+	fees              []*feeMove
+	takingFee         bool                // the balance of the sender is being checked for the fee
+	readsAfterFees    map[kv.Key]struct{} // keys read after the fees were taken
+	iteratedAfterFees map[kv.Key]struct{} // prefixes iterated after the fees were taken
+}
+
+// feeMove is the fee taken by the request run speculatively
+type feeMove struct {
+	sender   *iscp.AgentID // nil if the fee comes with the tokens of the request
+	account  *iscp.AgentID
+	transfer colored.Balances
+}
+
+// SpeculativeRun is the run of the off-ledger request on the snapshot of the state taken at the beginning of the
+// block. The result of the run is committed to the block only if the request has read nothing written by the
+// requests preceding it in the block, so the state is the same as if the requests were run one by one
+type SpeculativeRun struct {
+	vmctx        *VMContext
+	req          iscp.Request
+	requestIndex uint16
+	entropy      hashing.HashValue // entropy before the request
+	timestamp    time.Time         // timestamp of the state before the request
+	aborted      bool
+}
+
+// NewSpeculativeRun prepares the speculative run of the request with the given entropy and timestamp, expected
+// before the request in the block. It must be called before any request of the block is run
+func (vmctx *VMContext) NewSpeculativeRun(req iscp.Request, requestIndex uint16, entropy hashing.HashValue, timestamp time.Time) *SpeculativeRun {
+	if vmctx.writtenKeys == nil {
+		vmctx.writtenKeys = make(map[kv.Key]struct{})
+	}
+	virtualState := state.WrapMustOptimisticVirtualStateAccess(vmctx.virtualState.Copy(), vmctx.solidStateBaseline)
+	virtualState.ApplyStateUpdates(state.NewStateUpdate(timestamp))
+	ctx := &VMContext{
+		chainID:                vmctx.chainID,
+		chainInput:             vmctx.chainInput,
+		processors:             vmctx.processors,
+		txBuilder:              vmctx.txBuilder.Clone(),
+		virtualState:           virtualState,
+		solidStateBaseline:     vmctx.solidStateBaseline,
+		blockContext:           make(map[iscp.Hname]*blockContext),
+		blockContextCloseSeq:   make([]iscp.Hname, 0),
+		log:                    vmctx.log,
+		randomBeacon:           vmctx.randomBeacon,
+		validatorFeeTarget:     vmctx.validatorFeeTarget,
+		validatorParticipation: vmctx.validatorParticipation,
+		entropy:                entropy,
+		callStack:              make([]*callContext, 0),
+		speculation: &speculation{
+			reads:    make(map[kv.Key][]byte),
+			iterated: make(map[kv.Key]map[kv.Key]struct{}),
+			writes:   state.NewStateUpdate(),
+
+			readsAfterFees:    make(map[kv.Key]struct{}),
+			iteratedAfterFees: make(map[kv.Key]struct{}),
+		},
+	}
+	return &SpeculativeRun{
+		vmctx:        ctx,
+		req:          req,
+		requestIndex: requestIndex,
+		entropy:      entropy,
+		timestamp:    timestamp,
+	}
+}
+
+// Run runs the request on its own copy of the state. Different runs may be run concurrently
+func (run *SpeculativeRun) Run() {
+	defer func() {
+		if r := recover(); r != nil {
+			// if the panic is not caused by the speculation, it will happen again when the request is re-run
+			run.aborted = true
+			run.vmctx.log.Debugf("speculative run of %s aborted: %v", run.req.ID().Short(), r)
+		}
+	}()
+	run.vmctx.RunTheRequest(run.req, run.requestIndex)
+	if run.vmctx.exceededBlockOutputLimit {
+		run.aborted = true
+	}
+}
+
+// CommitSpeculativeRun takes the result of the speculative run to the block, if the request would have had the same
+// result when run at this point of the block. Returns false if the request must be run again
+func (vmctx *VMContext) CommitSpeculativeRun(run *SpeculativeRun) bool {
+	if run == nil || run.aborted {
+		return false
+	}
+	if run.entropy != vmctx.entropy || !run.timestamp.Equal(vmctx.virtualState.Timestamp()) {
+		return false
+	}
+	if vmctx.conflictsWith(run.vmctx.speculation) || run.vmctx.speculation.accessedFeeAccounts() {
+		return false
+	}
+	ctx := run.vmctx
+	vmctx.chainOwnerID = ctx.chainOwnerID
+	vmctx.maxEventSize = ctx.maxEventSize
+	vmctx.maxEventsPerReq = ctx.maxEventsPerReq
+	vmctx.feeColor = ctx.feeColor
+	vmctx.ownerFee = ctx.ownerFee
+	vmctx.validatorFee = ctx.validatorFee
+	vmctx.requestFee = ctx.requestFee
+	vmctx.remainingAfterFees = ctx.remainingAfterFees
+	vmctx.req = ctx.req
+	vmctx.requestIndex = ctx.requestIndex
+	vmctx.requestEventIndex = ctx.requestEventIndex
+	vmctx.requestOutputCount = 0
+	vmctx.entropy = ctx.entropy
+	vmctx.contractRecord = ctx.contractRecord
+	vmctx.lastError = ctx.lastError
+	vmctx.lastResult = ctx.lastResult
+	vmctx.exceededBlockOutputLimit = false
+	vmctx.gasBudget = ctx.gasBudget
+	vmctx.gasBurned = ctx.gasBurned
+
+	vmctx.currentStateUpdate = state.NewStateUpdate()
+	for _, fee := range ctx.speculation.fees {
+		if !vmctx.moveFee(fee.sender, fee.account, fee.transfer) {
+			vmctx.log.Panicf("CommitSpeculativeRun: inconsistency: fee of %s can't be moved", vmctx.req.ID())
+		}
+	}
+	vmctx.applyStateUpdate(vmctx.currentStateUpdate)
+	vmctx.applyStateUpdate(ctx.speculation.writes)
+	// the receipts of all requests go to the same map in the blocklog, so they are saved in the order of the block
+	vmctx.currentStateUpdate = state.NewStateUpdate()
+	vmctx.mustFinalizeRequestCall()
+	return true
+}
+
+// conflictsWith checks if any key read by the speculative run has been changed in the block since the snapshot
+func (vmctx *VMContext) conflictsWith(spec *speculation) bool {
+	kvs := vmctx.virtualState.KVStore()
+	for k, v := range spec.reads {
+		if _, written := vmctx.writtenKeys[k]; written && !bytes.Equal(kvs.MustGet(k), v) {
+			return true
+		}
+	}
+	for prefix, seen := range spec.iterated {
+		for k := range vmctx.writtenKeys {
+			if !k.HasPrefix(prefix) {
+				continue
+			}
+			if _, ok := seen[k]; ok != kvs.MustHas(k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessedFeeAccounts checks if the speculative run has accessed the accounts of its fees after taking them. The
+// fees are moved only when the run is committed, so such a run has seen the accounts without them
+func (spec *speculation) accessedFeeAccounts() bool {
+	if len(spec.fees) == 0 {
+		return false
+	}
+	partition := kv.Key(accounts.Contract.Hname().Bytes())
+	var prefixes []kv.Key
+	for _, fee := range spec.fees {
+		if fee.sender == nil {
+			prefixes = append(prefixes, partition+accounts.TotalAssetsKeyPrefix())
+		} else {
+			for _, prefix := range accounts.AccountKeyPrefixes(fee.sender) {
+				prefixes = append(prefixes, partition+prefix)
+			}
+		}
+		for _, prefix := range accounts.AccountKeyPrefixes(fee.account) {
+			prefixes = append(prefixes, partition+prefix)
+		}
+	}
+	for _, prefix := range prefixes {
+		for k := range spec.readsAfterFees {
+			if k.HasPrefix(prefix) {
+				return true
+			}
+		}
+		for k := range spec.iteratedAfterFees {
+			if k.HasPrefix(prefix) || prefix.HasPrefix(k) {
+				return true
+			}
+		}
+		for k := range spec.writes.Mutations().Sets {
+			if k.HasPrefix(prefix) {
+				return true
+			}
+		}
+		for k := range spec.writes.Mutations().Dels {
+			if k.HasPrefix(prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// speculateFee takes the fee in the speculative run, if the sender has enough tokens for it. The fee is moved when
+// the run is committed
+func (vmctx *VMContext) speculateFee(sender, account *iscp.AgentID, transfer colored.Balances) bool {
+	if sender != nil {
+		if sender.Equals(account) {
+			// no need to move
+			return true
+		}
+		vmctx.speculation.takingFee = true
+		available := vmctx.getBalancesOfAccount(sender)
+		vmctx.speculation.takingFee = false
+		if available == nil {
+			return false
+		}
+		for _, fee := range vmctx.speculation.fees {
+			if fee.sender != nil && fee.sender.Equals(sender) {
+				for col, bal := range fee.transfer {
+					available.SubNoOverflow(col, bal)
+				}
+			}
+		}
+		for col, bal := range transfer {
+			if available.Get(col) < bal {
+				return false
+			}
+		}
+	}
+	vmctx.speculation.fees = append(vmctx.speculation.fees, &feeMove{
+		sender:   sender,
+		account:  account,
+		transfer: transfer,
+	})
+	return true
+}
+
+// applyStateUpdate applies the update of the current request to the virtual state and keeps track of the written
+// keys, if speculative runs are used in the block
+func (vmctx *VMContext) applyStateUpdate(upd state.StateUpdate) {
+	vmctx.virtualState.ApplyStateUpdates(upd)
+	if vmctx.speculation != nil {
+		upd.Mutations().ApplyTo(vmctx.speculation.writes.Mutations())
+		return
+	}
+	if vmctx.writtenKeys == nil {
+		return
+	}
+	for k := range upd.Mutations().Sets {
+		vmctx.writtenKeys[k] = struct{}{}
+	}
+	for k := range upd.Mutations().Dels {
+		vmctx.writtenKeys[k] = struct{}{}
+	}
+}
+
+// recordRead remembers the value of the key as seen in the snapshot by the speculative run
+func (vmctx *VMContext) recordRead(key kv.Key, value []byte) {
+	if vmctx.speculation == nil {
+		return
+	}
+	if _, ok := vmctx.speculation.reads[key]; !ok {
+		vmctx.speculation.reads[key] = value
+	}
+	if len(vmctx.speculation.fees) > 0 && !vmctx.speculation.takingFee {
+		vmctx.speculation.readsAfterFees[key] = struct{}{}
+	}
+}
+
+// recordIteration returns the set of keys seen in the snapshot under the prefix, or nil if not speculating
+func (vmctx *VMContext) recordIteration(prefix kv.Key) map[kv.Key]struct{} {
+	if vmctx.speculation == nil {
+		return nil
+	}
+	if len(vmctx.speculation.fees) > 0 && !vmctx.speculation.takingFee {
+		vmctx.speculation.iteratedAfterFees[prefix] = struct{}{}
+	}
+	seen, ok := vmctx.speculation.iterated[prefix]
+	if !ok {
+		seen = make(map[kv.Key]struct{})
+		vmctx.speculation.iterated[prefix] = seen
+	}
+	return seen
+}
+
+// abortSpeculation stops the speculative run of the request, which can't be committed without running it again
+func (vmctx *VMContext) abortSpeculation(reason string) {
+	if vmctx.speculation != nil {
+		panic(xerrors.Errorf("%s: %w", reason, errSpeculationAborted))
+	}
+}
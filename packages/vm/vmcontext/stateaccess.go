@@ -5,6 +5,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 type chainStateWrapper struct {
@@ -21,10 +22,19 @@ func (vmctx *VMContext) chainState() chainStateWrapper {
 
 func (s chainStateWrapper) Has(name kv.Key) (bool, error) {
 	s.vmctx.solidStateBaseline.MustValidate()
+	s.vmctx.BurnGas(gas.StorageRead)
 
 	if _, ok := s.vmctx.currentStateUpdate.Mutations().Sets[name]; ok {
 		return true, nil
 	}
+	if s.vmctx.speculation != nil {
+		v, err := s.vmctx.virtualState.KVStore().Get(name)
+		if err != nil {
+			return false, err
+		}
+		s.vmctx.recordRead(name, v)
+		return v != nil, nil
+	}
 	return s.vmctx.virtualState.KVStore().Has(name)
 }
 
@@ -51,13 +61,19 @@ func (s chainStateWrapper) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) e
 
 	for k := range s.vmctx.currentStateUpdate.Mutations().Sets {
 		if k.HasPrefix(prefix) {
+			s.vmctx.BurnGas(gas.StorageRead)
 			if !f(k) {
 				return nil
 			}
 		}
 	}
+	seen := s.vmctx.recordIteration(prefix)
 	return s.vmctx.virtualState.KVStore().IterateKeys(prefix, func(k kv.Key) bool {
+		if seen != nil {
+			seen[k] = struct{}{}
+		}
 		if !s.vmctx.currentStateUpdate.Mutations().Contains(k) {
+			s.vmctx.BurnGas(gas.StorageRead)
 			return f(k)
 		}
 		return true
@@ -91,7 +107,11 @@ func (s chainStateWrapper) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) b
 			keys = append(keys, k)
 		}
 	}
+	seen := s.vmctx.recordIteration(prefix)
 	err := s.vmctx.virtualState.KVStore().IterateKeysSorted(prefix, func(k kv.Key) bool {
+		if seen != nil {
+			seen[k] = struct{}{}
+		}
 		if !s.vmctx.currentStateUpdate.Mutations().Contains(k) {
 			keys = append(keys, k)
 		}
@@ -102,6 +122,7 @@ func (s chainStateWrapper) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) b
 	}
 	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
 	for _, k := range keys {
+		s.vmctx.BurnGas(gas.StorageRead)
 		if !f(k) {
 			break
 		}
@@ -111,22 +132,29 @@ func (s chainStateWrapper) IterateKeysSorted(prefix kv.Key, f func(key kv.Key) b
 
 func (s chainStateWrapper) Get(name kv.Key) ([]byte, error) {
 	s.vmctx.solidStateBaseline.MustValidate()
+	s.vmctx.BurnGas(gas.StorageRead)
 
 	v, ok := s.vmctx.currentStateUpdate.Mutations().Sets[name]
 	if ok {
 		return v, nil
 	}
-	return s.vmctx.virtualState.KVStore().Get(name)
+	v, err := s.vmctx.virtualState.KVStore().Get(name)
+	if err == nil {
+		s.vmctx.recordRead(name, v)
+	}
+	return v, err
 }
 
 func (s chainStateWrapper) Del(name kv.Key) {
 	s.vmctx.solidStateBaseline.MustValidate()
+	s.vmctx.BurnGas(gas.StorageWrite + uint64(len(name))*gas.StoragePerByte)
 
 	s.vmctx.currentStateUpdate.Mutations().Del(name)
 }
 
 func (s chainStateWrapper) Set(name kv.Key, value []byte) {
 	s.vmctx.solidStateBaseline.MustValidate()
+	s.vmctx.BurnGas(gas.StorageWrite + uint64(len(name)+len(value))*gas.StoragePerByte)
 
 	s.vmctx.currentStateUpdate.Mutations().Set(name, value)
 }
@@ -134,9 +162,19 @@ func (s chainStateWrapper) Set(name kv.Key, value []byte) {
 func (vmctx *VMContext) State() kv.KVStore {
 	vmctx.solidStateBaseline.MustValidate()
 
-	return subrealm.New(vmctx.chainState(), kv.Key(vmctx.CurrentContractHname().Bytes()))
+	return contractState{subrealm.New(vmctx.chainState(), kv.Key(vmctx.CurrentContractHname().Bytes()))}
+}
+
+// contractState is the partition of the contract in the state of the chain
+type contractState struct {
+	kv.KVStore
 }
 
+// PostponeLedgerCheck makes the accounts contract skip the check of the ledger in each token movement, so the keys
+// of all accounts are neither read by the request nor recorded as read by its speculative run.
+// The ledger is checked when the request is finalized
+func (contractState) PostponeLedgerCheck() {}
+
 func (s chainStateWrapper) MustGet(key kv.Key) []byte {
 	return kv.MustGet(s, key)
 }
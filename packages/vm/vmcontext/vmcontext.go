@@ -10,9 +10,12 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
@@ -43,11 +46,14 @@ type VMContext struct {
 	blockContextCloseSeq []iscp.Hname
 	log                  *logger.Logger
 	blockOutputCount     uint8
+	randomBeacon         []byte
 	// fee related
-	validatorFeeTarget *iscp.AgentID // provided by validator
-	feeColor           colored.Color
-	ownerFee           uint64
-	validatorFee       uint64
+	validatorFeeTarget     *iscp.AgentID // provided by validator
+	validatorParticipation []*governance.ValidatorParticipation
+	feeColor               colored.Color
+	ownerFee               uint64
+	validatorFee           uint64
+	requestFee             uint64 // fee charged from the current request
 	// events related
 	maxEventSize    uint16
 	maxEventsPerReq uint16
@@ -64,6 +70,13 @@ type VMContext struct {
 	lastTotalAssets          colored.Balances
 	callStack                []*callContext
 	exceededBlockOutputLimit bool
+	// gas related
+	gasBudget   uint64
+	gasBurned   uint64
+	gasMetering bool
+	// speculative execution related
+	speculation *speculation        // not nil, if the request is run speculatively
+	writtenKeys map[kv.Key]struct{} // keys written in the block, tracked to validate the speculative runs
 }
 
 type callContext struct {
@@ -116,18 +129,20 @@ func CreateVMContext(task *vm.VMTask) *VMContext {
 	optimisticStateAccess.ApplyStateUpdates(openingStateUpdate)
 
 	ret := &VMContext{
-		chainID:              chainID,
-		chainInput:           task.ChainInput,
-		txBuilder:            txb,
-		virtualState:         optimisticStateAccess,
-		solidStateBaseline:   task.SolidStateBaseline,
-		validatorFeeTarget:   task.ValidatorFeeTarget,
-		processors:           task.Processors,
-		blockContext:         make(map[iscp.Hname]*blockContext),
-		blockContextCloseSeq: make([]iscp.Hname, 0),
-		log:                  task.Log,
-		entropy:              task.Entropy,
-		callStack:            make([]*callContext, 0),
+		chainID:                chainID,
+		chainInput:             task.ChainInput,
+		txBuilder:              txb,
+		virtualState:           optimisticStateAccess,
+		solidStateBaseline:     task.SolidStateBaseline,
+		validatorFeeTarget:     task.ValidatorFeeTarget,
+		validatorParticipation: task.ValidatorParticipation,
+		processors:             task.Processors,
+		blockContext:           make(map[iscp.Hname]*blockContext),
+		blockContextCloseSeq:   make([]iscp.Hname, 0),
+		log:                    task.Log,
+		entropy:                task.Entropy,
+		randomBeacon:           task.RandomBeacon,
+		callStack:              make([]*callContext, 0),
 	}
 	// consume chain input
 	err = txb.ConsumeAliasInput(task.ChainInput.Address())
@@ -158,6 +173,9 @@ func (vmctx *VMContext) BuildTransactionEssence(stateHash hashing.HashValue, tim
 // return nil for normal block and rotation address for rotation block
 func (vmctx *VMContext) CloseVMContext(numRequests, numSuccess, numOffLedger uint16) (uint32, hashing.HashValue, time.Time, ledgerstate.Address) {
 	rotationAddr := vmctx.mustSaveBlockInfo(numRequests, numSuccess, numOffLedger)
+	if rotationAddr == nil {
+		vmctx.distributeValidatorRewards()
+	}
 	vmctx.closeBlockContexts()
 
 	blockIndex := vmctx.virtualState.BlockIndex()
@@ -202,6 +220,9 @@ func (vmctx *VMContext) mustSaveBlockInfo(numRequests, numSuccess, numOffLedger
 	if idx != blockInfo.BlockIndex {
 		vmctx.log.Panicf("CloseVMContext: inconsistent block index")
 	}
+	if vmctx.randomBeacon != nil {
+		blocklog.SaveRandomBeacon(vmctx.State(), blockInfo.BlockIndex, vmctx.randomBeacon)
+	}
 	if vmctx.virtualState.PreviousStateHash() != blockInfo.PreviousStateHash {
 		vmctx.log.Panicf("CloseVMContext: inconsistent previous state hash")
 	}
@@ -211,12 +232,68 @@ func (vmctx *VMContext) mustSaveBlockInfo(numRequests, numSuccess, numOffLedger
 		vmctx.GoverningAddress(),
 		vmctx.chainInput.GetStateIndex(),
 	)
+	vmctx.pruneBlocklog()
 	vmctx.virtualState.ApplyStateUpdates(vmctx.currentStateUpdate)
 	vmctx.currentStateUpdate = nil // invalidate
 
 	return nil
 }
 
+// pruneBlocklog deletes receipts and events of old blocks according to the pruning policy of the chain
+func (vmctx *VMContext) pruneBlocklog() {
+	vmctx.pushCallContext(governance.Contract.Hname(), nil, nil)
+	policy := governance.GetPruningPolicy(vmctx.State())
+	vmctx.popCallContext()
+
+	vmctx.pushCallContext(blocklog.Contract.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	err := blocklog.PruneBlocks(vmctx.State(), vmctx.virtualState.BlockIndex(), policy.ReceiptsToKeep, vmctx.isNonceWithinTolerance)
+	if err != nil {
+		vmctx.log.Panicf("pruneBlocklog: %v", err)
+	}
+}
+
+// isNonceWithinTolerance returns true for the receipts of off-ledger requests which would pass the nonce check.
+// These receipts are needed to recognize the replayed requests
+func (vmctx *VMContext) isNonceWithinTolerance(rec *blocklog.RequestReceipt) bool {
+	req, ok := rec.Request.(*request.OffLedger)
+	if !ok {
+		return false
+	}
+	vmctx.pushCallContext(accounts.Contract.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	maxAssumed := accounts.GetMaxAssumedNonce(vmctx.State(), req.SenderAddress())
+	return maxAssumed < blocklog.OffLedgerNonceStrictOrderTolerance ||
+		req.Nonce() > maxAssumed-blocklog.OffLedgerNonceStrictOrderTolerance
+}
+
+// validatorFeeAccount is the account which collects the validator fees. If the participants of the block are known,
+// the fees are collected on the validator rewards account and split among the participants when the block is closed
+func (vmctx *VMContext) validatorFeeAccount() *iscp.AgentID {
+	if len(vmctx.validatorParticipation) > 0 {
+		return governance.ValidatorRewardsAccount(vmctx.chainID)
+	}
+	return vmctx.validatorFeeTarget
+}
+
+// distributeValidatorRewards allocates the validator fees collected in the block to the participants of the block
+func (vmctx *VMContext) distributeValidatorRewards() {
+	if len(vmctx.validatorParticipation) == 0 {
+		return
+	}
+	vmctx.currentStateUpdate = state.NewStateUpdate()
+	available := vmctx.getBalancesOfAccount(governance.ValidatorRewardsAccount(vmctx.chainID))
+
+	vmctx.pushCallContext(governance.Contract.Hname(), nil, nil)
+	governance.DistributeValidatorRewards(vmctx.State(), available, vmctx.validatorParticipation)
+	vmctx.popCallContext()
+
+	vmctx.virtualState.ApplyStateUpdates(vmctx.currentStateUpdate)
+	vmctx.currentStateUpdate = nil
+}
+
 // closeBlockContexts closing block contexts in deterministic FIFO sequence
 func (vmctx *VMContext) closeBlockContexts() {
 	vmctx.currentStateUpdate = state.NewStateUpdate()
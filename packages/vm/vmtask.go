@@ -11,6 +11,7 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 )
 
@@ -29,9 +30,13 @@ type VMTask struct {
 	SolidStateBaseline       coreutil.StateBaseline
 	Requests                 []iscp.Request
 	ProcessedRequestsCount   uint16
+	CommittedSpeculativeRuns uint16 // number of processed requests taken from their speculative runs
 	Timestamp                time.Time
 	Entropy                  hashing.HashValue
+	RandomBeacon             []byte // threshold BLS signature of the committee over blocklog.RandomBeaconMessage
 	ValidatorFeeTarget       *iscp.AgentID
+	ValidatorParticipation   []*governance.ValidatorParticipation // if not empty, the validator fees are split among them
+	Parallelism              int                                  // number of off-ledger requests run concurrently. Sequential run if < 2
 	Log                      *logger.Logger
 	OnFinish                 func(callResult dict.Dict, callError error, vmError error)
 	ResultTransactionEssence *ledgerstate.TransactionEssence // if not nil it is a normal block
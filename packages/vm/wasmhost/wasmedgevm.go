@@ -8,12 +8,14 @@ package wasmhost
 import (
 	"errors"
 
+	"github.com/iotaledger/wasp/packages/vm/gas"
 	"github.com/second-state/WasmEdge-go/wasmedge"
 )
 
 type WasmEdgeVM struct {
 	WasmVMBase
 	edge *wasmedge.VM
+	gas       *wasmedge.Global
 	memory    *wasmedge.Memory
 	module    *wasmedge.ImportObject
 	store     *wasmedge.Store
@@ -96,7 +98,11 @@ func (vm *WasmEdgeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 }
 
 func (vm *WasmEdgeVM) LoadWasm(wasmData []byte) error {
-	err := vm.edge.LoadWasmBuffer(wasmData)
+	wasmData, err := gas.InjectWasmMetering(wasmData)
+	if err != nil {
+		return err
+	}
+	err = vm.edge.LoadWasmBuffer(wasmData)
 	if err != nil {
 		return err
 	}
@@ -116,6 +122,10 @@ func (vm *WasmEdgeVM) Instantiate() error {
 	if vm.memory == nil {
 		return errors.New("no memory export")
 	}
+	vm.gas = vm.edge.GetStore().FindGlobal(gas.WasmGasGlobal)
+	if vm.gas == nil {
+		return errors.New("no gas export")
+	}
 	return nil
 }
 
@@ -149,6 +159,14 @@ func (vm *WasmEdgeVM) VMGetBytes(offset int32, size int32) []byte {
 	return bytes
 }
 
+func (vm *WasmEdgeVM) VMGetGas() (uint64, error) {
+	value, ok := vm.gas.GetValue().(int64)
+	if !ok {
+		return 0, errors.New("wasmedge.VMGetGas: gas global is not i64")
+	}
+	return uint64(value), nil
+}
+
 func (vm *WasmEdgeVM) VMGetSize() int32 {
 	return int32(vm.memory.GetPageSize() << 16)
 }
@@ -163,6 +181,10 @@ func (vm *WasmEdgeVM) VMSetBytes(offset int32, size int32, bytes []byte) int32 {
 	return int32(len(bytes))
 }
 
+func (vm *WasmEdgeVM) VMSetGas(gas uint64) {
+	vm.gas.SetValue(int64(gas))
+}
+
 func (vm *WasmEdgeVM) exportAbort(args []interface{}) []interface{} {
 	errMsg := args[0].(int32)
 	fileName := args[1].(int32)
@@ -6,11 +6,15 @@
 package wasmhost
 
 import (
+	"errors"
+
+	"github.com/iotaledger/wasp/packages/vm/gas"
 	"github.com/wasmerio/wasmer-go/wasmer"
 )
 
 type WasmerVM struct {
 	WasmVMBase
+	gas      *wasmer.Global
 	instance *wasmer.Instance
 	linker   *wasmer.ImportObject
 	memory   *wasmer.Memory
@@ -69,7 +73,10 @@ func (vm *WasmerVM) importFunc(nrParams, nrResults int, function func([]wasmer.V
 }
 
 func (vm *WasmerVM) LoadWasm(wasmData []byte) error {
-	var err error
+	wasmData, err := gas.InjectWasmMetering(wasmData)
+	if err != nil {
+		return err
+	}
 	vm.module, err = wasmer.NewModule(vm.store, wasmData)
 	if err != nil {
 		return err
@@ -79,6 +86,10 @@ func (vm *WasmerVM) LoadWasm(wasmData []byte) error {
 		return err
 	}
 	vm.memory, err = vm.instance.Exports.GetMemory("memory")
+	if err != nil {
+		return err
+	}
+	vm.gas, err = vm.instance.Exports.GetGlobal(gas.WasmGasGlobal)
 	return err
 }
 
@@ -111,6 +122,25 @@ func (vm *WasmerVM) UnsafeMemory() []byte {
 	return vm.memory.Data()
 }
 
+func (vm *WasmerVM) VMGetGas() (uint64, error) {
+	value, err := vm.gas.Get()
+	if err != nil {
+		return 0, errors.New("wasmer.VMGetGas: " + err.Error())
+	}
+	gasLeft, ok := value.(int64)
+	if !ok {
+		return 0, errors.New("wasmer.VMGetGas: gas global is not i64")
+	}
+	return uint64(gasLeft), nil
+}
+
+func (vm *WasmerVM) VMSetGas(gas uint64) {
+	err := vm.gas.Set(int64(gas), wasmer.I64)
+	if err != nil {
+		panic("wasmer.VMSetGas: " + err.Error())
+	}
+}
+
 func (vm *WasmerVM) exportAbort(args []wasmer.Value) ([]wasmer.Value, error) {
 	errMsg := args[0].I32()
 	fileName := args[1].I32()
@@ -17,6 +17,7 @@ func Connect(h wasmlib.ScHost) wasmlib.ScHost {
 
 type WasmGoVM struct {
 	WasmVMBase
+	gas    uint64
 	scName string
 	onLoad func()
 }
@@ -64,6 +65,15 @@ func (vm *WasmGoVM) RunScFunction(index int32) error {
 	})
 }
 
+// VMGetGas returns the gas last set, Go code is not metered per instruction
+func (vm *WasmGoVM) VMGetGas() (uint64, error) {
+	return vm.gas, nil
+}
+
+func (vm *WasmGoVM) VMSetGas(gas uint64) {
+	vm.gas = gas
+}
+
 func (vm *WasmGoVM) UnsafeMemory() []byte {
 	// no need to communicate through Wasm mem pool
 	return nil
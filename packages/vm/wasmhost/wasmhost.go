@@ -10,8 +10,16 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/wasmlib/go/wasmlib"
 )
 
+// GasMeter is charged with the gas burned by the Wasm code and the host calls
+type GasMeter interface {
+	BurnGas(gas uint64)
+	GasBudgetLeft() uint64
+}
+
 type WasmStore interface {
 	GetKvStore(id int32) *KvStoreHost
+	// GetGasMeter returns the gas meter of the current call or nil when the gas is not metered
+	GetGasMeter() GasMeter
 }
 
 type WasmHost struct {
@@ -42,6 +50,10 @@ func (host *WasmHost) getKvStore(id int32) *KvStoreHost {
 	return host.store.GetKvStore(id)
 }
 
+func (host *WasmHost) getGasMeter() GasMeter {
+	return host.store.GetGasMeter()
+}
+
 func (host *WasmHost) InitVM(vm WasmVM, store WasmStore) error {
 	host.store = store
 	return vm.LinkHost(vm, host)
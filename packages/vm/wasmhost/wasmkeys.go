@@ -51,6 +51,10 @@ const (
 	// When anything changes to the keys give this one a different value
 	// and make sure that the client side is updated accordingly
 	KeyZzzzzzz = int32(-41)
+
+	// keys added after KeyZzzzzzz keep the ids of the keys above unchanged,
+	// so the contracts built with the previous version still work
+	KeyRandomSeed = int32(-42)
 )
 
 // associate names with predefined key ids
@@ -87,6 +91,7 @@ var predefinedKeyMap = map[string]int32{
 	"$params":          KeyParams,
 	"$post":            KeyPost,
 	"$random":          KeyRandom,
+	"$randomSeed":      KeyRandomSeed,
 	"$requestID":       KeyRequestID,
 	"$results":         KeyResults,
 	"$return":          KeyReturn,
@@ -100,7 +105,7 @@ var predefinedKeyMap = map[string]int32{
 var predefinedKeys = initKeyMap()
 
 func initKeyMap() [][]byte {
-	keys := make([][]byte, len(predefinedKeyMap)+1)
+	keys := make([][]byte, len(predefinedKeyMap)+2) // the id of KeyZzzzzzz is not mapped
 	for k, v := range predefinedKeyMap {
 		keys[-v] = []byte(k)
 	}
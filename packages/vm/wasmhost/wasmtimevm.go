@@ -7,11 +7,13 @@ import (
 	"errors"
 
 	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 type WasmTimeVM struct {
 	WasmVMBase
 	engine    *wasmtime.Engine
+	gas       *wasmtime.Global
 	instance  *wasmtime.Instance
 	interrupt *wasmtime.InterruptHandle
 	linker    *wasmtime.Linker
@@ -78,6 +80,10 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) (err error) {
 }
 
 func (vm *WasmTimeVM) LoadWasm(wasmData []byte) (err error) {
+	wasmData, err = gas.InjectWasmMetering(wasmData)
+	if err != nil {
+		return err
+	}
 	vm.module, err = wasmtime.NewModule(vm.engine, wasmData)
 	if err != nil {
 		return err
@@ -98,6 +104,14 @@ func (vm *WasmTimeVM) Instantiate() (err error) {
 	if vm.memory == nil {
 		return errors.New("not a memory type")
 	}
+	global := vm.instance.GetExport(vm.store, gas.WasmGasGlobal)
+	if global == nil {
+		return errors.New("no gas export")
+	}
+	vm.gas = global.Global()
+	if vm.gas == nil {
+		return errors.New("not a global type")
+	}
 	return nil
 }
 
@@ -127,3 +141,14 @@ func (vm *WasmTimeVM) RunScFunction(index int32) error {
 func (vm *WasmTimeVM) UnsafeMemory() []byte {
 	return vm.memory.UnsafeData(vm.store)
 }
+
+func (vm *WasmTimeVM) VMGetGas() (uint64, error) {
+	return uint64(vm.gas.Get(vm.store).I64()), nil
+}
+
+func (vm *WasmTimeVM) VMSetGas(gas uint64) {
+	err := vm.gas.Set(vm.store, wasmtime.ValI64(int64(gas)))
+	if err != nil {
+		panic("VMSetGas: " + err.Error())
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/iotaledger/wasp/packages/vm/gas"
 )
 
 const (
@@ -48,13 +50,16 @@ type WasmVM interface {
 	RunScFunction(index int32) error
 	UnsafeMemory() []byte
 	VMGetBytes(offset int32, size int32) []byte
+	VMGetGas() (uint64, error)
 	VMGetSize() int32
 	VMSetBytes(offset int32, size int32, bytes []byte) int32
+	VMSetGas(gas uint64)
 }
 
 type WasmVMBase struct {
 	impl           WasmVM
 	host           *WasmHost
+	gasSet         uint64
 	panicErr       error
 	result         []byte
 	resultKeyID    int32
@@ -88,10 +93,54 @@ func (vm *WasmVMBase) getKvStore(id int32) *KvStoreHost {
 	return vm.host.getKvStore(id)
 }
 
+// burnWasmGas charges the gas meter with the gas used by the Wasm code since the gas was last set
+// Returns true if the Wasm code has run out of gas
+func (vm *WasmVMBase) burnWasmGas() (bool, error) {
+	left, err := vm.impl.VMGetGas()
+	if err != nil {
+		return false, err
+	}
+	exhausted := left == gas.WasmGasExhausted
+	if exhausted {
+		left = 0
+	}
+	used := vm.gasSet - left
+	vm.gasSet = left
+	if meter := vm.host.getGasMeter(); meter != nil {
+		meter.BurnGas(used)
+	}
+	return exhausted, nil
+}
+
+// chargeHostCall is used at the start of every host function. It charges the gas
+// used by the Wasm code so far together with the cost of the host call
+func (vm *WasmVMBase) chargeHostCall() {
+	if _, err := vm.burnWasmGas(); err != nil {
+		panic(err)
+	}
+	if meter := vm.host.getGasMeter(); meter != nil {
+		meter.BurnGas(gas.WasmHostCall)
+	}
+}
+
+// refuelWasm sets the gas of the Wasm code to what is left of the gas budget.
+// It is used at the end of every host function, because the host call could burn any
+// amount of gas, for example by calling another smart contract
+func (vm *WasmVMBase) refuelWasm() {
+	left := gas.MaxGasPerRequest
+	if meter := vm.host.getGasMeter(); meter != nil {
+		left = meter.GasBudgetLeft()
+	}
+	vm.gasSet = left
+	vm.impl.VMSetGas(left)
+}
+
 func (vm *WasmVMBase) HostAbort(errMsg, fileName, line, col int32) {
 	// crude implementation assumes texts to only use ASCII part of UTF-16
 
 	defer vm.catchPanicMessage()
+	vm.chargeHostCall()
+	defer vm.refuelWasm()
 
 	// null-terminated UTF-16 error message
 	str1 := make([]byte, 0)
@@ -114,6 +163,8 @@ func (vm *WasmVMBase) HostAbort(errMsg, fileName, line, col int32) {
 
 func (vm *WasmVMBase) HostFdWrite(_fd, iovs, _size, written int32) int32 {
 	defer vm.catchPanicMessage()
+	vm.chargeHostCall()
+	defer vm.refuelWasm()
 
 	host := vm.getKvStore(0)
 	host.TraceAllf("HostFdWrite(...)")
@@ -131,6 +182,8 @@ func (vm *WasmVMBase) HostFdWrite(_fd, iovs, _size, written int32) int32 {
 
 func (vm *WasmVMBase) HostGetBytes(objID, keyID, typeID, stringRef, size int32) int32 {
 	defer vm.catchPanicMessage()
+	vm.chargeHostCall()
+	defer vm.refuelWasm()
 
 	host := vm.getKvStore(0)
 	host.TraceAllf("HostGetBytes(o%d,k%d,t%d,r%d,s%d)", objID, keyID, typeID, stringRef, size)
@@ -179,6 +232,8 @@ func (vm *WasmVMBase) HostGetBytes(objID, keyID, typeID, stringRef, size int32)
 
 func (vm *WasmVMBase) HostGetKeyID(keyRef, size int32) int32 {
 	defer vm.catchPanicMessage()
+	vm.chargeHostCall()
+	defer vm.refuelWasm()
 
 	host := vm.getKvStore(0)
 	host.TraceAllf("HostGetKeyID(r%d,s%d)", keyRef, size)
@@ -195,6 +250,8 @@ func (vm *WasmVMBase) HostGetKeyID(keyRef, size int32) int32 {
 
 func (vm *WasmVMBase) HostGetObjectID(objID, keyID, typeID int32) int32 {
 	defer vm.catchPanicMessage()
+	vm.chargeHostCall()
+	defer vm.refuelWasm()
 
 	host := vm.getKvStore(0)
 	host.TraceAllf("HostGetObjectID(o%d,k%d,t%d)", objID, keyID, typeID)
@@ -203,6 +260,8 @@ func (vm *WasmVMBase) HostGetObjectID(objID, keyID, typeID int32) int32 {
 
 func (vm *WasmVMBase) HostSetBytes(objID, keyID, typeID, stringRef, size int32) {
 	defer vm.catchPanicMessage()
+	vm.chargeHostCall()
+	defer vm.refuelWasm()
 
 	host := vm.getKvStore(0)
 	host.TraceAllf("HostSetBytes(o%d,k%d,t%d,r%d,s%d)", objID, keyID, typeID, stringRef, size)
@@ -248,12 +307,9 @@ func (vm *WasmVMBase) Run(runner func() error) (err error) {
 
 	if vm.timeoutStarted {
 		// no need to wrap nested calls in timeout code
+		vm.refuelWasm()
 		err = runner()
-		if vm.panicErr != nil {
-			err = vm.panicErr
-			vm.panicErr = nil
-		}
-		return err
+		return vm.runResult(err)
 	}
 
 	timeout := defaultTimeout
@@ -276,14 +332,28 @@ func (vm *WasmVMBase) Run(runner func() error) (err error) {
 		}
 	}()
 
+	vm.refuelWasm()
 	vm.timeoutStarted = true
 	err = runner()
 	done <- true
 	vm.timeoutStarted = false
+	return vm.runResult(err)
+}
+
+// runResult determines the error of the finished run and charges the gas used by the Wasm code.
+// Running out of gas makes the Wasm code trap, in which case the error is replaced by gas.ErrNotEnoughGas
+func (vm *WasmVMBase) runResult(err error) error {
 	if vm.panicErr != nil {
 		err = vm.panicErr
 		vm.panicErr = nil
 	}
+	exhausted, errGas := vm.burnWasmGas()
+	if errGas != nil {
+		return errGas
+	}
+	if exhausted {
+		return gas.ErrNotEnoughGas
+	}
 	return err
 }
 
@@ -314,6 +314,12 @@ func (ctx ScFuncContext) Post(chainID ScChainID, hContract, hFunction ScHname, p
 
 // TODO expose Entropy function
 
+// retrieve the seed derived from the random beacon of the block, which can't be influenced by the block proposer
+// note that the seed is the same for all requests in the block
+func (ctx ScFuncContext) RandomSeed() ScHash {
+	return Root.GetHash(KeyRandomSeed).Value()
+}
+
 // generates a random value from 0 to max (exclusive max) using a deterministic RNG
 func (ctx ScFuncContext) Random(max int64) int64 {
 	state := ScMutableMap{objID: OBJ_ID_STATE}
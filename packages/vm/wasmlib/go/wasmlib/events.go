@@ -4,6 +4,7 @@
 package wasmlib
 
 import (
+	"encoding/binary"
 	"strconv"
 )
 
@@ -99,3 +100,129 @@ func (e *EventEncoder) Uint32(value uint32) *EventEncoder {
 func (e *EventEncoder) Uint64(value uint64) *EventEncoder {
 	return e.String(strconv.FormatUint(value, 10))
 }
+
+// \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\
+
+// TypedEventEncoder encodes a typed event: the name and the named fields with their schema types.
+// Up to 4 fields can be marked as indexed, the events can be queried by the values of the indexed fields
+type TypedEventEncoder struct {
+	name   string
+	count  uint16
+	fields *BytesEncoder
+	last   int
+}
+
+func NewTypedEventEncoder(eventName string) *TypedEventEncoder {
+	return &TypedEventEncoder{name: eventName, fields: NewBytesEncoder(), last: -1}
+}
+
+func (e *TypedEventEncoder) field(name, typ string, value []byte) *TypedEventEncoder {
+	e.fields.String(name).String(typ).Bytes(value)
+	// the position of the indexed flag, which follows the value
+	e.last = len(e.fields.data)
+	e.fields.Bool(false)
+	e.count++
+	return e
+}
+
+// Indexed marks the last added field as indexed
+func (e *TypedEventEncoder) Indexed() *TypedEventEncoder {
+	if e.last < 0 {
+		Panic("no event field to index")
+	}
+	e.fields.data[e.last] = 1
+	return e
+}
+
+func (e *TypedEventEncoder) Address(name string, value ScAddress) *TypedEventEncoder {
+	return e.field(name, "Address", value.Bytes())
+}
+
+func (e *TypedEventEncoder) AgentID(name string, value ScAgentID) *TypedEventEncoder {
+	return e.field(name, "AgentID", value.Bytes())
+}
+
+func (e *TypedEventEncoder) Bool(name string, value bool) *TypedEventEncoder {
+	if value {
+		return e.field(name, "Bool", []byte{1})
+	}
+	return e.field(name, "Bool", []byte{0})
+}
+
+func (e *TypedEventEncoder) Bytes(name string, value []byte) *TypedEventEncoder {
+	return e.field(name, "Bytes", value)
+}
+
+func (e *TypedEventEncoder) ChainID(name string, value ScChainID) *TypedEventEncoder {
+	return e.field(name, "ChainID", value.Bytes())
+}
+
+func (e *TypedEventEncoder) Color(name string, value ScColor) *TypedEventEncoder {
+	return e.field(name, "Color", value.Bytes())
+}
+
+func (e *TypedEventEncoder) Emit() {
+	encoder := NewBytesEncoder().String(e.name).Uint16(e.count)
+	encoder.data = append(encoder.data, e.fields.Data()...)
+	SetBytes(1, KeyEvent, TYPE_BYTES, encoder.Data())
+}
+
+func (e *TypedEventEncoder) Hash(name string, value ScHash) *TypedEventEncoder {
+	return e.field(name, "Hash", value.Bytes())
+}
+
+func (e *TypedEventEncoder) Hname(name string, value ScHname) *TypedEventEncoder {
+	return e.field(name, "Hname", value.Bytes())
+}
+
+func (e *TypedEventEncoder) Int8(name string, value int8) *TypedEventEncoder {
+	return e.field(name, "Int8", []byte{byte(value)})
+}
+
+func (e *TypedEventEncoder) Int16(name string, value int16) *TypedEventEncoder {
+	bytes := make([]byte, 2)
+	binary.LittleEndian.PutUint16(bytes, uint16(value))
+	return e.field(name, "Int16", bytes)
+}
+
+func (e *TypedEventEncoder) Int32(name string, value int32) *TypedEventEncoder {
+	bytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bytes, uint32(value))
+	return e.field(name, "Int32", bytes)
+}
+
+func (e *TypedEventEncoder) Int64(name string, value int64) *TypedEventEncoder {
+	bytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bytes, uint64(value))
+	return e.field(name, "Int64", bytes)
+}
+
+func (e *TypedEventEncoder) RequestID(name string, value ScRequestID) *TypedEventEncoder {
+	return e.field(name, "RequestID", value.Bytes())
+}
+
+func (e *TypedEventEncoder) String(name, value string) *TypedEventEncoder {
+	return e.field(name, "String", []byte(value))
+}
+
+func (e *TypedEventEncoder) Uint8(name string, value uint8) *TypedEventEncoder {
+	return e.field(name, "Uint8", []byte{value})
+}
+
+func (e *TypedEventEncoder) Uint16(name string, value uint16) *TypedEventEncoder {
+	bytes := make([]byte, 2)
+	binary.LittleEndian.PutUint16(bytes, value)
+	return e.field(name, "Uint16", bytes)
+}
+
+func (e *TypedEventEncoder) Uint32(name string, value uint32) *TypedEventEncoder {
+	bytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bytes, value)
+	return e.field(name, "Uint32", bytes)
+}
+
+func (e *TypedEventEncoder) Uint64(name string, value uint64) *TypedEventEncoder {
+	bytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bytes, value)
+	return e.field(name, "Uint64", bytes)
+}
@@ -67,4 +67,7 @@ const (
 	KeyTransfers       = Key32(-39)
 	KeyUtility         = Key32(-40)
 	KeyZzzzzzz         = Key32(-41)
+
+	// keys added after KeyZzzzzzz keep the ids of the keys above unchanged
+	KeyRandomSeed = Key32(-42)
 )
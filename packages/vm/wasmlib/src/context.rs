@@ -357,6 +357,12 @@ impl ScFuncContext {
         (rnd as u64 % max as u64) as i64
     }
 
+    // retrieve the seed derived from the random beacon of the block, which can't be influenced by the block proposer
+    // note that the seed is the same for all requests in the block
+    pub fn random_seed(&self) -> ScHash {
+        ROOT.get_hash(&KEY_RANDOM_SEED).value()
+    }
+
     // retrieve the request id of this transaction
     pub fn request_id(&self) -> ScRequestID {
         ROOT.get_request_id(&KEY_REQUEST_ID).value()
@@ -1,8 +1,10 @@
 // Copyright 2020 IOTA Stiftung
 // SPDX-License-Identifier: Apache-2.0
 
+use crate::bytes::*;
 use crate::context::*;
 use crate::hashtypes::*;
+use crate::host::*;
 use crate::keys::*;
 
 // encodes separate entities into a byte buffer
@@ -115,3 +117,143 @@ impl EventEncoder {
         self.string(&value.to_string())
     }
 }
+
+// \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\
+
+// encodes a typed event: the name and the named fields with their schema types
+// up to 4 fields can be marked as indexed, the events can be queried by the values of the indexed fields
+pub struct TypedEventEncoder {
+    name: String,
+    count: u16,
+    fields: Vec<u8>,
+    last: Option<usize>,
+}
+
+impl TypedEventEncoder {
+    // constructs an encoder
+    pub fn new(event_name: &str) -> TypedEventEncoder {
+        TypedEventEncoder { name: event_name.to_string(), count: 0, fields: Vec::new(), last: None }
+    }
+
+    fn field(&mut self, name: &str, typ: &str, value: &[u8]) -> &TypedEventEncoder {
+        let mut encoder = BytesEncoder::new();
+        encoder.string(name);
+        encoder.string(typ);
+        encoder.bytes(value);
+        self.fields.extend_from_slice(&encoder.data());
+        // the position of the indexed flag, which follows the value
+        self.last = Some(self.fields.len());
+        self.fields.push(0);
+        self.count += 1;
+        self
+    }
+
+    // marks the last added field as indexed
+    pub fn indexed(&mut self) -> &TypedEventEncoder {
+        match self.last {
+            Some(pos) => self.fields[pos] = 1,
+            None => panic("no event field to index"),
+        }
+        self
+    }
+
+    // encodes an ScAddress field
+    pub fn address(&mut self, name: &str, value: &ScAddress) -> &TypedEventEncoder {
+        self.field(name, "Address", value.to_bytes())
+    }
+
+    // encodes an ScAgentID field
+    pub fn agent_id(&mut self, name: &str, value: &ScAgentID) -> &TypedEventEncoder {
+        self.field(name, "AgentID", value.to_bytes())
+    }
+
+    // encodes a Bool field as 0/1
+    pub fn bool(&mut self, name: &str, value: bool) -> &TypedEventEncoder {
+        self.field(name, "Bool", &[value as u8])
+    }
+
+    // encodes a Bytes field
+    pub fn bytes(&mut self, name: &str, value: &[u8]) -> &TypedEventEncoder {
+        self.field(name, "Bytes", value)
+    }
+
+    // encodes an ScChainID field
+    pub fn chain_id(&mut self, name: &str, value: &ScChainID) -> &TypedEventEncoder {
+        self.field(name, "ChainID", value.to_bytes())
+    }
+
+    // encodes an ScColor field
+    pub fn color(&mut self, name: &str, value: &ScColor) -> &TypedEventEncoder {
+        self.field(name, "Color", value.to_bytes())
+    }
+
+    // emits the encoded event
+    pub fn emit(&self) {
+        let mut encoder = BytesEncoder::new();
+        encoder.string(&self.name);
+        encoder.uint16(self.count);
+        let mut data = encoder.data();
+        data.extend_from_slice(&self.fields);
+        ROOT.get_bytes(&KEY_EVENT).set_value(&data);
+    }
+
+    // encodes an ScHash field
+    pub fn hash(&mut self, name: &str, value: &ScHash) -> &TypedEventEncoder {
+        self.field(name, "Hash", value.to_bytes())
+    }
+
+    // encodes an ScHname field
+    pub fn hname(&mut self, name: &str, value: ScHname) -> &TypedEventEncoder {
+        self.field(name, "Hname", &value.to_bytes())
+    }
+
+    // encodes an Int8 field
+    pub fn int8(&mut self, name: &str, value: i8) -> &TypedEventEncoder {
+        self.field(name, "Int8", &value.to_le_bytes())
+    }
+
+    // encodes an Int16 field
+    pub fn int16(&mut self, name: &str, value: i16) -> &TypedEventEncoder {
+        self.field(name, "Int16", &value.to_le_bytes())
+    }
+
+    // encodes an Int32 field
+    pub fn int32(&mut self, name: &str, value: i32) -> &TypedEventEncoder {
+        self.field(name, "Int32", &value.to_le_bytes())
+    }
+
+    // encodes an Int64 field
+    pub fn int64(&mut self, name: &str, value: i64) -> &TypedEventEncoder {
+        self.field(name, "Int64", &value.to_le_bytes())
+    }
+
+    // encodes an ScRequestID field
+    pub fn request_id(&mut self, name: &str, value: &ScRequestID) -> &TypedEventEncoder {
+        self.field(name, "RequestID", value.to_bytes())
+    }
+
+    // encodes an UTF-8 text string field
+    pub fn string(&mut self, name: &str, value: &str) -> &TypedEventEncoder {
+        self.field(name, "String", value.as_bytes())
+    }
+
+    // encodes an Uint8 field
+    pub fn uint8(&mut self, name: &str, value: u8) -> &TypedEventEncoder {
+        self.field(name, "Uint8", &[value])
+    }
+
+    // encodes an Uint16 field
+    pub fn uint16(&mut self, name: &str, value: u16) -> &TypedEventEncoder {
+        self.field(name, "Uint16", &value.to_le_bytes())
+    }
+
+    // encodes an Uint32 field
+    pub fn uint32(&mut self, name: &str, value: u32) -> &TypedEventEncoder {
+        self.field(name, "Uint32", &value.to_le_bytes())
+    }
+
+    // encodes an Uint64 field
+    pub fn uint64(&mut self, name: &str, value: u64) -> &TypedEventEncoder {
+        self.field(name, "Uint64", &value.to_le_bytes())
+    }
+}
@@ -79,4 +79,7 @@ pub const KEY_TRACE            : Key32 = Key32(-38);
 pub const KEY_TRANSFERS        : Key32 = Key32(-39);
 pub const KEY_UTILITY          : Key32 = Key32(-40);
 pub const KEY_ZZZZZZZ          : Key32 = Key32(-41);
+
+// keys added after KEY_ZZZZZZZ keep the ids of the keys above unchanged
+pub const KEY_RANDOM_SEED      : Key32 = Key32(-42);
 // @formatter:on
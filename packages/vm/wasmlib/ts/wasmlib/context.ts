@@ -329,6 +329,12 @@ export class ScFuncContext extends ScBaseContext implements ScViewCallContext, S
         return (Convert.toI64(seed.slice(0, 8)) as u64 % max as u64) as i64;
     }
 
+    // retrieve the seed derived from the random beacon of the block, which can't be influenced by the block proposer
+    // note that the seed is the same for all requests in the block
+    randomSeed(): ScHash {
+        return ROOT.getHash(keys.KEY_RANDOM_SEED).value();
+    }
+
     // retrieve the request id of this transaction
     requestID(): ScRequestID {
         return ROOT.getRequestID(keys.KEY_REQUEST_ID).value();
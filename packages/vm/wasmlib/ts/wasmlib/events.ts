@@ -4,6 +4,9 @@
 import {ScAddress, ScAgentID, ScChainID, ScColor, ScHash, ScHname, ScRequestID} from "./hashtypes";
 import * as keys from "./keys";
 import {base58Encode, ROOT} from "./context";
+import {BytesEncoder} from "./bytes";
+import {Convert} from "./convert";
+import {panic} from "./host";
 
 // encodes separate entities into a byte buffer
 export class EventEncoder {
@@ -113,3 +116,136 @@ export class EventEncoder {
         return this.string(value.toString());
     }
 }
+
+// \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\ // \\
+
+// encodes a typed event: the name and the named fields with their schema types
+// up to 4 fields can be marked as indexed, the events can be queried by the values of the indexed fields
+export class TypedEventEncoder {
+    name: string;
+    count: u16;
+    fields: BytesEncoder;
+    last: i32;
+
+    // constructs an encoder
+    constructor(eventName: string) {
+        this.name = eventName;
+        this.count = 0;
+        this.fields = new BytesEncoder();
+        this.last = -1;
+    }
+
+    field(name: string, typ: string, value: u8[]): TypedEventEncoder {
+        this.fields.string(name).string(typ).bytes(value);
+        // the position of the indexed flag, which follows the value
+        this.last = this.fields.buf.length;
+        this.fields.bool(false);
+        this.count++;
+        return this;
+    }
+
+    // marks the last added field as indexed
+    indexed(): TypedEventEncoder {
+        if (this.last < 0) {
+            panic("no event field to index");
+        }
+        this.fields.buf[this.last] = 1;
+        return this;
+    }
+
+    // encodes an ScAddress field
+    address(name: string, value: ScAddress): TypedEventEncoder {
+        return this.field(name, "Address", value.toBytes());
+    }
+
+    // encodes an ScAgentID field
+    agentID(name: string, value: ScAgentID): TypedEventEncoder {
+        return this.field(name, "AgentID", value.toBytes());
+    }
+
+    // encodes a Bool field as 0/1
+    bool(name: string, value: bool): TypedEventEncoder {
+        return this.field(name, "Bool", [(value ? 1 : 0) as u8]);
+    }
+
+    // encodes a Bytes field
+    bytes(name: string, value: u8[]): TypedEventEncoder {
+        return this.field(name, "Bytes", value);
+    }
+
+    // encodes an ScChainID field
+    chainID(name: string, value: ScChainID): TypedEventEncoder {
+        return this.field(name, "ChainID", value.toBytes());
+    }
+
+    // encodes an ScColor field
+    color(name: string, value: ScColor): TypedEventEncoder {
+        return this.field(name, "Color", value.toBytes());
+    }
+
+    // emits the encoded event
+    emit(): void {
+        let data = new BytesEncoder().string(this.name).uint16(this.count).data();
+        ROOT.getBytes(keys.KEY_EVENT).setValue(data.concat(this.fields.data()));
+    }
+
+    // encodes an ScHash field
+    hash(name: string, value: ScHash): TypedEventEncoder {
+        return this.field(name, "Hash", value.toBytes());
+    }
+
+    // encodes an ScHname field
+    hname(name: string, value: ScHname): TypedEventEncoder {
+        return this.field(name, "Hname", value.toBytes());
+    }
+
+    // encodes an Int8 field
+    int8(name: string, value: i8): TypedEventEncoder {
+        return this.field(name, "Int8", [value as u8]);
+    }
+
+    // encodes an Int16 field
+    int16(name: string, value: i16): TypedEventEncoder {
+        return this.field(name, "Int16", Convert.fromI16(value));
+    }
+
+    // encodes an Int32 field
+    int32(name: string, value: i32): TypedEventEncoder {
+        return this.field(name, "Int32", Convert.fromI32(value));
+    }
+
+    // encodes an Int64 field
+    int64(name: string, value: i64): TypedEventEncoder {
+        return this.field(name, "Int64", Convert.fromI64(value));
+    }
+
+    // encodes an ScRequestID field
+    requestID(name: string, value: ScRequestID): TypedEventEncoder {
+        return this.field(name, "RequestID", value.toBytes());
+    }
+
+    // encodes an UTF-8 text string field
+    string(name: string, value: string): TypedEventEncoder {
+        return this.field(name, "String", Convert.fromString(value));
+    }
+
+    // encodes an Uint8 field
+    uint8(name: string, value: u8): TypedEventEncoder {
+        return this.field(name, "Uint8", [value]);
+    }
+
+    // encodes an Uint16 field
+    uint16(name: string, value: u16): TypedEventEncoder {
+        return this.field(name, "Uint16", Convert.fromI16(value as i16));
+    }
+
+    // encodes an Uint32 field
+    uint32(name: string, value: u32): TypedEventEncoder {
+        return this.field(name, "Uint32", Convert.fromI32(value as i32));
+    }
+
+    // encodes an Uint64 field
+    uint64(name: string, value: u64): TypedEventEncoder {
+        return this.field(name, "Uint64", Convert.fromI64(value as i64));
+    }
+}
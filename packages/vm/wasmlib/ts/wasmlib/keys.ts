@@ -72,4 +72,7 @@ export const KEY_TRACE            = new Key32(-38);
 export const KEY_TRANSFERS        = new Key32(-39);
 export const KEY_UTILITY          = new Key32(-40);
 export const KEY_ZZZZZZZ          = new Key32(-41);
+
+// keys added after KEY_ZZZZZZZ keep the ids of the keys above unchanged
+export const KEY_RANDOM_SEED      = new Key32(-42);
 // @formatter:on
@@ -36,6 +36,7 @@ var typeIds = map[int32]int32{
 	wasmhost.KeyParams:          wasmhost.OBJTYPE_MAP,
 	wasmhost.KeyPost:            wasmhost.OBJTYPE_BYTES,
 	wasmhost.KeyRandom:          wasmhost.OBJTYPE_BYTES,
+	wasmhost.KeyRandomSeed:      wasmhost.OBJTYPE_HASH,
 	wasmhost.KeyRequestID:       wasmhost.OBJTYPE_REQUEST_ID,
 	wasmhost.KeyResults:         wasmhost.OBJTYPE_MAP,
 	wasmhost.KeyReturn:          wasmhost.OBJTYPE_MAP,
@@ -92,6 +93,8 @@ func (o *ScContext) GetBytes(keyID, typeID int32) []byte {
 		return ctx.ContractCreator().Bytes()
 	case wasmhost.KeyRandom:
 		return ctx.GetEntropy().Bytes()
+	case wasmhost.KeyRandomSeed:
+		return ctx.GetRandomSeed().Bytes()
 	case wasmhost.KeyRequestID:
 		return ctx.Request().ID().Bytes()
 	case wasmhost.KeyTimestamp:
@@ -158,6 +161,10 @@ func (o *ScContext) SetBytes(keyID, typeID int32, bytes []byte) {
 	case wasmhost.KeyDeploy:
 		o.processDeploy(bytes)
 	case wasmhost.KeyEvent:
+		if typeID == wasmhost.OBJTYPE_BYTES {
+			o.processTypedEvent(bytes)
+			return
+		}
 		o.wc.ctx.Event(string(bytes))
 	case wasmhost.KeyLog:
 		o.wc.log().Infof(string(bytes))
@@ -172,6 +179,24 @@ func (o *ScContext) SetBytes(keyID, typeID int32, bytes []byte) {
 	}
 }
 
+// processTypedEvent decodes the typed event encoded by the wasmlib event encoder
+func (o *ScContext) processTypedEvent(bytes []byte) {
+	decode := NewBytesDecoder(bytes)
+	event := iscp.NewEvent(string(decode.Bytes()))
+	for n := decode.Uint16(); n > 0; n-- {
+		name := string(decode.Bytes())
+		typ := string(decode.Bytes())
+		value := decode.Bytes()
+		if decode.Bool() {
+			event.WithIndexedField(name, typ, value)
+			continue
+		}
+		event.WithField(name, typ, value)
+	}
+	o.Tracef("EVENT %s", event.Name)
+	o.wc.ctx.EmitEvent(event)
+}
+
 func (o *ScContext) processCall(bytes []byte) {
 	decode := NewBytesDecoder(bytes)
 	contract, err := iscp.HnameFromBytes(decode.Bytes())
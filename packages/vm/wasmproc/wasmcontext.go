@@ -4,6 +4,7 @@ import (
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/gas"
 	"github.com/iotaledger/wasp/packages/vm/wasmhost"
 	"github.com/iotaledger/wasp/packages/vm/wasmlib/go/wasmlib"
 )
@@ -17,6 +18,7 @@ type WasmContext struct {
 	ctx      iscp.Sandbox
 	ctxView  iscp.SandboxView
 	function string
+	gasMeter wasmhost.GasMeter
 	id       int32
 	host     *wasmhost.WasmHost
 	proc     *WasmProcessor
@@ -76,6 +78,7 @@ func (wc *WasmContext) Call(ctx interface{}) (dict.Dict, error) {
 	default:
 		panic(iscp.ErrWrongTypeEntryPoint)
 	}
+	wc.gasMeter = wc.newGasMeter()
 
 	if wc.function == "" {
 		// init function was missing, do nothing
@@ -109,6 +112,18 @@ func (wc *WasmContext) callFunction() error {
 	return err
 }
 
+// newGasMeter returns the gas meter of the request. Views called outside of a request
+// get their own gas meter with the budget of gas.MaxGasPerView
+func (wc *WasmContext) newGasMeter() wasmhost.GasMeter {
+	if wc.ctx != nil {
+		return wc.ctx
+	}
+	if meter, ok := wc.ctxView.(wasmhost.GasMeter); ok {
+		return meter
+	}
+	return &viewGasMeter{left: gas.MaxGasPerView}
+}
+
 func (wc *WasmContext) FunctionFromCode(code uint32) string {
 	return wc.host.FunctionFromCode(code)
 }
@@ -140,3 +155,19 @@ func (wc *WasmContext) state() kv.KVStore {
 	}
 	return NewScViewState(wc.ctxView)
 }
+
+type viewGasMeter struct {
+	left uint64
+}
+
+func (m *viewGasMeter) BurnGas(g uint64) {
+	if g > m.left {
+		m.left = 0
+		panic(gas.ErrNotEnoughGas)
+	}
+	m.left -= g
+}
+
+func (m *viewGasMeter) GasBudgetLeft() uint64 {
+	return m.left
+}
@@ -94,6 +94,23 @@ func (proc *WasmProcessor) GetKvStore(id int32) *wasmhost.KvStoreHost {
 	return &mainProcessor.contexts[id].KvStoreHost
 }
 
+// GetGasMeter returns the gas meter of the current context. The gas used by 'on_load' is not metered
+func (proc *WasmProcessor) GetGasMeter() wasmhost.GasMeter {
+	id := proc.currentContextID
+	if id == 0 {
+		return nil
+	}
+
+	mainProcessor := proc
+	if proc.mainProcessor != nil {
+		mainProcessor = proc.mainProcessor
+	}
+	mainProcessor.contextLock.Lock()
+	defer mainProcessor.contextLock.Unlock()
+
+	return mainProcessor.contexts[id].gasMeter
+}
+
 func (proc *WasmProcessor) KillContext(id int32) {
 	proc.contextLock.Lock()
 	defer proc.contextLock.Unlock()
@@ -32,6 +32,14 @@ func addDKSharesEndpoints(adm echoswagger.ApiGroup, registryProvider registry.Pr
 		TimeoutMS:   10000,
 	}
 	addr1 := iscp.RandomChainID().AsAddress()
+	reshareExample := model.DKSharesReshareRequest{
+		SharedAddress:  addr1.Base58(),
+		PeerNetIDs:     []string{"wasp1:4000", "wasp2:4000", "wasp3:4000", "wasp4:4000"},
+		NewPeerNetIDs:  []string{"wasp2:4000", "wasp3:4000", "wasp4:4000", "wasp5:4000"},
+		NewPeerPubKeys: []string{base64.StdEncoding.EncodeToString([]byte("key"))},
+		NewThreshold:   3,
+		TimeoutMS:      10000,
+	}
 	infoExample := model.DKSharesInfo{
 		Address:      addr1.Base58(),
 		SharedPubKey: base64.StdEncoding.EncodeToString([]byte("key")),
@@ -47,6 +55,11 @@ func addDKSharesEndpoints(adm echoswagger.ApiGroup, registryProvider registry.Pr
 		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
 		SetSummary("Generate a new distributed key")
 
+	adm.POST(routes.DKSharesReshare(), s.handleDKSharesReshare).
+		AddParamBody(reshareExample, "DKSharesReshareRequest", "Request parameters", true).
+		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
+		SetSummary("Reshare an existing distributed key to a new committee, keeping the address")
+
 	adm.GET(routes.DKSharesGet(":sharedAddress"), s.handleDKSharesGet).
 		AddParamPath("", "sharedAddress", "Address of the DK share (base58)").
 		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
@@ -80,8 +93,13 @@ func (s *dkSharesService) handleDKSharesPost(c echo.Context) error {
 		}
 	}
 
+	dkgNode := s.dkgNode()
+	if dkgNode == nil {
+		// the DKG needs the node identity key, it isn't available if the key is held by an external signer
+		return httperrors.ServerError("DKG is not available on this node")
+	}
 	var dkShare *tcrypto.DKShare
-	dkShare, err = s.dkgNode().GenerateDistributedKey(
+	dkShare, err = dkgNode.GenerateDistributedKey(
 		req.PeerNetIDs,
 		peerPubKeys,
 		req.Threshold,
@@ -103,6 +121,73 @@ func (s *dkSharesService) handleDKSharesPost(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+func (s *dkSharesService) handleDKSharesReshare(c echo.Context) error {
+	var req model.DKSharesReshareRequest
+	var err error
+
+	if err = c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body.")
+	}
+
+	var sharedAddress ledgerstate.Address
+	if sharedAddress, err = ledgerstate.AddressFromBase58EncodedString(req.SharedAddress); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid SharedAddress=%v", req.SharedAddress))
+	}
+
+	if req.NewPeerPubKeys != nil && len(req.NewPeerNetIDs) != len(req.NewPeerPubKeys) {
+		return httperrors.BadRequest("Inconsistent NewPeerNetIDs and NewPeerPubKeys.")
+	}
+
+	var newPeerPubKeys []ed25519.PublicKey
+	if req.NewPeerPubKeys != nil {
+		newPeerPubKeys = make([]ed25519.PublicKey, len(req.NewPeerPubKeys))
+		for i := range req.NewPeerPubKeys {
+			if newPeerPubKeys[i], err = ed25519.PublicKeyFromString(req.NewPeerPubKeys[i]); err != nil {
+				return httperrors.BadRequest(fmt.Sprintf("Invalid NewPeerPubKeys[%v]=%v", i, req.NewPeerPubKeys[i]))
+			}
+		}
+	}
+
+	peerNetIDs := req.PeerNetIDs
+	if len(peerNetIDs) == 0 {
+		var cr *registry.CommitteeRecord
+		if cr, err = s.registry().GetCommitteeRecord(sharedAddress); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+		if cr == nil {
+			return httperrors.BadRequest(fmt.Sprintf("No committee record for %v, PeerNetIDs must be specified.", req.SharedAddress))
+		}
+		peerNetIDs = cr.Nodes
+	}
+
+	dkgNode := s.dkgNode()
+	if dkgNode == nil {
+		return httperrors.ServerError("DKG is not available on this node")
+	}
+	var dkShare *tcrypto.DKShare
+	dkShare, err = dkgNode.ReshareDistributedKey(
+		sharedAddress,
+		peerNetIDs,
+		req.NewPeerNetIDs,
+		newPeerPubKeys,
+		req.NewThreshold,
+		3*time.Second,
+		time.Duration(req.TimeoutMS)*time.Millisecond,
+	)
+	if err != nil {
+		if _, ok := err.(dkg.InvalidParamsError); ok {
+			return httperrors.BadRequest(err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	var response *model.DKSharesInfo
+	if response, err = makeDKSharesInfo(dkShare); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
 func (s *dkSharesService) handleDKSharesGet(c echo.Context) error {
 	var err error
 	var dkShare *tcrypto.DKShare
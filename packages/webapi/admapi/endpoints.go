@@ -50,6 +50,7 @@ func AddEndpoints(
 	addChainStatsEndpoints(adm, chainsProvider)
 	addCommitteeRecordEndpoints(adm, registryProvider, chainsProvider)
 	addChainEndpoints(adm, registryProvider, chainsProvider, metrics)
+	addRotateChainEndpoint(adm, registryProvider, chainsProvider)
 	addDKSharesEndpoints(adm, registryProvider, nodeProvider)
 	addPeeringEndpoints(adm, network, tnm)
 }
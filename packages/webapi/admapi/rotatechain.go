@@ -0,0 +1,100 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package admapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/apilib"
+	"github.com/iotaledger/wasp/packages/chain/messages"
+	"github.com/iotaledger/wasp/packages/chains"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/iscp/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addRotateChainEndpoint(adm echoswagger.ApiGroup, registryProvider registry.Provider, chainsProvider chains.Provider) {
+	example := model.RotateChainRequest{
+		CommitteeAPIHosts:     []string{"wasp1.example.org:9090", "wasp2.example.org:9090", "wasp3.example.org:9090"},
+		CommitteePeeringHosts: []string{"wasp1.example.org:4000", "wasp2.example.org:4000", "wasp3.example.org:4000"},
+		Threshold:             2,
+	}
+
+	s := &rotateChainService{registryProvider, chainsProvider}
+
+	adm.POST(routes.RotateChain(":chainID"), s.handleRotateChain).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(example, "Request", "Parameters of the rotation", true).
+		AddResponse(http.StatusOK, "Address of the new committee", model.RotateChainResponse{}, nil).
+		SetSummary("Rotate the chain to a new committee. The governance calls are signed by the node identity")
+}
+
+type rotateChainService struct {
+	registry registry.Provider
+	chains   chains.Provider
+}
+
+func (s *rotateChainService) handleRotateChain(c echo.Context) error {
+	aliasAddress, err := ledgerstate.AliasAddressFromBase58EncodedString(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid alias address: %s", c.Param("chainID")))
+	}
+	chainID := iscp.NewChainID(aliasAddress)
+
+	var req model.RotateChainRequest
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	if len(req.CommitteeAPIHosts) == 0 || len(req.CommitteeAPIHosts) != len(req.CommitteePeeringHosts) {
+		return httperrors.BadRequest("API and peering hosts of the committee nodes don't match")
+	}
+
+	ch := s.chains().Get(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID.Base58()))
+	}
+	nodeSigner, err := s.registry().NodeSigner()
+	if err != nil {
+		return err
+	}
+
+	addr, err := apilib.RotateChain(apilib.RotateChainParams{
+		ChainID:               chainID,
+		AllPeeringHosts:       req.PeeringHosts,
+		CommitteeAPIHosts:     req.CommitteeAPIHosts,
+		CommitteePeeringHosts: req.CommitteePeeringHosts,
+		T:                     req.Threshold,
+		ClaimOwnership:        req.ClaimOwnership,
+		Timeout:               time.Duration(req.TimeoutMS) * time.Millisecond,
+		PostGovernanceCall: func(entryPoint string, params dict.Dict) (iscp.RequestID, error) {
+			offLedgerReq := request.NewOffLedger(chainID, governance.Contract.Hname(), iscp.Hn(entryPoint), requestargs.New(params))
+			if err := offLedgerReq.SignWith(nodeSigner.PublicKey(), nodeSigner.Sign); err != nil {
+				return iscp.RequestID{}, err
+			}
+			ch.EnqueueOffLedgerRequestMsg(&messages.OffLedgerRequestMsgIn{
+				OffLedgerRequestMsg: messages.OffLedgerRequestMsg{
+					ChainID: chainID,
+					Req:     offLedgerReq,
+				},
+			})
+			return offLedgerReq.ID(), nil
+		},
+	})
+	if err != nil {
+		log.Errorf("rotating chain %s: %v", chainID.Base58(), err)
+		return httperrors.ServerError(err.Error())
+	}
+	return c.JSON(http.StatusOK, model.RotateChainResponse{StateControllerAddress: model.NewAddress(addr)})
+}
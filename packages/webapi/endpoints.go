@@ -12,6 +12,7 @@ import (
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/webapi/admapi"
+	"github.com/iotaledger/wasp/packages/webapi/events"
 	"github.com/iotaledger/wasp/packages/webapi/info"
 	"github.com/iotaledger/wasp/packages/webapi/reqstatus"
 	"github.com/iotaledger/wasp/packages/webapi/request"
@@ -40,16 +41,18 @@ func Init(
 	server.SetResponseContentType(echo.MIMEApplicationJSON)
 
 	pub := server.Group("public", "").SetDescription("Public endpoints")
-	addWebSocketEndpoint(pub, log)
+	addWebSocketEndpoint(pub, chainsProvider.ChainProvider(), log)
 
 	info.AddEndpoints(pub, network)
 	reqstatus.AddEndpoints(pub, chainsProvider.ChainProvider())
+	events.AddEndpoints(pub, chainsProvider.ChainProvider())
 	state.AddEndpoints(pub, chainsProvider)
 	request.AddEndpoints(
 		pub,
 		chainsProvider.ChainProvider(),
 		webapiutil.GetAccountBalance,
 		webapiutil.HasRequestBeenProcessed,
+		webapiutil.GetAccountNonce,
 		time.Duration(parameters.GetInt(parameters.OffledgerAPICacheTTL))*time.Second,
 		log,
 	)
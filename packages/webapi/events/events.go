@@ -0,0 +1,139 @@
+package events
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/chains"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/packages/webapi/webapiutil"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+type eventsWebAPI struct {
+	getEvents func(chainID *iscp.ChainID, q *blocklog.TypedEventsQuery) ([]*blocklog.EventRecord, *uint32, error)
+}
+
+func AddEndpoints(server echoswagger.ApiRouter, getChain chains.ChainProvider) {
+	e := &eventsWebAPI{
+		getEvents: func(chainID *iscp.ChainID, q *blocklog.TypedEventsQuery) ([]*blocklog.EventRecord, *uint32, error) {
+			ch := getChain(chainID)
+			if ch == nil {
+				return nil, nil, httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID.String()))
+			}
+			res, err := webapiutil.CallView(ch, blocklog.Contract.Hname(), blocklog.FuncGetTypedEvents.Hname(), q.Params())
+			if err != nil {
+				return nil, nil, err
+			}
+			return blocklog.DecodeTypedEvents(res)
+		},
+	}
+
+	addQueryParams := func(route echoswagger.Api) {
+		for i := 0; i < iscp.MaxEventTopics; i++ {
+			route.AddParamQuery("", fmt.Sprintf("topic%d", i), fmt.Sprintf("Value of the indexed field #%d (hex). Requires the event name", i), false)
+		}
+		route.AddParamQuery(uint32(0), "fromBlock", "First block of the range", false).
+			AddParamQuery(uint32(0), "toBlock", "Last block of the range, defaults to the latest block", false).
+			AddParamQuery(uint32(0), "cursor", "Cursor returned by the previous page. It replaces fromBlock", false).
+			AddParamQuery(uint32(blocklog.DefaultEventsLimit), "limit", "Maximum number of events to return", false)
+	}
+
+	addQueryParams(server.GET(routes.ContractEvents(":chainID", ":contractHname"), e.handleEvents).
+		SetSummary("Get the typed events emitted by the contract").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "contractHname", "Contract Hname").
+		AddResponse(http.StatusOK, "Events", model.EventsResponse{}, nil))
+
+	addQueryParams(server.GET(routes.ContractEventsByName(":chainID", ":contractHname", ":eventName"), e.handleEvents).
+		SetSummary("Get the typed events with the given name emitted by the contract, filtered by the topics").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "contractHname", "Contract Hname").
+		AddParamPath("", "eventName", "Name of the event").
+		AddResponse(http.StatusOK, "Events", model.EventsResponse{}, nil))
+}
+
+func (e *eventsWebAPI) handleEvents(c echo.Context) error {
+	chainID, err := iscp.ChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid Chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	contract, err := iscp.HnameFromString(c.Param("contractHname"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid contract hname %+v: %s", c.Param("contractHname"), err.Error()))
+	}
+	q, err := parseQuery(c)
+	if err != nil {
+		return err
+	}
+	q.Contract = contract
+	recs, cursor, err := e.getEvents(chainID, q)
+	if err != nil {
+		if _, ok := err.(*httperrors.HTTPError); ok {
+			return err
+		}
+		return httperrors.BadRequest(fmt.Sprintf("Could not fetch the events: %s", err.Error()))
+	}
+	return c.JSON(http.StatusOK, model.NewEventsResponse(recs, cursor))
+}
+
+func parseQuery(c echo.Context) (*blocklog.TypedEventsQuery, error) {
+	ret := &blocklog.TypedEventsQuery{
+		EventName: c.Param("eventName"),
+	}
+	for i := 0; i < iscp.MaxEventTopics; i++ {
+		s := c.QueryParam(fmt.Sprintf("topic%d", i))
+		if s == "" {
+			continue
+		}
+		topic, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("Invalid topic%d %+v: %s", i, s, err.Error()))
+		}
+		for len(ret.Topics) < i {
+			ret.Topics = append(ret.Topics, nil)
+		}
+		ret.Topics = append(ret.Topics, topic)
+	}
+	for name, dst := range map[string]*uint32{
+		"fromBlock": &ret.FromBlock,
+		"toBlock":   &ret.ToBlock,
+		"limit":     &ret.Limit,
+	} {
+		v, ok, err := parseUint32(c, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			*dst = v
+		}
+	}
+	cursor, ok, err := parseUint32(c, "cursor")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		ret.Cursor = &cursor
+	}
+	return ret, nil
+}
+
+func parseUint32(c echo.Context, name string) (uint32, bool, error) {
+	s := c.QueryParam(name)
+	if s == "" {
+		return 0, false, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, false, httperrors.BadRequest(fmt.Sprintf("Invalid %s %+v: %s", name, s, err.Error()))
+	}
+	return uint32(v), true, nil
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/packages/webapi/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractEvents(t *testing.T) {
+	chainID := iscp.RandomChainID()
+	contract := iscp.Hn("contract")
+	rec := &blocklog.EventRecord{
+		Key:      blocklog.NewEventLookupKey(5, 1, 2),
+		Contract: contract,
+		Event: iscp.NewEvent("transfer").
+			WithIndexedField("owner", "String", codec.EncodeString("alice")).
+			WithField("amount", "Int64", codec.EncodeInt64(42)),
+	}
+	next := uint32(7)
+
+	var query *blocklog.TypedEventsQuery
+	e := &eventsWebAPI{
+		getEvents: func(chainID *iscp.ChainID, q *blocklog.TypedEventsQuery) ([]*blocklog.EventRecord, *uint32, error) {
+			query = q
+			return []*blocklog.EventRecord{rec}, &next, nil
+		},
+	}
+
+	var res model.EventsResponse
+	testutil.CallWebAPIRequestHandler(
+		t,
+		e.handleEvents,
+		http.MethodGet,
+		routes.ContractEventsByName(":chainID", ":contractHname", ":eventName"),
+		map[string]string{
+			"chainID":       chainID.Base58(),
+			"contractHname": contract.String(),
+			"eventName":     "transfer",
+		},
+		nil,
+		&res,
+		http.StatusOK,
+	)
+
+	require.EqualValues(t, contract, query.Contract)
+	require.EqualValues(t, "transfer", query.EventName)
+	require.Len(t, res.Events, 1)
+	require.EqualValues(t, 7, *res.Cursor)
+	ev := res.Events[0]
+	require.EqualValues(t, contract.String(), ev.Contract)
+	require.EqualValues(t, 5, ev.BlockIndex)
+	require.EqualValues(t, 1, ev.RequestIndex)
+	require.EqualValues(t, 2, ev.EventIndex)
+	require.EqualValues(t, "transfer", ev.Name)
+	require.Len(t, ev.Fields, 2)
+	require.True(t, ev.Fields[0].Indexed)
+	require.EqualValues(t, "alice", ev.Fields[0].Text)
+	require.EqualValues(t, codec.EncodeInt64(42), ev.Fields[1].Value.Bytes())
+	require.EqualValues(t, "42", ev.Fields[1].Text)
+}
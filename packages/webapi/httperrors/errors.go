@@ -35,3 +35,19 @@ func Timeout(message string) *HTTPError {
 func ServerError(message string) *HTTPError {
 	return &HTTPError{Code: http.StatusInternalServerError, Message: message}
 }
+
+func PayloadTooLarge(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusRequestEntityTooLarge, Message: message}
+}
+
+func TooManyRequests(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusTooManyRequests, Message: message}
+}
+
+func ServiceUnavailable(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusServiceUnavailable, Message: message}
+}
+
+func Gone(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusGone, Message: message}
+}
@@ -10,7 +10,7 @@ import (
 type Color string
 
 func NewColor(color ledgerstate.Color) Color {
-	return Color(color.String())
+	return Color(color.Base58())
 }
 
 func (c Color) MarshalJSON() ([]byte, error) {
@@ -20,3 +20,9 @@ func (bd *CommitteeRecord) Record() *registry.CommitteeRecord {
 		Nodes:   bd.Nodes,
 	}
 }
+
+// CommitteePublicKey is the shared public key of the committee which currently runs the chain
+type CommitteePublicKey struct {
+	Address      Address `json:"address" swagger:"desc(Committee address (base58-encoded))"`
+	SharedPubKey string  `json:"sharedPubKey" swagger:"desc(Shared public key (base64-encoded))"`
+}
@@ -11,6 +11,16 @@ type DKSharesPostRequest struct {
 	TimeoutMS   uint32   `json:"timeoutMS" swagger:"desc(Timeout in milliseconds.)"`
 }
 
+// DKSharesReshareRequest is a POST request for resharing an existing DKShare to a new committee.
+type DKSharesReshareRequest struct {
+	SharedAddress  string   `json:"sharedAddress" swagger:"desc(Address of the DK share (base58).)"`
+	PeerNetIDs     []string `json:"peerNetIDs" swagger:"desc(Optional, NetIDs of the nodes of the current committee taking part in the resharing, at least the threshold of them. The committee record of the address is used by default.)"`
+	NewPeerNetIDs  []string `json:"newPeerNetIDs" swagger:"desc(NetIDs of the nodes of the new committee.)"`
+	NewPeerPubKeys []string `json:"newPeerPubKeys" swagger:"desc(Optional, base64 encoded public keys of the nodes of the new committee.)"`
+	NewThreshold   uint16   `json:"newThreshold" swagger:"desc(Should be =< len(NewPeerNetIDs))"`
+	TimeoutMS      uint32   `json:"timeoutMS" swagger:"desc(Timeout in milliseconds.)"`
+}
+
 // DKSharesInfo stands for the DKShare representation, returned by the GET and POST methods.
 type DKSharesInfo struct {
 	Address      string   `json:"address" swagger:"desc(New generated shared address.)"`
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+)
+
+type EventField struct {
+	Name    string `swagger:"desc(Name of the field)"`
+	Type    string `swagger:"desc(Schema type of the value, e.g. Int64 or AgentID)"`
+	Value   Bytes  `swagger:"desc(Encoded value of the field (base64))"`
+	Text    string `swagger:"desc(Human readable value of the field)"`
+	Indexed bool   `swagger:"desc(True if the field is a topic of the event)"`
+}
+
+type Event struct {
+	Contract     string        `swagger:"desc(Hname of the contract which emitted the event)"`
+	BlockIndex   uint32        `swagger:"desc(Index of the block)"`
+	RequestIndex uint16        `swagger:"desc(Index of the request in the block)"`
+	EventIndex   uint16        `swagger:"desc(Index of the event in the request)"`
+	Name         string        `swagger:"desc(Name of the event)"`
+	Fields       []*EventField `swagger:"desc(Fields of the event, in the order of declaration)"`
+}
+
+type EventsResponse struct {
+	Events []*Event `swagger:"desc(Events in the order of emission)"`
+	Cursor *uint32  `swagger:"desc(Cursor of the next page or null if there are no more events)"`
+}
+
+func NewEvent(rec *blocklog.EventRecord) *Event {
+	ret := &Event{
+		Contract:     rec.Contract.String(),
+		BlockIndex:   rec.Key.BlockIndex(),
+		RequestIndex: rec.Key.RequestIndex(),
+		EventIndex:   rec.Key.RequestEventIndex(),
+		Name:         rec.Event.Name,
+		Fields:       make([]*EventField, len(rec.Event.Fields)),
+	}
+	for i, f := range rec.Event.Fields {
+		ret.Fields[i] = &EventField{
+			Name:    f.Name,
+			Type:    f.Type,
+			Value:   NewBytes(f.Value),
+			Text:    blocklog.EventFieldText(f),
+			Indexed: f.Indexed,
+		}
+	}
+	return ret
+}
+
+func NewEventsResponse(recs []*blocklog.EventRecord, cursor *uint32) *EventsResponse {
+	ret := &EventsResponse{
+		Events: make([]*Event, len(recs)),
+		Cursor: cursor,
+	}
+	for i, rec := range recs {
+		ret.Events[i] = NewEvent(rec)
+	}
+	return ret
+}
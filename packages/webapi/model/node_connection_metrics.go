@@ -25,9 +25,22 @@ type NodeConnectionMessagesMetrics struct {
 	InUnspentAliasOutput *NodeConnectionMessageMetrics `swagger:"desc(Stats of received UnspentAliasOutput messages)"`
 }
 
+type NodeConnectionEndpointMetrics struct {
+	Address          string    `swagger:"desc(Address of the txstream server of the L1 node)"`
+	Connected        bool      `swagger:"desc(Whether the connection is established)"`
+	Healthy          bool      `swagger:"desc(Whether the endpoint passes the health checks)"`
+	Active           bool      `swagger:"desc(Whether the endpoint is the one used by the chains)"`
+	Connects         uint32    `swagger:"desc(Number of times the connection was established)"`
+	MessagesSent     uint32    `swagger:"desc(Number of messages sent to the endpoint)"`
+	MessagesReceived uint32    `swagger:"desc(Number of messages received from the endpoint)"`
+	LastReceived     time.Time `swagger:"desc(Last time data was received from the endpoint)"`
+}
+
 type NodeConnectionMetrics struct {
 	NodeConnectionMessagesMetrics
 	Subscribed []Address
+	Endpoints  []*NodeConnectionEndpointMetrics
+	Failovers  uint32
 }
 
 func NewNodeConnectionMetrics(metrics nodeconnmetrics.NodeConnectionMetrics) *NodeConnectionMetrics {
@@ -37,9 +50,29 @@ func NewNodeConnectionMetrics(metrics nodeconnmetrics.NodeConnectionMetrics) *No
 	for i := range s {
 		s[i] = NewAddress(subscribed[i])
 	}
+	endpoints := metrics.GetEndpoints()
+	e := make([]*NodeConnectionEndpointMetrics, len(endpoints))
+	for i := range e {
+		e[i] = NewNodeConnectionEndpointMetrics(endpoints[i])
+	}
 	return &NodeConnectionMetrics{
 		NodeConnectionMessagesMetrics: *ncmm,
 		Subscribed:                    s,
+		Endpoints:                     e,
+		Failovers:                     metrics.GetFailovers(),
+	}
+}
+
+func NewNodeConnectionEndpointMetrics(metrics nodeconnmetrics.NodeConnectionEndpointMetrics) *NodeConnectionEndpointMetrics {
+	return &NodeConnectionEndpointMetrics{
+		Address:          metrics.GetAddress(),
+		Connected:        metrics.IsConnected(),
+		Healthy:          metrics.IsHealthy(),
+		Active:           metrics.IsActive(),
+		Connects:         metrics.GetConnects(),
+		MessagesSent:     metrics.GetMessagesSent(),
+		MessagesReceived: metrics.GetMessagesReceived(),
+		LastReceived:     metrics.GetLastReceived(),
 	}
 }
 
@@ -3,3 +3,23 @@ package model
 type OffLedgerRequestBody struct {
 	Request Bytes `swagger:"desc(Offledger Request (base64))"`
 }
+
+type OffLedgerRequestBatchBody struct {
+	Requests []Bytes `swagger:"desc(Offledger Requests (base64))"`
+}
+
+type OffLedgerRequestResult struct {
+	RequestID string `swagger:"desc(ID of the request (base58), empty if the request could not be parsed)"`
+	Accepted  bool   `swagger:"desc(True if the request has been handed to the mempool)"`
+	Status    int    `swagger:"desc(HTTP status the request would get if it was submitted alone)"`
+	Error     string `swagger:"desc(Reason of the rejection)"`
+}
+
+type OffLedgerRequestBatchResponse struct {
+	Results []OffLedgerRequestResult `swagger:"desc(Results for the requests of the batch, in the same order)"`
+}
+
+type EthereumAccountResponse struct {
+	AgentID string `swagger:"desc(Agent ID of the sender of the off-ledger requests signed with the Ethereum key)"`
+	Nonce   uint64 `swagger:"desc(Nonce to be used in the next off-ledger request of the account)"`
+}
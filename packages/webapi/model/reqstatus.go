@@ -1,6 +1,12 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+)
 
 type WaitRequestProcessedParams struct {
 	Timeout time.Duration `swagger:"desc(Timeout in nanoseconds),default(30 seconds)"`
@@ -11,3 +17,44 @@ type RequestStatusResponse struct {
 }
 
 const WaitRequestProcessedDefaultTimeout = 30 * time.Second
+
+type RequestError struct {
+	Contract string   `swagger:"desc(Hname of the contract which reported the error)"`
+	Code     uint16   `swagger:"desc(Error code: 0 - contract error, 1 - panic, 2 - not enough gas, 3 - not enough fees, 4 - invalid request)"`
+	CodeName string   `swagger:"desc(Human readable name of the error code)"`
+	Message  string   `swagger:"desc(Error message)"`
+	Params   []string `swagger:"desc(Optional parameters of the error)"`
+}
+
+type RequestReceiptResponse struct {
+	RequestID    string        `swagger:"desc(Request ID (base58))"`
+	BlockIndex   uint32        `swagger:"desc(Index of the block the request was processed in)"`
+	RequestIndex uint16        `swagger:"desc(Index of the request in the block)"`
+	Error        *RequestError `swagger:"desc(Error of the request or null if it was successful)"`
+	Result       dict.JSONDict `swagger:"desc(Result returned by the request. Empty if the result is too big to be stored in the receipt)"`
+	FeeColor     Color         `swagger:"desc(Color of the fee charged (base58))"`
+	Fee          uint64        `swagger:"desc(Fee charged)"`
+	GasUsed      uint64        `swagger:"desc(Gas burned by the request)"`
+}
+
+func NewRequestReceiptResponse(rec *blocklog.RequestReceipt, blockIndex uint32, requestIndex uint16) *RequestReceiptResponse {
+	ret := &RequestReceiptResponse{
+		RequestID:    rec.Request.ID().Base58(),
+		BlockIndex:   blockIndex,
+		RequestIndex: requestIndex,
+		Result:       rec.Result.JSONDict(),
+		FeeColor:     NewColor(ledgerstate.Color(rec.FeeColor)),
+		Fee:          rec.Fee,
+		GasUsed:      rec.GasUsed,
+	}
+	if rec.Error != nil {
+		ret.Error = &RequestError{
+			Contract: rec.Error.Contract.String(),
+			Code:     uint16(rec.Error.Code),
+			CodeName: rec.Error.Code.String(),
+			Message:  rec.Error.Message,
+			Params:   rec.Error.Params,
+		}
+	}
+	return ret
+}
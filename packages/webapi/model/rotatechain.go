@@ -0,0 +1,19 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+// RotateChainRequest is a POST request for rotating the chain to a new committee.
+type RotateChainRequest struct {
+	CommitteeAPIHosts     []string `json:"committeeAPIHosts" swagger:"desc(API hosts of the nodes of the new committee.)"`
+	CommitteePeeringHosts []string `json:"committeePeeringHosts" swagger:"desc(NetIDs of the nodes of the new committee.)"`
+	PeeringHosts          []string `json:"peeringHosts" swagger:"desc(Optional, NetIDs of all peers of the chain, put into the chain records of the nodes which start running the chain.)"`
+	Threshold             uint16   `json:"threshold" swagger:"desc(Quorum of the new committee.)"`
+	ClaimOwnership        bool     `json:"claimOwnership" swagger:"desc(Claim the ownership of the chain delegated to the node before the rotation.)"`
+	TimeoutMS             uint32   `json:"timeoutMS" swagger:"desc(Optional timeout of each step in milliseconds.)"`
+}
+
+// RotateChainResponse is the result of the rotation of the chain.
+type RotateChainResponse struct {
+	StateControllerAddress Address `json:"stateControllerAddress" swagger:"desc(Address of the new committee.)"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// StateProofResponse is the value of the key in the chain state together with the proof of it
+type StateProofResponse struct {
+	Key       Bytes     `swagger:"desc(Key)"`
+	Value     Bytes     `swagger:"desc(Value or empty if the key is absent)"`
+	StateHash HashValue `swagger:"desc(State hash (base58), the proof is verifiable against it)"`
+	Proof     Bytes     `swagger:"desc(Binary encoded proof of inclusion or absence of the key)"`
+}
@@ -8,21 +8,29 @@ import (
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chains"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/packages/webapi/webapiutil"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
 type reqstatusWebAPI struct {
-	getChain func(chainID *iscp.ChainID) chain.ChainRequests
+	getChain   func(chainID *iscp.ChainID) chain.ChainRequests
+	getReceipt func(chainID *iscp.ChainID, reqID iscp.RequestID) (*blocklog.RequestReceipt, uint32, uint16, error)
 }
 
 func AddEndpoints(server echoswagger.ApiRouter, getChain chains.ChainProvider) {
-	r := &reqstatusWebAPI{func(chainID *iscp.ChainID) chain.ChainRequests {
-		return getChain(chainID)
-	}}
+	r := &reqstatusWebAPI{
+		getChain: func(chainID *iscp.ChainID) chain.ChainRequests {
+			return getChain(chainID)
+		},
+		getReceipt: func(chainID *iscp.ChainID, reqID iscp.RequestID) (*blocklog.RequestReceipt, uint32, uint16, error) {
+			return webapiutil.GetRequestReceipt(getChain(chainID), reqID)
+		},
+	}
 
 	server.GET(routes.RequestStatus(":chainID", ":reqID"), r.handleRequestStatus).
 		SetSummary("Get the processing status of a given request in the node").
@@ -30,6 +38,13 @@ func AddEndpoints(server echoswagger.ApiRouter, getChain chains.ChainProvider) {
 		AddParamPath("", "reqID", "Request ID (base58)").
 		AddResponse(http.StatusOK, "Request status", model.RequestStatusResponse{}, nil)
 
+	server.GET(routes.RequestReceipt(":chainID", ":reqID"), r.handleRequestReceipt).
+		SetSummary("Get the receipt of the processed request: the result, the fee charged and the error, if any").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "reqID", "Request ID (base58)").
+		AddResponse(http.StatusOK, "Request receipt", model.RequestReceiptResponse{}, nil).
+		AddResponse(http.StatusNotFound, "Request not processed yet", nil, nil)
+
 	server.GET(routes.WaitRequestProcessed(":chainID", ":reqID"), r.handleWaitRequestProcessed).
 		SetSummary("Wait until the given request has been processed by the node").
 		AddParamPath("", "chainID", "ChainID (base58)").
@@ -38,7 +53,7 @@ func AddEndpoints(server echoswagger.ApiRouter, getChain chains.ChainProvider) {
 }
 
 func (r *reqstatusWebAPI) handleRequestStatus(c echo.Context) error {
-	ch, reqID, err := r.parseParams(c)
+	_, ch, reqID, err := r.parseParams(c)
 	if err != nil {
 		return err
 	}
@@ -54,8 +69,23 @@ func (r *reqstatusWebAPI) handleRequestStatus(c echo.Context) error {
 	})
 }
 
+func (r *reqstatusWebAPI) handleRequestReceipt(c echo.Context) error {
+	chainID, _, reqID, err := r.parseParams(c)
+	if err != nil {
+		return err
+	}
+	receipt, blockIndex, requestIndex, err := r.getReceipt(chainID, reqID)
+	if err != nil {
+		return httperrors.ServerError(fmt.Sprintf("Could not fetch the request receipt: %s", err.Error()))
+	}
+	if receipt == nil {
+		return httperrors.NotFound(fmt.Sprintf("Request not processed: %s", reqID.Base58()))
+	}
+	return c.JSON(http.StatusOK, model.NewRequestReceiptResponse(receipt, blockIndex, requestIndex))
+}
+
 func (r *reqstatusWebAPI) handleWaitRequestProcessed(c echo.Context) error {
-	ch, reqID, err := r.parseParams(c)
+	_, ch, reqID, err := r.parseParams(c)
 	if err != nil {
 		return err
 	}
@@ -95,18 +125,18 @@ func (r *reqstatusWebAPI) handleWaitRequestProcessed(c echo.Context) error {
 	}
 }
 
-func (r *reqstatusWebAPI) parseParams(c echo.Context) (chain.ChainRequests, iscp.RequestID, error) {
+func (r *reqstatusWebAPI) parseParams(c echo.Context) (*iscp.ChainID, chain.ChainRequests, iscp.RequestID, error) {
 	chainID, err := iscp.ChainIDFromBase58(c.Param("chainID"))
 	if err != nil {
-		return nil, iscp.RequestID{}, httperrors.BadRequest(fmt.Sprintf("Invalid Chain ID %+v: %s", c.Param("chainID"), err.Error()))
+		return nil, nil, iscp.RequestID{}, httperrors.BadRequest(fmt.Sprintf("Invalid Chain ID %+v: %s", c.Param("chainID"), err.Error()))
 	}
 	theChain := r.getChain(chainID)
 	if theChain == nil {
-		return nil, iscp.RequestID{}, httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID.String()))
+		return nil, nil, iscp.RequestID{}, httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID.String()))
 	}
 	reqID, err := iscp.RequestIDFromBase58(c.Param("reqID"))
 	if err != nil {
-		return nil, iscp.RequestID{}, httperrors.BadRequest(fmt.Sprintf("Invalid request id %+v: %s", c.Param("reqID"), err.Error()))
+		return nil, nil, iscp.RequestID{}, httperrors.BadRequest(fmt.Sprintf("Invalid request id %+v: %s", c.Param("reqID"), err.Error()))
 	}
-	return theChain, reqID, nil
+	return chainID, theChain, reqID, nil
 }
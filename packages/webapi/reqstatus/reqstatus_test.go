@@ -8,6 +8,8 @@ import (
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/packages/webapi/testutil"
@@ -22,6 +24,10 @@ func (m *mockChain) GetRequestProcessingStatus(id iscp.RequestID) chain.RequestP
 	return chain.RequestProcessingStatusCompleted
 }
 
+func (m *mockChain) CheckRequest(req iscp.Request) error {
+	panic("not implemented")
+}
+
 func (m *mockChain) AttachToRequestProcessed(func(iscp.RequestID)) (attachID *events.Closure) {
 	panic("not implemented")
 }
@@ -31,7 +37,7 @@ func (m *mockChain) DetachFromRequestProcessed(attachID *events.Closure) {
 }
 
 func TestRequestStatus(t *testing.T) {
-	r := &reqstatusWebAPI{func(chainID *iscp.ChainID) chain.ChainRequests {
+	r := &reqstatusWebAPI{getChain: func(chainID *iscp.ChainID) chain.ChainRequests {
 		return &mockChain{}
 	}}
 
@@ -55,3 +61,46 @@ func TestRequestStatus(t *testing.T) {
 
 	require.True(t, res.IsProcessed)
 }
+
+func TestRequestReceipt(t *testing.T) {
+	chainID := iscp.RandomChainID()
+	req := request.NewOffLedger(chainID, iscp.Hn("contract"), iscp.Hn("entrypoint"), nil)
+	receipt := &blocklog.RequestReceipt{
+		Request: req,
+		Error:   iscp.NewRequestError(iscp.Hn("contract"), iscp.RequestErrorNotEnoughFees, "not enough fees", "100"),
+		Fee:     10,
+	}
+
+	r := &reqstatusWebAPI{
+		getChain: func(chainID *iscp.ChainID) chain.ChainRequests {
+			return &mockChain{}
+		},
+		getReceipt: func(chainID *iscp.ChainID, reqID iscp.RequestID) (*blocklog.RequestReceipt, uint32, uint16, error) {
+			return receipt, 3, 1, nil
+		},
+	}
+
+	var res model.RequestReceiptResponse
+	testutil.CallWebAPIRequestHandler(
+		t,
+		r.handleRequestReceipt,
+		http.MethodGet,
+		routes.RequestReceipt(":chainID", ":reqID"),
+		map[string]string{
+			"chainID": chainID.Base58(),
+			"reqID":   req.ID().Base58(),
+		},
+		nil,
+		&res,
+		http.StatusOK,
+	)
+
+	require.EqualValues(t, req.ID().Base58(), res.RequestID)
+	require.EqualValues(t, 3, res.BlockIndex)
+	require.EqualValues(t, 1, res.RequestIndex)
+	require.EqualValues(t, 10, res.Fee)
+	require.NotNil(t, res.Error)
+	require.EqualValues(t, iscp.RequestErrorNotEnoughFees, res.Error.Code)
+	require.EqualValues(t, iscp.Hn("contract").String(), res.Error.Contract)
+	require.EqualValues(t, []string{"100"}, res.Error.Params)
+}
@@ -0,0 +1,179 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/iotaledger/hive.go/marshalutil"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+)
+
+// MaxBatchSize is the maximum number of requests in one batch
+const MaxBatchSize = 1000
+
+// handleNewRequestBatch checks the requests of the batch in parallel, each one as if it was submitted alone.
+// The valid requests are handed to the chain at once. The chain admits them to the mempool in the order
+// of the batch, the result of each request is returned to the client
+func (o *offLedgerReqAPI) handleNewRequestBatch(c echo.Context) error {
+	chainID, err := parseChainID(c)
+	if err != nil {
+		return err
+	}
+	ch := o.getChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Unknown chain: %s", chainID.Base58()))
+	}
+	reqs, parseErrs, err := parseBatch(c)
+	if err != nil {
+		return err
+	}
+
+	results := make([]model.OffLedgerRequestResult, len(reqs))
+	valid := make([]bool, len(reqs))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				_, balances, err := o.validateRequest(chainID, reqs[i])
+				if err == nil {
+					if err = checkBalances(reqs[i], balances); err != nil {
+						o.requestsCache.Set(reqs[i].ID(), cachedRejected)
+					}
+				}
+				valid[i] = err == nil
+				results[i] = newRequestResult(reqs[i], err)
+			}
+		}()
+	}
+	seen := make(map[iscp.RequestID]bool)
+	for i, req := range reqs {
+		if parseErrs[i] != nil {
+			results[i] = newRequestResult(nil, parseErrs[i])
+			continue
+		}
+		// the duplicates would pass the checks together
+		if seen[req.ID()] {
+			results[i] = newRequestResult(req, httperrors.BadRequest("duplicate request in the batch"))
+			continue
+		}
+		seen[req.ID()] = true
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	batch := make([]*request.OffLedger, 0, len(reqs))
+	positions := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		if valid[i] {
+			batch = append(batch, req)
+			positions = append(positions, i)
+		}
+	}
+	if len(batch) == 0 {
+		return c.JSON(http.StatusOK, &model.OffLedgerRequestBatchResponse{Results: results})
+	}
+	errs, err := o.admitRequests(c, ch, batch)
+	if err != nil {
+		return err
+	}
+	for j, err := range errs {
+		if err != nil {
+			// rejected requests are not cached, they can be retried later
+			err = mempoolRejectionError(err)
+		}
+		results[positions[j]] = newRequestResult(batch[j], err)
+	}
+	return c.JSON(http.StatusOK, &model.OffLedgerRequestBatchResponse{Results: results})
+}
+
+func newRequestResult(req *request.OffLedger, err error) model.OffLedgerRequestResult {
+	ret := model.OffLedgerRequestResult{
+		Accepted: err == nil,
+		Status:   http.StatusAccepted,
+	}
+	if req != nil {
+		ret.RequestID = req.ID().Base58()
+	}
+	if err == nil {
+		return ret
+	}
+	ret.Error = err.Error()
+	ret.Status = http.StatusInternalServerError
+	var httpErr *httperrors.HTTPError
+	if xerrors.As(err, &httpErr) {
+		ret.Status = httpErr.Code
+	}
+	return ret
+}
+
+// parseBatch parses the body of the batch request. The requests which can't be parsed are returned as errors
+// at their position in the batch, the error is returned only if the body as a whole is malformed
+func parseBatch(c echo.Context) ([]*request.OffLedger, []error, error) {
+	contentType := c.Request().Header.Get("Content-Type")
+	if strings.Contains(strings.ToLower(contentType), "json") {
+		r := new(model.OffLedgerRequestBatchBody)
+		if err := c.Bind(r); err != nil {
+			return nil, nil, httperrors.BadRequest("Error parsing requests from payload")
+		}
+		if len(r.Requests) > MaxBatchSize {
+			return nil, nil, httperrors.PayloadTooLarge(fmt.Sprintf("Too many requests in the batch, the maximum is %d", MaxBatchSize))
+		}
+		reqs := make([]*request.OffLedger, len(r.Requests))
+		errs := make([]error, len(r.Requests))
+		for i, data := range r.Requests {
+			reqs[i], errs[i] = parseOffLedgerRequest(data.Bytes())
+		}
+		return reqs, errs, nil
+	}
+
+	// binary format: each request is prefixed with its length
+	data, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, nil, httperrors.BadRequest("Error parsing requests from payload")
+	}
+	mu := marshalutil.New(data)
+	reqs := make([]*request.OffLedger, 0)
+	errs := make([]error, 0)
+	for mu.ReadOffset() < len(data) {
+		if len(reqs) == MaxBatchSize {
+			return nil, nil, httperrors.PayloadTooLarge(fmt.Sprintf("Too many requests in the batch, the maximum is %d", MaxBatchSize))
+		}
+		size, err := mu.ReadUint32()
+		if err != nil {
+			return nil, nil, httperrors.BadRequest("Error parsing requests from payload")
+		}
+		reqBytes, err := mu.ReadBytes(int(size))
+		if err != nil {
+			return nil, nil, httperrors.BadRequest(fmt.Sprintf("Error parsing request #%d from payload", len(reqs)))
+		}
+		req, err := parseOffLedgerRequest(reqBytes)
+		reqs = append(reqs, req)
+		errs = append(errs, err)
+	}
+	return reqs, errs, nil
+}
+
+func parseOffLedgerRequest(data []byte) (*request.OffLedger, error) {
+	rGeneric, err := request.FromMarshalUtil(marshalutil.New(data))
+	if err != nil {
+		return nil, httperrors.BadRequest("Error parsing request")
+	}
+	req, ok := rGeneric.(*request.OffLedger)
+	if !ok {
+		return nil, httperrors.BadRequest("Error parsing request: off-ledger request expected")
+	}
+	return req, nil
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/chains"
 	"github.com/iotaledger/wasp/packages/iscp"
@@ -21,11 +23,13 @@ import (
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
+	"golang.org/x/xerrors"
 )
 
 type (
 	getAccountBalanceFn       func(ch chain.Chain, agentID *iscp.AgentID) (colored.Balances, error)
 	hasRequestBeenProcessedFn func(ch chain.Chain, reqID iscp.RequestID) (bool, error)
+	getAccountNonceFn         func(ch chain.Chain, agentID *iscp.AgentID) (uint64, error)
 )
 
 func AddEndpoints(
@@ -33,6 +37,7 @@ func AddEndpoints(
 	getChain chains.ChainProvider,
 	getChainBalance getAccountBalanceFn,
 	hasRequestBeenProcessed hasRequestBeenProcessedFn,
+	getAccountNonce getAccountNonceFn,
 	cacheTTL time.Duration,
 	log *logger.Logger,
 ) {
@@ -40,6 +45,7 @@ func AddEndpoints(
 		getChain:                getChain,
 		getAccountBalance:       getChainBalance,
 		hasRequestBeenProcessed: hasRequestBeenProcessed,
+		getAccountNonce:         getAccountNonce,
 		requestsCache:           expiringcache.New(cacheTTL),
 		log:                     log,
 	}
@@ -51,85 +57,204 @@ func AddEndpoints(
 			"Request",
 			"Offledger Request encoded in base64. Optionally, the body can be the binary representation of the offledger request, but mime-type must be specified to \"application/octet-stream\"",
 			false).
-		AddResponse(http.StatusAccepted, "Request submitted", nil, nil)
+		AddResponse(http.StatusAccepted, "Request submitted", nil, nil).
+		AddResponse(http.StatusRequestEntityTooLarge, "Request exceeds the mempool size limit", nil, nil).
+		AddResponse(http.StatusTooManyRequests, "Too many pending requests of the sender", nil, nil).
+		AddResponse(http.StatusServiceUnavailable, "Mempool is full", nil, nil)
+
+	server.POST(routes.NewRequestBatch(":chainID"), instance.handleNewRequestBatch).
+		SetSummary("New batch of off-ledger requests").
+		AddParamPath("", "chainID", "chainID represented in base58").
+		AddParamBody(
+			model.OffLedgerRequestBatchBody{Requests: []model.Bytes{"base64 string"}},
+			"Requests",
+			fmt.Sprintf("Offledger Requests encoded in base64, at most %d. Optionally, the body can be the binary representation of the offledger requests, each one prefixed with its length as uint32, but mime-type must be specified to \"application/octet-stream\"", MaxBatchSize),
+			false).
+		AddResponse(http.StatusOK, "Results of the requests", model.OffLedgerRequestBatchResponse{}, nil).
+		AddResponse(http.StatusRequestEntityTooLarge, "Too many requests in the batch", nil, nil)
+
+	server.GET(routes.EthereumAccount(":chainID", ":ethAddress"), instance.handleEthereumAccount).
+		SetSummary("Get the on-chain account of the sender of off-ledger requests signed with an Ethereum key").
+		AddParamPath("", "chainID", "chainID represented in base58").
+		AddParamPath("", "ethAddress", "Ethereum address (hex)").
+		AddResponse(http.StatusOK, "Agent ID and nonce of the account", model.EthereumAccountResponse{}, nil)
 }
 
 type offLedgerReqAPI struct {
 	getChain                chains.ChainProvider
 	getAccountBalance       getAccountBalanceFn
 	hasRequestBeenProcessed hasRequestBeenProcessedFn
+	getAccountNonce         getAccountNonceFn
 	requestsCache           *expiringcache.ExpiringCache
 	log                     *logger.Logger
 }
 
+// cachedRequest is the reason why the request is kept in the requests cache
+type cachedRequest int
+
+const (
+	// the request can't be accepted: it has a wrong signature or it is already processed
+	cachedRejected = cachedRequest(iota)
+	// the request has been accepted by the mempool. It may still be evicted from it
+	cachedAccepted
+)
+
 func (o *offLedgerReqAPI) handleNewRequest(c echo.Context) error {
 	chainID, offLedgerReq, err := parseParams(c)
 	if err != nil {
 		return err
 	}
+	ch, balances, err := o.validateRequest(chainID, offLedgerReq)
+	if err != nil {
+		return err
+	}
+	if err := checkBalances(offLedgerReq, balances); err != nil {
+		o.requestsCache.Set(offLedgerReq.ID(), cachedRejected)
+		return err
+	}
+	errs, err := o.admitRequests(c, ch, []*request.OffLedger{offLedgerReq})
+	if err != nil {
+		return err
+	}
+	if errs[0] != nil {
+		// rejected requests are not cached, they can be retried later
+		return mempoolRejectionError(errs[0])
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+// admitRequests hands the requests to the chain and waits until the mempool admits or rejects them.
+// Only the accepted requests are cached. It returns the result of each request
+func (o *offLedgerReqAPI) admitRequests(c echo.Context, ch chain.Chain, reqs []*request.OffLedger) ([]error, error) {
+	admitted := make(chan []error, 1)
+	ch.EnqueueOffLedgerRequestBatchMsg(&messages.OffLedgerRequestBatchMsgIn{
+		ChainID: ch.ID(),
+		Reqs:    reqs,
+		Results: admitted,
+	})
+	var errs []error
+	select {
+	case errs = <-admitted:
+	case <-c.Request().Context().Done():
+		return nil, httperrors.ServiceUnavailable("the requests have been handed to the chain, the results are not known")
+	}
+	for i, err := range errs {
+		if err == nil {
+			o.requestsCache.Set(reqs[i].ID(), cachedAccepted)
+		}
+	}
+	return errs, nil
+}
 
+// validateRequest checks the request against the state of the chain and returns the chain and the balances
+// of the sender. The limits of the mempool are not checked
+func (o *offLedgerReqAPI) validateRequest(chainID *iscp.ChainID, offLedgerReq *request.OffLedger) (chain.Chain, colored.Balances, error) {
 	reqID := offLedgerReq.ID()
 
-	if o.requestsCache.Get(reqID) != nil {
-		return httperrors.BadRequest("request already processed")
+	cached := o.requestsCache.Get(reqID)
+	if cached == cachedRejected {
+		return nil, nil, httperrors.BadRequest("request already processed")
 	}
 
 	// check req signature
-	if !offLedgerReq.VerifySignature() {
-		o.requestsCache.Set(reqID, true)
-		return httperrors.BadRequest("Invalid signature.")
+	if cached == nil && !offLedgerReq.VerifySignature() {
+		o.requestsCache.Set(reqID, cachedRejected)
+		return nil, nil, httperrors.BadRequest("Invalid signature.")
 	}
 
 	// check req is for the correct chain
 	if !offLedgerReq.ChainID().Equals(chainID) {
 		// do not add to cache, it can still be sent to the correct chain
-		return httperrors.BadRequest("Request is for a different chain")
+		return nil, nil, httperrors.BadRequest("Request is for a different chain")
 	}
 
 	// check chain exists
 	ch := o.getChain(chainID)
 	if ch == nil {
-		return httperrors.NotFound(fmt.Sprintf("Unknown chain: %s", chainID.Base58()))
+		return nil, nil, httperrors.NotFound(fmt.Sprintf("Unknown chain: %s", chainID.Base58()))
+	}
+
+	// the accepted request is known to the chain, unless it has been evicted from the mempool
+	if cached == cachedAccepted && ch.GetRequestProcessingStatus(reqID) != chain.RequestProcessingStatusUnknown {
+		return nil, nil, httperrors.BadRequest("request already processed")
 	}
 
 	alreadyProcessed, err := o.hasRequestBeenProcessed(ch, reqID)
 	if err != nil {
 		o.log.Errorf("webapi.offledger - check if already processed: %w", err)
-		return httperrors.ServerError("internal error")
+		return nil, nil, httperrors.ServerError("internal error")
 	}
 
 	if alreadyProcessed {
-		o.requestsCache.Set(reqID, true)
-		return httperrors.BadRequest("request already processed")
+		o.requestsCache.Set(reqID, cachedRejected)
+		return nil, nil, httperrors.BadRequest("request already processed")
 	}
 
 	// check user has on-chain balance
 	balances, err := o.getAccountBalance(ch, offLedgerReq.SenderAccount())
 	if err != nil {
 		o.log.Errorf("webapi.offledger - account balance: %w", err)
-		return httperrors.ServerError("Unable to get account balance")
+		return nil, nil, httperrors.ServerError("Unable to get account balance")
 	}
+	return ch, balances, nil
+}
 
-	o.requestsCache.Set(reqID, true)
-
+func checkBalances(offLedgerReq *request.OffLedger, balances colored.Balances) error {
 	if len(balances) == 0 {
 		return httperrors.BadRequest(fmt.Sprintf("No balance on account %s", offLedgerReq.SenderAccount().Base58()))
 	}
-	ch.EnqueueOffLedgerRequestMsg(&messages.OffLedgerRequestMsgIn{
-		OffLedgerRequestMsg: messages.OffLedgerRequestMsg{
-			ChainID: ch.ID(),
-			Req:     offLedgerReq,
-		},
-		SenderNetID: "",
+	return nil
+}
+
+func (o *offLedgerReqAPI) handleEthereumAccount(c echo.Context) error {
+	chainID, err := parseChainID(c)
+	if err != nil {
+		return err
+	}
+	if !common.IsHexAddress(c.Param("ethAddress")) {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid Ethereum address: %s", c.Param("ethAddress")))
+	}
+	ch := o.getChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Unknown chain: %s", chainID.Base58()))
+	}
+	agentID := request.AgentIDFromEthereum(common.HexToAddress(c.Param("ethAddress")))
+	nonce, err := o.getAccountNonce(ch, agentID)
+	if err != nil {
+		o.log.Errorf("webapi.offledger - account nonce: %v", err)
+		return httperrors.ServerError("Unable to get account nonce")
+	}
+	// the account contract keeps the maximum nonce assumed to be used
+	return c.JSON(http.StatusOK, &model.EthereumAccountResponse{
+		AgentID: agentID.String(),
+		Nonce:   nonce + 1,
 	})
+}
 
-	return c.NoContent(http.StatusAccepted)
+func mempoolRejectionError(err error) error {
+	switch {
+	case xerrors.Is(err, mempool.ErrRequestTooLarge):
+		return httperrors.PayloadTooLarge(err.Error())
+	case xerrors.Is(err, mempool.ErrSenderLimitExceeded):
+		return httperrors.TooManyRequests(err.Error())
+	case xerrors.Is(err, mempool.ErrPoolFull):
+		return httperrors.ServiceUnavailable(err.Error())
+	}
+	return httperrors.ServerError(err.Error())
+}
+
+func parseChainID(c echo.Context) (*iscp.ChainID, error) {
+	chainID, err := iscp.ChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return nil, httperrors.BadRequest(fmt.Sprintf("Invalid Chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	return chainID, nil
 }
 
 func parseParams(c echo.Context) (chainID *iscp.ChainID, req *request.OffLedger, err error) {
-	chainID, err = iscp.ChainIDFromBase58(c.Param("chainID"))
+	chainID, err = parseChainID(c)
 	if err != nil {
-		return nil, nil, httperrors.BadRequest(fmt.Sprintf("Invalid Chain ID %+v: %s", c.Param("chainID"), err.Error()))
+		return nil, nil, err
 	}
 
 	contentType := c.Request().Header.Get("Content-Type")
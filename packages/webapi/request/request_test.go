@@ -5,13 +5,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/marshalutil"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/messages"
 	"github.com/iotaledger/wasp/packages/chains"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/iscp/request"
 	"github.com/iotaledger/wasp/packages/metrics/nodeconnmetrics"
 	util "github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/testutil/testchain"
@@ -20,10 +25,13 @@ import (
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/packages/webapi/testutil"
+	"github.com/stretchr/testify/require"
 )
 
 type mockedChain struct {
 	*testchain.MockedChainCore
+	mempoolErr    error
+	requestStatus chain.RequestProcessingStatus
 }
 
 var (
@@ -37,7 +45,11 @@ var (
 // chain.ChainRequests implementation
 
 func (m *mockedChain) GetRequestProcessingStatus(_ iscp.RequestID) chain.RequestProcessingStatus {
-	panic("implement me")
+	return m.requestStatus
+}
+
+func (m *mockedChain) CheckRequest(_ iscp.Request) error {
+	return m.mempoolErr
 }
 
 func (m *mockedChain) AttachToRequestProcessed(func(iscp.RequestID)) (attachID *events.Closure) {
@@ -74,13 +86,25 @@ func (m *mockedChain) GetNodeConnectionMetrics() nodeconnmetrics.NodeConnectionM
 
 // private methods
 
-func createMockedGetChain(t *testing.T) chains.ChainProvider {
+func createMockedGetChain(t *testing.T, mempoolErr ...error) chains.ChainProvider {
 	return func(chainID *iscp.ChainID) chain.Chain {
 		chainCore := testchain.NewMockedChainCore(t, chainID, testlogger.NewLogger(t))
 		chainCore.OnOffLedgerRequest(func(msg *messages.OffLedgerRequestMsgIn) {
 			t.Logf("Offledger request %v received", msg)
 		})
-		return &mockedChain{chainCore}
+		ret := &mockedChain{MockedChainCore: chainCore, requestStatus: chain.RequestProcessingStatusBacklog}
+		if len(mempoolErr) > 0 {
+			ret.mempoolErr = mempoolErr[0]
+		}
+		chainCore.OnOffLedgerRequestBatch(func(msg *messages.OffLedgerRequestBatchMsgIn) {
+			t.Logf("Offledger request batch of %d requests received", len(msg.Reqs))
+			results := make([]error, len(msg.Reqs))
+			for i := range results {
+				results[i] = ret.mempoolErr
+			}
+			msg.Results <- results
+		})
+		return ret
 	}
 }
 
@@ -94,10 +118,15 @@ func hasRequestBeenProcessedMocked(ret bool) hasRequestBeenProcessedFn {
 	}
 }
 
+func getAccountNonceMocked(_ chain.Chain, _ *iscp.AgentID) (uint64, error) {
+	return 42, nil
+}
+
 func newMockedAPI(t *testing.T) *offLedgerReqAPI {
 	return &offLedgerReqAPI{
 		getChain:                createMockedGetChain(t),
 		getAccountBalance:       getAccountBalanceMocked,
+		getAccountNonce:         getAccountNonceMocked,
 		hasRequestBeenProcessed: hasRequestBeenProcessedMocked(false),
 		requestsCache:           expiringcache.New(10 * time.Second),
 	}
@@ -116,6 +145,25 @@ func testRequest(t *testing.T, instance *offLedgerReqAPI, chainID *iscp.ChainID,
 	)
 }
 
+func testRequestBatch(t *testing.T, instance *offLedgerReqAPI, chainID *iscp.ChainID, body interface{}, expectedStatus int) []model.OffLedgerRequestResult {
+	res := &model.OffLedgerRequestBatchResponse{}
+	var resObj interface{}
+	if expectedStatus < 400 {
+		resObj = res
+	}
+	testutil.CallWebAPIRequestHandler(
+		t,
+		instance.handleNewRequestBatch,
+		http.MethodPost,
+		routes.NewRequestBatch(":chainID"),
+		map[string]string{"chainID": chainID.Base58()},
+		body,
+		resObj,
+		expectedStatus,
+	)
+	return res.Results
+}
+
 // Tests
 
 func TestNewRequestBase64(t *testing.T) {
@@ -132,6 +180,48 @@ func TestNewRequestBinary(t *testing.T) {
 	testRequest(t, instance, chainID, body, http.StatusAccepted)
 }
 
+func TestNewRequestSecp256k1(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := iscp.RandomChainID()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	req := request.NewOffLedger(chainID, iscp.Hn("target"), iscp.Hn("entry point"), nil)
+	req.SignSecp256k1(key)
+	body := model.OffLedgerRequestBody{Request: model.NewBytes(req.Bytes())}
+	testRequest(t, instance, chainID, body, http.StatusAccepted)
+}
+
+func TestEthereumAccount(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := iscp.RandomChainID()
+	ethAddress := common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+
+	res := &model.EthereumAccountResponse{}
+	testutil.CallWebAPIRequestHandler(
+		t,
+		instance.handleEthereumAccount,
+		http.MethodGet,
+		routes.EthereumAccount(":chainID", ":ethAddress"),
+		map[string]string{"chainID": chainID.Base58(), "ethAddress": ethAddress.Hex()},
+		nil,
+		res,
+		http.StatusOK,
+	)
+	require.EqualValues(t, request.AgentIDFromEthereum(ethAddress).String(), res.AgentID)
+	require.EqualValues(t, 43, res.Nonce)
+
+	testutil.CallWebAPIRequestHandler(
+		t,
+		instance.handleEthereumAccount,
+		http.MethodGet,
+		routes.EthereumAccount(":chainID", ":ethAddress"),
+		map[string]string{"chainID": chainID.Base58(), "ethAddress": "foo"},
+		nil,
+		nil,
+		http.StatusBadRequest,
+	)
+}
+
 func TestRequestAlreadyProcessed(t *testing.T) {
 	instance := newMockedAPI(t)
 	instance.hasRequestBeenProcessed = hasRequestBeenProcessedMocked(true)
@@ -146,3 +236,155 @@ func TestWrongChainID(t *testing.T) {
 	body := util.DummyOffledgerRequest(iscp.RandomChainID()).Bytes()
 	testRequest(t, instance, iscp.RandomChainID(), body, http.StatusBadRequest)
 }
+
+func TestMempoolRejection(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+	}{
+		{mempool.ErrRequestTooLarge, http.StatusRequestEntityTooLarge},
+		{mempool.ErrSenderLimitExceeded, http.StatusTooManyRequests},
+		{mempool.ErrPoolFull, http.StatusServiceUnavailable},
+	} {
+		instance := newMockedAPI(t)
+		instance.getChain = createMockedGetChain(t, tc.err)
+
+		chainID := iscp.RandomChainID()
+		body := util.DummyOffledgerRequest(chainID).Bytes()
+		testRequest(t, instance, chainID, body, tc.status)
+
+		// rejected requests can be submitted again when the mempool has room for them
+		instance.getChain = createMockedGetChain(t)
+		testRequest(t, instance, chainID, body, http.StatusAccepted)
+	}
+}
+
+func TestRequestEvictedFromMempool(t *testing.T) {
+	instance := newMockedAPI(t)
+	ch := &mockedChain{requestStatus: chain.RequestProcessingStatusBacklog}
+	instance.getChain = func(chainID *iscp.ChainID) chain.Chain {
+		ch.MockedChainCore = testchain.NewMockedChainCore(t, chainID, testlogger.NewLogger(t))
+		ch.OnOffLedgerRequestBatch(func(msg *messages.OffLedgerRequestBatchMsgIn) {
+			msg.Results <- make([]error, len(msg.Reqs))
+		})
+		return ch
+	}
+
+	chainID := iscp.RandomChainID()
+	body := util.DummyOffledgerRequest(chainID).Bytes()
+	testRequest(t, instance, chainID, body, http.StatusAccepted)
+	// the request is still in the mempool
+	testRequest(t, instance, chainID, body, http.StatusBadRequest)
+
+	// the request was evicted from the mempool: it can be submitted again
+	ch.requestStatus = chain.RequestProcessingStatusUnknown
+	testRequest(t, instance, chainID, body, http.StatusAccepted)
+}
+
+func TestNewRequestBatchBase64(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := iscp.RandomChainID()
+	req1 := util.DummyOffledgerRequest(chainID)
+	req2 := util.DummyOffledgerRequest(chainID)
+	body := model.OffLedgerRequestBatchBody{Requests: []model.Bytes{
+		model.NewBytes(req1.Bytes()),
+		model.NewBytes(util.DummyOffledgerRequest(iscp.RandomChainID()).Bytes()),
+		model.NewBytes([]byte{1, 2, 3}),
+		model.NewBytes(req2.Bytes()),
+		model.NewBytes(req1.Bytes()),
+	}}
+	results := testRequestBatch(t, instance, chainID, body, http.StatusOK)
+	require.Len(t, results, 5)
+	for i, status := range []int{http.StatusAccepted, http.StatusBadRequest, http.StatusBadRequest, http.StatusAccepted, http.StatusBadRequest} {
+		require.EqualValues(t, status, results[i].Status)
+		require.Equal(t, status == http.StatusAccepted, results[i].Accepted)
+		require.Equal(t, status == http.StatusAccepted, results[i].Error == "")
+	}
+	require.Equal(t, req1.ID().Base58(), results[0].RequestID)
+	require.Empty(t, results[2].RequestID)
+
+	// the accepted requests are already known to the node
+	results = testRequestBatch(t, instance, chainID, body, http.StatusOK)
+	require.False(t, results[0].Accepted)
+	require.False(t, results[3].Accepted)
+}
+
+func TestNewRequestBatchBinary(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := iscp.RandomChainID()
+	var received []*request.OffLedger
+	instance.getChain = func(chainID *iscp.ChainID) chain.Chain {
+		chainCore := testchain.NewMockedChainCore(t, chainID, testlogger.NewLogger(t))
+		chainCore.OnOffLedgerRequestBatch(func(msg *messages.OffLedgerRequestBatchMsgIn) {
+			received = append(received, msg.Reqs...)
+			msg.Results <- make([]error, len(msg.Reqs))
+		})
+		return &mockedChain{MockedChainCore: chainCore}
+	}
+
+	mu := marshalutil.New()
+	reqs := make([]*request.OffLedger, 10)
+	for i := range reqs {
+		reqs[i] = util.DummyOffledgerRequest(chainID)
+		data := reqs[i].Bytes()
+		mu.WriteUint32(uint32(len(data))).WriteBytes(data)
+	}
+	results := testRequestBatch(t, instance, chainID, mu.Bytes(), http.StatusOK)
+	require.Len(t, results, len(reqs))
+	require.Len(t, received, len(reqs))
+	for i, req := range reqs {
+		require.True(t, results[i].Accepted)
+		require.Equal(t, req.ID(), received[i].ID())
+	}
+
+	// the batch is malformed as a whole
+	testRequestBatch(t, instance, chainID, mu.Bytes()[:len(mu.Bytes())-1], http.StatusBadRequest)
+}
+
+func TestNewRequestBatchMempoolAdmission(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := iscp.RandomChainID()
+	// the mempool accepts only the first requests of the batch
+	const limit = 2
+	instance.getChain = func(chainID *iscp.ChainID) chain.Chain {
+		chainCore := testchain.NewMockedChainCore(t, chainID, testlogger.NewLogger(t))
+		chainCore.OnOffLedgerRequestBatch(func(msg *messages.OffLedgerRequestBatchMsgIn) {
+			results := make([]error, len(msg.Reqs))
+			for i := limit; i < len(results); i++ {
+				results[i] = mempool.ErrSenderLimitExceeded
+			}
+			msg.Results <- results
+		})
+		return &mockedChain{MockedChainCore: chainCore, requestStatus: chain.RequestProcessingStatusBacklog}
+	}
+
+	body := model.OffLedgerRequestBatchBody{Requests: make([]model.Bytes, 4)}
+	for i := range body.Requests {
+		body.Requests[i] = model.NewBytes(util.DummyOffledgerRequest(chainID).Bytes())
+	}
+	results := testRequestBatch(t, instance, chainID, body, http.StatusOK)
+	require.Len(t, results, len(body.Requests))
+	for i, res := range results {
+		require.Equal(t, i < limit, res.Accepted)
+		if i >= limit {
+			require.EqualValues(t, http.StatusTooManyRequests, res.Status)
+		}
+	}
+
+	// the rejected requests can be submitted again
+	results = testRequestBatch(t, instance, chainID, body, http.StatusOK)
+	for i, res := range results {
+		require.Equal(t, i < limit, res.Status == http.StatusBadRequest)
+	}
+}
+
+func TestNewRequestBatchTooLarge(t *testing.T) {
+	instance := newMockedAPI(t)
+	chainID := iscp.RandomChainID()
+	data := util.DummyOffledgerRequest(chainID).Bytes()
+	body := model.OffLedgerRequestBatchBody{Requests: make([]model.Bytes, MaxBatchSize+1)}
+	for i := range body.Requests {
+		body.Requests[i] = model.NewBytes(data)
+	}
+	testRequestBatch(t, instance, chainID, body, http.StatusRequestEntityTooLarge)
+}
@@ -11,6 +11,14 @@ func NewRequest(chainID string) string {
 	return "/request/" + chainID
 }
 
+func NewRequestBatch(chainID string) string {
+	return "/requests/" + chainID
+}
+
+func EthereumAccount(chainID, ethAddress string) string {
+	return "/chain/" + chainID + "/ethereum/" + ethAddress
+}
+
 func CallView(chainID, contractHname, functionName string) string {
 	return "chain/" + chainID + "/contract/" + contractHname + "/callview/" + functionName
 }
@@ -19,14 +27,38 @@ func RequestStatus(chainID, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/status"
 }
 
+func RequestReceipt(chainID, reqID string) string {
+	return "/chain/" + chainID + "/request/" + reqID + "/receipt"
+}
+
 func WaitRequestProcessed(chainID, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/wait"
 }
 
+func ContractEvents(chainID, contractHname string) string {
+	return "/chain/" + chainID + "/contract/" + contractHname + "/events"
+}
+
+func ContractEventsByName(chainID, contractHname, eventName string) string {
+	return "/chain/" + chainID + "/contract/" + contractHname + "/events/" + eventName
+}
+
+func EventStream(chainID string) string {
+	return "/chain/" + chainID + "/eventstream"
+}
+
+func CommitteePublicKey(chainID string) string {
+	return "/chain/" + chainID + "/committee/pubkey"
+}
+
 func StateGet(chainID, key string) string {
 	return "/chain/" + chainID + "/state/" + key
 }
 
+func StateGetProof(chainID, key string) string {
+	return "/chain/" + chainID + "/stateproof/" + key
+}
+
 func ActivateChain(chainID string) string {
 	return "/adm/chain/" + chainID + "/activate"
 }
@@ -39,6 +71,10 @@ func ListChainRecords() string {
 	return "/adm/chainrecords"
 }
 
+func RotateChain(chainID string) string {
+	return "/adm/chain/" + chainID + "/rotate"
+}
+
 func PutChainRecord() string {
 	return "/adm/chainrecord"
 }
@@ -71,6 +107,10 @@ func DKSharesPost() string {
 	return "/adm/dks"
 }
 
+func DKSharesReshare() string {
+	return "/adm/dks/reshare"
+}
+
 func DKSharesGet(sharedAddress string) string {
 	return "/adm/dks/" + sharedAddress
 }
@@ -6,14 +6,20 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/chains"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/iscp"
 	"github.com/iotaledger/wasp/packages/iscp/coreutil"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/optimism"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/trie"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/packages/webapi/webapiutil"
 	"github.com/labstack/echo/v4"
@@ -37,6 +43,8 @@ func AddEndpoints(server echoswagger.ApiRouter, allChains chains.Provider) {
 		AddParamPath("", "contractHname", "Contract Hname").
 		AddParamPath("getInfo", "fname", "Function name").
 		AddParamBody(dictExample, "params", "Parameters", false).
+		AddParamQuery(uint32(0), "blockIndex", "Call the view against the state after the given block, defaults to the latest state", false).
+		AddParamQuery("", "stateHash", "Call the view against the state with the given hash (base58), defaults to the latest state", false).
 		AddResponse(http.StatusOK, "Result", dictExample, nil)
 
 	server.GET(routes.CallView(":chainID", ":contractHname", ":fname"), s.handleCallView).
@@ -45,13 +53,28 @@ func AddEndpoints(server echoswagger.ApiRouter, allChains chains.Provider) {
 		AddParamPath("", "contractHname", "Contract Hname").
 		AddParamPath("getInfo", "fname", "Function name").
 		AddParamBody(dictExample, "params", "Parameters", false).
+		AddParamQuery(uint32(0), "blockIndex", "Call the view against the state after the given block, defaults to the latest state", false).
+		AddParamQuery("", "stateHash", "Call the view against the state with the given hash (base58), defaults to the latest state", false).
 		AddResponse(http.StatusOK, "Result", dictExample, nil)
 
 	server.GET(routes.StateGet(":chainID", ":key"), s.handleStateGet).
 		SetSummary("Fetch the raw value associated with the given key in the chain state").
 		AddParamPath("", "chainID", "ChainID (base58-encoded)").
 		AddParamPath("", "key", "Key (hex-encoded)").
+		AddParamQuery(uint32(0), "blockIndex", "Read the state after the given block, defaults to the latest state", false).
+		AddParamQuery("", "stateHash", "Read the state with the given hash (base58), defaults to the latest state", false).
 		AddResponse(http.StatusOK, "Result", []byte("value"), nil)
+
+	server.GET(routes.StateGetProof(":chainID", ":key"), s.handleStateGetProof).
+		SetSummary("Fetch the raw value associated with the given key in the chain state together with the proof against the state hash").
+		AddParamPath("", "chainID", "ChainID (base58-encoded)").
+		AddParamPath("", "key", "Key (hex-encoded)").
+		AddResponse(http.StatusOK, "Result", model.StateProofResponse{}, nil)
+
+	server.GET(routes.CommitteePublicKey(":chainID"), s.handleCommitteePublicKey).
+		SetSummary("Fetch the shared public key of the committee which currently runs the chain").
+		AddParamPath("", "chainID", "ChainID (base58-encoded)").
+		AddResponse(http.StatusOK, "Committee public key", model.CommitteePublicKey{}, nil)
 }
 
 func (s *callViewService) handleCallView(c echo.Context) error {
@@ -76,8 +99,20 @@ func (s *callViewService) handleCallView(c echo.Context) error {
 	if theChain == nil {
 		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID))
 	}
-	ret, err := webapiutil.CallView(theChain, contractHname, iscp.Hn(fname), params)
+	blockIndex, err := historicalBlockIndex(c, theChain)
+	if err != nil {
+		return err
+	}
+	var ret dict.Dict
+	if blockIndex == nil {
+		ret, err = webapiutil.CallView(theChain, contractHname, iscp.Hn(fname), params)
+	} else {
+		ret, err = webapiutil.CallViewAtBlock(theChain, *blockIndex, contractHname, iscp.Hn(fname), params)
+	}
 	if err != nil {
+		if errors.Is(err, state.ErrStateNotRetained) {
+			return httperrors.Gone(fmt.Sprintf("View call failed: %v", err))
+		}
 		return httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
 	}
 
@@ -99,11 +134,21 @@ func (s *callViewService) handleStateGet(c echo.Context) error {
 	if theChain == nil {
 		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID))
 	}
+	blockIndex, err := historicalBlockIndex(c, theChain)
+	if err != nil {
+		return err
+	}
 
 	var ret []byte
 	err = optimism.RetryOnStateInvalidated(func() error {
 		var err error
-		ret, err = theChain.GetStateReader().KVStoreReader().Get(kv.Key(key))
+		stateReader := theChain.GetStateReader()
+		if blockIndex != nil {
+			if stateReader, err = theChain.GetHistoricalStateReader(*blockIndex); err != nil {
+				return err
+			}
+		}
+		ret, err = stateReader.KVStoreReader().Get(kv.Key(key))
 		return err
 	})
 	if err != nil {
@@ -111,8 +156,95 @@ func (s *callViewService) handleStateGet(c echo.Context) error {
 		if errors.Is(err, coreutil.ErrorStateInvalidated) {
 			return httperrors.Conflict(reason)
 		}
+		if errors.Is(err, state.ErrStateNotRetained) {
+			return httperrors.Gone(reason)
+		}
 		return httperrors.BadRequest(reason)
 	}
 
 	return c.JSON(http.StatusOK, ret)
 }
+
+func (s *callViewService) handleStateGetProof(c echo.Context) error {
+	chainID, err := iscp.ChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+
+	key, err := hex.DecodeString(c.Param("key"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("cannot parse hex-encoded key: %+v", c.Param("key")))
+	}
+
+	theChain := s.chains().Get(chainID)
+	if theChain == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID))
+	}
+
+	var value []byte
+	var stateHash hashing.HashValue
+	var proof *trie.Proof
+	err = optimism.RetryOnStateInvalidated(func() error {
+		var err error
+		stateReader := theChain.GetStateReader()
+		if value, err = stateReader.KVStoreReader().Get(kv.Key(key)); err != nil {
+			return err
+		}
+		if proof, err = stateReader.GetProof(kv.Key(key)); err != nil {
+			return err
+		}
+		stateHash, err = stateReader.Hash()
+		return err
+	})
+	if err != nil {
+		reason := fmt.Sprintf("State proof failed: %v", err)
+		if errors.Is(err, coreutil.ErrorStateInvalidated) {
+			return httperrors.Conflict(reason)
+		}
+		return httperrors.BadRequest(reason)
+	}
+
+	return c.JSON(http.StatusOK, model.StateProofResponse{
+		Key:       model.NewBytes(key),
+		Value:     model.NewBytes(value),
+		StateHash: model.NewHashValue(stateHash),
+		Proof:     model.NewBytes(proof.Bytes()),
+	})
+}
+
+// historicalBlockIndex returns the block index selected by the optional 'blockIndex' or 'stateHash'
+// query parameters, or nil if the latest state is requested
+func historicalBlockIndex(c echo.Context, ch chain.ChainCore) (*uint32, error) {
+	blockIndexParam := c.QueryParam("blockIndex")
+	stateHashParam := c.QueryParam("stateHash")
+	switch {
+	case blockIndexParam != "" && stateHashParam != "":
+		return nil, httperrors.BadRequest("blockIndex and stateHash are mutually exclusive")
+	case blockIndexParam != "":
+		blockIndex, err := strconv.ParseUint(blockIndexParam, 10, 32)
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", blockIndexParam))
+		}
+		ret := uint32(blockIndex)
+		return &ret, nil
+	case stateHashParam != "":
+		stateHash, err := hashing.HashValueFromBase58(stateHashParam)
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("Invalid state hash: %+v", stateHashParam))
+		}
+		var ret uint32
+		err = optimism.RetryOnStateInvalidated(func() error {
+			var err error
+			ret, err = ch.FindBlockIndexByStateHash(stateHash)
+			return err
+		})
+		if errors.Is(err, state.ErrStateNotRetained) {
+			return nil, httperrors.Gone(fmt.Sprintf("State not found: %v", err))
+		}
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("State not found: %v", err))
+		}
+		return &ret, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,37 @@
+package state
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/labstack/echo/v4"
+)
+
+// handleCommitteePublicKey returns the shared public key of the committee of the chain. Clients must verify it
+// against the state address of the chain output on L1, the node is not trusted
+func (s *callViewService) handleCommitteePublicKey(c echo.Context) error {
+	chainID, err := iscp.ChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	theChain := s.chains().Get(chainID)
+	if theChain == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID))
+	}
+	committeeInfo := theChain.GetCommitteeInfo()
+	if committeeInfo == nil || committeeInfo.SharedPublic == nil {
+		return httperrors.NotFound(fmt.Sprintf("Committee of chain %s is not available", chainID))
+	}
+	pubKey, err := committeeInfo.SharedPublic.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, model.CommitteePublicKey{
+		Address:      model.NewAddress(committeeInfo.Address),
+		SharedPubKey: base64.StdEncoding.EncodeToString(pubKey),
+	})
+}
@@ -19,3 +19,19 @@ func CallView(ch chain.ChainCore, contractHname, viewHname iscp.Hname, params di
 
 	return ret, err
 }
+
+// CallViewAtBlock calls the view function against the state after the block with the given index
+func CallViewAtBlock(ch chain.ChainCore, blockIndex uint32, contractHname, viewHname iscp.Hname, params dict.Dict) (dict.Dict, error) {
+	var ret dict.Dict
+	err := optimism.RetryOnStateInvalidated(func() error {
+		stateReader, err := ch.GetHistoricalStateReader(blockIndex)
+		if err != nil {
+			return err
+		}
+		vctx := viewcontext.New(ch.ID(), stateReader, ch.Processors(), ch.Log().Named("view"))
+		ret, err = vctx.CallView(contractHname, viewHname, params)
+		return err
+	})
+
+	return ret, err
+}
@@ -0,0 +1,37 @@
+package webapiutil
+
+import (
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/blocklog"
+)
+
+// GetRequestReceipt returns the receipt of the request together with the indices of the block and of the request in it.
+// Returns nil receipt if the request has not been processed yet
+func GetRequestReceipt(ch chain.ChainCore, reqID iscp.RequestID) (*blocklog.RequestReceipt, uint32, uint16, error) {
+	res, err := CallView(ch, blocklog.Contract.Hname(), blocklog.FuncGetRequestReceipt.Hname(),
+		dict.Dict{
+			blocklog.ParamRequestID: reqID.Bytes(),
+		})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !res.MustHas(blocklog.ParamRequestRecord) {
+		return nil, 0, 0, nil
+	}
+	receipt, err := blocklog.RequestReceiptFromBytes(res.MustGet(blocklog.ParamRequestRecord))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	blockIndex, err := codec.DecodeUint32(res.MustGet(blocklog.ParamBlockIndex))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	requestIndex, err := codec.DecodeUint16(res.MustGet(blocklog.ParamRequestIndex))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return receipt, blockIndex, requestIndex, nil
+}
@@ -2,25 +2,43 @@ package webapi
 
 import (
 	_ "embed"
+	"fmt"
 
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chains"
 	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/publisher/eventstream"
 	"github.com/iotaledger/wasp/packages/publisher/publisherws"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
 type webSocketAPI struct {
-	pws *publisherws.PublisherWebSocket
+	pws      *publisherws.PublisherWebSocket
+	stream   *eventstream.EventStream
+	getChain chains.ChainProvider
 }
 
-func addWebSocketEndpoint(e echoswagger.ApiGroup, log *logger.Logger) *webSocketAPI {
+func addWebSocketEndpoint(e echoswagger.ApiGroup, getChain chains.ChainProvider, log *logger.Logger) *webSocketAPI {
 	api := &webSocketAPI{
-		pws: publisherws.New(log, []string{"state", "vmmsg"}),
+		pws:      publisherws.New(log, []string{"state", "vmmsg"}),
+		stream:   eventstream.New(log),
+		getChain: getChain,
 	}
 
 	e.GET("/chain/:chainid/ws", api.handleWebSocket)
 
+	e.GET(routes.EventStream(":chainID"), api.handleEventStream).
+		SetSummary("Stream the blocks, the receipts, the contract events and the rotations of the chain as JSON over the websocket").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamQuery("", eventstream.ParamCursor, "Cursor of the last received event (<blockIndex>-<seq>). The stream continues after it", false).
+		AddParamQuery("", eventstream.ParamTypes, "Comma separated types of the events: block, rotation, receipt, event", false).
+		AddParamQuery("", eventstream.ParamContract, "Contract Hname of the receipts and the events", false).
+		AddParamQuery("", eventstream.ParamEventName, "Name of the typed events", false).
+		AddParamQuery("", eventstream.ParamRequestID, "Request ID of the receipts and the events", false)
+
 	return api
 }
 
@@ -31,3 +49,18 @@ func (w *webSocketAPI) handleWebSocket(c echo.Context) error {
 	}
 	return w.pws.ServeHTTP(chainID, c.Response(), c.Request())
 }
+
+func (w *webSocketAPI) handleEventStream(c echo.Context) error {
+	chainID, err := iscp.ChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	theChain := w.getChain(chainID)
+	if theChain == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", chainID))
+	}
+	if err := w.stream.ServeHTTP(chainID, theChain.GetStateReader, c.Response(), c.Request()); err != nil {
+		return httperrors.BadRequest(err.Error())
+	}
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 	_ "github.com/iotaledger/wasp/packages/chain/chainimpl"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/chain/nodeconnimpl"
+	"github.com/iotaledger/wasp/packages/chain/statemgr"
 	"github.com/iotaledger/wasp/packages/chains"
 	metricspkg "github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/parameters"
@@ -44,6 +46,15 @@ func run(_ *node.Plugin) {
 		parameters.GetInt(parameters.OffledgerBroadcastUpToNPeers),
 		time.Duration(parameters.GetInt(parameters.OffledgerBroadcastInterval))*time.Millisecond,
 		parameters.GetBool(parameters.PullMissingRequestsFromCommittee),
+		uint16(parameters.GetInt(parameters.ConsensusMaxBatchSize)),
+		parameters.GetInt(parameters.VMParallelism),
+		statemgr.SnapshotConfig{
+			Dir:           parameters.GetString(parameters.SnapshotDirectory),
+			Interval:      uint32(parameters.GetInt(parameters.SnapshotInterval)),
+			SyncThreshold: uint32(parameters.GetInt(parameters.SnapshotSyncThreshold)),
+		},
+		uint32(parameters.GetInt(parameters.StateHistoryDepth)),
+		mempoolConfig(),
 		peering.DefaultNetworkProvider(),
 		database.GetOrCreateKVStore,
 	)
@@ -77,3 +88,16 @@ func AllChains() *chains.Chains {
 	initialized.MustWait(5 * time.Second)
 	return allChains
 }
+
+func mempoolConfig() mempool.Config {
+	cfg := mempool.Config{
+		MaxPoolSize:          parameters.GetInt(parameters.MempoolMaxPoolSize),
+		MaxRequestsPerSender: parameters.GetInt(parameters.MempoolMaxRequestsPerSender),
+		MaxTotalBytes:        parameters.GetInt(parameters.MempoolMaxTotalBytes),
+		EvictionPolicy:       mempool.EvictionPolicy(parameters.GetString(parameters.MempoolEvictionPolicy)),
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Panicf("invalid mempool configuration: %v", err)
+	}
+	return cfg
+}
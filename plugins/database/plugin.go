@@ -43,6 +43,10 @@ func run(_ *node.Plugin) {
 	if err != nil {
 		log.Errorf("failed to start as daemon: %s", err)
 	}
+	err = daemon.BackgroundWorker(pluginName+"[Pruning]", dbm.RunPruning, parameters.PriorityDBPruning)
+	if err != nil {
+		log.Errorf("failed to start as daemon: %s", err)
+	}
 }
 
 func GetRegistryKVStore() kvstore.KVStore {
@@ -8,6 +8,7 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	hive_node "github.com/iotaledger/hive.go/node"
 	dkg_pkg "github.com/iotaledger/wasp/packages/dkg"
+	registry_pkg "github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/plugins/peering"
 	"github.com/iotaledger/wasp/plugins/registry"
 	"go.uber.org/zap"
@@ -27,6 +28,11 @@ func Init() *hive_node.Plugin {
 		var err error
 		var nodeIdentity *ed25519.KeyPair
 		if nodeIdentity, err = registry.GetNodeIdentity(); err != nil {
+			if xerrors.Is(err, registry_pkg.ErrKeysHeldBySigner) {
+				// The DKG needs the node identity key, the node can't take part in it.
+				log.Warnf("DKG is disabled: %v", err)
+				return
+			}
 			panic("cannot get the node key")
 		}
 		defaultNode, err = dkg_pkg.NewNode(
@@ -47,6 +53,7 @@ func Init() *hive_node.Plugin {
 
 // DefaultNode returns the default instance of the DKG Node Provider.
 // It should be used to access all the DKG Node functions (not the DKG Initiator's).
+// It is nil, if the keys of the node are held by an external signer.
 func DefaultNode() *dkg_pkg.Node {
 	return defaultNode
 }
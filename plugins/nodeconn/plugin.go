@@ -1,14 +1,12 @@
 package nodeconn
 
 import (
-	"net"
 	"time"
 
-	txstream "github.com/iotaledger/goshimmer/packages/txstream/client"
-
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/chain/nodeconnimpl"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/util/ready"
 	"github.com/iotaledger/wasp/plugins/peering"
@@ -17,12 +15,10 @@ import (
 // PluginName is the name of the NodeConn plugin.
 const PluginName = "NodeConn"
 
-const dialTimeout = 1 * time.Second
-
 var (
 	log *logger.Logger
 
-	nodeConn    *txstream.Client
+	nodeConn    *nodeconnimpl.Pool
 	initialized = ready.New("NodeConn")
 )
 
@@ -31,7 +27,7 @@ func Init() *node.Plugin {
 	return node.NewPlugin(PluginName, node.Enabled, configure, run)
 }
 
-func NodeConnection() *txstream.Client {
+func NodeConnection() *nodeconnimpl.Pool {
 	initialized.MustWait(5 * time.Second)
 	return nodeConn
 }
@@ -40,16 +36,32 @@ func configure(_ *node.Plugin) {
 	log = logger.NewLogger(PluginName)
 }
 
+// nodeAddresses returns the txstream addresses of the L1 nodes
+func nodeAddresses() []string {
+	addrs := parameters.GetStringSlice(parameters.NodeAddresses)
+	if len(addrs) > 0 {
+		return addrs
+	}
+	return []string{parameters.GetString(parameters.NodeAddress)}
+}
+
 func run(_ *node.Plugin) {
 	err := daemon.BackgroundWorker(PluginName, func(shutdownSignal <-chan struct{}) {
-		addr := parameters.GetString(parameters.NodeAddress)
-		dial := txstream.DialFunc(func() (string, net.Conn, error) {
-			log.Infof("connecting with node at %s", addr)
-			conn, err := net.DialTimeout("tcp", addr, dialTimeout)
-			return addr, conn, err
-		})
-
-		nodeConn = txstream.New(peering.DefaultNetworkProvider().Self().NetID(), log, dial)
+		addrs := nodeAddresses()
+		log.Infof("connecting with nodes at %v", addrs)
+
+		var err error
+		nodeConn, err = nodeconnimpl.NewPool(
+			peering.DefaultNetworkProvider().Self().NetID(),
+			addrs,
+			log,
+			time.Duration(parameters.GetInt(parameters.NodeConnProbeInterval))*time.Millisecond,
+			time.Duration(parameters.GetInt(parameters.NodeConnProbeTimeout))*time.Millisecond,
+		)
+		if err != nil {
+			log.Errorf("failed to connect with nodes: %v", err)
+			return
+		}
 		initialized.SetReady()
 		defer nodeConn.Close()
 
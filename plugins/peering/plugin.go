@@ -4,13 +4,13 @@
 package peering
 
 import (
-	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/wasp/packages/parameters"
 	peering_pkg "github.com/iotaledger/wasp/packages/peering"
 	peering_lpp "github.com/iotaledger/wasp/packages/peering/lpp"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/plugins/registry"
 )
 
@@ -29,18 +29,15 @@ func Init() *node.Plugin {
 	configure := func(_ *node.Plugin) {
 		log = logger.NewLogger(pluginName)
 		var err error
-		var nodeKeyPair *ed25519.KeyPair
-		if nodeKeyPair, err = registry.DefaultRegistry().GetNodeIdentity(); err != nil {
-			panic(err)
-		}
-		if err != nil {
+		var nodeSigner signer.Signer
+		if nodeSigner, err = registry.DefaultRegistry().NodeSigner(); err != nil {
 			log.Panicf("Init.peering: %v", err)
 		}
 		netID := parameters.GetString(parameters.PeeringMyNetID)
 		netImpl, tnmImpl, err := peering_lpp.NewNetworkProvider(
 			netID,
 			parameters.GetInt(parameters.PeeringPort),
-			nodeKeyPair,
+			nodeSigner,
 			registry.DefaultRegistry(),
 			log,
 		)
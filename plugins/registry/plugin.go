@@ -6,7 +6,9 @@ package registry
 import (
 	"github.com/iotaledger/hive.go/logger"
 	hive_node "github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/signer"
 	"github.com/iotaledger/wasp/plugins/database"
 )
 
@@ -22,10 +24,26 @@ func DefaultRegistry() *registry.Impl {
 // Init is an entry point for the plugin.
 func Init() *hive_node.Plugin {
 	configure := func(_ *hive_node.Plugin) {
+		log := logger.NewLogger(pluginName)
 		defaultRegistry = registry.NewRegistry(
-			logger.NewLogger(pluginName),
+			log,
 			database.GetRegistryKVStore(),
 		)
+		encryptor, err := registry.EncryptorFromConfig()
+		if err != nil {
+			log.Panicf("Init.registry: %v", err)
+		}
+		if encryptor != nil {
+			defaultRegistry.WithEncryption(encryptor)
+		}
+		if socketPath := parameters.GetString(registry.CfgKeysSignerSocket); socketPath != "" {
+			nodeSigner, err := signer.Dial(socketPath)
+			if err != nil {
+				log.Panicf("Init.registry: cannot connect to the signer: %v", err)
+			}
+			defaultRegistry.WithSigner(nodeSigner)
+			log.Infof("The keys are held by the signer at %s", socketPath)
+		}
 	}
 	run := func(_ *hive_node.Plugin) {
 		// Nothing to run here.
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/ledgerstate"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/client/chainclient"
+	"github.com/iotaledger/wasp/contracts/native/inccounter"
+	"github.com/iotaledger/wasp/packages/apilib"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/iscp/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/stretchr/testify/require"
+)
+
+// the chain runs on the nodes 0..3, the new committee includes the nodes 4 and 5 which don't run it yet
+var (
+	rotateChainPeers        = []int{0, 1, 2, 3}
+	rotateChainNewCommittee = []int{2, 3, 4, 5}
+)
+
+func setupRotateChainTest(t *testing.T) *chainEnv {
+	clu := newCluster(t, 6)
+	chain, err := clu.DeployChainWithDKG("chain", rotateChainPeers, rotateChainPeers, 3)
+	require.NoError(t, err)
+
+	e := newChainEnv(t, clu, chain)
+	_, err = chain.DeployContract(incCounterSCName, inccounter.Contract.ProgramHash.String(), "inccounter testing contract", nil)
+	require.NoError(t, err)
+	waitUntil(t, e.contractIsDeployed(incCounterSCName), rotateChainPeers, 30*time.Second)
+	return e
+}
+
+func governanceCallPoster(e *chainEnv, keyPair *ed25519.KeyPair) apilib.GovernanceCallFn {
+	chainClient := e.chain.Client(keyPair)
+	return func(entryPoint string, params dict.Dict) (iscp.RequestID, error) {
+		tx, err := chainClient.Post1Request(governance.Contract.Hname(), iscp.Hn(entryPoint), chainclient.PostRequestParams{
+			Transfer: colored.NewBalancesForIotas(1),
+			Args:     requestargs.New(params),
+		})
+		if err != nil {
+			return iscp.RequestID{}, err
+		}
+		return request.RequestsInTransaction(e.chain.ChainID, tx)[0], nil
+	}
+}
+
+func (e *chainEnv) rotateChainParams(poster apilib.GovernanceCallFn) apilib.RotateChainParams {
+	return apilib.RotateChainParams{
+		ChainID:               e.chain.ChainID,
+		AllPeeringHosts:       e.clu.Config.PeeringHosts(e.clu.Config.AllNodes()),
+		CommitteeAPIHosts:     e.clu.Config.APIHosts(rotateChainNewCommittee),
+		CommitteePeeringHosts: e.clu.Config.PeeringHosts(rotateChainNewCommittee),
+		T:                     3,
+		PostGovernanceCall:    poster,
+		Timeout:               60 * time.Second,
+	}
+}
+
+func (e *chainEnv) checkIncCounterAfterRotation() {
+	myClient := e.chain.SCClient(incCounterSCHname, e.chain.OriginatorKeyPair())
+	_, err := myClient.PostRequest(inccounter.FuncIncCounter.Name)
+	require.NoError(e.t, err)
+	waitUntil(e.t, e.counterEquals(1), rotateChainNewCommittee, 30*time.Second)
+}
+
+func TestRotateChain(t *testing.T) {
+	e := setupRotateChainTest(t)
+	oldAddr, err := e.callGetStateController(0)
+	require.NoError(t, err)
+
+	addr, err := apilib.RotateChain(e.rotateChainParams(governanceCallPoster(e, e.chain.OriginatorKeyPair())))
+	require.NoError(t, err)
+	require.False(t, addr.Equals(oldAddr))
+
+	for _, i := range e.clu.Config.AllNodes() {
+		require.True(t, e.waitStateController(i, addr, 30*time.Second))
+	}
+	e.checkIncCounterAfterRotation()
+}
+
+func TestRotateChainViaNode(t *testing.T) {
+	e := setupRotateChainTest(t)
+
+	// the node can rotate the chain once it owns the chain
+	self, err := e.clu.WaspClient(0).GetPeeringSelf()
+	require.NoError(t, err)
+	nodePubKey, err := ed25519.PublicKeyFromString(self.PubKey)
+	require.NoError(t, err)
+	nodeAgentID := iscp.NewAgentID(ledgerstate.NewED25519Address(nodePubKey), 0)
+
+	params := chainclient.NewPostRequestParams(governance.ParamChainOwner, nodeAgentID).WithIotas(1)
+	tx, err := e.chain.OriginatorClient().Post1Request(governance.Contract.Hname(), governance.FuncDelegateChainOwnership.Hname(), *params)
+	require.NoError(t, err)
+	err = e.chain.CommitteeMultiClient().WaitUntilAllRequestsProcessed(e.chain.ChainID, tx, 30*time.Second)
+	require.NoError(t, err)
+
+	addr, err := e.clu.WaspClient(0).RotateChain(e.chain.ChainID, &model.RotateChainRequest{
+		CommitteeAPIHosts:     e.clu.Config.APIHosts(rotateChainNewCommittee),
+		CommitteePeeringHosts: e.clu.Config.PeeringHosts(rotateChainNewCommittee),
+		PeeringHosts:          e.clu.Config.PeeringHosts(e.clu.Config.AllNodes()),
+		Threshold:             3,
+		ClaimOwnership:        true,
+	})
+	require.NoError(t, err)
+
+	for _, i := range e.clu.Config.AllNodes() {
+		require.True(t, e.waitStateController(i, addr, 30*time.Second))
+	}
+	_, ownerID := e.getChainInfo()
+	require.True(t, ownerID.Equals(nodeAgentID))
+	e.checkIncCounterAfterRotation()
+}
+
+func TestRotateChainRollback(t *testing.T) {
+	e := setupRotateChainTest(t)
+	oldAddr, err := e.callGetStateController(0)
+	require.NoError(t, err)
+
+	// the governance calls of somebody who doesn't own the chain fail
+	keyPair := wallet.KeyPair(1)
+	e.requestFunds(ledgerstate.NewED25519Address(keyPair.PublicKey), "notOwner")
+	_, err = apilib.RotateChain(e.rotateChainParams(governanceCallPoster(e, keyPair)))
+	require.Error(t, err)
+
+	for _, i := range rotateChainPeers {
+		require.True(t, e.waitStateController(i, oldAddr, 5*time.Second))
+	}
+	// the chain is deactivated on the nodes where it was activated by the rotation
+	for _, i := range []int{4, 5} {
+		rec, err := e.clu.WaspClient(i).GetChainRecord(e.chain.ChainID)
+		require.NoError(t, err)
+		require.False(t, rec.Active)
+	}
+}
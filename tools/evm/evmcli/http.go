@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -69,7 +70,17 @@ func (j *JSONRPCServer) serveHTTP(rpcsrv *rpc.Server) {
 		AllowMethods: []string{http.MethodPost, http.MethodGet},
 		AllowHeaders: []string{"*"},
 	}))
-	e.Any("/", echo.WrapHandler(rpcsrv))
+	// eth_subscribe is only available through the websocket transport, which
+	// is served on the same endpoint as the HTTP transport
+	wsHandler := rpcsrv.WebsocketHandler(j.corsAllowOrigins)
+	e.Any("/", func(c echo.Context) error {
+		if isWebsocket(c.Request()) {
+			wsHandler.ServeHTTP(c.Response(), c.Request())
+			return nil
+		}
+		rpcsrv.ServeHTTP(c.Response(), c.Request())
+		return nil
+	})
 
 	fmt.Printf("Starting JSON-RPC server on %s\n", j.listenAddr)
 	if err := e.Start(j.listenAddr); err != nil {
@@ -78,3 +89,8 @@ func (j *JSONRPCServer) serveHTTP(rpcsrv *rpc.Server) {
 		}
 	}
 }
+
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
@@ -20,6 +20,7 @@ const (
 	KeyEvents    = "events"
 	KeyExist     = "exist"
 	KeyFunc      = "func"
+	KeyIndexed   = "indexed"
 	KeyInit      = "init"
 	KeyMandatory = "mandatory"
 	KeyMap       = "map"
@@ -277,6 +278,8 @@ func (g *GenBase) emitIf(line string) {
 		condition = g.newTypes[g.keys[KeyProxy]]
 	case KeyFunc:
 		condition = g.keys["kind"] == KeyFunc
+	case KeyIndexed:
+		condition = g.currentField.Indexed
 	case KeyInit:
 		condition = g.currentFunc.Name == KeyInit
 	case KeyMap:
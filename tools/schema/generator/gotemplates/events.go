@@ -18,7 +18,7 @@ $#set separator
 $#each event eventParam
 
 func (e $TypeName) $EvtName($params) {
-	wasmlib.NewEventEncoder("$package.$evtName").
+	wasmlib.NewTypedEventEncoder("$package.$evtName").
 $#each event eventEmit
 		Emit()
 }
@@ -30,6 +30,11 @@ $#set separator ,
 `,
 	// *******************************
 	"eventEmit": `
-		$FldType($fldName).
+		$FldType("$fldName", $fldName).
+$#if indexed eventIndexed
+`,
+	// *******************************
+	"eventIndexed": `
+		Indexed().
 `,
 }
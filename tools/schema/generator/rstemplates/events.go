@@ -30,16 +30,21 @@ $#set params $params, $fld_name: $fldRef$fldParamLangType
 `,
 	// *******************************
 	"eventParamNone": `
-		EventEncoder::new("$package.$evtName").emit();
+		TypedEventEncoder::new("$package.$evtName").emit();
 `,
 	// *******************************
 	"eventParams": `
-		let mut encoder = EventEncoder::new("$package.$evtName");
+		let mut encoder = TypedEventEncoder::new("$package.$evtName");
 $#each event eventEmit
 		encoder.emit();
 `,
 	// *******************************
 	"eventEmit": `
-		encoder.$fld_type($fldRef$fld_name);
+		encoder.$fld_type("$fldName", $fldRef$fld_name);
+$#if indexed eventIndexed
+`,
+	// *******************************
+	"eventIndexed": `
+		encoder.indexed();
 `,
 }
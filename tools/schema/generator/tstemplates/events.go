@@ -17,7 +17,7 @@ $#set separator
 $#each event eventParam
 
 	$evtName($params): void {
-		new wasmlib.EventEncoder("$package.$evtName").
+		new wasmlib.TypedEventEncoder("$package.$evtName").
 $#each event eventEmit
 		emit();
 	}
@@ -29,6 +29,11 @@ $#set separator ,
 `,
 	// *******************************
 	"eventEmit": `
-		$fldType($fldName).
+		$fldType("$fldName", $fldName).
+$#if indexed eventIndexed
+`,
+	// *******************************
+	"eventIndexed": `
+		indexed().
 `,
 }
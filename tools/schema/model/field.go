@@ -17,6 +17,11 @@ var (
 	fldTypeRegexp  = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]+$`)
 )
 
+// MaxEventTopics is the maximum number of indexed fields of the event, same as iscp.MaxEventTopics
+const MaxEventTopics = 4
+
+const indexedSuffix = "@indexed"
+
 var FieldTypes = map[string]int32{
 	"Address":   wasmlib.TYPE_ADDRESS,
 	"AgentID":   wasmlib.TYPE_AGENT_ID,
@@ -43,6 +48,7 @@ type Field struct {
 	Alias    string // internal name alias, can be different from Name
 	Array    bool
 	Comment  string
+	Indexed  bool // event field which can be used to filter the events
 	KeyID    int
 	MapKey   string
 	Optional bool
@@ -75,6 +81,12 @@ func (f *Field) Compile(s *Schema, fldName, fldType string) error {
 		fldType = strings.TrimSpace(fldType[:index])
 	}
 
+	// remove indexed indicator
+	if strings.HasSuffix(fldType, indexedSuffix) {
+		f.Indexed = true
+		fldType = strings.TrimSpace(fldType[:len(fldType)-len(indexedSuffix)])
+	}
+
 	// remove optional indicator
 	n := len(fldType)
 	if n > 1 && fldType[n-1:] == "?" {
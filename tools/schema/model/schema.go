@@ -253,6 +253,7 @@ func (s *Schema) compileStruct(kind, structName string, structFields StringMap)
 	structDef := &Struct{Name: structName}
 	fieldNames := make(StringMap)
 	fieldAliases := make(StringMap)
+	indexed := 0
 	for _, fldName := range sortedKeys(structFields) {
 		fldType := structFields[fldName]
 		field, err := s.compileField(fldName, fldType)
@@ -268,6 +269,15 @@ func (s *Schema) compileStruct(kind, structName string, structFields StringMap)
 		if field.MapKey != "" {
 			return nil, fmt.Errorf("%s field cannot be a map", kind)
 		}
+		if field.Indexed {
+			if kind != "event" {
+				return nil, fmt.Errorf("%s field cannot be indexed", kind)
+			}
+			indexed++
+			if indexed > MaxEventTopics {
+				return nil, fmt.Errorf("event %s has more than %d indexed fields", structName, MaxEventTopics)
+			}
+		}
 		if _, ok := fieldNames[field.Name]; ok {
 			return nil, fmt.Errorf("duplicate %s field name", kind)
 		}
@@ -3,6 +3,7 @@ package chain
 import (
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/spf13/cobra"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
 )
@@ -83,3 +85,19 @@ var depositCmd = &cobra.Command{
 		})
 	},
 }
+
+var ethereumAccountCmd = &cobra.Command{
+	Use:   "ethereum-account <address>",
+	Short: "Show on-chain account of an Ethereum address",
+	Long:  "Show the agent ID and the next nonce of the sender of off-ledger requests signed with the Ethereum key.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !common.IsHexAddress(args[0]) {
+			log.Fatalf("invalid Ethereum address: %s", args[0])
+		}
+		account, err := config.WaspClient().GetEthereumAccount(GetCurrentChainID(), common.HexToAddress(args[0]))
+		log.Check(err)
+		log.Printf("agent ID: %s\n", account.AgentID)
+		log.Printf("next nonce: %d\n", account.Nonce)
+	},
+}
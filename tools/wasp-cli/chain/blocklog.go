@@ -94,9 +94,19 @@ func logReceipt(receipt *blocklog.RequestReceipt, index ...uint16) {
 		argsTree = dict.Dict(args)
 	}
 
-	errMsg := "(empty)"
-	if receipt.Error != "" {
-		errMsg = fmt.Sprintf("%q", receipt.Error)
+	var errTree interface{} = "(empty)"
+	if receipt.Error != nil {
+		errTree = []log.TreeItem{
+			{K: "Contract Hname", V: receipt.Error.Contract.String()},
+			{K: "Code", V: fmt.Sprintf("%d (%s)", receipt.Error.Code, receipt.Error.Code)},
+			{K: "Message", V: fmt.Sprintf("%q", receipt.Error.Message)},
+			{K: "Params", V: fmt.Sprintf("%q", receipt.Error.Params)},
+		}
+	}
+
+	var resultTree interface{} = "(empty)"
+	if len(receipt.Result) > 0 {
+		resultTree = receipt.Result
 	}
 
 	tree := []log.TreeItem{
@@ -107,7 +117,10 @@ func logReceipt(receipt *blocklog.RequestReceipt, index ...uint16) {
 		{K: "Entry point", V: req.Target().EntryPoint.String()},
 		{K: "Timestamp", V: timestamp},
 		{K: "Arguments", V: argsTree},
-		{K: "Error", V: errMsg},
+		{K: "Fee", V: fmt.Sprintf("%d %s", receipt.Fee, receipt.FeeColor.String())},
+		{K: "Gas used", V: fmt.Sprintf("%d", receipt.GasUsed)},
+		{K: "Result", V: resultTree},
+		{K: "Error", V: errTree},
 	}
 	if len(index) > 0 {
 		log.Printf("Request #%d (%s):\n", index[0], req.ID().Base58())
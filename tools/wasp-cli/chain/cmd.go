@@ -24,12 +24,15 @@ func Init(rootCmd *cobra.Command) {
 
 	chainCmd.AddCommand(listCmd)
 	chainCmd.AddCommand(deployCmd())
+	chainCmd.AddCommand(rotateCmd())
 	chainCmd.AddCommand(infoCmd)
 	chainCmd.AddCommand(listContractsCmd)
 	chainCmd.AddCommand(deployContractCmd)
+	chainCmd.AddCommand(upgradeContractCmd())
 	chainCmd.AddCommand(listAccountsCmd)
 	chainCmd.AddCommand(balanceCmd)
 	chainCmd.AddCommand(depositCmd)
+	chainCmd.AddCommand(ethereumAccountCmd)
 	chainCmd.AddCommand(listBlobsCmd)
 	chainCmd.AddCommand(storeBlobCmd)
 	chainCmd.AddCommand(showBlobCmd)
@@ -1,9 +1,11 @@
 package chain
 
 import (
+	"crypto/ecdsa"
 	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/iotaledger/goshimmer/packages/ledgerstate"
 	"github.com/iotaledger/wasp/client/chainclient"
 	"github.com/iotaledger/wasp/packages/iscp"
@@ -18,6 +20,8 @@ import (
 func postRequestCmd() *cobra.Command {
 	var transfer []string
 	var offLedger bool
+	var ethKey string
+	var encrypted bool
 
 	cmd := &cobra.Command{
 		Use:   "post-request <name> <funcname> [params]",
@@ -27,12 +31,19 @@ func postRequestCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			fname := args[1]
 			params := chainclient.PostRequestParams{
-				Args:     requestargs.New().AddEncodeSimpleMany(util.EncodeParams(args[2:])),
-				Transfer: parseColoredBalances(transfer),
+				Args:      requestargs.New().AddEncodeSimpleMany(util.EncodeParams(args[2:])),
+				Transfer:  parseColoredBalances(transfer),
+				Encrypted: encrypted,
 			}
 
 			scClient := SCClient(iscp.Hn(args[0]))
 
+			if ethKey != "" {
+				if !offLedger {
+					log.Fatalf("only off-ledger requests can be signed with an Ethereum key")
+				}
+				params.EthereumKey = parseEthereumKey(ethKey)
+			}
 			if offLedger {
 				util.WithOffLedgerRequest(GetCurrentChainID(), func() (*request.OffLedger, error) {
 					return scClient.PostOffLedgerRequest(fname, params)
@@ -51,10 +62,22 @@ func postRequestCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&offLedger, "off-ledger", "o", false,
 		"post an off-ledger request",
 	)
+	cmd.Flags().BoolVarP(&encrypted, "encrypted", "e", false,
+		"encrypt the params to the committee, so they are revealed only after the request is ordered",
+	)
+	cmd.Flags().StringVarP(&ethKey, "eth-key", "", "",
+		"sign the off-ledger request with the Ethereum private key (hex) instead of the wallet key",
+	)
 
 	return cmd
 }
 
+func parseEthereumKey(s string) *ecdsa.PrivateKey {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(s, "0x"))
+	log.Check(err)
+	return key
+}
+
 func colorFromString(s string) colored.Color {
 	if s == colored.IOTA.String() {
 		return colored.IOTA
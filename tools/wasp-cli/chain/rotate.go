@@ -0,0 +1,99 @@
+package chain
+
+import (
+	"os"
+	"time"
+
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/client/chainclient"
+	"github.com/iotaledger/wasp/packages/apilib"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/colored"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/iscp/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+func rotateCmd() *cobra.Command {
+	var (
+		peers          []int
+		committee      []int
+		quorum         int
+		viaNode        int
+		claimOwnership bool
+		timeout        time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate the chain to a new committee",
+		Long: "Runs DKG among the new committee nodes, activates the chain on them and rotates the chain " +
+			"to the new committee, signing the governance calls with the wallet of the chain owner. " +
+			"With --via-node, the whole workflow is run by the node, which signs the governance calls with its identity.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if committee == nil {
+				log.Fatalf("--committee is required")
+			}
+			if peers == nil {
+				peers = committee
+			}
+
+			if viaNode >= 0 {
+				waspClient := client.NewWaspClient(config.CommitteeAPI([]int{viaNode})[0])
+				addr, err := waspClient.RotateChain(GetCurrentChainID(), &model.RotateChainRequest{
+					CommitteeAPIHosts:     config.CommitteeAPI(committee),
+					CommitteePeeringHosts: config.CommitteePeering(committee),
+					PeeringHosts:          config.CommitteePeering(peers),
+					Threshold:             uint16(quorum),
+					ClaimOwnership:        claimOwnership,
+					TimeoutMS:             uint32(timeout.Milliseconds()),
+				})
+				log.Check(err)
+				log.Printf("chain rotated to the committee %s\n", addr.Base58())
+				return
+			}
+
+			chainClient := Client()
+			addr, err := apilib.RotateChain(apilib.RotateChainParams{
+				ChainID:               GetCurrentChainID(),
+				AllPeeringHosts:       config.CommitteePeering(peers),
+				CommitteeAPIHosts:     config.CommitteeAPI(committee),
+				CommitteePeeringHosts: config.CommitteePeering(committee),
+				T:                     uint16(quorum),
+				Timeout:               timeout,
+				Textout:               os.Stdout,
+				PostGovernanceCall: func(entryPoint string, params dict.Dict) (iscp.RequestID, error) {
+					tx, err := chainClient.Post1Request(governance.Contract.Hname(), iscp.Hn(entryPoint), chainclient.PostRequestParams{
+						Transfer: colored.NewBalancesForIotas(1),
+						Args:     requestargs.New(params),
+					})
+					if err != nil {
+						return iscp.RequestID{}, err
+					}
+					reqIDs := request.RequestsInTransaction(chainClient.ChainID, tx)
+					if len(reqIDs) == 0 {
+						return iscp.RequestID{}, xerrors.New("no request in the transaction")
+					}
+					return reqIDs[0], nil
+				},
+			})
+			log.Check(err)
+			log.Printf("chain rotated to the committee %s\n", addr.Base58())
+		},
+	}
+
+	cmd.Flags().IntSliceVarP(&peers, "peers", "", nil, "indices of peer nodes of the chain (default: same as committee)")
+	cmd.Flags().IntSliceVarP(&committee, "committee", "", nil, "indices of the nodes of the new committee")
+	cmd.Flags().IntVarP(&quorum, "quorum", "", 3, "quorum of the new committee")
+	cmd.Flags().IntVarP(&viaNode, "via-node", "", -1, "index of the node which runs the rotation, signing the governance calls with its identity")
+	cmd.Flags().BoolVarP(&claimOwnership, "claim-ownership", "", false, "with --via-node: the node claims the ownership delegated to it first")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", apilib.DefaultRotateChainTimeout, "timeout of each step")
+	return cmd
+}
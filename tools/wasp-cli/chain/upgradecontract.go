@@ -0,0 +1,78 @@
+package chain
+
+import (
+	"github.com/iotaledger/wasp/client/chainclient"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/iscp"
+	"github.com/iotaledger/wasp/packages/iscp/request"
+	"github.com/iotaledger/wasp/packages/iscp/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/cobra"
+)
+
+func upgradeContractCmd() *cobra.Command {
+	var migrate bool
+	var description string
+
+	cmd := &cobra.Command{
+		Use:   "upgrade-contract <vmtype> <name> <filename|program-hash> [migrate-params]",
+		Short: "Replace the program of a contract in the chain, keeping its state",
+		Args:  cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			vmtype := args[0]
+			name := args[1]
+			migrateParams := util.EncodeParams(args[3:])
+
+			var progHash hashing.HashValue
+
+			switch vmtype {
+			case vmtypes.Core:
+				log.Fatalf("cannot upgrade core contracts")
+
+			case vmtypes.Native:
+				var err error
+				progHash, err = hashing.HashValueFromBase58(args[2])
+				log.Check(err)
+
+			default:
+				filename := args[2]
+				blobFieldValues := codec.MakeDict(map[string]interface{}{
+					blob.VarFieldVMType:             vmtype,
+					blob.VarFieldProgramDescription: description,
+					blob.VarFieldProgramBinary:      util.ReadFile(filename),
+				})
+				progHash = uploadBlob(blobFieldValues)
+			}
+
+			upgradeContract(name, progHash, migrate, migrateParams)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&migrate, "migrate", "m", false, "call the 'migrate' entry point of the new program with the migrate-params")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "description of the uploaded program")
+	return cmd
+}
+
+func upgradeContract(name string, progHash hashing.HashValue, migrate bool, migrateParams dict.Dict) {
+	util.WithOffLedgerRequest(GetCurrentChainID(), func() (*request.OffLedger, error) {
+		return Client().PostOffLedgerRequest(
+			root.Contract.Hname(),
+			root.FuncUpgradeContract.Hname(),
+			chainclient.PostRequestParams{
+				Args: requestargs.New().
+					AddEncodeSimpleMany(codec.MakeDict(map[string]interface{}{
+						root.ParamHname:       iscp.Hn(name),
+						root.ParamProgramHash: progHash,
+						root.ParamMigrate:     migrate,
+					})).
+					AddEncodeSimpleMany(migrateParams),
+			},
+		)
+	})
+}
@@ -26,6 +26,7 @@ var nodeconnMetricsCmd = &cobra.Command{
 			for _, s := range nodeconnMetrics.Subscribed {
 				log.Printf("\t%s\n", s)
 			}
+			printEndpointsMetrics(nodeconnMetrics)
 			printMessagesMetrics(&nodeconnMetrics.NodeConnectionMessagesMetrics)
 		} else {
 			chid, err := iscp.ChainIDFromBase58(chainIDStr)
@@ -37,6 +38,25 @@ var nodeconnMetricsCmd = &cobra.Command{
 	},
 }
 
+func printEndpointsMetrics(nodeconnMetrics *model.NodeConnectionMetrics) {
+	log.Printf("L1 node endpoints, failovers: %d\n", nodeconnMetrics.Failovers)
+	header := []string{"Address", "Connected", "Healthy", "Active", "Connects", "Sent", "Received", "Last received"}
+	table := make([][]string, len(nodeconnMetrics.Endpoints))
+	for i, e := range nodeconnMetrics.Endpoints {
+		table[i] = []string{
+			e.Address,
+			fmt.Sprintf("%v", e.Connected),
+			fmt.Sprintf("%v", e.Healthy),
+			fmt.Sprintf("%v", e.Active),
+			fmt.Sprintf("%v", e.Connects),
+			fmt.Sprintf("%v", e.MessagesSent),
+			fmt.Sprintf("%v", e.MessagesReceived),
+			e.LastReceived.String(),
+		}
+	}
+	log.PrintTable(header, table)
+}
+
 func printMessagesMetrics(msgsMetrics *model.NodeConnectionMessagesMetrics) {
 	header := []string{"Message name", "", "Total", "Last time", "Last message"}
 	table := make([][]string, 8)
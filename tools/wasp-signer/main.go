@@ -0,0 +1,85 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// wasp-signer holds the node identity and the DK shares of a Wasp node in a separate process.
+// The node reaches it over a Unix socket, configured with keys.signerSocket.
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/iotaledger/wasp/packages/signer"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// passphraseEnvVar is an alternative to the passphrase file
+const passphraseEnvVar = "WASP_SIGNER_PASSPHRASE"
+
+var (
+	keysDir        string
+	socketPath     string
+	passphraseFile string
+)
+
+func main() {
+	cmd := &cobra.Command{
+		Use:   "wasp-signer",
+		Short: "wasp-signer holds the keys of a Wasp node and signs on its behalf",
+		Long: "wasp-signer holds the node identity and the DK shares of a Wasp node, encrypted with the passphrase. " +
+			"The node identity is generated on the first run. The passphrase is read from the --passphrase-file or " +
+			"from the " + passphraseEnvVar + " environment variable.",
+		Args: cobra.NoArgs,
+		Run:  run,
+	}
+	cmd.Flags().StringVar(&keysDir, "keys", "wasp-signer-keys", "directory of the keys")
+	cmd.Flags().StringVar(&socketPath, "socket", "wasp-signer.sock", "Unix socket to listen on")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "file with the passphrase to encrypt the keys")
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(cmd *cobra.Command, args []string) {
+	zapLog, err := zap.NewProduction()
+	if err != nil {
+		panic(err)
+	}
+	log := zapLog.Named("wasp-signer").Sugar()
+	defer func() { _ = log.Sync() }()
+
+	var encryptor *signer.Encryptor
+	if passphraseFile != "" {
+		encryptor, err = signer.NewEncryptorFromFile(passphraseFile)
+	} else {
+		encryptor, err = signer.NewEncryptor([]byte(os.Getenv(passphraseEnvVar)))
+	}
+	if err != nil {
+		log.Fatalf("passphrase: %v", err)
+	}
+	keyStore, err := signer.NewFileKeyStore(keysDir, encryptor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	identity, err := keyStore.NodeIdentity()
+	if err != nil {
+		log.Fatalf("node identity: %v", err)
+	}
+	server, err := signer.NewServer(signer.NewLocal(identity, keyStore), socketPath, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("serving the node identity %s on %s", identity.PublicKey.String(), socketPath)
+
+	shutdown := make(chan struct{})
+	go func() {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		<-sigs
+		close(shutdown)
+	}()
+	server.Run(shutdown)
+	log.Info("stopped")
+}